@@ -0,0 +1,67 @@
+package api
+
+import (
+	"opentalaria/auth"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"testing"
+)
+
+func TestACLsAPIs(t *testing.T) {
+	acls := auth.NewACLStore()
+	authorizer := auth.NewACLAuthorizer(acls, true)
+
+	createResp := GenerateCreateACLsResponse(3, protocol.CreateAclsRequest{Version: 3, Creations: []protocol.AclCreation{
+		{ResourceType: int8(auth.ResourceTopic), ResourceName: "orders", ResourcePatternType: int8(auth.PatternLiteral), Principal: "User:alice", Host: "*", Operation: int8(auth.OperationWrite), PermissionType: int8(auth.PermissionAllow)},
+		{ResourceType: int8(auth.ResourceTopic), ResourceName: "orders", ResourcePatternType: int8(auth.PatternMatch), Principal: "User:alice", Host: "*", Operation: int8(auth.OperationWrite), PermissionType: int8(auth.PermissionAllow)},
+	}}, acls)
+	if len(createResp.Results) != 2 || createResp.Results[0].ErrorCode != int16(utils.ErrNoError) || createResp.Results[1].ErrorCode != int16(utils.ErrInvalidRequest) {
+		t.Fatalf("create results = %+v, want the second ACL rejected", createResp.Results)
+	}
+
+	orders := auth.Resource{Type: auth.ResourceTopic, Name: "orders"}
+	if !authorizer.Authorize(auth.NewUserPrincipal("alice"), auth.OperationWrite, orders) {
+		t.Error("alice may not write to orders, although an ACL allows it")
+	}
+	if authorizer.Authorize(auth.NewUserPrincipal("bob"), auth.OperationWrite, orders) {
+		t.Error("bob may write to orders without an ACL")
+	}
+
+	name := "orders"
+	describeResp := GenerateDescribeACLsResponse(3, protocol.DescribeAclsRequest{Version: 3, ResourceTypeFilter: int8(auth.ResourceAny), ResourceNameFilter: &name,
+		PatternTypeFilter: int8(auth.PatternMatch), Operation: int8(auth.OperationAny), PermissionType: int8(auth.PermissionAny)}, acls)
+	if describeResp.ErrorCode != int16(utils.ErrNoError) || len(describeResp.Resources) != 1 || len(describeResp.Resources[0].Acls) != 1 {
+		t.Fatalf("described resources = %+v, want the ACL of orders", describeResp.Resources)
+	}
+	if r := describeResp.Resources[0]; r.ResourceName != "orders" || r.PatternType != int8(auth.PatternLiteral) || r.Acls[0].Principal != "User:alice" {
+		t.Errorf("described resource = %+v, want the literal ACL of alice", r)
+	}
+
+	// encode and decode the response, to make sure it survives the wire format
+	respBytes, err := protocol.Encode(describeResp)
+	if err != nil {
+		t.Fatalf("error encoding response: %v", err)
+	}
+	decoded := protocol.DescribeAclsResponse{}
+	if _, err := protocol.VersionedDecode(respBytes, &decoded, 3); err != nil {
+		t.Fatalf("error decoding response: %v", err)
+	}
+	if len(decoded.Resources) != 1 {
+		t.Errorf("decoded resources = %+v, want one", decoded.Resources)
+	}
+
+	invalid := GenerateDescribeACLsResponse(3, protocol.DescribeAclsRequest{Version: 3}, acls)
+	if invalid.ErrorCode != int16(utils.ErrInvalidRequest) || invalid.ErrorMessage == nil {
+		t.Errorf("describe with unknown filter = %+v, want INVALID_REQUEST", invalid)
+	}
+
+	deleteResp := GenerateDeleteACLsResponse(3, protocol.DeleteAclsRequest{Version: 3, Filters: []protocol.DeleteAclsFilter{
+		{ResourceTypeFilter: int8(auth.ResourceTopic), PatternTypeFilter: int8(auth.PatternAny), Operation: int8(auth.OperationAny), PermissionType: int8(auth.PermissionAny)},
+	}}, acls)
+	if len(deleteResp.FilterResults) != 1 || len(deleteResp.FilterResults[0].MatchingAcls) != 1 {
+		t.Fatalf("delete results = %+v, want the ACL of orders", deleteResp.FilterResults)
+	}
+	if !authorizer.Authorize(auth.NewUserPrincipal("bob"), auth.OperationWrite, orders) {
+		t.Error("bob may not write to orders after its ACLs were deleted")
+	}
+}
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"fmt"
+	"opentalaria/config"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+)
+
+// operations of the IncrementalAlterConfigs API
+const (
+	configOperationSet      int8 = 0
+	configOperationDelete   int8 = 1
+	configOperationAppend   int8 = 2
+	configOperationSubtract int8 = 3
+)
+
+// dynamicConfig is a broker configuration key which can be changed at runtime.
+type dynamicConfig struct {
+	validate func(value string) error
+	set      func(conf *config.Config, value string) error
+	// reset restores the value read from the configuration at startup
+	reset func(conf *config.Config)
+}
+
+// dynamicBrokerConfigs lists the broker configuration keys which can be altered through the AlterConfigs APIs.
+// All other keys are read-only.
+var dynamicBrokerConfigs = map[string]dynamicConfig{
+	"log.level": {
+		validate: func(value string) error {
+			_, err := config.ParseLogLevel(value)
+			return err
+		},
+		set:   (*config.Config).SetLogLevel,
+		reset: (*config.Config).ResetLogLevel,
+	},
+}
+
+// configChange is a change of a configuration key requested by either AlterConfigs or IncrementalAlterConfigs.
+type configChange struct {
+	name      string
+	operation int8
+	value     *string
+}
+
+type AlterConfigsAPI struct {
+	Request Request
+}
+
+func (a AlterConfigsAPI) Name() string {
+	return "AlterConfigs"
+}
+
+func (a AlterConfigsAPI) GetRequest() Request {
+	return a.Request
+}
+
+func (a AlterConfigsAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.AlterConfigsResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (a AlterConfigsAPI) GeneratePayload() ([]byte, error) {
+	req := *a.GetRequest().Body.(*protocol.AlterConfigsRequest)
+
+	resp := GenerateAlterConfigsResponse(a.GetRequest().Header.RequestApiVersion, req, a.GetRequest().Config)
+
+	return encodeResponse(a.GetRequest(), resp)
+}
+
+// GenerateAlterConfigsResponse sets the dynamic configuration keys of the broker.
+// A key without value is reset to the value read from the configuration at startup.
+func GenerateAlterConfigsResponse(version int16, req protocol.AlterConfigsRequest, config *config.Config) *protocol.AlterConfigsResponse {
+	resp := protocol.AlterConfigsResponse{
+		Version:   version,
+		Responses: []protocol.AlterConfigsResourceResponse_AlterConfigsResponse{},
+	}
+
+	for _, resource := range req.Resources {
+		changes := []configChange{}
+		for _, c := range resource.Configs {
+			operation := configOperationSet
+			if c.Value == nil {
+				operation = configOperationDelete
+			}
+			changes = append(changes, configChange{name: c.Name, operation: operation, value: c.Value})
+		}
+
+		kerr, errMsg := alterBrokerConfigs(config, resource.ResourceType, resource.ResourceName, changes, req.ValidateOnly)
+
+		resp.Responses = append(resp.Responses, protocol.AlterConfigsResourceResponse_AlterConfigsResponse{
+			Version:      version,
+			ErrorCode:    int16(kerr),
+			ErrorMessage: errMsg,
+			ResourceType: resource.ResourceType,
+			ResourceName: resource.ResourceName,
+		})
+	}
+
+	return &resp
+}
+
+type IncrementalAlterConfigsAPI struct {
+	Request Request
+}
+
+func (a IncrementalAlterConfigsAPI) Name() string {
+	return "IncrementalAlterConfigs"
+}
+
+func (a IncrementalAlterConfigsAPI) GetRequest() Request {
+	return a.Request
+}
+
+func (a IncrementalAlterConfigsAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.IncrementalAlterConfigsResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (a IncrementalAlterConfigsAPI) GeneratePayload() ([]byte, error) {
+	req := *a.GetRequest().Body.(*protocol.IncrementalAlterConfigsRequest)
+
+	resp := GenerateIncrementalAlterConfigsResponse(a.GetRequest().Header.RequestApiVersion, req, a.GetRequest().Config)
+
+	return encodeResponse(a.GetRequest(), resp)
+}
+
+// GenerateIncrementalAlterConfigsResponse sets or deletes the dynamic configuration keys of the broker.
+// None of the dynamic keys is a list, so the append and subtract operations are rejected.
+func GenerateIncrementalAlterConfigsResponse(version int16, req protocol.IncrementalAlterConfigsRequest, config *config.Config) *protocol.IncrementalAlterConfigsResponse {
+	resp := protocol.IncrementalAlterConfigsResponse{
+		Version:   version,
+		Responses: []protocol.AlterConfigsResourceResponse_IncrementalAlterConfigsResponse{},
+	}
+
+	for _, resource := range req.Resources {
+		changes := []configChange{}
+		for _, c := range resource.Configs {
+			changes = append(changes, configChange{name: c.Name, operation: c.ConfigOperation, value: c.Value})
+		}
+
+		kerr, errMsg := alterBrokerConfigs(config, resource.ResourceType, resource.ResourceName, changes, req.ValidateOnly)
+
+		resp.Responses = append(resp.Responses, protocol.AlterConfigsResourceResponse_IncrementalAlterConfigsResponse{
+			Version:      version,
+			ErrorCode:    int16(kerr),
+			ErrorMessage: errMsg,
+			ResourceType: resource.ResourceType,
+			ResourceName: resource.ResourceName,
+		})
+	}
+
+	return &resp
+}
+
+// alterBrokerConfigs validates all changes of the resource before applying any of them,
+// so a resource is either altered completely or not at all. It returns the error code and message of the resource.
+func alterBrokerConfigs(config *config.Config, resourceType int8, resourceName string, changes []configChange, validateOnly bool) (utils.KError, *string) {
+	if resourceType != resourceTypeBroker {
+		return alterConfigsError(utils.ErrInvalidRequest, fmt.Sprintf("unsupported resource type %d", resourceType))
+	}
+	if !isLocalBrokerResource(config, resourceName) {
+		return alterConfigsError(utils.ErrInvalidRequest, fmt.Sprintf("unexpected broker id %s, expected %d", resourceName, config.Broker.BrokerID))
+	}
+
+	for _, change := range changes {
+		dynamic, ok := dynamicBrokerConfigs[change.name]
+		if !ok {
+			return alterConfigsError(utils.ErrInvalidConfig, fmt.Sprintf("config %s can't be altered at runtime", change.name))
+		}
+
+		switch change.operation {
+		case configOperationSet:
+			if change.value == nil {
+				return alterConfigsError(utils.ErrInvalidConfig, fmt.Sprintf("missing value for config %s", change.name))
+			}
+			if err := dynamic.validate(*change.value); err != nil {
+				return alterConfigsError(utils.ErrInvalidConfig, fmt.Sprintf("invalid value for config %s: %s", change.name, err))
+			}
+		case configOperationDelete:
+		case configOperationAppend, configOperationSubtract:
+			return alterConfigsError(utils.ErrInvalidConfig, fmt.Sprintf("config %s is not a list", change.name))
+		default:
+			return alterConfigsError(utils.ErrInvalidRequest, fmt.Sprintf("unknown config operation %d", change.operation))
+		}
+	}
+
+	if validateOnly {
+		return utils.ErrNoError, nil
+	}
+
+	for _, change := range changes {
+		dynamic := dynamicBrokerConfigs[change.name]
+
+		if change.operation == configOperationDelete {
+			dynamic.reset(config)
+			continue
+		}
+
+		if err := dynamic.set(config, *change.value); err != nil {
+			return alterConfigsError(utils.ErrInvalidConfig, err.Error())
+		}
+	}
+
+	return utils.ErrNoError, nil
+}
+
+func alterConfigsError(kerr utils.KError, msg string) (utils.KError, *string) {
+	return kerr, &msg
+}
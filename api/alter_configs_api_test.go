@@ -0,0 +1,161 @@
+package api
+
+import (
+	"bytes"
+	"log/slog"
+	"opentalaria/config"
+	"opentalaria/logger"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"strings"
+	"testing"
+)
+
+func newAlterConfigsTestConfig(t *testing.T) *config.Config {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://:9092")
+	t.Setenv("OT_LOG_LEVEL", "info")
+
+	conf, err := config.NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conf
+}
+
+func TestGenerateAlterConfigsResponse_LogLevel(t *testing.T) {
+	conf := newAlterConfigsTestConfig(t)
+
+	var buf bytes.Buffer
+	log := slog.New(logger.NewLevelHandler(conf.LogLevel, slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: logger.LevelTrace})))
+
+	log.Debug("before")
+	if buf.Len() != 0 {
+		t.Fatalf("debug record logged at level info: %s", buf.String())
+	}
+
+	debug := "DEBUG"
+	req := protocol.AlterConfigsRequest{
+		Resources: []protocol.AlterConfigsResource_AlterConfigsRequest{{
+			ResourceType: resourceTypeBroker,
+			ResourceName: "",
+			Configs:      []protocol.AlterableConfig_AlterConfigsRequest{{Name: "log.level", Value: &debug}},
+		}},
+	}
+
+	resp := GenerateAlterConfigsResponse(0, req, conf)
+	if errCode := resp.Responses[0].ErrorCode; errCode != int16(utils.ErrNoError) {
+		t.Fatalf("error code = %d, message %v", errCode, *resp.Responses[0].ErrorMessage)
+	}
+
+	log.Debug("after")
+	if !strings.Contains(buf.String(), "msg=after") {
+		t.Errorf("debug record was not logged after altering the log level: %q", buf.String())
+	}
+
+	// the new level is described as a dynamic config
+	describeResp := GenerateDescribeConfigsResponse(1, protocol.DescribeConfigsRequest{
+		Resources: []protocol.DescribeConfigsResource{{ResourceType: resourceTypeBroker, ConfigurationKeys: []string{"log.level"}}},
+	}, conf, nil)
+	entry := describeResp.Results[0].Configs[0]
+	if entry.Value == nil || *entry.Value != "debug" || entry.ConfigSource != configSourceDynamicBroker || entry.ReadOnly {
+		t.Errorf("log.level = %+v, want a writable dynamic debug level", entry)
+	}
+}
+
+func TestGenerateAlterConfigsResponse_Invalid(t *testing.T) {
+	conf := newAlterConfigsTestConfig(t)
+
+	debug := "debug"
+	verbose := "verbose"
+	port := "9093"
+	tests := []struct {
+		name     string
+		resource protocol.AlterConfigsResource_AlterConfigsRequest
+		want     utils.KError
+	}{
+		{
+			name: "read-only key",
+			resource: protocol.AlterConfigsResource_AlterConfigsRequest{
+				ResourceType: resourceTypeBroker,
+				Configs: []protocol.AlterableConfig_AlterConfigsRequest{
+					{Name: "log.level", Value: &debug},
+					{Name: "debug.server.port", Value: &port},
+				},
+			},
+			want: utils.ErrInvalidConfig,
+		},
+		{
+			name: "invalid value",
+			resource: protocol.AlterConfigsResource_AlterConfigsRequest{
+				ResourceType: resourceTypeBroker,
+				Configs:      []protocol.AlterableConfig_AlterConfigsRequest{{Name: "log.level", Value: &verbose}},
+			},
+			want: utils.ErrInvalidConfig,
+		},
+		{
+			name: "topic resource",
+			resource: protocol.AlterConfigsResource_AlterConfigsRequest{
+				ResourceType: resourceTypeTopic,
+				ResourceName: "test-topic",
+				Configs:      []protocol.AlterableConfig_AlterConfigsRequest{{Name: "log.level", Value: &debug}},
+			},
+			want: utils.ErrInvalidRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := protocol.AlterConfigsRequest{Resources: []protocol.AlterConfigsResource_AlterConfigsRequest{tt.resource}}
+
+			resp := GenerateAlterConfigsResponse(1, req, conf)
+			if errCode := resp.Responses[0].ErrorCode; errCode != int16(tt.want) {
+				t.Errorf("error code = %d, want %d", errCode, tt.want)
+			}
+
+			// a rejected resource is not altered at all
+			if conf.LogLevel.Level() != slog.LevelInfo {
+				t.Errorf("log level = %v, want it unchanged", conf.LogLevel.Level())
+			}
+		})
+	}
+}
+
+func TestGenerateIncrementalAlterConfigsResponse(t *testing.T) {
+	conf := newAlterConfigsTestConfig(t)
+
+	trace := "trace"
+	alter := func(operation int8, value *string, validateOnly bool) utils.KError {
+		req := protocol.IncrementalAlterConfigsRequest{
+			Resources: []protocol.AlterConfigsResource_IncrementalAlterConfigsRequest{{
+				ResourceType: resourceTypeBroker,
+				ResourceName: "",
+				Configs: []protocol.AlterableConfig_IncrementalAlterConfigsRequest{{
+					Name:            "log.level",
+					ConfigOperation: operation,
+					Value:           value,
+				}},
+			}},
+			ValidateOnly: validateOnly,
+		}
+
+		resp := GenerateIncrementalAlterConfigsResponse(1, req, conf)
+		return utils.KError(resp.Responses[0].ErrorCode)
+	}
+
+	if kerr := alter(configOperationSet, &trace, true); kerr != utils.ErrNoError || conf.LogLevel.Level() != slog.LevelInfo {
+		t.Errorf("validate only: error %v, level %v, want no error and an unchanged level", kerr, conf.LogLevel.Level())
+	}
+
+	if kerr := alter(configOperationSet, &trace, false); kerr != utils.ErrNoError || conf.LogLevel.Level() != logger.LevelTrace {
+		t.Errorf("set: error %v, level %v, want no error and level trace", kerr, conf.LogLevel.Level())
+	}
+
+	if kerr := alter(configOperationAppend, &trace, false); kerr != utils.ErrInvalidConfig {
+		t.Errorf("append: error %v, want %v", kerr, utils.ErrInvalidConfig)
+	}
+
+	if kerr := alter(configOperationDelete, nil, false); kerr != utils.ErrNoError || conf.LogLevel.Level() != slog.LevelInfo {
+		t.Errorf("delete: error %v, level %v, want no error and the configured level info", kerr, conf.LogLevel.Level())
+	}
+}
@@ -1,14 +1,24 @@
 package api
 
 import (
-	"encoding/binary"
+	"context"
 	"fmt"
 	"log/slog"
 	"net"
+	"opentalaria/auth"
 	"opentalaria/config"
+	"opentalaria/coordinator"
+	"opentalaria/logger"
+	"opentalaria/metadata"
 	"opentalaria/protocol"
+	"opentalaria/storage"
+	"opentalaria/utils"
+	"reflect"
+	"time"
 )
 
+// API is implemented by the handlers of the Kafka APIs.
+// GeneratePayload returns a nil payload for requests which don't expect a response, like produce requests with acks=0.
 type API interface {
 	Name() string
 	GeneratePayload() ([]byte, error)
@@ -17,41 +27,175 @@ type API interface {
 }
 
 type Request struct {
+	// Ctx is cancelled when the client disconnects, so handlers which wait can give up early.
+	Ctx     context.Context
 	Header  protocol.RequestHeader
 	Message []byte
-	Conn    net.Conn
-	Config  *config.Config
+	Body    protocol.Request
+	// DecodeError is set if the header was parsed, but the body could not be decoded.
+	DecodeError error
+	Conn        net.Conn
+	Config      *config.Config
+	Topics      *metadata.TopicRegistry
+	Logs        *storage.LogManager
+	Raft        *metadata.RaftState
+	Groups      *coordinator.GroupCoordinator
+	ProducerIDs *metadata.ProducerIDManager
+	// Brokers tracks the registrations and heartbeats of the brokers, it is nil on nodes without the controller role.
+	Brokers *metadata.BrokerRegistry
+	// Session is the SASL authentication state of the connection of the request.
+	Session *auth.Session
+	// Authorizer decides whether the principal of the request may perform its operations, a nil Authorizer allows all of them.
+	Authorizer auth.Authorizer
+	// ACLs are the ACLs the authorizer of the broker checks, which admin clients manage with the ACL APIs.
+	ACLs *auth.ACLStore
+	// ThrottleTime is how long the response is delayed, because the client exceeded its request quota.
+	ThrottleTime time.Duration
+	// ClientSoftware is the client library of the connection of the request, it is nil for requests built in tests.
+	ClientSoftware *ClientSoftware
+	// Serves reports whether the listener of the connection serves the API, a nil Serves serves all of them, like
+	// requests built in tests.
+	Serves func(apiKey int16) bool
+}
+
+// ClientSoftware is the name and version of the client library of a connection, which clients send in ApiVersions since v3.
+// Both are empty until the client reported them.
+type ClientSoftware struct {
+	Name    string
+	Version string
+}
+
+// Context returns the context of the request, or the background context if it has none, like requests built in tests.
+func (r Request) Context() context.Context {
+	if r.Ctx == nil {
+		return context.Background()
+	}
+
+	return r.Ctx
+}
+
+// Principal returns the principal of the client which sent the request, which is Anonymous unless the client authenticated.
+func (r Request) Principal() auth.Principal {
+	if r.Session == nil {
+		return auth.Anonymous
+	}
+
+	return r.Session.Principal()
+}
+
+// ErrorResponder is implemented by handlers which can answer a request with just an error code,
+// for example when the request body can't be decoded, because its version is not supported or it is malformed.
+// ErrorPayload returns an error if the response of the request version can't express the error code.
+type ErrorResponder interface {
+	ErrorPayload(kerr utils.KError) ([]byte, error)
 }
 
 func HandleResponse(api API) error {
-	payload := make([]byte, 0)
+	var msg []byte
+	var err error
+
+	body := api.GetRequest().Body
+	switch {
+	case !protocol.IsSupportedVersion(body):
+		msg, err = errorPayload(api, utils.ErrUnsupportedVersion, fmt.Errorf("unsupported version %d of API %s", body.GetVersion(), api.Name()))
+	case api.GetRequest().DecodeError != nil:
+		slog.Warn("error decoding request body", "api", api.Name(), "version", body.GetVersion(), "err", api.GetRequest().DecodeError)
+		msg, err = errorPayload(api, utils.ErrInvalidMessage, fmt.Errorf("error decoding request body of API %s: %w", api.Name(), api.GetRequest().DecodeError))
+	default:
+		traceRequest(api, body)
+		if kerr := authorize(api); kerr != utils.ErrNoError {
+			msg, err = errorPayload(api, kerr, fmt.Errorf("%w: %s request of %s", kerr, api.Name(), api.GetRequest().Principal()))
+			break
+		}
+		msg, err = api.GeneratePayload()
+	}
+	if err != nil {
+		return err
+	}
+
+	if msg == nil {
+		return nil
+	}
+
+	if err := throttle(api.GetRequest()); err != nil {
+		return err
+	}
+
+	return writeResponse(api, msg)
+}
+
+// throttle delays the response of a client which exceeded its request quota, like Kafka does.
+// The wait is aborted with the error of the context when it is cancelled, for example because the client disconnected.
+func throttle(req Request) error {
+	if req.ThrottleTime <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(req.ThrottleTime)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
+
+// encodeResponse encodes the response of the request, with the throttle time of the request if the response has one.
+func encodeResponse(req Request, resp protocol.Response) ([]byte, error) {
+	if throttleTimeMs := reflect.ValueOf(resp).Elem().FieldByName("ThrottleTimeMs"); throttleTimeMs.IsValid() && throttleTimeMs.Kind() == reflect.Int32 {
+		throttleTimeMs.SetInt(req.ThrottleTime.Milliseconds())
+	}
 
+	return protocol.Encode(resp)
+}
+
+// errorPayload answers the request with just the error code, if the handler supports it.
+// Kafka closes the connection when the response can't express the error, so do we by returning cause.
+func errorPayload(api API, kerr utils.KError, cause error) ([]byte, error) {
+	responder, ok := api.(ErrorResponder)
+	if !ok {
+		return nil, cause
+	}
+
+	slog.Debug("answering request with an error", "api", api.Name(), "error", protocol.ErrorCodeName(int16(kerr)))
+	msg, err := responder.ErrorPayload(kerr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", cause, err)
+	}
+
+	return msg, nil
+}
+
+// writeResponse writes the response header and payload to the connection of the request, prefixed with the response size.
+// The response is framed in a pooled buffer, which is returned to the pool once Write has returned, since the connection
+// doesn't keep a reference to it.
+func writeResponse(api API, msg []byte) error {
 	resHeader := protocol.ResponseHeader{
 		Version:       api.GetHeaderVersion(api.GetRequest().Header.RequestApiVersion),
 		CorrelationID: api.GetRequest().Header.CorrelationID,
 	}
 
-	resHeaderBytes, err := protocol.Encode(&resHeader)
-	if err != nil {
-		return err
-	}
-	// TODO: calculate the payload size before merging the header with the message payload, to avoid the append operation
-	payload = append(payload, resHeaderBytes...)
+	bufp := protocol.GetBuffer()
+	buf := *bufp
+	defer func() {
+		*bufp = buf
+		protocol.PutBuffer(bufp)
+	}()
 
-	msg, err := api.GeneratePayload()
+	buf, err := protocol.AppendEncode(buf, &resHeader)
 	if err != nil {
 		return err
 	}
+	buf = append(buf, msg...)
 
-	payload = append(payload, msg...)
+	slog.Debug(fmt.Sprintf("writing %d bytes", len(buf)), "api", api.Name())
 
-	// prepend payload size to the final byte array that will be sent back via the wire
-	result := make([]byte, 0)
-	result = binary.BigEndian.AppendUint32(result, uint32(len(payload)))
-	result = append(result, payload...)
-
-	slog.Debug(fmt.Sprintf("writing %d bytes", len(result)), "api", api.Name())
+	return protocol.WriteFrame(api.GetRequest().Conn, buf)
+}
 
-	_, err = api.GetRequest().Conn.Write(result)
-	return err
+// traceRequest logs the fields of a decoded request at trace level. Sensitive fields, like SASL auth bytes, are redacted.
+func traceRequest(api API, req any) {
+	slog.Log(context.Background(), logger.LevelTrace, "decoded request", "api", api.Name(), "request", logger.Fields(req))
 }
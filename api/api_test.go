@@ -0,0 +1,290 @@
+package api
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"reflect"
+	"testing"
+)
+
+func TestHandleResponse_UnsupportedVersion(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	req := Request{
+		Header: getMockHeader(2, (&protocol.ApiVersionsRequest{}).GetKey(), 99, 42),
+		Body:   &protocol.ApiVersionsRequest{Version: 99},
+		Conn:   server,
+	}
+	handler, err := NewHandler(req)
+	if err != nil {
+		t.Fatalf("error creating handler: %v", err)
+	}
+
+	go func() {
+		if err := HandleResponse(handler); err != nil {
+			t.Errorf("error handling response: %v", err)
+		}
+	}()
+
+	sizeBytes := make([]byte, 4)
+	if _, err := io.ReadFull(client, sizeBytes); err != nil {
+		t.Fatalf("error reading response size: %v", err)
+	}
+	payload := make([]byte, binary.BigEndian.Uint32(sizeBytes))
+	if _, err := io.ReadFull(client, payload); err != nil {
+		t.Fatalf("error reading response: %v", err)
+	}
+
+	if correlationID := int32(binary.BigEndian.Uint32(payload)); correlationID != 42 {
+		t.Errorf("correlation ID = %d, want 42", correlationID)
+	}
+
+	// ApiVersions errors are always sent with version 0 of the response
+	resp := protocol.ApiVersionsResponse{}
+	if _, err := protocol.VersionedDecode(payload[4:], &resp, 0); err != nil {
+		t.Fatalf("error decoding response: %v", err)
+	}
+	if resp.ErrorCode != int16(utils.ErrUnsupportedVersion) {
+		t.Errorf("error code = %d, want %d", resp.ErrorCode, utils.ErrUnsupportedVersion)
+	}
+	if len(resp.ApiKeys) == 0 {
+		t.Error("expected the supported API versions in the error response")
+	}
+}
+
+func TestHandleResponse_UnsupportedVersionWithoutErrorResponse(t *testing.T) {
+	req := Request{
+		Header: getMockHeader(2, (&protocol.ProduceRequest{}).GetKey(), 1, 1),
+		Body:   &protocol.ProduceRequest{Version: 1},
+	}
+	handler, err := NewHandler(req)
+	if err != nil {
+		t.Fatalf("error creating handler: %v", err)
+	}
+
+	if err := HandleResponse(handler); err == nil {
+		t.Error("expected an error for an unsupported produce version")
+	}
+}
+
+func TestNewHandler_UnknownAPIKey(t *testing.T) {
+	if _, err := NewHandler(Request{Header: getMockHeader(2, 9999, 0, 1)}); err == nil {
+		t.Error("expected an error for an unknown API key")
+	}
+}
+
+func TestHandleResponse_UnsupportedAPI(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	// AddOffsetsToTxn is defined by the protocol, but has no handler
+	req := Request{
+		Header: getMockHeader(1, (&protocol.AddOffsetsToTxnRequest{}).GetKey(), 1, 42),
+		Body:   &protocol.AddOffsetsToTxnRequest{Version: 1, TransactionalID: "txn", GroupID: "group"},
+		Conn:   server,
+	}
+	handler, err := NewHandler(req)
+	if err != nil {
+		t.Fatalf("error creating handler: %v", err)
+	}
+	if handler.Name() != "AddOffsetsToTxn" {
+		t.Errorf("handler name = %s, want AddOffsetsToTxn", handler.Name())
+	}
+
+	go func() {
+		if err := HandleResponse(handler); err != nil {
+			t.Errorf("error handling response: %v", err)
+		}
+	}()
+
+	sizeBytes := make([]byte, 4)
+	if _, err := io.ReadFull(client, sizeBytes); err != nil {
+		t.Fatalf("error reading response size: %v", err)
+	}
+	payload := make([]byte, binary.BigEndian.Uint32(sizeBytes))
+	if _, err := io.ReadFull(client, payload); err != nil {
+		t.Fatalf("error reading response: %v", err)
+	}
+
+	if correlationID := int32(binary.BigEndian.Uint32(payload)); correlationID != 42 {
+		t.Errorf("correlation ID = %d, want 42", correlationID)
+	}
+	resp := protocol.AddOffsetsToTxnResponse{}
+	if _, err := protocol.VersionedDecode(payload[4:], &resp, 1); err != nil {
+		t.Fatalf("error decoding response: %v", err)
+	}
+	if resp.ErrorCode != int16(utils.ErrUnsupportedVersion) {
+		t.Errorf("error code = %d, want %d", resp.ErrorCode, utils.ErrUnsupportedVersion)
+	}
+}
+
+func TestHandleResponse_UnsupportedAPIWithoutErrorCode(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	// AddPartitionsToTxn only has a top-level error code from v4, before the error is in the result of every partition
+	req := Request{
+		Header: getMockHeader(1, (&protocol.AddPartitionsToTxnRequest{}).GetKey(), 1, 42),
+		Body: &protocol.AddPartitionsToTxnRequest{
+			Version:                   1,
+			V3AndBelowTransactionalID: "txn",
+			V3AndBelowTopics: []protocol.AddPartitionsToTxnTopic_AddPartitionsToTxnRequest{
+				{Name: "foo", Partitions: []int32{0, 1}},
+				{Name: "bar", Partitions: []int32{2}},
+			},
+		},
+		Conn: server,
+	}
+	handler, err := NewHandler(req)
+	if err != nil {
+		t.Fatalf("error creating handler: %v", err)
+	}
+
+	go func() {
+		if err := HandleResponse(handler); err != nil {
+			t.Errorf("error handling response: %v", err)
+		}
+	}()
+
+	sizeBytes := make([]byte, 4)
+	if _, err := io.ReadFull(client, sizeBytes); err != nil {
+		t.Fatalf("error reading response size: %v", err)
+	}
+	payload := make([]byte, binary.BigEndian.Uint32(sizeBytes))
+	if _, err := io.ReadFull(client, payload); err != nil {
+		t.Fatalf("error reading response: %v", err)
+	}
+
+	resp := protocol.AddPartitionsToTxnResponse{}
+	if _, err := protocol.VersionedDecode(payload[4:], &resp, 1); err != nil {
+		t.Fatalf("error decoding response: %v", err)
+	}
+	want := map[string][]int32{"foo": {0, 1}, "bar": {2}}
+	if len(resp.ResultsByTopicV3AndBelow) != len(want) {
+		t.Fatalf("topic results = %+v, want %v", resp.ResultsByTopicV3AndBelow, want)
+	}
+	for _, topic := range resp.ResultsByTopicV3AndBelow {
+		if len(topic.ResultsByPartition) != len(want[topic.Name]) {
+			t.Errorf("partition results of %s = %+v, want partitions %v", topic.Name, topic.ResultsByPartition, want[topic.Name])
+		}
+		for i, partition := range topic.ResultsByPartition {
+			if partition.PartitionIndex != want[topic.Name][i] || partition.PartitionErrorCode != int16(utils.ErrUnsupportedVersion) {
+				t.Errorf("partition result of %s = %+v, want partition %d with error code %d", topic.Name, partition, want[topic.Name][i], utils.ErrUnsupportedVersion)
+			}
+		}
+	}
+}
+
+func TestUnsupportedAPI_ErrorPayload(t *testing.T) {
+	// every version of every API without handler can be answered with an error
+	for apiKey := int16(0); apiKey < 100; apiKey++ {
+		if _, ok := handlers[apiKey]; ok {
+			continue
+		}
+		for version := int16(0); version < 20; version++ {
+			body, err := protocol.NewRequest(apiKey, version)
+			if err != nil {
+				break
+			}
+			if !body.IsValidVersion() {
+				continue
+			}
+
+			handler, err := NewHandler(Request{Header: getMockHeader(2, apiKey, version, 1), Body: body})
+			if err != nil {
+				t.Fatal(err)
+			}
+			payload, err := handler.(ErrorResponder).ErrorPayload(utils.ErrUnsupportedVersion)
+			if err != nil {
+				t.Errorf("%s v%d: error generating payload: %v", handler.Name(), version, err)
+				continue
+			}
+
+			resp, _ := protocol.NewResponse(apiKey, version)
+			if _, err := protocol.VersionedDecode(payload, resp, version); err != nil {
+				t.Errorf("%s v%d: error decoding response: %v", handler.Name(), version, err)
+				continue
+			}
+			if _, ok := resourceErrors[apiKey]; ok {
+				continue
+			}
+			if errorCode := reflect.ValueOf(resp).Elem().FieldByName("ErrorCode").Int(); errorCode != int64(utils.ErrUnsupportedVersion) {
+				t.Errorf("%s v%d: error code = %d, want %d", handler.Name(), version, errorCode, utils.ErrUnsupportedVersion)
+			}
+		}
+	}
+}
+
+func TestWriteResponse_HeaderVersions(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiKey     int16
+		apiVersion int16
+		want       []byte
+	}{
+		{name: "header v0", apiKey: (&protocol.MetadataRequest{}).GetKey(), apiVersion: 8, want: []byte{0, 0, 0, 42, 'x'}},
+		{name: "header v1", apiKey: (&protocol.MetadataRequest{}).GetKey(), apiVersion: 9, want: []byte{0, 0, 0, 42, 0, 'x'}},
+		{name: "flexible ApiVersions keeps header v0", apiKey: (&protocol.ApiVersionsRequest{}).GetKey(), apiVersion: 3, want: []byte{0, 0, 0, 42, 'x'}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := net.Pipe()
+			defer server.Close()
+			defer client.Close()
+
+			handler, err := NewHandler(Request{Header: getMockHeader(2, tt.apiKey, tt.apiVersion, 42), Conn: server})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			go func() {
+				if err := writeResponse(handler, []byte("x")); err != nil {
+					t.Errorf("error writing response: %v", err)
+				}
+			}()
+
+			got, err := protocol.ReadFrame(client, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(tt.want) {
+				t.Errorf("response = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIVersionsAPI_ClientSoftware(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int16
+		want    ClientSoftware
+	}{
+		{name: "v3 reports the client software", version: 3, want: ClientSoftware{Name: "librdkafka", Version: "2.3.0"}},
+		{name: "v2 has no client software", version: 2, want: ClientSoftware{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			software := &ClientSoftware{}
+			req := Request{
+				Header:         getMockHeader(2, (&protocol.ApiVersionsRequest{}).GetKey(), tt.version, 1),
+				Body:           &protocol.ApiVersionsRequest{Version: tt.version, ClientSoftwareName: "librdkafka", ClientSoftwareVersion: "2.3.0"},
+				ClientSoftware: software,
+			}
+
+			if _, err := (APIVersionsAPI{Request: req}).GeneratePayload(); err != nil {
+				t.Fatal(err)
+			}
+			if *software != tt.want {
+				t.Errorf("client software = %+v, want %+v", *software, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,11 @@
 package api
 
 import (
+	"log/slog"
+	"opentalaria/logger"
 	"opentalaria/protocol"
+	"opentalaria/utils"
+	"slices"
 )
 
 type APIVersionsAPI struct {
@@ -17,15 +21,38 @@ func (a APIVersionsAPI) GetRequest() Request {
 }
 
 func (a APIVersionsAPI) GeneratePayload() ([]byte, error) {
-	// handle response
-	apiVersionRequest := protocol.ApiVersionsRequest{}
-	_, err := protocol.VersionedDecode(a.Request.Message, &apiVersionRequest, a.Request.Header.RequestApiVersion)
-	if err != nil {
-		return nil, err
+	req := a.GetRequest().Body.(*protocol.ApiVersionsRequest)
+	if req.Version >= 3 {
+		header := a.GetRequest().Header
+		slog.Debug("client connected", logger.Request(&header), "clientSoftwareName", req.ClientSoftwareName,
+			"clientSoftwareVersion", req.ClientSoftwareVersion, "remote", remoteHost(a.GetRequest().Conn))
+		if a.GetRequest().ClientSoftware != nil {
+			*a.GetRequest().ClientSoftware = ClientSoftware{Name: req.ClientSoftwareName, Version: req.ClientSoftwareVersion}
+		}
 	}
 
-	response := NewAPIVersionsResponse(a.GetRequest().Header.RequestApiVersion)
-	return protocol.Encode(response)
+	response := a.newResponse(a.GetRequest().Header.RequestApiVersion)
+	return encodeResponse(a.GetRequest(), response)
+}
+
+// ErrorPayload answers an ApiVersions request with version 0 of the response, which every client can parse.
+// Clients use the error code and the list of supported versions to retry with a version the broker supports.
+func (a APIVersionsAPI) ErrorPayload(kerr utils.KError) ([]byte, error) {
+	response := a.newResponse(0)
+	response.ErrorCode = int16(kerr)
+
+	return encodeResponse(a.GetRequest(), response)
+}
+
+// newResponse returns the response of the version with the APIs served on the listener of the connection only,
+// because clients send requests of any API which is advertised.
+func (a APIVersionsAPI) newResponse(version int16) *protocol.ApiVersionsResponse {
+	response := NewAPIVersionsResponse(version)
+	if serves := a.GetRequest().Serves; serves != nil {
+		response.ApiKeys = slices.DeleteFunc(response.ApiKeys, func(v protocol.ApiVersion) bool { return !serves(v.ApiKey) })
+	}
+
+	return response
 }
 
 func (a APIVersionsAPI) GetHeaderVersion(requestVersion int16) int16 {
@@ -34,23 +61,45 @@ func (a APIVersionsAPI) GetHeaderVersion(requestVersion int16) int16 {
 
 func getAPIVersions() []protocol.ApiVersion {
 	return []protocol.ApiVersion{
-		{ApiKey: (&protocol.ApiVersionsRequest{}).GetKey(), MinVersion: 0, MaxVersion: 3},
-		{ApiKey: (&protocol.MetadataRequest{}).GetKey(), MinVersion: 0, MaxVersion: 8},
-		{ApiKey: (&protocol.ProduceRequest{}).GetKey(), MinVersion: 0, MaxVersion: 8},
-		{ApiKey: (&protocol.CreateTopicsRequest{}).GetKey(), MinVersion: 0, MaxVersion: 4},
-		// {APIKey: FetchKey, MinVersion: 0, MaxVersion: 3},
-		// {APIKey: OffsetsKey, MinVersion: 0, MaxVersion: 2},
+		{ApiKey: (&protocol.ApiVersionsRequest{}).GetKey(), MinVersion: (&protocol.ApiVersionsRequest{}).GetRequiredVersion(), MaxVersion: 3},
+		{ApiKey: (&protocol.MetadataRequest{}).GetKey(), MinVersion: (&protocol.MetadataRequest{}).GetRequiredVersion(), MaxVersion: 8},
+		{ApiKey: (&protocol.ProduceRequest{}).GetKey(), MinVersion: (&protocol.ProduceRequest{}).GetRequiredVersion(), MaxVersion: 8},
+		{ApiKey: (&protocol.FetchRequest{}).GetKey(), MinVersion: (&protocol.FetchRequest{}).GetRequiredVersion(), MaxVersion: 16},
+		{ApiKey: (&protocol.ListOffsetsRequest{}).GetKey(), MinVersion: (&protocol.ListOffsetsRequest{}).GetRequiredVersion(), MaxVersion: 7},
+		{ApiKey: (&protocol.CreateTopicsRequest{}).GetKey(), MinVersion: (&protocol.CreateTopicsRequest{}).GetRequiredVersion(), MaxVersion: 4},
+		{ApiKey: (&protocol.DeleteTopicsRequest{}).GetKey(), MinVersion: (&protocol.DeleteTopicsRequest{}).GetRequiredVersion(), MaxVersion: 6},
+		{ApiKey: (&protocol.VoteRequest{}).GetKey(), MinVersion: (&protocol.VoteRequest{}).GetRequiredVersion(), MaxVersion: 2},
+		{ApiKey: (&protocol.DescribeQuorumRequest{}).GetKey(), MinVersion: (&protocol.DescribeQuorumRequest{}).GetRequiredVersion(), MaxVersion: 2},
+		{ApiKey: (&protocol.DescribeConfigsRequest{}).GetKey(), MinVersion: (&protocol.DescribeConfigsRequest{}).GetRequiredVersion(), MaxVersion: 4},
+		{ApiKey: (&protocol.AlterConfigsRequest{}).GetKey(), MinVersion: (&protocol.AlterConfigsRequest{}).GetRequiredVersion(), MaxVersion: 2},
+		{ApiKey: (&protocol.IncrementalAlterConfigsRequest{}).GetKey(), MinVersion: (&protocol.IncrementalAlterConfigsRequest{}).GetRequiredVersion(), MaxVersion: 1},
+		{ApiKey: (&protocol.FindCoordinatorRequest{}).GetKey(), MinVersion: (&protocol.FindCoordinatorRequest{}).GetRequiredVersion(), MaxVersion: 4},
+		{ApiKey: (&protocol.JoinGroupRequest{}).GetKey(), MinVersion: (&protocol.JoinGroupRequest{}).GetRequiredVersion(), MaxVersion: 9},
+		{ApiKey: (&protocol.SyncGroupRequest{}).GetKey(), MinVersion: (&protocol.SyncGroupRequest{}).GetRequiredVersion(), MaxVersion: 5},
+		{ApiKey: (&protocol.HeartbeatRequest{}).GetKey(), MinVersion: (&protocol.HeartbeatRequest{}).GetRequiredVersion(), MaxVersion: 4},
+		{ApiKey: (&protocol.LeaveGroupRequest{}).GetKey(), MinVersion: (&protocol.LeaveGroupRequest{}).GetRequiredVersion(), MaxVersion: 5},
+		{ApiKey: (&protocol.OffsetCommitRequest{}).GetKey(), MinVersion: (&protocol.OffsetCommitRequest{}).GetRequiredVersion(), MaxVersion: 8},
+		{ApiKey: (&protocol.OffsetFetchRequest{}).GetKey(), MinVersion: (&protocol.OffsetFetchRequest{}).GetRequiredVersion(), MaxVersion: 8},
+		{ApiKey: (&protocol.InitProducerIdRequest{}).GetKey(), MinVersion: (&protocol.InitProducerIdRequest{}).GetRequiredVersion(), MaxVersion: 5},
+		{ApiKey: (&protocol.SaslHandshakeRequest{}).GetKey(), MinVersion: (&protocol.SaslHandshakeRequest{}).GetRequiredVersion(), MaxVersion: 1},
+		{ApiKey: (&protocol.SaslAuthenticateRequest{}).GetKey(), MinVersion: (&protocol.SaslAuthenticateRequest{}).GetRequiredVersion(), MaxVersion: 2},
+		{ApiKey: (&protocol.CreateAclsRequest{}).GetKey(), MinVersion: (&protocol.CreateAclsRequest{}).GetRequiredVersion(), MaxVersion: 3},
+		{ApiKey: (&protocol.DeleteAclsRequest{}).GetKey(), MinVersion: (&protocol.DeleteAclsRequest{}).GetRequiredVersion(), MaxVersion: 3},
+		{ApiKey: (&protocol.DescribeAclsRequest{}).GetKey(), MinVersion: (&protocol.DescribeAclsRequest{}).GetRequiredVersion(), MaxVersion: 3},
+		{ApiKey: (&protocol.CreateDelegationTokenRequest{}).GetKey(), MinVersion: (&protocol.CreateDelegationTokenRequest{}).GetRequiredVersion(), MaxVersion: 3},
+		{ApiKey: (&protocol.RenewDelegationTokenRequest{}).GetKey(), MinVersion: (&protocol.RenewDelegationTokenRequest{}).GetRequiredVersion(), MaxVersion: 2},
+		{ApiKey: (&protocol.ExpireDelegationTokenRequest{}).GetKey(), MinVersion: (&protocol.ExpireDelegationTokenRequest{}).GetRequiredVersion(), MaxVersion: 2},
+		{ApiKey: (&protocol.DescribeDelegationTokenRequest{}).GetKey(), MinVersion: (&protocol.DescribeDelegationTokenRequest{}).GetRequiredVersion(), MaxVersion: 3},
+		{ApiKey: (&protocol.BrokerRegistrationRequest{}).GetKey(), MinVersion: (&protocol.BrokerRegistrationRequest{}).GetRequiredVersion(), MaxVersion: 4},
+		{ApiKey: (&protocol.BrokerHeartbeatRequest{}).GetKey(), MinVersion: (&protocol.BrokerHeartbeatRequest{}).GetRequiredVersion(), MaxVersion: 1},
+		{ApiKey: (&protocol.DescribeProducersRequest{}).GetKey(), MinVersion: (&protocol.DescribeProducersRequest{}).GetRequiredVersion(), MaxVersion: 0},
+		{ApiKey: (&protocol.DescribeTransactionsRequest{}).GetKey(), MinVersion: (&protocol.DescribeTransactionsRequest{}).GetRequiredVersion(), MaxVersion: 0},
+		{ApiKey: (&protocol.DescribeLogDirsRequest{}).GetKey(), MinVersion: (&protocol.DescribeLogDirsRequest{}).GetRequiredVersion(), MaxVersion: 4},
 		// {APIKey: LeaderAndISRKey, MinVersion: 0, MaxVersion: 1},
 		// {APIKey: StopReplicaKey, MinVersion: 0, MaxVersion: 0},
-		// {APIKey: FindCoordinatorKey, MinVersion: 0, MaxVersion: 1},
-		// {APIKey: JoinGroupKey, MinVersion: 0, MaxVersion: 1},
-		// {APIKey: HeartbeatKey, MinVersion: 0, MaxVersion: 1},
-		// {APIKey: LeaveGroupKey, MinVersion: 0, MaxVersion: 1},
-		// {APIKey: SyncGroupKey, MinVersion: 0, MaxVersion: 1},
 		// {APIKey: DescribeGroupsKey, MinVersion: 0, MaxVersion: 1},
 		// {APIKey: ListGroupsKey, MinVersion: 0, MaxVersion: 1},
 		// {APIKey: CreateTopicsKey, MinVersion: 0, MaxVersion: 1},
-		// {APIKey: DeleteTopicsKey, MinVersion: 0, MaxVersion: 1},
 	}
 }
 
@@ -0,0 +1,286 @@
+package api
+
+import (
+	"log/slog"
+	"opentalaria/auth"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+
+	"github.com/google/uuid"
+)
+
+// action is an operation on a resource, which the principal of a request must be authorized for.
+type action struct {
+	operation auth.Operation
+	resource  auth.Resource
+}
+
+// actions maps API keys to the actions their requests perform, like Kafka documents them for its ACLs.
+// APIs which are not listed need no authorization, like ApiVersions and the SASL APIs, which clients send before they are authenticated.
+// Metadata is not listed either, since Kafka answers it for the topics the client may describe and leaves out the others,
+// instead of rejecting the request. GenerateMetadataResponse authorizes its topics one by one.
+// TODO: Kafka also accepts CreateTopics if the principal may create topics on the cluster.
+var actions = map[int16]func(req Request) []action{
+	(&protocol.ProduceRequest{}).GetKey(): func(req Request) []action {
+		body := req.Body.(*protocol.ProduceRequest)
+		var actions []action
+		for _, t := range body.TopicData {
+			actions = append(actions, topicAction(auth.OperationWrite, t.Name))
+		}
+		return actions
+	},
+	(&protocol.FetchRequest{}).GetKey(): func(req Request) []action {
+		body := req.Body.(*protocol.FetchRequest)
+		// followers replicate the partitions, consumers fetch with replica ID -1, which moved into the replica state in v15
+		replicaID := body.ReplicaID
+		if body.Version >= 15 {
+			replicaID = body.ReplicaState.ReplicaID
+		}
+		if replicaID >= 0 {
+			return []action{{operation: auth.OperationClusterAction, resource: auth.ClusterResource}}
+		}
+		var actions []action
+		for _, t := range body.Topics {
+			if name, ok := topicName(req, t.Topic, t.TopicID); ok {
+				actions = append(actions, topicAction(auth.OperationRead, name))
+			}
+		}
+		return actions
+	},
+	(&protocol.ListOffsetsRequest{}).GetKey(): func(req Request) []action {
+		body := req.Body.(*protocol.ListOffsetsRequest)
+		var actions []action
+		for _, t := range body.Topics {
+			actions = append(actions, topicAction(auth.OperationDescribe, t.Name))
+		}
+		return actions
+	},
+	(&protocol.CreateTopicsRequest{}).GetKey(): func(req Request) []action {
+		body := req.Body.(*protocol.CreateTopicsRequest)
+		var actions []action
+		for _, t := range body.Topics {
+			actions = append(actions, topicAction(auth.OperationCreate, t.Name))
+		}
+		return actions
+	},
+	(&protocol.DeleteTopicsRequest{}).GetKey(): func(req Request) []action {
+		body := req.Body.(*protocol.DeleteTopicsRequest)
+		var actions []action
+		for _, name := range body.TopicNames {
+			actions = append(actions, topicAction(auth.OperationDelete, name))
+		}
+		for _, t := range body.Topics {
+			var name string
+			if t.Name != nil {
+				name = *t.Name
+			}
+			if name, ok := topicName(req, name, t.TopicID); ok {
+				actions = append(actions, topicAction(auth.OperationDelete, name))
+			}
+		}
+		return actions
+	},
+	(&protocol.DescribeConfigsRequest{}).GetKey(): func(req Request) []action {
+		body := req.Body.(*protocol.DescribeConfigsRequest)
+		var actions []action
+		for _, r := range body.Resources {
+			actions = append(actions, configAction(auth.OperationDescribeConfigs, r.ResourceType, r.ResourceName))
+		}
+		return actions
+	},
+	(&protocol.AlterConfigsRequest{}).GetKey(): func(req Request) []action {
+		body := req.Body.(*protocol.AlterConfigsRequest)
+		var actions []action
+		for _, r := range body.Resources {
+			actions = append(actions, configAction(auth.OperationAlterConfigs, r.ResourceType, r.ResourceName))
+		}
+		return actions
+	},
+	(&protocol.IncrementalAlterConfigsRequest{}).GetKey(): func(req Request) []action {
+		body := req.Body.(*protocol.IncrementalAlterConfigsRequest)
+		var actions []action
+		for _, r := range body.Resources {
+			actions = append(actions, configAction(auth.OperationAlterConfigs, r.ResourceType, r.ResourceName))
+		}
+		return actions
+	},
+	(&protocol.FindCoordinatorRequest{}).GetKey(): func(req Request) []action {
+		body := req.Body.(*protocol.FindCoordinatorRequest)
+		resourceType := auth.ResourceGroup
+		if body.KeyType == coordinatorKeyTypeTransaction {
+			resourceType = auth.ResourceTransactionalID
+		}
+		// the key was replaced by a list of keys in v4
+		keys := body.CoordinatorKeys
+		if body.Version < 4 {
+			keys = []string{body.Key}
+		}
+		var actions []action
+		for _, key := range keys {
+			actions = append(actions, action{operation: auth.OperationDescribe, resource: auth.Resource{Type: resourceType, Name: key}})
+		}
+		return actions
+	},
+	(&protocol.JoinGroupRequest{}).GetKey(): func(req Request) []action {
+		return []action{groupAction(auth.OperationRead, req.Body.(*protocol.JoinGroupRequest).GroupID)}
+	},
+	(&protocol.SyncGroupRequest{}).GetKey(): func(req Request) []action {
+		return []action{groupAction(auth.OperationRead, req.Body.(*protocol.SyncGroupRequest).GroupID)}
+	},
+	(&protocol.HeartbeatRequest{}).GetKey(): func(req Request) []action {
+		return []action{groupAction(auth.OperationRead, req.Body.(*protocol.HeartbeatRequest).GroupID)}
+	},
+	(&protocol.LeaveGroupRequest{}).GetKey(): func(req Request) []action {
+		return []action{groupAction(auth.OperationRead, req.Body.(*protocol.LeaveGroupRequest).GroupID)}
+	},
+	(&protocol.OffsetCommitRequest{}).GetKey(): func(req Request) []action {
+		body := req.Body.(*protocol.OffsetCommitRequest)
+		actions := []action{groupAction(auth.OperationRead, body.GroupID)}
+		for _, t := range body.Topics {
+			actions = append(actions, topicAction(auth.OperationRead, t.Name))
+		}
+		return actions
+	},
+	(&protocol.OffsetFetchRequest{}).GetKey(): func(req Request) []action {
+		body := req.Body.(*protocol.OffsetFetchRequest)
+		var actions []action
+		if body.Version < 8 {
+			actions = append(actions, groupAction(auth.OperationDescribe, body.GroupID))
+			for _, t := range body.Topics {
+				actions = append(actions, topicAction(auth.OperationDescribe, t.Name))
+			}
+		}
+		for _, g := range body.Groups {
+			actions = append(actions, groupAction(auth.OperationDescribe, g.GroupID))
+			for _, t := range g.Topics {
+				actions = append(actions, topicAction(auth.OperationDescribe, t.Name))
+			}
+		}
+		return actions
+	},
+	(&protocol.InitProducerIdRequest{}).GetKey(): func(req Request) []action {
+		body := req.Body.(*protocol.InitProducerIdRequest)
+		if body.TransactionalID != nil {
+			return []action{{operation: auth.OperationWrite, resource: auth.Resource{Type: auth.ResourceTransactionalID, Name: *body.TransactionalID}}}
+		}
+		return []action{{operation: auth.OperationIdempotentWrite, resource: auth.ClusterResource}}
+	},
+	(&protocol.DescribeProducersRequest{}).GetKey(): func(req Request) []action {
+		body := req.Body.(*protocol.DescribeProducersRequest)
+		var actions []action
+		for _, t := range body.Topics {
+			actions = append(actions, topicAction(auth.OperationRead, t.Name))
+		}
+		return actions
+	},
+	(&protocol.DescribeTransactionsRequest{}).GetKey(): func(req Request) []action {
+		body := req.Body.(*protocol.DescribeTransactionsRequest)
+		var actions []action
+		for _, id := range body.TransactionalIds {
+			actions = append(actions, action{operation: auth.OperationDescribe, resource: auth.Resource{Type: auth.ResourceTransactionalID, Name: id}})
+		}
+		return actions
+	},
+	(&protocol.VoteRequest{}).GetKey(): func(req Request) []action {
+		return []action{{operation: auth.OperationClusterAction, resource: auth.ClusterResource}}
+	},
+	(&protocol.BrokerRegistrationRequest{}).GetKey(): func(req Request) []action {
+		return []action{{operation: auth.OperationClusterAction, resource: auth.ClusterResource}}
+	},
+	(&protocol.BrokerHeartbeatRequest{}).GetKey(): func(req Request) []action {
+		return []action{{operation: auth.OperationClusterAction, resource: auth.ClusterResource}}
+	},
+	(&protocol.DescribeQuorumRequest{}).GetKey(): func(req Request) []action {
+		return []action{{operation: auth.OperationDescribe, resource: auth.ClusterResource}}
+	},
+	(&protocol.CreateAclsRequest{}).GetKey(): func(req Request) []action {
+		return []action{{operation: auth.OperationAlter, resource: auth.ClusterResource}}
+	},
+	(&protocol.DeleteAclsRequest{}).GetKey(): func(req Request) []action {
+		return []action{{operation: auth.OperationAlter, resource: auth.ClusterResource}}
+	},
+	(&protocol.DescribeAclsRequest{}).GetKey(): func(req Request) []action {
+		return []action{{operation: auth.OperationDescribe, resource: auth.ClusterResource}}
+	},
+	(&protocol.DescribeLogDirsRequest{}).GetKey(): func(req Request) []action {
+		return []action{{operation: auth.OperationDescribe, resource: auth.ClusterResource}}
+	},
+}
+
+func topicAction(operation auth.Operation, name string) action {
+	return action{operation: operation, resource: auth.Resource{Type: auth.ResourceTopic, Name: name}}
+}
+
+func groupAction(operation auth.Operation, groupID string) action {
+	return action{operation: operation, resource: auth.Resource{Type: auth.ResourceGroup, Name: groupID}}
+}
+
+// configAction returns the action on the resource of a config request, the configs of brokers belong to the cluster.
+func configAction(operation auth.Operation, resourceType int8, name string) action {
+	if resourceType == resourceTypeTopic {
+		return topicAction(operation, name)
+	}
+
+	return action{operation: operation, resource: auth.ClusterResource}
+}
+
+// topicName returns the name of a topic which is referenced by name or, in newer versions, by ID.
+// Unknown topic IDs are not authorized, the handler answers them with UNKNOWN_TOPIC_ID.
+func topicName(req Request, name string, topicID uuid.UUID) (string, bool) {
+	if topicID == uuid.Nil {
+		return name, true
+	}
+
+	topic, ok := req.Topics.GetTopicByID(topicID)
+	if !ok {
+		return "", false
+	}
+
+	return topic.Name, true
+}
+
+// authorize checks the actions of the request against the authorizer of the request.
+// It returns the error code of the resource type of the first action the principal is not authorized for.
+func authorize(api API) utils.KError {
+	req := api.GetRequest()
+	if req.Authorizer == nil {
+		return utils.ErrNoError
+	}
+
+	requestActions, ok := actions[req.Header.RequestApiKey]
+	if !ok {
+		return utils.ErrNoError
+	}
+
+	principal := req.Principal()
+	for _, a := range requestActions(req) {
+		if req.Authorizer.Authorize(principal, a.operation, a.resource) {
+			continue
+		}
+
+		slog.Info("request denied", "api", api.Name(), "principal", principal.String(), "operation", a.operation.String(), "resource", a.resource.String())
+		return authorizationError(a.resource.Type)
+	}
+
+	return utils.ErrNoError
+}
+
+// isAuthorized reports whether the principal may perform the operation on the resource, for handlers which authorize
+// the resources of a request one by one. A nil authorizer allows every operation.
+func isAuthorized(authorizer auth.Authorizer, principal auth.Principal, operation auth.Operation, resource auth.Resource) bool {
+	return authorizer == nil || authorizer.Authorize(principal, operation, resource)
+}
+
+// authorizationError returns the error code of a denied operation on a resource of the type.
+func authorizationError(resourceType auth.ResourceType) utils.KError {
+	switch resourceType {
+	case auth.ResourceTopic:
+		return utils.ErrTopicAuthorizationFailed
+	case auth.ResourceGroup:
+		return utils.ErrGroupAuthorizationFailed
+	case auth.ResourceTransactionalID:
+		return utils.ErrTransactionalIDAuthorizationFailed
+	default:
+		return utils.ErrClusterAuthorizationFailed
+	}
+}
@@ -0,0 +1,239 @@
+package api
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"opentalaria/auth"
+	"opentalaria/config"
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"testing"
+)
+
+type denyAll struct{}
+
+func (denyAll) Authorize(auth.Principal, auth.Operation, auth.Resource) bool {
+	return false
+}
+
+// handleDenied handles the request with a deny-all authorizer and returns the payload of the response, without the correlation ID.
+func handleDenied(t *testing.T, req Request) []byte {
+	t.Helper()
+
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	req.Conn = server
+	req.Authorizer = denyAll{}
+	handler, err := NewHandler(req)
+	if err != nil {
+		t.Fatalf("error creating handler: %v", err)
+	}
+
+	go func() {
+		if err := HandleResponse(handler); err != nil {
+			t.Errorf("error handling response: %v", err)
+		}
+	}()
+
+	sizeBytes := make([]byte, 4)
+	if _, err := io.ReadFull(client, sizeBytes); err != nil {
+		t.Fatalf("error reading response size: %v", err)
+	}
+	payload := make([]byte, binary.BigEndian.Uint32(sizeBytes))
+	if _, err := io.ReadFull(client, payload); err != nil {
+		t.Fatalf("error reading response: %v", err)
+	}
+
+	return payload[4:]
+}
+
+func TestHandleResponse_Denied(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      protocol.Request
+		errorCode func(t *testing.T, payload []byte) int16
+		want      utils.KError
+	}{
+		{
+			name: "InitProducerId",
+			body: &protocol.InitProducerIdRequest{Version: 1, ProducerID: -1, ProducerEpoch: -1},
+			errorCode: func(t *testing.T, payload []byte) int16 {
+				resp := protocol.InitProducerIdResponse{}
+				if _, err := protocol.VersionedDecode(payload, &resp, 1); err != nil {
+					t.Fatalf("error decoding response: %v", err)
+				}
+				return resp.ErrorCode
+			},
+			want: utils.ErrClusterAuthorizationFailed,
+		},
+		{
+			name: "Fetch",
+			body: &protocol.FetchRequest{Version: 11, ReplicaID: -1, Topics: []protocol.FetchTopic_FetchRequest{{Version: 11, Topic: "test-topic"}}},
+			errorCode: func(t *testing.T, payload []byte) int16 {
+				resp := protocol.FetchResponse{}
+				if _, err := protocol.VersionedDecode(payload, &resp, 11); err != nil {
+					t.Fatalf("error decoding response: %v", err)
+				}
+				return resp.ErrorCode
+			},
+			want: utils.ErrTopicAuthorizationFailed,
+		},
+		{
+			name: "JoinGroup",
+			body: &protocol.JoinGroupRequest{Version: 5, GroupID: "test-group", ProtocolType: "consumer"},
+			errorCode: func(t *testing.T, payload []byte) int16 {
+				resp := protocol.JoinGroupResponse{}
+				if _, err := protocol.VersionedDecode(payload, &resp, 5); err != nil {
+					t.Fatalf("error decoding response: %v", err)
+				}
+				return resp.ErrorCode
+			},
+			want: utils.ErrGroupAuthorizationFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := Request{
+				Header: getMockHeader(1, tt.body.GetKey(), tt.body.GetVersion(), 7),
+				Body:   tt.body,
+				Topics: metadata.NewTopicRegistry(),
+			}
+
+			if code := tt.errorCode(t, handleDenied(t, req)); code != int16(tt.want) {
+				t.Errorf("error code = %d, want %d", code, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleResponse_DeniedMetadata(t *testing.T) {
+	topics := metadata.NewTopicRegistry()
+	if _, err := topics.CreateTopic("test-topic", 1, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	conf := config.MockConfig()
+
+	named, missing := "test-topic", "missing-topic"
+	tests := []struct {
+		name       string
+		topics     []protocol.MetadataRequestTopic
+		wantTopics []string
+	}{
+		{name: "all topics", topics: nil, wantTopics: nil},
+		{name: "named topics", topics: []protocol.MetadataRequestTopic{{Name: &named}, {Name: &missing}}, wantTopics: []string{named, missing}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := &protocol.MetadataRequest{Version: 1, Topics: tt.topics}
+			payload := handleDenied(t, Request{
+				Header: getMockHeader(1, body.GetKey(), 1, 7),
+				Body:   body,
+				Config: conf,
+				Topics: topics,
+			})
+
+			resp := protocol.MetadataResponse{}
+			if _, err := protocol.VersionedDecode(payload, &resp, 1); err != nil {
+				t.Fatalf("error decoding response: %v", err)
+			}
+			if len(resp.Brokers) != 1 {
+				t.Errorf("brokers = %+v, want the local broker", resp.Brokers)
+			}
+
+			// the topics which can't be described are left out of all topics, those named are denied
+			if len(resp.Topics) != len(tt.wantTopics) {
+				t.Fatalf("topics = %+v, want %v", resp.Topics, tt.wantTopics)
+			}
+			for i, topic := range resp.Topics {
+				if *topic.Name != tt.wantTopics[i] || topic.ErrorCode != int16(utils.ErrTopicAuthorizationFailed) || len(topic.Partitions) != 0 {
+					t.Errorf("topic %d = %s with error code %d and %d partitions, want %s denied", i, *topic.Name, topic.ErrorCode, len(topic.Partitions), tt.wantTopics[i])
+				}
+			}
+		})
+	}
+}
+
+func TestHandleResponse_DeniedWithoutErrorResponse(t *testing.T) {
+	req := Request{
+		Header:     getMockHeader(1, (&protocol.ProduceRequest{}).GetKey(), 8, 1),
+		Body:       &protocol.ProduceRequest{Version: 8, Acks: 1, TopicData: []protocol.TopicProduceData{{Version: 8, Name: "test-topic"}}},
+		Authorizer: denyAll{},
+	}
+	handler, err := NewHandler(req)
+	if err != nil {
+		t.Fatalf("error creating handler: %v", err)
+	}
+
+	// produce responses have no top-level error code, so the connection is closed
+	if err := HandleResponse(handler); err == nil {
+		t.Error("expected an error for a denied produce request")
+	}
+}
+
+func TestHandleResponse_NotAuthorizedAPI(t *testing.T) {
+	payload := handleDenied(t, Request{
+		Header: getMockHeader(1, (&protocol.ApiVersionsRequest{}).GetKey(), 0, 1),
+		Body:   &protocol.ApiVersionsRequest{Version: 0},
+	})
+
+	// ApiVersions is answered without authorization, clients send it before authenticating
+	resp := protocol.ApiVersionsResponse{}
+	if _, err := protocol.VersionedDecode(payload, &resp, 0); err != nil {
+		t.Fatalf("error decoding response: %v", err)
+	}
+	if resp.ErrorCode != int16(utils.ErrNoError) {
+		t.Errorf("error code = %d, want %d", resp.ErrorCode, utils.ErrNoError)
+	}
+}
+
+func TestActions(t *testing.T) {
+	topics := metadata.NewTopicRegistry()
+	topic, err := topics.CreateTopic("by-id", 1, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		body protocol.Request
+		want []action
+	}{
+		{
+			name: "fetch by topic ID",
+			body: &protocol.FetchRequest{Version: 13, ReplicaID: -1, Topics: []protocol.FetchTopic_FetchRequest{{TopicID: topic.TopicID}}},
+			want: []action{topicAction(auth.OperationRead, "by-id")},
+		},
+		{
+			name: "follower fetch",
+			body: &protocol.FetchRequest{Version: 15, ReplicaID: -1, ReplicaState: protocol.ReplicaState_FetchRequest{ReplicaID: 1}, Topics: []protocol.FetchTopic_FetchRequest{{Topic: "__cluster_metadata"}}},
+			want: []action{{operation: auth.OperationClusterAction, resource: auth.ClusterResource}},
+		},
+		{
+			name: "broker configs",
+			body: &protocol.DescribeConfigsRequest{Version: 4, Resources: []protocol.DescribeConfigsResource{{ResourceType: resourceTypeBroker, ResourceName: "1"}, {ResourceType: resourceTypeTopic, ResourceName: "t"}}},
+			want: []action{{operation: auth.OperationDescribeConfigs, resource: auth.ClusterResource}, topicAction(auth.OperationDescribeConfigs, "t")},
+		},
+		{
+			name: "offset commit",
+			body: &protocol.OffsetCommitRequest{Version: 8, GroupID: "g", Topics: []protocol.OffsetCommitRequestTopic{{Name: "t"}}},
+			want: []action{groupAction(auth.OperationRead, "g"), topicAction(auth.OperationRead, "t")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := actions[tt.body.GetKey()](Request{Body: tt.body, Topics: topics})
+			if len(got) != len(tt.want) {
+				t.Fatalf("actions = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("action %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"fmt"
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"time"
+)
+
+type BrokerHeartbeatAPI struct {
+	Request Request
+}
+
+func (b BrokerHeartbeatAPI) Name() string {
+	return "BrokerHeartbeat"
+}
+
+func (b BrokerHeartbeatAPI) GetRequest() Request {
+	return b.Request
+}
+
+func (b BrokerHeartbeatAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.BrokerHeartbeatResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (b BrokerHeartbeatAPI) GeneratePayload() ([]byte, error) {
+	req := *b.GetRequest().Body.(*protocol.BrokerHeartbeatRequest)
+
+	resp := GenerateBrokerHeartbeatResponse(b.GetRequest().Header.RequestApiVersion, req, b.GetRequest().Brokers, time.Now())
+
+	return encodeResponse(b.GetRequest(), resp)
+}
+
+// ErrorPayload answers the request with a top-level error code.
+func (b BrokerHeartbeatAPI) ErrorPayload(kerr utils.KError) ([]byte, error) {
+	resp := protocol.BrokerHeartbeatResponse{Version: b.GetRequest().Header.RequestApiVersion, ErrorCode: int16(kerr), IsFenced: true}
+	if !resp.IsValidVersion() {
+		return nil, fmt.Errorf("unknown BrokerHeartbeat response version %d", resp.Version)
+	}
+
+	return encodeResponse(b.GetRequest(), &resp)
+}
+
+// GenerateBrokerHeartbeatResponse records the heartbeat of a registered broker, which keeps it unfenced.
+// A broker which wants to shut down is fenced and told to shut down right away, there are no partition leaders to move.
+func GenerateBrokerHeartbeatResponse(version int16, req protocol.BrokerHeartbeatRequest, brokers *metadata.BrokerRegistry, now time.Time) *protocol.BrokerHeartbeatResponse {
+	resp := protocol.BrokerHeartbeatResponse{
+		Version:   version,
+		ErrorCode: int16(utils.ErrNoError),
+		IsFenced:  true,
+	}
+
+	if brokers == nil {
+		resp.ErrorCode = int16(utils.ErrNotController)
+		return &resp
+	}
+
+	result, err := brokers.Heartbeat(req.BrokerID, req.BrokerEpoch, req.CurrentMetadataOffset, req.WantFence || req.WantShutDown, now)
+	if err != nil {
+		resp.ErrorCode = int16(utils.ErrorCode(err))
+		return &resp
+	}
+
+	resp.IsCaughtUp = result.CaughtUp
+	resp.IsFenced = result.Fenced
+	resp.ShouldShutDown = req.WantShutDown
+
+	return &resp
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"opentalaria/config"
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestGenerateBrokerHeartbeatResponse(t *testing.T) {
+	conf := config.MockConfig()
+	brokers := metadata.NewBrokerRegistry(metadata.NewLog(metadata.NewStandaloneRaftState(conf.Broker.BrokerID)), 9*time.Second)
+	now := time.Now()
+
+	registration := protocol.BrokerRegistrationRequest{
+		BrokerID:      1,
+		ClusterID:     conf.Cluster.ClusterID,
+		IncarnationID: uuid.New(),
+		Listeners:     []protocol.Listener_BrokerRegistrationRequest{{Name: "PLAINTEXT", Host: "localhost", Port: 9092}},
+	}
+	registered := GenerateBrokerRegistrationResponse(4, registration, conf, brokers, now)
+	if registered.ErrorCode != int16(utils.ErrNoError) {
+		t.Fatalf("registration error code = %d", registered.ErrorCode)
+	}
+
+	// the broker stays fenced until it caught up with the metadata log up to its registration
+	heartbeat := protocol.BrokerHeartbeatRequest{BrokerID: 1, BrokerEpoch: registered.BrokerEpoch, CurrentMetadataOffset: -1}
+	resp := GenerateBrokerHeartbeatResponse(1, heartbeat, brokers, now)
+	if resp.ErrorCode != int16(utils.ErrNoError) || resp.IsCaughtUp || !resp.IsFenced {
+		t.Errorf("heartbeat before catching up = %+v, want a fenced broker which is not caught up", resp)
+	}
+
+	heartbeat.CurrentMetadataOffset = registered.BrokerEpoch
+	resp = GenerateBrokerHeartbeatResponse(1, heartbeat, brokers, now)
+	if resp.ErrorCode != int16(utils.ErrNoError) || !resp.IsCaughtUp || resp.IsFenced {
+		t.Errorf("heartbeat after catching up = %+v, want an unfenced broker", resp)
+	}
+
+	heartbeat.BrokerEpoch = registered.BrokerEpoch + 1
+	resp = GenerateBrokerHeartbeatResponse(1, heartbeat, brokers, now)
+	if resp.ErrorCode != int16(utils.ErrStaleBrokerEpoch) {
+		t.Errorf("heartbeat with another epoch error code = %d, want STALE_BROKER_EPOCH", resp.ErrorCode)
+	}
+
+	// nodes without the controller role have no broker registry
+	resp = GenerateBrokerHeartbeatResponse(1, heartbeat, nil, now)
+	if resp.ErrorCode != int16(utils.ErrNotController) {
+		t.Errorf("heartbeat to a broker error code = %d, want NOT_CONTROLLER", resp.ErrorCode)
+	}
+
+	registration.ClusterID = "other"
+	registered = GenerateBrokerRegistrationResponse(4, registration, conf, brokers, now)
+	if registered.ErrorCode != int16(utils.ErrInconsistentClusterID) {
+		t.Errorf("registration with another cluster ID error code = %d, want INCONSISTENT_CLUSTER_ID", registered.ErrorCode)
+	}
+}
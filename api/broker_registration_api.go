@@ -0,0 +1,93 @@
+package api
+
+import (
+	"fmt"
+	"opentalaria/config"
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"time"
+)
+
+type BrokerRegistrationAPI struct {
+	Request Request
+}
+
+func (b BrokerRegistrationAPI) Name() string {
+	return "BrokerRegistration"
+}
+
+func (b BrokerRegistrationAPI) GetRequest() Request {
+	return b.Request
+}
+
+func (b BrokerRegistrationAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.BrokerRegistrationResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (b BrokerRegistrationAPI) GeneratePayload() ([]byte, error) {
+	req := *b.GetRequest().Body.(*protocol.BrokerRegistrationRequest)
+
+	resp := GenerateBrokerRegistrationResponse(b.GetRequest().Header.RequestApiVersion, req, b.GetRequest().Config, b.GetRequest().Brokers, time.Now())
+
+	return encodeResponse(b.GetRequest(), resp)
+}
+
+// ErrorPayload answers the request with a top-level error code.
+func (b BrokerRegistrationAPI) ErrorPayload(kerr utils.KError) ([]byte, error) {
+	resp := protocol.BrokerRegistrationResponse{Version: b.GetRequest().Header.RequestApiVersion, ErrorCode: int16(kerr), BrokerEpoch: -1}
+	if !resp.IsValidVersion() {
+		return nil, fmt.Errorf("unknown BrokerRegistration response version %d", resp.Version)
+	}
+
+	return encodeResponse(b.GetRequest(), &resp)
+}
+
+// GenerateBrokerRegistrationResponse registers a broker with the controller, which assigns it a broker epoch.
+// Only controllers serve the request, the broker registry is nil on nodes without the controller role.
+func GenerateBrokerRegistrationResponse(version int16, req protocol.BrokerRegistrationRequest, config *config.Config, brokers *metadata.BrokerRegistry, now time.Time) *protocol.BrokerRegistrationResponse {
+	resp := protocol.BrokerRegistrationResponse{
+		Version:     version,
+		ErrorCode:   int16(utils.ErrNoError),
+		BrokerEpoch: -1,
+	}
+
+	if brokers == nil {
+		resp.ErrorCode = int16(utils.ErrNotController)
+		return &resp
+	}
+	if req.ClusterID != config.Cluster.ClusterID {
+		resp.ErrorCode = int16(utils.ErrInconsistentClusterID)
+		return &resp
+	}
+
+	registration := protocol.RegisterBrokerRecord{
+		BrokerID:      req.BrokerID,
+		IncarnationID: req.IncarnationID,
+		Rack:          req.Rack,
+	}
+	for _, l := range req.Listeners {
+		registration.EndPoints = append(registration.EndPoints, protocol.BrokerEndpoint{
+			Name:             l.Name,
+			Host:             l.Host,
+			Port:             l.Port,
+			SecurityProtocol: l.SecurityProtocol,
+		})
+	}
+	for _, f := range req.Features {
+		registration.Features = append(registration.Features, protocol.BrokerFeature{
+			Name:                f.Name,
+			MinSupportedVersion: f.MinSupportedVersion,
+			MaxSupportedVersion: f.MaxSupportedVersion,
+		})
+	}
+
+	brokerEpoch, err := brokers.Register(registration, now)
+	if err != nil {
+		resp.ErrorCode = int16(utils.ErrorCode(err))
+		return &resp
+	}
+	resp.BrokerEpoch = brokerEpoch
+
+	return &resp
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"log/slog"
+	"opentalaria/auth"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+)
+
+type CreateACLsAPI struct {
+	Request Request
+}
+
+func (c CreateACLsAPI) Name() string {
+	return "CreateAcls"
+}
+
+func (c CreateACLsAPI) GetRequest() Request {
+	return c.Request
+}
+
+func (c CreateACLsAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.CreateAclsResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (c CreateACLsAPI) GeneratePayload() ([]byte, error) {
+	req := *c.GetRequest().Body.(*protocol.CreateAclsRequest)
+
+	resp := GenerateCreateACLsResponse(c.GetRequest().Header.RequestApiVersion, req, c.GetRequest().ACLs)
+
+	return encodeResponse(c.GetRequest(), resp)
+}
+
+// GenerateCreateACLsResponse adds the ACLs of the request to the store, with a result per ACL in the order of the request.
+func GenerateCreateACLsResponse(version int16, req protocol.CreateAclsRequest, acls *auth.ACLStore) *protocol.CreateAclsResponse {
+	resp := protocol.CreateAclsResponse{
+		Version: version,
+		Results: []protocol.AclCreationResult{},
+	}
+
+	for _, creation := range req.Creations {
+		result := protocol.AclCreationResult{Version: version, ErrorCode: int16(utils.ErrNoError)}
+
+		acl := auth.ACL{
+			ResourceType: auth.ResourceType(creation.ResourceType),
+			ResourceName: creation.ResourceName,
+			PatternType:  auth.PatternType(creation.ResourcePatternType),
+			Principal:    creation.Principal,
+			Host:         creation.Host,
+			Operation:    auth.Operation(creation.Operation),
+			Permission:   auth.Permission(creation.PermissionType),
+		}
+		if err := acls.Create(acl); err != nil {
+			msg := err.Error()
+			result.ErrorCode = int16(utils.ErrInvalidRequest)
+			result.ErrorMessage = &msg
+		} else {
+			slog.Info("created ACL", "resource", auth.Resource{Type: acl.ResourceType, Name: acl.ResourceName}.String(),
+				"principal", acl.Principal, "operation", acl.Operation.String(), "permission", acl.Permission.String())
+		}
+
+		resp.Results = append(resp.Results, result)
+	}
+
+	return &resp
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"opentalaria/protocol"
+	"opentalaria/utils"
+)
+
+type CreateDelegationTokenAPI struct {
+	Request Request
+}
+
+func (d CreateDelegationTokenAPI) Name() string {
+	return "CreateDelegationToken"
+}
+
+func (d CreateDelegationTokenAPI) GetRequest() Request {
+	return d.Request
+}
+
+func (d CreateDelegationTokenAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.CreateDelegationTokenResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (d CreateDelegationTokenAPI) GeneratePayload() ([]byte, error) {
+	resp := GenerateCreateDelegationTokenResponse(d.GetRequest().Header.RequestApiVersion)
+
+	return encodeResponse(d.GetRequest(), resp)
+}
+
+// GenerateCreateDelegationTokenResponse rejects the request, delegation tokens are not supported,
+// so the delegation token authentication is always disabled.
+func GenerateCreateDelegationTokenResponse(version int16) *protocol.CreateDelegationTokenResponse {
+	return &protocol.CreateDelegationTokenResponse{
+		Version:   version,
+		ErrorCode: int16(utils.ErrDelegationTokenAuthDisabled),
+		Hmac:      []byte{},
+	}
+}
@@ -1,8 +1,17 @@
 package api
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"opentalaria/config"
+	"opentalaria/metadata"
 	"opentalaria/protocol"
+	"opentalaria/storage"
 	"opentalaria/utils"
+	"slices"
+	"time"
 )
 
 type CreateTopicsAPI struct {
@@ -22,33 +31,141 @@ func (m CreateTopicsAPI) GetHeaderVersion(requestVersion int16) int16 {
 }
 
 func (m CreateTopicsAPI) GeneratePayload() ([]byte, error) {
-	req := protocol.CreateTopicsRequest{}
-	_, err := protocol.VersionedDecode(m.GetRequest().Message, &req, m.GetRequest().Header.RequestApiVersion)
+	req := *m.GetRequest().Body.(*protocol.CreateTopicsRequest)
 
-	resp := GenerateCreateTopicsResponse(m.GetRequest().Header.RequestApiVersion, req, err)
+	var brokers []metadata.ReplicaBroker
+	if m.GetRequest().Brokers != nil {
+		brokers = m.GetRequest().Brokers.ReplicaBrokers()
+	}
+
+	resp := GenerateCreateTopicsResponse(m.GetRequest().Header.RequestApiVersion, req, m.GetRequest().Config, m.GetRequest().Topics, m.GetRequest().Logs, brokers)
 
-	return protocol.Encode(resp)
+	return encodeResponse(m.GetRequest(), resp)
 }
 
-func GenerateCreateTopicsResponse(version int16, req protocol.CreateTopicsRequest, err error) *protocol.CreateTopicsResponse {
+// GenerateCreateTopicsResponse creates the topics of the request. Their replicas are assigned to the brokers, rack-aware
+// if the brokers have racks. Without brokers to assign them to, the topics only live on the local broker.
+// Topics without a number of partitions or replication factor get the defaults of the config.
+func GenerateCreateTopicsResponse(version int16, req protocol.CreateTopicsRequest, config *config.Config, topics *metadata.TopicRegistry, logs *storage.LogManager,
+	brokers []metadata.ReplicaBroker) *protocol.CreateTopicsResponse {
 	response := protocol.CreateTopicsResponse{}
 
 	response.Version = version
-	// TODO: handle throttle time
-	response.ThrottleTimeMs = 0
 
-	errorCode := int16(utils.ErrNoError)
-	if err != nil {
-		errorCode = int16(utils.ErrInvalidRequest)
+	// Topic creation is done in memory, so it never takes long enough to hit the deadline.
+	// The deadline is still honored, so the behavior doesn't change once topic creation has to go through a persistence layer.
+	ctx := context.Background()
+	if req.TimeoutMs > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(req.TimeoutMs)*time.Millisecond)
+		defer cancel()
 	}
 
 	for _, topic := range req.Topics {
-		response.Topics = append(response.Topics, protocol.CreatableTopicResult{
-			Version:   req.Version,
-			Name:      topic.Name,
-			ErrorCode: errorCode,
-		})
+		result := protocol.CreatableTopicResult{
+			Version:           req.Version,
+			Name:              topic.Name,
+			NumPartitions:     -1,
+			ReplicationFactor: -1,
+		}
+
+		if ctx.Err() != nil {
+			result.ErrorCode = int16(utils.ErrRequestTimedOut)
+			response.Topics = append(response.Topics, result)
+			continue
+		}
+
+		numPartitions, replicationFactor := topic.NumPartitions, topic.ReplicationFactor
+		if len(topic.Assignments) > 0 {
+			// manual replica assignment overrides the number of partitions and replication factor
+			numPartitions = int32(len(topic.Assignments))
+			replicationFactor = int16(len(topic.Assignments[0].BrokerIds))
+		}
+		// -1 means the broker defaults should be used
+		if numPartitions == -1 {
+			numPartitions = config.NumPartitions
+		}
+		if replicationFactor == -1 {
+			replicationFactor = config.DefaultReplicationFactor
+		}
+
+		created, err := createTopic(topic, numPartitions, replicationFactor, req.ValidateOnly, topics, logs, brokers, config.Broker.BrokerID)
+		if err != nil {
+			slog.Debug("error creating topic", "topic", topic.Name, "err", err)
+
+			result.ErrorCode = int16(utils.ErrorCode(err))
+			errMsg := err.Error()
+			result.ErrorMessage = &errMsg
+		} else {
+			result.ErrorCode = int16(utils.ErrNoError)
+			result.TopicID = created.TopicID
+			result.NumPartitions = created.NumPartitions
+			result.ReplicationFactor = created.ReplicationFactor
+		}
+
+		response.Topics = append(response.Topics, result)
 	}
 
 	return &response
 }
+
+// createTopic creates the topic with the configs of the request, with its replicas assigned to the brokers, unless the request assigned them.
+// The replication factor can't exceed the number of brokers, which is the local broker only if there are no registered brokers.
+// TODO: keep the manual assignments of the request, once they are validated against the registered brokers.
+func createTopic(topic protocol.CreatableTopic, numPartitions int32, replicationFactor int16, validateOnly bool, topics *metadata.TopicRegistry,
+	logs *storage.LogManager, brokers []metadata.ReplicaBroker, brokerID int32) (metadata.Topic, error) {
+	if brokerCount := max(len(brokers), 1); int(replicationFactor) > brokerCount {
+		return metadata.Topic{}, fmt.Errorf("%w: replication factor %d larger than the %d available brokers", utils.ErrInvalidReplicationFactor, replicationFactor, brokerCount)
+	}
+
+	configs := map[string]string{}
+	for _, c := range topic.Configs {
+		if c.Value != nil {
+			configs[c.Name] = *c.Value
+		}
+	}
+	if err := metadata.ValidateTopicConfigs(configs); err != nil {
+		return metadata.Topic{}, err
+	}
+
+	var created metadata.Topic
+	var err error
+	if len(topic.Assignments) > 0 || len(brokers) == 0 {
+		created, err = topics.CreateTopic(topic.Name, numPartitions, replicationFactor, validateOnly)
+	} else {
+		// like Kafka, a random start spreads the first partitions of the topics over the brokers
+		var replicas [][]int32
+		if replicas, err = metadata.AssignReplicas(brokers, numPartitions, replicationFactor, rand.Intn(len(brokers))); err != nil {
+			return metadata.Topic{}, err
+		}
+		created, err = topics.CreateTopicWithReplicas(topic.Name, replicas, validateOnly)
+	}
+	if err != nil || validateOnly {
+		return created, err
+	}
+
+	if err := topics.SetTopicConfigs(created.Name, configs); err != nil {
+		return metadata.Topic{}, err
+	}
+	createPartitionLogs(created, logs, brokerID)
+
+	return created, nil
+}
+
+// createPartitionLogs creates the logs of the partitions of the topic which have a replica on the broker, like a Kafka
+// broker does when it becomes a replica, so reads of the partitions find them before the first produce request.
+// A log which can't be created is created again by the first produce request.
+func createPartitionLogs(topic metadata.Topic, logs *storage.LogManager, brokerID int32) {
+	if logs == nil {
+		return
+	}
+
+	for partition := int32(0); partition < topic.NumPartitions; partition++ {
+		if topic.Replicas != nil && !slices.Contains(topic.Replicas[partition], brokerID) {
+			continue
+		}
+		if _, err := logs.GetOrCreatePartition(topic.Name, partition); err != nil {
+			slog.Error("error creating partition log", "topic", topic.Name, "partition", partition, "err", err)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package api
+
+import (
+	"opentalaria/auth"
+	"opentalaria/config"
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/storage"
+	"opentalaria/utils"
+	"testing"
+)
+
+func TestGenerateCreateTopicsResponse(t *testing.T) {
+	conf := config.MockConfig()
+	topics := metadata.NewTopicRegistry()
+	req := protocol.CreateTopicsRequest{
+		Version: 5,
+		Topics: []protocol.CreatableTopic{
+			{Name: "test-topic", NumPartitions: 3, ReplicationFactor: 1},
+			{Name: "test-topic", NumPartitions: 1, ReplicationFactor: 1},
+			{Name: "invalid topic", NumPartitions: 1, ReplicationFactor: 1},
+			{Name: "default-topic", NumPartitions: -1, ReplicationFactor: -1},
+		},
+		TimeoutMs: 1000,
+	}
+	logs := storage.NewLogManager()
+
+	resp := GenerateCreateTopicsResponse(req.Version, req, conf, topics, logs, nil)
+
+	wantErrors := []utils.KError{utils.ErrNoError, utils.ErrTopicAlreadyExists, utils.ErrInvalidTopic, utils.ErrNoError}
+	if len(resp.Topics) != len(wantErrors) {
+		t.Fatalf("expected %d topic results, got %d", len(wantErrors), len(resp.Topics))
+	}
+	for i, want := range wantErrors {
+		if resp.Topics[i].ErrorCode != int16(want) {
+			t.Errorf("topic %d error code = %d, want %d", i, resp.Topics[i].ErrorCode, want)
+		}
+	}
+
+	if resp.Topics[0].NumPartitions != 3 {
+		t.Errorf("topic test-topic partitions = %d, want 3", resp.Topics[0].NumPartitions)
+	}
+	for partition := int32(0); partition < 3; partition++ {
+		if _, ok := logs.GetPartition("test-topic", partition); !ok {
+			t.Errorf("log of test-topic partition %d not created", partition)
+		}
+	}
+
+	if _, err := protocol.Encode(resp); err != nil {
+		t.Errorf("error encoding response: %v", err)
+	}
+}
+
+func TestGenerateCreateTopicsResponse_ValidateOnly(t *testing.T) {
+	conf := config.MockConfig()
+	topics := metadata.NewTopicRegistry()
+	req := protocol.CreateTopicsRequest{
+		Version:      4,
+		Topics:       []protocol.CreatableTopic{{Name: "test-topic", NumPartitions: 1, ReplicationFactor: 1}},
+		ValidateOnly: true,
+	}
+	logs := storage.NewLogManager()
+
+	resp := GenerateCreateTopicsResponse(req.Version, req, conf, topics, logs, nil)
+	if resp.Topics[0].ErrorCode != int16(utils.ErrNoError) {
+		t.Errorf("error code = %d, want %d", resp.Topics[0].ErrorCode, utils.ErrNoError)
+	}
+
+	if _, ok := topics.GetTopic("test-topic"); ok {
+		t.Error("topic should not be created when validateOnly is set")
+	}
+	if _, ok := logs.GetPartition("test-topic", 0); ok {
+		t.Error("partition log should not be created when validateOnly is set")
+	}
+}
+
+func TestCreateTopics_Metadata(t *testing.T) {
+	conf := config.MockConfig()
+	topics := metadata.NewTopicRegistry()
+
+	req := protocol.CreateTopicsRequest{
+		Version: 4,
+		Topics:  []protocol.CreatableTopic{{Name: "test-topic", NumPartitions: 2, ReplicationFactor: 1}},
+	}
+	GenerateCreateTopicsResponse(req.Version, req, conf, topics, nil, nil)
+
+	resp := GenerateMetadataResponse(8, protocol.MetadataRequest{Version: 8}, conf, topics, nil, nil, nil, auth.Anonymous)
+	if len(resp.Topics) != 1 {
+		t.Fatalf("expected 1 topic in metadata response, got %d", len(resp.Topics))
+	}
+
+	topic := resp.Topics[0]
+	if *topic.Name != "test-topic" {
+		t.Errorf("topic name = %s, want test-topic", *topic.Name)
+	}
+	if len(topic.Partitions) != 2 {
+		t.Fatalf("expected 2 partitions, got %d", len(topic.Partitions))
+	}
+	for _, partition := range topic.Partitions {
+		if partition.LeaderID != conf.Broker.BrokerID {
+			t.Errorf("partition %d leader = %d, want %d", partition.PartitionIndex, partition.LeaderID, conf.Broker.BrokerID)
+		}
+	}
+
+	unknown := "unknown-topic"
+	resp = GenerateMetadataResponse(8, protocol.MetadataRequest{Version: 8, Topics: []protocol.MetadataRequestTopic{{Name: &unknown}}}, conf, topics, nil, nil, nil, auth.Anonymous)
+	if resp.Topics[0].ErrorCode != int16(utils.ErrUnknownTopicOrPartition) {
+		t.Errorf("unknown topic error code = %d, want %d", resp.Topics[0].ErrorCode, utils.ErrUnknownTopicOrPartition)
+	}
+}
+
+func TestGenerateCreateTopicsResponse_RackAware(t *testing.T) {
+	conf := config.MockConfig()
+	topics := metadata.NewTopicRegistry()
+	rackA, rackB := "a", "b"
+	brokers := []metadata.ReplicaBroker{{ID: 0, Rack: &rackA}, {ID: 1, Rack: &rackB}, {ID: 2, Rack: &rackA}}
+
+	req := protocol.CreateTopicsRequest{
+		Version: 4,
+		Topics: []protocol.CreatableTopic{
+			{Name: "test-topic", NumPartitions: 6, ReplicationFactor: 2},
+			{Name: "too-many-replicas", NumPartitions: 1, ReplicationFactor: 4},
+		},
+	}
+	resp := GenerateCreateTopicsResponse(req.Version, req, conf, topics, nil, brokers)
+	if resp.Topics[0].ErrorCode != int16(utils.ErrNoError) {
+		t.Fatalf("error code = %d, want %d", resp.Topics[0].ErrorCode, utils.ErrNoError)
+	}
+	if resp.Topics[1].ErrorCode != int16(utils.ErrInvalidReplicationFactor) {
+		t.Errorf("replication factor above the number of brokers error code = %d, want %d", resp.Topics[1].ErrorCode, utils.ErrInvalidReplicationFactor)
+	}
+
+	// the start of the assignment is random, but each partition has a replica on both racks
+	topic, _ := topics.GetTopic("test-topic")
+	racks := map[int32]string{0: rackA, 1: rackB, 2: rackA}
+	if len(topic.Replicas) != 6 {
+		t.Fatalf("replicas = %v, want 6 partitions", topic.Replicas)
+	}
+	for p, replicas := range topic.Replicas {
+		if len(replicas) != 2 || racks[replicas[0]] == racks[replicas[1]] {
+			t.Errorf("replicas of partition %d = %v, want one on each rack", p, replicas)
+		}
+	}
+}
+
+func TestGenerateCreateTopicsResponse_Defaults(t *testing.T) {
+	conf := config.MockConfig()
+	conf.NumPartitions = 4
+	conf.DefaultReplicationFactor = 2
+	brokers := []metadata.ReplicaBroker{{ID: 0}, {ID: 1}}
+
+	req := protocol.CreateTopicsRequest{
+		Version: 4,
+		Topics: []protocol.CreatableTopic{
+			{Name: "default-topic", NumPartitions: -1, ReplicationFactor: -1},
+			{Name: "explicit-topic", NumPartitions: 2, ReplicationFactor: 1},
+		},
+	}
+	resp := GenerateCreateTopicsResponse(req.Version, req, conf, metadata.NewTopicRegistry(), nil, brokers)
+
+	want := []struct {
+		numPartitions     int32
+		replicationFactor int16
+	}{{4, 2}, {2, 1}}
+	for i, w := range want {
+		got := resp.Topics[i]
+		if got.ErrorCode != int16(utils.ErrNoError) || got.NumPartitions != w.numPartitions || got.ReplicationFactor != w.replicationFactor {
+			t.Errorf("topic %s = error code %d, %d partitions, replication factor %d, want %d partitions, replication factor %d",
+				got.Name, got.ErrorCode, got.NumPartitions, got.ReplicationFactor, w.numPartitions, w.replicationFactor)
+		}
+	}
+}
+
+func TestGenerateCreateTopicsResponse_OverReplicated(t *testing.T) {
+	tests := []struct {
+		name    string
+		brokers []metadata.ReplicaBroker
+		topic   protocol.CreatableTopic
+	}{
+		{name: "local broker only", topic: protocol.CreatableTopic{Name: "test-topic", NumPartitions: 1, ReplicationFactor: 2}},
+		{name: "registered brokers", brokers: []metadata.ReplicaBroker{{ID: 0}, {ID: 1}}, topic: protocol.CreatableTopic{Name: "test-topic", NumPartitions: 1, ReplicationFactor: 3}},
+		{
+			name:    "manual assignment",
+			brokers: []metadata.ReplicaBroker{{ID: 0}},
+			topic: protocol.CreatableTopic{
+				Name: "test-topic", NumPartitions: -1, ReplicationFactor: -1,
+				Assignments: []protocol.CreatableReplicaAssignment{{PartitionIndex: 0, BrokerIds: []int32{0, 1}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topics := metadata.NewTopicRegistry()
+			req := protocol.CreateTopicsRequest{Version: 4, Topics: []protocol.CreatableTopic{tt.topic}}
+
+			resp := GenerateCreateTopicsResponse(req.Version, req, config.MockConfig(), topics, nil, tt.brokers)
+			if resp.Topics[0].ErrorCode != int16(utils.ErrInvalidReplicationFactor) {
+				t.Errorf("error code = %d, want %d", resp.Topics[0].ErrorCode, utils.ErrInvalidReplicationFactor)
+			}
+			if _, ok := topics.GetTopic("test-topic"); ok {
+				t.Error("an over replicated topic should not be created")
+			}
+		})
+	}
+}
+
+func TestGenerateCreateTopicsResponse_Configs(t *testing.T) {
+	conf := config.MockConfig()
+	topics := metadata.NewTopicRegistry()
+	logAppendTime, invalid := metadata.TimestampTypeLogAppendTime, "WallClockTime"
+	req := protocol.CreateTopicsRequest{
+		Version: 5,
+		Topics: []protocol.CreatableTopic{
+			{Name: "test-topic", NumPartitions: 1, ReplicationFactor: 1, Configs: []protocol.CreatableTopicConfig{{Name: metadata.TopicConfigMessageTimestampType, Value: &logAppendTime}}},
+			{Name: "invalid-topic", NumPartitions: 1, ReplicationFactor: 1, Configs: []protocol.CreatableTopicConfig{{Name: metadata.TopicConfigMessageTimestampType, Value: &invalid}}},
+		},
+	}
+
+	resp := GenerateCreateTopicsResponse(req.Version, req, conf, topics, nil, nil)
+
+	if resp.Topics[0].ErrorCode != int16(utils.ErrNoError) || resp.Topics[1].ErrorCode != int16(utils.ErrInvalidConfig) {
+		t.Errorf("error codes = %d, %d, want %d, %d", resp.Topics[0].ErrorCode, resp.Topics[1].ErrorCode, utils.ErrNoError, utils.ErrInvalidConfig)
+	}
+
+	topic, _ := topics.GetTopic("test-topic")
+	if value, _ := topic.Config(metadata.TopicConfigMessageTimestampType); value != metadata.TimestampTypeLogAppendTime {
+		t.Errorf("%s = %q, want %s", metadata.TopicConfigMessageTimestampType, value, metadata.TimestampTypeLogAppendTime)
+	}
+	if _, ok := topics.GetTopic("invalid-topic"); ok {
+		t.Error("the topic with an invalid config was created")
+	}
+}
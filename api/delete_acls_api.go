@@ -0,0 +1,82 @@
+package api
+
+import (
+	"opentalaria/auth"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+)
+
+type DeleteACLsAPI struct {
+	Request Request
+}
+
+func (d DeleteACLsAPI) Name() string {
+	return "DeleteAcls"
+}
+
+func (d DeleteACLsAPI) GetRequest() Request {
+	return d.Request
+}
+
+func (d DeleteACLsAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.DeleteAclsResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (d DeleteACLsAPI) GeneratePayload() ([]byte, error) {
+	req := *d.GetRequest().Body.(*protocol.DeleteAclsRequest)
+
+	resp := GenerateDeleteACLsResponse(d.GetRequest().Header.RequestApiVersion, req, d.GetRequest().ACLs)
+
+	return encodeResponse(d.GetRequest(), resp)
+}
+
+// GenerateDeleteACLsResponse removes the ACLs matching the filters of the request, and returns the removed ACLs per filter.
+func GenerateDeleteACLsResponse(version int16, req protocol.DeleteAclsRequest, acls *auth.ACLStore) *protocol.DeleteAclsResponse {
+	resp := protocol.DeleteAclsResponse{
+		Version:       version,
+		FilterResults: []protocol.DeleteAclsFilterResult{},
+	}
+
+	for _, f := range req.Filters {
+		result := protocol.DeleteAclsFilterResult{
+			Version:      version,
+			ErrorCode:    int16(utils.ErrNoError),
+			MatchingAcls: []protocol.DeleteAclsMatchingACL{},
+		}
+
+		filter := auth.ACLFilter{
+			ResourceType: auth.ResourceType(f.ResourceTypeFilter),
+			ResourceName: f.ResourceNameFilter,
+			PatternType:  auth.PatternType(f.PatternTypeFilter),
+			Principal:    f.PrincipalFilter,
+			Host:         f.HostFilter,
+			Operation:    auth.Operation(f.Operation),
+			Permission:   auth.Permission(f.PermissionType),
+		}
+		if err := filter.Validate(); err != nil {
+			msg := err.Error()
+			result.ErrorCode = int16(utils.ErrInvalidRequest)
+			result.ErrorMessage = &msg
+			resp.FilterResults = append(resp.FilterResults, result)
+			continue
+		}
+
+		for _, acl := range acls.Delete(filter) {
+			result.MatchingAcls = append(result.MatchingAcls, protocol.DeleteAclsMatchingACL{
+				Version:        version,
+				ErrorCode:      int16(utils.ErrNoError),
+				ResourceType:   int8(acl.ResourceType),
+				ResourceName:   acl.ResourceName,
+				PatternType:    int8(acl.PatternType),
+				Principal:      acl.Principal,
+				Host:           acl.Host,
+				Operation:      int8(acl.Operation),
+				PermissionType: int8(acl.Permission),
+			})
+		}
+
+		resp.FilterResults = append(resp.FilterResults, result)
+	}
+
+	return &resp
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/storage"
+	"opentalaria/utils"
+)
+
+type DeleteTopicsAPI struct {
+	Request Request
+}
+
+func (d DeleteTopicsAPI) Name() string {
+	return "DeleteTopics"
+}
+
+func (d DeleteTopicsAPI) GetRequest() Request {
+	return d.Request
+}
+
+func (d DeleteTopicsAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.DeleteTopicsResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (d DeleteTopicsAPI) GeneratePayload() ([]byte, error) {
+	req := *d.GetRequest().Body.(*protocol.DeleteTopicsRequest)
+
+	resp := GenerateDeleteTopicsResponse(d.GetRequest().Header.RequestApiVersion, req, d.GetRequest().Topics, d.GetRequest().Logs)
+
+	return encodeResponse(d.GetRequest(), resp)
+}
+
+func GenerateDeleteTopicsResponse(version int16, req protocol.DeleteTopicsRequest, topics *metadata.TopicRegistry, logs *storage.LogManager) *protocol.DeleteTopicsResponse {
+	response := protocol.DeleteTopicsResponse{}
+
+	response.Version = version
+
+	// up to v5 topics are identified by name only
+	for _, name := range req.TopicNames {
+		topic, err := topics.DeleteTopic(name)
+		response.Responses = append(response.Responses, deletableTopicResult(version, name, topic, err))
+	}
+
+	// from v6 topics are identified either by name or by topic ID
+	for _, state := range req.Topics {
+		if state.Name != nil {
+			topic, err := topics.DeleteTopic(*state.Name)
+			response.Responses = append(response.Responses, deletableTopicResult(version, *state.Name, topic, err))
+		} else {
+			topic, err := topics.DeleteTopicByID(state.TopicID)
+			response.Responses = append(response.Responses, deletableTopicResult(version, topic.Name, topic, err))
+		}
+	}
+
+	for _, result := range response.Responses {
+		if result.ErrorCode == int16(utils.ErrNoError) {
+			logs.DeleteTopic(*result.Name)
+		}
+	}
+
+	return &response
+}
+
+func deletableTopicResult(version int16, name string, topic metadata.Topic, err error) protocol.DeletableTopicResult {
+	result := protocol.DeletableTopicResult{
+		Version:   version,
+		TopicID:   topic.TopicID,
+		ErrorCode: int16(utils.ErrNoError),
+	}
+
+	// the topic name is nullable, it is unknown if a topic ID which doesn't exist was requested
+	if name != "" {
+		result.Name = &name
+	}
+
+	if err != nil {
+		result.ErrorCode = int16(utils.ErrorCode(err))
+		errMsg := err.Error()
+		result.ErrorMessage = &errMsg
+	}
+
+	return result
+}
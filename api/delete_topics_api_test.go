@@ -0,0 +1,70 @@
+package api
+
+import (
+	"opentalaria/auth"
+	"opentalaria/config"
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/storage"
+	"opentalaria/utils"
+	"testing"
+)
+
+func TestDeleteTopics_Metadata(t *testing.T) {
+	conf := config.MockConfig()
+	topics := metadata.NewTopicRegistry()
+
+	GenerateCreateTopicsResponse(4, protocol.CreateTopicsRequest{
+		Version: 4,
+		Topics: []protocol.CreatableTopic{
+			{Name: "test-topic", NumPartitions: 1, ReplicationFactor: 1},
+			{Name: "other-topic", NumPartitions: 1, ReplicationFactor: 1},
+		},
+	}, conf, topics, nil, nil)
+
+	resp := GenerateDeleteTopicsResponse(5, protocol.DeleteTopicsRequest{
+		Version:    5,
+		TopicNames: []string{"test-topic", "unknown-topic"},
+	}, topics, storage.NewLogManager())
+
+	if len(resp.Responses) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(resp.Responses))
+	}
+	if resp.Responses[0].ErrorCode != int16(utils.ErrNoError) {
+		t.Errorf("test-topic error code = %d, want %d", resp.Responses[0].ErrorCode, utils.ErrNoError)
+	}
+	if resp.Responses[1].ErrorCode != int16(utils.ErrUnknownTopicOrPartition) {
+		t.Errorf("unknown-topic error code = %d, want %d", resp.Responses[1].ErrorCode, utils.ErrUnknownTopicOrPartition)
+	}
+	if _, err := protocol.Encode(resp); err != nil {
+		t.Errorf("error encoding response: %v", err)
+	}
+
+	metadataResp := GenerateMetadataResponse(8, protocol.MetadataRequest{Version: 8}, conf, topics, nil, nil, nil, auth.Anonymous)
+	if len(metadataResp.Topics) != 1 || *metadataResp.Topics[0].Name != "other-topic" {
+		t.Errorf("expected only other-topic in metadata response, got %v", metadataResp.Topics)
+	}
+}
+
+func TestDeleteTopics_ByTopicID(t *testing.T) {
+	topics := metadata.NewTopicRegistry()
+	created, err := topics.CreateTopic("test-topic", 1, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp := GenerateDeleteTopicsResponse(6, protocol.DeleteTopicsRequest{
+		Version: 6,
+		Topics:  []protocol.DeleteTopicState{{TopicID: created.TopicID}},
+	}, topics, storage.NewLogManager())
+
+	if resp.Responses[0].ErrorCode != int16(utils.ErrNoError) {
+		t.Errorf("error code = %d, want %d", resp.Responses[0].ErrorCode, utils.ErrNoError)
+	}
+	if resp.Responses[0].Name == nil || *resp.Responses[0].Name != "test-topic" {
+		t.Errorf("expected the deleted topic name in the response, got %v", resp.Responses[0].Name)
+	}
+	if _, ok := topics.GetTopic("test-topic"); ok {
+		t.Error("topic should be deleted")
+	}
+}
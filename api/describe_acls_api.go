@@ -0,0 +1,105 @@
+package api
+
+import (
+	"fmt"
+	"opentalaria/auth"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+)
+
+type DescribeACLsAPI struct {
+	Request Request
+}
+
+func (d DescribeACLsAPI) Name() string {
+	return "DescribeAcls"
+}
+
+func (d DescribeACLsAPI) GetRequest() Request {
+	return d.Request
+}
+
+func (d DescribeACLsAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.DescribeAclsResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (d DescribeACLsAPI) GeneratePayload() ([]byte, error) {
+	req := *d.GetRequest().Body.(*protocol.DescribeAclsRequest)
+
+	resp := GenerateDescribeACLsResponse(d.GetRequest().Header.RequestApiVersion, req, d.GetRequest().ACLs)
+
+	return encodeResponse(d.GetRequest(), resp)
+}
+
+// ErrorPayload answers the request with a top-level error code.
+func (d DescribeACLsAPI) ErrorPayload(kerr utils.KError) ([]byte, error) {
+	resp := protocol.DescribeAclsResponse{
+		Version:   d.GetRequest().Header.RequestApiVersion,
+		ErrorCode: int16(kerr),
+		Resources: []protocol.DescribeAclsResource{},
+	}
+	if !resp.IsValidVersion() {
+		return nil, fmt.Errorf("unknown DescribeAcls response version %d", resp.Version)
+	}
+
+	return encodeResponse(d.GetRequest(), &resp)
+}
+
+// GenerateDescribeACLsResponse returns the ACLs matching the filter of the request, grouped by their resource pattern.
+func GenerateDescribeACLsResponse(version int16, req protocol.DescribeAclsRequest, acls *auth.ACLStore) *protocol.DescribeAclsResponse {
+	resp := protocol.DescribeAclsResponse{
+		Version:   version,
+		ErrorCode: int16(utils.ErrNoError),
+		Resources: []protocol.DescribeAclsResource{},
+	}
+
+	filter := auth.ACLFilter{
+		ResourceType: auth.ResourceType(req.ResourceTypeFilter),
+		ResourceName: req.ResourceNameFilter,
+		PatternType:  auth.PatternType(req.PatternTypeFilter),
+		Principal:    req.PrincipalFilter,
+		Host:         req.HostFilter,
+		Operation:    auth.Operation(req.Operation),
+		Permission:   auth.Permission(req.PermissionType),
+	}
+	if err := filter.Validate(); err != nil {
+		msg := err.Error()
+		resp.ErrorCode = int16(utils.ErrInvalidRequest)
+		resp.ErrorMessage = &msg
+		return &resp
+	}
+
+	type resourcePattern struct {
+		resourceType auth.ResourceType
+		name         string
+		patternType  auth.PatternType
+	}
+
+	// index of the resource pattern in the response
+	resources := map[resourcePattern]int{}
+	for _, acl := range acls.Describe(filter) {
+		key := resourcePattern{acl.ResourceType, acl.ResourceName, acl.PatternType}
+		i, ok := resources[key]
+		if !ok {
+			i = len(resp.Resources)
+			resources[key] = i
+			resp.Resources = append(resp.Resources, protocol.DescribeAclsResource{
+				Version:      version,
+				ResourceType: int8(acl.ResourceType),
+				ResourceName: acl.ResourceName,
+				PatternType:  int8(acl.PatternType),
+				Acls:         []protocol.AclDescription{},
+			})
+		}
+
+		resp.Resources[i].Acls = append(resp.Resources[i].Acls, protocol.AclDescription{
+			Version:        version,
+			Principal:      acl.Principal,
+			Host:           acl.Host,
+			Operation:      int8(acl.Operation),
+			PermissionType: int8(acl.Permission),
+		})
+	}
+
+	return &resp
+}
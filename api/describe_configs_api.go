@@ -0,0 +1,261 @@
+package api
+
+import (
+	"fmt"
+	"opentalaria/config"
+	"opentalaria/logger"
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// resource types of the config APIs
+const (
+	resourceTypeTopic        int8 = 2
+	resourceTypeBroker       int8 = 4
+	resourceTypeBrokerLogger int8 = 8
+)
+
+// config sources reported in the DescribeConfigs response
+const (
+	configSourceUnknown       int8 = 0
+	configSourceDynamicTopic  int8 = 1
+	configSourceDynamicBroker int8 = 2
+	configSourceStaticBroker  int8 = 4
+	configSourceDefault       int8 = 5
+)
+
+// config types reported in the DescribeConfigs response from v3
+const (
+	configTypeUnknown  int8 = 0
+	configTypeBoolean  int8 = 1
+	configTypeString   int8 = 2
+	configTypeInt      int8 = 3
+	configTypeLong     int8 = 5
+	configTypeDouble   int8 = 6
+	configTypeList     int8 = 7
+	configTypePassword int8 = 9
+)
+
+type DescribeConfigsAPI struct {
+	Request Request
+}
+
+func (d DescribeConfigsAPI) Name() string {
+	return "DescribeConfigs"
+}
+
+func (d DescribeConfigsAPI) GetRequest() Request {
+	return d.Request
+}
+
+func (d DescribeConfigsAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.DescribeConfigsResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (d DescribeConfigsAPI) GeneratePayload() ([]byte, error) {
+	req := *d.GetRequest().Body.(*protocol.DescribeConfigsRequest)
+
+	resp := GenerateDescribeConfigsResponse(d.GetRequest().Header.RequestApiVersion, req, d.GetRequest().Config, d.GetRequest().Topics)
+
+	return encodeResponse(d.GetRequest(), resp)
+}
+
+// GenerateDescribeConfigsResponse describes the configuration of the broker, as read by viper from the config file,
+// the environment and the defaults, and the configs of topics, with the overrides set when they were created.
+func GenerateDescribeConfigsResponse(version int16, req protocol.DescribeConfigsRequest, config *config.Config, topics *metadata.TopicRegistry) *protocol.DescribeConfigsResponse {
+	resp := protocol.DescribeConfigsResponse{
+		Version: version,
+		Results: []protocol.DescribeConfigsResult{},
+	}
+
+	for _, resource := range req.Resources {
+		result := protocol.DescribeConfigsResult{
+			Version:      version,
+			ErrorCode:    int16(utils.ErrNoError),
+			ResourceType: resource.ResourceType,
+			ResourceName: resource.ResourceName,
+			Configs:      []protocol.DescribeConfigsResourceResult{},
+		}
+
+		switch resource.ResourceType {
+		case resourceTypeBroker:
+			if !isLocalBrokerResource(config, resource.ResourceName) {
+				setDescribeConfigsError(&result, utils.ErrInvalidRequest, fmt.Sprintf("unexpected broker id %s, expected %d", resource.ResourceName, config.Broker.BrokerID))
+				break
+			}
+
+			result.Configs = describeBrokerConfigs(version, config, resource.ConfigurationKeys, req.IncludeSynonyms)
+		case resourceTypeTopic:
+			topic, ok := topics.GetTopic(resource.ResourceName)
+			if !ok {
+				setDescribeConfigsError(&result, utils.ErrUnknownTopicOrPartition, fmt.Sprintf("topic %s does not exist", resource.ResourceName))
+				break
+			}
+
+			result.Configs = describeTopicConfigs(version, topic, resource.ConfigurationKeys, req.IncludeSynonyms)
+		default:
+			setDescribeConfigsError(&result, utils.ErrInvalidRequest, fmt.Sprintf("unsupported resource type %d", resource.ResourceType))
+		}
+
+		resp.Results = append(resp.Results, result)
+	}
+
+	return &resp
+}
+
+// isLocalBrokerResource reports whether the resource name of a broker resource refers to this broker.
+// An empty name refers to the cluster-wide default, which is the broker configuration as well in a single node cluster.
+func isLocalBrokerResource(config *config.Config, resourceName string) bool {
+	return resourceName == "" || resourceName == strconv.Itoa(int(config.Broker.BrokerID))
+}
+
+func setDescribeConfigsError(result *protocol.DescribeConfigsResult, kerr utils.KError, msg string) {
+	result.ErrorCode = int16(kerr)
+	result.ErrorMessage = &msg
+}
+
+// describeBrokerConfigs returns the requested configuration keys, or all known keys if keys is nil.
+// Keys which are not known to the broker are returned without a value. Only the keys in dynamicBrokerConfigs are writable.
+func describeBrokerConfigs(version int16, conf *config.Config, keys []string, includeSynonyms bool) []protocol.DescribeConfigsResourceResult {
+	if keys == nil {
+		keys = conf.Env.AllKeys()
+		slices.Sort(keys)
+	}
+
+	configs := []protocol.DescribeConfigsResourceResult{}
+	for _, key := range keys {
+		_, dynamic := dynamicBrokerConfigs[key]
+
+		entry := protocol.DescribeConfigsResourceResult{
+			Version:      version,
+			Name:         key,
+			ReadOnly:     !dynamic,
+			ConfigSource: configSourceUnknown,
+			IsSensitive:  config.IsSensitive(key),
+			Synonyms:     []protocol.DescribeConfigsSynonym{},
+			ConfigType:   configTypeUnknown,
+		}
+
+		if conf.Env.IsSet(key) {
+			value := conf.Env.Get(key)
+
+			entry.ConfigType = configType(value)
+			entry.ConfigSource = configSourceDefault
+			if conf.IsSetByUser(key) {
+				entry.ConfigSource = configSourceStaticBroker
+			}
+
+			// the log level can be changed at runtime, which viper doesn't know about
+			if key == "log.level" {
+				value = strings.ToLower(logger.LevelName(conf.LogLevel.Level()))
+				if conf.IsLogLevelOverridden() {
+					entry.ConfigSource = configSourceDynamicBroker
+				}
+			}
+
+			// sensitive values are never returned, only the fact that they are set
+			if entry.IsSensitive {
+				entry.ConfigType = configTypePassword
+			} else {
+				s := formatConfigValue(value)
+				entry.Value = &s
+			}
+		}
+
+		if includeSynonyms && entry.ConfigSource != configSourceUnknown {
+			entry.Synonyms = append(entry.Synonyms, protocol.DescribeConfigsSynonym{
+				Version: version,
+				Name:    entry.Name,
+				Value:   entry.Value,
+				Source:  entry.ConfigSource,
+			})
+		}
+
+		configs = append(configs, entry)
+	}
+
+	return configs
+}
+
+// describeTopicConfigs returns the requested configs of the topic, or all configs supported by the broker if keys is nil.
+// Configs which are not supported are returned without a value. Topic configs can't be altered yet, so they are read-only.
+func describeTopicConfigs(version int16, topic metadata.Topic, keys []string, includeSynonyms bool) []protocol.DescribeConfigsResourceResult {
+	if keys == nil {
+		keys = metadata.TopicConfigNames()
+	}
+
+	configs := []protocol.DescribeConfigsResourceResult{}
+	for _, key := range keys {
+		entry := protocol.DescribeConfigsResourceResult{
+			Version:      version,
+			Name:         key,
+			ReadOnly:     true,
+			ConfigSource: configSourceUnknown,
+			Synonyms:     []protocol.DescribeConfigsSynonym{},
+			ConfigType:   configTypeUnknown,
+		}
+
+		if value, ok := topic.Config(key); ok {
+			entry.Value = &value
+			entry.ConfigType = configTypeString
+			entry.ConfigSource = configSourceDefault
+			if _, overridden := topic.Configs[key]; overridden {
+				entry.ConfigSource = configSourceDynamicTopic
+			}
+		}
+
+		if includeSynonyms && entry.ConfigSource != configSourceUnknown {
+			entry.Synonyms = append(entry.Synonyms, protocol.DescribeConfigsSynonym{
+				Version: version,
+				Name:    entry.Name,
+				Value:   entry.Value,
+				Source:  entry.ConfigSource,
+			})
+		}
+
+		configs = append(configs, entry)
+	}
+
+	return configs
+}
+
+// formatConfigValue formats the value like Kafka does, with lists as comma-separated values.
+func formatConfigValue(value any) string {
+	switch v := value.(type) {
+	case []string:
+		return strings.Join(v, ",")
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// configType infers the type of the configuration from its value.
+// Values read from the environment are always strings, since viper doesn't know their types.
+func configType(value any) int8 {
+	switch value.(type) {
+	case bool:
+		return configTypeBoolean
+	case int, int32:
+		return configTypeInt
+	case int64:
+		return configTypeLong
+	case float32, float64:
+		return configTypeDouble
+	case []string, []any:
+		return configTypeList
+	case string:
+		return configTypeString
+	default:
+		return configTypeUnknown
+	}
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"opentalaria/config"
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"testing"
+)
+
+func newDescribeConfigsTestConfig(t *testing.T) *config.Config {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:9092")
+	t.Setenv("OT_SSL_KEYSTORE_PASSWORD", "hunter2")
+
+	conf, err := config.NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conf
+}
+
+func TestGenerateDescribeConfigsResponse_BrokerKeys(t *testing.T) {
+	conf := newDescribeConfigsTestConfig(t)
+
+	req := protocol.DescribeConfigsRequest{
+		Resources: []protocol.DescribeConfigsResource{{
+			ResourceType:      resourceTypeBroker,
+			ResourceName:      "",
+			ConfigurationKeys: []string{"listeners", "log.level", "ssl.keystore.password", "unknown.key"},
+		}},
+		IncludeSynonyms: true,
+	}
+
+	resp := GenerateDescribeConfigsResponse(4, req, conf, metadata.NewTopicRegistry())
+
+	// encode and decode the response, to make sure it survives the wire format
+	respBytes, err := protocol.Encode(resp)
+	if err != nil {
+		t.Fatalf("error encoding response: %v", err)
+	}
+	decoded := protocol.DescribeConfigsResponse{}
+	if _, err := protocol.VersionedDecode(respBytes, &decoded, 4); err != nil {
+		t.Fatalf("error decoding response: %v", err)
+	}
+
+	result := decoded.Results[0]
+	if result.ErrorCode != int16(utils.ErrNoError) {
+		t.Fatalf("error code = %d", result.ErrorCode)
+	}
+	if len(result.Configs) != 4 {
+		t.Fatalf("got %d configs, want 4", len(result.Configs))
+	}
+
+	listeners := result.Configs[0]
+	if listeners.Value == nil || *listeners.Value != "PLAINTEXT://localhost:9092" {
+		t.Errorf("listeners = %v, want PLAINTEXT://localhost:9092", listeners.Value)
+	}
+	if listeners.ConfigSource != configSourceStaticBroker {
+		t.Errorf("listeners source = %d, want %d", listeners.ConfigSource, configSourceStaticBroker)
+	}
+	if len(listeners.Synonyms) != 1 {
+		t.Errorf("listeners synonyms = %+v, want one", listeners.Synonyms)
+	}
+
+	logLevel := result.Configs[1]
+	if logLevel.Value == nil || *logLevel.Value != "warn" {
+		t.Errorf("log.level = %v, want the default warn", logLevel.Value)
+	}
+	if logLevel.ConfigSource != configSourceDefault {
+		t.Errorf("log.level source = %d, want %d", logLevel.ConfigSource, configSourceDefault)
+	}
+
+	password := result.Configs[2]
+	if !password.IsSensitive || password.Value != nil {
+		t.Errorf("ssl.keystore.password = %v, sensitive %v, want a redacted value", password.Value, password.IsSensitive)
+	}
+	if password.ConfigType != configTypePassword {
+		t.Errorf("ssl.keystore.password type = %d, want %d", password.ConfigType, configTypePassword)
+	}
+
+	unknown := result.Configs[3]
+	if unknown.Value != nil || !unknown.ReadOnly || unknown.ConfigSource != configSourceUnknown {
+		t.Errorf("unknown.key = %+v, want a read-only entry without value", unknown)
+	}
+}
+
+func TestGenerateDescribeConfigsResponse_AllBrokerKeys(t *testing.T) {
+	conf := newDescribeConfigsTestConfig(t)
+
+	req := protocol.DescribeConfigsRequest{
+		Resources: []protocol.DescribeConfigsResource{{
+			ResourceType: resourceTypeBroker,
+			ResourceName: "0",
+		}},
+	}
+	conf.Broker.BrokerID = 0
+
+	resp := GenerateDescribeConfigsResponse(1, req, conf, metadata.NewTopicRegistry())
+
+	found := false
+	for _, entry := range resp.Results[0].Configs {
+		if entry.Name == "socket.request.max.bytes" {
+			found = true
+			if entry.Value == nil || *entry.Value != "104857600" {
+				t.Errorf("socket.request.max.bytes = %v, want 104857600", entry.Value)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("socket.request.max.bytes missing from %+v", resp.Results[0].Configs)
+	}
+}
+
+func TestGenerateDescribeConfigsResponse_Errors(t *testing.T) {
+	conf := newDescribeConfigsTestConfig(t)
+	conf.Broker.BrokerID = 1
+
+	req := protocol.DescribeConfigsRequest{
+		Resources: []protocol.DescribeConfigsResource{
+			{ResourceType: resourceTypeBroker, ResourceName: "2"},
+			{ResourceType: resourceTypeTopic, ResourceName: "missing-topic"},
+			{ResourceType: resourceTypeBrokerLogger, ResourceName: "1"},
+		},
+	}
+
+	resp := GenerateDescribeConfigsResponse(2, req, conf, metadata.NewTopicRegistry())
+
+	want := []utils.KError{utils.ErrInvalidRequest, utils.ErrUnknownTopicOrPartition, utils.ErrInvalidRequest}
+	for i, result := range resp.Results {
+		if result.ErrorCode != int16(want[i]) {
+			t.Errorf("resource %s: error code = %d, want %d", result.ResourceName, result.ErrorCode, want[i])
+		}
+		if result.ErrorMessage == nil {
+			t.Errorf("resource %s: missing error message", result.ResourceName)
+		}
+	}
+}
+
+func TestGenerateDescribeConfigsResponse_Topic(t *testing.T) {
+	conf := newDescribeConfigsTestConfig(t)
+	topics := metadata.NewTopicRegistry()
+	for _, name := range []string{"default-topic", "test-topic"} {
+		if _, err := topics.CreateTopic(name, 1, 1, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := topics.SetTopicConfigs("test-topic", map[string]string{metadata.TopicConfigMessageTimestampType: metadata.TimestampTypeLogAppendTime}); err != nil {
+		t.Fatal(err)
+	}
+
+	req := protocol.DescribeConfigsRequest{
+		Resources: []protocol.DescribeConfigsResource{
+			{ResourceType: resourceTypeTopic, ResourceName: "default-topic"},
+			{ResourceType: resourceTypeTopic, ResourceName: "test-topic", ConfigurationKeys: []string{metadata.TopicConfigMessageTimestampType, "unknown.config"}},
+		},
+	}
+
+	resp := GenerateDescribeConfigsResponse(3, req, conf, topics)
+
+	tests := []struct {
+		result int
+		config int
+		name   string
+		// wantValue is empty for configs without a value
+		wantValue  string
+		wantSource int8
+	}{
+		{result: 0, config: 0, name: metadata.TopicConfigMessageTimestampType, wantValue: metadata.TimestampTypeCreateTime, wantSource: configSourceDefault},
+		{result: 1, config: 0, name: metadata.TopicConfigMessageTimestampType, wantValue: metadata.TimestampTypeLogAppendTime, wantSource: configSourceDynamicTopic},
+		{result: 1, config: 1, name: "unknown.config", wantSource: configSourceUnknown},
+	}
+	for _, tt := range tests {
+		result := resp.Results[tt.result]
+		if result.ErrorCode != int16(utils.ErrNoError) {
+			t.Fatalf("%s: error code = %d", result.ResourceName, result.ErrorCode)
+		}
+		entry := result.Configs[tt.config]
+		value := ""
+		if entry.Value != nil {
+			value = *entry.Value
+		}
+		if entry.Name != tt.name || entry.ConfigSource != tt.wantSource || value != tt.wantValue {
+			t.Errorf("%s: config %s = %q from source %d, want %s = %q from source %d", result.ResourceName, entry.Name, value, entry.ConfigSource, tt.name, tt.wantValue, tt.wantSource)
+		}
+	}
+
+	if _, err := protocol.Encode(resp); err != nil {
+		t.Errorf("error encoding response: %v", err)
+	}
+}
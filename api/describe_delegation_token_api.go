@@ -0,0 +1,36 @@
+package api
+
+import (
+	"opentalaria/protocol"
+	"opentalaria/utils"
+)
+
+type DescribeDelegationTokenAPI struct {
+	Request Request
+}
+
+func (d DescribeDelegationTokenAPI) Name() string {
+	return "DescribeDelegationToken"
+}
+
+func (d DescribeDelegationTokenAPI) GetRequest() Request {
+	return d.Request
+}
+
+func (d DescribeDelegationTokenAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.DescribeDelegationTokenResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (d DescribeDelegationTokenAPI) GeneratePayload() ([]byte, error) {
+	resp := GenerateDescribeDelegationTokenResponse(d.GetRequest().Header.RequestApiVersion)
+
+	return encodeResponse(d.GetRequest(), resp)
+}
+
+// GenerateDescribeDelegationTokenResponse rejects the request, there are no delegation tokens to describe.
+func GenerateDescribeDelegationTokenResponse(version int16) *protocol.DescribeDelegationTokenResponse {
+	return &protocol.DescribeDelegationTokenResponse{
+		Version:   version,
+		ErrorCode: int16(utils.ErrDelegationTokenAuthDisabled),
+	}
+}
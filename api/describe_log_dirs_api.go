@@ -0,0 +1,109 @@
+package api
+
+import (
+	"opentalaria/config"
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/storage"
+	"opentalaria/utils"
+)
+
+type DescribeLogDirsAPI struct {
+	Request Request
+}
+
+func (d DescribeLogDirsAPI) Name() string {
+	return "DescribeLogDirs"
+}
+
+func (d DescribeLogDirsAPI) GetRequest() Request {
+	return d.Request
+}
+
+func (d DescribeLogDirsAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.DescribeLogDirsResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (d DescribeLogDirsAPI) GeneratePayload() ([]byte, error) {
+	req := *d.GetRequest().Body.(*protocol.DescribeLogDirsRequest)
+	resp := GenerateDescribeLogDirsResponse(d.GetRequest().Header.RequestApiVersion, req, d.GetRequest().Config, d.GetRequest().Topics, d.GetRequest().Logs)
+
+	return encodeResponse(d.GetRequest(), resp)
+}
+
+// GenerateDescribeLogDirsResponse describes the partitions in the log dirs with the bytes of the record batches in their logs.
+// Partitions kept in memory, or without a log yet, are reported in the first of the log dirs. The total and usable bytes
+// of the log dirs are unknown. Without requested topics, all topics are described.
+// Requested topics and partitions which don't exist are left out, like Kafka does.
+func GenerateDescribeLogDirsResponse(version int16, req protocol.DescribeLogDirsRequest, config *config.Config, topics *metadata.TopicRegistry, logs *storage.LogManager) *protocol.DescribeLogDirsResponse {
+	response := protocol.DescribeLogDirsResponse{Version: version, ErrorCode: int16(utils.ErrNoError)}
+
+	for _, dir := range config.LogDirs {
+		response.Results = append(response.Results, protocol.DescribeLogDirsResult{
+			Version:     version,
+			ErrorCode:   int16(utils.ErrNoError),
+			LogDir:      dir,
+			Topics:      []protocol.DescribeLogDirsTopic{},
+			TotalBytes:  -1,
+			UsableBytes: -1,
+		})
+	}
+
+	for _, requested := range requestedLogDirPartitions(req, topics) {
+		for _, index := range requested.Partitions {
+			result := &response.Results[0]
+			partition := protocol.DescribeLogDirsPartition{Version: version, PartitionIndex: index}
+			// the log of a partition is created by the first produce request
+			if log, ok := logs.GetPartition(requested.Topic, index); ok {
+				partition.PartitionSize = log.Size()
+				for i := range response.Results {
+					if response.Results[i].LogDir == log.LogDir() {
+						result = &response.Results[i]
+					}
+				}
+			}
+
+			if n := len(result.Topics); n == 0 || result.Topics[n-1].Name != requested.Topic {
+				result.Topics = append(result.Topics, protocol.DescribeLogDirsTopic{Version: version, Name: requested.Topic})
+			}
+			topic := &result.Topics[len(result.Topics)-1]
+			topic.Partitions = append(topic.Partitions, partition)
+		}
+	}
+
+	return &response
+}
+
+// requestedLogDirPartitions returns the requested partitions which exist, all partitions if no topics were requested.
+func requestedLogDirPartitions(req protocol.DescribeLogDirsRequest, topics *metadata.TopicRegistry) []protocol.DescribableLogDirTopic {
+	var requested []protocol.DescribableLogDirTopic
+
+	if req.Topics == nil {
+		for _, topic := range topics.ListTopics() {
+			partitions := make([]int32, topic.NumPartitions)
+			for i := range partitions {
+				partitions[i] = int32(i)
+			}
+			requested = append(requested, protocol.DescribableLogDirTopic{Topic: topic.Name, Partitions: partitions})
+		}
+
+		return requested
+	}
+
+	for _, requestedTopic := range req.Topics {
+		topic, ok := topics.GetTopic(requestedTopic.Topic)
+		if !ok {
+			continue
+		}
+
+		existing := protocol.DescribableLogDirTopic{Topic: topic.Name}
+		for _, index := range requestedTopic.Partitions {
+			if index >= 0 && index < topic.NumPartitions {
+				existing.Partitions = append(existing.Partitions, index)
+			}
+		}
+		requested = append(requested, existing)
+	}
+
+	return requested
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"opentalaria/config"
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/storage"
+	"testing"
+	"time"
+)
+
+func TestGenerateDescribeLogDirsResponse(t *testing.T) {
+	conf := config.MockConfig()
+	conf.LogDirs = []string{"/data/logs-1", "/data/logs-2"}
+	topics := metadata.NewTopicRegistry()
+	for _, name := range []string{"a-topic", "b-topic"} {
+		if _, err := topics.CreateTopic(name, 2, 1, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	logs := storage.NewLogManager()
+
+	// a batch as it is received from the wire, with its encoded size
+	batch := protocol.RecordBatch{
+		BaseTimestamp:   time.Now(),
+		MaxTimestamp:    time.Now(),
+		ProducerId:      -1,
+		ProducerEpoch:   -1,
+		BaseSequence:    -1,
+		Records:         []protocol.Record{{Value: []byte("first")}, {OffsetDelta: 1, Value: []byte("second")}},
+		LastOffsetDelta: 1,
+	}
+	batchBytes, err := protocol.Encode(&batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := protocol.Decode(batchBytes, &batch); err != nil {
+		t.Fatal(err)
+	}
+
+	produce := protocol.ProduceRequest{Version: 8, Acks: -1, TopicData: []protocol.TopicProduceData{
+		{Name: "b-topic", PartitionData: []protocol.PartitionProduceData{
+			{Index: 1, Records: protocol.Records{Batches: []protocol.RecordBatch{batch}}},
+			{Index: 1, Records: protocol.Records{Batches: []protocol.RecordBatch{batch}}},
+		}},
+	}}
+	GenerateProduceResponse(produce.Version, produce, topics, logs)
+	produced := int64(2 * len(batchBytes))
+
+	t.Run("all topics", func(t *testing.T) {
+		resp := GenerateDescribeLogDirsResponse(4, protocol.DescribeLogDirsRequest{Version: 4}, conf, topics, logs)
+
+		if len(resp.Results) != 2 || resp.Results[0].LogDir != "/data/logs-1" || resp.Results[1].LogDir != "/data/logs-2" {
+			t.Fatalf("results = %+v, want the 2 log dirs", resp.Results)
+		}
+		if len(resp.Results[1].Topics) != 0 {
+			t.Errorf("topics of the second log dir = %+v, want none", resp.Results[1].Topics)
+		}
+
+		got := map[string][]int64{}
+		for _, topic := range resp.Results[0].Topics {
+			for _, p := range topic.Partitions {
+				got[topic.Name] = append(got[topic.Name], p.PartitionSize)
+			}
+		}
+		want := map[string][]int64{"a-topic": {0, 0}, "b-topic": {0, produced}}
+		for name, sizes := range want {
+			if len(got[name]) != len(sizes) || got[name][0] != sizes[0] || got[name][1] != sizes[1] {
+				t.Errorf("partition sizes of %s = %v, want %v", name, got[name], sizes)
+			}
+		}
+
+		if _, err := protocol.Encode(resp); err != nil {
+			t.Errorf("error encoding response: %v", err)
+		}
+	})
+
+	t.Run("requested topics", func(t *testing.T) {
+		req := protocol.DescribeLogDirsRequest{Version: 1, Topics: []protocol.DescribableLogDirTopic{
+			{Topic: "b-topic", Partitions: []int32{1, 5}},
+			{Topic: "unknown-topic", Partitions: []int32{0}},
+		}}
+		resp := GenerateDescribeLogDirsResponse(1, req, conf, topics, logs)
+
+		topics := resp.Results[0].Topics
+		if len(topics) != 1 || topics[0].Name != "b-topic" || len(topics[0].Partitions) != 1 {
+			t.Fatalf("topics = %+v, want partition 1 of b-topic", topics)
+		}
+		if p := topics[0].Partitions[0]; p.PartitionIndex != 1 || p.PartitionSize != produced {
+			t.Errorf("partition = %+v, want partition 1 with %d bytes", p, produced)
+		}
+
+		if _, err := protocol.Encode(resp); err != nil {
+			t.Errorf("error encoding response: %v", err)
+		}
+	})
+
+	t.Run("logs on disk", func(t *testing.T) {
+		conf := config.MockConfig()
+		conf.LogDirs = []string{t.TempDir(), t.TempDir()}
+		logs, err := storage.OpenLogManager(storage.DiskOptions{Dirs: conf.LogDirs, SegmentBytes: 1 << 20})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer logs.Close()
+
+		// the partitions are placed in both log dirs
+		produce := protocol.ProduceRequest{Version: 8, Acks: -1, TopicData: []protocol.TopicProduceData{
+			{Name: "a-topic", PartitionData: []protocol.PartitionProduceData{
+				{Index: 0, Records: protocol.Records{Batches: []protocol.RecordBatch{batch}}},
+				{Index: 1, Records: protocol.Records{Batches: []protocol.RecordBatch{batch}}},
+			}},
+		}}
+		GenerateProduceResponse(produce.Version, produce, topics, logs)
+
+		req := protocol.DescribeLogDirsRequest{Version: 4, Topics: []protocol.DescribableLogDirTopic{{Topic: "a-topic", Partitions: []int32{0, 1}}}}
+		resp := GenerateDescribeLogDirsResponse(4, req, conf, topics, logs)
+
+		for i, result := range resp.Results {
+			if len(result.Topics) != 1 || len(result.Topics[0].Partitions) != 1 {
+				t.Fatalf("topics of %s = %+v, want a partition of a-topic", result.LogDir, result.Topics)
+			}
+			if p := result.Topics[0].Partitions[0]; p.PartitionIndex != int32(i) || p.PartitionSize != int64(len(batchBytes)) {
+				t.Errorf("partition in %s = %+v, want partition %d with %d bytes", result.LogDir, p, i, len(batchBytes))
+			}
+		}
+	})
+}
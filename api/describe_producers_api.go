@@ -0,0 +1,59 @@
+package api
+
+import (
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+)
+
+type DescribeProducersAPI struct {
+	Request Request
+}
+
+func (d DescribeProducersAPI) Name() string {
+	return "DescribeProducers"
+}
+
+func (d DescribeProducersAPI) GetRequest() Request {
+	return d.Request
+}
+
+func (d DescribeProducersAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.DescribeProducersResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (d DescribeProducersAPI) GeneratePayload() ([]byte, error) {
+	req := *d.GetRequest().Body.(*protocol.DescribeProducersRequest)
+	resp := GenerateDescribeProducersResponse(d.GetRequest().Header.RequestApiVersion, req, d.GetRequest().Topics)
+
+	return encodeResponse(d.GetRequest(), resp)
+}
+
+// GenerateDescribeProducersResponse describes the active producers of the partitions. The broker doesn't track the state
+// of idempotent or transactional producers, so the partitions have no active producers.
+func GenerateDescribeProducersResponse(version int16, req protocol.DescribeProducersRequest, topics *metadata.TopicRegistry) *protocol.DescribeProducersResponse {
+	response := protocol.DescribeProducersResponse{Version: version}
+
+	for _, requestedTopic := range req.Topics {
+		topic, topicExists := topics.GetTopic(requestedTopic.Name)
+
+		topicResponse := protocol.TopicResponse{Version: version, Name: requestedTopic.Name}
+		for _, index := range requestedTopic.PartitionIndexes {
+			partition := protocol.PartitionResponse{
+				Version:         version,
+				PartitionIndex:  index,
+				ErrorCode:       int16(utils.ErrNoError),
+				ActiveProducers: []protocol.ProducerState{},
+			}
+			if !topicExists || index < 0 || index >= topic.NumPartitions {
+				partition.ErrorCode = int16(utils.ErrUnknownTopicOrPartition)
+			}
+
+			topicResponse.Partitions = append(topicResponse.Partitions, partition)
+		}
+
+		response.Topics = append(response.Topics, topicResponse)
+	}
+
+	return &response
+}
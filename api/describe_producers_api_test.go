@@ -0,0 +1,44 @@
+package api
+
+import (
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"testing"
+)
+
+func TestGenerateDescribeProducersResponse(t *testing.T) {
+	topics := metadata.NewTopicRegistry()
+	if _, err := topics.CreateTopic("test-topic", 2, 1, false); err != nil {
+		t.Fatal(err)
+	}
+
+	req := protocol.DescribeProducersRequest{Topics: []protocol.TopicRequest_DescribeProducersRequest{
+		{Name: "test-topic", PartitionIndexes: []int32{0, 1, 2}},
+		{Name: "unknown-topic", PartitionIndexes: []int32{0}},
+	}}
+	resp := GenerateDescribeProducersResponse(0, req, topics)
+
+	want := [][]utils.KError{
+		{utils.ErrNoError, utils.ErrNoError, utils.ErrUnknownTopicOrPartition},
+		{utils.ErrUnknownTopicOrPartition},
+	}
+	if len(resp.Topics) != len(want) {
+		t.Fatalf("got %d topics, want %d", len(resp.Topics), len(want))
+	}
+	for i, topic := range resp.Topics {
+		if topic.Name != req.Topics[i].Name || len(topic.Partitions) != len(want[i]) {
+			t.Fatalf("topic %d = %s with %d partitions, want %s with %d partitions", i, topic.Name, len(topic.Partitions), req.Topics[i].Name, len(want[i]))
+		}
+		for j, p := range topic.Partitions {
+			if p.PartitionIndex != req.Topics[i].PartitionIndexes[j] || p.ErrorCode != int16(want[i][j]) || len(p.ActiveProducers) != 0 {
+				t.Errorf("%s partition %d = error code %d with %d producers, want error code %d without producers",
+					topic.Name, p.PartitionIndex, p.ErrorCode, len(p.ActiveProducers), want[i][j])
+			}
+		}
+	}
+
+	if _, err := protocol.Encode(resp); err != nil {
+		t.Errorf("error encoding response: %v", err)
+	}
+}
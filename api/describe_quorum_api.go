@@ -0,0 +1,126 @@
+package api
+
+import (
+	"fmt"
+	"opentalaria/config"
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"time"
+)
+
+type DescribeQuorumAPI struct {
+	Request Request
+}
+
+func (d DescribeQuorumAPI) Name() string {
+	return "DescribeQuorum"
+}
+
+func (d DescribeQuorumAPI) GetRequest() Request {
+	return d.Request
+}
+
+func (d DescribeQuorumAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.DescribeQuorumResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (d DescribeQuorumAPI) GeneratePayload() ([]byte, error) {
+	req := *d.GetRequest().Body.(*protocol.DescribeQuorumRequest)
+
+	resp := GenerateDescribeQuorumResponse(d.GetRequest().Header.RequestApiVersion, req, d.GetRequest().Config, d.GetRequest().Raft)
+
+	return encodeResponse(d.GetRequest(), resp)
+}
+
+// ErrorPayload answers the request with a top-level error code.
+func (d DescribeQuorumAPI) ErrorPayload(kerr utils.KError) ([]byte, error) {
+	resp := protocol.DescribeQuorumResponse{Version: d.GetRequest().Header.RequestApiVersion, ErrorCode: int16(kerr)}
+	if !resp.IsValidVersion() {
+		return nil, fmt.Errorf("unknown DescribeQuorum response version %d", resp.Version)
+	}
+
+	return encodeResponse(d.GetRequest(), &resp)
+}
+
+// GenerateDescribeQuorumResponse describes the leader and the replicas of the metadata partition.
+// Since OpenTalaria runs a single-node quorum, the local node is the only voter and there are no observers.
+func GenerateDescribeQuorumResponse(version int16, req protocol.DescribeQuorumRequest, config *config.Config, raft *metadata.RaftState) *protocol.DescribeQuorumResponse {
+	resp := protocol.DescribeQuorumResponse{
+		Version:   version,
+		ErrorCode: int16(utils.ErrNoError),
+	}
+
+	leaderID, leaderEpoch := raft.Leader()
+	logEndOffset := raft.MetadataLog().LogEndOffset()
+
+	for _, describeTopic := range req.Topics {
+		topicResponse := protocol.TopicData_DescribeQuorumResponse{
+			Version:   version,
+			TopicName: describeTopic.TopicName,
+		}
+
+		for _, describePartition := range describeTopic.Partitions {
+			partitionResponse := protocol.PartitionData_DescribeQuorumResponse{
+				Version:        version,
+				PartitionIndex: describePartition.PartitionIndex,
+				ErrorCode:      int16(utils.ErrNoError),
+				LeaderID:       -1,
+				LeaderEpoch:    -1,
+				HighWatermark:  -1,
+				CurrentVoters:  []protocol.ReplicaState_DescribeQuorumResponse{},
+				Observers:      []protocol.ReplicaState_DescribeQuorumResponse{},
+			}
+
+			if describeTopic.TopicName != metadata.ClusterMetadataTopic || describePartition.PartitionIndex != 0 {
+				partitionResponse.ErrorCode = int16(utils.ErrUnknownTopicOrPartition)
+				topicResponse.Partitions = append(topicResponse.Partitions, partitionResponse)
+				continue
+			}
+
+			partitionResponse.LeaderID = leaderID
+			partitionResponse.LeaderEpoch = leaderEpoch
+			// with a single voter, every record in the metadata log is committed right away
+			partitionResponse.HighWatermark = logEndOffset
+
+			for _, voterID := range raft.Voters() {
+				voter := protocol.ReplicaState_DescribeQuorumResponse{
+					Version:               version,
+					ReplicaID:             voterID,
+					LogEndOffset:          -1,
+					LastFetchTimestamp:    -1,
+					LastCaughtUpTimestamp: -1,
+				}
+
+				// the leader reports its own log end offset, and is always caught up with itself
+				if voterID == leaderID {
+					voter.LogEndOffset = logEndOffset
+					voter.LastCaughtUpTimestamp = time.Now().UnixMilli()
+				}
+
+				partitionResponse.CurrentVoters = append(partitionResponse.CurrentVoters, voter)
+			}
+
+			topicResponse.Partitions = append(topicResponse.Partitions, partitionResponse)
+		}
+
+		resp.Topics = append(resp.Topics, topicResponse)
+	}
+
+	// from v2 the response includes the endpoints of the voters, which is only the local node for now
+	node := protocol.Node{
+		Version: version,
+		NodeID:  raft.NodeID(),
+	}
+	for _, listener := range config.Broker.AdvertisedListeners {
+		node.Listeners = append(node.Listeners, protocol.Listener_DescribeQuorumResponse{
+			Version: version,
+			Name:    listener.ListenerName,
+			Host:    listener.Host,
+			Port:    uint16(listener.Port),
+		})
+	}
+	resp.Nodes = append(resp.Nodes, node)
+
+	return &resp
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"opentalaria/config"
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"testing"
+)
+
+func TestGenerateDescribeQuorumResponse_SingleNode(t *testing.T) {
+	conf := config.MockConfig()
+	raft := metadata.NewStandaloneRaftState(conf.Broker.BrokerID)
+
+	req := protocol.DescribeQuorumRequest{
+		Topics: []protocol.TopicData_DescribeQuorumRequest{{
+			TopicName:  metadata.ClusterMetadataTopic,
+			Partitions: []protocol.PartitionData_DescribeQuorumRequest{{PartitionIndex: 0}},
+		}},
+	}
+
+	for _, version := range []int16{0, 1, 2} {
+		req.Version = version
+		resp := GenerateDescribeQuorumResponse(version, req, conf, raft)
+
+		// encode and decode the response, to make sure the nested structure survives the wire format of this version
+		respBytes, err := protocol.Encode(resp)
+		if err != nil {
+			t.Fatalf("v%d: error encoding response: %v", version, err)
+		}
+		decoded := protocol.DescribeQuorumResponse{}
+		if _, err := protocol.VersionedDecode(respBytes, &decoded, version); err != nil {
+			t.Fatalf("v%d: error decoding response: %v", version, err)
+		}
+
+		partition := decoded.Topics[0].Partitions[0]
+		if partition.ErrorCode != int16(utils.ErrNoError) {
+			t.Fatalf("v%d: error code = %d", version, partition.ErrorCode)
+		}
+		if partition.LeaderID != conf.Broker.BrokerID {
+			t.Errorf("v%d: leader = %d, want the local broker %d", version, partition.LeaderID, conf.Broker.BrokerID)
+		}
+		if partition.LeaderEpoch != 0 {
+			t.Errorf("v%d: leader epoch = %d, want 0", version, partition.LeaderEpoch)
+		}
+		if len(partition.CurrentVoters) != 1 || partition.CurrentVoters[0].ReplicaID != conf.Broker.BrokerID {
+			t.Errorf("v%d: voters = %+v, want only the local broker", version, partition.CurrentVoters)
+		}
+		if len(partition.Observers) != 0 {
+			t.Errorf("v%d: observers = %+v, want none", version, partition.Observers)
+		}
+		if version >= 2 && (len(decoded.Nodes) != 1 || decoded.Nodes[0].NodeID != conf.Broker.BrokerID) {
+			t.Errorf("v%d: nodes = %+v, want only the local broker", version, decoded.Nodes)
+		}
+	}
+}
+
+func TestGenerateDescribeQuorumResponse_UnknownPartition(t *testing.T) {
+	req := protocol.DescribeQuorumRequest{
+		Topics: []protocol.TopicData_DescribeQuorumRequest{{
+			TopicName:  "test-topic",
+			Partitions: []protocol.PartitionData_DescribeQuorumRequest{{PartitionIndex: 0}},
+		}},
+	}
+
+	resp := GenerateDescribeQuorumResponse(1, req, config.MockConfig(), metadata.NewStandaloneRaftState(1))
+
+	if errCode := resp.Topics[0].Partitions[0].ErrorCode; errCode != int16(utils.ErrUnknownTopicOrPartition) {
+		t.Errorf("error code = %d, want %d", errCode, utils.ErrUnknownTopicOrPartition)
+	}
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"opentalaria/protocol"
+	"opentalaria/utils"
+)
+
+type DescribeTransactionsAPI struct {
+	Request Request
+}
+
+func (d DescribeTransactionsAPI) Name() string {
+	return "DescribeTransactions"
+}
+
+func (d DescribeTransactionsAPI) GetRequest() Request {
+	return d.Request
+}
+
+func (d DescribeTransactionsAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.DescribeTransactionsResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (d DescribeTransactionsAPI) GeneratePayload() ([]byte, error) {
+	req := *d.GetRequest().Body.(*protocol.DescribeTransactionsRequest)
+	resp := GenerateDescribeTransactionsResponse(d.GetRequest().Header.RequestApiVersion, req)
+
+	return encodeResponse(d.GetRequest(), resp)
+}
+
+// GenerateDescribeTransactionsResponse answers every transactional ID with TRANSACTIONAL_ID_NOT_FOUND.
+// Transactions are not supported, the broker has no transaction coordinator which could know them.
+func GenerateDescribeTransactionsResponse(version int16, req protocol.DescribeTransactionsRequest) *protocol.DescribeTransactionsResponse {
+	response := protocol.DescribeTransactionsResponse{Version: version}
+
+	for _, id := range req.TransactionalIds {
+		response.TransactionStates = append(response.TransactionStates, protocol.TransactionState_DescribeTransactionsResponse{
+			Version:         version,
+			ErrorCode:       int16(utils.ErrTransactionalIDNotFound),
+			TransactionalID: id,
+			ProducerID:      -1,
+			ProducerEpoch:   -1,
+		})
+	}
+
+	return &response
+}
@@ -0,0 +1,25 @@
+package api
+
+import (
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"testing"
+)
+
+func TestGenerateDescribeTransactionsResponse(t *testing.T) {
+	req := protocol.DescribeTransactionsRequest{TransactionalIds: []string{"txn-1", "txn-2"}}
+	resp := GenerateDescribeTransactionsResponse(0, req)
+
+	if len(resp.TransactionStates) != 2 {
+		t.Fatalf("got %d transaction states, want 2", len(resp.TransactionStates))
+	}
+	for i, state := range resp.TransactionStates {
+		if state.TransactionalID != req.TransactionalIds[i] || state.ErrorCode != int16(utils.ErrTransactionalIDNotFound) || state.ProducerID != -1 {
+			t.Errorf("transaction state %d = %+v, want %s not found", i, state, req.TransactionalIds[i])
+		}
+	}
+
+	if _, err := protocol.Encode(resp); err != nil {
+		t.Errorf("error encoding response: %v", err)
+	}
+}
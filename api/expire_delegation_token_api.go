@@ -0,0 +1,36 @@
+package api
+
+import (
+	"opentalaria/protocol"
+	"opentalaria/utils"
+)
+
+type ExpireDelegationTokenAPI struct {
+	Request Request
+}
+
+func (d ExpireDelegationTokenAPI) Name() string {
+	return "ExpireDelegationToken"
+}
+
+func (d ExpireDelegationTokenAPI) GetRequest() Request {
+	return d.Request
+}
+
+func (d ExpireDelegationTokenAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.ExpireDelegationTokenResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (d ExpireDelegationTokenAPI) GeneratePayload() ([]byte, error) {
+	resp := GenerateExpireDelegationTokenResponse(d.GetRequest().Header.RequestApiVersion)
+
+	return encodeResponse(d.GetRequest(), resp)
+}
+
+// GenerateExpireDelegationTokenResponse rejects the request, there are no delegation tokens to expire.
+func GenerateExpireDelegationTokenResponse(version int16) *protocol.ExpireDelegationTokenResponse {
+	return &protocol.ExpireDelegationTokenResponse{
+		Version:   version,
+		ErrorCode: int16(utils.ErrDelegationTokenAuthDisabled),
+	}
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/storage"
+	"opentalaria/utils"
+	"reflect"
+	"time"
+)
+
+type FetchAPI struct {
+	Request Request
+}
+
+func (f FetchAPI) Name() string {
+	return "Fetch"
+}
+
+func (f FetchAPI) GetRequest() Request {
+	return f.Request
+}
+
+func (f FetchAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.FetchResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (f FetchAPI) GeneratePayload() ([]byte, error) {
+	req := *f.GetRequest().Body.(*protocol.FetchRequest)
+
+	resp, err := GenerateFetchResponse(f.GetRequest().Context(), f.GetRequest().Header.RequestApiVersion, req, f.GetRequest().Topics, f.GetRequest().Logs, f.GetRequest().Raft)
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeResponse(f.GetRequest(), resp)
+}
+
+// ErrorPayload answers the request with a top-level error code, which fetch responses have from v7.
+func (f FetchAPI) ErrorPayload(kerr utils.KError) ([]byte, error) {
+	resp := protocol.FetchResponse{Version: f.GetRequest().Header.RequestApiVersion, ErrorCode: int16(kerr)}
+	if resp.Version < 7 || !resp.IsValidVersion() {
+		return nil, fmt.Errorf("fetch response v%d has no top-level error code", resp.Version)
+	}
+
+	return encodeResponse(f.GetRequest(), &resp)
+}
+
+// GenerateFetchResponse reads record batches from the partition logs, starting at the fetch offset of each partition.
+// The size of the returned batches is limited by max_bytes for the whole response and partition_max_bytes per partition.
+// Fetches of the __cluster_metadata topic, which KRaft followers use to replicate the metadata log, are served from the Raft state.
+// If less than min_bytes are available, the fetch waits up to max_wait_ms for records to be appended to the fetched partitions.
+// The wait is aborted with the error of the context when it is cancelled, for example because the client disconnected.
+// TODO: fetch sessions are not implemented, the broker always answers with a full response.
+func GenerateFetchResponse(ctx context.Context, version int16, req protocol.FetchRequest, topics *metadata.TopicRegistry, logs *storage.LogManager, raft *metadata.RaftState) (*protocol.FetchResponse, error) {
+	resp, size, appended := fetchLogs(version, req, topics, logs, raft)
+	if size >= req.MinBytes || req.MaxWaitMs <= 0 || hasPartitionError(resp) {
+		return resp, nil
+	}
+
+	timer := time.NewTimer(time.Duration(req.MaxWaitMs) * time.Millisecond)
+	defer timer.Stop()
+
+	for {
+		// wait for the context, the timer or an append to any of the fetched partitions
+		cases := []reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timer.C)},
+		}
+		for _, ch := range appended {
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch)})
+		}
+
+		chosen, _, _ := reflect.Select(cases)
+		switch chosen {
+		case 0:
+			return nil, ctx.Err()
+		case 1:
+			resp, _, _ = fetchLogs(version, req, topics, logs, raft)
+			return resp, nil
+		}
+
+		resp, size, appended = fetchLogs(version, req, topics, logs, raft)
+		if size >= req.MinBytes || hasPartitionError(resp) {
+			return resp, nil
+		}
+	}
+}
+
+// fetchLogs reads the partition logs once. It returns the response with the number of bytes read,
+// and the channels which are closed when records are appended to the fetched logs.
+func fetchLogs(version int16, req protocol.FetchRequest, topics *metadata.TopicRegistry, logs *storage.LogManager, raft *metadata.RaftState) (*protocol.FetchResponse, int32, []<-chan struct{}) {
+	var appended []<-chan struct{}
+
+	resp := protocol.FetchResponse{
+		Version:   version,
+		ErrorCode: int16(utils.ErrNoError),
+		SessionID: 0,
+	}
+
+	remainingBytes := req.MaxBytes
+
+	for _, fetchTopic := range req.Topics {
+		topicResponse := protocol.FetchableTopicResponse{
+			Version: version,
+			Topic:   fetchTopic.Topic,
+			TopicID: fetchTopic.TopicID,
+		}
+
+		// from v13 topics are identified by topic ID instead of name
+		var topic metadata.Topic
+		var topicExists bool
+		unknownTopicError := utils.ErrUnknownTopicOrPartition
+		isMetadataTopic := fetchTopic.Topic == metadata.ClusterMetadataTopic
+		if version >= 13 {
+			isMetadataTopic = fetchTopic.TopicID == metadata.ClusterMetadataTopicID
+		}
+
+		// the metadata topic is not part of the topic registry, it only has the partition replicated by the Raft quorum
+		if isMetadataTopic {
+			topic = metadata.Topic{Name: metadata.ClusterMetadataTopic, TopicID: metadata.ClusterMetadataTopicID, NumPartitions: 1}
+			topicExists = true
+		} else if version >= 13 {
+			topic, topicExists = topics.GetTopicByID(fetchTopic.TopicID)
+			unknownTopicError = utils.ErrUnknownTopicID
+		} else {
+			topic, topicExists = topics.GetTopic(fetchTopic.Topic)
+		}
+
+		for _, fetchPartition := range fetchTopic.Partitions {
+			partitionResponse := protocol.PartitionData_FetchResponse{
+				Version:              version,
+				PartitionIndex:       fetchPartition.Partition,
+				ErrorCode:            int16(utils.ErrNoError),
+				HighWatermark:        -1,
+				LastStableOffset:     -1,
+				LogStartOffset:       -1,
+				PreferredReadReplica: -1,
+				Records:              protocol.Records{Batches: []protocol.RecordBatch{}},
+			}
+
+			if !topicExists || fetchPartition.Partition < 0 || fetchPartition.Partition >= topic.NumPartitions {
+				partitionResponse.ErrorCode = int16(unknownTopicError)
+				topicResponse.Partitions = append(topicResponse.Partitions, partitionResponse)
+				continue
+			}
+
+			var log *storage.PartitionLog
+			if isMetadataTopic {
+				log = raft.MetadataLog()
+			} else {
+				// the logs are created with their topic, a partition without log has no replica on this broker,
+				// which Kafka answers with NOT_LEADER_OR_FOLLOWER, so the client refreshes its metadata
+				var ok bool
+				if log, ok = logs.GetPartition(topic.Name, fetchPartition.Partition); !ok {
+					partitionResponse.ErrorCode = int16(utils.ErrNotLeaderForPartition)
+					topicResponse.Partitions = append(topicResponse.Partitions, partitionResponse)
+					continue
+				}
+			}
+
+			// get the channel before reading, so a batch appended after the read wakes up the fetch
+			appended = append(appended, log.Appended())
+
+			// like Kafka, return the first batch even if it exceeds the limits, as long as nothing else was returned yet
+			minOneBatch := remainingBytes == req.MaxBytes
+			remainingBytes -= readPartitionLog(log, fetchPartition, min(fetchPartition.PartitionMaxBytes, remainingBytes), minOneBatch, &partitionResponse)
+
+			topicResponse.Partitions = append(topicResponse.Partitions, partitionResponse)
+		}
+
+		resp.Responses = append(resp.Responses, topicResponse)
+	}
+
+	return &resp, req.MaxBytes - remainingBytes, appended
+}
+
+// hasPartitionError reports whether any partition of the response has an error, which is answered without waiting.
+func hasPartitionError(resp *protocol.FetchResponse) bool {
+	for _, topic := range resp.Responses {
+		for _, partition := range topic.Partitions {
+			if partition.ErrorCode != int16(utils.ErrNoError) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// readPartitionLog fills the partition response with the batches read from the log and the log offsets.
+// It returns the number of bytes read.
+func readPartitionLog(log *storage.PartitionLog, fetchPartition protocol.FetchPartition_FetchRequest, maxBytes int32, minOneBatch bool, partitionResponse *protocol.PartitionData_FetchResponse) int32 {
+	// with a single replica, every appended record is committed right away
+	partitionResponse.HighWatermark = log.LogEndOffset()
+	partitionResponse.LastStableOffset = log.LogEndOffset()
+	partitionResponse.LogStartOffset = log.LogStartOffset()
+
+	batches, err := log.Read(fetchPartition.FetchOffset, maxBytes, minOneBatch)
+	if err != nil {
+		partitionResponse.ErrorCode = int16(utils.ErrorCode(err))
+		return 0
+	}
+
+	partitionResponse.Records.Batches = batches
+
+	var size int32
+	for _, batch := range batches {
+		size += int32(batch.Size())
+	}
+
+	return size
+}
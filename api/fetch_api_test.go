@@ -0,0 +1,383 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/storage"
+	"opentalaria/utils"
+	"testing"
+	"time"
+)
+
+func produceTestBatch(t *testing.T, version int16, topics *metadata.TopicRegistry, logs *storage.LogManager) []byte {
+	t.Helper()
+
+	batch := protocol.RecordBatch{
+		LastOffsetDelta: 1,
+		BaseTimestamp:   time.UnixMilli(1700000000000),
+		MaxTimestamp:    time.UnixMilli(1700000000000),
+		ProducerId:      -1,
+		ProducerEpoch:   -1,
+		BaseSequence:    -1,
+		Records: []protocol.Record{
+			{OffsetDelta: 0, Value: []byte("first")},
+			{OffsetDelta: 1, Value: []byte("second")},
+		},
+	}
+	batchBytes, err := protocol.Encode(&batch)
+	if err != nil {
+		t.Fatalf("error encoding batch: %v", err)
+	}
+
+	// run the request through the wire format, like a real client would send it
+	req := protocol.ProduceRequest{
+		Version: version,
+		Acks:    -1,
+		TopicData: []protocol.TopicProduceData{{
+			Name:          "test-topic",
+			PartitionData: []protocol.PartitionProduceData{{Index: 0, Records: protocol.Records{Batches: []protocol.RecordBatch{batch}}}},
+		}},
+	}
+	reqBytes, err := protocol.Encode(&req)
+	if err != nil {
+		t.Fatalf("error encoding produce request: %v", err)
+	}
+	decoded := protocol.ProduceRequest{}
+	if _, err := protocol.VersionedDecode(reqBytes, &decoded, version); err != nil {
+		t.Fatalf("error decoding produce request: %v", err)
+	}
+
+	resp := GenerateProduceResponse(version, decoded, topics, logs)
+	if errCode := resp.Responses[0].PartitionResponses[0].ErrorCode; errCode != int16(utils.ErrNoError) {
+		t.Fatalf("produce error code = %d", errCode)
+	}
+
+	return batchBytes
+}
+
+func fetchRequest(version int16, topic metadata.Topic, offset int64, partitionMaxBytes int32) protocol.FetchRequest {
+	return protocol.FetchRequest{
+		Version:  version,
+		MaxBytes: 1024 * 1024,
+		Topics: []protocol.FetchTopic_FetchRequest{{
+			Topic:      topic.Name,
+			TopicID:    topic.TopicID,
+			Partitions: []protocol.FetchPartition_FetchRequest{{Partition: 0, FetchOffset: offset, PartitionMaxBytes: partitionMaxBytes}},
+		}},
+	}
+}
+
+func TestGenerateFetchResponse_ProduceRoundTrip(t *testing.T) {
+	for _, version := range []int16{11, 12, 13} {
+		topics := metadata.NewTopicRegistry()
+		topic, err := topics.CreateTopic("test-topic", 1, 1, false)
+		if err != nil {
+			t.Fatalf("error creating topic: %v", err)
+		}
+		logs := storage.NewLogManager()
+
+		first := produceTestBatch(t, 8, topics, logs)
+		second := produceTestBatch(t, 8, topics, logs)
+
+		resp, _ := GenerateFetchResponse(context.Background(), version, fetchRequest(version, topic, 0, 1024*1024), topics, logs, metadata.NewStandaloneRaftState(1))
+
+		// encode and decode the response, to make sure the records survive the wire format of this version
+		respBytes, err := protocol.Encode(resp)
+		if err != nil {
+			t.Fatalf("v%d: error encoding fetch response: %v", version, err)
+		}
+		decoded := protocol.FetchResponse{}
+		if _, err := protocol.VersionedDecode(respBytes, &decoded, version); err != nil {
+			t.Fatalf("v%d: error decoding fetch response: %v", version, err)
+		}
+
+		partition := decoded.Responses[0].Partitions[0]
+		if partition.ErrorCode != int16(utils.ErrNoError) {
+			t.Fatalf("v%d: fetch error code = %d", version, partition.ErrorCode)
+		}
+		if partition.HighWatermark != 4 {
+			t.Errorf("v%d: high watermark = %d, want 4", version, partition.HighWatermark)
+		}
+		if len(partition.Records.Batches) != 2 {
+			t.Fatalf("v%d: fetched %d batches, want 2", version, len(partition.Records.Batches))
+		}
+
+		// apart from the base offset assigned by the broker, the fetched batches are the exact bytes produced
+		for i, produced := range [][]byte{first, second} {
+			fetched, err := protocol.Encode(&partition.Records.Batches[i])
+			if err != nil {
+				t.Fatalf("v%d: error encoding fetched batch: %v", version, err)
+			}
+			if !bytes.Equal(fetched[8:], produced[8:]) {
+				t.Errorf("v%d: fetched batch %d differs from the produced batch", version, i)
+			}
+			if partition.Records.Batches[i].BaseOffset != int64(i*2) {
+				t.Errorf("v%d: batch %d base offset = %d, want %d", version, i, partition.Records.Batches[i].BaseOffset, i*2)
+			}
+		}
+	}
+}
+
+func TestGenerateFetchResponse_Limits(t *testing.T) {
+	topics := metadata.NewTopicRegistry()
+	topic, err := topics.CreateTopic("test-topic", 1, 1, false)
+	if err != nil {
+		t.Fatalf("error creating topic: %v", err)
+	}
+	logs := storage.NewLogManager()
+
+	batch := produceTestBatch(t, 8, topics, logs)
+	produceTestBatch(t, 8, topics, logs)
+
+	tests := []struct {
+		name              string
+		offset            int64
+		partitionMaxBytes int32
+		wantBatches       int
+	}{
+		{name: "offset inside the second batch", offset: 3, partitionMaxBytes: 1024, wantBatches: 1},
+		{name: "partition max bytes fits one batch", offset: 0, partitionMaxBytes: int32(len(batch)) + 1, wantBatches: 1},
+		{name: "first batch larger than partition max bytes", offset: 0, partitionMaxBytes: 1, wantBatches: 1},
+		{name: "offset at the log end", offset: 4, partitionMaxBytes: 1024, wantBatches: 0},
+		{name: "offset past the log end", offset: 100, partitionMaxBytes: 1024, wantBatches: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _ := GenerateFetchResponse(context.Background(), 12, fetchRequest(12, topic, tt.offset, tt.partitionMaxBytes), topics, logs, metadata.NewStandaloneRaftState(1))
+
+			partition := resp.Responses[0].Partitions[0]
+			if partition.ErrorCode != int16(utils.ErrNoError) {
+				t.Errorf("error code = %d, want %d", partition.ErrorCode, utils.ErrNoError)
+			}
+			if partition.HighWatermark != 4 {
+				t.Errorf("high watermark = %d, want 4", partition.HighWatermark)
+			}
+			if len(partition.Records.Batches) != tt.wantBatches {
+				t.Errorf("fetched %d batches, want %d", len(partition.Records.Batches), tt.wantBatches)
+			}
+		})
+	}
+}
+
+func TestGenerateFetchResponse_UnknownTopic(t *testing.T) {
+	resp, _ := GenerateFetchResponse(context.Background(), 12, fetchRequest(12, metadata.Topic{Name: "unknown-topic"}, 0, 1024), metadata.NewTopicRegistry(), storage.NewLogManager(), metadata.NewStandaloneRaftState(1))
+
+	if resp.Responses[0].Partitions[0].ErrorCode != int16(utils.ErrUnknownTopicOrPartition) {
+		t.Errorf("error code = %d, want %d", resp.Responses[0].Partitions[0].ErrorCode, utils.ErrUnknownTopicOrPartition)
+	}
+}
+
+func TestGenerateFetchResponse_MissingLog(t *testing.T) {
+	topics := metadata.NewTopicRegistry()
+	topic, err := topics.CreateTopic("test-topic", 1, 1, false)
+	if err != nil {
+		t.Fatalf("error creating topic: %v", err)
+	}
+	logs := storage.NewLogManager()
+
+	resp, _ := GenerateFetchResponse(context.Background(), 12, fetchRequest(12, topic, 0, 1024), topics, logs, metadata.NewStandaloneRaftState(1))
+
+	if resp.Responses[0].Partitions[0].ErrorCode != int16(utils.ErrNotLeaderForPartition) {
+		t.Errorf("error code = %d, want %d", resp.Responses[0].Partitions[0].ErrorCode, utils.ErrNotLeaderForPartition)
+	}
+	if _, ok := logs.GetPartition(topic.Name, 0); ok {
+		t.Error("fetch created the partition log")
+	}
+}
+
+func TestGenerateFetchResponse_ClusterMetadata(t *testing.T) {
+	raft := metadata.NewStandaloneRaftState(1)
+	for i := 0; i < 3; i++ {
+		batch := protocol.RecordBatch{
+			BaseTimestamp: time.UnixMilli(1700000000000),
+			MaxTimestamp:  time.UnixMilli(1700000000000),
+			ProducerId:    -1,
+			ProducerEpoch: -1,
+			BaseSequence:  -1,
+			Records:       []protocol.Record{{Value: []byte("metadata record")}},
+		}
+		// encode and decode the batch, so it has the size of a batch received from the wire
+		batchBytes, err := protocol.Encode(&batch)
+		if err != nil {
+			t.Fatalf("error encoding batch: %v", err)
+		}
+		if err := protocol.Decode(batchBytes, &batch); err != nil {
+			t.Fatalf("error decoding batch: %v", err)
+		}
+		if _, err := raft.AppendMetadata(batch); err != nil {
+			t.Fatalf("error appending metadata batch: %v", err)
+		}
+	}
+
+	metadataTopic := metadata.Topic{Name: metadata.ClusterMetadataTopic, TopicID: metadata.ClusterMetadataTopicID}
+	for _, version := range []int16{12, 13} {
+		// a user topic log must not be mixed up with the metadata log
+		logs := storage.NewLogManager()
+
+		resp, _ := GenerateFetchResponse(context.Background(), version, fetchRequest(version, metadataTopic, 0, 1024*1024), metadata.NewTopicRegistry(), logs, raft)
+
+		partition := resp.Responses[0].Partitions[0]
+		if partition.ErrorCode != int16(utils.ErrNoError) {
+			t.Fatalf("v%d: error code = %d", version, partition.ErrorCode)
+		}
+		if partition.HighWatermark != 3 {
+			t.Errorf("v%d: high watermark = %d, want 3", version, partition.HighWatermark)
+		}
+		if partition.LogStartOffset != 0 {
+			t.Errorf("v%d: log start offset = %d, want 0", version, partition.LogStartOffset)
+		}
+		if len(partition.Records.Batches) != 3 {
+			t.Fatalf("v%d: fetched %d batches, want the full metadata log of 3 batches", version, len(partition.Records.Batches))
+		}
+		for i, batch := range partition.Records.Batches {
+			if batch.BaseOffset != int64(i) {
+				t.Errorf("v%d: batch %d base offset = %d, want %d", version, i, batch.BaseOffset, i)
+			}
+		}
+		if _, ok := logs.GetPartition(metadata.ClusterMetadataTopic, 0); ok {
+			t.Errorf("v%d: fetching the metadata topic created a user topic log", version)
+		}
+	}
+
+	// the metadata topic only has a single partition
+	req := fetchRequest(12, metadataTopic, 0, 1024)
+	req.Topics[0].Partitions[0].Partition = 1
+	resp, _ := GenerateFetchResponse(context.Background(), 12, req, metadata.NewTopicRegistry(), storage.NewLogManager(), raft)
+	if resp.Responses[0].Partitions[0].ErrorCode != int16(utils.ErrUnknownTopicOrPartition) {
+		t.Errorf("error code = %d, want %d", resp.Responses[0].Partitions[0].ErrorCode, utils.ErrUnknownTopicOrPartition)
+	}
+}
+
+func TestGenerateFetchResponse_Wait(t *testing.T) {
+	topics := metadata.NewTopicRegistry()
+	topic, err := topics.CreateTopic("test-topic", 1, 1, false)
+	if err != nil {
+		t.Fatalf("error creating topic: %v", err)
+	}
+	logs := storage.NewLogManager()
+	if _, err := logs.GetOrCreatePartition(topic.Name, 0); err != nil {
+		t.Fatalf("error creating partition log: %v", err)
+	}
+
+	req := fetchRequest(12, topic, 0, 1024*1024)
+	req.MinBytes = 1
+	req.MaxWaitMs = 60000
+
+	type result struct {
+		resp *protocol.FetchResponse
+		err  error
+	}
+	fetch := func(ctx context.Context) <-chan result {
+		done := make(chan result, 1)
+		go func() {
+			resp, err := GenerateFetchResponse(ctx, 12, req, topics, logs, metadata.NewStandaloneRaftState(1))
+			done <- result{resp, err}
+		}()
+		return done
+	}
+
+	// a produce wakes up the waiting fetch
+	done := fetch(context.Background())
+	time.Sleep(50 * time.Millisecond)
+	produceTestBatch(t, 8, topics, logs)
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			t.Fatalf("fetch error: %v", r.err)
+		}
+		if batches := r.resp.Responses[0].Partitions[0].Records.Batches; len(batches) != 1 {
+			t.Errorf("fetched %d batches, want 1", len(batches))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("fetch did not return after the produce")
+	}
+
+	// cancelling the context aborts the wait
+	req.Topics[0].Partitions[0].FetchOffset = 2
+	ctx, cancel := context.WithCancel(context.Background())
+	done = fetch(ctx)
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case r := <-done:
+		if !errors.Is(r.err, context.Canceled) {
+			t.Errorf("fetch error = %v, want %v", r.err, context.Canceled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("fetch did not return after the context was cancelled")
+	}
+
+	// without new records the fetch answers once max_wait_ms expires
+	req.MaxWaitMs = 50
+	r := <-fetch(context.Background())
+	if r.err != nil {
+		t.Fatalf("fetch error: %v", r.err)
+	}
+	if batches := r.resp.Responses[0].Partitions[0].Records.Batches; len(batches) != 0 {
+		t.Errorf("fetched %d batches, want none", len(batches))
+	}
+}
+
+func TestGenerateFetchResponse_WaitForMinBytes(t *testing.T) {
+	topics := metadata.NewTopicRegistry()
+	topic, err := topics.CreateTopic("test-topic", 1, 1, false)
+	if err != nil {
+		t.Fatalf("error creating topic: %v", err)
+	}
+	logs := storage.NewLogManager()
+
+	batch := produceTestBatch(t, 8, topics, logs)
+
+	// the first batch is available, but min_bytes asks for two of them
+	req := fetchRequest(12, topic, 0, 1024*1024)
+	req.MinBytes = int32(2 * len(batch))
+	req.MaxWaitMs = 60000
+
+	done := make(chan *protocol.FetchResponse, 1)
+	go func() {
+		resp, _ := GenerateFetchResponse(context.Background(), 12, req, topics, logs, metadata.NewStandaloneRaftState(1))
+		done <- resp
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("fetch returned before min_bytes were available")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	produceTestBatch(t, 8, topics, logs)
+
+	select {
+	case resp := <-done:
+		if batches := resp.Responses[0].Partitions[0].Records.Batches; len(batches) != 2 {
+			t.Errorf("fetched %d batches, want 2", len(batches))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("fetch did not return once min_bytes were available")
+	}
+
+	// deleting the topic wakes up the fetch, which answers with an error instead of waiting for max_wait_ms
+	req.Topics[0].Partitions[0].FetchOffset = 4
+	req.MinBytes = 1
+	go func() {
+		resp, _ := GenerateFetchResponse(context.Background(), 12, req, topics, logs, metadata.NewStandaloneRaftState(1))
+		done <- resp
+	}()
+	time.Sleep(50 * time.Millisecond)
+	GenerateDeleteTopicsResponse(6, protocol.DeleteTopicsRequest{Version: 6, Topics: []protocol.DeleteTopicState{{Name: &topic.Name}}}, topics, logs)
+
+	select {
+	case resp := <-done:
+		if errCode := resp.Responses[0].Partitions[0].ErrorCode; errCode != int16(utils.ErrUnknownTopicOrPartition) {
+			t.Errorf("error code = %d, want %d", errCode, utils.ErrUnknownTopicOrPartition)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("fetch did not return after the topic was deleted")
+	}
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"fmt"
+	"net"
+	"opentalaria/config"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+)
+
+// key types of the FindCoordinator API
+const (
+	coordinatorKeyTypeGroup       int8 = 0
+	coordinatorKeyTypeTransaction int8 = 1
+)
+
+type FindCoordinatorAPI struct {
+	Request Request
+}
+
+func (f FindCoordinatorAPI) Name() string {
+	return "FindCoordinator"
+}
+
+func (f FindCoordinatorAPI) GetRequest() Request {
+	return f.Request
+}
+
+func (f FindCoordinatorAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.FindCoordinatorResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (f FindCoordinatorAPI) GeneratePayload() ([]byte, error) {
+	req := *f.GetRequest().Body.(*protocol.FindCoordinatorRequest)
+
+	listener := f.GetRequest().Config.Broker.AdvertisedListenerForPort(localPort(f.GetRequest().Conn))
+	resp := GenerateFindCoordinatorResponse(f.GetRequest().Header.RequestApiVersion, req, f.GetRequest().Config, listener)
+
+	return encodeResponse(f.GetRequest(), resp)
+}
+
+// ErrorPayload answers the request with a top-level error code, which is only read by clients before v4.
+func (f FindCoordinatorAPI) ErrorPayload(kerr utils.KError) ([]byte, error) {
+	resp := protocol.FindCoordinatorResponse{Version: f.GetRequest().Header.RequestApiVersion, ErrorCode: int16(kerr), NodeID: -1, Port: -1}
+	if !resp.IsValidVersion() || resp.Version >= 4 {
+		return nil, fmt.Errorf("FindCoordinator response version %d can't express a top-level error", resp.Version)
+	}
+
+	return encodeResponse(f.GetRequest(), &resp)
+}
+
+// GenerateFindCoordinatorResponse returns this broker as the coordinator of every key, since OpenTalaria runs as a single node.
+// The broker is described with the advertised listener the client connected to.
+// Before v4 the request contains a single key, which is answered in the top-level fields of the response.
+func GenerateFindCoordinatorResponse(version int16, req protocol.FindCoordinatorRequest, config *config.Config, listener config.Listener) *protocol.FindCoordinatorResponse {
+	resp := protocol.FindCoordinatorResponse{
+		Version: version,
+	}
+
+	if version < 4 {
+		coordinator := findCoordinator(version, req.Key, req.KeyType, config.Broker.BrokerID, listener)
+
+		resp.ErrorCode = coordinator.ErrorCode
+		resp.ErrorMessage = coordinator.ErrorMessage
+		resp.NodeID = coordinator.NodeID
+		resp.Host = coordinator.Host
+		resp.Port = coordinator.Port
+
+		return &resp
+	}
+
+	resp.Coordinators = []protocol.Coordinator{}
+	for _, key := range req.CoordinatorKeys {
+		resp.Coordinators = append(resp.Coordinators, findCoordinator(version, key, req.KeyType, config.Broker.BrokerID, listener))
+	}
+
+	return &resp
+}
+
+func findCoordinator(version int16, key string, keyType int8, brokerID int32, listener config.Listener) protocol.Coordinator {
+	if keyType != coordinatorKeyTypeGroup && keyType != coordinatorKeyTypeTransaction {
+		errMsg := fmt.Sprintf("unsupported coordinator key type %d", keyType)
+		return protocol.Coordinator{
+			Version:      version,
+			Key:          key,
+			NodeID:       -1,
+			Port:         -1,
+			ErrorCode:    int16(utils.ErrInvalidRequest),
+			ErrorMessage: &errMsg,
+		}
+	}
+
+	return protocol.Coordinator{
+		Version:   version,
+		Key:       key,
+		NodeID:    brokerID,
+		Host:      listener.Host,
+		Port:      listener.Port,
+		ErrorCode: int16(utils.ErrNoError),
+	}
+}
+
+// localPort returns the port of the listener which accepted the connection, or -1 if it is not a TCP connection.
+func localPort(conn net.Conn) int32 {
+	if conn == nil {
+		return -1
+	}
+
+	addr, ok := conn.LocalAddr().(*net.TCPAddr)
+	if !ok {
+		return -1
+	}
+
+	return int32(addr.Port)
+}
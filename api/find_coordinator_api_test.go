@@ -0,0 +1,83 @@
+package api
+
+import (
+	"net"
+	"opentalaria/config"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"testing"
+)
+
+func TestFindCoordinatorAPI_ConnectionListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	client, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	port := int32(ln.Addr().(*net.TCPAddr).Port)
+	conf := config.MockConfig()
+	conf.Broker = config.MockBroker(
+		config.WithListener(config.Listener{Host: "127.0.0.1", Port: port - 1, SecurityProtocol: config.PLAINTEXT, ListenerName: "internal"}),
+		config.WithListener(config.Listener{Host: "127.0.0.1", Port: port, SecurityProtocol: config.PLAINTEXT, ListenerName: "external"}),
+		config.WithAdvertisedListener(config.Listener{Host: "internal.example.com", Port: 19092, SecurityProtocol: config.PLAINTEXT, ListenerName: "internal"}),
+		config.WithAdvertisedListener(config.Listener{Host: "external.example.com", Port: 29092, SecurityProtocol: config.PLAINTEXT, ListenerName: "external"}),
+	)
+
+	for _, version := range []int16{3, 4} {
+		req := protocol.FindCoordinatorRequest{Version: version, Key: "test-group", CoordinatorKeys: []string{"test-group"}}
+		api := FindCoordinatorAPI{Request: Request{
+			Header: protocol.RequestHeader{RequestApiKey: req.GetKey(), RequestApiVersion: version},
+			Body:   &req,
+			Conn:   conn,
+			Config: conf,
+		}}
+
+		payload, err := api.GeneratePayload()
+		if err != nil {
+			t.Fatalf("v%d: %v", version, err)
+		}
+
+		resp := protocol.FindCoordinatorResponse{}
+		if _, err := protocol.VersionedDecode(payload, &resp, version); err != nil {
+			t.Fatalf("v%d: error decoding response: %v", version, err)
+		}
+
+		coordinator := protocol.Coordinator{ErrorCode: resp.ErrorCode, NodeID: resp.NodeID, Host: resp.Host, Port: resp.Port}
+		if version >= 4 {
+			if len(resp.Coordinators) != 1 {
+				t.Fatalf("v%d: coordinators = %+v, want one", version, resp.Coordinators)
+			}
+			coordinator = resp.Coordinators[0]
+		}
+
+		if coordinator.ErrorCode != int16(utils.ErrNoError) {
+			t.Errorf("v%d: error code = %d", version, coordinator.ErrorCode)
+		}
+		if coordinator.NodeID != conf.Broker.BrokerID || coordinator.Host != "external.example.com" || coordinator.Port != 29092 {
+			t.Errorf("v%d: coordinator = %d %s:%d, want %d external.example.com:29092", version, coordinator.NodeID, coordinator.Host, coordinator.Port, conf.Broker.BrokerID)
+		}
+	}
+}
+
+func TestGenerateFindCoordinatorResponse_UnsupportedKeyType(t *testing.T) {
+	req := protocol.FindCoordinatorRequest{KeyType: 2, CoordinatorKeys: []string{"group:topic:0"}}
+
+	resp := GenerateFindCoordinatorResponse(4, req, config.MockConfig(), config.MockBroker().AdvertisedListeners[0])
+
+	if errCode := resp.Coordinators[0].ErrorCode; errCode != int16(utils.ErrInvalidRequest) {
+		t.Errorf("error code = %d, want %d", errCode, utils.ErrInvalidRequest)
+	}
+}
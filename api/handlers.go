@@ -0,0 +1,58 @@
+package api
+
+import (
+	"opentalaria/protocol"
+)
+
+// handlers maps API keys to the constructors of their handlers.
+// Supporting a new API only requires its handler to be listed here.
+var handlers = map[int16]func(req Request) API{
+	(&protocol.ProduceRequest{}).GetKey():                 func(req Request) API { return ProduceAPI{Request: req} },
+	(&protocol.FetchRequest{}).GetKey():                   func(req Request) API { return FetchAPI{Request: req} },
+	(&protocol.ListOffsetsRequest{}).GetKey():             func(req Request) API { return ListOffsetsAPI{Request: req} },
+	(&protocol.MetadataRequest{}).GetKey():                func(req Request) API { return MetadataAPI{Request: req} },
+	(&protocol.ApiVersionsRequest{}).GetKey():             func(req Request) API { return APIVersionsAPI{Request: req} },
+	(&protocol.CreateTopicsRequest{}).GetKey():            func(req Request) API { return CreateTopicsAPI{Request: req} },
+	(&protocol.DeleteTopicsRequest{}).GetKey():            func(req Request) API { return DeleteTopicsAPI{Request: req} },
+	(&protocol.VoteRequest{}).GetKey():                    func(req Request) API { return VoteAPI{Request: req} },
+	(&protocol.DescribeQuorumRequest{}).GetKey():          func(req Request) API { return DescribeQuorumAPI{Request: req} },
+	(&protocol.DescribeConfigsRequest{}).GetKey():         func(req Request) API { return DescribeConfigsAPI{Request: req} },
+	(&protocol.AlterConfigsRequest{}).GetKey():            func(req Request) API { return AlterConfigsAPI{Request: req} },
+	(&protocol.IncrementalAlterConfigsRequest{}).GetKey(): func(req Request) API { return IncrementalAlterConfigsAPI{Request: req} },
+	(&protocol.FindCoordinatorRequest{}).GetKey():         func(req Request) API { return FindCoordinatorAPI{Request: req} },
+	(&protocol.JoinGroupRequest{}).GetKey():               func(req Request) API { return JoinGroupAPI{Request: req} },
+	(&protocol.SyncGroupRequest{}).GetKey():               func(req Request) API { return SyncGroupAPI{Request: req} },
+	(&protocol.HeartbeatRequest{}).GetKey():               func(req Request) API { return HeartbeatAPI{Request: req} },
+	(&protocol.LeaveGroupRequest{}).GetKey():              func(req Request) API { return LeaveGroupAPI{Request: req} },
+	(&protocol.OffsetCommitRequest{}).GetKey():            func(req Request) API { return OffsetCommitAPI{Request: req} },
+	(&protocol.OffsetFetchRequest{}).GetKey():             func(req Request) API { return OffsetFetchAPI{Request: req} },
+	(&protocol.InitProducerIdRequest{}).GetKey():          func(req Request) API { return InitProducerIDAPI{Request: req} },
+	(&protocol.SaslHandshakeRequest{}).GetKey():           func(req Request) API { return SaslHandshakeAPI{Request: req} },
+	(&protocol.SaslAuthenticateRequest{}).GetKey():        func(req Request) API { return SaslAuthenticateAPI{Request: req} },
+	(&protocol.CreateAclsRequest{}).GetKey():              func(req Request) API { return CreateACLsAPI{Request: req} },
+	(&protocol.DeleteAclsRequest{}).GetKey():              func(req Request) API { return DeleteACLsAPI{Request: req} },
+	(&protocol.DescribeAclsRequest{}).GetKey():            func(req Request) API { return DescribeACLsAPI{Request: req} },
+	(&protocol.CreateDelegationTokenRequest{}).GetKey():   func(req Request) API { return CreateDelegationTokenAPI{Request: req} },
+	(&protocol.RenewDelegationTokenRequest{}).GetKey():    func(req Request) API { return RenewDelegationTokenAPI{Request: req} },
+	(&protocol.ExpireDelegationTokenRequest{}).GetKey():   func(req Request) API { return ExpireDelegationTokenAPI{Request: req} },
+	(&protocol.DescribeDelegationTokenRequest{}).GetKey(): func(req Request) API { return DescribeDelegationTokenAPI{Request: req} },
+	(&protocol.BrokerRegistrationRequest{}).GetKey():      func(req Request) API { return BrokerRegistrationAPI{Request: req} },
+	(&protocol.BrokerHeartbeatRequest{}).GetKey():         func(req Request) API { return BrokerHeartbeatAPI{Request: req} },
+	(&protocol.DescribeProducersRequest{}).GetKey():       func(req Request) API { return DescribeProducersAPI{Request: req} },
+	(&protocol.DescribeTransactionsRequest{}).GetKey():    func(req Request) API { return DescribeTransactionsAPI{Request: req} },
+	(&protocol.DescribeLogDirsRequest{}).GetKey():         func(req Request) API { return DescribeLogDirsAPI{Request: req} },
+}
+
+// NewHandler returns the handler for the API key of the request.
+// APIs of the protocol without handler are answered by UnsupportedAPI, unknown API keys are an error.
+func NewHandler(req Request) (API, error) {
+	newHandler, ok := handlers[req.Header.RequestApiKey]
+	if !ok {
+		if _, err := protocol.NewResponse(req.Header.RequestApiKey, req.Header.RequestApiVersion); err != nil {
+			return nil, err
+		}
+		return UnsupportedAPI{Request: req}, nil
+	}
+
+	return newHandler(req), nil
+}
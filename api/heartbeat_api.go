@@ -0,0 +1,52 @@
+package api
+
+import (
+	"fmt"
+	"opentalaria/coordinator"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+)
+
+type HeartbeatAPI struct {
+	Request Request
+}
+
+func (h HeartbeatAPI) Name() string {
+	return "Heartbeat"
+}
+
+func (h HeartbeatAPI) GetRequest() Request {
+	return h.Request
+}
+
+func (h HeartbeatAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.HeartbeatResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (h HeartbeatAPI) GeneratePayload() ([]byte, error) {
+	req := *h.GetRequest().Body.(*protocol.HeartbeatRequest)
+
+	resp := GenerateHeartbeatResponse(h.GetRequest().Header.RequestApiVersion, req, h.GetRequest().Groups)
+
+	return encodeResponse(h.GetRequest(), resp)
+}
+
+// ErrorPayload answers the request with a top-level error code.
+func (h HeartbeatAPI) ErrorPayload(kerr utils.KError) ([]byte, error) {
+	resp := protocol.HeartbeatResponse{Version: h.GetRequest().Header.RequestApiVersion, ErrorCode: int16(kerr)}
+	if !resp.IsValidVersion() {
+		return nil, fmt.Errorf("unknown Heartbeat response version %d", resp.Version)
+	}
+
+	return encodeResponse(h.GetRequest(), &resp)
+}
+
+// GenerateHeartbeatResponse keeps the session of the member alive. REBALANCE_IN_PROGRESS tells the member to rejoin the group.
+func GenerateHeartbeatResponse(version int16, req protocol.HeartbeatRequest, groups *coordinator.GroupCoordinator) *protocol.HeartbeatResponse {
+	err := groups.Heartbeat(req.GroupID, req.GenerationID, req.MemberID)
+
+	return &protocol.HeartbeatResponse{
+		Version:   version,
+		ErrorCode: int16(utils.ErrorCode(err)),
+	}
+}
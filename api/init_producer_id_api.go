@@ -0,0 +1,84 @@
+package api
+
+import (
+	"fmt"
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+)
+
+type InitProducerIDAPI struct {
+	Request Request
+}
+
+func (i InitProducerIDAPI) Name() string {
+	return "InitProducerId"
+}
+
+func (i InitProducerIDAPI) GetRequest() Request {
+	return i.Request
+}
+
+func (i InitProducerIDAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.InitProducerIdResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (i InitProducerIDAPI) GeneratePayload() ([]byte, error) {
+	req := *i.GetRequest().Body.(*protocol.InitProducerIdRequest)
+
+	resp := GenerateInitProducerIDResponse(i.GetRequest().Header.RequestApiVersion, req, i.GetRequest().ProducerIDs)
+
+	return encodeResponse(i.GetRequest(), resp)
+}
+
+// ErrorPayload answers the request with a top-level error code.
+func (i InitProducerIDAPI) ErrorPayload(kerr utils.KError) ([]byte, error) {
+	resp := protocol.InitProducerIdResponse{
+		Version:                 i.GetRequest().Header.RequestApiVersion,
+		ErrorCode:               int16(kerr),
+		ProducerID:              -1,
+		ProducerEpoch:           -1,
+		OngoingTxnProducerID:    -1,
+		OngoingTxnProducerEpoch: -1,
+	}
+	if !resp.IsValidVersion() {
+		return nil, fmt.Errorf("unknown InitProducerId response version %d", resp.Version)
+	}
+
+	return encodeResponse(i.GetRequest(), &resp)
+}
+
+// GenerateInitProducerIDResponse allocates the producer ID of an idempotent producer.
+// Transactions are not supported, so producers with a transactional ID are rejected.
+func GenerateInitProducerIDResponse(version int16, req protocol.InitProducerIdRequest, producerIDs *metadata.ProducerIDManager) *protocol.InitProducerIdResponse {
+	resp := protocol.InitProducerIdResponse{
+		Version:                 version,
+		ErrorCode:               int16(utils.ErrNoError),
+		ProducerID:              -1,
+		ProducerEpoch:           -1,
+		OngoingTxnProducerID:    -1,
+		OngoingTxnProducerEpoch: -1,
+	}
+
+	if req.TransactionalID != nil {
+		resp.ErrorCode = int16(utils.ErrInvalidRequest)
+		return &resp
+	}
+
+	// producer ID and epoch were added in v3, older versions always ask for a new ID
+	producerID, producerEpoch := int64(-1), int16(-1)
+	if version >= 3 {
+		producerID, producerEpoch = req.ProducerID, req.ProducerEpoch
+	}
+
+	id, epoch, err := producerIDs.InitProducerID(producerID, producerEpoch)
+	if err != nil {
+		resp.ErrorCode = int16(utils.ErrorCode(err))
+		return &resp
+	}
+
+	resp.ProducerID = id
+	resp.ProducerEpoch = epoch
+
+	return &resp
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"testing"
+)
+
+func TestGenerateInitProducerIDResponse(t *testing.T) {
+	producerIDs := metadata.NewProducerIDManager()
+
+	seen := map[int64]bool{}
+	for _, version := range []int16{0, 2, 5} {
+		for i := 0; i < 2; i++ {
+			req := protocol.InitProducerIdRequest{Version: version, TransactionTimeoutMs: 60000, ProducerID: -1, ProducerEpoch: -1}
+			resp := GenerateInitProducerIDResponse(version, req, producerIDs)
+
+			// encode and decode the response, to make sure it survives the wire format of this version
+			respBytes, err := protocol.Encode(resp)
+			if err != nil {
+				t.Fatalf("v%d: error encoding response: %v", version, err)
+			}
+			decoded := protocol.InitProducerIdResponse{}
+			if _, err := protocol.VersionedDecode(respBytes, &decoded, version); err != nil {
+				t.Fatalf("v%d: error decoding response: %v", version, err)
+			}
+
+			if decoded.ErrorCode != int16(utils.ErrNoError) || decoded.ProducerEpoch != 0 {
+				t.Errorf("v%d: error code %d at epoch %d, want no error at epoch 0", version, decoded.ErrorCode, decoded.ProducerEpoch)
+			}
+			if seen[decoded.ProducerID] {
+				t.Errorf("v%d: producer ID %d was returned twice", version, decoded.ProducerID)
+			}
+			seen[decoded.ProducerID] = true
+		}
+	}
+
+	transactionalID := "test-transaction"
+	resp := GenerateInitProducerIDResponse(5, protocol.InitProducerIdRequest{Version: 5, TransactionalID: &transactionalID, ProducerID: -1, ProducerEpoch: -1}, producerIDs)
+	if resp.ErrorCode != int16(utils.ErrInvalidRequest) || resp.ProducerID != -1 {
+		t.Errorf("transactional producer = %+v, want it rejected", resp)
+	}
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"fmt"
+	"net"
+	"opentalaria/coordinator"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"time"
+)
+
+type JoinGroupAPI struct {
+	Request Request
+}
+
+func (j JoinGroupAPI) Name() string {
+	return "JoinGroup"
+}
+
+func (j JoinGroupAPI) GetRequest() Request {
+	return j.Request
+}
+
+func (j JoinGroupAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.JoinGroupResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+// GeneratePayload blocks until the rebalance started by the join completes, which is how Kafka answers JoinGroup as well.
+func (j JoinGroupAPI) GeneratePayload() ([]byte, error) {
+	req := *j.GetRequest().Body.(*protocol.JoinGroupRequest)
+
+	clientID := ""
+	if j.GetRequest().Header.ClientID != nil {
+		clientID = *j.GetRequest().Header.ClientID
+	}
+
+	resp := GenerateJoinGroupResponse(j.GetRequest().Header.RequestApiVersion, req, clientID, remoteHost(j.GetRequest().Conn), j.GetRequest().Groups)
+
+	return encodeResponse(j.GetRequest(), resp)
+}
+
+// ErrorPayload answers the request with a top-level error code.
+func (j JoinGroupAPI) ErrorPayload(kerr utils.KError) ([]byte, error) {
+	version := j.GetRequest().Header.RequestApiVersion
+	resp := protocol.JoinGroupResponse{Version: version, ErrorCode: int16(kerr), GenerationID: -1, ProtocolName: protocolName(version, "")}
+	if !resp.IsValidVersion() {
+		return nil, fmt.Errorf("unknown JoinGroup response version %d", resp.Version)
+	}
+
+	return encodeResponse(j.GetRequest(), &resp)
+}
+
+// GenerateJoinGroupResponse joins the member to the group. Only the leader of the new generation receives the members of the group,
+// so it can compute the assignments it sends with SyncGroup.
+func GenerateJoinGroupResponse(version int16, req protocol.JoinGroupRequest, clientID, clientHost string, groups *coordinator.GroupCoordinator) *protocol.JoinGroupResponse {
+	joinReq := coordinator.JoinRequest{
+		MemberID:         req.MemberID,
+		GroupInstanceID:  req.GroupInstanceID,
+		ClientID:         clientID,
+		ClientHost:       clientHost,
+		ProtocolType:     req.ProtocolType,
+		SessionTimeout:   time.Duration(req.SessionTimeoutMs) * time.Millisecond,
+		RebalanceTimeout: time.Duration(req.RebalanceTimeoutMs) * time.Millisecond,
+	}
+	for _, p := range req.Protocols {
+		joinReq.Protocols = append(joinReq.Protocols, coordinator.Protocol{Name: p.Name, Metadata: p.Metadata})
+	}
+
+	result := groups.JoinGroup(req.GroupID, joinReq)
+
+	resp := protocol.JoinGroupResponse{
+		Version:      version,
+		ErrorCode:    int16(utils.ErrorCode(result.Err)),
+		GenerationID: result.GenerationID,
+		ProtocolName: protocolName(version, result.ProtocolName),
+		Leader:       result.LeaderID,
+		MemberID:     result.MemberID,
+		Members:      []protocol.JoinGroupResponseMember{},
+	}
+	if result.ProtocolType != "" {
+		resp.ProtocolType = &result.ProtocolType
+	}
+
+	for _, m := range result.Members {
+		resp.Members = append(resp.Members, protocol.JoinGroupResponseMember{
+			Version:         version,
+			MemberID:        m.MemberID,
+			GroupInstanceID: m.GroupInstanceID,
+			Metadata:        m.Metadata,
+		})
+	}
+
+	return &resp
+}
+
+// protocolName returns the protocol name of a group response. It is nullable from v7 of JoinGroup,
+// but required by older versions, which get an empty name if no protocol was selected.
+func protocolName(version int16, name string) *string {
+	if name == "" && version >= 7 {
+		return nil
+	}
+
+	return &name
+}
+
+// remoteHost returns the IP of the client, used to describe group members.
+func remoteHost(conn net.Conn) string {
+	if conn == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return conn.RemoteAddr().String()
+	}
+
+	return host
+}
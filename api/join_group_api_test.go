@@ -0,0 +1,82 @@
+package api
+
+import (
+	"fmt"
+	"opentalaria/coordinator"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"testing"
+	"time"
+)
+
+func TestGenerateJoinGroupResponse_SingleConsumer(t *testing.T) {
+	groups := coordinator.NewGroupCoordinator(0, time.Minute)
+
+	for _, version := range []int16{2, 5, 9} {
+		groupID := fmt.Sprintf("group-v%d", version)
+		joinReq := protocol.JoinGroupRequest{
+			Version:            version,
+			GroupID:            groupID,
+			SessionTimeoutMs:   30000,
+			RebalanceTimeoutMs: 60000,
+			ProtocolType:       "consumer",
+			Protocols:          []protocol.JoinGroupRequestProtocol{{Name: "range", Metadata: []byte{0, 1}}},
+		}
+
+		join := GenerateJoinGroupResponse(version, joinReq, "test-client", "127.0.0.1", groups)
+
+		// encode and decode the response, to make sure it survives the wire format of this version
+		respBytes, err := protocol.Encode(join)
+		if err != nil {
+			t.Fatalf("v%d: error encoding response: %v", version, err)
+		}
+		decoded := protocol.JoinGroupResponse{}
+		if _, err := protocol.VersionedDecode(respBytes, &decoded, version); err != nil {
+			t.Fatalf("v%d: error decoding response: %v", version, err)
+		}
+
+		if decoded.ErrorCode != int16(utils.ErrNoError) || decoded.GenerationID != 1 {
+			t.Fatalf("v%d: error code %d at generation %d, want no error at generation 1", version, decoded.ErrorCode, decoded.GenerationID)
+		}
+		if decoded.Leader != decoded.MemberID || len(decoded.Members) != 1 {
+			t.Errorf("v%d: leader %s with members %+v, want the member to lead itself", version, decoded.Leader, decoded.Members)
+		}
+		if decoded.ProtocolName == nil || *decoded.ProtocolName != "range" {
+			t.Errorf("v%d: protocol = %v, want range", version, decoded.ProtocolName)
+		}
+
+		syncReq := protocol.SyncGroupRequest{
+			GroupID:      groupID,
+			GenerationID: decoded.GenerationID,
+			MemberID:     decoded.MemberID,
+			Assignments:  []protocol.SyncGroupRequestAssignment{{MemberID: decoded.MemberID, Assignment: []byte{2, 3}}},
+		}
+		sync := GenerateSyncGroupResponse(version, syncReq, groups)
+		if sync.ErrorCode != int16(utils.ErrNoError) || string(sync.Assignment) != string([]byte{2, 3}) {
+			t.Errorf("v%d: sync = %+v, want the assignment passed through", version, sync)
+		}
+
+		heartbeat := GenerateHeartbeatResponse(version, protocol.HeartbeatRequest{GroupID: groupID, GenerationID: 1, MemberID: decoded.MemberID}, groups)
+		if heartbeat.ErrorCode != int16(utils.ErrNoError) {
+			t.Errorf("v%d: heartbeat error code = %d", version, heartbeat.ErrorCode)
+		}
+
+		leaveReq := protocol.LeaveGroupRequest{
+			GroupID:  groupID,
+			MemberID: decoded.MemberID,
+			Members:  []protocol.MemberIdentity{{MemberID: decoded.MemberID}, {MemberID: "unknown"}},
+		}
+		leave := GenerateLeaveGroupResponse(version, leaveReq, groups)
+		if leave.ErrorCode != int16(utils.ErrNoError) {
+			t.Errorf("v%d: leave error code = %d", version, leave.ErrorCode)
+		}
+		if version >= 3 && (len(leave.Members) != 2 || leave.Members[1].ErrorCode != int16(utils.ErrUnknownMemberId)) {
+			t.Errorf("v%d: leave members = %+v, want the unknown member rejected", version, leave.Members)
+		}
+
+		group, _ := groups.Group(groupID)
+		if group.State() != coordinator.Empty || group.GenerationID() != 2 {
+			t.Errorf("v%d: group %s at generation %d, want Empty at generation 2", version, group.State(), group.GenerationID())
+		}
+	}
+}
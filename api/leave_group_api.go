@@ -0,0 +1,68 @@
+package api
+
+import (
+	"fmt"
+	"opentalaria/coordinator"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+)
+
+type LeaveGroupAPI struct {
+	Request Request
+}
+
+func (l LeaveGroupAPI) Name() string {
+	return "LeaveGroup"
+}
+
+func (l LeaveGroupAPI) GetRequest() Request {
+	return l.Request
+}
+
+func (l LeaveGroupAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.LeaveGroupResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (l LeaveGroupAPI) GeneratePayload() ([]byte, error) {
+	req := *l.GetRequest().Body.(*protocol.LeaveGroupRequest)
+
+	resp := GenerateLeaveGroupResponse(l.GetRequest().Header.RequestApiVersion, req, l.GetRequest().Groups)
+
+	return encodeResponse(l.GetRequest(), resp)
+}
+
+// ErrorPayload answers the request with a top-level error code.
+func (l LeaveGroupAPI) ErrorPayload(kerr utils.KError) ([]byte, error) {
+	resp := protocol.LeaveGroupResponse{Version: l.GetRequest().Header.RequestApiVersion, ErrorCode: int16(kerr)}
+	if !resp.IsValidVersion() {
+		return nil, fmt.Errorf("unknown LeaveGroup response version %d", resp.Version)
+	}
+
+	return encodeResponse(l.GetRequest(), &resp)
+}
+
+// GenerateLeaveGroupResponse removes the members from the group. Before v3 the request contains a single member,
+// whose error is the top-level error of the response. From v3 every member gets its own error code.
+func GenerateLeaveGroupResponse(version int16, req protocol.LeaveGroupRequest, groups *coordinator.GroupCoordinator) *protocol.LeaveGroupResponse {
+	resp := protocol.LeaveGroupResponse{
+		Version:   version,
+		ErrorCode: int16(utils.ErrNoError),
+	}
+
+	if version < 3 {
+		resp.ErrorCode = int16(utils.ErrorCode(groups.LeaveGroup(req.GroupID, req.MemberID)))
+		return &resp
+	}
+
+	resp.Members = []protocol.MemberResponse{}
+	for _, member := range req.Members {
+		resp.Members = append(resp.Members, protocol.MemberResponse{
+			Version:         version,
+			MemberID:        member.MemberID,
+			GroupInstanceID: member.GroupInstanceID,
+			ErrorCode:       int16(utils.ErrorCode(groups.LeaveGroup(req.GroupID, member.MemberID))),
+		})
+	}
+
+	return &resp
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"log/slog"
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/storage"
+	"opentalaria/utils"
+	"time"
+)
+
+const (
+	// special timestamps in a ListOffsets request, which don't look up a record by timestamp
+	latestTimestamp       int64 = -1
+	earliestTimestamp     int64 = -2
+	maxTimestampTimestamp int64 = -3
+)
+
+type ListOffsetsAPI struct {
+	Request Request
+}
+
+func (l ListOffsetsAPI) Name() string {
+	return "ListOffsets"
+}
+
+func (l ListOffsetsAPI) GetRequest() Request {
+	return l.Request
+}
+
+func (l ListOffsetsAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.ListOffsetsResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (l ListOffsetsAPI) GeneratePayload() ([]byte, error) {
+	req := *l.GetRequest().Body.(*protocol.ListOffsetsRequest)
+
+	resp := GenerateListOffsetsResponse(l.GetRequest().Header.RequestApiVersion, req, l.GetRequest().Topics, l.GetRequest().Logs)
+
+	return encodeResponse(l.GetRequest(), resp)
+}
+
+// GenerateListOffsetsResponse looks up the offset for the requested timestamp of each partition.
+// EARLIEST (-2) returns the log start offset, LATEST (-1) the log end offset, MAX_TIMESTAMP (-3) the offset of the record
+// with the largest timestamp and any other value the first offset with a timestamp greater than or equal to it.
+func GenerateListOffsetsResponse(version int16, req protocol.ListOffsetsRequest, topics *metadata.TopicRegistry, logs *storage.LogManager) *protocol.ListOffsetsResponse {
+	resp := protocol.ListOffsetsResponse{
+		Version: version,
+	}
+
+	for _, listTopic := range req.Topics {
+		topicResponse := protocol.ListOffsetsTopicResponse{
+			Version: version,
+			Name:    listTopic.Name,
+		}
+
+		topic, topicExists := topics.GetTopic(listTopic.Name)
+
+		for _, listPartition := range listTopic.Partitions {
+			partitionResponse := protocol.ListOffsetsPartitionResponse{
+				Version:        version,
+				PartitionIndex: listPartition.PartitionIndex,
+				ErrorCode:      int16(utils.ErrNoError),
+				Timestamp:      -1,
+				Offset:         -1,
+				LeaderEpoch:    -1,
+			}
+
+			if !topicExists || listPartition.PartitionIndex < 0 || listPartition.PartitionIndex >= topic.NumPartitions {
+				partitionResponse.ErrorCode = int16(utils.ErrUnknownTopicOrPartition)
+				topicResponse.Partitions = append(topicResponse.Partitions, partitionResponse)
+				continue
+			}
+
+			// like in Fetch, a partition without log has no replica on this broker
+			log, ok := logs.GetPartition(topic.Name, listPartition.PartitionIndex)
+			if !ok {
+				partitionResponse.ErrorCode = int16(utils.ErrNotLeaderForPartition)
+				topicResponse.Partitions = append(topicResponse.Partitions, partitionResponse)
+				continue
+			}
+			// all partitions are led by this broker with the initial epoch, the same as in the Metadata response
+			partitionResponse.LeaderEpoch = 0
+
+			switch listPartition.Timestamp {
+			case earliestTimestamp:
+				partitionResponse.Offset = log.LogStartOffset()
+			case latestTimestamp:
+				partitionResponse.Offset = log.LogEndOffset()
+			default:
+				var offset int64
+				var timestamp time.Time
+				var err error
+				if listPartition.Timestamp == maxTimestampTimestamp {
+					offset, timestamp, ok, err = log.MaxTimestampOffset()
+				} else {
+					offset, timestamp, ok, err = log.OffsetForTimestamp(time.UnixMilli(listPartition.Timestamp))
+				}
+				if err != nil {
+					slog.Error("error reading partition log", "topic", topic.Name, "partition", listPartition.PartitionIndex, "err", err)
+					partitionResponse.ErrorCode = int16(utils.ErrKafkaStorageError)
+				} else if ok {
+					partitionResponse.Offset = offset
+					partitionResponse.Timestamp = timestamp.UnixMilli()
+				}
+			}
+
+			topicResponse.Partitions = append(topicResponse.Partitions, partitionResponse)
+		}
+
+		resp.Topics = append(resp.Topics, topicResponse)
+	}
+
+	return &resp
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/storage"
+	"opentalaria/utils"
+	"testing"
+	"time"
+)
+
+func TestGenerateListOffsetsResponse(t *testing.T) {
+	topics := metadata.NewTopicRegistry()
+	if _, err := topics.CreateTopic("test-topic", 1, 1, false); err != nil {
+		t.Fatalf("error creating topic: %v", err)
+	}
+	logs := storage.NewLogManager()
+	produceTestBatch(t, 8, topics, logs)
+	produceTestBatch(t, 8, topics, logs)
+
+	tests := []struct {
+		name       string
+		topic      string
+		partition  int32
+		timestamp  int64
+		wantOffset int64
+		wantErr    utils.KError
+	}{
+		{name: "earliest", topic: "test-topic", timestamp: earliestTimestamp, wantOffset: 0},
+		{name: "latest", topic: "test-topic", timestamp: latestTimestamp, wantOffset: 4},
+		{name: "max timestamp", topic: "test-topic", timestamp: maxTimestampTimestamp, wantOffset: 0},
+		{name: "by timestamp", topic: "test-topic", timestamp: 1700000000000, wantOffset: 0},
+		{name: "timestamp after the last record", topic: "test-topic", timestamp: 1700000000001, wantOffset: -1},
+		{name: "unknown partition", topic: "test-topic", partition: 1, timestamp: latestTimestamp, wantOffset: -1, wantErr: utils.ErrUnknownTopicOrPartition},
+		{name: "unknown topic", topic: "unknown-topic", timestamp: latestTimestamp, wantOffset: -1, wantErr: utils.ErrUnknownTopicOrPartition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := protocol.ListOffsetsRequest{
+				Version: 7,
+				Topics: []protocol.ListOffsetsTopic{{
+					Name:       tt.topic,
+					Partitions: []protocol.ListOffsetsPartition{{PartitionIndex: tt.partition, Timestamp: tt.timestamp}},
+				}},
+			}
+
+			resp := GenerateListOffsetsResponse(req.Version, req, topics, logs)
+
+			partition := resp.Topics[0].Partitions[0]
+			if partition.ErrorCode != int16(tt.wantErr) {
+				t.Errorf("error code = %d, want %d", partition.ErrorCode, tt.wantErr)
+			}
+			if partition.Offset != tt.wantOffset {
+				t.Errorf("offset = %d, want %d", partition.Offset, tt.wantOffset)
+			}
+
+			if _, err := protocol.Encode(resp); err != nil {
+				t.Errorf("error encoding response: %v", err)
+			}
+		})
+	}
+}
+
+func TestGenerateListOffsetsResponse_AfterRetention(t *testing.T) {
+	topics := metadata.NewTopicRegistry()
+	if _, err := topics.CreateTopic("test-topic", 1, 1, false); err != nil {
+		t.Fatalf("error creating topic: %v", err)
+	}
+	// a segment per batch, the records of 1700000000000 are older than the retention
+	logs, err := storage.OpenLogManager(storage.DiskOptions{
+		Dirs:         []string{t.TempDir()},
+		SegmentBytes: 1,
+		Retention:    storage.Retention{Time: 24 * time.Hour, Bytes: -1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logs.Close()
+	for i := 0; i < 3; i++ {
+		produceTestBatch(t, 8, topics, logs)
+	}
+
+	if err := logs.ApplyRetention(time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	req := protocol.ListOffsetsRequest{
+		Version: 7,
+		Topics: []protocol.ListOffsetsTopic{{
+			Name:       "test-topic",
+			Partitions: []protocol.ListOffsetsPartition{{PartitionIndex: 0, Timestamp: earliestTimestamp}},
+		}},
+	}
+	resp := GenerateListOffsetsResponse(req.Version, req, topics, logs)
+
+	// the active segment is kept
+	if partition := resp.Topics[0].Partitions[0]; partition.ErrorCode != 0 || partition.Offset != 4 {
+		t.Errorf("earliest offset = %d, error code = %d, want 4", partition.Offset, partition.ErrorCode)
+	}
+}
@@ -1,10 +1,14 @@
 package api
 
 import (
+	"errors"
+	"log/slog"
+	"opentalaria/auth"
 	"opentalaria/config"
+	"opentalaria/metadata"
 	"opentalaria/protocol"
+	"opentalaria/storage"
 	"opentalaria/utils"
-	"time"
 )
 
 type MetadataAPI struct {
@@ -24,25 +28,26 @@ func (m MetadataAPI) GetHeaderVersion(requestVersion int16) int16 {
 }
 
 func (m MetadataAPI) GeneratePayload() ([]byte, error) {
-	req := protocol.MetadataRequest{}
-	_, err := protocol.VersionedDecode(m.GetRequest().Message, &req, m.GetRequest().Header.RequestApiVersion)
-	if err != nil {
-		return nil, err
+	req := *m.GetRequest().Body.(*protocol.MetadataRequest)
+
+	var brokers []metadata.ReplicaBroker
+	if m.Request.Brokers != nil {
+		brokers = m.Request.Brokers.ReplicaBrokers()
 	}
 
-	response := GenerateMetadataResponse(m.GetRequest().Header.RequestApiVersion, m.Request.Config)
-	return protocol.Encode(response)
+	response := GenerateMetadataResponse(m.GetRequest().Header.RequestApiVersion, req, m.Request.Config, m.Request.Topics, m.Request.Logs, brokers,
+		m.Request.Authorizer, m.Request.Principal())
+	return encodeResponse(m.GetRequest(), response)
 }
 
-func GenerateMetadataResponse(version int16, config *config.Config) *protocol.MetadataResponse {
-	// For now the returned data is mock, just so we can continue developing the rest of the APIs.
-	// Once we have a more robust project architecture, this struct will be populated with the real
-	// cluster metadata.
+// GenerateMetadataResponse describes the broker and the requested topics the principal may describe. Unknown topics are
+// auto created, if allowed and the principal may create them, with their replicas assigned to the brokers like the topics
+// of CreateTopics. A nil authorizer allows every operation.
+func GenerateMetadataResponse(version int16, req protocol.MetadataRequest, config *config.Config, topics *metadata.TopicRegistry,
+	logs *storage.LogManager, brokers []metadata.ReplicaBroker, authorizer auth.Authorizer, principal auth.Principal) *protocol.MetadataResponse {
 	response := protocol.MetadataResponse{}
 
 	response.Version = version
-	// TODO: handle throttle time
-	response.ThrottleTimeMs = 0
 
 	// TODO: we will have to handle multiple advertised listeners, this implementation is very naive and assumes OpenTalaria won't be run in cluster mode
 	// Since cluster mode is not supported for now, we take the first AdvertisedListener as broker config.
@@ -51,29 +56,118 @@ func GenerateMetadataResponse(version int16, config *config.Config) *protocol.Me
 		NodeID: config.Broker.BrokerID,
 		Host:   listener.Host,
 		Port:   listener.Port,
-		Rack:   nil, // for now OpenTalaria does not support rack awareness.
+		Rack:   config.Broker.Rack,
 	})
 
 	response.ClusterID = &config.Cluster.ClusterID
 	response.ControllerID = config.Broker.BrokerID
-	topicName := "test-topic"
 
-	response.Topics = append(response.Topics, protocol.MetadataResponseTopic{
-		ErrorCode:  int16(utils.ErrNoError),
-		Name:       &topicName,
-		IsInternal: false,
-		Partitions: []protocol.MetadataResponsePartition{{
-			ErrorCode:       int16(utils.ErrNoError),
-			PartitionIndex:  0,
-			LeaderID:        1,
-			LeaderEpoch:     int32(time.Now().Unix()),
-			ReplicaNodes:    []int32{0},
-			IsrNodes:        []int32{0},
-			OfflineReplicas: []int32{0},
-		}},
-		TopicAuthorizedOperations: 0,
-	})
+	// A null topic list requests the metadata of all topics. Since v1, an empty list requests none of them, which clients
+	// use to fetch only the brokers and the controller. In v0, which has no null list, an empty list requests all topics.
+	// Like in Kafka, topics the principal may not describe are left out of the list of all topics,
+	// and topics it requested by name are answered with TOPIC_AUTHORIZATION_FAILED.
+	mayDescribe := func(name string) bool {
+		return isAuthorized(authorizer, principal, auth.OperationDescribe, auth.Resource{Type: auth.ResourceTopic, Name: name})
+	}
+
+	if req.Topics == nil || (version == 0 && len(req.Topics) == 0) {
+		for _, topic := range topics.ListTopics() {
+			if mayDescribe(topic.Name) {
+				response.Topics = append(response.Topics, metadataResponseTopic(topic, config.Broker.BrokerID))
+			}
+		}
+	}
+
+	for _, requestedTopic := range req.Topics {
+		if requestedTopic.Name == nil {
+			continue
+		}
+
+		// checked before the topic is looked up, so the principal can't tell whether it exists
+		if !mayDescribe(*requestedTopic.Name) {
+			response.Topics = append(response.Topics, protocol.MetadataResponseTopic{
+				ErrorCode: int16(utils.ErrTopicAuthorizationFailed),
+				Name:      requestedTopic.Name,
+			})
+			continue
+		}
+
+		topic, ok := topics.GetTopic(*requestedTopic.Name)
+		if !ok {
+			// before v4 clients couldn't opt out of auto creation
+			var err error = utils.ErrUnknownTopicOrPartition
+			if (version < 4 || req.AllowAutoTopicCreation) && config.Env.GetBool("auto.create.topics.enable") {
+				// like CreateTopics, the principal must be allowed to create topics on the cluster or this topic
+				if isAuthorized(authorizer, principal, auth.OperationCreate, auth.ClusterResource) ||
+					isAuthorized(authorizer, principal, auth.OperationCreate, auth.Resource{Type: auth.ResourceTopic, Name: *requestedTopic.Name}) {
+					topic, err = autoCreateTopic(*requestedTopic.Name, config, topics, logs, brokers)
+				} else {
+					err = utils.ErrTopicAuthorizationFailed
+				}
+			}
+			if err != nil {
+				response.Topics = append(response.Topics, protocol.MetadataResponseTopic{
+					ErrorCode: int16(utils.ErrorCode(err)),
+					Name:      requestedTopic.Name,
+				})
+				continue
+			}
+		}
+
+		response.Topics = append(response.Topics, metadataResponseTopic(topic, config.Broker.BrokerID))
+	}
+
 	response.ClusterAuthorizedOperations = 0
 
 	return &response
 }
+
+// autoCreateTopic creates a topic requested by a client with the default number of partitions and replication factor.
+// A topic created concurrently by another client is returned as is.
+func autoCreateTopic(name string, config *config.Config, topics *metadata.TopicRegistry, logs *storage.LogManager, brokers []metadata.ReplicaBroker) (metadata.Topic, error) {
+	topic, err := createTopic(protocol.CreatableTopic{Name: name}, config.NumPartitions, config.DefaultReplicationFactor, false, topics, logs, brokers, config.Broker.BrokerID)
+	if errors.Is(err, utils.ErrTopicAlreadyExists) {
+		if topic, ok := topics.GetTopic(name); ok {
+			return topic, nil
+		}
+	}
+	if err != nil {
+		return metadata.Topic{}, err
+	}
+
+	slog.Info("auto created topic", "topic", name, "partitions", topic.NumPartitions, "replicationFactor", topic.ReplicationFactor)
+
+	return topic, nil
+}
+
+// metadataResponseTopic builds the metadata of a topic. Partitions are led by their preferred leader, the first of their replicas.
+// Partitions of topics without a replica assignment are led by, and replicated only to, the broker with the given ID.
+func metadataResponseTopic(topic metadata.Topic, brokerID int32) protocol.MetadataResponseTopic {
+	name := topic.Name
+	result := protocol.MetadataResponseTopic{
+		ErrorCode:                 int16(utils.ErrNoError),
+		Name:                      &name,
+		TopicID:                   topic.TopicID,
+		IsInternal:                false,
+		TopicAuthorizedOperations: 0,
+	}
+
+	for i := int32(0); i < topic.NumPartitions; i++ {
+		replicas := []int32{brokerID}
+		if int(i) < len(topic.Replicas) {
+			replicas = topic.Replicas[i]
+		}
+
+		result.Partitions = append(result.Partitions, protocol.MetadataResponsePartition{
+			ErrorCode:       int16(utils.ErrNoError),
+			PartitionIndex:  i,
+			LeaderID:        replicas[0],
+			LeaderEpoch:     0,
+			ReplicaNodes:    replicas,
+			IsrNodes:        replicas,
+			OfflineReplicas: []int32{},
+		})
+	}
+
+	return result
+}
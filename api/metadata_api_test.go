@@ -1,8 +1,11 @@
 package api
 
 import (
+	"opentalaria/auth"
 	"opentalaria/config"
+	"opentalaria/metadata"
 	"opentalaria/protocol"
+	"opentalaria/utils"
 	"reflect"
 	"testing"
 )
@@ -19,6 +22,12 @@ func getMockHeader(version int, requestApiKey, requestApiVersion int16, correlat
 	}
 }
 
+type authorizerFunc func(principal auth.Principal, operation auth.Operation, resource auth.Resource) bool
+
+func (f authorizerFunc) Authorize(principal auth.Principal, operation auth.Operation, resource auth.Resource) bool {
+	return f(principal, operation, resource)
+}
+
 func TestMetadataAPI_GetRequest(t *testing.T) {
 	type fields struct {
 		Request Request
@@ -160,10 +169,12 @@ func TestMetadataAPI_GeneratePayload(t *testing.T) {
 			fields: fields{
 				Request: Request{
 					Header: getMockHeader(1, 3, 0, 0),
+					Body:   &protocol.MetadataRequest{Version: 0},
 					Config: config,
+					Topics: metadata.NewTopicRegistry(),
 				},
 			},
-			want:    []byte{0, 0, 0, 1, 0, 0, 0, 1, 0, 9, 49, 50, 55, 46, 48, 46, 48, 46, 49, 0, 0, 35, 132, 0, 0, 0, 1, 0, 0, 0, 10, 116, 101, 115, 116, 45, 116, 111, 112, 105, 99, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0},
+			want:    []byte{0, 0, 0, 1, 0, 0, 0, 1, 0, 9, 49, 50, 55, 46, 48, 46, 48, 46, 49, 0, 0, 35, 132, 0, 0, 0, 0},
 			wantErr: false,
 		},
 	}
@@ -183,3 +194,135 @@ func TestMetadataAPI_GeneratePayload(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateMetadataResponse_TopicList(t *testing.T) {
+	topics := metadata.NewTopicRegistry()
+	if _, err := topics.CreateTopic("test-topic", 1, 1, false); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		version    int16
+		topics     []protocol.MetadataRequestTopic
+		wantTopics int
+	}{
+		{name: "v0 empty list", version: 0, topics: []protocol.MetadataRequestTopic{}, wantTopics: 1},
+		{name: "v1 null list", version: 1, topics: nil, wantTopics: 1},
+		{name: "v1 empty list", version: 1, topics: []protocol.MetadataRequestTopic{}, wantTopics: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the request goes through the wire format, which tells the null list from the empty one
+			reqBytes, err := protocol.Encode(&protocol.MetadataRequest{Version: tt.version, Topics: tt.topics})
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := protocol.MetadataRequest{}
+			if _, err := protocol.VersionedDecode(reqBytes, &req, tt.version); err != nil {
+				t.Fatal(err)
+			}
+
+			resp := GenerateMetadataResponse(tt.version, req, config.MockConfig(), topics, nil, nil, nil, auth.Anonymous)
+			if len(resp.Topics) != tt.wantTopics {
+				t.Errorf("topics = %d, want %d", len(resp.Topics), tt.wantTopics)
+			}
+			if len(resp.Brokers) != 1 {
+				t.Errorf("brokers = %+v, want the local broker", resp.Brokers)
+			}
+		})
+	}
+}
+
+func TestGenerateMetadataResponse_AutoCreateTopics(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:9092")
+	t.Setenv("OT_NUM_PARTITIONS", "3")
+
+	name := "auto-topic"
+	// the principal may describe every topic, but only create what the test allows
+	allowCreate := func(resource auth.Resource) auth.Authorizer {
+		return authorizerFunc(func(_ auth.Principal, operation auth.Operation, r auth.Resource) bool {
+			return operation != auth.OperationCreate || r == resource
+		})
+	}
+	tests := []struct {
+		name        string
+		enable      string
+		version     int16
+		allowCreate bool
+		authorizer  auth.Authorizer
+		wantCreated bool
+		wantErr     utils.KError
+	}{
+		{name: "disabled", enable: "false", version: 12, allowCreate: true, wantCreated: false, wantErr: utils.ErrUnknownTopicOrPartition},
+		{name: "enabled", enable: "true", version: 12, allowCreate: true, wantCreated: true},
+		{name: "not allowed by the client", enable: "true", version: 12, allowCreate: false, wantCreated: false, wantErr: utils.ErrUnknownTopicOrPartition},
+		{name: "always allowed before v4", enable: "true", version: 3, allowCreate: false, wantCreated: true},
+		{name: "create on the cluster", enable: "true", version: 12, allowCreate: true, authorizer: allowCreate(auth.ClusterResource), wantCreated: true},
+		{name: "create on the topic", enable: "true", version: 12, allowCreate: true, authorizer: allowCreate(auth.Resource{Type: auth.ResourceTopic, Name: name}), wantCreated: true},
+		{name: "create not authorized", enable: "true", version: 12, allowCreate: true, authorizer: allowCreate(auth.Resource{Type: auth.ResourceTopic, Name: "other"}), wantCreated: false, wantErr: utils.ErrTopicAuthorizationFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OT_AUTO_CREATE_TOPICS_ENABLE", tt.enable)
+
+			conf, err := config.NewConfig("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			topics := metadata.NewTopicRegistry()
+
+			req := protocol.MetadataRequest{Version: tt.version, Topics: []protocol.MetadataRequestTopic{{Name: &name}}, AllowAutoTopicCreation: tt.allowCreate}
+			resp := GenerateMetadataResponse(tt.version, req, conf, topics, nil, nil, tt.authorizer, auth.Anonymous)
+
+			topic := resp.Topics[0]
+			if tt.wantCreated {
+				if topic.ErrorCode != int16(utils.ErrNoError) || len(topic.Partitions) != 3 {
+					t.Errorf("topic = error code %d with %d partitions, want no error with 3 partitions", topic.ErrorCode, len(topic.Partitions))
+				}
+			} else if topic.ErrorCode != int16(tt.wantErr) {
+				t.Errorf("error code = %d, want %d", topic.ErrorCode, tt.wantErr)
+			}
+
+			if _, ok := topics.GetTopic(name); ok != tt.wantCreated {
+				t.Errorf("topic exists = %v, want %v", ok, tt.wantCreated)
+			}
+		})
+	}
+}
+
+func TestGenerateMetadataResponse_Rack(t *testing.T) {
+	rack := "rack-1"
+	tests := []struct {
+		name string
+		rack *string
+	}{
+		{name: "configured rack", rack: &rack},
+		{name: "no rack is null", rack: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := config.MockConfig()
+			conf.Broker.Rack = tt.rack
+
+			// the rack is part of the response since v1
+			resp := GenerateMetadataResponse(1, protocol.MetadataRequest{Version: 1}, conf, metadata.NewTopicRegistry(), nil, nil, nil, auth.Anonymous)
+
+			respBytes, err := protocol.Encode(resp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded := protocol.MetadataResponse{}
+			if _, err := protocol.VersionedDecode(respBytes, &decoded, 1); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(decoded.Brokers) != 1 {
+				t.Fatalf("brokers = %+v, want the local broker", decoded.Brokers)
+			}
+			if got := decoded.Brokers[0].Rack; !reflect.DeepEqual(got, tt.rack) {
+				t.Errorf("rack = %v, want %v", got, tt.rack)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"opentalaria/coordinator"
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"time"
+)
+
+type OffsetCommitAPI struct {
+	Request Request
+}
+
+func (o OffsetCommitAPI) Name() string {
+	return "OffsetCommit"
+}
+
+func (o OffsetCommitAPI) GetRequest() Request {
+	return o.Request
+}
+
+func (o OffsetCommitAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.OffsetCommitResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (o OffsetCommitAPI) GeneratePayload() ([]byte, error) {
+	req := *o.GetRequest().Body.(*protocol.OffsetCommitRequest)
+
+	resp := GenerateOffsetCommitResponse(o.GetRequest().Header.RequestApiVersion, req, o.GetRequest().Topics, o.GetRequest().Groups)
+
+	return encodeResponse(o.GetRequest(), resp)
+}
+
+// GenerateOffsetCommitResponse stores the offsets of the group. Offsets of unknown partitions are rejected,
+// all other partitions get the result of the commit, which fails as a whole if the member may not commit for the group.
+func GenerateOffsetCommitResponse(version int16, req protocol.OffsetCommitRequest, topics *metadata.TopicRegistry, groups *coordinator.GroupCoordinator) *protocol.OffsetCommitResponse {
+	resp := protocol.OffsetCommitResponse{
+		Version: version,
+		Topics:  []protocol.OffsetCommitResponseTopic{},
+	}
+
+	now := time.Now()
+	offsets := map[coordinator.TopicPartition]coordinator.OffsetAndMetadata{}
+	// the error codes of the committed partitions are only known after the commit
+	committed := []*protocol.OffsetCommitResponsePartition{}
+
+	for _, commitTopic := range req.Topics {
+		topic, topicExists := topics.GetTopic(commitTopic.Name)
+
+		topicResponse := protocol.OffsetCommitResponseTopic{
+			Version:    version,
+			Name:       commitTopic.Name,
+			Partitions: make([]protocol.OffsetCommitResponsePartition, len(commitTopic.Partitions)),
+		}
+
+		for i, commitPartition := range commitTopic.Partitions {
+			topicResponse.Partitions[i] = protocol.OffsetCommitResponsePartition{
+				Version:        version,
+				PartitionIndex: commitPartition.PartitionIndex,
+				ErrorCode:      int16(utils.ErrNoError),
+			}
+
+			if !topicExists || commitPartition.PartitionIndex < 0 || commitPartition.PartitionIndex >= topic.NumPartitions {
+				topicResponse.Partitions[i].ErrorCode = int16(utils.ErrUnknownTopicOrPartition)
+				continue
+			}
+
+			// the leader epoch is only sent from v6
+			leaderEpoch := commitPartition.CommittedLeaderEpoch
+			if version < 6 {
+				leaderEpoch = -1
+			}
+
+			offsets[coordinator.TopicPartition{Topic: commitTopic.Name, Partition: commitPartition.PartitionIndex}] = coordinator.OffsetAndMetadata{
+				Offset:      commitPartition.CommittedOffset,
+				LeaderEpoch: leaderEpoch,
+				Metadata:    commitPartition.CommittedMetadata,
+				CommitTime:  now,
+			}
+			committed = append(committed, &topicResponse.Partitions[i])
+		}
+
+		resp.Topics = append(resp.Topics, topicResponse)
+	}
+
+	if len(offsets) > 0 {
+		errCode := int16(utils.ErrorCode(groups.CommitOffsets(req.GroupID, req.GenerationIdOrMemberEpoch, req.MemberID, offsets)))
+		for _, partition := range committed {
+			partition.ErrorCode = errCode
+		}
+	}
+
+	return &resp
+}
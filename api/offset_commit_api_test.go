@@ -0,0 +1,149 @@
+package api
+
+import (
+	"opentalaria/coordinator"
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"testing"
+	"time"
+)
+
+func TestOffsetCommitFetch_RoundTrip(t *testing.T) {
+	topics := metadata.NewTopicRegistry()
+	if _, err := topics.CreateTopic("test-topic", 2, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	groups := coordinator.NewGroupCoordinator(0, time.Minute)
+
+	commitMetadata := "consumer-1"
+	commitReq := protocol.OffsetCommitRequest{
+		GroupID:                   "test-group",
+		GenerationIdOrMemberEpoch: -1,
+		Topics: []protocol.OffsetCommitRequestTopic{{
+			Name: "test-topic",
+			Partitions: []protocol.OffsetCommitRequestPartition{
+				{PartitionIndex: 1, CommittedOffset: 42, CommittedLeaderEpoch: 3, CommittedMetadata: &commitMetadata},
+				{PartitionIndex: 5, CommittedOffset: 7},
+			},
+		}},
+	}
+
+	commit := GenerateOffsetCommitResponse(8, commitReq, topics, groups)
+	partitions := commit.Topics[0].Partitions
+	if partitions[0].ErrorCode != int16(utils.ErrNoError) {
+		t.Errorf("partition 1: error code = %d", partitions[0].ErrorCode)
+	}
+	if partitions[1].ErrorCode != int16(utils.ErrUnknownTopicOrPartition) {
+		t.Errorf("partition 5: error code = %d, want %d", partitions[1].ErrorCode, utils.ErrUnknownTopicOrPartition)
+	}
+
+	for _, version := range []int16{1, 7, 8} {
+		fetchReq := protocol.OffsetFetchRequest{
+			Version: version,
+			GroupID: "test-group",
+			Topics:  []protocol.OffsetFetchRequestTopic{{Name: "test-topic", PartitionIndexes: []int32{1}}},
+			Groups: []protocol.OffsetFetchRequestGroup{{
+				GroupID: "test-group",
+				Topics:  []protocol.OffsetFetchRequestTopics{{Name: "test-topic", PartitionIndexes: []int32{1}}},
+			}},
+		}
+
+		resp := GenerateOffsetFetchResponse(version, fetchReq, groups)
+
+		// encode and decode the response, to make sure it survives the wire format of this version
+		respBytes, err := protocol.Encode(resp)
+		if err != nil {
+			t.Fatalf("v%d: error encoding response: %v", version, err)
+		}
+		decoded := protocol.OffsetFetchResponse{}
+		if _, err := protocol.VersionedDecode(respBytes, &decoded, version); err != nil {
+			t.Fatalf("v%d: error decoding response: %v", version, err)
+		}
+
+		var offset int64
+		var fetchedMetadata *string
+		if version < 8 {
+			offset, fetchedMetadata = decoded.Topics[0].Partitions[0].CommittedOffset, decoded.Topics[0].Partitions[0].Metadata
+		} else {
+			offset, fetchedMetadata = decoded.Groups[0].Topics[0].Partitions[0].CommittedOffset, decoded.Groups[0].Topics[0].Partitions[0].Metadata
+		}
+
+		if offset != 42 {
+			t.Errorf("v%d: committed offset = %d, want 42", version, offset)
+		}
+		if fetchedMetadata == nil || *fetchedMetadata != commitMetadata {
+			t.Errorf("v%d: metadata = %v, want %s", version, fetchedMetadata, commitMetadata)
+		}
+	}
+
+	// a null topic list fetches all committed offsets
+	all := GenerateOffsetFetchResponse(7, protocol.OffsetFetchRequest{GroupID: "test-group"}, groups)
+	if len(all.Topics) != 1 || len(all.Topics[0].Partitions) != 1 || all.Topics[0].Partitions[0].CommittedLeaderEpoch != 3 {
+		t.Errorf("all offsets = %+v, want the committed partition 1", all.Topics)
+	}
+}
+
+func TestOffsetFetch_Uncommitted(t *testing.T) {
+	groups := coordinator.NewGroupCoordinator(0, time.Minute)
+
+	for _, groupID := range []string{"unknown-group", "empty-group"} {
+		if groupID == "empty-group" {
+			err := groups.CommitOffsets(groupID, -1, "", map[coordinator.TopicPartition]coordinator.OffsetAndMetadata{{Topic: "other-topic", Partition: 0}: {Offset: 1}})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		req := protocol.OffsetFetchRequest{
+			GroupID: groupID,
+			Topics:  []protocol.OffsetFetchRequestTopic{{Name: "test-topic", PartitionIndexes: []int32{0}}},
+		}
+
+		resp := GenerateOffsetFetchResponse(5, req, groups)
+
+		partition := resp.Topics[0].Partitions[0]
+		if partition.CommittedOffset != -1 || partition.ErrorCode != int16(utils.ErrNoError) {
+			t.Errorf("%s: offset %d with error code %d, want -1 without error", groupID, partition.CommittedOffset, partition.ErrorCode)
+		}
+	}
+}
+
+func TestOffsetCommit_GroupMember(t *testing.T) {
+	topics := metadata.NewTopicRegistry()
+	if _, err := topics.CreateTopic("test-topic", 1, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	groups := coordinator.NewGroupCoordinator(0, time.Minute)
+
+	join := groups.JoinGroup("test-group", coordinator.JoinRequest{
+		ProtocolType:     "consumer",
+		Protocols:        []coordinator.Protocol{{Name: "range"}},
+		SessionTimeout:   time.Minute,
+		RebalanceTimeout: time.Minute,
+	})
+	groups.SyncGroup("test-group", join.GenerationID, join.MemberID, nil)
+
+	commit := func(generationID int32, memberID string) int16 {
+		req := protocol.OffsetCommitRequest{
+			GroupID:                   "test-group",
+			GenerationIdOrMemberEpoch: generationID,
+			MemberID:                  memberID,
+			Topics: []protocol.OffsetCommitRequestTopic{{
+				Name:       "test-topic",
+				Partitions: []protocol.OffsetCommitRequestPartition{{PartitionIndex: 0, CommittedOffset: 10}},
+			}},
+		}
+		return GenerateOffsetCommitResponse(8, req, topics, groups).Topics[0].Partitions[0].ErrorCode
+	}
+
+	if errCode := commit(join.GenerationID, join.MemberID); errCode != int16(utils.ErrNoError) {
+		t.Errorf("member commit: error code = %d", errCode)
+	}
+	if errCode := commit(join.GenerationID+1, join.MemberID); errCode != int16(utils.ErrIllegalGeneration) {
+		t.Errorf("stale generation: error code = %d, want %d", errCode, utils.ErrIllegalGeneration)
+	}
+	if errCode := commit(-1, ""); errCode != int16(utils.ErrUnknownMemberId) {
+		t.Errorf("commit without membership to an active group: error code = %d, want %d", errCode, utils.ErrUnknownMemberId)
+	}
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"cmp"
+	"fmt"
+	"opentalaria/coordinator"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"slices"
+)
+
+type OffsetFetchAPI struct {
+	Request Request
+}
+
+func (o OffsetFetchAPI) Name() string {
+	return "OffsetFetch"
+}
+
+func (o OffsetFetchAPI) GetRequest() Request {
+	return o.Request
+}
+
+func (o OffsetFetchAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.OffsetFetchResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (o OffsetFetchAPI) GeneratePayload() ([]byte, error) {
+	req := *o.GetRequest().Body.(*protocol.OffsetFetchRequest)
+
+	resp := GenerateOffsetFetchResponse(o.GetRequest().Header.RequestApiVersion, req, o.GetRequest().Groups)
+
+	return encodeResponse(o.GetRequest(), resp)
+}
+
+// ErrorPayload answers the request with a top-level error code, which only exists from v2 to v7.
+func (o OffsetFetchAPI) ErrorPayload(kerr utils.KError) ([]byte, error) {
+	resp := protocol.OffsetFetchResponse{Version: o.GetRequest().Header.RequestApiVersion, ErrorCode: int16(kerr)}
+	if !resp.IsValidVersion() || resp.Version < 2 || resp.Version >= 8 {
+		return nil, fmt.Errorf("OffsetFetch response version %d can't express a top-level error", resp.Version)
+	}
+
+	return encodeResponse(o.GetRequest(), &resp)
+}
+
+// committedOffset is the committed offset of a partition in an OffsetFetch response.
+type committedOffset struct {
+	partition int32
+	offset    coordinator.OffsetAndMetadata
+}
+
+// GenerateOffsetFetchResponse returns the committed offsets of the requested partitions, or of all partitions if no topics are requested.
+// Partitions without committed offset, including partitions of unknown groups, get offset -1 without error,
+// which tells the consumer to apply its offset reset policy.
+// Before v8 the request contains a single group, from v8 the offsets of several groups can be fetched at once.
+func GenerateOffsetFetchResponse(version int16, req protocol.OffsetFetchRequest, groups *coordinator.GroupCoordinator) *protocol.OffsetFetchResponse {
+	resp := protocol.OffsetFetchResponse{
+		Version:   version,
+		ErrorCode: int16(utils.ErrNoError),
+	}
+
+	if version < 8 {
+		requested := map[string][]int32{}
+		for _, topic := range req.Topics {
+			requested[topic.Name] = topic.PartitionIndexes
+		}
+		if req.Topics == nil {
+			requested = nil
+		}
+
+		resp.Topics = []protocol.OffsetFetchResponseTopic{}
+		for _, topic := range fetchOffsets(groups, req.GroupID, requested) {
+			topicResponse := protocol.OffsetFetchResponseTopic{Version: version, Name: topic.name}
+			for _, p := range topic.partitions {
+				topicResponse.Partitions = append(topicResponse.Partitions, protocol.OffsetFetchResponsePartition{
+					Version:              version,
+					PartitionIndex:       p.partition,
+					CommittedOffset:      p.offset.Offset,
+					CommittedLeaderEpoch: p.offset.LeaderEpoch,
+					Metadata:             p.offset.Metadata,
+					ErrorCode:            int16(utils.ErrNoError),
+				})
+			}
+			resp.Topics = append(resp.Topics, topicResponse)
+		}
+
+		return &resp
+	}
+
+	resp.Groups = []protocol.OffsetFetchResponseGroup{}
+	for _, group := range req.Groups {
+		requested := map[string][]int32{}
+		for _, topic := range group.Topics {
+			requested[topic.Name] = topic.PartitionIndexes
+		}
+		if group.Topics == nil {
+			requested = nil
+		}
+
+		groupResponse := protocol.OffsetFetchResponseGroup{
+			Version:   version,
+			GroupID:   group.GroupID,
+			Topics:    []protocol.OffsetFetchResponseTopics{},
+			ErrorCode: int16(utils.ErrNoError),
+		}
+		for _, topic := range fetchOffsets(groups, group.GroupID, requested) {
+			topicResponse := protocol.OffsetFetchResponseTopics{Version: version, Name: topic.name}
+			for _, p := range topic.partitions {
+				topicResponse.Partitions = append(topicResponse.Partitions, protocol.OffsetFetchResponsePartitions{
+					Version:              version,
+					PartitionIndex:       p.partition,
+					CommittedOffset:      p.offset.Offset,
+					CommittedLeaderEpoch: p.offset.LeaderEpoch,
+					Metadata:             p.offset.Metadata,
+					ErrorCode:            int16(utils.ErrNoError),
+				})
+			}
+			groupResponse.Topics = append(groupResponse.Topics, topicResponse)
+		}
+		resp.Groups = append(resp.Groups, groupResponse)
+	}
+
+	return &resp
+}
+
+type topicOffsets struct {
+	name       string
+	partitions []committedOffset
+}
+
+// fetchOffsets returns the committed offsets of the requested partitions by topic, or all committed offsets of the group if requested is nil.
+func fetchOffsets(groups *coordinator.GroupCoordinator, groupID string, requested map[string][]int32) []topicOffsets {
+	if requested == nil {
+		requested = map[string][]int32{}
+		for tp := range groups.FetchAllOffsets(groupID) {
+			requested[tp.Topic] = append(requested[tp.Topic], tp.Partition)
+		}
+	}
+
+	noMetadata := ""
+	result := []topicOffsets{}
+	for name, partitions := range requested {
+		topic := topicOffsets{name: name}
+		for _, partition := range partitions {
+			offset, ok := groups.FetchOffset(groupID, coordinator.TopicPartition{Topic: name, Partition: partition})
+			if !ok {
+				offset = coordinator.OffsetAndMetadata{Offset: -1, LeaderEpoch: -1, Metadata: &noMetadata}
+			}
+			topic.partitions = append(topic.partitions, committedOffset{partition: partition, offset: offset})
+		}
+
+		slices.SortFunc(topic.partitions, func(a, b committedOffset) int { return cmp.Compare(a.partition, b.partition) })
+		result = append(result, topic)
+	}
+
+	slices.SortFunc(result, func(a, b topicOffsets) int { return cmp.Compare(a.name, b.name) })
+	return result
+}
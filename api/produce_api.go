@@ -2,8 +2,11 @@ package api
 
 import (
 	"log/slog"
+	"opentalaria/metadata"
 	"opentalaria/protocol"
+	"opentalaria/storage"
 	"opentalaria/utils"
+	"time"
 )
 
 type ProduceAPI struct {
@@ -22,40 +25,91 @@ func (p ProduceAPI) GetHeaderVersion(requestVersion int16) int16 {
 	return (&protocol.ProduceResponse{Version: requestVersion}).GetHeaderVersion()
 }
 
-// TODO: this is a placeholder function for now. We need to implement a backend that handles cluster topology in order to implement the API correctly and consume the messages.
 func (p ProduceAPI) GeneratePayload() ([]byte, error) {
-	req := protocol.ProduceRequest{}
-	_, err := protocol.VersionedDecode(p.GetRequest().Message, &req, p.GetRequest().Header.RequestApiVersion)
-	if err != nil {
-		return nil, err
+	req := *p.GetRequest().Body.(*protocol.ProduceRequest)
+
+	resp := GenerateProduceResponse(p.GetRequest().Header.RequestApiVersion, req, p.GetRequest().Topics, p.GetRequest().Logs)
+
+	// the client doesn't wait for a response if acks is 0
+	if req.Acks == 0 {
+		return nil, nil
 	}
 
+	return encodeResponse(p.GetRequest(), resp)
+}
+
+// GenerateProduceResponse appends the record batches of the request to the partition logs and returns the assigned base offsets.
+// The batches of topics with message.timestamp.type LogAppendTime get the time of the append as their timestamp.
+func GenerateProduceResponse(version int16, req protocol.ProduceRequest, topics *metadata.TopicRegistry, logs *storage.LogManager) *protocol.ProduceResponse {
 	resp := protocol.ProduceResponse{
-		Version: p.GetRequest().Header.RequestApiVersion,
+		Version: version,
 	}
 
-	for _, topic := range req.TopicData {
-		topicResponse := protocol.TopicProduceResponse{}
-		topicResponse.Version = resp.Version
-		topicResponse.Name = topic.Name
+	for _, topicData := range req.TopicData {
+		topicResponse := protocol.TopicProduceResponse{
+			Version: version,
+			Name:    topicData.Name,
+		}
 
-		for _, partition := range topic.PartitionData {
-			slog.Debug("Received records", "records", partition.Records)
+		topic, topicExists := topics.GetTopic(topicData.Name)
 
-			topicResponse.PartitionResponses = append(topicResponse.PartitionResponses, protocol.PartitionProduceResponse{
-				Version:    resp.Version,
-				Index:      partition.Index,
-				ErrorCode:  int16(utils.ErrNoError),
-				BaseOffset: partition.Records.BaseOffset,
-				// TODO: this needs to be implemented, see documentation for details
+		for _, partition := range topicData.PartitionData {
+			partitionResponse := protocol.PartitionProduceResponse{
+				Version:         version,
+				Index:           partition.Index,
+				ErrorCode:       int16(utils.ErrNoError),
+				BaseOffset:      -1,
 				LogAppendTimeMs: -1,
-				LogStartOffset:  0,
-				// TODO: Don't forget to handle errors when the protocol is fully implemented
-			})
+				LogStartOffset:  -1,
+			}
+
+			switch {
+			case !topicExists || partition.Index < 0 || partition.Index >= topic.NumPartitions:
+				partitionResponse.ErrorCode = int16(utils.ErrUnknownTopicOrPartition)
+			case len(partition.Records.Batches) != 1:
+				// since v3 a produce request must contain exactly one record batch per partition
+				partitionResponse.ErrorCode = int16(utils.ErrInvalidRecord)
+			default:
+				batch := partition.Records.Batches[0]
+				if err := batch.VerifyCRC(); err != nil {
+					slog.Warn("rejecting corrupt record batch", "topic", topicData.Name, "partition", partition.Index, "err", err)
+					partitionResponse.ErrorCode = int16(utils.ErrInvalidMessage)
+					break
+				}
+
+				// with LogAppendTime, the timestamps of the producer are replaced with the time of the append
+				if timestampType, _ := topic.Config(metadata.TopicConfigMessageTimestampType); timestampType == metadata.TimestampTypeLogAppendTime {
+					appendTime := time.Now()
+					batch.SetLogAppendTime(appendTime)
+					partitionResponse.LogAppendTimeMs = appendTime.UnixMilli()
+				}
+
+				log, err := logs.GetOrCreatePartition(topicData.Name, partition.Index)
+				if err != nil {
+					slog.Error("error creating partition log", "topic", topicData.Name, "partition", partition.Index, "err", err)
+					partitionResponse.ErrorCode = int16(utils.ErrKafkaStorageError)
+					break
+				}
+				baseOffset, err := log.Append(batch)
+				if err != nil {
+					slog.Error("error appending to partition log", "topic", topicData.Name, "partition", partition.Index, "err", err)
+					partitionResponse.ErrorCode = int16(utils.ErrKafkaStorageError)
+					break
+				}
+				partitionResponse.BaseOffset = baseOffset
+				partitionResponse.LogStartOffset = log.LogStartOffset()
+			}
+
+			if partitionResponse.ErrorCode != int16(utils.ErrNoError) {
+				errMsg := utils.KError(partitionResponse.ErrorCode).Error()
+				partitionResponse.ErrorMessage = &errMsg
+			}
+
+			topicResponse.PartitionResponses = append(topicResponse.PartitionResponses, partitionResponse)
 		}
 
 		resp.Responses = append(resp.Responses, topicResponse)
 	}
 
-	return protocol.Encode(&resp)
+	return &resp
 }
@@ -0,0 +1,225 @@
+package api
+
+import (
+	"bytes"
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/storage"
+	"opentalaria/utils"
+	"testing"
+	"time"
+)
+
+func TestGenerateProduceResponse(t *testing.T) {
+	topics := metadata.NewTopicRegistry()
+	if _, err := topics.CreateTopic("test-topic", 2, 1, false); err != nil {
+		t.Fatalf("error creating topic: %v", err)
+	}
+	logs := storage.NewLogManager()
+
+	batch := protocol.RecordBatch{LastOffsetDelta: 2, Records: make([]protocol.Record, 3)}
+	req := protocol.ProduceRequest{
+		Version: 8,
+		Acks:    -1,
+		TopicData: []protocol.TopicProduceData{
+			{Name: "test-topic", PartitionData: []protocol.PartitionProduceData{
+				{Index: 1, Records: protocol.Records{Batches: []protocol.RecordBatch{batch}}},
+				{Index: 1, Records: protocol.Records{Batches: []protocol.RecordBatch{batch}}},
+				{Index: 2, Records: protocol.Records{Batches: []protocol.RecordBatch{batch}}},
+				{Index: 0},
+			}},
+			{Name: "unknown-topic", PartitionData: []protocol.PartitionProduceData{
+				{Index: 0, Records: protocol.Records{Batches: []protocol.RecordBatch{batch}}},
+			}},
+		},
+	}
+
+	resp := GenerateProduceResponse(req.Version, req, topics, logs)
+
+	partitions := resp.Responses[0].PartitionResponses
+	wantErrors := []utils.KError{utils.ErrNoError, utils.ErrNoError, utils.ErrUnknownTopicOrPartition, utils.ErrInvalidRecord}
+	for i, want := range wantErrors {
+		if partitions[i].ErrorCode != int16(want) {
+			t.Errorf("partition response %d error code = %d, want %d", i, partitions[i].ErrorCode, want)
+		}
+	}
+
+	if partitions[0].BaseOffset != 0 || partitions[1].BaseOffset != 3 {
+		t.Errorf("base offsets = %d, %d, want 0, 3", partitions[0].BaseOffset, partitions[1].BaseOffset)
+	}
+
+	if resp.Responses[1].PartitionResponses[0].ErrorCode != int16(utils.ErrUnknownTopicOrPartition) {
+		t.Errorf("unknown topic error code = %d", resp.Responses[1].PartitionResponses[0].ErrorCode)
+	}
+
+	log, ok := logs.GetPartition("test-topic", 1)
+	if !ok || log.LogEndOffset() != 6 {
+		t.Error("expected 6 records in the partition log")
+	}
+
+	if _, err := protocol.Encode(resp); err != nil {
+		t.Errorf("error encoding response: %v", err)
+	}
+}
+
+func TestGenerateProduceResponse_CorruptBatch(t *testing.T) {
+	topics := metadata.NewTopicRegistry()
+	if _, err := topics.CreateTopic("test-topic", 1, 1, false); err != nil {
+		t.Fatalf("error creating topic: %v", err)
+	}
+	logs := storage.NewLogManager()
+
+	batch := protocol.RecordBatch{
+		ProducerId:    -1,
+		ProducerEpoch: -1,
+		BaseSequence:  -1,
+		Records:       []protocol.Record{{Value: []byte("value")}},
+	}
+	req := protocol.ProduceRequest{
+		Version: 8,
+		Acks:    -1,
+		TopicData: []protocol.TopicProduceData{{
+			Name:          "test-topic",
+			PartitionData: []protocol.PartitionProduceData{{Index: 0, Records: protocol.Records{Batches: []protocol.RecordBatch{batch}}}},
+		}},
+	}
+	reqBytes, err := protocol.Encode(&req)
+	if err != nil {
+		t.Fatalf("error encoding request: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		corrupt bool
+		wantErr utils.KError
+	}{
+		{name: "valid", wantErr: utils.ErrNoError},
+		// a bit of the record value flipped on the way, which the decoding doesn't notice
+		{name: "corrupt", corrupt: true, wantErr: utils.ErrInvalidMessage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := bytes.Clone(reqBytes)
+			if tt.corrupt {
+				buf[bytes.Index(buf, []byte("value"))] ^= 0x01
+			}
+			decoded := protocol.ProduceRequest{}
+			if _, err := protocol.VersionedDecode(buf, &decoded, req.Version); err != nil {
+				t.Fatalf("error decoding request: %v", err)
+			}
+
+			before := int64(0)
+			if log, ok := logs.GetPartition("test-topic", 0); ok {
+				before = log.LogEndOffset()
+			}
+
+			resp := GenerateProduceResponse(req.Version, decoded, topics, logs)
+
+			partition := resp.Responses[0].PartitionResponses[0]
+			if partition.ErrorCode != int16(tt.wantErr) {
+				t.Errorf("error code = %d, want %d", partition.ErrorCode, tt.wantErr)
+			}
+			after := int64(0)
+			if log, ok := logs.GetPartition("test-topic", 0); ok {
+				after = log.LogEndOffset()
+			}
+			if stored := after > before; stored == tt.corrupt {
+				t.Errorf("batch stored = %v, want %v", stored, !tt.corrupt)
+			}
+		})
+	}
+}
+
+func TestGenerateProduceResponse_TimestampType(t *testing.T) {
+	topics := metadata.NewTopicRegistry()
+	for name, timestampType := range map[string]string{"create-time": metadata.TimestampTypeCreateTime, "log-append-time": metadata.TimestampTypeLogAppendTime} {
+		if _, err := topics.CreateTopic(name, 1, 1, false); err != nil {
+			t.Fatalf("error creating topic: %v", err)
+		}
+		if err := topics.SetTopicConfigs(name, map[string]string{metadata.TopicConfigMessageTimestampType: timestampType}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	logs := storage.NewLogManager()
+
+	createTime := time.UnixMilli(1700000000000)
+	batch := protocol.RecordBatch{
+		BaseTimestamp:   createTime,
+		MaxTimestamp:    createTime.Add(5 * time.Millisecond),
+		ProducerId:      -1,
+		ProducerEpoch:   -1,
+		BaseSequence:    -1,
+		LastOffsetDelta: 1,
+		Records:         []protocol.Record{{Value: []byte("first")}, {TimestampDelta: 5, OffsetDelta: 1, Value: []byte("second")}},
+	}
+
+	tests := []struct {
+		topic         string
+		logAppendTime bool
+	}{
+		{topic: "create-time"},
+		{topic: "log-append-time", logAppendTime: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.topic, func(t *testing.T) {
+			req := protocol.ProduceRequest{
+				Version: 8,
+				Acks:    -1,
+				TopicData: []protocol.TopicProduceData{{
+					Name:          tt.topic,
+					PartitionData: []protocol.PartitionProduceData{{Index: 0, Records: protocol.Records{Batches: []protocol.RecordBatch{batch}}}},
+				}},
+			}
+			// run the request through the wire format, so the batch is appended as the producer sent it
+			buf, err := protocol.Encode(&req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded := protocol.ProduceRequest{}
+			if _, err := protocol.VersionedDecode(buf, &decoded, req.Version); err != nil {
+				t.Fatal(err)
+			}
+
+			before := time.Now().Truncate(time.Millisecond)
+			resp := GenerateProduceResponse(req.Version, decoded, topics, logs)
+			after := time.Now()
+
+			partition := resp.Responses[0].PartitionResponses[0]
+			if partition.ErrorCode != int16(utils.ErrNoError) {
+				t.Fatalf("error code = %d", partition.ErrorCode)
+			}
+
+			log, _ := logs.GetPartition(tt.topic, 0)
+			batches, err := log.Read(0, 1<<20, true)
+			if err != nil || len(batches) != 1 {
+				t.Fatalf("read %d batches, err = %v", len(batches), err)
+			}
+			stored := batches[0]
+			if err := stored.VerifyCRC(); err != nil {
+				t.Errorf("stored batch: %v", err)
+			}
+
+			if !tt.logAppendTime {
+				if partition.LogAppendTimeMs != -1 {
+					t.Errorf("log append time = %d, want -1", partition.LogAppendTimeMs)
+				}
+				if stored.TimestampType != protocol.CreateTime || !stored.MaxTimestamp.Equal(batch.MaxTimestamp) || !stored.RecordTimestamp(1).Equal(batch.MaxTimestamp) {
+					t.Errorf("stored batch has timestamp type %d and max timestamp %v, want the timestamps of the producer", stored.TimestampType, stored.MaxTimestamp)
+				}
+				return
+			}
+
+			appendTime := time.UnixMilli(partition.LogAppendTimeMs)
+			if appendTime.Before(before) || appendTime.After(after) {
+				t.Errorf("log append time = %v, want between %v and %v", appendTime, before, after)
+			}
+			if stored.TimestampType != protocol.LogAppendTime || !stored.MaxTimestamp.Equal(appendTime) || !stored.RecordTimestamp(0).Equal(appendTime) {
+				t.Errorf("stored batch has timestamp type %d and max timestamp %v, want LogAppendTime at %v", stored.TimestampType, stored.MaxTimestamp, appendTime)
+			}
+			// the timestamp lookups find the records by their append time
+			if offset, _, ok, _ := log.OffsetForTimestamp(createTime.Add(time.Hour)); !ok || offset != 0 {
+				t.Errorf("offset for a timestamp after the create time = %d, %v, want 0", offset, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"opentalaria/protocol"
+	"opentalaria/utils"
+)
+
+type RenewDelegationTokenAPI struct {
+	Request Request
+}
+
+func (d RenewDelegationTokenAPI) Name() string {
+	return "RenewDelegationToken"
+}
+
+func (d RenewDelegationTokenAPI) GetRequest() Request {
+	return d.Request
+}
+
+func (d RenewDelegationTokenAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.RenewDelegationTokenResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (d RenewDelegationTokenAPI) GeneratePayload() ([]byte, error) {
+	resp := GenerateRenewDelegationTokenResponse(d.GetRequest().Header.RequestApiVersion)
+
+	return encodeResponse(d.GetRequest(), resp)
+}
+
+// GenerateRenewDelegationTokenResponse rejects the request, there are no delegation tokens to renew.
+func GenerateRenewDelegationTokenResponse(version int16) *protocol.RenewDelegationTokenResponse {
+	return &protocol.RenewDelegationTokenResponse{
+		Version:   version,
+		ErrorCode: int16(utils.ErrDelegationTokenAuthDisabled),
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"opentalaria/auth"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+)
+
+type SaslAuthenticateAPI struct {
+	Request Request
+}
+
+func (s SaslAuthenticateAPI) Name() string {
+	return "SaslAuthenticate"
+}
+
+func (s SaslAuthenticateAPI) GetRequest() Request {
+	return s.Request
+}
+
+func (s SaslAuthenticateAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.SaslAuthenticateResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (s SaslAuthenticateAPI) GeneratePayload() ([]byte, error) {
+	req := *s.GetRequest().Body.(*protocol.SaslAuthenticateRequest)
+
+	resp := GenerateSaslAuthenticateResponse(s.GetRequest().Header.RequestApiVersion, req, s.GetRequest().Session)
+
+	return encodeResponse(s.GetRequest(), resp)
+}
+
+// ErrorPayload answers the request with a top-level error code.
+func (s SaslAuthenticateAPI) ErrorPayload(kerr utils.KError) ([]byte, error) {
+	resp := protocol.SaslAuthenticateResponse{
+		Version:   s.GetRequest().Header.RequestApiVersion,
+		ErrorCode: int16(kerr),
+		AuthBytes: []byte{},
+	}
+	if !resp.IsValidVersion() {
+		return nil, fmt.Errorf("unknown SaslAuthenticate response version %d", resp.Version)
+	}
+
+	return encodeResponse(s.GetRequest(), &resp)
+}
+
+// GenerateSaslAuthenticateResponse passes the auth bytes of the client to the mechanism picked with SaslHandshake.
+// Sessions don't expire, so the session lifetime is always 0.
+func GenerateSaslAuthenticateResponse(version int16, req protocol.SaslAuthenticateRequest, session *auth.Session) *protocol.SaslAuthenticateResponse {
+	resp := protocol.SaslAuthenticateResponse{
+		Version:   version,
+		ErrorCode: int16(utils.ErrNoError),
+		AuthBytes: []byte{},
+	}
+
+	authBytes, err := session.Authenticate(req.AuthBytes)
+	if err != nil {
+		slog.Info("SASL authentication failed", "err", err)
+
+		msg := err.Error()
+		resp.ErrorCode = int16(utils.ErrorCode(err))
+		resp.ErrorMessage = &msg
+		return &resp
+	}
+
+	resp.AuthBytes = authBytes
+	if session.Authenticated() {
+		slog.Debug("SASL authentication succeeded", "principal", session.Principal().String())
+	}
+
+	return &resp
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"encoding/base64"
+	"opentalaria/auth"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"slices"
+	"testing"
+)
+
+func TestGenerateSaslHandshakeResponse(t *testing.T) {
+	authenticator, err := auth.NewAuthenticator([]string{auth.ScramSha512}, auth.NewMemoryCredentialStore(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session := auth.NewSession(authenticator)
+	resp := GenerateSaslHandshakeResponse(1, protocol.SaslHandshakeRequest{Version: 1, Mechanism: auth.ScramSha256}, session)
+	if resp.ErrorCode != int16(utils.ErrUnsupportedSASLMechanism) || !slices.Equal(resp.Mechanisms, []string{auth.ScramSha512}) {
+		t.Errorf("disabled mechanism = %+v, want UNSUPPORTED_SASL_MECHANISM with the enabled mechanisms", resp)
+	}
+
+	resp = GenerateSaslHandshakeResponse(1, protocol.SaslHandshakeRequest{Version: 1, Mechanism: auth.ScramSha512}, session)
+	if resp.ErrorCode != int16(utils.ErrNoError) {
+		t.Errorf("enabled mechanism = %+v, want no error", resp)
+	}
+
+	resp = GenerateSaslHandshakeResponse(1, protocol.SaslHandshakeRequest{Version: 1, Mechanism: auth.ScramSha512}, session)
+	if resp.ErrorCode != int16(utils.ErrIllegalSASLState) {
+		t.Errorf("second handshake = %+v, want ILLEGAL_SASL_STATE", resp)
+	}
+
+	resp = GenerateSaslHandshakeResponse(1, protocol.SaslHandshakeRequest{Version: 1, Mechanism: auth.ScramSha512}, auth.NewSession(nil))
+	if resp.ErrorCode != int16(utils.ErrUnsupportedSASLMechanism) || len(resp.Mechanisms) != 0 {
+		t.Errorf("handshake without SASL = %+v, want UNSUPPORTED_SASL_MECHANISM without mechanisms", resp)
+	}
+}
+
+func TestGenerateSaslAuthenticateResponse(t *testing.T) {
+	credential, err := auth.NewScramCredential(auth.ScramSha256, "secret", auth.MinScramIterations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := auth.NewMemoryCredentialStore()
+	store.SetScramCredential(auth.ScramSha256, "alice", credential)
+	authenticator, err := auth.NewAuthenticator([]string{auth.ScramSha256}, store, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, version := range []int16{0, 1, 2} {
+		session := auth.NewSession(authenticator)
+
+		resp := GenerateSaslAuthenticateResponse(version, protocol.SaslAuthenticateRequest{Version: version, AuthBytes: []byte("n,,n=alice,r=abc")}, session)
+		if resp.ErrorCode != int16(utils.ErrIllegalSASLState) {
+			t.Errorf("v%d: authenticate before handshake = %+v, want ILLEGAL_SASL_STATE", version, resp)
+		}
+
+		if err := session.Handshake(auth.ScramSha256); err != nil {
+			t.Fatal(err)
+		}
+
+		resp = GenerateSaslAuthenticateResponse(version, protocol.SaslAuthenticateRequest{Version: version, AuthBytes: []byte("n,,n=alice,r=abc")}, session)
+		if resp.ErrorCode != int16(utils.ErrNoError) || len(resp.AuthBytes) == 0 {
+			t.Errorf("v%d: client first message = %+v, want the server first message", version, resp)
+		}
+
+		// encode and decode the response, to make sure it survives the wire format of this version
+		respBytes, err := protocol.Encode(resp)
+		if err != nil {
+			t.Fatalf("v%d: error encoding response: %v", version, err)
+		}
+		decoded := protocol.SaslAuthenticateResponse{}
+		if _, err := protocol.VersionedDecode(respBytes, &decoded, version); err != nil {
+			t.Fatalf("v%d: error decoding response: %v", version, err)
+		}
+		if string(decoded.AuthBytes) != string(resp.AuthBytes) {
+			t.Errorf("v%d: decoded auth bytes = %q, want %q", version, decoded.AuthBytes, resp.AuthBytes)
+		}
+
+		resp = GenerateSaslAuthenticateResponse(version, protocol.SaslAuthenticateRequest{Version: version, AuthBytes: []byte("c=biws,r=abc,p=AAAA")}, session)
+		if resp.ErrorCode != int16(utils.ErrSASLAuthenticationFailed) || resp.ErrorMessage == nil {
+			t.Errorf("v%d: wrong client final message = %+v, want SASL_AUTHENTICATION_FAILED with a message", version, resp)
+		}
+		if session.Authenticated() {
+			t.Errorf("v%d: session is authenticated after a failed exchange", version)
+		}
+	}
+}
+
+func TestRequest_Principal(t *testing.T) {
+	if got := (Request{}).Principal().String(); got != "User:ANONYMOUS" {
+		t.Errorf("principal without session = %s, want User:ANONYMOUS", got)
+	}
+
+	authenticator, err := auth.NewAuthenticator([]string{auth.OAuthBearer}, nil, auth.NewUnsecuredTokenValidator())
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := Request{Session: auth.NewSession(authenticator)}
+	if got := req.Principal().String(); got != "User:ANONYMOUS" {
+		t.Errorf("principal before authenticating = %s, want User:ANONYMOUS", got)
+	}
+
+	if err := req.Session.Handshake(auth.OAuthBearer); err != nil {
+		t.Fatal(err)
+	}
+	enc := base64.RawURLEncoding
+	token := enc.EncodeToString([]byte(`{"alg":"none"}`)) + "." + enc.EncodeToString([]byte(`{"sub":"alice","exp":4102444800}`)) + "."
+	resp := GenerateSaslAuthenticateResponse(2, protocol.SaslAuthenticateRequest{Version: 2, AuthBytes: []byte("n,,\x01auth=Bearer " + token + "\x01\x01")}, req.Session)
+	if resp.ErrorCode != int16(utils.ErrNoError) {
+		t.Fatalf("authenticate = %+v, want no error", resp)
+	}
+	if got := req.Principal().String(); got != "User:alice" {
+		t.Errorf("principal after authenticating = %s, want User:alice", got)
+	}
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"fmt"
+	"opentalaria/auth"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+)
+
+type SaslHandshakeAPI struct {
+	Request Request
+}
+
+func (s SaslHandshakeAPI) Name() string {
+	return "SaslHandshake"
+}
+
+func (s SaslHandshakeAPI) GetRequest() Request {
+	return s.Request
+}
+
+func (s SaslHandshakeAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.SaslHandshakeResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (s SaslHandshakeAPI) GeneratePayload() ([]byte, error) {
+	req := *s.GetRequest().Body.(*protocol.SaslHandshakeRequest)
+
+	resp := GenerateSaslHandshakeResponse(s.GetRequest().Header.RequestApiVersion, req, s.GetRequest().Session)
+
+	return encodeResponse(s.GetRequest(), resp)
+}
+
+// ErrorPayload answers the request with a top-level error code.
+func (s SaslHandshakeAPI) ErrorPayload(kerr utils.KError) ([]byte, error) {
+	resp := protocol.SaslHandshakeResponse{
+		Version:    s.GetRequest().Header.RequestApiVersion,
+		ErrorCode:  int16(kerr),
+		Mechanisms: []string{},
+	}
+	if !resp.IsValidVersion() {
+		return nil, fmt.Errorf("unknown SaslHandshake response version %d", resp.Version)
+	}
+
+	return encodeResponse(s.GetRequest(), &resp)
+}
+
+// GenerateSaslHandshakeResponse starts the exchange of the mechanism picked by the client.
+// The enabled mechanisms are always returned, so a client which picked a disabled one can tell why it was rejected.
+func GenerateSaslHandshakeResponse(version int16, req protocol.SaslHandshakeRequest, session *auth.Session) *protocol.SaslHandshakeResponse {
+	resp := protocol.SaslHandshakeResponse{
+		Version:    version,
+		ErrorCode:  int16(utils.ErrNoError),
+		Mechanisms: session.Mechanisms(),
+	}
+
+	if err := session.Handshake(req.Mechanism); err != nil {
+		resp.ErrorCode = int16(utils.ErrorCode(err))
+	}
+
+	return &resp
+}
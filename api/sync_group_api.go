@@ -0,0 +1,71 @@
+package api
+
+import (
+	"fmt"
+	"opentalaria/coordinator"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+)
+
+type SyncGroupAPI struct {
+	Request Request
+}
+
+func (s SyncGroupAPI) Name() string {
+	return "SyncGroup"
+}
+
+func (s SyncGroupAPI) GetRequest() Request {
+	return s.Request
+}
+
+func (s SyncGroupAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.SyncGroupResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+// GeneratePayload blocks until the leader has sent the assignments of the generation.
+func (s SyncGroupAPI) GeneratePayload() ([]byte, error) {
+	req := *s.GetRequest().Body.(*protocol.SyncGroupRequest)
+
+	resp := GenerateSyncGroupResponse(s.GetRequest().Header.RequestApiVersion, req, s.GetRequest().Groups)
+
+	return encodeResponse(s.GetRequest(), resp)
+}
+
+// ErrorPayload answers the request with a top-level error code.
+func (s SyncGroupAPI) ErrorPayload(kerr utils.KError) ([]byte, error) {
+	resp := protocol.SyncGroupResponse{Version: s.GetRequest().Header.RequestApiVersion, ErrorCode: int16(kerr), Assignment: []byte{}}
+	if !resp.IsValidVersion() {
+		return nil, fmt.Errorf("unknown SyncGroup response version %d", resp.Version)
+	}
+
+	return encodeResponse(s.GetRequest(), &resp)
+}
+
+// GenerateSyncGroupResponse returns the assignment of the member. The assignments computed by the leader are passed through
+// to the members as they are, since they are opaque to the coordinator.
+func GenerateSyncGroupResponse(version int16, req protocol.SyncGroupRequest, groups *coordinator.GroupCoordinator) *protocol.SyncGroupResponse {
+	assignments := map[string][]byte{}
+	for _, assignment := range req.Assignments {
+		assignments[assignment.MemberID] = assignment.Assignment
+	}
+
+	result := groups.SyncGroup(req.GroupID, req.GenerationID, req.MemberID, assignments)
+
+	resp := protocol.SyncGroupResponse{
+		Version:    version,
+		ErrorCode:  int16(utils.ErrorCode(result.Err)),
+		Assignment: result.Assignment,
+	}
+	if resp.Assignment == nil {
+		resp.Assignment = []byte{}
+	}
+	if result.ProtocolType != "" {
+		resp.ProtocolType = &result.ProtocolType
+	}
+	if result.ProtocolName != "" {
+		resp.ProtocolName = &result.ProtocolName
+	}
+
+	return &resp
+}
@@ -0,0 +1,307 @@
+package api
+
+import (
+	"fmt"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"reflect"
+)
+
+// UnsupportedAPI answers the requests of APIs the protocol defines, but the broker has no handler for.
+// They are not listed in the ApiVersions response, but clients which don't check it get an UNSUPPORTED_VERSION error
+// instead of a closed connection.
+type UnsupportedAPI struct {
+	Request Request
+}
+
+func (u UnsupportedAPI) Name() string {
+	return protocol.ApiKeyName(u.GetRequest().Header.RequestApiKey)
+}
+
+func (u UnsupportedAPI) GetRequest() Request {
+	return u.Request
+}
+
+func (u UnsupportedAPI) GetHeaderVersion(requestVersion int16) int16 {
+	resp, err := protocol.NewResponse(u.GetRequest().Header.RequestApiKey, requestVersion)
+	if err != nil {
+		return 0
+	}
+
+	return resp.GetHeaderVersion()
+}
+
+func (u UnsupportedAPI) GeneratePayload() ([]byte, error) {
+	return u.ErrorPayload(utils.ErrUnsupportedVersion)
+}
+
+// ErrorPayload answers the request with the top-level error code of the response, or, for the responses listed in
+// resourceErrors, with the error code in the result of every resource of the request.
+func (u UnsupportedAPI) ErrorPayload(kerr utils.KError) ([]byte, error) {
+	version := u.GetRequest().Header.RequestApiVersion
+	var resp protocol.Response
+	if resourceError, ok := resourceErrors[u.GetRequest().Header.RequestApiKey]; ok {
+		resp = resourceError(version, u.GetRequest().Body, int16(kerr))
+	} else {
+		var err error
+		resp, err = protocol.NewResponse(u.GetRequest().Header.RequestApiKey, version)
+		if err != nil {
+			return nil, err
+		}
+		// all versions of the other responses start with a top-level error code
+		reflect.ValueOf(resp).Elem().FieldByName("ErrorCode").SetInt(int64(kerr))
+		if telemetry, ok := resp.(*protocol.GetTelemetrySubscriptionsResponse); ok {
+			// the accepted compression types can't be null
+			telemetry.AcceptedCompressionTypes = []int8{}
+		}
+	}
+	if !resp.IsValidVersion() {
+		return nil, fmt.Errorf("unknown %s response version %d", u.Name(), resp.GetVersion())
+	}
+
+	return encodeResponse(u.GetRequest(), resp)
+}
+
+// resourceErrors maps the APIs whose responses have no top-level error code, in all or some of their versions,
+// to functions returning a response with the error code in the result of every resource of the request.
+// The body may be partially decoded, if the request is malformed.
+var resourceErrors = map[int16]func(version int16, body protocol.Request, errorCode int16) protocol.Response{
+	(&protocol.AddPartitionsToTxnRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		req := body.(*protocol.AddPartitionsToTxnRequest)
+		// the top-level error code exists from v4, where several transactions can be added at once
+		resp := protocol.AddPartitionsToTxnResponse{Version: version, ErrorCode: errorCode}
+		for _, txn := range req.Transactions {
+			resp.ResultsByTransaction = append(resp.ResultsByTransaction, protocol.AddPartitionsToTxnResult{
+				TransactionalID: txn.TransactionalID,
+				TopicResults:    addPartitionsToTxnErrors(txn.Topics, errorCode),
+			})
+		}
+		resp.ResultsByTopicV3AndBelow = addPartitionsToTxnErrors(req.V3AndBelowTopics, errorCode)
+		return &resp
+	},
+	(&protocol.AlterClientQuotasRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		resp := protocol.AlterClientQuotasResponse{Version: version}
+		for _, entry := range body.(*protocol.AlterClientQuotasRequest).Entries {
+			result := protocol.EntryData_AlterClientQuotasResponse{ErrorCode: errorCode}
+			for _, entity := range entry.Entity {
+				result.Entity = append(result.Entity, protocol.EntityData_AlterClientQuotasResponse{EntityType: entity.EntityType, EntityName: entity.EntityName})
+			}
+			resp.Entries = append(resp.Entries, result)
+		}
+		return &resp
+	},
+	(&protocol.AlterReplicaLogDirsRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		resp := protocol.AlterReplicaLogDirsResponse{Version: version}
+		for _, dir := range body.(*protocol.AlterReplicaLogDirsRequest).Dirs {
+			for _, topic := range dir.Topics {
+				result := protocol.AlterReplicaLogDirTopicResult{TopicName: topic.Name}
+				for _, partition := range topic.Partitions {
+					result.Partitions = append(result.Partitions, protocol.AlterReplicaLogDirPartitionResult{PartitionIndex: partition, ErrorCode: errorCode})
+				}
+				resp.Results = append(resp.Results, result)
+			}
+		}
+		return &resp
+	},
+	(&protocol.AlterUserScramCredentialsRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		req := body.(*protocol.AlterUserScramCredentialsRequest)
+		resp := protocol.AlterUserScramCredentialsResponse{Version: version}
+		for _, deletion := range req.Deletions {
+			resp.Results = append(resp.Results, protocol.AlterUserScramCredentialsResult{User: deletion.Name, ErrorCode: errorCode})
+		}
+		for _, upsertion := range req.Upsertions {
+			resp.Results = append(resp.Results, protocol.AlterUserScramCredentialsResult{User: upsertion.Name, ErrorCode: errorCode})
+		}
+		return &resp
+	},
+	(&protocol.ConsumerGroupDescribeRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		resp := protocol.ConsumerGroupDescribeResponse{Version: version}
+		for _, groupID := range body.(*protocol.ConsumerGroupDescribeRequest).GroupIds {
+			resp.Groups = append(resp.Groups, protocol.DescribedGroup_ConsumerGroupDescribeResponse{GroupID: groupID, ErrorCode: errorCode})
+		}
+		return &resp
+	},
+	(&protocol.CreatePartitionsRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		resp := protocol.CreatePartitionsResponse{Version: version}
+		for _, topic := range body.(*protocol.CreatePartitionsRequest).Topics {
+			resp.Results = append(resp.Results, protocol.CreatePartitionsTopicResult{Name: topic.Name, ErrorCode: errorCode})
+		}
+		return &resp
+	},
+	(&protocol.DeleteGroupsRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		resp := protocol.DeleteGroupsResponse{Version: version}
+		for _, groupID := range body.(*protocol.DeleteGroupsRequest).GroupsNames {
+			resp.Results = append(resp.Results, protocol.DeletableGroupResult{GroupID: groupID, ErrorCode: errorCode})
+		}
+		return &resp
+	},
+	(&protocol.DeleteRecordsRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		resp := protocol.DeleteRecordsResponse{Version: version}
+		for _, topic := range body.(*protocol.DeleteRecordsRequest).Topics {
+			result := protocol.DeleteRecordsTopicResult{Name: topic.Name}
+			for _, partition := range topic.Partitions {
+				result.Partitions = append(result.Partitions, protocol.DeleteRecordsPartitionResult{PartitionIndex: partition.PartitionIndex, LowWatermark: -1, ErrorCode: errorCode})
+			}
+			resp.Topics = append(resp.Topics, result)
+		}
+		return &resp
+	},
+	(&protocol.DeleteShareGroupStateRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		resp := protocol.DeleteShareGroupStateResponse{Version: version}
+		for _, topic := range body.(*protocol.DeleteShareGroupStateRequest).Topics {
+			result := protocol.DeleteStateResult{TopicID: topic.TopicID}
+			for _, partition := range topic.Partitions {
+				result.Partitions = append(result.Partitions, protocol.PartitionResult_DeleteShareGroupStateResponse{Partition: partition.Partition, ErrorCode: errorCode})
+			}
+			resp.Results = append(resp.Results, result)
+		}
+		return &resp
+	},
+	(&protocol.DescribeGroupsRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		resp := protocol.DescribeGroupsResponse{Version: version}
+		for _, groupID := range body.(*protocol.DescribeGroupsRequest).Groups {
+			resp.Groups = append(resp.Groups, protocol.DescribedGroup_DescribeGroupsResponse{GroupID: groupID, ErrorCode: errorCode})
+		}
+		return &resp
+	},
+	(&protocol.DescribeShareGroupOffsetsRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		resp := protocol.DescribeShareGroupOffsetsResponse{Version: version}
+		for _, topic := range body.(*protocol.DescribeShareGroupOffsetsRequest).Topics {
+			result := protocol.DescribeShareGroupOffsetsResponseTopic{TopicName: topic.TopicName}
+			for _, partition := range topic.Partitions {
+				result.Partitions = append(result.Partitions, protocol.DescribeShareGroupOffsetsResponsePartition{PartitionIndex: partition, StartOffset: -1, ErrorCode: errorCode})
+			}
+			resp.Responses = append(resp.Responses, result)
+		}
+		return &resp
+	},
+	(&protocol.DescribeTopicPartitionsRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		resp := protocol.DescribeTopicPartitionsResponse{Version: version}
+		for _, topic := range body.(*protocol.DescribeTopicPartitionsRequest).Topics {
+			name := topic.Name
+			resp.Topics = append(resp.Topics, protocol.DescribeTopicPartitionsResponseTopic{Name: &name, ErrorCode: errorCode})
+		}
+		return &resp
+	},
+	(&protocol.ElectLeadersRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		// the top-level error code exists from v1
+		resp := protocol.ElectLeadersResponse{Version: version, ErrorCode: errorCode}
+		for _, topic := range body.(*protocol.ElectLeadersRequest).TopicPartitions {
+			result := protocol.ReplicaElectionResult{Topic: topic.Topic}
+			for _, partition := range topic.Partitions {
+				result.PartitionResult = append(result.PartitionResult, protocol.PartitionResult_ElectLeadersResponse{PartitionID: partition, ErrorCode: errorCode})
+			}
+			resp.ReplicaElectionResults = append(resp.ReplicaElectionResults, result)
+		}
+		return &resp
+	},
+	(&protocol.InitializeShareGroupStateRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		resp := protocol.InitializeShareGroupStateResponse{Version: version}
+		for _, topic := range body.(*protocol.InitializeShareGroupStateRequest).Topics {
+			result := protocol.InitializeStateResult{TopicID: topic.TopicID}
+			for _, partition := range topic.Partitions {
+				result.Partitions = append(result.Partitions, protocol.PartitionResult_InitializeShareGroupStateResponse{Partition: partition.Partition, ErrorCode: errorCode})
+			}
+			resp.Results = append(resp.Results, result)
+		}
+		return &resp
+	},
+	(&protocol.OffsetForLeaderEpochRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		resp := protocol.OffsetForLeaderEpochResponse{Version: version}
+		for _, topic := range body.(*protocol.OffsetForLeaderEpochRequest).Topics {
+			result := protocol.OffsetForLeaderTopicResult{Topic: topic.Topic}
+			for _, partition := range topic.Partitions {
+				result.Partitions = append(result.Partitions, protocol.EpochEndOffset_OffsetForLeaderEpochResponse{Partition: partition.Partition, LeaderEpoch: -1, EndOffset: -1, ErrorCode: errorCode})
+			}
+			resp.Topics = append(resp.Topics, result)
+		}
+		return &resp
+	},
+	(&protocol.ReadShareGroupStateRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		resp := protocol.ReadShareGroupStateResponse{Version: version}
+		for _, topic := range body.(*protocol.ReadShareGroupStateRequest).Topics {
+			result := protocol.ReadStateResult{TopicID: topic.TopicID}
+			for _, partition := range topic.Partitions {
+				result.Partitions = append(result.Partitions, protocol.PartitionResult_ReadShareGroupStateResponse{Partition: partition.Partition, StateEpoch: -1, StartOffset: -1, ErrorCode: errorCode})
+			}
+			resp.Results = append(resp.Results, result)
+		}
+		return &resp
+	},
+	(&protocol.ReadShareGroupStateSummaryRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		resp := protocol.ReadShareGroupStateSummaryResponse{Version: version}
+		for _, topic := range body.(*protocol.ReadShareGroupStateSummaryRequest).Topics {
+			result := protocol.ReadStateSummaryResult{TopicID: topic.TopicID}
+			for _, partition := range topic.Partitions {
+				result.Partitions = append(result.Partitions, protocol.PartitionResult_ReadShareGroupStateSummaryResponse{Partition: partition.Partition, StateEpoch: -1, StartOffset: -1, ErrorCode: errorCode})
+			}
+			resp.Results = append(resp.Results, result)
+		}
+		return &resp
+	},
+	(&protocol.ShareGroupDescribeRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		resp := protocol.ShareGroupDescribeResponse{Version: version}
+		for _, groupID := range body.(*protocol.ShareGroupDescribeRequest).GroupIds {
+			resp.Groups = append(resp.Groups, protocol.DescribedGroup_ShareGroupDescribeResponse{GroupID: groupID, ErrorCode: errorCode})
+		}
+		return &resp
+	},
+	(&protocol.StreamsGroupDescribeRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		resp := protocol.StreamsGroupDescribeResponse{Version: version}
+		for _, groupID := range body.(*protocol.StreamsGroupDescribeRequest).GroupIds {
+			resp.Groups = append(resp.Groups, protocol.DescribedGroup_StreamsGroupDescribeResponse{GroupID: groupID, ErrorCode: errorCode})
+		}
+		return &resp
+	},
+	(&protocol.TxnOffsetCommitRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		resp := protocol.TxnOffsetCommitResponse{Version: version}
+		for _, topic := range body.(*protocol.TxnOffsetCommitRequest).Topics {
+			result := protocol.TxnOffsetCommitResponseTopic{Name: topic.Name}
+			for _, partition := range topic.Partitions {
+				result.Partitions = append(result.Partitions, protocol.TxnOffsetCommitResponsePartition{PartitionIndex: partition.PartitionIndex, ErrorCode: errorCode})
+			}
+			resp.Topics = append(resp.Topics, result)
+		}
+		return &resp
+	},
+	(&protocol.WriteShareGroupStateRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		resp := protocol.WriteShareGroupStateResponse{Version: version}
+		for _, topic := range body.(*protocol.WriteShareGroupStateRequest).Topics {
+			result := protocol.WriteStateResult{TopicID: topic.TopicID}
+			for _, partition := range topic.Partitions {
+				result.Partitions = append(result.Partitions, protocol.PartitionResult_WriteShareGroupStateResponse{Partition: partition.Partition, ErrorCode: errorCode})
+			}
+			resp.Results = append(resp.Results, result)
+		}
+		return &resp
+	},
+	(&protocol.WriteTxnMarkersRequest{}).GetKey(): func(version int16, body protocol.Request, errorCode int16) protocol.Response {
+		resp := protocol.WriteTxnMarkersResponse{Version: version}
+		for _, marker := range body.(*protocol.WriteTxnMarkersRequest).Markers {
+			result := protocol.WritableTxnMarkerResult{ProducerID: marker.ProducerID}
+			for _, topic := range marker.Topics {
+				topicResult := protocol.WritableTxnMarkerTopicResult{Name: topic.Name}
+				for _, partition := range topic.PartitionIndexes {
+					topicResult.Partitions = append(topicResult.Partitions, protocol.WritableTxnMarkerPartitionResult{PartitionIndex: partition, ErrorCode: errorCode})
+				}
+				result.Topics = append(result.Topics, topicResult)
+			}
+			resp.Markers = append(resp.Markers, result)
+		}
+		return &resp
+	},
+}
+
+// addPartitionsToTxnErrors returns the results of the partitions of the topics of an AddPartitionsToTxn request, with the error code.
+func addPartitionsToTxnErrors(topics []protocol.AddPartitionsToTxnTopic_AddPartitionsToTxnRequest, errorCode int16) []protocol.AddPartitionsToTxnTopicResult_AddPartitionsToTxnResponse {
+	var results []protocol.AddPartitionsToTxnTopicResult_AddPartitionsToTxnResponse
+	for _, topic := range topics {
+		result := protocol.AddPartitionsToTxnTopicResult_AddPartitionsToTxnResponse{Name: topic.Name}
+		for _, partition := range topic.Partitions {
+			result.ResultsByPartition = append(result.ResultsByPartition, protocol.AddPartitionsToTxnPartitionResult{PartitionIndex: partition, PartitionErrorCode: errorCode})
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
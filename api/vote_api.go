@@ -0,0 +1,93 @@
+package api
+
+import (
+	"fmt"
+	"opentalaria/metadata"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+)
+
+type VoteAPI struct {
+	Request Request
+}
+
+func (v VoteAPI) Name() string {
+	return "Vote"
+}
+
+func (v VoteAPI) GetRequest() Request {
+	return v.Request
+}
+
+func (v VoteAPI) GetHeaderVersion(requestVersion int16) int16 {
+	return (&protocol.VoteResponse{Version: requestVersion}).GetHeaderVersion()
+}
+
+func (v VoteAPI) GeneratePayload() ([]byte, error) {
+	req := *v.GetRequest().Body.(*protocol.VoteRequest)
+
+	resp := GenerateVoteResponse(v.GetRequest().Header.RequestApiVersion, req, v.GetRequest().Config.Cluster.ClusterID, v.GetRequest().Raft)
+
+	return encodeResponse(v.GetRequest(), resp)
+}
+
+// ErrorPayload answers the request with a top-level error code.
+func (v VoteAPI) ErrorPayload(kerr utils.KError) ([]byte, error) {
+	resp := protocol.VoteResponse{Version: v.GetRequest().Header.RequestApiVersion, ErrorCode: int16(kerr)}
+	if !resp.IsValidVersion() {
+		return nil, fmt.Errorf("unknown Vote response version %d", resp.Version)
+	}
+
+	return encodeResponse(v.GetRequest(), &resp)
+}
+
+// GenerateVoteResponse answers the vote requests of candidates for the leadership of the metadata partition.
+// The only partition with a quorum is the single partition of the __cluster_metadata topic, other partitions are unknown.
+func GenerateVoteResponse(version int16, req protocol.VoteRequest, clusterID string, raft *metadata.RaftState) *protocol.VoteResponse {
+	resp := protocol.VoteResponse{
+		Version:   version,
+		ErrorCode: int16(utils.ErrNoError),
+	}
+
+	if req.ClusterID != nil && *req.ClusterID != clusterID {
+		resp.ErrorCode = int16(utils.ErrInconsistentClusterID)
+		return &resp
+	}
+
+	for _, voteTopic := range req.Topics {
+		topicResponse := protocol.TopicData_VoteResponse{
+			Version:   version,
+			TopicName: voteTopic.TopicName,
+		}
+
+		for _, votePartition := range voteTopic.Partitions {
+			partitionResponse := protocol.PartitionData_VoteResponse{
+				Version:        version,
+				PartitionIndex: votePartition.PartitionIndex,
+				ErrorCode:      int16(utils.ErrNoError),
+				LeaderID:       -1,
+				LeaderEpoch:    -1,
+			}
+
+			if voteTopic.TopicName != metadata.ClusterMetadataTopic || votePartition.PartitionIndex != 0 {
+				partitionResponse.ErrorCode = int16(utils.ErrUnknownTopicOrPartition)
+				topicResponse.Partitions = append(topicResponse.Partitions, partitionResponse)
+				continue
+			}
+
+			partitionResponse.VoteGranted, partitionResponse.LeaderID, partitionResponse.LeaderEpoch = raft.Vote(
+				votePartition.ReplicaID,
+				votePartition.ReplicaEpoch,
+				votePartition.LastOffsetEpoch,
+				votePartition.LastOffset,
+				votePartition.PreVote,
+			)
+
+			topicResponse.Partitions = append(topicResponse.Partitions, partitionResponse)
+		}
+
+		resp.Topics = append(resp.Topics, topicResponse)
+	}
+
+	return &resp
+}
@@ -0,0 +1,295 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// PatternType defines how the resource name of an ACL matches the names of resources.
+// The values are the pattern type codes of the Kafka protocol.
+type PatternType int8
+
+const (
+	PatternUnknown PatternType = 0
+	// PatternAny only occurs in filters, it matches ACLs of any pattern type
+	PatternAny PatternType = 1
+	// PatternMatch only occurs in filters, it matches the ACLs which apply to the resource named in the filter
+	PatternMatch    PatternType = 2
+	PatternLiteral  PatternType = 3
+	PatternPrefixed PatternType = 4
+)
+
+// Permission defines whether an ACL allows or denies its operation. The values are the permission type codes of the Kafka protocol.
+type Permission int8
+
+const (
+	PermissionUnknown Permission = 0
+	// PermissionAny only occurs in filters, it matches ACLs of any permission
+	PermissionAny   Permission = 1
+	PermissionDeny  Permission = 2
+	PermissionAllow Permission = 3
+)
+
+func (p Permission) String() string {
+	switch p {
+	case PermissionAny:
+		return "ANY"
+	case PermissionDeny:
+		return "DENY"
+	case PermissionAllow:
+		return "ALLOW"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+const (
+	// WildcardResource is the name of literal ACLs which apply to all resources of their type.
+	WildcardResource = "*"
+	// WildcardPrincipal is the principal of ACLs which apply to all users.
+	WildcardPrincipal = "User:*"
+	// WildcardHost is the host of ACLs which apply to clients on all hosts.
+	WildcardHost = "*"
+)
+
+// ACL allows or denies a principal an operation on the resources matching its resource pattern.
+type ACL struct {
+	ResourceType ResourceType
+	ResourceName string
+	PatternType  PatternType
+	// Principal is the principal in its string form, e.g. User:alice
+	Principal  string
+	Host       string
+	Operation  Operation
+	Permission Permission
+}
+
+// Validate checks that the ACL names a concrete resource pattern, operation and permission, as opposed to a filter.
+func (a ACL) Validate() error {
+	switch a.ResourceType {
+	case ResourceTopic, ResourceGroup, ResourceCluster, ResourceTransactionalID, ResourceDelegationToken:
+	default:
+		return fmt.Errorf("invalid resource type %s", a.ResourceType)
+	}
+	if a.PatternType != PatternLiteral && a.PatternType != PatternPrefixed {
+		return fmt.Errorf("invalid resource pattern type %d", a.PatternType)
+	}
+	if a.ResourceName == "" {
+		return errors.New("resource name must not be empty")
+	}
+	if a.ResourceType == ResourceCluster && (a.PatternType != PatternLiteral || a.ResourceName != ClusterResource.Name) {
+		return fmt.Errorf("the cluster resource must be the literal %s", ClusterResource.Name)
+	}
+	if principalType, name, ok := strings.Cut(a.Principal, ":"); !ok || principalType == "" || name == "" {
+		return fmt.Errorf("invalid principal %q, expected the form User:name", a.Principal)
+	}
+	if a.Host == "" {
+		return errors.New("host must not be empty")
+	}
+	if a.Operation == OperationUnknown || a.Operation == OperationAny {
+		return fmt.Errorf("invalid operation %s", a.Operation)
+	}
+	if a.Permission != PermissionAllow && a.Permission != PermissionDeny {
+		return fmt.Errorf("invalid permission type %d", a.Permission)
+	}
+
+	return nil
+}
+
+// ACLFilter selects ACLs to describe or delete. Nil names, principals and hosts match any value.
+type ACLFilter struct {
+	ResourceType ResourceType
+	ResourceName *string
+	PatternType  PatternType
+	Principal    *string
+	Host         *string
+	Operation    Operation
+	Permission   Permission
+}
+
+// Validate checks that the filter can match ACLs, unknown types can't.
+func (f ACLFilter) Validate() error {
+	if f.ResourceType == ResourceUnknown {
+		return errors.New("invalid resource type filter")
+	}
+	if f.PatternType == PatternUnknown {
+		return errors.New("invalid resource pattern type filter")
+	}
+	if f.Operation == OperationUnknown {
+		return errors.New("invalid operation filter")
+	}
+	if f.Permission == PermissionUnknown {
+		return errors.New("invalid permission type filter")
+	}
+
+	return nil
+}
+
+// Matches reports whether the ACL is selected by the filter.
+func (f ACLFilter) Matches(acl ACL) bool {
+	if f.ResourceType != ResourceAny && f.ResourceType != acl.ResourceType {
+		return false
+	}
+	if !f.matchesPattern(acl) {
+		return false
+	}
+	if f.Principal != nil && *f.Principal != acl.Principal {
+		return false
+	}
+	if f.Host != nil && *f.Host != acl.Host {
+		return false
+	}
+	if f.Operation != OperationAny && f.Operation != acl.Operation {
+		return false
+	}
+
+	return f.Permission == PermissionAny || f.Permission == acl.Permission
+}
+
+func (f ACLFilter) matchesPattern(acl ACL) bool {
+	switch f.PatternType {
+	case PatternAny:
+		return f.ResourceName == nil || *f.ResourceName == acl.ResourceName
+	case PatternMatch:
+		return f.ResourceName == nil || patternMatches(acl, *f.ResourceName)
+	default:
+		return f.PatternType == acl.PatternType && (f.ResourceName == nil || *f.ResourceName == acl.ResourceName)
+	}
+}
+
+// patternMatches reports whether the resource pattern of the ACL applies to the resource with the name.
+func patternMatches(acl ACL, name string) bool {
+	switch acl.PatternType {
+	case PatternLiteral:
+		return acl.ResourceName == name || acl.ResourceName == WildcardResource
+	case PatternPrefixed:
+		return strings.HasPrefix(name, acl.ResourceName)
+	default:
+		return false
+	}
+}
+
+// ACLStore keeps the ACLs of the broker in memory, so they are lost when the broker restarts.
+// It is safe for concurrent use, ACLs are changed by admin clients while other clients are authorized.
+type ACLStore struct {
+	mu   sync.RWMutex
+	acls []ACL
+}
+
+// NewACLStore returns a store without ACLs.
+func NewACLStore() *ACLStore {
+	return &ACLStore{}
+}
+
+// Create adds the ACL. Creating an ACL which already exists is not an error, like in Kafka.
+func (s *ACLStore) Create(acl ACL) error {
+	if err := acl.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !slices.Contains(s.acls, acl) {
+		s.acls = append(s.acls, acl)
+	}
+
+	return nil
+}
+
+// Delete removes the ACLs matching the filter and returns them.
+func (s *ACLStore) Delete(filter ACLFilter) []ACL {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted []ACL
+	s.acls = slices.DeleteFunc(s.acls, func(acl ACL) bool {
+		if filter.Matches(acl) {
+			deleted = append(deleted, acl)
+			return true
+		}
+		return false
+	})
+
+	return deleted
+}
+
+// Describe returns the ACLs matching the filter, in the order they were created.
+func (s *ACLStore) Describe(filter ACLFilter) []ACL {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var acls []ACL
+	for _, acl := range s.acls {
+		if filter.Matches(acl) {
+			acls = append(acls, acl)
+		}
+	}
+
+	return acls
+}
+
+// resourceACLs returns the ACLs whose resource pattern applies to the resource.
+func (s *ACLStore) resourceACLs(resource Resource) []ACL {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var acls []ACL
+	for _, acl := range s.acls {
+		if acl.ResourceType == resource.Type && patternMatches(acl, resource.Name) {
+			acls = append(acls, acl)
+		}
+	}
+
+	return acls
+}
+
+// impliedOperations are the operations which are allowed by an ACL allowing another operation, like in Kafka.
+var impliedOperations = map[Operation][]Operation{
+	OperationDescribe:        {OperationRead, OperationWrite, OperationDelete, OperationAlter},
+	OperationDescribeConfigs: {OperationAlterConfigs},
+}
+
+// ACLAuthorizer is the Authorizer which checks operations against the ACLs in a store.
+// An operation is denied if a deny ACL matches it, and allowed if an allow ACL matches it.
+// Operations on resources without any ACLs are allowed if allowIfNoACL is set, which keeps a broker without ACLs open.
+// The Authorizer interface has no client host, so only the allow ACLs of all hosts apply, while deny ACLs apply to every host.
+type ACLAuthorizer struct {
+	acls         *ACLStore
+	allowIfNoACL bool
+}
+
+// NewACLAuthorizer returns an authorizer which checks operations against the ACLs of the store.
+func NewACLAuthorizer(acls *ACLStore, allowIfNoACL bool) *ACLAuthorizer {
+	return &ACLAuthorizer{acls: acls, allowIfNoACL: allowIfNoACL}
+}
+
+func (a *ACLAuthorizer) Authorize(principal Principal, operation Operation, resource Resource) bool {
+	acls := a.acls.resourceACLs(resource)
+	if len(acls) == 0 {
+		return a.allowIfNoACL
+	}
+
+	name := principal.String()
+	principalMatches := func(acl ACL) bool {
+		return acl.Principal == name || acl.Principal == WildcardPrincipal
+	}
+
+	for _, acl := range acls {
+		if acl.Permission == PermissionDeny && principalMatches(acl) && (acl.Operation == operation || acl.Operation == OperationAll) {
+			return false
+		}
+	}
+
+	allowed := append([]Operation{operation, OperationAll}, impliedOperations[operation]...)
+	for _, acl := range acls {
+		if acl.Permission == PermissionAllow && principalMatches(acl) && acl.Host == WildcardHost && slices.Contains(allowed, acl.Operation) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,90 @@
+package auth
+
+import "testing"
+
+func TestACLAuthorizer(t *testing.T) {
+	store := NewACLStore()
+	acls := []ACL{
+		{ResourceType: ResourceTopic, ResourceName: "orders", PatternType: PatternLiteral, Principal: "User:alice", Host: WildcardHost, Operation: OperationWrite, Permission: PermissionAllow},
+		{ResourceType: ResourceTopic, ResourceName: "logs-", PatternType: PatternPrefixed, Principal: WildcardPrincipal, Host: WildcardHost, Operation: OperationRead, Permission: PermissionAllow},
+		{ResourceType: ResourceTopic, ResourceName: "logs-secret", PatternType: PatternLiteral, Principal: "User:bob", Host: WildcardHost, Operation: OperationAll, Permission: PermissionDeny},
+		{ResourceType: ResourceGroup, ResourceName: "*", PatternType: PatternLiteral, Principal: "User:alice", Host: "10.0.0.1", Operation: OperationRead, Permission: PermissionAllow},
+	}
+	for _, acl := range acls {
+		if err := store.Create(acl); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	alice, bob := NewUserPrincipal("alice"), NewUserPrincipal("bob")
+	topic := func(name string) Resource { return Resource{Type: ResourceTopic, Name: name} }
+	tests := []struct {
+		name      string
+		principal Principal
+		operation Operation
+		resource  Resource
+		want      bool
+	}{
+		{name: "literal allow", principal: alice, operation: OperationWrite, resource: topic("orders"), want: true},
+		{name: "other principal", principal: bob, operation: OperationWrite, resource: topic("orders"), want: false},
+		{name: "other operation", principal: alice, operation: OperationRead, resource: topic("orders"), want: false},
+		{name: "describe implied by write", principal: alice, operation: OperationDescribe, resource: topic("orders"), want: true},
+		{name: "prefixed allow for all users", principal: bob, operation: OperationRead, resource: topic("logs-app"), want: true},
+		{name: "deny wins over allow", principal: bob, operation: OperationRead, resource: topic("logs-secret"), want: false},
+		{name: "deny of other principal", principal: alice, operation: OperationRead, resource: topic("logs-secret"), want: true},
+		{name: "allow of a specific host", principal: alice, operation: OperationRead, resource: Resource{Type: ResourceGroup, Name: "g"}, want: false},
+		{name: "resource without ACLs", principal: bob, operation: OperationDelete, resource: topic("other"), want: true},
+	}
+	authorizer := NewACLAuthorizer(store, true)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := authorizer.Authorize(tt.principal, tt.operation, tt.resource); got != tt.want {
+				t.Errorf("Authorize(%s, %s, %s) = %v, want %v", tt.principal, tt.operation, tt.resource, got, tt.want)
+			}
+		})
+	}
+
+	if NewACLAuthorizer(store, false).Authorize(bob, OperationDelete, topic("other")) {
+		t.Error("resource without ACLs was allowed with allowIfNoACL unset")
+	}
+}
+
+func TestACLStore(t *testing.T) {
+	store := NewACLStore()
+	acl := ACL{ResourceType: ResourceTopic, ResourceName: "logs-", PatternType: PatternPrefixed, Principal: "User:alice", Host: WildcardHost, Operation: OperationRead, Permission: PermissionAllow}
+	if err := store.Create(acl); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Create(acl); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := acl
+	invalid.PatternType = PatternMatch
+	if err := store.Create(invalid); err == nil {
+		t.Error("ACL with MATCH pattern type was created")
+	}
+	invalid = acl
+	invalid.Principal = "alice"
+	if err := store.Create(invalid); err == nil {
+		t.Error("ACL with principal without type was created")
+	}
+
+	name := "logs-app"
+	match := ACLFilter{ResourceType: ResourceTopic, ResourceName: &name, PatternType: PatternMatch, Operation: OperationAny, Permission: PermissionAny}
+	if got := store.Describe(match); len(got) != 1 || got[0] != acl {
+		t.Errorf("ACLs matching %s = %v, want %v", name, got, acl)
+	}
+	literal := match
+	literal.PatternType = PatternLiteral
+	if got := store.Describe(literal); len(got) != 0 {
+		t.Errorf("literal ACLs of %s = %v, want none", name, got)
+	}
+
+	if deleted := store.Delete(ACLFilter{ResourceType: ResourceAny, PatternType: PatternAny, Operation: OperationAny, Permission: PermissionAny}); len(deleted) != 1 {
+		t.Errorf("deleted %v, want the single ACL", deleted)
+	}
+	if got := store.Describe(match); len(got) != 0 {
+		t.Errorf("ACLs after delete = %v, want none", got)
+	}
+}
@@ -0,0 +1,159 @@
+// Package auth implements the server side of SASL authentication. Clients pick a mechanism with SaslHandshake
+// and exchange the messages of the mechanism with SaslAuthenticate, until the client is authenticated or rejected.
+package auth
+
+import (
+	"fmt"
+	"slices"
+
+	"opentalaria/utils"
+)
+
+const (
+	ScramSha256 = "SCRAM-SHA-256"
+	ScramSha512 = "SCRAM-SHA-512"
+	OAuthBearer = "OAUTHBEARER"
+)
+
+// Server is the server side of the exchange of a SASL mechanism.
+type Server interface {
+	// Next processes a message of the client and returns the response of the server.
+	// done reports that the exchange is complete and the client is authenticated.
+	Next(msg []byte) (response []byte, done bool, err error)
+	// Principal returns the name of the authenticated client, once the exchange is done.
+	Principal() string
+}
+
+// Authenticator creates the servers of the enabled SASL mechanisms.
+// It is shared by all connections, and safe for concurrent use as long as its credential store is.
+type Authenticator struct {
+	mechanisms  []string
+	credentials CredentialStore
+	tokens      TokenValidator
+}
+
+// NewAuthenticator returns an Authenticator for the mechanisms, which looks up the SCRAM credentials of clients in the store
+// and validates the tokens of OAUTHBEARER clients with the validator. The validator can be nil if OAUTHBEARER is not enabled.
+func NewAuthenticator(mechanisms []string, credentials CredentialStore, tokens TokenValidator) (*Authenticator, error) {
+	for _, m := range mechanisms {
+		if m == OAuthBearer {
+			if tokens == nil {
+				return nil, fmt.Errorf("SASL mechanism %s requires a token validator", m)
+			}
+			continue
+		}
+		if _, ok := scramHashes[m]; !ok {
+			return nil, fmt.Errorf("unsupported SASL mechanism %s", m)
+		}
+	}
+
+	return &Authenticator{mechanisms: mechanisms, credentials: credentials, tokens: tokens}, nil
+}
+
+// Mechanisms returns the enabled mechanisms.
+func (a *Authenticator) Mechanisms() []string {
+	return a.mechanisms
+}
+
+// newServer returns the server for a new exchange of the mechanism.
+func (a *Authenticator) newServer(mechanism string) (Server, error) {
+	if !slices.Contains(a.mechanisms, mechanism) {
+		return nil, utils.ErrUnsupportedSASLMechanism
+	}
+
+	if mechanism == OAuthBearer {
+		return newOAuthBearerServer(a.tokens), nil
+	}
+
+	return newScramServer(mechanism, a.credentials), nil
+}
+
+// Session holds the authentication state of a client connection.
+// It is not safe for concurrent use, requests of a connection are handled one at a time.
+type Session struct {
+	authenticator *Authenticator
+	server        Server
+	authenticated bool
+	// failed is set when an exchange was rejected, the connection is closed after the response
+	failed    bool
+	principal Principal
+}
+
+// NewSession returns the session of a new connection, which isn't authenticated yet.
+// A nil authenticator means that SASL is not enabled, so every handshake is rejected.
+func NewSession(authenticator *Authenticator) *Session {
+	return &Session{authenticator: authenticator, principal: Anonymous}
+}
+
+// Enabled reports whether the client has to authenticate, which is the case if SASL is enabled.
+func (s *Session) Enabled() bool {
+	return s.authenticator != nil
+}
+
+// Mechanisms returns the mechanisms the client can pick from.
+func (s *Session) Mechanisms() []string {
+	if s.authenticator == nil {
+		return []string{}
+	}
+
+	return s.authenticator.Mechanisms()
+}
+
+// Handshake starts the exchange of the mechanism. A connection can only authenticate once.
+// The returned error is a utils.KError, so it can be sent back to the client as is.
+func (s *Session) Handshake(mechanism string) error {
+	if s.server != nil || s.Authenticated() {
+		return utils.ErrIllegalSASLState
+	}
+	if s.authenticator == nil {
+		return utils.ErrUnsupportedSASLMechanism
+	}
+
+	server, err := s.authenticator.newServer(mechanism)
+	if err != nil {
+		return err
+	}
+	s.server = server
+
+	return nil
+}
+
+// Authenticate passes the message of the client to the mechanism picked with Handshake, and returns the response of the server.
+// The returned error wraps a utils.KError, with the reason the client was rejected.
+func (s *Session) Authenticate(msg []byte) ([]byte, error) {
+	if s.server == nil {
+		s.failed = true
+		return nil, utils.ErrIllegalSASLState
+	}
+
+	response, done, err := s.server.Next(msg)
+	if err != nil {
+		// the exchange can't continue, so the client has to reconnect to authenticate again
+		s.failed = true
+		return nil, fmt.Errorf("%w: %w", utils.ErrSASLAuthenticationFailed, err)
+	}
+
+	if done {
+		s.authenticated = true
+		s.principal = NewUserPrincipal(s.server.Principal())
+		s.server = nil
+	}
+
+	return response, nil
+}
+
+// Authenticated reports whether the client completed the exchange of its mechanism.
+func (s *Session) Authenticated() bool {
+	return s.authenticated
+}
+
+// Failed reports whether an authentication of the client was rejected. Like Kafka, the connection is closed
+// once the client got the response with the reason.
+func (s *Session) Failed() bool {
+	return s.failed
+}
+
+// Principal returns the principal of the authenticated client, or Anonymous if it isn't authenticated.
+func (s *Session) Principal() Principal {
+	return s.principal
+}
@@ -0,0 +1,96 @@
+package auth
+
+import "fmt"
+
+// Operation is an operation clients perform on resources. The values are the ACL operation codes of the Kafka protocol.
+type Operation int8
+
+const (
+	OperationUnknown         Operation = 0
+	OperationAny             Operation = 1
+	OperationAll             Operation = 2
+	OperationRead            Operation = 3
+	OperationWrite           Operation = 4
+	OperationCreate          Operation = 5
+	OperationDelete          Operation = 6
+	OperationAlter           Operation = 7
+	OperationDescribe        Operation = 8
+	OperationClusterAction   Operation = 9
+	OperationDescribeConfigs Operation = 10
+	OperationAlterConfigs    Operation = 11
+	OperationIdempotentWrite Operation = 12
+)
+
+var operationNames = map[Operation]string{
+	OperationUnknown:         "UNKNOWN",
+	OperationAny:             "ANY",
+	OperationAll:             "ALL",
+	OperationRead:            "READ",
+	OperationWrite:           "WRITE",
+	OperationCreate:          "CREATE",
+	OperationDelete:          "DELETE",
+	OperationAlter:           "ALTER",
+	OperationDescribe:        "DESCRIBE",
+	OperationClusterAction:   "CLUSTER_ACTION",
+	OperationDescribeConfigs: "DESCRIBE_CONFIGS",
+	OperationAlterConfigs:    "ALTER_CONFIGS",
+	OperationIdempotentWrite: "IDEMPOTENT_WRITE",
+}
+
+func (o Operation) String() string {
+	if name, ok := operationNames[o]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Operation(%d)", int8(o))
+}
+
+// ResourceType is the type of the resources operations are performed on. The values are the resource type codes of the Kafka protocol.
+type ResourceType int8
+
+const (
+	ResourceUnknown         ResourceType = 0
+	ResourceAny             ResourceType = 1
+	ResourceTopic           ResourceType = 2
+	ResourceGroup           ResourceType = 3
+	ResourceCluster         ResourceType = 4
+	ResourceTransactionalID ResourceType = 5
+	ResourceDelegationToken ResourceType = 6
+)
+
+var resourceTypeNames = map[ResourceType]string{
+	ResourceUnknown:         "UNKNOWN",
+	ResourceAny:             "ANY",
+	ResourceTopic:           "TOPIC",
+	ResourceGroup:           "GROUP",
+	ResourceCluster:         "CLUSTER",
+	ResourceTransactionalID: "TRANSACTIONAL_ID",
+	ResourceDelegationToken: "DELEGATION_TOKEN",
+}
+
+func (t ResourceType) String() string {
+	if name, ok := resourceTypeNames[t]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("ResourceType(%d)", int8(t))
+}
+
+// Resource is a resource operations are performed on, like a topic or a consumer group.
+type Resource struct {
+	Type ResourceType
+	Name string
+}
+
+// ClusterResource is the resource of operations on the cluster, which has a fixed name like in Kafka.
+var ClusterResource = Resource{Type: ResourceCluster, Name: "kafka-cluster"}
+
+func (r Resource) String() string {
+	return r.Type.String() + ":" + r.Name
+}
+
+// Authorizer decides whether principals may perform operations on resources.
+// Implementations are shared by all connections, so they must be safe for concurrent use.
+type Authorizer interface {
+	Authorize(principal Principal, operation Operation, resource Resource) bool
+}
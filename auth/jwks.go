@@ -0,0 +1,322 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// jwksFetchTimeout bounds the request of the keys, so an unreachable endpoint doesn't block the authentications.
+	jwksFetchTimeout = 10 * time.Second
+	// jwksMinRefreshInterval is the minimum time between two requests of the keys, so tokens signed by unknown keys
+	// can't make the broker flood the identity provider with requests.
+	jwksMinRefreshInterval = 10 * time.Second
+	// jwksMaxSize is the largest key set which is read, a key set has a few keys of a few kilobytes.
+	jwksMaxSize = 1 << 20
+)
+
+// JWKSTokenValidator accepts JSON Web Tokens signed by one of the keys of the JSON Web Key Set which the identity
+// provider serves at its JWKS endpoint, like the validator Kafka configures with sasl.oauthbearer.jwks.endpoint.url.
+// Besides the signature, it checks the expiry of the token, and its issuer and audience if they are expected.
+// The keys are requested with the first token, and again once they are older than the refresh interval, or when a token
+// is signed by an unknown key, because the identity provider rotated its keys.
+type JWKSTokenValidator struct {
+	url             string
+	issuer          string
+	audience        string
+	refreshInterval time.Duration
+	client          *http.Client
+	// now returns the current time, it is replaced in tests to check expired tokens
+	now func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// NewJWKSTokenValidator returns a JWKSTokenValidator for the keys served at url. An empty issuer or audience
+// accepts tokens of any issuer or audience.
+func NewJWKSTokenValidator(url, issuer, audience string, refreshInterval time.Duration) *JWKSTokenValidator {
+	return &JWKSTokenValidator{
+		url:             url,
+		issuer:          issuer,
+		audience:        audience,
+		refreshInterval: refreshInterval,
+		client:          &http.Client{Timeout: jwksFetchTimeout},
+		now:             time.Now,
+	}
+}
+
+func (v *JWKSTokenValidator) ValidateToken(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("token is not a JSON Web Token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return "", fmt.Errorf("invalid token header: %w", err)
+	}
+	if header.Alg == "" || header.Alg == "none" {
+		return "", errors.New("token must be signed")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("invalid token signature: %w", err)
+	}
+
+	key, err := v.key(header.Kid)
+	if err != nil {
+		return "", err
+	}
+	if err := verifyJWTSignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return "", err
+	}
+
+	var claims jwtClaims
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return "", fmt.Errorf("invalid token claims: %w", err)
+	}
+	if err := claims.validate(v.now()); err != nil {
+		return "", err
+	}
+	if v.issuer != "" && claims.Iss != v.issuer {
+		return "", fmt.Errorf("token issued by %q, not by %q", claims.Iss, v.issuer)
+	}
+	if v.audience != "" && !claims.hasAudience(v.audience) {
+		return "", fmt.Errorf("token not issued for the audience %q", v.audience)
+	}
+
+	return claims.Sub, nil
+}
+
+// key returns the key with the key ID, or the only key of the key set if the token has no key ID.
+// The keys are requested again if they are stale, or if the key isn't known.
+func (v *JWKSTokenValidator) key(kid string) (crypto.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := v.now()
+	key, ok := v.lookup(kid)
+	stale := v.keys == nil || now.Sub(v.fetchedAt) >= v.refreshInterval
+	if stale || (!ok && now.Sub(v.fetchedAt) >= jwksMinRefreshInterval) {
+		keys, err := v.fetchKeys()
+		v.fetchedAt = now
+		// the previous keys are kept if the identity provider can't be reached, the tokens signed by them are still valid
+		if err != nil && v.keys == nil {
+			return nil, fmt.Errorf("error requesting the keys to verify the token: %w", err)
+		}
+		if err == nil {
+			v.keys = keys
+		}
+		key, ok = v.lookup(kid)
+	}
+	if !ok {
+		return nil, fmt.Errorf("token signed by the unknown key %q", kid)
+	}
+
+	return key, nil
+}
+
+func (v *JWKSTokenValidator) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+	key, ok := v.keys[kid]
+
+	return key, ok
+}
+
+// jwk is a JSON Web Key, as defined by RFC 7517, with the parameters of RSA and elliptic curve public keys.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetchKeys requests the key set, and returns its signing keys by key ID. Keys of other types are skipped.
+func (v *JWKSTokenValidator) fetchKeys() (map[string]crypto.PublicKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), jwksFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s from %s", resp.Status, v.url)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, jwksMaxSize)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("invalid key set: %w", err)
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", k.Kid, err)
+		}
+		if key != nil {
+			keys[k.Kid] = key
+		}
+	}
+
+	return keys, nil
+}
+
+// publicKey returns the RSA or ECDSA public key, or nil for other key types.
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeJWKInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeJWKInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		var ecdhCurve ecdh.Curve
+		switch k.Crv {
+		case "P-256":
+			curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+		case "P-384":
+			curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+		case "P-521":
+			curve, ecdhCurve = elliptic.P521(), ecdh.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeJWKInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeJWKInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		// the ecdh package rejects points which are not on the curve
+		size := (curve.Params().BitSize + 7) / 8
+		if x.BitLen() > size*8 || y.BitLen() > size*8 {
+			return nil, errors.New("invalid elliptic curve point")
+		}
+		point := append([]byte{4}, append(x.FillBytes(make([]byte, size)), y.FillBytes(make([]byte, size))...)...)
+		if _, err := ecdhCurve.NewPublicKey(point); err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func decodeJWKInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("missing key parameter")
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
+
+// verifyJWTSignature verifies the signature of a JSON Web Token with the algorithm of its header, which must match the
+// type of the key. The RSA (RS*, PS*) and ECDSA (ES*) algorithms are supported.
+func verifyJWTSignature(alg string, key crypto.PublicKey, signed, signature []byte) error {
+	if len(alg) != 5 {
+		return fmt.Errorf("unsupported token algorithm %q", alg)
+	}
+	var hash crypto.Hash
+	switch alg[2:] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported token algorithm %q", alg)
+	}
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	var err error
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		switch alg[:2] {
+		case "RS":
+			err = rsa.VerifyPKCS1v15(k, hash, digest, signature)
+		case "PS":
+			err = rsa.VerifyPSS(k, hash, digest, signature, nil)
+		default:
+			return fmt.Errorf("token algorithm %q doesn't match the RSA key", alg)
+		}
+	case *ecdsa.PublicKey:
+		if curves := map[string]string{"ES256": "P-256", "ES384": "P-384", "ES512": "P-521"}; curves[alg] != k.Curve.Params().Name {
+			return fmt.Errorf("token algorithm %q doesn't match the elliptic curve key", alg)
+		}
+		// the signature is the concatenation of r and s, each as long as the size of the curve
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid token signature")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			err = errors.New("verification error")
+		}
+	}
+	if err != nil {
+		return errors.New("invalid token signature")
+	}
+
+	return nil
+}
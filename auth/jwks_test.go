@@ -0,0 +1,198 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// signedToken returns a JSON Web Token with the claims, signed by the key with RS256 or ES256.
+func signedToken(t *testing.T, key crypto.Signer, kid, claims string) string {
+	t.Helper()
+
+	alg := "RS256"
+	if _, ok := key.(*ecdsa.PrivateKey); ok {
+		alg = "ES256"
+	}
+	enc := base64.RawURLEncoding
+	signed := enc.EncodeToString([]byte(`{"alg":"`+alg+`","kid":"`+kid+`"}`)) + "." + enc.EncodeToString([]byte(claims))
+	digest := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		if signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:]); err != nil {
+			t.Fatal(err)
+		}
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+
+	return signed + "." + enc.EncodeToString(signature)
+}
+
+// publicJWK returns the JSON Web Key of the public key.
+func publicJWK(kid string, key crypto.PublicKey) map[string]string {
+	enc := base64.RawURLEncoding
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return map[string]string{"kty": "RSA", "kid": kid, "use": "sig", "n": enc.EncodeToString(k.N.Bytes()), "e": enc.EncodeToString(big.NewInt(int64(k.E)).Bytes())}
+	case *ecdsa.PublicKey:
+		return map[string]string{"kty": "EC", "kid": kid, "crv": "P-256", "x": enc.EncodeToString(k.X.FillBytes(make([]byte, 32))), "y": enc.EncodeToString(k.Y.FillBytes(make([]byte, 32)))}
+	}
+	return nil
+}
+
+// jwksServer serves the JSON Web Key Set returned by keys, and counts its requests.
+func jwksServer(t *testing.T, keys func() []map[string]string) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		json.NewEncoder(w).Encode(map[string]any{"keys": keys()})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestJWKSTokenValidator(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server, _ := jwksServer(t, func() []map[string]string {
+		return []map[string]string{
+			publicJWK("rsa", &rsaKey.PublicKey),
+			publicJWK("ec", &ecKey.PublicKey),
+			{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"},
+		}
+	})
+	validator := NewJWKSTokenValidator(server.URL, "https://idp.example.com", "kafka", time.Hour)
+	validator.now = func() time.Time { return time.Unix(1000, 0) }
+
+	valid := `{"sub":"alice","exp":2000,"iss":"https://idp.example.com","aud":"kafka"}`
+	tests := []struct {
+		name      string
+		token     string
+		principal string
+		err       string
+	}{
+		{name: "RS256", token: signedToken(t, rsaKey, "rsa", valid), principal: "alice"},
+		{name: "ES256", token: signedToken(t, ecKey, "ec", valid), principal: "alice"},
+		{name: "audience list", token: signedToken(t, rsaKey, "rsa", `{"sub":"alice","exp":2000,"iss":"https://idp.example.com","aud":["other","kafka"]}`), principal: "alice"},
+		{name: "signed by another key", token: signedToken(t, otherKey, "rsa", valid), err: "invalid token signature"},
+		{name: "unknown key", token: signedToken(t, otherKey, "other", valid), err: "unknown key"},
+		{name: "encryption key", token: signedToken(t, rsaKey, "enc", valid), err: "unknown key"},
+		{name: "algorithm of another key type", token: strings.Replace(signedToken(t, ecKey, "ec", valid), base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"ES256","kid":"ec"}`)), base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","kid":"ec"}`)), 1), err: "doesn't match"},
+		{name: "unsigned", token: unsecuredToken(valid), err: "must be signed"},
+		{name: "expired", token: signedToken(t, rsaKey, "rsa", `{"sub":"alice","exp":1000,"iss":"https://idp.example.com","aud":"kafka"}`), err: "expired"},
+		{name: "other issuer", token: signedToken(t, rsaKey, "rsa", `{"sub":"alice","exp":2000,"iss":"https://other.example.com","aud":"kafka"}`), err: "issued by"},
+		{name: "other audience", token: signedToken(t, rsaKey, "rsa", `{"sub":"alice","exp":2000,"iss":"https://idp.example.com","aud":"other"}`), err: "audience"},
+		{name: "without subject", token: signedToken(t, rsaKey, "rsa", `{"exp":2000,"iss":"https://idp.example.com","aud":"kafka"}`), err: "without subject"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := validator.ValidateToken(tt.token)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("error = %v, want it to contain %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil || principal != tt.principal {
+				t.Errorf("principal = %q, error %v, want %q", principal, err, tt.principal)
+			}
+		})
+	}
+}
+
+func TestJWKSTokenValidator_KeyRotation(t *testing.T) {
+	oldKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rotated atomic.Bool
+	server, requests := jwksServer(t, func() []map[string]string {
+		if rotated.Load() {
+			return []map[string]string{publicJWK("new", &newKey.PublicKey)}
+		}
+		return []map[string]string{publicJWK("old", &oldKey.PublicKey)}
+	})
+	now := time.Unix(1000, 0)
+	validator := NewJWKSTokenValidator(server.URL, "", "", time.Hour)
+	validator.now = func() time.Time { return now }
+
+	claims := `{"sub":"alice","exp":100000}`
+	if _, err := validator.ValidateToken(signedToken(t, oldKey, "old", claims)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := validator.ValidateToken(signedToken(t, oldKey, "old", claims)); err != nil {
+		t.Fatal(err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("key set requested %d times, want once", got)
+	}
+
+	// a token of an unknown key is only looked up again after the minimum refresh interval
+	rotated.Store(true)
+	if _, err := validator.ValidateToken(signedToken(t, newKey, "new", claims)); err == nil {
+		t.Error("token of the rotated key accepted before the minimum refresh interval")
+	}
+	now = now.Add(jwksMinRefreshInterval)
+	if _, err := validator.ValidateToken(signedToken(t, newKey, "new", claims)); err != nil {
+		t.Errorf("token of the rotated key rejected: %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("key set requested %d times, want twice", got)
+	}
+
+	// without a key ID, the only key of the set is used
+	if _, err := validator.ValidateToken(signedToken(t, newKey, "", claims)); err != nil {
+		t.Errorf("token without key ID rejected: %v", err)
+	}
+}
+
+func TestJWKSTokenValidator_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator := NewJWKSTokenValidator(server.URL, "", "", time.Hour)
+	if _, err := validator.ValidateToken(signedToken(t, key, "ec", `{"sub":"alice","exp":100000}`)); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("error = %v, want the status of the key set request", err)
+	}
+}
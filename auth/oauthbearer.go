@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TokenValidator validates the bearer tokens of OAUTHBEARER clients, for example by verifying their signature against
+// the keys of the identity provider and checking their expiry. Validators can be swapped without touching the SASL exchange.
+type TokenValidator interface {
+	// ValidateToken returns the principal the token was issued to, or an error with the reason the token was rejected,
+	// which is sent to the client.
+	ValidateToken(token string) (principal string, err error)
+}
+
+// oauthBearerServer is the server side of an OAUTHBEARER exchange, as defined by RFC 7628. The client sends its token
+// in its initial response, which is either accepted with an empty response or rejected.
+type oauthBearerServer struct {
+	tokens    TokenValidator
+	done      bool
+	principal string
+}
+
+func newOAuthBearerServer(tokens TokenValidator) *oauthBearerServer {
+	return &oauthBearerServer{tokens: tokens}
+}
+
+func (s *oauthBearerServer) Next(msg []byte) ([]byte, bool, error) {
+	if s.done {
+		return nil, false, errors.New("OAUTHBEARER exchange already completed")
+	}
+
+	token, authzid, err := parseOAuthBearerResponse(string(msg))
+	if err != nil {
+		return nil, false, err
+	}
+
+	principal, err := s.tokens.ValidateToken(token)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid OAUTHBEARER token: %w", err)
+	}
+	if authzid != "" && authzid != principal {
+		return nil, false, fmt.Errorf("OAUTHBEARER authorization identity %s doesn't match the principal of the token", authzid)
+	}
+
+	s.done = true
+	s.principal = principal
+
+	return []byte{}, true, nil
+}
+
+func (s *oauthBearerServer) Principal() string {
+	return s.principal
+}
+
+// parseOAuthBearerResponse parses the initial response of the client, e.g. "n,,\x01auth=Bearer token\x01\x01",
+// which is the gs2 header followed by key=value pairs separated by \x01. Other pairs, like the SASL extensions
+// Kafka clients send, are ignored.
+func parseOAuthBearerResponse(msg string) (token, authzid string, err error) {
+	flag, rest, ok := strings.Cut(msg, ",")
+	if !ok {
+		return "", "", errors.New("invalid OAUTHBEARER client response")
+	}
+	if flag != "n" && flag != "y" {
+		return "", "", errors.New("OAUTHBEARER channel binding is not supported")
+	}
+	authzid, pairs, ok := strings.Cut(rest, ",")
+	if !ok || !strings.HasSuffix(pairs, "\x01\x01") {
+		return "", "", errors.New("invalid OAUTHBEARER client response")
+	}
+	if authzid != "" {
+		if !strings.HasPrefix(authzid, "a=") {
+			return "", "", errors.New("invalid OAUTHBEARER authorization identity")
+		}
+		if authzid, err = unescapeScramName(strings.TrimPrefix(authzid, "a=")); err != nil {
+			return "", "", err
+		}
+	}
+
+	for _, pair := range strings.Split(strings.Trim(pairs, "\x01"), "\x01") {
+		key, value, _ := strings.Cut(pair, "=")
+		if key != "auth" {
+			continue
+		}
+
+		scheme, token, ok := strings.Cut(value, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			return "", "", errors.New("OAUTHBEARER auth value must be a bearer token")
+		}
+
+		return token, authzid, nil
+	}
+
+	return "", "", errors.New("OAUTHBEARER client response without token")
+}
+
+// UnsecuredTokenValidator accepts unsigned JSON Web Tokens, with the "none" algorithm, and returns their subject.
+// It only checks that the token hasn't expired, so anyone can claim any principal. Like the unsecured validator of Kafka,
+// it is meant for development and testing, production deployments use a validator which verifies signatures, like
+// JWKSTokenValidator.
+type UnsecuredTokenValidator struct {
+	// now returns the current time, it is replaced in tests to check expired tokens
+	now func() time.Time
+}
+
+// NewUnsecuredTokenValidator returns an UnsecuredTokenValidator.
+func NewUnsecuredTokenValidator() *UnsecuredTokenValidator {
+	return &UnsecuredTokenValidator{now: time.Now}
+}
+
+func (v *UnsecuredTokenValidator) ValidateToken(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("token is not a JSON Web Token")
+	}
+	if parts[2] != "" {
+		return "", errors.New("token must not be signed")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return "", fmt.Errorf("invalid token header: %w", err)
+	}
+	if header.Alg != "none" {
+		return "", fmt.Errorf("token algorithm must be none, not %q", header.Alg)
+	}
+
+	var claims jwtClaims
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return "", fmt.Errorf("invalid token claims: %w", err)
+	}
+	if err := claims.validate(v.now()); err != nil {
+		return "", err
+	}
+
+	return claims.Sub, nil
+}
+
+// jwtClaims are the claims of a JSON Web Token which the validators check.
+type jwtClaims struct {
+	Sub string   `json:"sub"`
+	Exp *float64 `json:"exp"`
+	Iss string   `json:"iss"`
+	// Aud is a single audience or a list of audiences
+	Aud any `json:"aud"`
+}
+
+// validate checks that the token has a subject and hasn't expired.
+func (c jwtClaims) validate(now time.Time) error {
+	if c.Sub == "" {
+		return errors.New("token without subject")
+	}
+	if c.Exp == nil {
+		return errors.New("token without expiry")
+	}
+	if expiry := time.UnixMilli(int64(*c.Exp * 1000)); !now.Before(expiry) {
+		return fmt.Errorf("token expired at %s", expiry.UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// hasAudience reports whether the token was issued for the audience.
+func (c jwtClaims) hasAudience(audience string) bool {
+	switch aud := c.Aud.(type) {
+	case string:
+		return aud == audience
+	case []any:
+		for _, a := range aud {
+			if a == audience {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// decodeJWTPart decodes a base64url encoded part of a JSON Web Token.
+func decodeJWTPart(part string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
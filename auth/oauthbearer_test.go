@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"opentalaria/utils"
+)
+
+// unsecuredToken returns an unsigned JSON Web Token with the claims.
+func unsecuredToken(claims string) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"none"}`)) + "." + enc.EncodeToString([]byte(claims)) + "."
+}
+
+func oauthBearerResponse(authzid, token string) []byte {
+	return []byte("n," + authzid + ",\x01auth=Bearer " + token + "\x01\x01")
+}
+
+func TestUnsecuredTokenValidator(t *testing.T) {
+	validator := NewUnsecuredTokenValidator()
+	validator.now = func() time.Time { return time.Unix(1000, 0) }
+
+	signedHeader := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256"}`))
+	tests := []struct {
+		name      string
+		token     string
+		principal string
+		err       string
+	}{
+		{name: "valid", token: unsecuredToken(`{"sub":"alice","exp":2000}`), principal: "alice"},
+		{name: "fractional expiry", token: unsecuredToken(`{"sub":"alice","exp":1000.5}`), principal: "alice"},
+		{name: "expired", token: unsecuredToken(`{"sub":"alice","exp":1000}`), err: "expired"},
+		{name: "without expiry", token: unsecuredToken(`{"sub":"alice"}`), err: "without expiry"},
+		{name: "without subject", token: unsecuredToken(`{"exp":2000}`), err: "without subject"},
+		{name: "signed", token: unsecuredToken(`{"sub":"alice","exp":2000}`) + "c2ln", err: "signed"},
+		{name: "other algorithm", token: signedHeader + "." + strings.Split(unsecuredToken(`{"sub":"alice","exp":2000}`), ".")[1] + ".", err: "algorithm"},
+		{name: "not a JWT", token: "opaque", err: "not a JSON Web Token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := validator.ValidateToken(tt.token)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("error = %v, want it to contain %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil || principal != tt.principal {
+				t.Errorf("principal = %q, error %v, want %q", principal, err, tt.principal)
+			}
+		})
+	}
+}
+
+func TestOAuthBearerServer(t *testing.T) {
+	validator := NewUnsecuredTokenValidator()
+	token := unsecuredToken(`{"sub":"alice","exp":4102444800}`)
+
+	tests := []struct {
+		name      string
+		msg       []byte
+		principal string
+	}{
+		{name: "token", msg: oauthBearerResponse("", token), principal: "alice"},
+		{name: "matching authorization identity", msg: oauthBearerResponse("a=alice", token), principal: "alice"},
+		{name: "extensions", msg: []byte("n,,\x01traceId=123\x01auth=Bearer " + token + "\x01\x01"), principal: "alice"},
+		{name: "other authorization identity", msg: oauthBearerResponse("a=bob", token)},
+		{name: "without token", msg: []byte("n,,\x01traceId=123\x01\x01")},
+		{name: "other scheme", msg: []byte("n,,\x01auth=Basic YWxpY2U6c2VjcmV0\x01\x01")},
+		{name: "channel binding", msg: []byte("p=tls-unique,,\x01auth=Bearer " + token + "\x01\x01")},
+		{name: "unterminated", msg: []byte("n,,\x01auth=Bearer " + token)},
+		{name: "rejected token", msg: oauthBearerResponse("", unsecuredToken(`{"sub":"alice","exp":1}`))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newOAuthBearerServer(validator)
+
+			response, done, err := server.Next(tt.msg)
+			if tt.principal == "" {
+				if err == nil || done {
+					t.Errorf("done %v, error %v, want the exchange to fail", done, err)
+				}
+				return
+			}
+			if err != nil || !done || len(response) != 0 {
+				t.Fatalf("response %q, done %v, error %v, want an empty response", response, done, err)
+			}
+			if server.Principal() != tt.principal {
+				t.Errorf("principal = %q, want %q", server.Principal(), tt.principal)
+			}
+		})
+	}
+}
+
+func TestSession_OAuthBearer(t *testing.T) {
+	if _, err := NewAuthenticator([]string{OAuthBearer}, nil, nil); err == nil {
+		t.Error("OAUTHBEARER was enabled without a token validator")
+	}
+
+	authenticator, err := NewAuthenticator([]string{ScramSha256, OAuthBearer}, NewMemoryCredentialStore(), NewUnsecuredTokenValidator())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session := NewSession(authenticator)
+	if err := session.Handshake(OAuthBearer); err != nil {
+		t.Fatal(err)
+	}
+	_, err = session.Authenticate(oauthBearerResponse("", unsecuredToken(`{"sub":"alice","exp":1}`)))
+	if !errors.Is(err, utils.ErrSASLAuthenticationFailed) || !strings.Contains(err.Error(), "token expired") {
+		t.Errorf("authenticate with expired token = %v, want %v with the reason", err, utils.ErrSASLAuthenticationFailed)
+	}
+
+	session = NewSession(authenticator)
+	if err := session.Handshake(OAuthBearer); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := session.Authenticate(oauthBearerResponse("", unsecuredToken(`{"sub":"alice","exp":4102444800}`))); err != nil {
+		t.Fatal(err)
+	}
+	if got := session.Principal().String(); got != "User:alice" {
+		t.Errorf("principal = %s, want User:alice", got)
+	}
+}
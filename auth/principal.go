@@ -0,0 +1,23 @@
+package auth
+
+// PrincipalTypeUser is the type of the principals of users, the only type Kafka defines.
+const PrincipalTypeUser = "User"
+
+// Principal is the identity of a client, which its operations are authorized for.
+type Principal struct {
+	Type string
+	Name string
+}
+
+// Anonymous is the principal of clients which are not authenticated, like the clients of PLAINTEXT listeners.
+var Anonymous = Principal{Type: PrincipalTypeUser, Name: "ANONYMOUS"}
+
+// NewUserPrincipal returns the principal of the user with the name.
+func NewUserPrincipal(name string) Principal {
+	return Principal{Type: PrincipalTypeUser, Name: name}
+}
+
+// String returns the principal in the form Kafka uses in ACLs, e.g. User:alice.
+func (p Principal) String() string {
+	return p.Type + ":" + p.Name
+}
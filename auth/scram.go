@@ -0,0 +1,334 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MinScramIterations is the lowest iteration count of SCRAM credentials, like Kafka requires.
+const MinScramIterations = 4096
+
+// errInvalidCredentials is returned for unknown users as well as wrong passwords, with the message Kafka uses for both.
+// The other failures of the exchange return their own errors, and an unknown user is rejected at the client first message
+// while a wrong password is only rejected at the client final message.
+var errInvalidCredentials = errors.New("authentication failed: invalid user credentials")
+
+var scramHashes = map[string]func() hash.Hash{
+	ScramSha256: sha256.New,
+	ScramSha512: sha512.New,
+}
+
+// ScramCredential is the salted verifier of a password, as defined by RFC 5802. The password itself is not stored.
+type ScramCredential struct {
+	Salt       []byte
+	Iterations int
+	StoredKey  []byte
+	ServerKey  []byte
+}
+
+// NewScramCredential derives the credential of the password for the mechanism, with a random salt.
+func NewScramCredential(mechanism, password string, iterations int) (ScramCredential, error) {
+	newHash, ok := scramHashes[mechanism]
+	if !ok {
+		return ScramCredential{}, fmt.Errorf("unsupported SCRAM mechanism %s", mechanism)
+	}
+	if iterations < MinScramIterations {
+		return ScramCredential{}, fmt.Errorf("SCRAM iterations %d are below the minimum of %d", iterations, MinScramIterations)
+	}
+
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return ScramCredential{}, err
+	}
+
+	return saltedScramCredential(newHash, password, salt, iterations), nil
+}
+
+func saltedScramCredential(newHash func() hash.Hash, password string, salt []byte, iterations int) ScramCredential {
+	saltedPassword := scramHi(newHash, []byte(password), salt, iterations)
+	clientKey := scramHMAC(newHash, saltedPassword, []byte("Client Key"))
+	storedKey := newHash()
+	storedKey.Write(clientKey)
+
+	return ScramCredential{
+		Salt:       salt,
+		Iterations: iterations,
+		StoredKey:  storedKey.Sum(nil),
+		ServerKey:  scramHMAC(newHash, saltedPassword, []byte("Server Key")),
+	}
+}
+
+// CredentialStore resolves the SCRAM credentials of users.
+type CredentialStore interface {
+	// ScramCredential returns the credential of the user for the mechanism, or false if the user has none.
+	ScramCredential(mechanism, username string) (ScramCredential, bool)
+}
+
+// MemoryCredentialStore is a CredentialStore which keeps the credentials in memory.
+// It is safe for concurrent use, so credentials can be changed while clients authenticate.
+type MemoryCredentialStore struct {
+	mu          sync.RWMutex
+	credentials map[string]map[string]ScramCredential
+}
+
+// NewMemoryCredentialStore returns a store without credentials.
+func NewMemoryCredentialStore() *MemoryCredentialStore {
+	return &MemoryCredentialStore{credentials: map[string]map[string]ScramCredential{}}
+}
+
+// SetScramCredential sets the credential of the user for the mechanism.
+func (s *MemoryCredentialStore) SetScramCredential(mechanism, username string, credential ScramCredential) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.credentials[mechanism] == nil {
+		s.credentials[mechanism] = map[string]ScramCredential{}
+	}
+	s.credentials[mechanism][username] = credential
+}
+
+func (s *MemoryCredentialStore) ScramCredential(mechanism, username string) (ScramCredential, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	credential, ok := s.credentials[mechanism][username]
+	return credential, ok
+}
+
+type scramState int
+
+const (
+	scramClientFirst scramState = iota
+	scramClientFinal
+	scramDone
+	scramFailed
+)
+
+// scramServer is the server side of a SCRAM exchange, as defined by RFC 5802:
+// client-first, server-first, client-final and server-final messages.
+type scramServer struct {
+	mechanism   string
+	newHash     func() hash.Hash
+	credentials CredentialStore
+	// nonce generates the server part of the nonce, it is replaced in tests to get predictable messages
+	nonce func() (string, error)
+
+	state           scramState
+	username        string
+	credential      ScramCredential
+	gs2Header       string
+	combinedNonce   string
+	clientFirstBare string
+	serverFirst     string
+}
+
+func newScramServer(mechanism string, credentials CredentialStore) *scramServer {
+	return &scramServer{
+		mechanism:   mechanism,
+		newHash:     scramHashes[mechanism],
+		credentials: credentials,
+		nonce:       randomNonce,
+	}
+}
+
+func (s *scramServer) Next(msg []byte) ([]byte, bool, error) {
+	var response []byte
+	var err error
+
+	switch s.state {
+	case scramClientFirst:
+		response, err = s.handleClientFirst(string(msg))
+	case scramClientFinal:
+		response, err = s.handleClientFinal(string(msg))
+	default:
+		err = errors.New("SCRAM exchange already completed")
+	}
+
+	if err != nil {
+		s.state = scramFailed
+		return nil, false, err
+	}
+
+	return response, s.state == scramDone, nil
+}
+
+func (s *scramServer) Principal() string {
+	if s.state != scramDone {
+		return ""
+	}
+
+	return s.username
+}
+
+// handleClientFirst parses gs2-header client-first-message-bare, e.g. "n,,n=user,r=nonce", and answers with the salt,
+// the iteration count and the nonce of the client extended by the server.
+func (s *scramServer) handleClientFirst(msg string) ([]byte, error) {
+	// the gs2 header is the channel binding flag and an optional authorization identity
+	flag, rest, ok := strings.Cut(msg, ",")
+	if !ok {
+		return nil, errors.New("invalid SCRAM client first message")
+	}
+	if flag != "n" && flag != "y" {
+		return nil, errors.New("SCRAM channel binding is not supported")
+	}
+	authzid, bare, ok := strings.Cut(rest, ",")
+	if !ok {
+		return nil, errors.New("invalid SCRAM client first message")
+	}
+
+	attrs, err := parseScramAttributes(bare)
+	if err != nil {
+		return nil, err
+	}
+	username, err := unescapeScramName(attrs["n"])
+	if err != nil {
+		return nil, err
+	}
+	clientNonce := attrs["r"]
+	if username == "" || clientNonce == "" {
+		return nil, errors.New("SCRAM client first message without user name or nonce")
+	}
+	if authzid != "" && authzid != "a="+escapeScramName(username) {
+		return nil, errors.New("SCRAM authorization identity must match the user name")
+	}
+
+	credential, ok := s.credentials.ScramCredential(s.mechanism, username)
+	if !ok {
+		return nil, errInvalidCredentials
+	}
+
+	serverNonce, err := s.nonce()
+	if err != nil {
+		return nil, err
+	}
+
+	s.username = username
+	s.credential = credential
+	s.gs2Header = flag + "," + authzid + ","
+	s.combinedNonce = clientNonce + serverNonce
+	s.clientFirstBare = bare
+	s.serverFirst = "r=" + s.combinedNonce + ",s=" + base64.StdEncoding.EncodeToString(credential.Salt) + ",i=" + strconv.Itoa(credential.Iterations)
+	s.state = scramClientFinal
+
+	return []byte(s.serverFirst), nil
+}
+
+// handleClientFinal verifies the proof of the client final message, e.g. "c=biws,r=nonce,p=proof",
+// and answers with the signature of the server, so the client can verify the server as well.
+func (s *scramServer) handleClientFinal(msg string) ([]byte, error) {
+	withoutProof, proofAttr, ok := strings.Cut(msg, ",p=")
+	if !ok {
+		return nil, errors.New("SCRAM client final message without proof")
+	}
+	attrs, err := parseScramAttributes(withoutProof)
+	if err != nil {
+		return nil, err
+	}
+
+	if attrs["c"] != base64.StdEncoding.EncodeToString([]byte(s.gs2Header)) {
+		return nil, errors.New("SCRAM channel binding doesn't match the client first message")
+	}
+	// the nonce must be the one of this exchange, so a client final message can't be replayed
+	if subtle.ConstantTimeCompare([]byte(attrs["r"]), []byte(s.combinedNonce)) != 1 {
+		return nil, errors.New("SCRAM nonce doesn't match the nonce of the exchange")
+	}
+
+	proof, err := base64.StdEncoding.DecodeString(proofAttr)
+	if err != nil {
+		return nil, errors.New("invalid SCRAM client proof")
+	}
+
+	authMessage := []byte(s.clientFirstBare + "," + s.serverFirst + "," + withoutProof)
+
+	// the proof is the client key xor the client signature, the hash of the recovered client key must be the stored key
+	clientSignature := scramHMAC(s.newHash, s.credential.StoredKey, authMessage)
+	if len(proof) != len(clientSignature) {
+		return nil, errInvalidCredentials
+	}
+	clientKey := make([]byte, len(proof))
+	subtle.XORBytes(clientKey, proof, clientSignature)
+	storedKey := s.newHash()
+	storedKey.Write(clientKey)
+	if subtle.ConstantTimeCompare(storedKey.Sum(nil), s.credential.StoredKey) != 1 {
+		return nil, errInvalidCredentials
+	}
+
+	serverSignature := scramHMAC(s.newHash, s.credential.ServerKey, authMessage)
+	s.state = scramDone
+
+	return []byte("v=" + base64.StdEncoding.EncodeToString(serverSignature)), nil
+}
+
+// parseScramAttributes parses the comma separated name=value attributes of a SCRAM message.
+// Unknown attributes, like the extensions Kafka clients send, are kept but ignored.
+func parseScramAttributes(msg string) (map[string]string, error) {
+	attrs := map[string]string{}
+	for _, attr := range strings.Split(msg, ",") {
+		name, value, ok := strings.Cut(attr, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid SCRAM attribute %q", attr)
+		}
+		attrs[name] = value
+	}
+
+	return attrs, nil
+}
+
+// unescapeScramName decodes a user name, where ',' and '=' are sent as "=2C" and "=3D".
+func unescapeScramName(name string) (string, error) {
+	unescaped := strings.NewReplacer("=2C", ",", "=3D", "=").Replace(name)
+	if strings.Count(name, "=") != strings.Count(name, "=2C")+strings.Count(name, "=3D") {
+		return "", errors.New("invalid escape sequence in SCRAM user name")
+	}
+
+	return unescaped, nil
+}
+
+func escapeScramName(name string) string {
+	return strings.NewReplacer("=", "=3D", ",", "=2C").Replace(name)
+}
+
+// randomNonce returns 32 random bytes, encoded in base64, which never contains ','.
+func randomNonce() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func scramHMAC(newHash func() hash.Hash, key, msg []byte) []byte {
+	mac := hmac.New(newHash, key)
+	mac.Write(msg)
+	return mac.Sum(nil)
+}
+
+// scramHi is the Hi function of RFC 5802, which is PBKDF2 with HMAC as the pseudorandom function and a single block of output.
+func scramHi(newHash func() hash.Hash, password, salt []byte, iterations int) []byte {
+	mac := hmac.New(newHash, password)
+	mac.Write(salt)
+	mac.Write([]byte{0, 0, 0, 1})
+	u := mac.Sum(nil)
+
+	result := bytes.Clone(u)
+	for i := 1; i < iterations; i++ {
+		mac.Reset()
+		mac.Write(u)
+		u = mac.Sum(u[:0])
+		subtle.XORBytes(result, result, u)
+	}
+
+	return result
+}
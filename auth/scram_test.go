@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"testing"
+
+	"opentalaria/utils"
+)
+
+// rfc7677Server returns a server set up with the credential and nonce of the SCRAM-SHA-256 example of RFC 7677.
+func rfc7677Server(t *testing.T) *scramServer {
+	t.Helper()
+
+	salt, err := base64.StdEncoding.DecodeString("W22ZaJ0SNY7soEsUEjb6gQ==")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := NewMemoryCredentialStore()
+	store.SetScramCredential(ScramSha256, "user", saltedScramCredential(sha256.New, "pencil", salt, 4096))
+
+	server := newScramServer(ScramSha256, store)
+	server.nonce = func() (string, error) { return "%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0", nil }
+
+	return server
+}
+
+func TestScramServer_RFC7677(t *testing.T) {
+	server := rfc7677Server(t)
+
+	serverFirst, done, err := server.Next([]byte("n,,n=user,r=rOprNGfwEbeRWgbNEkqO"))
+	if err != nil || done {
+		t.Fatalf("client first: done %v, error %v", done, err)
+	}
+	if want := "r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096"; string(serverFirst) != want {
+		t.Errorf("server first = %s, want %s", serverFirst, want)
+	}
+
+	serverFinal, done, err := server.Next([]byte("c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ="))
+	if err != nil || !done {
+		t.Fatalf("client final: done %v, error %v", done, err)
+	}
+	if want := "v=6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4="; string(serverFinal) != want {
+		t.Errorf("server final = %s, want %s", serverFinal, want)
+	}
+	if server.Principal() != "user" {
+		t.Errorf("principal = %q, want user", server.Principal())
+	}
+}
+
+func TestScramServer_Rejected(t *testing.T) {
+	tests := []struct {
+		name        string
+		clientFirst string
+		clientFinal string
+	}{
+		{name: "unknown user", clientFirst: "n,,n=alice,r=rOprNGfwEbeRWgbNEkqO"},
+		{name: "channel binding", clientFirst: "p=tls-unique,,n=user,r=rOprNGfwEbeRWgbNEkqO"},
+		{name: "missing nonce", clientFirst: "n,,n=user"},
+		{name: "invalid user name escape", clientFirst: "n,,n=us=er,r=rOprNGfwEbeRWgbNEkqO"},
+		{
+			name:        "wrong proof",
+			clientFirst: "n,,n=user,r=rOprNGfwEbeRWgbNEkqO",
+			clientFinal: "c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,p=AAAAZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ=",
+		},
+		{
+			name:        "nonce of another exchange",
+			clientFirst: "n,,n=user,r=rOprNGfwEbeRWgbNEkqO",
+			clientFinal: "c=biws,r=rOprNGfwEbeRWgbNEkqOother,p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ=",
+		},
+		{
+			name:        "channel binding of another header",
+			clientFirst: "n,,n=user,r=rOprNGfwEbeRWgbNEkqO",
+			clientFinal: "c=eSws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ=",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := rfc7677Server(t)
+
+			_, _, err := server.Next([]byte(tt.clientFirst))
+			if tt.clientFinal != "" {
+				if err != nil {
+					t.Fatalf("client first: %v", err)
+				}
+				_, _, err = server.Next([]byte(tt.clientFinal))
+			}
+			if err == nil {
+				t.Fatal("exchange succeeded, want it rejected")
+			}
+
+			// a rejected exchange can't continue
+			if _, _, err := server.Next([]byte(tt.clientFirst)); err == nil {
+				t.Error("rejected exchange accepted another message")
+			}
+			if server.Principal() != "" {
+				t.Errorf("principal = %q, want none", server.Principal())
+			}
+		})
+	}
+}
+
+func TestSession(t *testing.T) {
+	store := NewMemoryCredentialStore()
+	credential, err := NewScramCredential(ScramSha512, "secret", MinScramIterations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.SetScramCredential(ScramSha512, "alice", credential)
+	authenticator, err := NewAuthenticator([]string{ScramSha512}, store, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session := NewSession(authenticator)
+	if session.Principal() != Anonymous {
+		t.Errorf("principal before authenticating = %s, want %s", session.Principal(), Anonymous)
+	}
+	if _, err := session.Authenticate([]byte("n,,n=alice,r=nonce")); !errors.Is(err, utils.ErrIllegalSASLState) {
+		t.Errorf("authenticate without handshake = %v, want %v", err, utils.ErrIllegalSASLState)
+	}
+	if err := session.Handshake(ScramSha256); !errors.Is(err, utils.ErrUnsupportedSASLMechanism) {
+		t.Errorf("handshake of disabled mechanism = %v, want %v", err, utils.ErrUnsupportedSASLMechanism)
+	}
+	if err := session.Handshake(ScramSha512); err != nil {
+		t.Fatal(err)
+	}
+
+	client := newTestScramClient(t, ScramSha512, "alice", "secret")
+	serverFirst, err := session.Authenticate(client.first())
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverFinal, err := session.Authenticate(client.final(serverFirst))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.verify(serverFinal)
+
+	if got := session.Principal().String(); !session.Authenticated() || got != "User:alice" {
+		t.Errorf("session authenticated %v as %s, want User:alice", session.Authenticated(), got)
+	}
+	if err := session.Handshake(ScramSha512); !errors.Is(err, utils.ErrIllegalSASLState) {
+		t.Errorf("second handshake = %v, want %v", err, utils.ErrIllegalSASLState)
+	}
+}
+
+func TestSession_WrongPassword(t *testing.T) {
+	store := NewMemoryCredentialStore()
+	credential, err := NewScramCredential(ScramSha256, "secret", MinScramIterations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.SetScramCredential(ScramSha256, "alice", credential)
+	authenticator, err := NewAuthenticator([]string{ScramSha256}, store, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session := NewSession(authenticator)
+	if err := session.Handshake(ScramSha256); err != nil {
+		t.Fatal(err)
+	}
+
+	client := newTestScramClient(t, ScramSha256, "alice", "wrong")
+	serverFirst, err := session.Authenticate(client.first())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := session.Authenticate(client.final(serverFirst)); !errors.Is(err, utils.ErrSASLAuthenticationFailed) {
+		t.Errorf("authenticate with wrong password = %v, want %v", err, utils.ErrSASLAuthenticationFailed)
+	}
+	if session.Authenticated() || !session.Failed() {
+		t.Errorf("session authenticated = %v, failed = %v with the wrong password", session.Authenticated(), session.Failed())
+	}
+}
+
+// testScramClient is the client side of a SCRAM exchange, like Kafka clients implement it.
+type testScramClient struct {
+	t         *testing.T
+	mechanism string
+	username  string
+	password  string
+
+	clientFirstBare string
+	authMessage     string
+	saltedPassword  []byte
+}
+
+func newTestScramClient(t *testing.T, mechanism, username, password string) *testScramClient {
+	return &testScramClient{t: t, mechanism: mechanism, username: username, password: password}
+}
+
+func (c *testScramClient) first() []byte {
+	c.clientFirstBare = "n=" + escapeScramName(c.username) + ",r=clientnonce"
+	return []byte("n,," + c.clientFirstBare)
+}
+
+func (c *testScramClient) final(serverFirst []byte) []byte {
+	c.t.Helper()
+
+	attrs, err := parseScramAttributes(string(serverFirst))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	salt, err := base64.StdEncoding.DecodeString(attrs["s"])
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	iterations, err := strconv.Atoi(attrs["i"])
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	newHash := scramHashes[c.mechanism]
+	c.saltedPassword = scramHi(newHash, []byte(c.password), salt, iterations)
+	withoutProof := "c=biws,r=" + attrs["r"]
+	c.authMessage = c.clientFirstBare + "," + string(serverFirst) + "," + withoutProof
+
+	clientKey := scramHMAC(newHash, c.saltedPassword, []byte("Client Key"))
+	storedKey := newHash()
+	storedKey.Write(clientKey)
+	proof := scramHMAC(newHash, storedKey.Sum(nil), []byte(c.authMessage))
+	subtle.XORBytes(proof, proof, clientKey)
+
+	return []byte(withoutProof + ",p=" + base64.StdEncoding.EncodeToString(proof))
+}
+
+func (c *testScramClient) verify(serverFinal []byte) {
+	c.t.Helper()
+
+	newHash := scramHashes[c.mechanism]
+	serverKey := scramHMAC(newHash, c.saltedPassword, []byte("Server Key"))
+	want := "v=" + base64.StdEncoding.EncodeToString(scramHMAC(newHash, serverKey, []byte(c.authMessage)))
+	if string(serverFinal) != want {
+		c.t.Errorf("server final = %s, want %s", serverFinal, want)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"net"
 	"net/netip"
 	"net/url"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -15,7 +17,13 @@ import (
 
 type Broker struct {
 	BrokerID int32
-	Rack     *string
+	// GeneratedBrokerID is set if broker.id was not set, so the broker ID is the first ID above ReservedBrokerMaxID,
+	// until the broker registers with the lowest ID no other broker registered with
+	GeneratedBrokerID bool
+	// ReservedBrokerMaxID is the largest broker ID which can be configured, set by reserved.broker.max.id
+	ReservedBrokerMaxID int32
+	// Rack is the rack of the broker, set by broker.rack. It is nil if the broker is not assigned to a rack.
+	Rack *string
 	// https://docs.confluent.io/platform/current/installation/configuration/broker-configs.html#listeners
 	Listeners []Listener
 	// https://docs.confluent.io/platform/current/installation/configuration/broker-configs.html#advertised-listeners
@@ -32,6 +40,61 @@ type Listener struct {
 	ListenerName     string
 }
 
+// String returns the listener in the NAME://host:port form of listeners and advertised.listeners.
+// A listener without a name is named after its security protocol.
+func (l Listener) String() string {
+	name := strings.ToUpper(l.ListenerName)
+	if name == "" {
+		name = l.SecurityProtocol.String()
+	}
+
+	// interface hosts contain a colon, but are not enclosed in brackets like IPv6 addresses
+	port := strconv.Itoa(int(l.Port))
+	if strings.HasPrefix(l.Host, interfaceHostPrefix) {
+		return name + "://" + l.Host + ":" + port
+	}
+
+	return name + "://" + net.JoinHostPort(l.Host, port)
+}
+
+// Normalize returns the listener with its name lowercased and an IPv6 host in the canonical form of netip.Addr,
+// so [0:0:0:0:0:0:0:1] and [::1] are the same host. Other hosts are kept as is.
+func (l Listener) Normalize() Listener {
+	l.ListenerName = strings.ToLower(l.ListenerName)
+	if addr, err := netip.ParseAddr(l.Host); err == nil && addr.Is6() {
+		l.Host = addr.String()
+	}
+
+	return l
+}
+
+// Equal reports whether the listeners are the same once normalized.
+func (l Listener) Equal(other Listener) bool {
+	return l.Normalize() == other.Normalize()
+}
+
+// dedupeListeners normalizes the listeners and drops those equal to an earlier one, which happens easily when
+// the same listener is written twice in a different case or IPv6 form. Listeners which only share a name or port
+// are kept, so the validation reports the conflict.
+func dedupeListeners(key string, listeners []Listener) []Listener {
+	result := make([]Listener, 0, len(listeners))
+	for _, l := range listeners {
+		l = l.Normalize()
+		if slices.Contains(result, l) {
+			slog.Warn("ignoring duplicate listener", "key", key, "listener", l)
+			continue
+		}
+		result = append(result, l)
+	}
+
+	return result
+}
+
+// MarshalText encodes the listener as its String form, so it is a plain string in JSON logs.
+func (l Listener) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
 // var (
 // 	// by default in KRaft mode, generated broker IDs start from reserved.broker.max.id + 1,
 // 	// where reserved.broker.max.id=1000 if the property is not set.
@@ -40,48 +103,49 @@ type Listener struct {
 // )
 
 // NewBroker returns a new instance of Broker.
-// For now OpenTalaria does not support rack awareness, but this will change in the future.
 func NewBroker(env *viper.Viper) (*Broker, error) {
 	broker := Broker{}
 
-	listenerStr := env.GetString("listeners")
-	if listenerStr == "" {
+	listeners, protocols, err := readListeners(env, "listeners")
+	if err != nil {
+		return &Broker{}, err
+	}
+	if len(listeners) == 0 {
 		return &Broker{}, errors.New("no listeners set")
 	}
-	listeners := strings.Split(strings.ReplaceAll(listenerStr, " ", ""), ",")
 
-	var advertisedListeners []string
-	advListenerStr := env.GetString("advertised.listeners")
-	if advListenerStr == "" {
-		advertisedListeners = listeners
-	} else {
-		advertisedListeners = strings.Split(strings.ReplaceAll(advListenerStr, " ", ""), ",")
+	advertisedListeners, advertisedProtocols, err := readListeners(env, "advertised.listeners")
+	if err != nil {
+		return &Broker{}, err
+	}
+	if len(advertisedListeners) == 0 {
+		advertisedListeners, advertisedProtocols = listeners, protocols
 	}
 
-	listenersArray, err := parseListeners(env, listeners, false)
+	advertisedListeners, err = resolveExternalHost(env, advertisedListeners, newExternalHostResolver(env))
 	if err != nil {
 		return &Broker{}, err
 	}
-	broker.Listeners = append(broker.Listeners, listenersArray...)
 
-	err = validateListeners(&broker)
+	listenersArray, err := parseListeners(env, listeners, protocols, false)
 	if err != nil {
 		return &Broker{}, err
 	}
+	broker.Listeners = dedupeListeners("listeners", listenersArray)
 
-	advertisedListenersArr, err := parseListeners(env, advertisedListeners, true)
+	advertisedListenersArr, err := parseListeners(env, advertisedListeners, advertisedProtocols, true)
 	if err != nil {
 		return &Broker{}, err
 	}
-	broker.AdvertisedListeners = append(broker.AdvertisedListeners, advertisedListenersArr...)
+	broker.AdvertisedListeners = dedupeListeners("advertised.listeners", advertisedListenersArr)
 
-	err = validateAdvertisedListeners(&broker)
-	if err != nil {
+	// the problems of all listeners are reported at once, instead of one per start
+	if err := errors.Join(validateListeners(&broker), validateAdvertisedListeners(&broker)); err != nil {
 		return &Broker{}, err
 	}
 
 	brokerId := env.GetInt("broker.id")
-	reservedBrokerMaxId := env.GetInt("reserved.max.broker.id")
+	reservedBrokerMaxId := env.GetInt("reserved.broker.max.id")
 
 	// validate Broker ID
 	if brokerId > reservedBrokerMaxId {
@@ -92,9 +156,15 @@ func NewBroker(env *viper.Viper) (*Broker, error) {
 
 	if brokerId == -1 {
 		brokerId = reservedBrokerMaxId + 1
+		broker.GeneratedBrokerID = true
 	}
 
 	broker.BrokerID = int32(brokerId)
+	broker.ReservedBrokerMaxID = int32(reservedBrokerMaxId)
+
+	if rack := strings.TrimSpace(env.GetString("broker.rack")); rack != "" {
+		broker.Rack = &rack
+	}
 
 	if len(broker.Listeners) > 1 {
 		return &Broker{}, errors.New("OpenTalaria does not support more than one listener for now. See https://github.com/IBM/opentalaria/issues/18")
@@ -103,7 +173,57 @@ func NewBroker(env *viper.Viper) (*Broker, error) {
 	return &broker, nil
 }
 
-func parseListeners(env *viper.Viper, listeners []string, advertised bool) ([]Listener, error) {
+// listenerEntry is a listener in the structured form of listeners and advertised.listeners,
+// a list of objects which is easier to maintain in a config file than a comma separated string.
+type listenerEntry struct {
+	Name string
+	Host string
+	Port int32
+	// Protocol is the security protocol of the listener. It is only needed if the name is not a security protocol,
+	// and takes precedence over listener.security.protocol.map.
+	Protocol string
+}
+
+// readListeners returns the listeners of the key in the listener_name://host:port form, which is how they are set
+// in environment variables. Listeners in the structured form are converted to it, with the security protocols
+// they set returned by listener name.
+func readListeners(env *viper.Viper, key string) ([]string, map[string]string, error) {
+	if _, ok := env.Get(key).([]any); !ok {
+		listenerStr := strings.ReplaceAll(env.GetString(key), " ", "")
+		if listenerStr == "" {
+			return nil, nil, nil
+		}
+
+		return strings.Split(listenerStr, ","), nil, nil
+	}
+
+	var entries []listenerEntry
+	if err := env.UnmarshalKey(key, &entries); err != nil {
+		return nil, nil, fmt.Errorf("error reading %s: %w", key, err)
+	}
+
+	listeners := make([]string, 0, len(entries))
+	protocols := map[string]string{}
+	for _, e := range entries {
+		if e.Name == "" {
+			return nil, nil, fmt.Errorf("missing name of listener %s:%d in %s", e.Host, e.Port, key)
+		}
+
+		host := e.Host
+		if strings.Contains(host, ":") && !strings.HasPrefix(host, interfaceHostPrefix) {
+			host = "[" + host + "]"
+		}
+		listeners = append(listeners, fmt.Sprintf("%s://%s:%d", e.Name, host, e.Port))
+
+		if e.Protocol != "" {
+			protocols[e.Name] = e.Protocol
+		}
+	}
+
+	return listeners, protocols, nil
+}
+
+func parseListeners(env *viper.Viper, listeners []string, protocols map[string]string, advertised bool) ([]Listener, error) {
 	result := []Listener{}
 
 	for _, l := range listeners {
@@ -111,7 +231,8 @@ func parseListeners(env *viper.Viper, listeners []string, advertised bool) ([]Li
 			continue
 		}
 
-		listener, err := parseListener(env, l, advertised)
+		scheme, _, _ := strings.Cut(l, "://")
+		listener, err := parseListener(env, l, protocols[scheme], advertised)
 		if err != nil {
 			return []Listener{}, err
 		}
@@ -122,16 +243,59 @@ func parseListeners(env *viper.Viper, listeners []string, advertised bool) ([]Li
 	return result, nil
 }
 
-func parseListener(env *viper.Viper, l string, advertised bool) (Listener, error) {
+// expandListener replaces ${VAR} and $VAR references in the listener with values from the environment.
+// An unset variable is an error, since expanding it to an empty host would silently bind to a different address.
+func expandListener(l string) (string, error) {
+	var missing []string
+	expanded := os.Expand(l, func(key string) string {
+		value, ok := os.LookupEnv(key)
+		if !ok {
+			missing = append(missing, key)
+		}
+		return value
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("listener %s references unset environment variables: %s", l, strings.Join(missing, ", "))
+	}
+
+	return expanded, nil
+}
+
+// parseListener parses a listener of the form listener_name://host:port. The security protocol is looked up
+// by listener name, unless protocol is set.
+func parseListener(env *viper.Viper, l string, protocol string, advertised bool) (Listener, error) {
+	l, err := expandListener(l)
+	if err != nil {
+		return Listener{}, err
+	}
+
+	// hosts of the form if:<name> are not valid URL hosts, so they are cut out before parsing the rest of the listener
+	var interfaceHost string
+	if scheme, hostPort, ok := strings.Cut(l, "://"); ok && strings.HasPrefix(hostPort, interfaceHostPrefix) {
+		i := strings.LastIndex(hostPort, ":")
+		if i <= len(interfaceHostPrefix) {
+			return Listener{}, fmt.Errorf("missing port in listener %s", l)
+		}
+		interfaceHost = hostPort[:i]
+		l = scheme + "://" + hostPort[i:]
+	}
+
 	listener, err := url.Parse(l)
 	if err != nil {
 		return Listener{}, err
 	}
 
 	// parse the security protocol from the url scheme.
-	// If the protocol is unknown treat the scheme as broker name and check the listener.security.protocol.map
-	listenerName, securityProtocol, err := getBrokerNameComponents(env, listener.Scheme)
-	if err != nil {
+	// If the protocol is unknown treat the scheme as broker name and check the listener.security.protocol.map,
+	// unless the security protocol was set with the listener.
+	listenerName, securityProtocol := listener.Scheme, UNDEFINED_SECURITY_PROTOCOL
+	if protocol != "" {
+		var ok bool
+		if securityProtocol, ok = ParseSecurityProtocol(protocol); !ok {
+			return Listener{}, fmt.Errorf("unknown security protocol %s for listener %s", protocol, listener.Scheme)
+		}
+	} else if listenerName, securityProtocol, err = getBrokerNameComponents(env, listener.Scheme); err != nil {
 		return Listener{}, err
 	}
 
@@ -145,6 +309,11 @@ func parseListener(env *viper.Viper, l string, advertised bool) (Listener, error
 		return Listener{}, err
 	}
 
+	// the interface is resolved to its address when the server binds the listener
+	if interfaceHost != "" {
+		host = interfaceHost
+	}
+
 	// The empty host was most likely inherited from the listeners variable.
 	// Since it's not allowed to advertise an empty host, we will get the IPv4 address of the first network interface.
 	if advertised && host == "" {
@@ -219,22 +388,24 @@ func getBrokerNameComponents(env *viper.Viper, s string) (string, SecurityProtoc
 
 // validateListeners performs common checks on the listeners as per Kafka specification https://kafka.apache.org/documentation/#brokerconfigs_listeners.
 // Broker name and port have to be unique. The exception is if the host for two entries is IPv4 and IPv6 respectively.
+// The problems of all listeners are joined into the returned error.
 func validateListeners(b *Broker) error {
 	ports := map[int32]string{}
 	listenerNames := map[string]string{}
 
+	var errs []error
 	for _, listener := range b.Listeners {
 		// Check uniqueness for ports
 		if val, ok := ports[listener.Port]; ok {
 			if areIpProtocolsSame(listener.Host, val) {
-				return fmt.Errorf("listener port is not unique for listener %s", listener.ListenerName)
+				errs = append(errs, fmt.Errorf("listener port is not unique for listener %s", listener))
 			}
 		}
 
 		// Check uniqueness for broker names
 		if val, ok := listenerNames[listener.ListenerName]; ok {
 			if areIpProtocolsSame(listener.Host, val) {
-				return fmt.Errorf("listener name is not unique for listener %s", listener.ListenerName)
+				errs = append(errs, fmt.Errorf("listener name is not unique for listener %s", listener))
 			}
 		}
 
@@ -242,7 +413,7 @@ func validateListeners(b *Broker) error {
 		listenerNames[listener.ListenerName] = listener.Host
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 func areIpProtocolsSame(host1, host2 string) bool {
@@ -257,27 +428,191 @@ func areIpProtocolsSame(host1, host2 string) bool {
 }
 
 // validateAdvertisedListeners performs common checks on the advertised listers as per Kafka specification https://kafka.apache.org/documentation/#brokerconfigs_advertised.listeners.
-// Unlike with listeners, having duplicated ports is allowed. The only constraint is advertising to 0.0.0.0 is not allowed.
+// Unlike with listeners, having duplicated ports is allowed. Advertising to 0.0.0.0 or :: is not allowed and
+// each listener name can be advertised only once, otherwise clients can't tell which host:port to use for it.
+// An advertised listener must use the same security protocol as the listener with the same name, or clients get a protocol mismatch.
+// The problems of all advertised listeners are joined into the returned error.
 func validateAdvertisedListeners(b *Broker) error {
+	listenerNames := map[string]bool{}
+
+	securityProtocols := map[string]SecurityProtocol{}
+	for _, listener := range b.Listeners {
+		securityProtocols[listener.ListenerName] = listener.SecurityProtocol
+	}
+
+	var errs []error
 	for _, listener := range b.AdvertisedListeners {
-		if strings.EqualFold(listener.Host, "0.0.0.0") || listener.Host == "" {
-			return fmt.Errorf("advertising listener on 0.0.0.0 address is not allowed for listener %s", listener.ListenerName)
+		// a listener may bind to all interfaces, but clients need the address of one of them
+		if ip := net.ParseIP(listener.Host); listener.Host == "" || (ip != nil && ip.IsUnspecified()) {
+			errs = append(errs, fmt.Errorf("advertising listener on the wildcard address is not allowed for listener %s, set advertised.listeners to the address clients connect to", listener))
+		}
+
+		if listenerNames[listener.ListenerName] {
+			errs = append(errs, fmt.Errorf("advertised listener name is not unique for listener %s", listener))
+		}
+		listenerNames[listener.ListenerName] = true
+
+		if securityProtocol, ok := securityProtocols[listener.ListenerName]; ok && securityProtocol != listener.SecurityProtocol {
+			errs = append(errs, fmt.Errorf("advertised listener %s uses security protocol %s, but the listener with the same name uses %s",
+				listener, listener.SecurityProtocol, securityProtocol))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ResolveEphemeralPort sets the port of the listener with the given name, if it was configured with port 0.
+// Advertised listeners with the same name and port 0 mirror the listener, so they are updated as well.
+// This should be called once the OS has assigned an ephemeral port to the listener's socket.
+func (b *Broker) ResolveEphemeralPort(listenerName string, port int32) {
+	for i, listener := range b.Listeners {
+		if listener.ListenerName == listenerName && listener.Port == 0 {
+			b.Listeners[i].Port = port
+		}
+	}
+
+	for i, listener := range b.AdvertisedListeners {
+		if listener.ListenerName == listenerName && listener.Port == 0 {
+			b.AdvertisedListeners[i].Port = port
+		}
+	}
+}
+
+// AdvertisedListenerForPort returns the advertised listener of the listener bound to the port,
+// which is the address that clients connected to this port should use to reach the broker.
+// If no listener is bound to the port, for example in unit tests without a connection, the first advertised listener is returned.
+func (b *Broker) AdvertisedListenerForPort(port int32) Listener {
+	for _, listener := range b.Listeners {
+		if listener.Port != port {
+			continue
+		}
+
+		for _, advertisedListener := range b.AdvertisedListeners {
+			if advertisedListener.ListenerName == listener.ListenerName {
+				return advertisedListener
+			}
+		}
+	}
+
+	if len(b.AdvertisedListeners) == 0 {
+		return Listener{}
+	}
+
+	return b.AdvertisedListeners[0]
+}
+
+// ResolveInterfaceHosts replaces the hosts of the form if:<name> in listeners and advertised listeners
+// with the address of the network interface, so the resolved address is advertised to clients.
+// This should be called before the server binds the listeners.
+func (b *Broker) ResolveInterfaceHosts() error {
+	for _, listeners := range [][]Listener{b.Listeners, b.AdvertisedListeners} {
+		for i := range listeners {
+			host, err := ResolveInterfaceHost(listeners[i].Host)
+			if err != nil {
+				return fmt.Errorf("error resolving the host of listener %s: %w", listeners[i].ListenerName, err)
+			}
+			listeners[i].Host = host
 		}
 	}
 
 	return nil
 }
 
+// ResolveInterfaceHost returns the first IPv4 address of the network interface for hosts of the form if:<name>,
+// or its first IPv6 address if it has no IPv4 address. Any other host is returned as is.
+func ResolveInterfaceHost(host string) (string, error) {
+	name, ok := strings.CutPrefix(host, interfaceHostPrefix)
+	if !ok {
+		return host, nil
+	}
+
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return "", fmt.Errorf("network interface %s not found: %w", name, err)
+	}
+
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return "", err
+	}
+
+	var ipv6 string
+	for _, addr := range addrs {
+		ipnet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+
+		if ip := ipnet.IP.To4(); ip != nil {
+			return ip.String(), nil
+		}
+
+		// link-local IPv6 addresses can't be used without a zone, which clients don't know
+		if ipv6 == "" && !ipnet.IP.IsLinkLocalUnicast() {
+			ipv6 = ipnet.IP.String()
+		}
+	}
+
+	if ipv6 == "" {
+		return "", fmt.Errorf("network interface %s has no IPv4 or IPv6 address", name)
+	}
+
+	return ipv6, nil
+}
+
 /**
  * Unit test helpers
  */
 
-// MockBroker generates a mock object used for unit testing.
-func MockBroker() *Broker {
+// MockBrokerOption customizes the broker of MockBroker.
+type MockBrokerOption func(*Broker)
+
+// WithBrokerID sets the ID of the mock broker.
+func WithBrokerID(id int32) MockBrokerOption {
+	return func(b *Broker) {
+		b.BrokerID = id
+	}
+}
+
+// WithRack assigns the mock broker to the rack.
+func WithRack(rack string) MockBrokerOption {
+	return func(b *Broker) {
+		b.Rack = &rack
+	}
+}
+
+// WithListener adds a listener to the mock broker. Like with advertised.listeners, the listeners are advertised as is,
+// unless advertised listeners are set with WithAdvertisedListener.
+func WithListener(l Listener) MockBrokerOption {
+	return func(b *Broker) {
+		b.Listeners = append(b.Listeners, l)
+	}
+}
+
+// WithAdvertisedListener adds an advertised listener to the mock broker.
+func WithAdvertisedListener(l Listener) MockBrokerOption {
+	return func(b *Broker) {
+		b.AdvertisedListeners = append(b.AdvertisedListeners, l)
+	}
+}
+
+// MockBroker generates a mock object used for unit testing. Without options it is broker 1 without rack,
+// with a PLAINTEXT listener on localhost:9092, which is advertised as 127.0.0.1:9092.
+func MockBroker(opts ...MockBrokerOption) *Broker {
 	broker := Broker{}
 
 	broker.BrokerID = 1
 	broker.Rack = nil
+	for _, opt := range opts {
+		opt(&broker)
+	}
+	if len(broker.Listeners) > 0 {
+		if len(broker.AdvertisedListeners) == 0 {
+			broker.AdvertisedListeners = slices.Clone(broker.Listeners)
+		}
+		return &broker
+	}
+
 	broker.Listeners = append(broker.Listeners, Listener{
 		Host:             "localhost",
 		Port:             9092,
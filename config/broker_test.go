@@ -1,7 +1,10 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -58,6 +61,52 @@ func Test_parseListener(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "listener bound to an interface",
+			args: args{
+				l:           "PLAINTEXT://if:eth1:9092",
+				securityMap: "",
+			},
+			want: Listener{
+				Host:             "if:eth1",
+				Port:             9092,
+				SecurityProtocol: PLAINTEXT,
+				ListenerName:     "plaintext",
+			},
+			wantErr: false,
+		},
+		{
+			name: "listener bound to an interface without port",
+			args: args{
+				l:           "PLAINTEXT://if:eth1",
+				securityMap: "",
+			},
+			want:    Listener{},
+			wantErr: true,
+		},
+		{
+			name: "listener with a host from the environment",
+			args: args{
+				l:           "PLAINTEXT://${TEST_LISTENER_HOST}:9092",
+				securityMap: "",
+			},
+			want: Listener{
+				Host:             "broker-1.example.com",
+				Port:             9092,
+				SecurityProtocol: PLAINTEXT,
+				ListenerName:     "plaintext",
+			},
+			wantErr: false,
+		},
+		{
+			name: "listener with an unset environment variable",
+			args: args{
+				l:           "PLAINTEXT://${TEST_LISTENER_UNSET_HOST}:9092",
+				securityMap: "",
+			},
+			want:    Listener{},
+			wantErr: true,
+		},
 		{
 			name: "custom listener name not in security map",
 			args: args{
@@ -100,13 +149,14 @@ func Test_parseListener(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Setenv("OT_LISTENERS", "PLAINTEXT://:9092")
 			t.Setenv("OT_LISTENER_SECURITY_PROTOCOL_MAP", tt.args.securityMap)
+			t.Setenv("TEST_LISTENER_HOST", "broker-1.example.com")
 
 			conf, err := NewConfig("")
 			if err != nil {
 				t.Error(err)
 			}
 
-			got, err := parseListener(conf.Env, tt.args.l, false)
+			got, err := parseListener(conf.Env, tt.args.l, "", false)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("parseListener() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -118,6 +168,46 @@ func Test_parseListener(t *testing.T) {
 	}
 }
 
+func TestListener_String(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://:9092")
+	t.Setenv("OT_LISTENER_SECURITY_PROTOCOL_MAP", "CUSTOM:SASL_SSL")
+
+	conf, err := NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		listener Listener
+		want     string
+	}{
+		{listener: Listener{Host: "localhost", Port: 9092, SecurityProtocol: PLAINTEXT, ListenerName: "plaintext"}, want: "PLAINTEXT://localhost:9092"},
+		{listener: Listener{Host: "", Port: 9093, SecurityProtocol: SSL, ListenerName: "ssl"}, want: "SSL://:9093"},
+		{listener: Listener{Host: "::1", Port: 9094, SecurityProtocol: SASL_SSL, ListenerName: "custom"}, want: "CUSTOM://[::1]:9094"},
+		{listener: Listener{Host: "if:eth1", Port: 9095, SecurityProtocol: PLAINTEXT, ListenerName: "plaintext"}, want: "PLAINTEXT://if:eth1:9095"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.listener.String(); got != tt.want {
+				t.Fatalf("String() = %q, want %q", got, tt.want)
+			}
+
+			parsed, err := parseListener(conf.Env, tt.listener.String(), "", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parsed != tt.listener {
+				t.Errorf("parseListener(String()) = %+v, want %+v", parsed, tt.listener)
+			}
+
+			text, err := tt.listener.MarshalText()
+			if err != nil || string(text) != tt.want {
+				t.Errorf("MarshalText() = %q, %v, want %q", text, err, tt.want)
+			}
+		})
+	}
+}
+
 func TestBroker_validateListeners(t *testing.T) {
 	type fields struct {
 		BrokerID            int32
@@ -323,6 +413,73 @@ func TestBroker_validateAdvertisedListeners(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Two listeners with same name",
+			fields: fields{
+				BrokerID: 0,
+				AdvertisedListeners: []Listener{
+					{
+						ListenerName:     "client",
+						Host:             "example.com",
+						Port:             1234,
+						SecurityProtocol: PLAINTEXT,
+					},
+					{
+						ListenerName:     "client",
+						Host:             "other.example.com",
+						Port:             5678,
+						SecurityProtocol: PLAINTEXT,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Same name and security protocol as the listener",
+			fields: fields{
+				BrokerID: 0,
+				Listeners: []Listener{
+					{
+						ListenerName:     "INTERNAL",
+						Host:             "",
+						Port:             9093,
+						SecurityProtocol: SSL,
+					},
+				},
+				AdvertisedListeners: []Listener{
+					{
+						ListenerName:     "INTERNAL",
+						Host:             "example.com",
+						Port:             9093,
+						SecurityProtocol: SSL,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Same name, different security protocol than the listener",
+			fields: fields{
+				BrokerID: 0,
+				Listeners: []Listener{
+					{
+						ListenerName:     "INTERNAL",
+						Host:             "",
+						Port:             9093,
+						SecurityProtocol: SSL,
+					},
+				},
+				AdvertisedListeners: []Listener{
+					{
+						ListenerName:     "INTERNAL",
+						Host:             "example.com",
+						Port:             9093,
+						SecurityProtocol: PLAINTEXT,
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "Invalid binding, 0.0.0.0",
 			fields: fields{
@@ -338,6 +495,21 @@ func TestBroker_validateAdvertisedListeners(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Invalid binding, ::",
+			fields: fields{
+				BrokerID: 0,
+				AdvertisedListeners: []Listener{
+					{
+						ListenerName:     "client",
+						Host:             "::",
+						Port:             1234,
+						SecurityProtocol: PLAINTEXT,
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "Invalid binding, empty host",
 			fields: fields{
@@ -368,3 +540,301 @@ func TestBroker_validateAdvertisedListeners(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveInterfaceHost(t *testing.T) {
+	tests := []struct {
+		name    string
+		host    string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain host", host: "localhost", want: "localhost"},
+		{name: "loopback interface", host: "if:lo", want: "127.0.0.1"},
+		{name: "unknown interface", host: "if:does-not-exist0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveInterfaceHost(tt.host)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveInterfaceHost() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveInterfaceHost() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBroker_ResolveInterfaceHosts(t *testing.T) {
+	b := &Broker{
+		Listeners:           []Listener{{Host: "if:lo", Port: 9092, ListenerName: "plaintext"}},
+		AdvertisedListeners: []Listener{{Host: "if:lo", Port: 9092, ListenerName: "plaintext"}},
+	}
+
+	if err := b.ResolveInterfaceHosts(); err != nil {
+		t.Fatal(err)
+	}
+
+	if b.Listeners[0].Host != "127.0.0.1" || b.AdvertisedListeners[0].Host != "127.0.0.1" {
+		t.Errorf("hosts = %q, %q, want the address of the loopback interface", b.Listeners[0].Host, b.AdvertisedListeners[0].Host)
+	}
+}
+
+func TestNewBroker_StructuredListeners(t *testing.T) {
+	want, err := NewConfig("testdata/config.yaml")
+	if err != nil {
+		t.Fatalf("error loading listeners string: %v", err)
+	}
+
+	got, err := NewConfig("testdata/listeners.yaml")
+	if err != nil {
+		t.Fatalf("error loading structured listeners: %v", err)
+	}
+
+	if !reflect.DeepEqual(got.Broker, want.Broker) {
+		t.Errorf("structured listeners = %+v, want %+v", got.Broker, want.Broker)
+	}
+}
+
+func TestNewBroker_StructuredListenerProtocol(t *testing.T) {
+	// the security protocol of a custom listener name can be set with the listener, instead of listener.security.protocol.map
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(configFile, []byte(`
+listeners:
+  - name: INTERNAL
+    host: localhost
+    port: 9093
+    protocol: SASL_SSL
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf, err := NewConfig(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Listener{{Host: "localhost", Port: 9093, SecurityProtocol: SASL_SSL, ListenerName: "internal"}}
+	if !reflect.DeepEqual(conf.Broker.Listeners, want) || !reflect.DeepEqual(conf.Broker.AdvertisedListeners, want) {
+		t.Errorf("listeners = %+v, advertised = %+v, want %+v", conf.Broker.Listeners, conf.Broker.AdvertisedListeners, want)
+	}
+}
+
+func TestNewBroker_Rack(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:9092")
+
+	conf, err := NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.Broker.Rack != nil {
+		t.Errorf("Rack = %q, want nil without broker.rack", *conf.Broker.Rack)
+	}
+
+	t.Setenv("OT_BROKER_RACK", "rack-1")
+	conf, err = NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.Broker.Rack == nil || *conf.Broker.Rack != "rack-1" {
+		t.Errorf("Rack = %v, want rack-1", conf.Broker.Rack)
+	}
+}
+
+func TestNewBroker_JoinsListenerProblems(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:9092,SSL://localhost:9092")
+	t.Setenv("OT_ADVERTISED_LISTENERS", "PLAINTEXT://0.0.0.0:9092,SSL://broker.example.com:9093")
+
+	_, err := NewConfig("")
+	if err == nil {
+		t.Fatal("NewConfig() should fail for the duplicated port and the advertised 0.0.0.0 address")
+	}
+
+	for _, want := range []string{
+		"listener port is not unique for listener SSL://localhost:9092",
+		"advertising listener on the wildcard address is not allowed for listener PLAINTEXT://0.0.0.0:9092",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+}
+
+func TestMockBroker(t *testing.T) {
+	internal := Listener{Host: "localhost", Port: 9093, SecurityProtocol: SASL_PLAINTEXT, ListenerName: "internal"}
+	external := Listener{Host: "0.0.0.0", Port: 9094, SecurityProtocol: SASL_SSL, ListenerName: "external"}
+	advertised := Listener{Host: "broker.example.com", Port: 9094, SecurityProtocol: SASL_SSL, ListenerName: "external"}
+	rack := "r1"
+
+	tests := []struct {
+		name           string
+		opts           []MockBrokerOption
+		wantID         int32
+		wantRack       *string
+		wantListeners  []Listener
+		wantAdvertised []Listener
+	}{
+		{
+			name:           "defaults",
+			wantID:         1,
+			wantListeners:  []Listener{{Host: "localhost", Port: 9092, SecurityProtocol: PLAINTEXT, ListenerName: "PLAINTEXT"}},
+			wantAdvertised: []Listener{{Host: "127.0.0.1", Port: 9092, SecurityProtocol: PLAINTEXT, ListenerName: "PLAINTEXT"}},
+		},
+		{
+			name:           "listeners are advertised as is",
+			opts:           []MockBrokerOption{WithListener(internal), WithListener(external), WithRack(rack)},
+			wantID:         1,
+			wantRack:       &rack,
+			wantListeners:  []Listener{internal, external},
+			wantAdvertised: []Listener{internal, external},
+		},
+		{
+			name:           "advertised listener",
+			opts:           []MockBrokerOption{WithBrokerID(3), WithListener(external), WithAdvertisedListener(advertised)},
+			wantID:         3,
+			wantListeners:  []Listener{external},
+			wantAdvertised: []Listener{advertised},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MockBroker(tt.opts...)
+			want := &Broker{BrokerID: tt.wantID, Rack: tt.wantRack, Listeners: tt.wantListeners, AdvertisedListeners: tt.wantAdvertised}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("MockBroker() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestNewConfig_ListenersSources(t *testing.T) {
+	// testdata/config.yaml binds the listener to port 9092 and advertises localhost:9092
+	tests := []struct {
+		name     string
+		env      map[string]string
+		opts     []Option
+		wantPort int32
+	}{
+		{name: "config file", wantPort: 9092},
+		{name: "environment variable overrides the file", env: map[string]string{"OT_LISTENERS": "PLAINTEXT://:9093"}, wantPort: 9093},
+		{
+			name:     "custom prefix",
+			env:      map[string]string{"OT_LISTENERS": "PLAINTEXT://:9093", "TALARIA_LISTENERS": "PLAINTEXT://:9094"},
+			opts:     []Option{WithEnvPrefix("TALARIA")},
+			wantPort: 9094,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			conf, err := NewConfigWithOptions("testdata/config.yaml", tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(conf.Broker.Listeners) != 1 || conf.Broker.Listeners[0].Port != tt.wantPort {
+				t.Errorf("listeners = %v, want a listener on port %d", conf.Broker.Listeners, tt.wantPort)
+			}
+			// the advertised listener is still read from the file
+			if len(conf.Broker.AdvertisedListeners) != 1 || conf.Broker.AdvertisedListeners[0].Host != "localhost" {
+				t.Errorf("advertised listeners = %v, want localhost from the config file", conf.Broker.AdvertisedListeners)
+			}
+		})
+	}
+}
+
+func TestNewBroker_BrokerID(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:9092")
+
+	tests := []struct {
+		name          string
+		brokerID      string
+		reservedMaxID string
+		wantID        int32
+		wantGenerated bool
+		wantErr       bool
+	}{
+		{name: "generated above the default reserved IDs", wantID: 1001, wantGenerated: true},
+		{name: "configured", brokerID: "5", wantID: 5},
+		{name: "generated above the reserved IDs", reservedMaxID: "100", wantID: 101, wantGenerated: true},
+		{name: "configured above the reserved IDs", brokerID: "1001", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.brokerID != "" {
+				t.Setenv("OT_BROKER_ID", tt.brokerID)
+			}
+			if tt.reservedMaxID != "" {
+				t.Setenv("OT_RESERVED_BROKER_MAX_ID", tt.reservedMaxID)
+			}
+
+			conf, err := NewConfig("")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if conf.Broker.BrokerID != tt.wantID || conf.Broker.GeneratedBrokerID != tt.wantGenerated {
+				t.Errorf("broker ID = %d, generated = %v, want %d, generated = %v", conf.Broker.BrokerID, conf.Broker.GeneratedBrokerID, tt.wantID, tt.wantGenerated)
+			}
+		})
+	}
+}
+
+func TestListener_Equal(t *testing.T) {
+	listener := Listener{Host: "::1", Port: 9092, SecurityProtocol: PLAINTEXT, ListenerName: "plaintext"}
+
+	tests := []struct {
+		name  string
+		other Listener
+		want  bool
+	}{
+		{name: "same", other: listener, want: true},
+		{name: "name in upper case", other: Listener{Host: "::1", Port: 9092, SecurityProtocol: PLAINTEXT, ListenerName: "PLAINTEXT"}, want: true},
+		{name: "expanded IPv6 address", other: Listener{Host: "0:0:0:0:0:0:0:1", Port: 9092, SecurityProtocol: PLAINTEXT, ListenerName: "plaintext"}, want: true},
+		{name: "other IPv6 address", other: Listener{Host: "::2", Port: 9092, SecurityProtocol: PLAINTEXT, ListenerName: "plaintext"}},
+		{name: "IPv4 loopback", other: Listener{Host: "127.0.0.1", Port: 9092, SecurityProtocol: PLAINTEXT, ListenerName: "plaintext"}},
+		{name: "other port", other: Listener{Host: "::1", Port: 9093, SecurityProtocol: PLAINTEXT, ListenerName: "plaintext"}},
+		{name: "other security protocol", other: Listener{Host: "::1", Port: 9092, SecurityProtocol: SSL, ListenerName: "plaintext"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := listener.Equal(tt.other); got != tt.want {
+				t.Errorf("Equal(%+v) = %v, want %v", tt.other, got, tt.want)
+			}
+			if got := tt.other.Equal(listener); got != tt.want {
+				t.Errorf("Equal is not symmetric for %+v", tt.other)
+			}
+		})
+	}
+}
+
+func TestNewBroker_DedupesListeners(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://[0:0:0:0:0:0:0:1]:9092,plaintext://[::1]:9092")
+	t.Setenv("OT_ADVERTISED_LISTENERS", "PLAINTEXT://[2001:DB8::1]:9092,PLAINTEXT://[2001:db8:0:0::1]:9092")
+
+	conf, err := NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantListeners := []Listener{{Host: "::1", Port: 9092, SecurityProtocol: PLAINTEXT, ListenerName: "plaintext"}}
+	if !reflect.DeepEqual(conf.Broker.Listeners, wantListeners) {
+		t.Errorf("listeners = %+v, want %+v", conf.Broker.Listeners, wantListeners)
+	}
+	wantAdvertised := []Listener{{Host: "2001:db8::1", Port: 9092, SecurityProtocol: PLAINTEXT, ListenerName: "plaintext"}}
+	if !reflect.DeepEqual(conf.Broker.AdvertisedListeners, wantAdvertised) {
+		t.Errorf("advertised listeners = %+v, want %+v", conf.Broker.AdvertisedListeners, wantAdvertised)
+	}
+
+	// listeners which share the name and port on different hosts still conflict
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://[::1]:9092,plaintext://[::2]:9092")
+	t.Setenv("OT_ADVERTISED_LISTENERS", "")
+	if _, err := NewConfig(""); err == nil || !strings.Contains(err.Error(), "listener port is not unique") {
+		t.Errorf("NewConfig() error = %v, want the conflicting listeners reported", err)
+	}
+}
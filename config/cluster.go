@@ -0,0 +1,203 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+)
+
+type Cluster struct {
+	ClusterID string
+	// Roles are the KRaft roles of the local node, set by process.roles
+	Roles map[ProcessRole]struct{}
+	// QuorumVoters are the controllers which vote in the KRaft quorum, set by controller.quorum.voters.
+	// There are none for a standalone node, which is the only voter.
+	QuorumVoters []Voter
+	// ControllerListenerNames are the names of the listeners the controllers use, set by controller.listener.names
+	ControllerListenerNames []string
+}
+
+// Voter is a controller of the KRaft quorum, with the address of its controller listener.
+type Voter struct {
+	ID   int32
+	Host string
+	Port int32
+}
+
+// ProcessRole is a role of a node in a KRaft cluster.
+type ProcessRole string
+
+const (
+	// RoleBroker nodes serve the requests of clients
+	RoleBroker ProcessRole = "broker"
+	// RoleController nodes are voters of the KRaft quorum, which manages the cluster metadata
+	RoleController ProcessRole = "controller"
+)
+
+// NewCluster reads the settings of the cluster. The cluster ID is generated if cluster.id is not set.
+func NewCluster(env *viper.Viper) (*Cluster, error) {
+	cluster := Cluster{}
+
+	roles, err := readRoles(env)
+	if err != nil {
+		return &Cluster{}, err
+	}
+	cluster.Roles = roles
+
+	voters, err := parseVoters(readList(env, "controller.quorum.voters"))
+	if err != nil {
+		return &Cluster{}, err
+	}
+	cluster.QuorumVoters = voters
+
+	for _, name := range readList(env, "controller.listener.names") {
+		cluster.ControllerListenerNames = append(cluster.ControllerListenerNames, strings.ToLower(name))
+	}
+
+	cluster.ClusterID = env.GetString("cluster.id")
+	if cluster.ClusterID == "" {
+		uid, err := uuid.NewV6()
+		if err != nil {
+			return &Cluster{}, err
+		}
+
+		cluster.ClusterID = uid.String()
+	}
+
+	return &cluster, nil
+}
+
+// readList returns the values of a property which is a comma separated string in environment variables
+// or a list in the config file, without empty values.
+func readList(env *viper.Viper, key string) []string {
+	var values []string
+	if list, ok := env.Get(key).([]any); ok {
+		for _, v := range list {
+			values = append(values, fmt.Sprint(v))
+		}
+	} else {
+		values = strings.Split(env.GetString(key), ",")
+	}
+
+	var result []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+// readRoles parses process.roles into the set of roles of the local node.
+func readRoles(env *viper.Viper) (map[ProcessRole]struct{}, error) {
+	roles := map[ProcessRole]struct{}{}
+	for _, v := range readList(env, "process.roles") {
+		role := ProcessRole(strings.ToLower(v))
+		switch role {
+		case RoleBroker, RoleController:
+			roles[role] = struct{}{}
+		default:
+			return nil, fmt.Errorf("invalid process.roles %q, expected broker, controller or broker,controller", v)
+		}
+	}
+	if len(roles) == 0 {
+		return nil, fmt.Errorf("process.roles must contain broker, controller or both")
+	}
+
+	return roles, nil
+}
+
+// parseVoters parses the voters of controller.quorum.voters, which have the form id@host:port, like 1@host1:9093.
+// IPv6 hosts are enclosed in brackets, like 2@[::1]:9093.
+func parseVoters(values []string) ([]Voter, error) {
+	var voters []Voter
+	ids := map[int32]bool{}
+	for _, v := range values {
+		id, hostPort, ok := strings.Cut(v, "@")
+		if !ok {
+			return nil, fmt.Errorf("invalid controller.quorum.voters entry %q, expected the form id@host:port", v)
+		}
+
+		parsedID, err := strconv.ParseInt(id, 10, 32)
+		if err != nil || parsedID < 0 {
+			return nil, fmt.Errorf("invalid voter id %q in controller.quorum.voters entry %q", id, v)
+		}
+
+		host, port, err := net.SplitHostPort(hostPort)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address in controller.quorum.voters entry %q: %w", v, err)
+		}
+		if host == "" {
+			return nil, fmt.Errorf("missing host in controller.quorum.voters entry %q", v)
+		}
+
+		parsedPort, err := strconv.ParseUint(port, 10, 16)
+		if err != nil || parsedPort == 0 {
+			return nil, fmt.Errorf("invalid port %q in controller.quorum.voters entry %q", port, v)
+		}
+
+		if ids[int32(parsedID)] {
+			return nil, fmt.Errorf("duplicate voter id %d in controller.quorum.voters", parsedID)
+		}
+		ids[int32(parsedID)] = true
+
+		voters = append(voters, Voter{ID: int32(parsedID), Host: host, Port: int32(parsedPort)})
+	}
+
+	return voters, nil
+}
+
+// validateController checks that a controller is one of the voters, if voters are set, and that the other voters
+// can reach it on a controller listener. A standalone node exchanges no quorum messages, so it needs no controller listener.
+func validateController(cluster *Cluster, broker *Broker) error {
+	if !cluster.HasRole(RoleController) {
+		return nil
+	}
+
+	if len(cluster.QuorumVoters) > 0 && !slices.ContainsFunc(cluster.QuorumVoters, func(v Voter) bool { return v.ID == broker.BrokerID }) {
+		return fmt.Errorf("the controller %d is not one of the voters in controller.quorum.voters", broker.BrokerID)
+	}
+	if cluster.Standalone(broker.BrokerID) {
+		return nil
+	}
+
+	if len(cluster.ControllerListenerNames) == 0 {
+		return errors.New("controller.listener.names must be set on controllers")
+	}
+	for _, l := range broker.Listeners {
+		if cluster.IsControllerListener(l.ListenerName) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("none of the listeners is a controller listener of controller.listener.names %s", strings.Join(cluster.ControllerListenerNames, ","))
+}
+
+// IsControllerListener reports whether the listener is one of the controller listeners.
+func (c *Cluster) IsControllerListener(listenerName string) bool {
+	return slices.Contains(c.ControllerListenerNames, strings.ToLower(listenerName))
+}
+
+// HasRole reports whether the local node has the role.
+func (c *Cluster) HasRole(role ProcessRole) bool {
+	_, ok := c.Roles[role]
+	return ok
+}
+
+// Standalone reports whether the local node is both broker and controller without other voters,
+// so it forms a single-node cluster on its own.
+// A standalone node needs no quorum negotiation, it is the leader of the metadata quorum as soon as it starts.
+func (c *Cluster) Standalone(nodeID int32) bool {
+	if !c.HasRole(RoleBroker) || !c.HasRole(RoleController) {
+		return false
+	}
+
+	return len(c.QuorumVoters) == 0 || (len(c.QuorumVoters) == 1 && c.QuorumVoters[0].ID == nodeID)
+}
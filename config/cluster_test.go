@@ -0,0 +1,137 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestNewCluster_Roles(t *testing.T) {
+	tests := []struct {
+		roles          string
+		wantErr        bool
+		wantBroker     bool
+		wantController bool
+	}{
+		{roles: "broker,controller", wantBroker: true, wantController: true},
+		{roles: " Controller , broker ", wantBroker: true, wantController: true},
+		{roles: "broker", wantBroker: true},
+		{roles: "controller", wantController: true},
+		{roles: "", wantErr: true},
+		{roles: "broker,zookeeper", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.roles, func(t *testing.T) {
+			env := viper.New()
+			env.Set("process.roles", tt.roles)
+
+			cluster, err := NewCluster(env)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("roles %v were accepted", cluster.Roles)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cluster.HasRole(RoleBroker) != tt.wantBroker || cluster.HasRole(RoleController) != tt.wantController {
+				t.Errorf("roles = %v, want broker %v and controller %v", cluster.Roles, tt.wantBroker, tt.wantController)
+			}
+			if cluster.Standalone(1) != (tt.wantBroker && tt.wantController) {
+				t.Errorf("standalone = %v with roles %v", cluster.Standalone(1), cluster.Roles)
+			}
+		})
+	}
+}
+
+func TestNewCluster_RolesConfigFile(t *testing.T) {
+	env := viper.New()
+	env.SetConfigType("yaml")
+	if err := env.ReadConfig(strings.NewReader("process.roles: [broker, controller]")); err != nil {
+		t.Fatal(err)
+	}
+
+	cluster, err := NewCluster(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cluster.Standalone(1) {
+		t.Errorf("roles = %v, want broker and controller", cluster.Roles)
+	}
+	if cluster.ClusterID == "" {
+		t.Error("no cluster ID was generated")
+	}
+}
+
+func Test_parseVoters(t *testing.T) {
+	voters, err := parseVoters([]string{"1@host1:9093", "2@[::1]:9094", "3@10.0.0.3:9095"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Voter{{ID: 1, Host: "host1", Port: 9093}, {ID: 2, Host: "::1", Port: 9094}, {ID: 3, Host: "10.0.0.3", Port: 9095}}
+	if !slices.Equal(voters, want) {
+		t.Errorf("voters = %+v, want %+v", voters, want)
+	}
+
+	for _, voter := range []string{
+		"host1:9093",
+		"@host1:9093",
+		"x@host1:9093",
+		"-1@host1:9093",
+		"1@host1",
+		"1@:9093",
+		"1@host1:port",
+		"1@host1:0",
+		"1@host1:65536",
+		"1@::1:9093",
+	} {
+		if _, err := parseVoters([]string{voter}); err == nil {
+			t.Errorf("malformed voter %q was accepted", voter)
+		}
+	}
+
+	if _, err := parseVoters([]string{"1@host1:9093", "1@host2:9093"}); err == nil {
+		t.Error("duplicate voter ids were accepted")
+	}
+}
+
+func TestNewConfig_QuorumVoters(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "CONTROLLER://localhost:9093")
+	t.Setenv("OT_LISTENER_SECURITY_PROTOCOL_MAP", "CONTROLLER:PLAINTEXT")
+	t.Setenv("OT_BROKER_ID", "0")
+	t.Setenv("OT_PROCESS_ROLES", "controller")
+	t.Setenv("OT_CONTROLLER_QUORUM_VOTERS", "0@host1:9093,2@host2:9093")
+	t.Setenv("OT_CONTROLLER_LISTENER_NAMES", "CONTROLLER")
+
+	conf, err := NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conf.Cluster.QuorumVoters) != 2 || !conf.Cluster.IsControllerListener("controller") {
+		t.Errorf("cluster = %+v, want two voters and the controller listener", conf.Cluster)
+	}
+
+	t.Setenv("OT_CONTROLLER_LISTENER_NAMES", "")
+	if _, err := NewConfig(""); err == nil {
+		t.Error("a controller without controller.listener.names was accepted")
+	}
+
+	t.Setenv("OT_CONTROLLER_LISTENER_NAMES", "OTHER")
+	if _, err := NewConfig(""); err == nil {
+		t.Error("a controller without a controller listener was accepted")
+	}
+
+	t.Setenv("OT_CONTROLLER_LISTENER_NAMES", "CONTROLLER")
+	t.Setenv("OT_CONTROLLER_QUORUM_VOTERS", "2@host2:9093,3@host3:9093")
+	if _, err := NewConfig(""); err == nil {
+		t.Error("a controller which is not a voter was accepted")
+	}
+
+	t.Setenv("OT_CONTROLLER_QUORUM_VOTERS", "2@host2:9093,2@host3:9093")
+	if _, err := NewConfig(""); err == nil {
+		t.Error("duplicate voter ids were accepted")
+	}
+}
@@ -2,6 +2,12 @@ package config
 
 import "strings"
 
+// interfaceHostPrefix marks a listener host as the name of a network interface, like if:eth1.
+// The listener binds to the address of the interface.
+const interfaceHostPrefix = "if:"
+
+// SecurityProtocol is the security protocol of a listener. It is the only definition of the security protocols,
+// the values are the IDs Kafka uses for them, like in the endpoints of broker registrations.
 type SecurityProtocol int
 
 const (
@@ -28,3 +34,20 @@ func ParseSecurityProtocol(p string) (SecurityProtocol, bool) {
 		return UNDEFINED_SECURITY_PROTOCOL, false
 	}
 }
+
+// String returns the name of the security protocol, which ParseSecurityProtocol parses back,
+// or UNDEFINED for UNDEFINED_SECURITY_PROTOCOL.
+func (p SecurityProtocol) String() string {
+	switch p {
+	case PLAINTEXT:
+		return "PLAINTEXT"
+	case SSL:
+		return "SSL"
+	case SASL_PLAINTEXT:
+		return "SASL_PLAINTEXT"
+	case SASL_SSL:
+		return "SASL_SSL"
+	default:
+		return "UNDEFINED"
+	}
+}
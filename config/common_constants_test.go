@@ -0,0 +1,29 @@
+package config
+
+import "testing"
+
+func TestSecurityProtocol_String(t *testing.T) {
+	tests := []struct {
+		protocol SecurityProtocol
+		want     string
+	}{
+		{PLAINTEXT, "PLAINTEXT"},
+		{SSL, "SSL"},
+		{SASL_PLAINTEXT, "SASL_PLAINTEXT"},
+		{SASL_SSL, "SASL_SSL"},
+		{UNDEFINED_SECURITY_PROTOCOL, "UNDEFINED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.protocol.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+
+			// String is the reverse of ParseSecurityProtocol, which doesn't accept the undefined protocol
+			parsed, ok := ParseSecurityProtocol(tt.protocol.String())
+			if ok != (tt.protocol != UNDEFINED_SECURITY_PROTOCOL) || parsed != tt.protocol {
+				t.Errorf("ParseSecurityProtocol(%q) = %v, %v", tt.want, parsed, ok)
+			}
+		})
+	}
+}
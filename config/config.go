@@ -1,47 +1,169 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
 	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
-	"github.com/google/uuid"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	OTProfile       OTProfile
-	LogLevel        slog.Level
-	LogFormat       string
+	OTProfile OTProfile
+	// LogLevel is read by the logger, so that the level can be changed at runtime
+	LogLevel  *slog.LevelVar
+	LogFormat string
+	// LogFile is the file the logs are written to, set by log.file. The logs are written to stdout when it is empty,
+	// or to both when log.console.enable is set. LogFileFormat is the format of the file, set by log.file.format,
+	// which defaults to LogFormat.
+	LogFile       string
+	LogFileFormat string
+	LogFileRotate LogFileRotate
+	LogConsole    bool
+	// LogSampling limits the records with the same message and level, set by log.sampling.enable,
+	// log.sampling.max.messages and log.sampling.interval.ms
+	LogSampling LogSampling
+
 	DebugServerPort int
+	// NumPartitions and DefaultReplicationFactor are the defaults of topics which are auto created,
+	// or created without the number of partitions or replication factor, set by num.partitions and default.replication.factor
+	NumPartitions            int32
+	DefaultReplicationFactor int16
+	// SocketSendBufferBytes and SocketReceiveBufferBytes are the sizes of the socket buffers of the client connections,
+	// set by socket.send.buffer.bytes and socket.receive.buffer.bytes. -1 keeps the buffer sizes of the OS.
+	SocketSendBufferBytes    int
+	SocketReceiveBufferBytes int
+	// SocketKeepAlive enables TCP keepalive probes on the client connections, every SocketKeepAliveInterval,
+	// set by socket.keepalive.enable and socket.keepalive.interval.ms
+	SocketKeepAlive         bool
+	SocketKeepAliveInterval time.Duration
+	// MessageDecompressedMaxBytes is the largest size the records of a compressed batch may decompress to,
+	// set by message.decompressed.max.bytes. socket.request.max.bytes only limits the compressed request.
+	MessageDecompressedMaxBytes int64
+	// LogDirs are the directories of the partition logs, set by log.dirs. They are only written to with LogStorage disk.
+	LogDirs []string
+	// LogStorage is where the partition logs are kept, memory or disk, set by log.storage. On disk, the logs are written
+	// to segments of LogSegmentBytes which are flushed every LogFlushInterval, set by log.segment.bytes and log.flush.interval.ms.
+	LogStorage       string
+	LogSegmentBytes  int64
+	LogFlushInterval time.Duration
+	// LogIndexIntervalBytes is the number of bytes of batches between the entries of the offset index of a segment,
+	// set by log.index.interval.bytes.
+	LogIndexIntervalBytes int64
+	// LogRetentionTime and LogRetentionBytes are the limits of the partition logs on disk beyond which their oldest segments
+	// are deleted, checked every LogRetentionCheckInterval, set by log.retention.ms, log.retention.bytes and
+	// log.retention.check.interval.ms. A negative limit disables it.
+	LogRetentionTime          time.Duration
+	LogRetentionBytes         int64
+	LogRetentionCheckInterval time.Duration
 
 	Broker  *Broker
 	Cluster *Cluster
+	Sasl    *Sasl
 
 	Env *viper.Viper
+
+	// envPrefix is the prefix of the environment variables which override the properties, like OT in OT_LOG_LEVEL
+	envPrefix string
+	// staticLogLevel is the log level read from the configuration, which LogLevel is reset to
+	staticLogLevel slog.Level
 }
 
-type Cluster struct {
-	ClusterID string
+// LogFileRotate are the limits of the log file, it is rotated once it grows past MaxBytes, set by log.file.max.bytes,
+// and the rotated files beyond MaxBackups or older than MaxAge are removed, set by log.file.max.backups and log.file.max.age.ms.
+// A zero value disables the limit.
+type LogFileRotate struct {
+	MaxBytes   int64
+	MaxBackups int
+	MaxAge     time.Duration
 }
 
+// LogSampling drops the records with the same message and level beyond MaxMessages per Interval, when it is enabled.
+type LogSampling struct {
+	Enabled     bool
+	MaxMessages int
+	Interval    time.Duration
+}
+
+// The values of log.storage.
+const (
+	LogStorageMemory = "memory"
+	LogStorageDisk   = "disk"
+)
+
+// DefaultEnvPrefix is the prefix of the environment variables which override the properties, unless it is changed with WithEnvPrefix.
+const DefaultEnvPrefix = "OT"
+
+// Option changes how the configuration is read.
+type Option func(*options)
+
+type options struct {
+	envPrefix string
+}
+
+// WithEnvPrefix sets the prefix of the environment variables which override the properties, for environments where
+// OT_ variables are used by other tools, like OpenTelemetry. With the prefix TALARIA, log.level is set by TALARIA_LOG_LEVEL.
+func WithEnvPrefix(prefix string) Option {
+	return func(o *options) {
+		o.envPrefix = prefix
+	}
+}
+
+// NewConfig reads the config file and the environment variables with the default prefix.
 func NewConfig(confFilename string) (*Config, error) {
-	config := Config{}
+	return NewConfigWithOptions(confFilename)
+}
+
+// NewConfigWithOptions reads the config file and the environment variables, like NewConfig, with the options applied.
+func NewConfigWithOptions(confFilename string, opts ...Option) (*Config, error) {
+	o := options{envPrefix: DefaultEnvPrefix}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// the separator is added by viper, TALARIA_ and TALARIA are the same prefix
+	prefix := strings.ToUpper(strings.TrimSuffix(o.envPrefix, "_"))
+	if prefix == "" {
+		return &Config{}, errors.New("the prefix of the environment variables must not be empty")
+	}
+
+	config := Config{envPrefix: prefix}
 
 	// init viper
 	env := viper.New()
 
 	env.AutomaticEnv()
-	env.SetEnvPrefix("ot")
+	env.SetEnvPrefix(prefix)
 	env.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
-	env.SetConfigType("yaml")
+	configType, err := getConfigType(confFilename)
+	if err != nil {
+		return &Config{}, err
+	}
+
+	env.SetConfigType(configType)
 	env.SetConfigFile(confFilename)
 	env.AddConfigPath(".")
 
 	// set defaults for configuration properties
 	setDefaults(env)
 
-	env.ReadInConfig()
+	// a missing config file is fine, the broker can be configured through environment variables only.
+	// Any other error, like a file whose content doesn't match its extension, is returned instead of silently falling back to the defaults.
+	if err := env.ReadInConfig(); err != nil && !isConfigFileNotFound(err) {
+		var parseErr viper.ConfigParseError
+		if errors.As(err, &parseErr) {
+			return &Config{}, fmt.Errorf("error parsing %s config file %s: %w", configType, confFilename, err)
+		}
+
+		return &Config{}, fmt.Errorf("error reading config file %s: %w", confFilename, err)
+	}
 
 	config.Env = env
 
@@ -50,6 +172,26 @@ func NewConfig(confFilename string) (*Config, error) {
 	config.LogFormat = env.GetString("log.format")
 	config.DebugServerPort = env.GetInt("debug.server.port")
 
+	if err := config.loadLogFile(); err != nil {
+		return &Config{}, err
+	}
+
+	if err := config.loadLogSampling(); err != nil {
+		return &Config{}, err
+	}
+
+	if err := config.loadTopicDefaults(); err != nil {
+		return &Config{}, err
+	}
+
+	if err := config.loadSocketOptions(); err != nil {
+		return &Config{}, err
+	}
+
+	if err := config.loadLogDirs(); err != nil {
+		return &Config{}, err
+	}
+
 	broker, err := NewBroker(env)
 	if err != nil {
 		return &Config{}, err
@@ -57,30 +199,301 @@ func NewConfig(confFilename string) (*Config, error) {
 
 	config.Broker = broker
 
-	clusterId := env.GetString("cluster.id")
-	if clusterId == "" {
-		uid, err := uuid.NewV6()
-		if err != nil {
-			return &Config{}, err
-		}
+	sasl, err := NewSasl(env)
+	if err != nil {
+		return &Config{}, err
+	}
+
+	config.Sasl = sasl
+
+	cluster, err := NewCluster(env)
+	if err != nil {
+		return &Config{}, err
+	}
+
+	config.Cluster = cluster
 
-		clusterId = uid.String()
+	if err := validateController(cluster, broker); err != nil {
+		return &Config{}, err
 	}
 
-	config.Cluster = &Cluster{
-		ClusterID: clusterId,
+	if err := config.validateDebugServerPort(); err != nil {
+		return &Config{}, err
 	}
 
 	return &config, nil
 }
 
+// validateDebugServerPort checks that the debug server, started in the localdev profile, doesn't listen on the port
+// of a listener. It binds all interfaces, so the host of the listener doesn't matter and the broker would fail to bind.
+func (c *Config) validateDebugServerPort() error {
+	// port 0 picks a free port, which can't collide
+	if c.OTProfile != Localdev || c.DebugServerPort == 0 {
+		return nil
+	}
+
+	for _, l := range c.Broker.Listeners {
+		if int(l.Port) == c.DebugServerPort {
+			return fmt.Errorf("debug.server.port %d is the port of the listener %s, the debug server and the listeners must use different ports", c.DebugServerPort, l)
+		}
+	}
+
+	return nil
+}
+
+// loadLogFile reads the log file and its rotation limits.
+func (c *Config) loadLogFile() error {
+	c.LogFile = c.Env.GetString("log.file")
+	c.LogFileFormat = c.Env.GetString("log.file.format")
+	if c.LogFileFormat == "" {
+		c.LogFileFormat = c.LogFormat
+	}
+	c.LogConsole = c.Env.GetBool("log.console.enable")
+
+	maxBytes := c.Env.GetInt64("log.file.max.bytes")
+	if maxBytes < 0 {
+		return fmt.Errorf("invalid log.file.max.bytes %d, it must be positive or 0 to never rotate the log file", maxBytes)
+	}
+	maxBackups := c.Env.GetInt("log.file.max.backups")
+	if maxBackups < 0 {
+		return fmt.Errorf("invalid log.file.max.backups %d, it must be positive or 0 to keep all rotated log files", maxBackups)
+	}
+	maxAge := c.Env.GetInt64("log.file.max.age.ms")
+	if maxAge < 0 {
+		return fmt.Errorf("invalid log.file.max.age.ms %d, it must be positive or 0 to keep rotated log files regardless of their age", maxAge)
+	}
+
+	c.LogFileRotate = LogFileRotate{
+		MaxBytes:   maxBytes,
+		MaxBackups: maxBackups,
+		MaxAge:     time.Duration(maxAge) * time.Millisecond,
+	}
+
+	return nil
+}
+
+// loadLogSampling reads the limits of the records with the same message and level.
+func (c *Config) loadLogSampling() error {
+	c.LogSampling.Enabled = c.Env.GetBool("log.sampling.enable")
+	if !c.LogSampling.Enabled {
+		return nil
+	}
+
+	maxMessages := c.Env.GetInt("log.sampling.max.messages")
+	if maxMessages < 1 {
+		return fmt.Errorf("invalid log.sampling.max.messages %d, it must be at least 1", maxMessages)
+	}
+	interval := c.Env.GetInt64("log.sampling.interval.ms")
+	if interval <= 0 {
+		return fmt.Errorf("invalid log.sampling.interval.ms %d, it must be positive", interval)
+	}
+
+	c.LogSampling.MaxMessages = maxMessages
+	c.LogSampling.Interval = time.Duration(interval) * time.Millisecond
+
+	return nil
+}
+
+// loadTopicDefaults reads the number of partitions and replication factor of topics created without them.
+func (c *Config) loadTopicDefaults() error {
+	numPartitions := c.Env.GetInt("num.partitions")
+	if numPartitions < 1 || numPartitions > math.MaxInt32 {
+		return fmt.Errorf("invalid num.partitions %d, it must be at least 1", numPartitions)
+	}
+	replicationFactor := c.Env.GetInt("default.replication.factor")
+	if replicationFactor < 1 || replicationFactor > math.MaxInt16 {
+		return fmt.Errorf("invalid default.replication.factor %d, it must be between 1 and %d", replicationFactor, math.MaxInt16)
+	}
+
+	c.NumPartitions = int32(numPartitions)
+	c.DefaultReplicationFactor = int16(replicationFactor)
+
+	return nil
+}
+
+// loadSocketOptions reads the socket options of the client connections and the limits of their requests.
+func (c *Config) loadSocketOptions() error {
+	var err error
+	if c.SocketSendBufferBytes, err = readSocketBufferSize(c.Env, "socket.send.buffer.bytes"); err != nil {
+		return err
+	}
+	if c.SocketReceiveBufferBytes, err = readSocketBufferSize(c.Env, "socket.receive.buffer.bytes"); err != nil {
+		return err
+	}
+
+	c.SocketKeepAlive = c.Env.GetBool("socket.keepalive.enable")
+	interval := c.Env.GetInt64("socket.keepalive.interval.ms")
+	if c.SocketKeepAlive && interval <= 0 {
+		return fmt.Errorf("invalid socket.keepalive.interval.ms %d, it must be positive", interval)
+	}
+	c.SocketKeepAliveInterval = time.Duration(interval) * time.Millisecond
+
+	c.MessageDecompressedMaxBytes = c.Env.GetInt64("message.decompressed.max.bytes")
+	if c.MessageDecompressedMaxBytes <= 0 || c.MessageDecompressedMaxBytes > math.MaxInt32 {
+		return fmt.Errorf("invalid message.decompressed.max.bytes %d, it must be between 1 and %d", c.MessageDecompressedMaxBytes, math.MaxInt32)
+	}
+
+	return nil
+}
+
+// readSocketBufferSize reads the size of a socket buffer, which is positive or -1 for the default of the OS.
+func readSocketBufferSize(env *viper.Viper, key string) (int, error) {
+	size := env.GetInt(key)
+	if size == 0 || size < -1 {
+		return 0, fmt.Errorf("invalid %s %d, it must be positive or -1 for the default of the OS", key, size)
+	}
+
+	return size, nil
+}
+
+// loadLogDirs reads the directories of the partition logs, a list in the config file or a comma separated string,
+// and how the logs are stored in them.
+func (c *Config) loadLogDirs() error {
+	c.LogDirs = nil
+	if dirs, ok := c.Env.Get("log.dirs").([]any); ok {
+		for _, dir := range dirs {
+			c.LogDirs = append(c.LogDirs, fmt.Sprint(dir))
+		}
+	} else {
+		for _, dir := range strings.Split(c.Env.GetString("log.dirs"), ",") {
+			if dir = strings.TrimSpace(dir); dir != "" {
+				c.LogDirs = append(c.LogDirs, dir)
+			}
+		}
+	}
+
+	if len(c.LogDirs) == 0 {
+		return errors.New("log.dirs must contain at least one directory")
+	}
+
+	c.LogStorage = strings.ToLower(c.Env.GetString("log.storage"))
+	if c.LogStorage != LogStorageMemory && c.LogStorage != LogStorageDisk {
+		return fmt.Errorf("invalid log.storage %q, expected %s or %s", c.LogStorage, LogStorageMemory, LogStorageDisk)
+	}
+
+	segmentBytes := c.Env.GetInt64("log.segment.bytes")
+	if segmentBytes < 1 || segmentBytes > math.MaxInt32 {
+		return fmt.Errorf("invalid log.segment.bytes %d, it must be between 1 and %d", segmentBytes, math.MaxInt32)
+	}
+	flushInterval := c.Env.GetInt64("log.flush.interval.ms")
+	if flushInterval < 0 {
+		return fmt.Errorf("invalid log.flush.interval.ms %d, it must be positive or 0 to leave the flushes to the OS", flushInterval)
+	}
+
+	indexIntervalBytes := c.Env.GetInt64("log.index.interval.bytes")
+	if indexIntervalBytes < 0 || indexIntervalBytes > math.MaxInt32 {
+		return fmt.Errorf("invalid log.index.interval.bytes %d, it must be between 0 and %d", indexIntervalBytes, math.MaxInt32)
+	}
+
+	c.LogSegmentBytes = segmentBytes
+	c.LogFlushInterval = time.Duration(flushInterval) * time.Millisecond
+	c.LogIndexIntervalBytes = indexIntervalBytes
+
+	retentionMs := c.Env.GetInt64("log.retention.ms")
+	if retentionMs < -1 || retentionMs == 0 {
+		return fmt.Errorf("invalid log.retention.ms %d, it must be positive or -1 to keep the segments regardless of their age", retentionMs)
+	}
+	retentionBytes := c.Env.GetInt64("log.retention.bytes")
+	if retentionBytes < -1 {
+		return fmt.Errorf("invalid log.retention.bytes %d, it must be at least 0 or -1 to keep the segments regardless of the size of the log", retentionBytes)
+	}
+	checkInterval := c.Env.GetInt64("log.retention.check.interval.ms")
+	if checkInterval <= 0 {
+		return fmt.Errorf("invalid log.retention.check.interval.ms %d, it must be positive", checkInterval)
+	}
+
+	c.LogRetentionTime = time.Duration(retentionMs) * time.Millisecond
+	c.LogRetentionBytes = retentionBytes
+	c.LogRetentionCheckInterval = time.Duration(checkInterval) * time.Millisecond
+
+	return nil
+}
+
+// IsSetByUser reports whether the property was set in the config file or through its environment variable,
+// as opposed to falling back to its default value.
+func (c *Config) IsSetByUser(key string) bool {
+	if c.Env.InConfig(key) {
+		return true
+	}
+
+	_, ok := os.LookupEnv(c.envVarName(key))
+	return ok
+}
+
+// envVarName returns the environment variable that overrides the property, e.g. OT_LOG_LEVEL for log.level.
+func (c *Config) envVarName(key string) string {
+	return c.envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// isConfigFileNotFound reports whether err means that there is no config file to read.
+// Viper returns a ConfigFileNotFoundError when it searches the config paths, but the error of the file system
+// when the file was set explicitly.
+func isConfigFileNotFound(err error) bool {
+	var notFoundErr viper.ConfigFileNotFoundError
+	return errors.As(err, &notFoundErr) || errors.Is(err, fs.ErrNotExist)
+}
+
+// getConfigType infers the format of the config file from its extension. Files without an extension are read as yaml.
+func getConfigType(confFilename string) (string, error) {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(confFilename), "."))
+
+	switch ext {
+	case "", "yaml", "yml":
+		return "yaml", nil
+	case "json", "toml":
+		return ext, nil
+	default:
+		return "", fmt.Errorf("unsupported config file extension %q, expected one of .yaml, .yml, .json or .toml", filepath.Ext(confFilename))
+	}
+}
+
 // setDefaults sets the default values for properties that are not set.
 func setDefaults(env *viper.Viper) {
 	env.SetDefault("log.level", "warn")
 	env.SetDefault("log.format", "text")
+	env.SetDefault("log.file", "")
+	env.SetDefault("log.file.format", "")
+	env.SetDefault("log.file.max.bytes", 104857600)
+	env.SetDefault("log.file.max.backups", 5)
+	env.SetDefault("log.file.max.age.ms", 604800000)
+	env.SetDefault("log.console.enable", false)
+	env.SetDefault("log.sampling.enable", false)
+	env.SetDefault("log.sampling.max.messages", 10)
+	env.SetDefault("log.sampling.interval.ms", 1000)
 	env.SetDefault("debug.server.port", 9090)
 	env.SetDefault("broker.id", -1)
+	env.SetDefault("process.roles", "broker,controller")
 	env.SetDefault("reserved.broker.max.id", 1000)
+	env.SetDefault("broker.session.timeout.ms", 9000)
+	env.SetDefault("broker.heartbeat.interval.ms", 2000)
+	env.SetDefault("socket.request.max.bytes", 104857600)
+	env.SetDefault("message.decompressed.max.bytes", 104857600)
+	env.SetDefault("socket.send.buffer.bytes", 102400)
+	env.SetDefault("socket.receive.buffer.bytes", 102400)
+	env.SetDefault("socket.keepalive.enable", true)
+	env.SetDefault("socket.keepalive.interval.ms", 15000)
+	env.SetDefault("connections.max.idle.ms", 600000)
+	env.SetDefault("log.dirs", "/tmp/opentalaria-logs")
+	env.SetDefault("log.storage", LogStorageMemory)
+	env.SetDefault("log.segment.bytes", 1073741824)
+	env.SetDefault("log.flush.interval.ms", 1000)
+	env.SetDefault("log.index.interval.bytes", 4096)
+	env.SetDefault("log.retention.ms", 604800000)
+	env.SetDefault("log.retention.bytes", -1)
+	env.SetDefault("log.retention.check.interval.ms", 300000)
+	env.SetDefault("max.connections.per.ip", math.MaxInt32)
+	env.SetDefault("group.min.session.timeout.ms", 6000)
+	env.SetDefault("group.max.session.timeout.ms", 1800000)
+	env.SetDefault("shutdown.timeout.ms", 30000)
+	env.SetDefault("auto.create.topics.enable", false)
+	env.SetDefault("num.partitions", 1)
+	env.SetDefault("default.replication.factor", 1)
+	env.SetDefault("sasl.enabled.mechanisms", "SCRAM-SHA-256,SCRAM-SHA-512")
+	env.SetDefault("sasl.oauthbearer.jwks.endpoint.refresh.ms", 3600000)
+	env.SetDefault("sasl.oauthbearer.unsecured", false)
+	env.SetDefault("allow.everyone.if.no.acl.found", true)
+	env.SetDefault("quota.requests.per.second", 0)
+	env.SetDefault("quota.requests.burst", 0)
 }
 
 /**
@@ -91,15 +504,23 @@ func setDefaults(env *viper.Viper) {
 func MockCluster() *Cluster {
 	return &Cluster{
 		ClusterID: "abc",
+		Roles:     map[ProcessRole]struct{}{RoleBroker: {}, RoleController: {}},
 	}
 }
 
 // MockConfig generates a mock object used for unit testing.
 func MockConfig() *Config {
-	config := Config{}
+	config := Config{envPrefix: DefaultEnvPrefix}
 
 	config.Cluster = MockCluster()
 	config.Broker = MockBroker()
+	config.LogLevel = new(slog.LevelVar)
+	config.NumPartitions = 1
+	config.DefaultReplicationFactor = 1
+	config.SocketSendBufferBytes = -1
+	config.SocketReceiveBufferBytes = -1
+	config.LogDirs = []string{"/tmp/opentalaria-logs"}
+	config.LogStorage = LogStorageMemory
 
 	return &config
 }
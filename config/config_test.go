@@ -0,0 +1,393 @@
+package config
+
+import (
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewConfig_FileFormats(t *testing.T) {
+	want, err := NewConfig("testdata/config.yaml")
+	if err != nil {
+		t.Fatalf("error loading yaml config: %v", err)
+	}
+	want.Env = nil
+
+	if want.DebugServerPort != 9091 || want.Cluster.ClusterID != "test-cluster" || want.LogLevel.Level() != slog.LevelInfo || want.OTProfile != Dev {
+		t.Fatalf("yaml config was not read: %+v", want)
+	}
+
+	for _, filename := range []string{"testdata/config.json", "testdata/config.toml"} {
+		t.Run(filename, func(t *testing.T) {
+			got, err := NewConfig(filename)
+			if err != nil {
+				t.Fatalf("error loading config: %v", err)
+			}
+			got.Env = nil
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("NewConfig() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestNewConfig_MismatchedExtension(t *testing.T) {
+	// the file has yaml content, but a json extension
+	if _, err := NewConfig("testdata/mismatched.json"); err == nil {
+		t.Error("expected a parse error for a config file whose content doesn't match its extension")
+	}
+}
+
+func Test_getConfigType(t *testing.T) {
+	tests := []struct {
+		filename string
+		want     string
+		wantErr  bool
+	}{
+		{filename: "", want: "yaml"},
+		{filename: "config", want: "yaml"},
+		{filename: "config.yaml", want: "yaml"},
+		{filename: "config.YML", want: "yaml"},
+		{filename: "config.json", want: "json"},
+		{filename: "/etc/opentalaria/config.toml", want: "toml"},
+		{filename: "config.ini", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			got, err := getConfigType(tt.filename)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getConfigType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("getConfigType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewConfig_BrokenFile(t *testing.T) {
+	if _, err := NewConfig("testdata/broken.yaml"); err == nil {
+		t.Error("expected an error for a syntactically broken config file")
+	}
+}
+
+func TestNewConfig_MissingFile(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://:9092")
+
+	// without a config file the broker is configured through environment variables only
+	if _, err := NewConfig("testdata/missing.yaml"); err != nil {
+		t.Errorf("NewConfig() error = %v, want no error for a missing config file", err)
+	}
+}
+
+func TestConfig_IsSetByUser(t *testing.T) {
+	t.Setenv("OT_CONNECTIONS_MAX_IDLE_MS", "1000")
+
+	conf, err := NewConfig("testdata/config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]bool{
+		"debug.server.port":        true,  // config file
+		"connections.max.idle.ms":  true,  // environment
+		"socket.request.max.bytes": false, // default
+	} {
+		if got := conf.IsSetByUser(key); got != want {
+			t.Errorf("IsSetByUser(%s) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestNewConfig_TopicDefaults(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:9092")
+
+	conf, err := NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.NumPartitions != 1 || conf.DefaultReplicationFactor != 1 {
+		t.Errorf("topic defaults = %d partitions, replication factor %d, want 1 and 1", conf.NumPartitions, conf.DefaultReplicationFactor)
+	}
+
+	t.Setenv("OT_NUM_PARTITIONS", "6")
+	t.Setenv("OT_DEFAULT_REPLICATION_FACTOR", "3")
+	conf, err = NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.NumPartitions != 6 || conf.DefaultReplicationFactor != 3 {
+		t.Errorf("topic defaults = %d partitions, replication factor %d, want 6 and 3", conf.NumPartitions, conf.DefaultReplicationFactor)
+	}
+
+	for _, env := range []string{"OT_NUM_PARTITIONS", "OT_DEFAULT_REPLICATION_FACTOR"} {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv(env, "0")
+			if _, err := NewConfig(""); err == nil {
+				t.Errorf("expected an error for %s=0", env)
+			}
+		})
+	}
+}
+
+func TestNewConfig_SocketOptions(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:9092")
+
+	conf, err := NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.SocketSendBufferBytes != 102400 || conf.SocketReceiveBufferBytes != 102400 {
+		t.Errorf("socket buffers = %d and %d, want the defaults of 102400", conf.SocketSendBufferBytes, conf.SocketReceiveBufferBytes)
+	}
+	if !conf.SocketKeepAlive || conf.SocketKeepAliveInterval != 15*time.Second {
+		t.Errorf("keepalive = %v every %v, want enabled every 15s", conf.SocketKeepAlive, conf.SocketKeepAliveInterval)
+	}
+
+	tests := []struct {
+		env     string
+		value   string
+		wantErr bool
+	}{
+		{env: "OT_SOCKET_SEND_BUFFER_BYTES", value: "-1"},
+		{env: "OT_SOCKET_SEND_BUFFER_BYTES", value: "0", wantErr: true},
+		{env: "OT_SOCKET_SEND_BUFFER_BYTES", value: "-2", wantErr: true},
+		{env: "OT_SOCKET_RECEIVE_BUFFER_BYTES", value: "65536"},
+		{env: "OT_SOCKET_RECEIVE_BUFFER_BYTES", value: "0", wantErr: true},
+		{env: "OT_SOCKET_KEEPALIVE_INTERVAL_MS", value: "0", wantErr: true},
+		{env: "OT_SOCKET_KEEPALIVE_ENABLE", value: "false"},
+		{env: "OT_MESSAGE_DECOMPRESSED_MAX_BYTES", value: "1048576"},
+		{env: "OT_MESSAGE_DECOMPRESSED_MAX_BYTES", value: "0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env+"="+tt.value, func(t *testing.T) {
+			t.Setenv(tt.env, tt.value)
+			if _, err := NewConfig(""); (err != nil) != tt.wantErr {
+				t.Errorf("NewConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewConfig_DebugServerPort(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:9092")
+
+	tests := []struct {
+		profile string
+		port    string
+		wantErr bool
+	}{
+		{profile: "localdev", port: "9090"},
+		{profile: "localdev", port: "9092", wantErr: true},
+		{profile: "localdev", port: "0"},
+		// the debug server only runs in the localdev profile
+		{profile: "prod", port: "9092"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.profile+"/"+tt.port, func(t *testing.T) {
+			t.Setenv("OT_PROFILE", tt.profile)
+			t.Setenv("OT_DEBUG_SERVER_PORT", tt.port)
+			_, err := NewConfig("")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "debug.server.port "+tt.port) {
+				t.Errorf("NewConfig() error = %v, want it to name debug.server.port", err)
+			}
+		})
+	}
+}
+
+func TestNewConfig_LogFile(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:9092")
+
+	conf, err := NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRotate := LogFileRotate{MaxBytes: 104857600, MaxBackups: 5, MaxAge: 7 * 24 * time.Hour}
+	if conf.LogFile != "" || conf.LogConsole || conf.LogFileRotate != wantRotate {
+		t.Errorf("log file = %q, console = %v, rotate = %+v, want no file with the default limits %+v", conf.LogFile, conf.LogConsole, conf.LogFileRotate, wantRotate)
+	}
+	if conf.LogFileFormat != "text" {
+		t.Errorf("log file format = %q, want the log format text", conf.LogFileFormat)
+	}
+	t.Run("OT_LOG_FILE_FORMAT=json", func(t *testing.T) {
+		t.Setenv("OT_LOG_FILE_FORMAT", "json")
+		conf, err := NewConfig("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if conf.LogFormat != "text" || conf.LogFileFormat != "json" {
+			t.Errorf("log format = %q, log file format = %q, want text and json", conf.LogFormat, conf.LogFileFormat)
+		}
+	})
+
+	tests := []struct {
+		env     string
+		value   string
+		wantErr bool
+	}{
+		{env: "OT_LOG_FILE", value: "/var/log/opentalaria/broker.log"},
+		{env: "OT_LOG_FILE_MAX_BYTES", value: "0"},
+		{env: "OT_LOG_FILE_MAX_BYTES", value: "-1", wantErr: true},
+		{env: "OT_LOG_FILE_MAX_BACKUPS", value: "-1", wantErr: true},
+		{env: "OT_LOG_FILE_MAX_AGE_MS", value: "-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env+"="+tt.value, func(t *testing.T) {
+			t.Setenv(tt.env, tt.value)
+			if _, err := NewConfig(""); (err != nil) != tt.wantErr {
+				t.Errorf("NewConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewConfig_LogSampling(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:9092")
+
+	conf, err := NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.LogSampling.Enabled {
+		t.Errorf("log sampling = %+v, want it disabled by default", conf.LogSampling)
+	}
+
+	t.Setenv("OT_LOG_SAMPLING_ENABLE", "true")
+	conf, err = NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (LogSampling{Enabled: true, MaxMessages: 10, Interval: time.Second}); conf.LogSampling != want {
+		t.Errorf("log sampling = %+v, want %+v", conf.LogSampling, want)
+	}
+
+	tests := []struct {
+		env     string
+		value   string
+		wantErr bool
+	}{
+		{env: "OT_LOG_SAMPLING_MAX_MESSAGES", value: "1"},
+		{env: "OT_LOG_SAMPLING_MAX_MESSAGES", value: "0", wantErr: true},
+		{env: "OT_LOG_SAMPLING_INTERVAL_MS", value: "0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env+"="+tt.value, func(t *testing.T) {
+			t.Setenv(tt.env, tt.value)
+			if _, err := NewConfig(""); (err != nil) != tt.wantErr {
+				t.Errorf("NewConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewConfig_LogDirs(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:9092")
+
+	conf, err := NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(conf.LogDirs, []string{"/tmp/opentalaria-logs"}) {
+		t.Errorf("log dirs = %v, want the default", conf.LogDirs)
+	}
+
+	t.Setenv("OT_LOG_DIRS", "/data/logs-1, /data/logs-2")
+	if conf, err = NewConfig(""); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(conf.LogDirs, []string{"/data/logs-1", "/data/logs-2"}) {
+		t.Errorf("log dirs = %v, want both directories", conf.LogDirs)
+	}
+
+	t.Setenv("OT_LOG_DIRS", " , ")
+	if _, err := NewConfig(""); err == nil {
+		t.Error("NewConfig() succeeded without log dirs")
+	}
+}
+
+func TestNewConfig_LogStorage(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:9092")
+
+	conf, err := NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.LogStorage != LogStorageMemory || conf.LogSegmentBytes != 1073741824 || conf.LogFlushInterval != time.Second {
+		t.Errorf("log storage = %s with segments of %d bytes flushed every %v, want the defaults", conf.LogStorage, conf.LogSegmentBytes, conf.LogFlushInterval)
+	}
+	if conf.LogIndexIntervalBytes != 4096 {
+		t.Errorf("log index interval = %d bytes, want the default", conf.LogIndexIntervalBytes)
+	}
+	if conf.LogRetentionTime != 7*24*time.Hour || conf.LogRetentionBytes != -1 || conf.LogRetentionCheckInterval != 5*time.Minute {
+		t.Errorf("log retention = %v and %d bytes checked every %v, want the defaults", conf.LogRetentionTime, conf.LogRetentionBytes, conf.LogRetentionCheckInterval)
+	}
+
+	tests := []struct {
+		env     string
+		value   string
+		wantErr bool
+	}{
+		{env: "OT_LOG_STORAGE", value: "Disk"},
+		{env: "OT_LOG_STORAGE", value: "tape", wantErr: true},
+		{env: "OT_LOG_SEGMENT_BYTES", value: "0", wantErr: true},
+		{env: "OT_LOG_SEGMENT_BYTES", value: "4294967296", wantErr: true},
+		{env: "OT_LOG_FLUSH_INTERVAL_MS", value: "0"},
+		{env: "OT_LOG_FLUSH_INTERVAL_MS", value: "-1", wantErr: true},
+		{env: "OT_LOG_INDEX_INTERVAL_BYTES", value: "0"},
+		{env: "OT_LOG_INDEX_INTERVAL_BYTES", value: "-1", wantErr: true},
+		{env: "OT_LOG_RETENTION_MS", value: "-1"},
+		{env: "OT_LOG_RETENTION_MS", value: "0", wantErr: true},
+		{env: "OT_LOG_RETENTION_BYTES", value: "0"},
+		{env: "OT_LOG_RETENTION_BYTES", value: "-2", wantErr: true},
+		{env: "OT_LOG_RETENTION_CHECK_INTERVAL_MS", value: "0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env+"="+tt.value, func(t *testing.T) {
+			t.Setenv(tt.env, tt.value)
+			if _, err := NewConfig(""); (err != nil) != tt.wantErr {
+				t.Errorf("NewConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewConfigWithOptions_EnvPrefix(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:9092")
+	t.Setenv("OT_LOG_LEVEL", "error")
+	t.Setenv("TALARIA_LISTENERS", "PLAINTEXT://localhost:9093")
+	t.Setenv("TALARIA_LOG_LEVEL", "debug")
+
+	conf, err := NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.LogLevel.Level() != slog.LevelError || conf.Broker.Listeners[0].Port != 9092 {
+		t.Errorf("default prefix: log level %v on port %d, want ERROR on port 9092", conf.LogLevel.Level(), conf.Broker.Listeners[0].Port)
+	}
+
+	for _, prefix := range []string{"TALARIA", "talaria_"} {
+		t.Run(prefix, func(t *testing.T) {
+			conf, err := NewConfigWithOptions("", WithEnvPrefix(prefix))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if conf.LogLevel.Level() != slog.LevelDebug || conf.Broker.Listeners[0].Port != 9093 {
+				t.Errorf("log level %v on port %d, want DEBUG on port 9093", conf.LogLevel.Level(), conf.Broker.Listeners[0].Port)
+			}
+			if !conf.IsSetByUser("log.level") || conf.IsSetByUser("num.partitions") {
+				t.Error("IsSetByUser should check the variables with the custom prefix")
+			}
+		})
+	}
+
+	if _, err := NewConfigWithOptions("", WithEnvPrefix("")); err == nil {
+		t.Error("expected an error for an empty prefix")
+	}
+}
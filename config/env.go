@@ -1,8 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"log/slog"
+	"opentalaria/logger"
 	"strings"
 )
 
@@ -29,17 +31,52 @@ func (c *Config) loadProfile() {
 }
 
 func (c *Config) loadLogLevel() {
-	switch strings.ToLower(c.Env.GetString("log.level")) {
+	level, err := ParseLogLevel(c.Env.GetString("log.level"))
+	if err != nil {
+		log.Println("no log level set or value is invalid, setting default WARN level")
+		level = slog.LevelWarn
+	}
+
+	c.staticLogLevel = level
+	c.LogLevel = new(slog.LevelVar)
+	c.LogLevel.Set(level)
+}
+
+// ParseLogLevel returns the level with the given name. The name is case-insensitive.
+func ParseLogLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "trace":
+		return logger.LevelTrace, nil
 	case "debug":
-		c.LogLevel = slog.LevelDebug
+		return slog.LevelDebug, nil
 	case "info":
-		c.LogLevel = slog.LevelInfo
+		return slog.LevelInfo, nil
 	case "warn":
-		c.LogLevel = slog.LevelWarn
+		return slog.LevelWarn, nil
 	case "error":
-		c.LogLevel = slog.LevelError
+		return slog.LevelError, nil
 	default:
-		log.Println("no log level set or value is invalid, setting default WARN level")
-		c.LogLevel = slog.LevelWarn
+		return slog.LevelWarn, fmt.Errorf("invalid log level %q", name)
+	}
+}
+
+// SetLogLevel changes the log level at runtime. Loggers created with the LogLevel of the config pick up the change immediately.
+func (c *Config) SetLogLevel(name string) error {
+	level, err := ParseLogLevel(name)
+	if err != nil {
+		return err
 	}
+
+	c.LogLevel.Set(level)
+	return nil
+}
+
+// ResetLogLevel restores the log level read from the configuration at startup.
+func (c *Config) ResetLogLevel() {
+	c.LogLevel.Set(c.staticLogLevel)
+}
+
+// IsLogLevelOverridden reports whether the log level was changed at runtime.
+func (c *Config) IsLogLevelOverridden() bool {
+	return c.LogLevel.Level() != c.staticLogLevel
 }
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"opentalaria/logger"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestConfig_loadLogLevel(t *testing.T) {
+	tests := []struct {
+		level string
+		want  slog.Level
+	}{
+		{level: "trace", want: logger.LevelTrace},
+		{level: "DEBUG", want: slog.LevelDebug},
+		{level: "info", want: slog.LevelInfo},
+		{level: "warn", want: slog.LevelWarn},
+		{level: "error", want: slog.LevelError},
+		{level: "verbose", want: slog.LevelWarn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			t.Setenv("OT_LISTENERS", "PLAINTEXT://:9092")
+			t.Setenv("OT_LOG_LEVEL", tt.level)
+
+			conf, err := NewConfig("")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if conf.LogLevel.Level() != tt.want {
+				t.Errorf("LogLevel = %v, want %v", conf.LogLevel.Level(), tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_Reload(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig := func(level, format string, port int) {
+		t.Helper()
+		content := fmt.Sprintf("log.level: %s\nlog.format: %s\nlisteners: PLAINTEXT://localhost:%d\n", level, format, port)
+		if err := os.WriteFile(configFile, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeConfig("info", "text", 9092)
+	conf, err := NewConfig(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a logger created at startup picks up the reloaded level
+	handler := logger.NewLevelHandler(conf.LogLevel, slog.NewTextHandler(io.Discard, nil))
+	if handler.Enabled(context.Background(), slog.LevelDebug) {
+		t.Fatal("debug records enabled at the info level")
+	}
+
+	// the operator edits the config file and sends SIGHUP
+	writeConfig("debug", "json", 9093)
+	next, err := NewConfig(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restart := conf.Reload(next)
+
+	if conf.LogLevel.Level() != slog.LevelDebug || !handler.Enabled(context.Background(), slog.LevelDebug) {
+		t.Errorf("log level = %v after the reload, want DEBUG", conf.LogLevel.Level())
+	}
+	if conf.LogFormat != "json" || conf.LogFileFormat != "json" {
+		t.Errorf("log formats = %s, %s after the reload, want json", conf.LogFormat, conf.LogFileFormat)
+	}
+	if !slices.Equal(restart, []string{"listeners"}) {
+		t.Errorf("keys which require a restart = %v, want [listeners]", restart)
+	}
+	if conf.Broker.Listeners[0].Port != 9092 {
+		t.Errorf("listener port = %d after the reload, want the port of the startup", conf.Broker.Listeners[0].Port)
+	}
+
+	// a log level set at runtime takes precedence over the config file, until it is reset
+	if err := conf.SetLogLevel("error"); err != nil {
+		t.Fatal(err)
+	}
+	writeConfig("warn", "json", 9092)
+	if next, err = NewConfig(configFile); err != nil {
+		t.Fatal(err)
+	}
+	if restart := conf.Reload(next); len(restart) != 0 {
+		t.Errorf("keys which require a restart = %v, want none", restart)
+	}
+	if conf.LogLevel.Level() != slog.LevelError {
+		t.Errorf("log level = %v after the reload, want the ERROR set at runtime", conf.LogLevel.Level())
+	}
+	conf.ResetLogLevel()
+	if conf.LogLevel.Level() != slog.LevelWarn {
+		t.Errorf("log level = %v after the reset, want the WARN of the reloaded config file", conf.LogLevel.Level())
+	}
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// externalHostToken is the host of advertised listeners which is replaced by the external address of the broker,
+// like in PLAINTEXT://<external>:9092, for brokers which only learn their public address at runtime.
+const externalHostToken = "<external>"
+
+// externalHostLookupTimeout bounds the lookup of the external address, so an unreachable endpoint doesn't block the startup.
+const externalHostLookupTimeout = 5 * time.Second
+
+// ExternalHostResolver looks up the external address of the broker.
+type ExternalHostResolver interface {
+	ResolveExternalHost(ctx context.Context) (string, error)
+}
+
+// HTTPExternalHostResolver reads the external address from the body of a GET request,
+// like the instance metadata endpoints of cloud providers return it.
+type HTTPExternalHostResolver struct {
+	URL    string
+	Client *http.Client
+}
+
+func (r HTTPExternalHostResolver) ResolveExternalHost(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.URL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s from %s", resp.Status, r.URL)
+	}
+
+	// an address is short, a larger body is not the address
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 256))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
+
+// newExternalHostResolver returns the resolver of external.host.lookup.url, or nil if it is not set.
+// It is a variable, so tests can inject a fake resolver.
+var newExternalHostResolver = func(env *viper.Viper) ExternalHostResolver {
+	url := env.GetString("external.host.lookup.url")
+	if url == "" {
+		return nil
+	}
+
+	return HTTPExternalHostResolver{URL: url}
+}
+
+// resolveExternalHost replaces the <external> host of the advertised listeners with the address looked up by the resolver.
+// If the lookup fails, or there is no resolver, external.host.fallback is used if it is set, otherwise the lookup error is returned.
+// There is no lookup if no advertised listener uses the <external> host.
+func resolveExternalHost(env *viper.Viper, listeners []string, resolver ExternalHostResolver) ([]string, error) {
+	uses := false
+	for _, l := range listeners {
+		uses = uses || strings.Contains(l, externalHostToken)
+	}
+	if !uses {
+		return listeners, nil
+	}
+
+	host, err := lookupExternalHost(resolver)
+	if err != nil {
+		fallback := env.GetString("external.host.fallback")
+		if fallback == "" {
+			return nil, fmt.Errorf("error resolving the %s host of advertised.listeners, and external.host.fallback is not set: %w", externalHostToken, err)
+		}
+
+		slog.Warn("error resolving the external host of advertised.listeners, using external.host.fallback", "external.host.fallback", fallback, "err", err)
+		host = fallback
+	}
+
+	// IPv6 addresses are enclosed in brackets in listeners
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+
+	resolved := make([]string, 0, len(listeners))
+	for _, l := range listeners {
+		resolved = append(resolved, strings.ReplaceAll(l, externalHostToken, host))
+	}
+
+	return resolved, nil
+}
+
+func lookupExternalHost(resolver ExternalHostResolver) (string, error) {
+	if resolver == nil {
+		return "", fmt.Errorf("external.host.lookup.url is not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), externalHostLookupTimeout)
+	defer cancel()
+
+	host, err := resolver.ResolveExternalHost(ctx)
+	if err != nil {
+		return "", err
+	}
+	if host == "" || strings.ContainsAny(host, " \t\r\n/") {
+		return "", fmt.Errorf("invalid external host %q", host)
+	}
+	if strings.Contains(host, ":") && net.ParseIP(host) == nil {
+		return "", fmt.Errorf("invalid external host %q", host)
+	}
+
+	return host, nil
+}
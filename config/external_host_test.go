@@ -0,0 +1,95 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+type fakeExternalHostResolver struct {
+	host string
+	err  error
+}
+
+func (r fakeExternalHostResolver) ResolveExternalHost(ctx context.Context) (string, error) {
+	return r.host, r.err
+}
+
+func TestNewConfig_ExternalHost(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolver ExternalHostResolver
+		fallback string
+		wantHost string
+		wantErr  bool
+	}{
+		{name: "resolved", resolver: fakeExternalHostResolver{host: "203.0.113.7"}, wantHost: "203.0.113.7"},
+		{name: "resolved IPv6", resolver: fakeExternalHostResolver{host: "2001:db8::7"}, wantHost: "2001:db8::7"},
+		{name: "lookup fails with fallback", resolver: fakeExternalHostResolver{err: errors.New("timeout")}, fallback: "broker.example.com", wantHost: "broker.example.com"},
+		{name: "lookup fails without fallback", resolver: fakeExternalHostResolver{err: errors.New("timeout")}, wantErr: true},
+		{name: "invalid host", resolver: fakeExternalHostResolver{host: "<html>not found</html>"}, wantErr: true},
+		{name: "no lookup url", resolver: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OT_LISTENERS", "PLAINTEXT://:9092")
+			t.Setenv("OT_ADVERTISED_LISTENERS", "PLAINTEXT://<external>:9092")
+			t.Setenv("OT_EXTERNAL_HOST_FALLBACK", tt.fallback)
+
+			newResolver := newExternalHostResolver
+			newExternalHostResolver = func(*viper.Viper) ExternalHostResolver { return tt.resolver }
+			defer func() { newExternalHostResolver = newResolver }()
+
+			conf, err := NewConfig("")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got := conf.Broker.AdvertisedListeners[0].Host; got != tt.wantHost {
+				t.Errorf("advertised host = %q, want %q", got, tt.wantHost)
+			}
+		})
+	}
+}
+
+func TestNewConfig_ExternalHostNotUsed(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://:9092")
+	t.Setenv("OT_ADVERTISED_LISTENERS", "PLAINTEXT://broker.example.com:9092")
+
+	newResolver := newExternalHostResolver
+	newExternalHostResolver = func(*viper.Viper) ExternalHostResolver {
+		return fakeExternalHostResolver{err: errors.New("the external host must not be looked up")}
+	}
+	defer func() { newExternalHostResolver = newResolver }()
+
+	if _, err := NewConfig(""); err != nil {
+		t.Errorf("NewConfig() error = %v", err)
+	}
+}
+
+func TestHTTPExternalHostResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/public-ipv4" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("203.0.113.7\n"))
+	}))
+	defer server.Close()
+
+	host, err := HTTPExternalHostResolver{URL: server.URL + "/public-ipv4"}.ResolveExternalHost(context.Background())
+	if err != nil || host != "203.0.113.7" {
+		t.Errorf("ResolveExternalHost() = %q, %v, want 203.0.113.7", host, err)
+	}
+
+	if _, err := (HTTPExternalHostResolver{URL: server.URL + "/missing"}).ResolveExternalHost(context.Background()); err == nil {
+		t.Error("ResolveExternalHost() of a missing endpoint should fail")
+	}
+}
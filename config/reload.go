@@ -0,0 +1,41 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+)
+
+// reloadableKeys are the properties which Reload applies at runtime.
+var reloadableKeys = map[string]bool{
+	"log.level":       true,
+	"log.format":      true,
+	"log.file.format": true,
+}
+
+// Reload applies the properties of next, the configuration read again from the config file and the environment,
+// which are safe to change at runtime: log.level, log.format and log.file.format. A log level set at runtime through
+// AlterConfigs takes precedence over the one of the config file, which it is reset to later on.
+// The loggers have to be recreated for a new log format to take effect.
+//
+// It returns the other properties which changed, sorted, which require a restart to take effect and are ignored.
+func (c *Config) Reload(next *Config) []string {
+	if !c.IsLogLevelOverridden() {
+		c.LogLevel.Set(next.staticLogLevel)
+	}
+	c.staticLogLevel = next.staticLogLevel
+	c.LogFormat = next.LogFormat
+	c.LogFileFormat = next.LogFileFormat
+
+	keys := append(c.Env.AllKeys(), next.Env.AllKeys()...)
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	var restart []string
+	for _, key := range keys {
+		if !reloadableKeys[key] && fmt.Sprint(c.Env.Get(key)) != fmt.Sprint(next.Env.Get(key)) {
+			restart = append(restart, key)
+		}
+	}
+
+	return restart
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Sasl holds the settings of SASL authentication.
+type Sasl struct {
+	// Mechanisms are the mechanisms clients can authenticate with, set by sasl.enabled.mechanisms
+	Mechanisms []string
+	// ScramUsers are the users which can authenticate with the SCRAM mechanisms, set by sasl.scram.passwords
+	ScramUsers []ScramUser
+	// OAuthBearerJWKSURL is the JWKS endpoint serving the keys which sign OAUTHBEARER tokens,
+	// set by sasl.oauthbearer.jwks.endpoint.url
+	OAuthBearerJWKSURL string
+	// OAuthBearerJWKSRefreshInterval is the time after which the keys are requested again,
+	// set by sasl.oauthbearer.jwks.endpoint.refresh.ms
+	OAuthBearerJWKSRefreshInterval time.Duration
+	// OAuthBearerExpectedIssuer is the issuer OAUTHBEARER tokens must have, if set, set by sasl.oauthbearer.expected.issuer
+	OAuthBearerExpectedIssuer string
+	// OAuthBearerExpectedAudience is the audience OAUTHBEARER tokens must be issued for, if set,
+	// set by sasl.oauthbearer.expected.audience
+	OAuthBearerExpectedAudience string
+	// OAuthBearerUnsecured accepts unsigned OAUTHBEARER tokens, for development and testing,
+	// set by sasl.oauthbearer.unsecured
+	OAuthBearerUnsecured bool
+}
+
+// ScramUser is a user name with its password, from which the broker derives the SCRAM credentials on startup.
+type ScramUser struct {
+	Name     string
+	Password string
+}
+
+// NewSasl reads the SASL settings. The mechanisms and SCRAM users can be set as comma separated strings, which is how they
+// are set in environment variables, or as lists in the config file.
+func NewSasl(env *viper.Viper) (*Sasl, error) {
+	sasl := Sasl{}
+
+	if mechanisms, ok := env.Get("sasl.enabled.mechanisms").([]any); ok {
+		for _, m := range mechanisms {
+			sasl.Mechanisms = append(sasl.Mechanisms, strings.ToUpper(fmt.Sprint(m)))
+		}
+	} else {
+		for _, m := range strings.Split(env.GetString("sasl.enabled.mechanisms"), ",") {
+			if m = strings.TrimSpace(m); m != "" {
+				sasl.Mechanisms = append(sasl.Mechanisms, strings.ToUpper(m))
+			}
+		}
+	}
+
+	if _, ok := env.Get("sasl.scram.passwords").([]any); ok {
+		if err := env.UnmarshalKey("sasl.scram.passwords", &sasl.ScramUsers); err != nil {
+			return nil, fmt.Errorf("error reading sasl.scram.passwords: %w", err)
+		}
+	} else if users := env.GetString("sasl.scram.passwords"); users != "" {
+		// the first ':' separates the name from the password, so passwords can contain it
+		for _, u := range strings.Split(users, ",") {
+			name, password, ok := strings.Cut(u, ":")
+			if !ok {
+				return nil, fmt.Errorf("sasl.scram.passwords entries must have the form name:password")
+			}
+			sasl.ScramUsers = append(sasl.ScramUsers, ScramUser{Name: name, Password: password})
+		}
+	}
+
+	for _, u := range sasl.ScramUsers {
+		if u.Name == "" || u.Password == "" {
+			return nil, fmt.Errorf("sasl.scram.passwords entries must have a name and a password")
+		}
+	}
+
+	sasl.OAuthBearerJWKSURL = env.GetString("sasl.oauthbearer.jwks.endpoint.url")
+	sasl.OAuthBearerJWKSRefreshInterval = time.Duration(env.GetInt64("sasl.oauthbearer.jwks.endpoint.refresh.ms")) * time.Millisecond
+	if sasl.OAuthBearerJWKSURL != "" {
+		if u, err := url.Parse(sasl.OAuthBearerJWKSURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("sasl.oauthbearer.jwks.endpoint.url %s must be an http or https URL", sasl.OAuthBearerJWKSURL)
+		}
+		if sasl.OAuthBearerJWKSRefreshInterval <= 0 {
+			return nil, fmt.Errorf("sasl.oauthbearer.jwks.endpoint.refresh.ms must be positive, got %d", sasl.OAuthBearerJWKSRefreshInterval.Milliseconds())
+		}
+	}
+	sasl.OAuthBearerExpectedIssuer = env.GetString("sasl.oauthbearer.expected.issuer")
+	sasl.OAuthBearerExpectedAudience = env.GetString("sasl.oauthbearer.expected.audience")
+	sasl.OAuthBearerUnsecured = env.GetBool("sasl.oauthbearer.unsecured")
+
+	return &sasl, nil
+}
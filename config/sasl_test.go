@@ -0,0 +1,101 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestNewSasl(t *testing.T) {
+	t.Setenv("OT_SASL_ENABLED_MECHANISMS", "scram-sha-512")
+	t.Setenv("OT_SASL_SCRAM_PASSWORDS", "alice:se:cret,bob:pw")
+
+	env := viper.New()
+	env.AutomaticEnv()
+	env.SetEnvPrefix("ot")
+	env.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+
+	sasl, err := NewSasl(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(sasl.Mechanisms, []string{"SCRAM-SHA-512"}) {
+		t.Errorf("mechanisms = %v, want [SCRAM-SHA-512]", sasl.Mechanisms)
+	}
+	want := []ScramUser{{Name: "alice", Password: "se:cret"}, {Name: "bob", Password: "pw"}}
+	if !slices.Equal(sasl.ScramUsers, want) {
+		t.Errorf("users = %+v, want %+v", sasl.ScramUsers, want)
+	}
+
+	t.Setenv("OT_SASL_SCRAM_PASSWORDS", "alice")
+	if _, err := NewSasl(env); err == nil {
+		t.Error("user without password was accepted")
+	}
+}
+
+func TestNewSasl_ConfigFile(t *testing.T) {
+	env := viper.New()
+	env.SetConfigType("yaml")
+	conf := `
+sasl:
+  enabled.mechanisms:
+    - SCRAM-SHA-256
+    - SCRAM-SHA-512
+  scram.passwords:
+    - name: alice
+      password: secret
+`
+	if err := env.ReadConfig(strings.NewReader(conf)); err != nil {
+		t.Fatal(err)
+	}
+
+	sasl, err := NewSasl(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(sasl.Mechanisms, []string{"SCRAM-SHA-256", "SCRAM-SHA-512"}) {
+		t.Errorf("mechanisms = %v, want [SCRAM-SHA-256 SCRAM-SHA-512]", sasl.Mechanisms)
+	}
+	if want := []ScramUser{{Name: "alice", Password: "secret"}}; !slices.Equal(sasl.ScramUsers, want) {
+		t.Errorf("users = %+v, want %+v", sasl.ScramUsers, want)
+	}
+}
+
+func TestNewSasl_OAuthBearer(t *testing.T) {
+	t.Setenv("OT_SASL_OAUTHBEARER_JWKS_ENDPOINT_URL", "https://idp.example.com/jwks")
+	t.Setenv("OT_SASL_OAUTHBEARER_JWKS_ENDPOINT_REFRESH_MS", "60000")
+	t.Setenv("OT_SASL_OAUTHBEARER_EXPECTED_ISSUER", "https://idp.example.com")
+	t.Setenv("OT_SASL_OAUTHBEARER_EXPECTED_AUDIENCE", "kafka")
+
+	env := viper.New()
+	env.AutomaticEnv()
+	env.SetEnvPrefix("ot")
+	env.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+
+	sasl, err := NewSasl(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sasl.OAuthBearerJWKSURL != "https://idp.example.com/jwks" || sasl.OAuthBearerJWKSRefreshInterval != time.Minute {
+		t.Errorf("JWKS endpoint = %s refreshed every %s, want https://idp.example.com/jwks every minute", sasl.OAuthBearerJWKSURL, sasl.OAuthBearerJWKSRefreshInterval)
+	}
+	if sasl.OAuthBearerExpectedIssuer != "https://idp.example.com" || sasl.OAuthBearerExpectedAudience != "kafka" {
+		t.Errorf("expected issuer %q and audience %q, want https://idp.example.com and kafka", sasl.OAuthBearerExpectedIssuer, sasl.OAuthBearerExpectedAudience)
+	}
+	if sasl.OAuthBearerUnsecured {
+		t.Error("unsecured tokens accepted without sasl.oauthbearer.unsecured")
+	}
+
+	t.Setenv("OT_SASL_OAUTHBEARER_JWKS_ENDPOINT_URL", "file:///etc/jwks.json")
+	if _, err := NewSasl(env); err == nil {
+		t.Error("JWKS endpoint without http scheme was accepted")
+	}
+	t.Setenv("OT_SASL_OAUTHBEARER_JWKS_ENDPOINT_URL", "https://idp.example.com/jwks")
+	t.Setenv("OT_SASL_OAUTHBEARER_JWKS_ENDPOINT_REFRESH_MS", "0")
+	if _, err := NewSasl(env); err == nil {
+		t.Error("refresh interval of 0 was accepted")
+	}
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"opentalaria/logger"
+	"os"
+	"slices"
+	"strings"
+)
+
+// redactedValue replaces the values of sensitive properties, like Kafka does in its logs.
+const redactedValue = "[hidden]"
+
+// IsSensitive reports whether the value of the configuration property must not be disclosed, in logs or to clients.
+func IsSensitive(key string) bool {
+	key = strings.ToLower(key)
+	return strings.Contains(key, "password") || strings.Contains(key, "secret")
+}
+
+// LogAttrs returns the effective configuration as grouped log attributes, so operators can see on startup what took effect
+// across the config file, environment variables and defaults. Properties set by the user are listed under "overrides",
+// with the values of sensitive properties redacted.
+func (c *Config) LogAttrs() []any {
+	attrs := []any{
+		slog.Int("broker.id", int(c.Broker.BrokerID)),
+		slog.String("cluster.id", c.Cluster.ClusterID),
+		listenerGroup("listeners", c.Broker.Listeners),
+		listenerGroup("advertised.listeners", c.Broker.AdvertisedListeners),
+		slog.Group("log",
+			slog.String("level", logger.LevelName(c.LogLevel.Level())),
+			slog.String("format", c.LogFormat),
+			slog.String("file", c.LogFile),
+		),
+	}
+
+	if c.Env == nil {
+		return attrs
+	}
+
+	if profile := c.Env.GetString("profile"); profile != "" {
+		attrs = append(attrs, slog.String("profile", profile))
+	}
+
+	keys := c.Env.AllKeys()
+	slices.Sort(keys)
+
+	// listeners are already part of the summary, parsed into their fields
+	skip := map[string]bool{"listeners": true, "advertised.listeners": true}
+
+	var overrides []any
+	for _, key := range keys {
+		if skip[key] || !c.IsSetByUser(key) {
+			continue
+		}
+
+		overrides = append(overrides, slog.String(key, redact(key, fmt.Sprint(c.Env.Get(key)))))
+	}
+
+	// viper only knows the keys of environment variables which have a default or are in the config file,
+	// the others are listed by the name of the variable
+	known := map[string]bool{}
+	for _, key := range append(keys, "listeners", "advertised.listeners") {
+		known[c.envVarName(key)] = true
+	}
+	env := os.Environ()
+	slices.Sort(env)
+	for _, kv := range env {
+		name, value, _ := strings.Cut(kv, "=")
+		if strings.HasPrefix(name, c.envPrefix+"_") && !known[name] {
+			overrides = append(overrides, slog.String(name, redact(name, value)))
+		}
+	}
+	if len(overrides) > 0 {
+		attrs = append(attrs, slog.Group("overrides", overrides...))
+	}
+
+	return attrs
+}
+
+// redact returns the value of the property, unless it is sensitive.
+func redact(key, value string) string {
+	if IsSensitive(key) {
+		return redactedValue
+	}
+
+	return value
+}
+
+// listenerGroup returns the listeners as a group of attributes, one group per listener name.
+func listenerGroup(name string, listeners []Listener) slog.Attr {
+	var attrs []any
+	for _, l := range listeners {
+		attrs = append(attrs, slog.Group(l.ListenerName,
+			slog.String("host", l.Host),
+			slog.Int("port", int(l.Port)),
+			slog.String("protocol", l.SecurityProtocol.String()),
+		))
+	}
+
+	return slog.Group(name, attrs...)
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestConfig_LogAttrs(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:9092")
+	t.Setenv("OT_SSL_KEYSTORE_PASSWORD", "hunter2")
+
+	conf, err := NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	slog.New(slog.NewJSONHandler(buf, nil)).Info("effective configuration", conf.LogAttrs()...)
+
+	var record struct {
+		Listeners map[string]struct {
+			Host     string
+			Port     int
+			Protocol string
+		}
+		Overrides map[string]string
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("error decoding log record %s: %v", buf, err)
+	}
+
+	listener := record.Listeners["plaintext"]
+	if listener.Host != "localhost" || listener.Port != 9092 || listener.Protocol != "PLAINTEXT" {
+		t.Errorf("listener = %+v, want PLAINTEXT on localhost:9092", listener)
+	}
+
+	if record.Overrides["OT_SSL_KEYSTORE_PASSWORD"] != redactedValue {
+		t.Errorf("password = %q, want it redacted", record.Overrides["OT_SSL_KEYSTORE_PASSWORD"])
+	}
+	if bytes.Contains(buf.Bytes(), []byte("hunter2")) {
+		t.Errorf("log record %s contains the password", buf)
+	}
+}
@@ -0,0 +1,121 @@
+// Package coordinator implements the group coordinator, which manages the membership of consumer groups
+// and the rebalances which distribute the partitions among the members.
+// Since OpenTalaria runs as a single node, this broker coordinates all groups, and their state is kept in memory.
+package coordinator
+
+import (
+	"sync"
+	"time"
+
+	"opentalaria/utils"
+
+	"github.com/google/uuid"
+)
+
+// GroupCoordinator holds the consumer groups of the broker.
+// It is safe for concurrent use, since the members of a group connect through different client connections.
+type GroupCoordinator struct {
+	mu     sync.Mutex
+	groups map[string]*Group
+
+	minSessionTimeout time.Duration
+	maxSessionTimeout time.Duration
+}
+
+// NewGroupCoordinator returns a coordinator without groups. Members must use a session timeout within the given bounds.
+func NewGroupCoordinator(minSessionTimeout, maxSessionTimeout time.Duration) *GroupCoordinator {
+	return &GroupCoordinator{
+		groups:            map[string]*Group{},
+		minSessionTimeout: minSessionTimeout,
+		maxSessionTimeout: maxSessionTimeout,
+	}
+}
+
+// Group returns the group with the given ID.
+func (c *GroupCoordinator) Group(groupID string) (*Group, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	group, ok := c.groups[groupID]
+	return group, ok
+}
+
+// JoinGroup adds the member to the group, creating the group if it doesn't exist, and waits for the rebalance to complete.
+// A member joining or changing its protocols starts a rebalance, which completes once all known members have rejoined
+// or the rebalance timeout has expired. The group then moves to the next generation, with the first member as leader.
+func (c *GroupCoordinator) JoinGroup(groupID string, req JoinRequest) JoinResult {
+	if groupID == "" {
+		return JoinResult{Err: utils.ErrInvalidGroupId, MemberID: req.MemberID, GenerationID: -1}
+	}
+
+	if req.SessionTimeout < c.minSessionTimeout || req.SessionTimeout > c.maxSessionTimeout {
+		return JoinResult{Err: utils.ErrInvalidSessionTimeout, MemberID: req.MemberID, GenerationID: -1}
+	}
+
+	group := c.getOrCreateGroup(groupID)
+
+	group.mu.Lock()
+	result := group.join(req, func() string { return newMemberID(req.ClientID) })
+	group.mu.Unlock()
+
+	return <-result
+}
+
+// SyncGroup returns the assignment of the member for the generation. The assignments are sent by the leader,
+// so the other members wait for the leader to sync the group.
+func (c *GroupCoordinator) SyncGroup(groupID string, generationID int32, memberID string, assignments map[string][]byte) SyncResult {
+	group, ok := c.Group(groupID)
+	if !ok {
+		return SyncResult{Err: utils.ErrUnknownMemberId}
+	}
+
+	group.mu.Lock()
+	result := group.sync(generationID, memberID, assignments)
+	group.mu.Unlock()
+
+	return <-result
+}
+
+// Heartbeat keeps the session of the member alive. utils.ErrRebalanceInProgress tells the member to rejoin the group.
+func (c *GroupCoordinator) Heartbeat(groupID string, generationID int32, memberID string) error {
+	group, ok := c.Group(groupID)
+	if !ok {
+		return utils.ErrUnknownMemberId
+	}
+
+	group.mu.Lock()
+	defer group.mu.Unlock()
+
+	return group.heartbeat(generationID, memberID)
+}
+
+// LeaveGroup removes the member from the group, which starts a rebalance of the remaining members.
+func (c *GroupCoordinator) LeaveGroup(groupID string, memberID string) error {
+	group, ok := c.Group(groupID)
+	if !ok {
+		return utils.ErrUnknownMemberId
+	}
+
+	group.mu.Lock()
+	defer group.mu.Unlock()
+
+	return group.leave(memberID)
+}
+
+func (c *GroupCoordinator) getOrCreateGroup(groupID string) *Group {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	group, ok := c.groups[groupID]
+	if !ok {
+		group = newGroup(groupID)
+		c.groups[groupID] = group
+	}
+
+	return group
+}
+
+// newMemberID generates a member ID prefixed with the client ID, like Kafka does, so members can be told apart in logs.
+func newMemberID(clientID string) string {
+	return clientID + "-" + uuid.NewString()
+}
@@ -0,0 +1,223 @@
+package coordinator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"opentalaria/utils"
+)
+
+const testGroup = "test-group"
+
+func newTestCoordinator() *GroupCoordinator {
+	return NewGroupCoordinator(0, time.Minute)
+}
+
+func joinRequest(memberID string, sessionTimeout time.Duration) JoinRequest {
+	return JoinRequest{
+		MemberID:         memberID,
+		ClientID:         "test-client",
+		ProtocolType:     "consumer",
+		Protocols:        []Protocol{{Name: "range", Metadata: []byte("subscription")}},
+		SessionTimeout:   sessionTimeout,
+		RebalanceTimeout: time.Second,
+	}
+}
+
+// joinAsync joins the group in the background, since joins block until the rebalance completes.
+func joinAsync(c *GroupCoordinator, req JoinRequest) <-chan JoinResult {
+	result := make(chan JoinResult, 1)
+	go func() {
+		result <- c.JoinGroup(testGroup, req)
+	}()
+
+	return result
+}
+
+func awaitJoin(t *testing.T, result <-chan JoinResult) JoinResult {
+	t.Helper()
+
+	select {
+	case r := <-result:
+		if r.Err != nil {
+			t.Fatalf("join failed: %v", r.Err)
+		}
+		return r
+	case <-time.After(5 * time.Second):
+		t.Fatal("join did not complete")
+		return JoinResult{}
+	}
+}
+
+// waitForState polls the group, since timers change its state in the background.
+func waitForState(t *testing.T, group *Group, state GroupState) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for group.State() != state {
+		if time.Now().After(deadline) {
+			t.Fatalf("group state = %s, want %s", group.State(), state)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestGroupCoordinator_SingleMember(t *testing.T) {
+	c := newTestCoordinator()
+
+	join := c.JoinGroup(testGroup, joinRequest("", time.Minute))
+	if join.Err != nil {
+		t.Fatal(join.Err)
+	}
+	if join.GenerationID != 1 {
+		t.Errorf("generation = %d, want 1", join.GenerationID)
+	}
+	if join.LeaderID != join.MemberID {
+		t.Errorf("leader = %s, want the only member %s", join.LeaderID, join.MemberID)
+	}
+	if join.ProtocolName != "range" || join.ProtocolType != "consumer" {
+		t.Errorf("protocol = %s/%s, want consumer/range", join.ProtocolType, join.ProtocolName)
+	}
+	if len(join.Members) != 1 || string(join.Members[0].Metadata) != "subscription" {
+		t.Errorf("members = %+v, want the leader with its subscription", join.Members)
+	}
+
+	sync := c.SyncGroup(testGroup, join.GenerationID, join.MemberID, map[string][]byte{join.MemberID: []byte("assignment")})
+	if sync.Err != nil {
+		t.Fatal(sync.Err)
+	}
+	if string(sync.Assignment) != "assignment" {
+		t.Errorf("assignment = %q, want the assignment of the leader passed through", sync.Assignment)
+	}
+
+	if err := c.Heartbeat(testGroup, join.GenerationID, join.MemberID); err != nil {
+		t.Errorf("heartbeat: %v", err)
+	}
+
+	group, _ := c.Group(testGroup)
+	if group.State() != Stable {
+		t.Errorf("state = %s, want Stable", group.State())
+	}
+}
+
+func TestGroupCoordinator_GenerationOnMembershipChange(t *testing.T) {
+	c := newTestCoordinator()
+
+	first := c.JoinGroup(testGroup, joinRequest("", time.Minute))
+	c.SyncGroup(testGroup, first.GenerationID, first.MemberID, map[string][]byte{first.MemberID: []byte("all")})
+
+	// a second member starts a rebalance, which waits for the first member to rejoin
+	secondJoin := joinAsync(c, joinRequest("", time.Minute))
+
+	group, _ := c.Group(testGroup)
+	waitForState(t, group, PreparingRebalance)
+	if err := c.Heartbeat(testGroup, first.GenerationID, first.MemberID); !errors.Is(err, utils.ErrRebalanceInProgress) {
+		t.Fatalf("heartbeat during rebalance = %v, want %v", err, utils.ErrRebalanceInProgress)
+	}
+
+	firstRejoin := awaitJoin(t, joinAsync(c, joinRequest(first.MemberID, time.Minute)))
+	second := awaitJoin(t, secondJoin)
+
+	if firstRejoin.GenerationID != 2 || second.GenerationID != 2 {
+		t.Fatalf("generations = %d, %d, want 2", firstRejoin.GenerationID, second.GenerationID)
+	}
+	if second.LeaderID != first.MemberID || len(firstRejoin.Members) != 2 || len(second.Members) != 0 {
+		t.Errorf("leader %s with members %+v, follower members %+v, want the first member to lead both", second.LeaderID, firstRejoin.Members, second.Members)
+	}
+
+	// the follower waits for the assignment of the leader
+	followerSync := make(chan SyncResult, 1)
+	go func() {
+		followerSync <- c.SyncGroup(testGroup, 2, second.MemberID, nil)
+	}()
+	c.SyncGroup(testGroup, 2, first.MemberID, map[string][]byte{first.MemberID: []byte("p0"), second.MemberID: []byte("p1")})
+	if sync := <-followerSync; sync.Err != nil || string(sync.Assignment) != "p1" {
+		t.Errorf("follower sync = %+v, want assignment p1", sync)
+	}
+
+	// the stale generation is rejected
+	if err := c.Heartbeat(testGroup, 1, first.MemberID); !errors.Is(err, utils.ErrIllegalGeneration) {
+		t.Errorf("heartbeat of generation 1 = %v, want %v", err, utils.ErrIllegalGeneration)
+	}
+
+	// a member leaving starts the next generation for the remaining member
+	if err := c.LeaveGroup(testGroup, second.MemberID); err != nil {
+		t.Fatal(err)
+	}
+	firstRejoin = awaitJoin(t, joinAsync(c, joinRequest(first.MemberID, time.Minute)))
+	if firstRejoin.GenerationID != 3 {
+		t.Errorf("generation after leave = %d, want 3", firstRejoin.GenerationID)
+	}
+
+	// the last member leaving empties the group, which is a new generation as well
+	if err := c.LeaveGroup(testGroup, first.MemberID); err != nil {
+		t.Fatal(err)
+	}
+	if group.State() != Empty || group.GenerationID() != 4 {
+		t.Errorf("group %s at generation %d, want Empty at generation 4", group.State(), group.GenerationID())
+	}
+}
+
+func TestGroupCoordinator_RebalanceTimeout(t *testing.T) {
+	c := newTestCoordinator()
+
+	req := joinRequest("", time.Minute)
+	req.RebalanceTimeout = 50 * time.Millisecond
+	first := c.JoinGroup(testGroup, req)
+	c.SyncGroup(testGroup, first.GenerationID, first.MemberID, nil)
+
+	// the first member never rejoins, so the rebalance completes without it
+	second := awaitJoin(t, joinAsync(c, req))
+
+	if second.GenerationID != 2 || second.LeaderID != second.MemberID {
+		t.Errorf("generation %d led by %s, want generation 2 led by %s", second.GenerationID, second.LeaderID, second.MemberID)
+	}
+
+	group, _ := c.Group(testGroup)
+	if members := group.MemberIDs(); len(members) != 1 || members[0] != second.MemberID {
+		t.Errorf("members = %v, want only %s", members, second.MemberID)
+	}
+}
+
+func TestGroupCoordinator_SessionExpiry(t *testing.T) {
+	c := newTestCoordinator()
+
+	join := c.JoinGroup(testGroup, joinRequest("", 50*time.Millisecond))
+	c.SyncGroup(testGroup, join.GenerationID, join.MemberID, nil)
+
+	// without heartbeats the member is removed, which empties the group
+	group, _ := c.Group(testGroup)
+	waitForState(t, group, Empty)
+
+	if group.GenerationID() != 2 {
+		t.Errorf("generation = %d, want 2", group.GenerationID())
+	}
+	if err := c.Heartbeat(testGroup, join.GenerationID, join.MemberID); !errors.Is(err, utils.ErrUnknownMemberId) {
+		t.Errorf("heartbeat of expired member = %v, want %v", err, utils.ErrUnknownMemberId)
+	}
+}
+
+func TestGroupCoordinator_JoinErrors(t *testing.T) {
+	c := NewGroupCoordinator(time.Second, time.Minute)
+
+	if r := c.JoinGroup("", joinRequest("", time.Minute)); !errors.Is(r.Err, utils.ErrInvalidGroupId) {
+		t.Errorf("empty group id: %v, want %v", r.Err, utils.ErrInvalidGroupId)
+	}
+
+	if r := c.JoinGroup(testGroup, joinRequest("", time.Millisecond)); !errors.Is(r.Err, utils.ErrInvalidSessionTimeout) {
+		t.Errorf("short session timeout: %v, want %v", r.Err, utils.ErrInvalidSessionTimeout)
+	}
+
+	if r := c.JoinGroup(testGroup, joinRequest("unknown", time.Minute)); !errors.Is(r.Err, utils.ErrUnknownMemberId) {
+		t.Errorf("unknown member: %v, want %v", r.Err, utils.ErrUnknownMemberId)
+	}
+
+	c.JoinGroup(testGroup, joinRequest("", time.Minute))
+
+	req := joinRequest("", time.Minute)
+	req.Protocols = []Protocol{{Name: "roundrobin"}}
+	if r := c.JoinGroup(testGroup, req); !errors.Is(r.Err, utils.ErrInconsistentGroupProtocol) {
+		t.Errorf("inconsistent protocol: %v, want %v", r.Err, utils.ErrInconsistentGroupProtocol)
+	}
+}
@@ -0,0 +1,569 @@
+package coordinator
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"opentalaria/utils"
+)
+
+// GroupState is the state of the rebalance protocol of a consumer group.
+type GroupState int
+
+const (
+	// Empty groups have no members, but may still have committed offsets.
+	Empty GroupState = iota
+	// PreparingRebalance groups wait for all members to rejoin.
+	PreparingRebalance
+	// CompletingRebalance groups wait for the leader to send the assignments.
+	CompletingRebalance
+	// Stable groups have a generation whose assignments were sent to the members.
+	Stable
+)
+
+func (s GroupState) String() string {
+	switch s {
+	case Empty:
+		return "Empty"
+	case PreparingRebalance:
+		return "PreparingRebalance"
+	case CompletingRebalance:
+		return "CompletingRebalance"
+	case Stable:
+		return "Stable"
+	default:
+		return "Unknown"
+	}
+}
+
+// Protocol is an assignment protocol supported by a member, with the member's metadata for it.
+type Protocol struct {
+	Name     string
+	Metadata []byte
+}
+
+// MemberMetadata describes a member to the group leader, with its metadata for the selected protocol.
+type MemberMetadata struct {
+	MemberID        string
+	GroupInstanceID *string
+	Metadata        []byte
+}
+
+// JoinRequest contains the parameters of a member joining the group. An empty MemberID joins a new member.
+type JoinRequest struct {
+	MemberID         string
+	GroupInstanceID  *string
+	ClientID         string
+	ClientHost       string
+	ProtocolType     string
+	Protocols        []Protocol
+	SessionTimeout   time.Duration
+	RebalanceTimeout time.Duration
+}
+
+// JoinResult is the outcome of a join. Only the leader receives the metadata of all members.
+// Err is a utils.KError, so it can be sent back to the client as is.
+type JoinResult struct {
+	Err          error
+	GenerationID int32
+	ProtocolType string
+	ProtocolName string
+	LeaderID     string
+	MemberID     string
+	Members      []MemberMetadata
+}
+
+// SyncResult is the assignment of a member after the leader synced the group.
+type SyncResult struct {
+	Err          error
+	ProtocolType string
+	ProtocolName string
+	Assignment   []byte
+}
+
+type member struct {
+	memberID         string
+	groupInstanceID  *string
+	clientID         string
+	clientHost       string
+	protocols        []Protocol
+	sessionTimeout   time.Duration
+	rebalanceTimeout time.Duration
+	assignment       []byte
+
+	// awaitingJoin and awaitingSync are set while the member waits for the rebalance to complete
+	awaitingJoin chan JoinResult
+	awaitingSync chan SyncResult
+
+	lastHeartbeat time.Time
+	sessionTimer  *time.Timer
+}
+
+func (m *member) supportsProtocol(name string) bool {
+	return slices.ContainsFunc(m.protocols, func(p Protocol) bool { return p.Name == name })
+}
+
+func (m *member) metadata(protocolName string) []byte {
+	for _, p := range m.protocols {
+		if p.Name == protocolName {
+			return p.Metadata
+		}
+	}
+
+	return nil
+}
+
+// Group is the in-memory state of a consumer group and its committed offsets.
+// A new generation starts every time the membership changes, so the generation id identifies the assignments of the members.
+type Group struct {
+	mu sync.Mutex
+
+	groupID      string
+	state        GroupState
+	generationID int32
+	protocolType string
+	protocolName string
+	leaderID     string
+	members      map[string]*member
+	offsets      map[TopicPartition]OffsetAndMetadata
+
+	rebalanceTimer *time.Timer
+}
+
+func newGroup(groupID string) *Group {
+	return &Group{
+		groupID: groupID,
+		state:   Empty,
+		members: map[string]*member{},
+		offsets: map[TopicPartition]OffsetAndMetadata{},
+	}
+}
+
+// State returns the state of the group.
+func (g *Group) State() GroupState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.state
+}
+
+// GenerationID returns the current generation of the group.
+func (g *Group) GenerationID() int32 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.generationID
+}
+
+// MemberIDs returns the IDs of the members of the group, sorted.
+func (g *Group) MemberIDs() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.sortedMemberIDs()
+}
+
+// join adds or updates the member and returns the channel on which the result of the join is delivered.
+// The caller must hold the lock of the group.
+func (g *Group) join(req JoinRequest, newMemberID func() string) <-chan JoinResult {
+	result := make(chan JoinResult, 1)
+
+	if req.ProtocolType == "" || len(req.Protocols) == 0 ||
+		(g.state != Empty && (req.ProtocolType != g.protocolType || !g.supportsProtocols(req.Protocols))) {
+		result <- JoinResult{Err: utils.ErrInconsistentGroupProtocol, MemberID: req.MemberID, GenerationID: -1}
+		return result
+	}
+
+	if req.MemberID == "" {
+		m := &member{memberID: newMemberID()}
+		g.members[m.memberID] = m
+		g.updateMember(m, req)
+
+		if g.leaderID == "" {
+			g.leaderID = m.memberID
+		}
+
+		m.awaitingJoin = result
+		if g.state != PreparingRebalance {
+			g.prepareRebalance()
+		}
+		g.maybeCompleteJoin()
+
+		return result
+	}
+
+	m, ok := g.members[req.MemberID]
+	if !ok {
+		result <- JoinResult{Err: utils.ErrUnknownMemberId, MemberID: req.MemberID, GenerationID: -1}
+		return result
+	}
+
+	protocolsChanged := !slices.EqualFunc(m.protocols, req.Protocols, func(a, b Protocol) bool {
+		return a.Name == b.Name && slices.Equal(a.Metadata, b.Metadata)
+	})
+	g.updateMember(m, req)
+
+	// a member which didn't change rejoining a settled group, e.g. after its join response was lost, gets the current generation
+	if g.state == CompletingRebalance || (g.state == Stable && m.memberID != g.leaderID) {
+		if !protocolsChanged {
+			result <- g.joinResult(m)
+			return result
+		}
+	}
+
+	// the previous join of the member, if any, is replaced, since its client gave up on it
+	if m.awaitingJoin != nil {
+		m.awaitingJoin <- JoinResult{Err: utils.ErrRebalanceInProgress, MemberID: m.memberID, GenerationID: -1}
+	}
+	m.awaitingJoin = result
+
+	if g.state != PreparingRebalance {
+		g.prepareRebalance()
+	}
+	g.maybeCompleteJoin()
+
+	return result
+}
+
+func (g *Group) updateMember(m *member, req JoinRequest) {
+	m.groupInstanceID = req.GroupInstanceID
+	m.clientID = req.ClientID
+	m.clientHost = req.ClientHost
+	m.protocols = req.Protocols
+	m.sessionTimeout = req.SessionTimeout
+	m.rebalanceTimeout = req.RebalanceTimeout
+	m.lastHeartbeat = time.Now()
+
+	if g.state == Empty {
+		g.protocolType = req.ProtocolType
+	}
+}
+
+// supportsProtocols reports whether at least one of the protocols is supported by all members of the group.
+func (g *Group) supportsProtocols(protocols []Protocol) bool {
+	for _, p := range protocols {
+		supported := true
+		for _, m := range g.members {
+			if !m.supportsProtocol(p.Name) {
+				supported = false
+				break
+			}
+		}
+
+		if supported {
+			return true
+		}
+	}
+
+	return false
+}
+
+// prepareRebalance starts a new rebalance. Members which are waiting for their assignment have to rejoin,
+// and members which don't rejoin within the rebalance timeout are removed from the group.
+func (g *Group) prepareRebalance() {
+	if g.state == CompletingRebalance {
+		for _, m := range g.members {
+			if m.awaitingSync != nil {
+				m.awaitingSync <- SyncResult{Err: utils.ErrRebalanceInProgress}
+				m.awaitingSync = nil
+			}
+		}
+	}
+
+	g.state = PreparingRebalance
+
+	var rebalanceTimeout time.Duration
+	for _, m := range g.members {
+		rebalanceTimeout = max(rebalanceTimeout, m.rebalanceTimeout)
+	}
+
+	if g.rebalanceTimer != nil {
+		g.rebalanceTimer.Stop()
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(rebalanceTimeout, func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+
+		// a stopped timer may have fired already, so only the current one expires the rebalance
+		if g.rebalanceTimer == timer {
+			g.expireRebalance()
+		}
+	})
+	g.rebalanceTimer = timer
+}
+
+// expireRebalance removes the members which didn't rejoin in time and completes the rebalance without them.
+func (g *Group) expireRebalance() {
+	if g.state != PreparingRebalance {
+		return
+	}
+
+	for _, m := range g.members {
+		if m.awaitingJoin == nil {
+			g.removeMember(m)
+		}
+	}
+
+	g.completeJoin()
+}
+
+// maybeCompleteJoin completes the rebalance if all members have rejoined.
+func (g *Group) maybeCompleteJoin() {
+	if g.state != PreparingRebalance {
+		return
+	}
+
+	for _, m := range g.members {
+		if m.awaitingJoin == nil {
+			return
+		}
+	}
+
+	g.completeJoin()
+}
+
+// completeJoin starts the next generation of the group and answers the pending joins.
+func (g *Group) completeJoin() {
+	if g.rebalanceTimer != nil {
+		g.rebalanceTimer.Stop()
+		g.rebalanceTimer = nil
+	}
+
+	g.generationID++
+
+	if len(g.members) == 0 {
+		g.state = Empty
+		g.protocolName = ""
+		g.leaderID = ""
+		return
+	}
+
+	g.state = CompletingRebalance
+	g.protocolName = g.selectProtocol()
+
+	for _, m := range g.members {
+		m.assignment = nil
+		m.awaitingJoin <- g.joinResult(m)
+		m.awaitingJoin = nil
+		m.lastHeartbeat = time.Now()
+		g.scheduleSessionExpiry(m)
+	}
+}
+
+// selectProtocol selects the protocol supported by all members which is preferred by most members.
+// Ties are broken by the preference of the leader.
+func (g *Group) selectProtocol() string {
+	candidates := []string{}
+	for _, p := range g.members[g.leaderID].protocols {
+		if g.supportsProtocols([]Protocol{p}) {
+			candidates = append(candidates, p.Name)
+		}
+	}
+
+	votes := map[string]int{}
+	for _, m := range g.members {
+		for _, p := range m.protocols {
+			if slices.Contains(candidates, p.Name) {
+				votes[p.Name]++
+				break
+			}
+		}
+	}
+
+	selected := ""
+	for _, name := range candidates {
+		if selected == "" || votes[name] > votes[selected] {
+			selected = name
+		}
+	}
+
+	return selected
+}
+
+func (g *Group) joinResult(m *member) JoinResult {
+	result := JoinResult{
+		GenerationID: g.generationID,
+		ProtocolType: g.protocolType,
+		ProtocolName: g.protocolName,
+		LeaderID:     g.leaderID,
+		MemberID:     m.memberID,
+		Members:      []MemberMetadata{},
+	}
+
+	if m.memberID == g.leaderID {
+		for _, id := range g.sortedMemberIDs() {
+			other := g.members[id]
+			result.Members = append(result.Members, MemberMetadata{
+				MemberID:        other.memberID,
+				GroupInstanceID: other.groupInstanceID,
+				Metadata:        other.metadata(g.protocolName),
+			})
+		}
+	}
+
+	return result
+}
+
+// sync stores the assignments sent by the leader and returns the channel on which the assignment of the member is delivered.
+// The caller must hold the lock of the group.
+func (g *Group) sync(generationID int32, memberID string, assignments map[string][]byte) <-chan SyncResult {
+	result := make(chan SyncResult, 1)
+
+	m, ok := g.members[memberID]
+	if !ok {
+		result <- SyncResult{Err: utils.ErrUnknownMemberId}
+		return result
+	}
+
+	if generationID != g.generationID {
+		result <- SyncResult{Err: utils.ErrIllegalGeneration}
+		return result
+	}
+
+	m.lastHeartbeat = time.Now()
+
+	switch g.state {
+	case PreparingRebalance:
+		result <- SyncResult{Err: utils.ErrRebalanceInProgress}
+	case CompletingRebalance:
+		m.awaitingSync = result
+
+		if memberID == g.leaderID {
+			for _, other := range g.members {
+				other.assignment = assignments[other.memberID]
+			}
+
+			g.state = Stable
+			for _, other := range g.members {
+				if other.awaitingSync != nil {
+					other.awaitingSync <- g.syncResult(other)
+					other.awaitingSync = nil
+				}
+			}
+		}
+	case Stable:
+		result <- g.syncResult(m)
+	default:
+		result <- SyncResult{Err: utils.ErrUnknownMemberId}
+	}
+
+	return result
+}
+
+func (g *Group) syncResult(m *member) SyncResult {
+	return SyncResult{
+		ProtocolType: g.protocolType,
+		ProtocolName: g.protocolName,
+		Assignment:   m.assignment,
+	}
+}
+
+// heartbeat keeps the session of the member alive and reports whether it has to rejoin the group.
+func (g *Group) heartbeat(generationID int32, memberID string) error {
+	m, ok := g.members[memberID]
+	if !ok {
+		return utils.ErrUnknownMemberId
+	}
+
+	if generationID != g.generationID {
+		return utils.ErrIllegalGeneration
+	}
+
+	m.lastHeartbeat = time.Now()
+
+	if g.state == PreparingRebalance {
+		return utils.ErrRebalanceInProgress
+	}
+
+	return nil
+}
+
+// leave removes the member from the group, which starts a rebalance of the remaining members.
+func (g *Group) leave(memberID string) error {
+	m, ok := g.members[memberID]
+	if !ok {
+		return utils.ErrUnknownMemberId
+	}
+
+	g.removeMember(m)
+
+	switch g.state {
+	case Stable, CompletingRebalance:
+		g.prepareRebalance()
+		g.maybeCompleteJoin()
+	case PreparingRebalance:
+		g.maybeCompleteJoin()
+	}
+
+	return nil
+}
+
+// removeMember removes the member, answers its pending requests and elects a new leader if it was the leader.
+func (g *Group) removeMember(m *member) {
+	delete(g.members, m.memberID)
+
+	if m.sessionTimer != nil {
+		m.sessionTimer.Stop()
+	}
+	if m.awaitingJoin != nil {
+		m.awaitingJoin <- JoinResult{Err: utils.ErrUnknownMemberId, MemberID: m.memberID, GenerationID: -1}
+		m.awaitingJoin = nil
+	}
+	if m.awaitingSync != nil {
+		m.awaitingSync <- SyncResult{Err: utils.ErrUnknownMemberId}
+		m.awaitingSync = nil
+	}
+
+	if g.leaderID == m.memberID {
+		g.leaderID = ""
+		if ids := g.sortedMemberIDs(); len(ids) > 0 {
+			g.leaderID = ids[0]
+		}
+	}
+}
+
+// scheduleSessionExpiry removes the member if it doesn't send a heartbeat within its session timeout.
+// The timer checks the time of the last heartbeat when it fires, so heartbeats don't have to reset it.
+func (g *Group) scheduleSessionExpiry(m *member) {
+	if m.sessionTimer != nil {
+		m.sessionTimer.Stop()
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(m.sessionTimeout, func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+
+		if g.members[m.memberID] != m || m.sessionTimer != timer {
+			return
+		}
+
+		// members waiting for a rebalance don't send heartbeats, the rebalance timeout applies to them instead
+		if m.awaitingJoin != nil || m.awaitingSync != nil {
+			g.scheduleSessionExpiry(m)
+			return
+		}
+
+		if remaining := m.sessionTimeout - time.Since(m.lastHeartbeat); remaining > 0 {
+			timer.Reset(remaining)
+			return
+		}
+
+		// the result is only an error if the member is unknown, which was checked above
+		_ = g.leave(m.memberID)
+	})
+	m.sessionTimer = timer
+}
+
+func (g *Group) sortedMemberIDs() []string {
+	ids := make([]string, 0, len(g.members))
+	for id := range g.members {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	return ids
+}
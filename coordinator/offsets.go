@@ -0,0 +1,106 @@
+package coordinator
+
+import (
+	"time"
+
+	"opentalaria/utils"
+)
+
+// TopicPartition identifies a partition of a topic.
+type TopicPartition struct {
+	Topic     string
+	Partition int32
+}
+
+// OffsetAndMetadata is the offset committed by a group for a partition.
+type OffsetAndMetadata struct {
+	Offset      int64
+	LeaderEpoch int32
+	Metadata    *string
+	CommitTime  time.Time
+}
+
+// CommitOffsets stores the offsets of the group, creating the group if it doesn't exist.
+// Members of the group must commit for the current generation. Consumers which don't use group management,
+// like consumers with manually assigned partitions, commit with generation -1 and without member ID, which is only
+// allowed while the group has no members.
+func (c *GroupCoordinator) CommitOffsets(groupID string, generationID int32, memberID string, offsets map[TopicPartition]OffsetAndMetadata) error {
+	if groupID == "" {
+		return utils.ErrInvalidGroupId
+	}
+
+	group := c.getOrCreateGroup(groupID)
+
+	group.mu.Lock()
+	defer group.mu.Unlock()
+
+	if err := group.validateCommit(generationID, memberID); err != nil {
+		return err
+	}
+
+	for tp, offset := range offsets {
+		group.offsets[tp] = offset
+	}
+
+	return nil
+}
+
+// FetchOffset returns the offset committed by the group for the partition.
+// It reports false if the group doesn't exist, or didn't commit an offset for the partition.
+func (c *GroupCoordinator) FetchOffset(groupID string, tp TopicPartition) (OffsetAndMetadata, bool) {
+	group, ok := c.Group(groupID)
+	if !ok {
+		return OffsetAndMetadata{}, false
+	}
+
+	group.mu.Lock()
+	defer group.mu.Unlock()
+
+	offset, ok := group.offsets[tp]
+	return offset, ok
+}
+
+// FetchAllOffsets returns all offsets committed by the group.
+func (c *GroupCoordinator) FetchAllOffsets(groupID string) map[TopicPartition]OffsetAndMetadata {
+	offsets := map[TopicPartition]OffsetAndMetadata{}
+
+	group, ok := c.Group(groupID)
+	if !ok {
+		return offsets
+	}
+
+	group.mu.Lock()
+	defer group.mu.Unlock()
+
+	for tp, offset := range group.offsets {
+		offsets[tp] = offset
+	}
+
+	return offsets
+}
+
+// validateCommit checks that the member is allowed to commit offsets for the generation.
+// The caller must hold the lock of the group.
+func (g *Group) validateCommit(generationID int32, memberID string) error {
+	if generationID < 0 && memberID == "" {
+		if g.state != Empty {
+			return utils.ErrUnknownMemberId
+		}
+		return nil
+	}
+
+	if _, ok := g.members[memberID]; !ok {
+		return utils.ErrUnknownMemberId
+	}
+
+	if generationID != g.generationID {
+		return utils.ErrIllegalGeneration
+	}
+
+	// the assignments of the generation are not known to the members yet
+	if g.state == CompletingRebalance {
+		return utils.ErrRebalanceInProgress
+	}
+
+	return nil
+}
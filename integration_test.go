@@ -0,0 +1,166 @@
+//go:build integration
+
+// The integration tests run a real Kafka client against the broker, to catch wire format mistakes which the
+// round trips of the unit tests can't. They need the confluent-kafka-go client, run them with `make integration`.
+package main
+
+import (
+	"context"
+	"net"
+	"opentalaria/config"
+	"opentalaria/protocol"
+	"opentalaria/utils"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+func TestIntegration_ProduceFetch(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:0")
+	t.Setenv("OT_ADVERTISED_LISTENERS", "PLAINTEXT://localhost:0")
+
+	conf, err := config.NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := server.listen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.registerBroker(); err != nil {
+		t.Fatal(err)
+	}
+	go server.serve(listener)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			t.Errorf("shutdown: %v", err)
+		}
+	}()
+
+	bootstrap := listener.Addr().String()
+	topic := "integration-topic"
+
+	t.Run("ApiVersions", func(t *testing.T) {
+		conn, err := net.Dial("tcp", bootstrap)
+		if err != nil {
+			t.Fatalf("error connecting: %v", err)
+		}
+		defer conn.Close()
+
+		clientID := "integration-client"
+		header := protocol.RequestHeader{Version: 1, RequestApiKey: (&protocol.ApiVersionsRequest{}).GetKey(), RequestApiVersion: 0, CorrelationID: 1, ClientID: &clientID}
+		writeTestRequest(t, conn, header, nil)
+
+		_, resp := readTestApiVersionsResponse(t, conn)
+		if resp.ErrorCode != int16(utils.ErrNoError) {
+			t.Fatalf("error code = %d, want no error", resp.ErrorCode)
+		}
+		for _, key := range []int16{
+			(&protocol.MetadataRequest{}).GetKey(),
+			(&protocol.CreateTopicsRequest{}).GetKey(),
+			(&protocol.ProduceRequest{}).GetKey(),
+			(&protocol.FetchRequest{}).GetKey(),
+		} {
+			if !slices.ContainsFunc(resp.ApiKeys, func(v protocol.ApiVersion) bool { return v.ApiKey == key }) {
+				t.Errorf("API key %d is not advertised", key)
+			}
+		}
+	})
+
+	t.Run("CreateTopics and Metadata", func(t *testing.T) {
+		admin, err := kafka.NewAdminClient(&kafka.ConfigMap{"bootstrap.servers": bootstrap})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer admin.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		results, err := admin.CreateTopics(ctx, []kafka.TopicSpecification{{Topic: topic, NumPartitions: 1, ReplicationFactor: 1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 || results[0].Error.Code() != kafka.ErrNoError {
+			t.Fatalf("create topics results = %v, want the topic created", results)
+		}
+
+		md, err := admin.GetMetadata(&topic, false, 10000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(md.Brokers) != 1 || md.Brokers[0].ID != conf.Broker.BrokerID {
+			t.Errorf("brokers = %v, want broker %d", md.Brokers, conf.Broker.BrokerID)
+		}
+		if tm, ok := md.Topics[topic]; !ok || tm.Error.Code() != kafka.ErrNoError || len(tm.Partitions) != 1 {
+			t.Errorf("topic metadata = %+v, want a single partition", tm)
+		}
+	})
+
+	values := []string{"first", "second", "third"}
+
+	t.Run("Produce", func(t *testing.T) {
+		producer, err := kafka.NewProducer(&kafka.ConfigMap{"bootstrap.servers": bootstrap, "acks": "all"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer producer.Close()
+
+		deliveries := make(chan kafka.Event, len(values))
+		for _, v := range values {
+			msg := &kafka.Message{TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: 0}, Value: []byte(v)}
+			if err := producer.Produce(msg, deliveries); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for i := range values {
+			select {
+			case e := <-deliveries:
+				m := e.(*kafka.Message)
+				if m.TopicPartition.Error != nil {
+					t.Fatalf("delivery of %q: %v", m.Value, m.TopicPartition.Error)
+				}
+				if m.TopicPartition.Offset != kafka.Offset(i) {
+					t.Errorf("offset of %q = %v, want %d", m.Value, m.TopicPartition.Offset, i)
+				}
+			case <-time.After(10 * time.Second):
+				t.Fatalf("timed out waiting for delivery %d", i)
+			}
+		}
+	})
+
+	t.Run("Fetch", func(t *testing.T) {
+		consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+			"bootstrap.servers":  bootstrap,
+			"group.id":           "integration-group",
+			"enable.auto.commit": false,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer consumer.Close()
+
+		// the partition is assigned directly, so the fetch doesn't depend on the group coordinator
+		if err := consumer.Assign([]kafka.TopicPartition{{Topic: &topic, Partition: 0, Offset: kafka.OffsetBeginning}}); err != nil {
+			t.Fatal(err)
+		}
+
+		for i, want := range values {
+			m, err := consumer.ReadMessage(10 * time.Second)
+			if err != nil {
+				t.Fatalf("reading message %d: %v", i, err)
+			}
+			if string(m.Value) != want || m.TopicPartition.Offset != kafka.Offset(i) {
+				t.Errorf("message %d = %q at offset %v, want %q at offset %d", i, m.Value, m.TopicPartition.Offset, want, i)
+			}
+		}
+	})
+}
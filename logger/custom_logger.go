@@ -1,12 +1,16 @@
 package logger
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
+	"net/netip"
 	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -18,6 +22,7 @@ const (
 	debug = "DEBUG"
 	err   = "ERROR"
 	warn  = "WARN"
+	trace = "TRACE"
 
 	green  = 32
 	yellow = 33
@@ -41,6 +46,8 @@ type Options struct {
 	// Levels with lower levels are discarded.
 	// If nil, the Handler uses [slog.LevelInfo].
 	Level slog.Leveler
+	// NoColor writes the time, level and message without ANSI colors, for output which isn't a terminal, like a log file.
+	NoColor bool
 }
 
 // NewCustomHandler creates a new CustomHandler instance.
@@ -58,21 +65,34 @@ func NewCustomHandler(out io.Writer, opts *Options) *CustomHandler {
 
 // Handle is a custom handler for logging records.
 func (ch *CustomHandler) Handle(ctx context.Context, r slog.Record) error {
-	indentLevel := 0
 	bufp := allocBuf()
 	buf := *bufp
 	defer func() {
 		*bufp = buf
 		freeBuf(bufp)
 	}()
-	lev, colCode := colorLogLevel(r.Level.String())
+	lev, colCode := colorLogLevel(LevelName(r.Level))
 
-	buf = formatLoggerOutput(buf, lev, r.Message, colCode)
+	if ch.opts.NoColor {
+		buf = formatPlainLoggerOutput(buf, LevelName(r.Level), r.Message)
+	} else {
+		buf = formatLoggerOutput(buf, lev, r.Message, colCode)
+	}
+	attrsStart := len(buf)
 
+	// attributes added with WithAttrs, then the attributes of the record inside the groups added with WithGroup
+	buf = append(buf, ch.preformatted...)
 	if r.NumAttrs() > 0 {
 		buf = ch.appendUnopenedGroups(buf, ch.indentLevel)
+		indentLevel := ch.indentLevel + len(ch.unopenedGroups)
 		r.Attrs(func(a slog.Attr) bool {
+			start := len(buf)
+			multiline := bytes.IndexByte(buf[attrsStart:], '\n') >= 0
 			buf = ch.appendAttr(buf, a, colCode, indentLevel)
+			// a top-level attribute following a group starts on a new line, instead of continuing the last line of the group
+			if multiline && indentLevel == 0 && len(buf) > start && buf[start] == ' ' {
+				buf[start] = '\n'
+			}
 			return true
 		})
 	}
@@ -121,6 +141,8 @@ func (ch *CustomHandler) WithGroup(name string) slog.Handler {
 	return &chCopy
 }
 
+// appendAttr formats the attribute as key: value. Top-level attributes follow the message on the same line,
+// the attributes of a group start on their own line below the key of the group, indented 4 spaces per level.
 func (ch *CustomHandler) appendAttr(buf []byte, a slog.Attr, colCode, indentLevel int) []byte {
 	// Resolve the Attr's value before doing anything else
 	a.Value = a.Value.Resolve()
@@ -129,21 +151,22 @@ func (ch *CustomHandler) appendAttr(buf []byte, a slog.Attr, colCode, indentLeve
 		return buf
 	}
 
-	// Indent 4 spaces per level.
-	buf = fmt.Appendf(buf, "%*s", indentLevel*4, "")
 	switch a.Value.Kind() {
 	case slog.KindString:
 		// Quote string values, to make them easy to parse.
-		buf = append(buf, " "...)
-		buf = append(buf, a.Key...)
+		buf = appendKey(buf, a.Key, indentLevel)
 		buf = append(buf, ": "...)
 		buf = strconv.AppendQuote(buf, a.Value.String())
 	case slog.KindTime:
 		// Write times in a standard way, without the monotonic time.
-		buf = append(buf, " "...)
-		buf = append(buf, a.Key...)
+		buf = appendKey(buf, a.Key, indentLevel)
 		buf = append(buf, ": "...)
 		buf = a.Value.Time().AppendFormat(buf, time.RFC3339Nano)
+	case slog.KindDuration:
+		// Write durations in human units, like 750ms, timeouts and intervals are read by operators.
+		buf = appendKey(buf, a.Key, indentLevel)
+		buf = append(buf, ": "...)
+		buf = append(buf, a.Value.Duration().String()...)
 	case slog.KindGroup:
 		attrs := a.Value.Group()
 		// Ignore empty groups.
@@ -153,21 +176,64 @@ func (ch *CustomHandler) appendAttr(buf []byte, a slog.Attr, colCode, indentLeve
 		// If the key is non-empty, write it out and indent the rest of the attrs.
 		// Otherwise, inline the attrs.
 		if a.Key != "" {
-			buf = fmt.Appendf(buf, "%s:\n", a.Key)
+			buf = appendKey(buf, a.Key, indentLevel)
+			buf = append(buf, ':')
 			indentLevel++
 		}
 		for _, ga := range attrs {
 			buf = ch.appendAttr(buf, ga, colCode, indentLevel)
 		}
 	default:
-		buf = append(buf, a.Key...)
+		buf = appendKey(buf, a.Key, indentLevel)
+		value := formatValue(a.Value)
+		// Multi-line values, like hex dumps, start on a new line and are indented one level below their key.
+		if strings.Contains(value, "\n") {
+			buf = append(buf, ':')
+			for _, line := range strings.Split(strings.TrimSuffix(value, "\n"), "\n") {
+				buf = fmt.Appendf(buf, "\n%*s%s", (indentLevel+1)*4, "", line)
+			}
+			break
+		}
 		buf = append(buf, ": "...)
-		buf = append(buf, a.Value.String()...)
-		buf = append(buf, '\n')
+		buf = append(buf, value...)
 	}
 	return buf
 }
 
+// formatValue formats the values of the default case. Addresses are written like the listeners they come from,
+// unset addresses as an empty string instead of <nil> or "invalid IP".
+func formatValue(v slog.Value) string {
+	if v.Kind() != slog.KindAny {
+		return v.String()
+	}
+
+	switch value := v.Any().(type) {
+	case net.IP:
+		if value == nil {
+			return `""`
+		}
+		return value.String()
+	case netip.Addr:
+		if !value.IsValid() {
+			return `""`
+		}
+		return value.String()
+	default:
+		return v.String()
+	}
+}
+
+// appendKey starts an attribute, on the line of the message at the top level, or on a new, indented line inside a group.
+func appendKey(buf []byte, key string, indentLevel int) []byte {
+	if indentLevel == 0 {
+		buf = append(buf, ' ')
+	} else {
+		buf = fmt.Appendf(buf, "\n%*s", indentLevel*4, "")
+	}
+
+	return append(buf, key...)
+}
+
 // formatLoggerOutput formats the logger output with timestamp, level, and message.
 // It returns the updated byte slice buffer with formatted logger output.
 // We are formatting logger output this way as we want to make sure our custom logger can print logs with /t and /n formatting.
@@ -184,6 +250,17 @@ func formatLoggerOutput(buf []byte, lev, msg string, colCode int) []byte {
 	return buf
 }
 
+// formatPlainLoggerOutput formats the logger output like formatLoggerOutput, without colors.
+func formatPlainLoggerOutput(buf []byte, lev, msg string) []byte {
+	buf = append(buf, "time="...)
+	buf = time.Now().AppendFormat(buf, time.RFC3339Nano)
+	buf = append(buf, " level="...)
+	buf = append(buf, lev...)
+	buf = append(buf, " msg="...)
+	buf = append(buf, msg...)
+	return buf
+}
+
 // Painter is a function that takes in a Bash color code and a string, and returns a string with the given string painted in the specified color.
 func painter(colorCode int, msg string) string {
 	//formatting message with ANSI escape sequence and selected color
@@ -199,6 +276,8 @@ func colorLogLevel(level string) (string, int) {
 		return painter(red, err), red
 	} else if level == warn {
 		return painter(yellow, warn), yellow
+	} else if level == trace {
+		return painter(cyan, trace), cyan
 	} else {
 		return painter(white, info), gray
 	}
@@ -207,7 +286,8 @@ func colorLogLevel(level string) (string, int) {
 
 func (ch *CustomHandler) appendUnopenedGroups(buf []byte, indentLevel int) []byte {
 	for _, g := range ch.unopenedGroups {
-		buf = fmt.Appendf(buf, "%*s%s:\n", indentLevel*4, "", g)
+		buf = appendKey(buf, g, indentLevel)
+		buf = append(buf, ':')
 		indentLevel++
 	}
 	return buf
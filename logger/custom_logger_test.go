@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
+	"net/netip"
 	"opentalaria/utils"
 	"reflect"
 	"runtime"
@@ -59,6 +61,7 @@ func Test_colorLogLevel(t *testing.T) {
 		{name: "Happy flow retriving DEBUG", args: args{level: debug}, want: "[97mDEBUG[0m", want1: 37},
 		{name: "Happy flow retriving INFO", args: args{level: err}, want: "[91mERROR[0m", want1: 91},
 		{name: "Happy flow retriving INFO", args: args{level: warn}, want: "[33mWARN[0m", want1: 33},
+		{name: "Happy flow retriving TRACE", args: args{level: "TRACE"}, want: "[36mTRACE[0m", want1: 36},
 		{name: "Happy flow retriving default case", args: args{level: "default"}, want: "[97mINFO[0m", want1: 37},
 	}
 	for _, tt := range tests {
@@ -260,3 +263,60 @@ func hasKey(key string) check {
 		return ""
 	}
 }
+
+func TestCustomHandler_GroupLayout(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(NewCustomHandler(&buf, nil)).With("broker", 1)
+
+	log.Info("effective configuration", "id", 1, slog.Group("listeners", slog.Group("plaintext", "host", "localhost", "port", 9092)), "format", "text")
+
+	// groups start on their own lines, and attributes following a group don't continue its last line
+	want := ` broker: 1 id: 1 listeners:
+    plaintext:
+        host: "localhost"
+        port: 9092
+format: "text"
+`
+	if got := buf.String(); !strings.HasSuffix(got, want) {
+		t.Errorf("got\n%s\nwant suffix\n%s", got, want)
+	}
+}
+
+func TestCustomHandler_DurationAndAddressAttrs(t *testing.T) {
+	tests := []struct {
+		name string
+		attr slog.Attr
+		want string
+	}{
+		{name: "duration", attr: slog.Duration("timeout", 750*time.Millisecond), want: " timeout: 750ms\n"},
+		{name: "duration passed as any", attr: slog.Any("interval", 15*time.Second), want: " interval: 15s\n"},
+		{name: "net.IP", attr: slog.Any("ip", net.ParseIP("192.168.0.1")), want: " ip: 192.168.0.1\n"},
+		{name: "IPv6 net.IP", attr: slog.Any("ip", net.ParseIP("::1")), want: " ip: ::1\n"},
+		{name: "nil net.IP", attr: slog.Any("ip", net.IP(nil)), want: ` ip: ""` + "\n"},
+		{name: "netip.Addr", attr: slog.Any("addr", netip.MustParseAddr("10.0.0.1")), want: " addr: 10.0.0.1\n"},
+		{name: "zero netip.Addr", attr: slog.Any("addr", netip.Addr{}), want: ` addr: ""` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			slog.New(NewCustomHandler(&buf, nil)).LogAttrs(context.Background(), slog.LevelInfo, "message", tt.attr)
+
+			if got := buf.String(); !strings.HasSuffix(got, tt.want) {
+				t.Errorf("got %q, want suffix %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCustomHandler_NoColor(t *testing.T) {
+	var buf bytes.Buffer
+	slog.New(NewCustomHandler(&buf, &Options{NoColor: true})).Warn("plain message", "key", "value")
+
+	got := buf.String()
+	if strings.Contains(got, "\033[") {
+		t.Errorf("got %q, want no ANSI escape sequences", got)
+	}
+	if want := ` level=WARN msg=plain message key: "value"` + "\n"; !strings.HasSuffix(got, want) {
+		t.Errorf("got %q, want suffix %q", got, want)
+	}
+}
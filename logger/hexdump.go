@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+)
+
+// DefaultHexDumpMaxBytes is the number of bytes HexDump prints before truncating the buffer.
+const DefaultHexDumpMaxBytes = 4096
+
+type HexDumpOptions struct {
+	// MaxBytes is the number of bytes to print, the rest of the buffer is summarized with a "... N more bytes" line.
+	// If zero or negative, the whole buffer is printed.
+	MaxBytes int
+}
+
+// HexDump returns a log value that renders b as a classic offset/hex/ascii hexdump, truncated after DefaultHexDumpMaxBytes.
+// The dump is only formatted if the record is logged, so it can be used on hot paths at trace level:
+//
+//	slog.Log(ctx, logger.LevelTrace, "decode failed", slog.Any("bytes", logger.HexDump(buf)))
+func HexDump(b []byte) slog.Value {
+	return HexDumpWithOptions(b, HexDumpOptions{MaxBytes: DefaultHexDumpMaxBytes})
+}
+
+// HexDumpWithOptions is like HexDump, but with the truncation set by opts.
+func HexDumpWithOptions(b []byte, opts HexDumpOptions) slog.Value {
+	return slog.AnyValue(hexDump{b: b, maxBytes: opts.MaxBytes})
+}
+
+type hexDump struct {
+	b        []byte
+	maxBytes int
+}
+
+// String formats the dump. The CustomHandler prints multi-line values indented below their key.
+func (h hexDump) String() string {
+	b := h.b
+	if h.maxBytes > 0 && len(b) > h.maxBytes {
+		b = b[:h.maxBytes]
+	}
+
+	dump := hex.Dump(b)
+	if truncated := len(h.b) - len(b); truncated > 0 {
+		dump += fmt.Sprintf("... %d more bytes\n", truncated)
+	}
+
+	return dump
+}
+
+// MarshalText makes the json handler render the dump as a string, instead of an empty object.
+func (h hexDump) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
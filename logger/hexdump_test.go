@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestHexDump(t *testing.T) {
+	got := HexDump([]byte("opentalaria\x00\x01")).Any().(hexDump).String()
+
+	want := "00000000  6f 70 65 6e 74 61 6c 61  72 69 61 00 01           |opentalaria..|\n"
+	if got != want {
+		t.Errorf("HexDump() = %q, want %q", got, want)
+	}
+}
+
+func TestHexDump_Truncate(t *testing.T) {
+	buf := bytes.Repeat([]byte{0xab}, 100)
+
+	tests := []struct {
+		name      string
+		maxBytes  int
+		wantLines int
+		wantMore  string
+	}{
+		{name: "truncated", maxBytes: 32, wantLines: 3, wantMore: "... 68 more bytes"},
+		{name: "no limit", maxBytes: 0, wantLines: 7},
+		{name: "limit above size", maxBytes: 1000, wantLines: 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := HexDumpWithOptions(buf, HexDumpOptions{MaxBytes: tt.maxBytes}).Any().(hexDump).String()
+
+			lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+			if len(lines) != tt.wantLines {
+				t.Errorf("got %d lines, want %d:\n%s", len(lines), tt.wantLines, got)
+			}
+			if tt.wantMore != "" && lines[len(lines)-1] != tt.wantMore {
+				t.Errorf("last line = %q, want %q", lines[len(lines)-1], tt.wantMore)
+			}
+			if tt.wantMore == "" && strings.Contains(got, "more bytes") {
+				t.Errorf("dump should not be truncated:\n%s", got)
+			}
+		})
+	}
+}
+
+func TestHexDump_CustomHandlerIndent(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(NewCustomHandler(&buf, &Options{Level: LevelTrace}))
+
+	log.Log(context.Background(), LevelTrace, "decode failed", slog.Group("request", slog.Any("bytes", HexDump(bytes.Repeat([]byte{0x01}, 20)))))
+
+	// the group indents the key by one level, the dump lines are indented one level below the key
+	got := buf.String()
+	if !strings.Contains(got, "    bytes:\n        00000000  01 01") {
+		t.Errorf("hex dump is not indented below its key:\n%s", got)
+	}
+	if !strings.Contains(got, "\n        00000010  01 01 01 01") {
+		t.Errorf("every line of the hex dump should be indented:\n%s", got)
+	}
+}
+
+func TestHexDump_JSONHandler(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	log.Info("decode failed", slog.Any("bytes", HexDump([]byte("kafka"))))
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("error unmarshalling log record: %v", err)
+	}
+	if dump, _ := record["bytes"].(string); !strings.Contains(dump, "|kafka|") {
+		t.Errorf("bytes = %v, want the hex dump as a string", record["bytes"])
+	}
+}
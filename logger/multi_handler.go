@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// A MultiHandler fans the records out to several handlers, like colored text on the console and json in a log file.
+// Each handler keeps its own format and level.
+type MultiHandler struct {
+	handlers []slog.Handler
+}
+
+// NewMultiHandler returns a MultiHandler which passes the records to all handlers.
+func NewMultiHandler(handlers ...slog.Handler) *MultiHandler {
+	return &MultiHandler{handlers: handlers}
+}
+
+// Enabled implements Handler.Enabled by reporting whether any of the handlers is enabled for the level.
+func (h *MultiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Handle implements Handler.Handle by passing a copy of the record to the handlers enabled for its level.
+// A failing handler doesn't keep the record from the others, their errors are joined.
+func (h *MultiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, handler := range h.handlers {
+		if !handler.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := handler.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// WithAttrs implements Handler.WithAttrs.
+func (h *MultiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithAttrs(attrs)
+	}
+
+	return &MultiHandler{handlers: handlers}
+}
+
+// WithGroup implements Handler.WithGroup.
+func (h *MultiHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithGroup(name)
+	}
+
+	return &MultiHandler{handlers: handlers}
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMultiHandler_TextAndJSON(t *testing.T) {
+	var text, jsonBuf bytes.Buffer
+	handler := NewMultiHandler(
+		NewCustomHandler(&text, nil),
+		slog.NewJSONHandler(&jsonBuf, &slog.HandlerOptions{ReplaceAttr: ReplaceLevelAttr}),
+	)
+	log := slog.New(handler).With("broker", 1).WithGroup("request")
+
+	log.Info("handled", "api", "Metadata")
+
+	if want := ` broker: 1 request:
+    api: "Metadata"
+`; !strings.HasSuffix(text.String(), want) {
+		t.Errorf("text got %q, want suffix %q", text.String(), want)
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(jsonBuf.Bytes(), &record); err != nil {
+		t.Fatalf("json sink got %q: %v", jsonBuf.String(), err)
+	}
+	if record["msg"] != "handled" || record["broker"] != float64(1) {
+		t.Errorf("json record = %v, want the message with the broker attribute", record)
+	}
+	if request, _ := record["request"].(map[string]any); request["api"] != "Metadata" {
+		t.Errorf("json record = %v, want the api attribute in the request group", record)
+	}
+}
+
+func TestMultiHandler_Levels(t *testing.T) {
+	var debugBuf, warnBuf bytes.Buffer
+	handler := NewMultiHandler(
+		NewCustomHandler(&debugBuf, &Options{Level: slog.LevelDebug}),
+		NewCustomHandler(&warnBuf, &Options{Level: slog.LevelWarn}),
+	)
+	log := slog.New(handler)
+
+	if handler.Enabled(context.Background(), LevelTrace) {
+		t.Error("enabled for trace, which none of the handlers logs")
+	}
+
+	log.Debug("details")
+	log.Warn("problem")
+
+	if got := debugBuf.String(); !strings.Contains(got, "details") || !strings.Contains(got, "problem") {
+		t.Errorf("debug handler got %q, want both records", got)
+	}
+	if got := warnBuf.String(); strings.Contains(got, "details") || !strings.Contains(got, "problem") {
+		t.Errorf("warn handler got %q, want only the warning", got)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestMultiHandler_FailingHandler(t *testing.T) {
+	var buf bytes.Buffer
+	handler := NewMultiHandler(NewCustomHandler(failingWriter{}, nil), NewCustomHandler(&buf, nil))
+
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "still logged", 0)
+	if err := handler.Handle(context.Background(), r); err == nil {
+		t.Error("the error of the failing handler was not returned")
+	}
+	if !strings.Contains(buf.String(), "still logged") {
+		t.Errorf("got %q, want the record in the other handler", buf.String())
+	}
+}
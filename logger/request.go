@@ -0,0 +1,22 @@
+package logger
+
+import (
+	"log/slog"
+	"opentalaria/protocol"
+)
+
+// Request groups the fields of a request header identifying the request in logs: the API name and version,
+// the correlation ID and the client ID.
+func Request(header *protocol.RequestHeader) slog.Attr {
+	clientID := ""
+	if header.ClientID != nil {
+		clientID = *header.ClientID
+	}
+
+	return slog.Group("request",
+		slog.String("api", protocol.ApiKeyName(header.RequestApiKey)),
+		slog.Int("apiVersion", int(header.RequestApiVersion)),
+		slog.Int("correlationID", int(header.CorrelationID)),
+		slog.String("clientID", clientID),
+	)
+}
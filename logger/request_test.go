@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"opentalaria/protocol"
+	"testing"
+)
+
+func TestRequest(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	clientID := "test-client"
+	log.Info("handling request", Request(&protocol.RequestHeader{RequestApiKey: 3, RequestApiVersion: 12, CorrelationID: 7, ClientID: &clientID}))
+
+	var record struct {
+		Request struct {
+			Api           string
+			ApiVersion    int
+			CorrelationID int
+			ClientID      string
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("error decoding log record %s: %v", buf.String(), err)
+	}
+
+	got := record.Request
+	if got.Api != "Metadata" || got.ApiVersion != 12 || got.CorrelationID != 7 || got.ClientID != "test-client" {
+		t.Errorf("request = %+v, want Metadata v12 with correlation ID 7 from test-client", got)
+	}
+}
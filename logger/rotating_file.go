@@ -0,0 +1,169 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the timestamp appended to the name of rotated files. It sorts in the order the files were rotated.
+const backupTimeFormat = "2006-01-02T15-04-05.000000000"
+
+// RotateOptions are the limits of a RotatingFile.
+type RotateOptions struct {
+	// MaxBytes is the size above which the file is rotated, 0 never rotates it
+	MaxBytes int64
+	// MaxBackups is the number of rotated files which are kept, 0 keeps all of them
+	MaxBackups int
+	// MaxAge is how long rotated files are kept, 0 keeps them regardless of their age
+	MaxAge time.Duration
+}
+
+// RotatingFile is an io.WriteCloser which appends to a log file and rotates it once it grows past MaxBytes.
+// The rotated file is renamed to the name of the file followed by the time of the rotation, like broker.log.2024-11-01T15-04-05.000000000,
+// and the backups beyond MaxBackups or older than MaxAge are removed.
+type RotatingFile struct {
+	path string
+	opts RotateOptions
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+	// now returns the time of the rotations, it is replaced by the tests
+	now func() time.Time
+}
+
+// NewRotatingFile opens the log file for appending, creating it and its directory if they don't exist.
+func NewRotatingFile(path string, opts RotateOptions) (*RotatingFile, error) {
+	f := &RotatingFile{path: path, opts: opts, now: time.Now}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("error creating the directory of log file %s: %w", path, err)
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Write appends p to the file, rotating it first if p would grow it past MaxBytes.
+// A write is never split across files, so a record larger than MaxBytes ends up alone in its file.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if f.opts.MaxBytes > 0 && f.size > 0 && f.size+int64(len(p)) > f.opts.MaxBytes {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close closes the file, later writes fail.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening log file %s: %w", f.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("error reading the size of log file %s: %w", f.path, err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// rotate renames the current file to a backup, opens a new file and removes the backups beyond the limits.
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("error closing log file %s: %w", f.path, err)
+	}
+	f.file = nil
+
+	backup := f.path + "." + f.now().UTC().Format(backupTimeFormat)
+	if err := os.Rename(f.path, backup); err != nil {
+		return fmt.Errorf("error rotating log file %s: %w", f.path, err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	return f.removeOldBackups()
+}
+
+// removeOldBackups removes the backups beyond MaxBackups, the oldest first, and the backups older than MaxAge.
+func (f *RotatingFile) removeOldBackups() error {
+	backups, err := f.backups()
+	if err != nil {
+		return err
+	}
+
+	var remove []string
+	if f.opts.MaxBackups > 0 && len(backups) > f.opts.MaxBackups {
+		remove = backups[:len(backups)-f.opts.MaxBackups]
+		backups = backups[len(backups)-f.opts.MaxBackups:]
+	}
+	if f.opts.MaxAge > 0 {
+		cutoff := f.now().Add(-f.opts.MaxAge)
+		for _, backup := range backups {
+			rotatedAt, err := time.Parse(backupTimeFormat, strings.TrimPrefix(filepath.Base(backup), filepath.Base(f.path)+"."))
+			if err == nil && rotatedAt.Before(cutoff) {
+				remove = append(remove, backup)
+			}
+		}
+	}
+
+	for _, backup := range remove {
+		if err := os.Remove(backup); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error removing rotated log file %s: %w", backup, err)
+		}
+	}
+
+	return nil
+}
+
+// backups returns the rotated files, the oldest first.
+func (f *RotatingFile) backups() ([]string, error) {
+	matches, err := filepath.Glob(f.path + ".*")
+	if err != nil {
+		return nil, fmt.Errorf("error listing rotated log files of %s: %w", f.path, err)
+	}
+
+	prefix := filepath.Base(f.path) + "."
+	backups := matches[:0]
+	for _, match := range matches {
+		if _, err := time.Parse(backupTimeFormat, strings.TrimPrefix(filepath.Base(match), prefix)); err == nil {
+			backups = append(backups, match)
+		}
+	}
+	slices.Sort(backups)
+
+	return backups, nil
+}
@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRotatingFile_RotatesPastMaxBytes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "broker.log")
+	f, err := NewRotatingFile(path, RotateOptions{MaxBytes: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	for _, line := range []string{"first\n", "second\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backups, err := f.backups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("backups = %v, want a single rotated file", backups)
+	}
+	assertFileContent(t, backups[0], "first\n")
+	assertFileContent(t, path, "second\n")
+}
+
+func TestRotatingFile_AppendsToExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broker.log")
+	if err := os.WriteFile(path, []byte("before restart\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := NewRotatingFile(path, RotateOptions{MaxBytes: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// the size of the existing content counts towards MaxBytes
+	if _, err := f.Write([]byte("after restart\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	assertFileContent(t, path, "after restart\n")
+}
+
+func TestRotatingFile_RemovesOldBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broker.log")
+	f, err := NewRotatingFile(path, RotateOptions{MaxBytes: 1, MaxBackups: 2, MaxAge: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	now := time.Date(2024, 11, 1, 15, 4, 5, 0, time.UTC)
+	f.now = func() time.Time { return now }
+
+	// a file of another log, which is never removed
+	other := path + ".other"
+	if err := os.WriteFile(other, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 4; i++ {
+		if _, err := f.Write([]byte{byte('a' + i)}); err != nil {
+			t.Fatal(err)
+		}
+		now = now.Add(time.Minute)
+	}
+
+	backups, err := f.backups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("backups = %v, want the 2 most recent", backups)
+	}
+	assertFileContent(t, backups[0], "b")
+	assertFileContent(t, backups[1], "c")
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("the file of another log was removed: %v", err)
+	}
+
+	// the backups older than MaxAge are removed on the next rotation
+	now = now.Add(2 * time.Hour)
+	if _, err := f.Write([]byte("e")); err != nil {
+		t.Fatal(err)
+	}
+	if backups, _ = f.backups(); len(backups) != 1 {
+		t.Fatalf("backups = %v, want only the file rotated now", backups)
+	}
+	assertFileContent(t, backups[0], "d")
+}
+
+func TestRotatingFile_WriteAfterClose(t *testing.T) {
+	f, err := NewRotatingFile(filepath.Join(t.TempDir(), "broker.log"), RotateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.Write([]byte("late")); err == nil {
+		t.Error("write after close succeeded")
+	}
+}
+
+func assertFileContent(t *testing.T, path, want string) {
+	t.Helper()
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("content of %s = %q, want %q", filepath.Base(path), got, want)
+	}
+}
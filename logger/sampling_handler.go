@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// SamplingOptions are the limits of a SamplingHandler.
+type SamplingOptions struct {
+	// MaxMessages is the number of records with the same message and level which are logged per Interval
+	MaxMessages int
+	Interval    time.Duration
+}
+
+// A SamplingHandler protects the logs from storms, like a client sending the same malformed request in a loop.
+// Records with the same message and level beyond MaxMessages per Interval are dropped, and once the interval is over,
+// a summary with the number of dropped records is logged with the next record.
+type SamplingHandler struct {
+	handler slog.Handler
+	sampler *sampler
+}
+
+// sampler counts the records of the handler and the handlers derived from it with WithAttrs and WithGroup.
+type sampler struct {
+	opts SamplingOptions
+	// now returns the time of the records, it is replaced by the tests
+	now func() time.Time
+
+	mu      sync.Mutex
+	windows map[sampleKey]*sampleWindow
+}
+
+type sampleKey struct {
+	level slog.Level
+	msg   string
+}
+
+// sampleWindow counts the records of a message in the current interval.
+type sampleWindow struct {
+	start time.Time
+	count int
+	// handler is the handler of the last dropped record, which logs the summary
+	handler slog.Handler
+}
+
+// NewSamplingHandler returns a SamplingHandler which passes the records within the limits to h.
+func NewSamplingHandler(h slog.Handler, opts SamplingOptions) *SamplingHandler {
+	return &SamplingHandler{
+		handler: h,
+		sampler: &sampler{opts: opts, now: time.Now, windows: map[sampleKey]*sampleWindow{}},
+	}
+}
+
+// Enabled implements Handler.Enabled.
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements Handler.Handle. It logs the summaries of the intervals which are over before the record,
+// and drops the record if its message was already logged MaxMessages times in the current interval.
+func (h *SamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	now := h.sampler.now()
+	key := sampleKey{level: r.Level, msg: r.Message}
+
+	h.sampler.mu.Lock()
+	summaries := h.sampler.expire(now)
+	w, ok := h.sampler.windows[key]
+	if !ok {
+		w = &sampleWindow{start: now}
+		h.sampler.windows[key] = w
+	}
+	w.count++
+	drop := w.count > h.sampler.opts.MaxMessages
+	if drop {
+		w.handler = h.handler
+	}
+	h.sampler.mu.Unlock()
+
+	var errs []error
+	for _, s := range summaries {
+		if err := s.handler.Handle(ctx, s.record); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if !drop {
+		if err := h.handler.Handle(ctx, r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// WithAttrs implements Handler.WithAttrs. The derived handler shares the counts of h.
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{handler: h.handler.WithAttrs(attrs), sampler: h.sampler}
+}
+
+// WithGroup implements Handler.WithGroup. The derived handler shares the counts of h.
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{handler: h.handler.WithGroup(name), sampler: h.sampler}
+}
+
+// sampleSummary is the record which reports the dropped records of an interval.
+type sampleSummary struct {
+	start   time.Time
+	handler slog.Handler
+	record  slog.Record
+}
+
+// expire removes the windows whose interval is over, and returns the summaries of those which dropped records,
+// in the order their intervals started.
+func (s *sampler) expire(now time.Time) []sampleSummary {
+	var summaries []sampleSummary
+	for key, w := range s.windows {
+		if now.Sub(w.start) < s.opts.Interval {
+			continue
+		}
+		delete(s.windows, key)
+
+		if dropped := w.count - s.opts.MaxMessages; dropped > 0 {
+			msg := fmt.Sprintf("%s (repeated %d more times)", key.msg, dropped)
+			record := slog.NewRecord(now, key.level, msg, 0)
+			record.AddAttrs(slog.Int("dropped", dropped), slog.Duration("interval", s.opts.Interval))
+			summaries = append(summaries, sampleSummary{start: w.start, handler: w.handler, record: record})
+		}
+	}
+	slices.SortFunc(summaries, func(a, b sampleSummary) int { return a.start.Compare(b.start) })
+
+	return summaries
+}
@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSamplingHandler_Storm(t *testing.T) {
+	var buf bytes.Buffer
+	handler := NewSamplingHandler(NewCustomHandler(&buf, nil), SamplingOptions{MaxMessages: 10, Interval: time.Second})
+	now := time.Date(2024, 11, 1, 15, 4, 5, 0, time.UTC)
+	handler.sampler.now = func() time.Time { return now }
+	log := slog.New(handler).With("client", "10.0.0.1:51234")
+
+	for i := 0; i < 1000; i++ {
+		log.Warn("error decoding request")
+	}
+	log.Info("unrelated")
+
+	if lines := strings.Count(buf.String(), "\n"); lines != 11 {
+		t.Fatalf("got %d lines, want the first 10 warnings and the unrelated record:\n%s", lines, buf.String())
+	}
+
+	// the summary is logged with the first record after the interval, with the attributes of the dropped records
+	buf.Reset()
+	now = now.Add(time.Second)
+	log.Warn("error decoding request")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want the summary and the warning:\n%s", len(lines), buf.String())
+	}
+	if want := `error decoding request (repeated 990 more times)`; !strings.Contains(lines[0], want) {
+		t.Errorf("summary = %q, want it to contain %q", lines[0], want)
+	}
+	if want := ` client: "10.0.0.1:51234" dropped: 990 interval: 1s`; !strings.HasSuffix(lines[0], want) {
+		t.Errorf("summary = %q, want suffix %q", lines[0], want)
+	}
+	if !strings.HasSuffix(lines[1], `msg=`+painter(yellow, "error decoding request")+` client: "10.0.0.1:51234"`) {
+		t.Errorf("got %q, want the warning of the new interval", lines[1])
+	}
+}
+
+func TestSamplingHandler_MessagesAndLevels(t *testing.T) {
+	var buf bytes.Buffer
+	handler := NewSamplingHandler(NewCustomHandler(&buf, nil), SamplingOptions{MaxMessages: 1, Interval: time.Minute})
+	log := slog.New(handler)
+
+	// records are counted by message and level, and the counts are shared with the derived loggers
+	log.Warn("first")
+	log.Error("first")
+	log.Warn("second")
+	log.WithGroup("request").Warn("first")
+
+	if lines := strings.Count(buf.String(), "\n"); lines != 3 {
+		t.Errorf("got %d lines, want 3:\n%s", lines, buf.String())
+	}
+}
+
+func TestSamplingHandler_Enabled(t *testing.T) {
+	handler := NewSamplingHandler(NewCustomHandler(&bytes.Buffer{}, &Options{Level: slog.LevelWarn}), SamplingOptions{MaxMessages: 1, Interval: time.Second})
+
+	if handler.Enabled(context.Background(), slog.LevelInfo) || !handler.Enabled(context.Background(), slog.LevelWarn) {
+		t.Error("Enabled doesn't follow the level of the wrapped handler")
+	}
+}
@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strconv"
+)
+
+// LevelTrace is a verbosity level below slog.LevelDebug, used for wire-protocol dumps
+// that are too noisy to be enabled by default.
+const LevelTrace = slog.Level(-8)
+
+const redacted = "[REDACTED]"
+
+// LevelName returns the name of the level, like slog.Level.String, but with LevelTrace named "TRACE" instead of "DEBUG-4".
+func LevelName(level slog.Level) string {
+	if level == LevelTrace {
+		return trace
+	}
+
+	return level.String()
+}
+
+// ReplaceLevelAttr can be used as ReplaceAttr in slog.HandlerOptions, so the builtin handlers name LevelTrace "TRACE" as well.
+func ReplaceLevelAttr(groups []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.LevelKey && len(groups) == 0 {
+		if level, ok := a.Value.Any().(slog.Level); ok {
+			a.Value = slog.StringValue(LevelName(level))
+		}
+	}
+
+	return a
+}
+
+// Fields returns a slog.LogValuer that renders the exported fields of v as nested slog groups.
+// Fields tagged with `sensitive:"true"` are replaced with a redaction marker, so the value can be used
+// to log decoded requests without leaking credentials. The reflection is done lazily, only if the record is logged.
+func Fields(v any) slog.LogValuer {
+	return fieldsValuer{v: v}
+}
+
+type fieldsValuer struct {
+	v any
+}
+
+func (f fieldsValuer) LogValue() slog.Value {
+	return reflectValue(reflect.ValueOf(f.v))
+}
+
+func reflectValue(rv reflect.Value) slog.Value {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return slog.StringValue("<nil>")
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		// types implementing fmt.Stringer, like uuid.UUID or time.Time, are better represented by their string form.
+		if s, ok := rv.Interface().(fmt.Stringer); ok {
+			return slog.StringValue(s.String())
+		}
+
+		attrs := make([]slog.Attr, 0, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			field := rv.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			if field.Tag.Get("sensitive") == "true" {
+				attrs = append(attrs, slog.String(field.Name, redacted))
+				continue
+			}
+
+			attrs = append(attrs, slog.Attr{Key: field.Name, Value: reflectValue(rv.Field(i))})
+		}
+		return slog.GroupValue(attrs...)
+	case reflect.Slice, reflect.Array:
+		// byte slices are printed as a whole, there is no point in listing every byte as a separate attribute.
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return slog.AnyValue(rv.Interface())
+		}
+
+		attrs := make([]slog.Attr, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			attrs = append(attrs, slog.Attr{Key: strconv.Itoa(i), Value: reflectValue(rv.Index(i))})
+		}
+		return slog.GroupValue(attrs...)
+	case reflect.Invalid:
+		return slog.StringValue("<nil>")
+	default:
+		return slog.AnyValue(rv.Interface())
+	}
+}
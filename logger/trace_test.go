@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"opentalaria/protocol"
+	"strings"
+	"testing"
+)
+
+func TestFields_RedactsSensitiveFields(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(NewCustomHandler(&buf, &Options{Level: LevelTrace}))
+
+	req := &protocol.SaslAuthenticateRequest{
+		Version:   2,
+		AuthBytes: []byte("\x00alice\x00secret-password"),
+	}
+	log.Log(context.Background(), LevelTrace, "decoded request", "request", Fields(req))
+
+	got := buf.String()
+	if strings.Contains(got, "secret-password") {
+		t.Errorf("trace output should not contain the SASL auth bytes, got %s", got)
+	}
+	if !strings.Contains(got, redacted) {
+		t.Errorf("trace output should contain %s, got %s", redacted, got)
+	}
+	if !strings.Contains(got, "Version") {
+		t.Errorf("trace output should contain the non-sensitive fields, got %s", got)
+	}
+}
+
+func TestFields_SkipsUnexportedFields(t *testing.T) {
+	type nested struct {
+		Name string
+	}
+	type request struct {
+		Topics   []nested
+		internal string
+	}
+
+	var buf bytes.Buffer
+	log := slog.New(NewCustomHandler(&buf, &Options{Level: LevelTrace}))
+	log.Log(context.Background(), LevelTrace, "decoded request", "request", Fields(request{
+		Topics:   []nested{{Name: "test-topic"}},
+		internal: "hidden",
+	}))
+
+	got := buf.String()
+	if strings.Contains(got, "hidden") {
+		t.Errorf("trace output should not contain unexported fields, got %s", got)
+	}
+	if !strings.Contains(got, "test-topic") {
+		t.Errorf("trace output should contain nested fields, got %s", got)
+	}
+}
+
+func TestLevelTrace_Verbosity(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    slog.Level
+		wantLogs bool
+	}{
+		{name: "suppressed at debug level", level: slog.LevelDebug, wantLogs: false},
+		{name: "emitted at trace level", level: LevelTrace, wantLogs: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := slog.New(NewLevelHandler(tt.level, NewCustomHandler(&buf, nil)))
+
+			log.Log(context.Background(), LevelTrace, "wire dump")
+
+			got := buf.String()
+			if gotLogs := strings.Contains(got, "wire dump"); gotLogs != tt.wantLogs {
+				t.Fatalf("trace record logged = %v, want %v, got %q", gotLogs, tt.wantLogs, got)
+			}
+			if tt.wantLogs && !strings.Contains(got, painter(cyan, trace)) {
+				t.Errorf("trace record should be labeled %s, got %q", trace, got)
+			}
+		})
+	}
+}
@@ -1,52 +1,121 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"opentalaria/config"
 	"opentalaria/logger"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	// We start a web server only in localdev mode, which should't expose any sensitive information.
 	// If we add some web APIs one day, this functionality has to be reviewed.
 	_ "expvar"
 )
 
-func initLogger(config *config.Config) {
+// initLogger opens the log file, if log.file is set, and sets the default logger. It returns the log file, which a reload
+// of the configuration keeps writing to.
+func initLogger(config *config.Config) (io.Writer, error) {
 	// print the log level before setting the log level handler so we can see what is set in case warn or error are set.
 	logLevel := config.LogLevel
-	slog.Info("Setting log level to " + logLevel.String())
+	slog.Info("Setting log level to " + logger.LevelName(logLevel.Level()))
 
 	// initialize logger with level handler based on LOG_LEVEL env variable.
 	// The default log level is Warn, if no env is set or the value is invalid.
+	// The handler reads the level from the config on every record, so changes made through AlterConfigs apply right away.
 	//
 	// JSON Handler might be better suited for a cloud environment. Set it with LOG_FORMAT=json env variable
-	var handler slog.Handler
-	if config.LogFormat == "json" {
-		handler = slog.NewJSONHandler(os.Stdout, nil)
-	} else {
-		handler = logger.NewCustomHandler(os.Stdout, nil)
+	//
+	// With LOG_FILE the logs are written to the file, without colors and in the format of LOG_FILE_FORMAT,
+	// and to stdout as well with LOG_CONSOLE_ENABLE=true.
+	//
+	// With LOG_SAMPLING_ENABLE=true, repeated messages beyond LOG_SAMPLING_MAX_MESSAGES per interval are dropped and summarized.
+	var file io.Writer
+	if config.LogFile != "" {
+		var err error
+		if file, err = logger.NewRotatingFile(config.LogFile, logger.RotateOptions(config.LogFileRotate)); err != nil {
+			return nil, err
+		}
+	}
+
+	setLogHandler(config, file)
+
+	return file, nil
+}
+
+// setLogHandler sets the default logger to write to stdout and the log file in the log formats of the config.
+func setLogHandler(config *config.Config, file io.Writer) {
+	handler := newLogHandler(os.Stdout, config.LogFormat, false)
+	if file != nil {
+		fileHandler := newLogHandler(file, config.LogFileFormat, true)
+		if config.LogConsole {
+			handler = logger.NewMultiHandler(handler, fileHandler)
+		} else {
+			handler = fileHandler
+		}
+	}
+
+	if config.LogSampling.Enabled {
+		handler = logger.NewSamplingHandler(handler, logger.SamplingOptions{MaxMessages: config.LogSampling.MaxMessages, Interval: config.LogSampling.Interval})
+	}
+
+	slog.SetDefault(slog.New(logger.NewLevelHandler(config.LogLevel, handler)))
+}
+
+// reloadConfig reads the config file and the environment again, and applies the settings which can change at runtime.
+// The loggers created before a change of the log format, like those of open connections, keep the previous format.
+func reloadConfig(conf *config.Config, opts []config.Option, confFile string, logFile io.Writer) {
+	next, err := config.NewConfigWithOptions(confFile, opts...)
+	if err != nil {
+		slog.Error("error reloading the configuration, keeping the current one", "err", err)
+		return
 	}
 
-	logger := slog.New(logger.NewLevelHandler(logLevel, handler))
+	formatChanged := next.LogFormat != conf.LogFormat || next.LogFileFormat != conf.LogFileFormat
+	for _, key := range conf.Reload(next) {
+		slog.Warn("configuration change requires restart, ignored", "key", key)
+	}
+	if formatChanged {
+		setLogHandler(conf, logFile)
+	}
 
-	slog.SetDefault(logger)
+	slog.Info("configuration reloaded", "log.level", logger.LevelName(conf.LogLevel.Level()), "log.format", conf.LogFormat)
+}
+
+// newLogHandler returns the handler of the log format, json or the colored text of the custom handler.
+func newLogHandler(out io.Writer, format string, noColor bool) slog.Handler {
+	if format == "json" {
+		return slog.NewJSONHandler(out, &slog.HandlerOptions{ReplaceAttr: logger.ReplaceLevelAttr})
+	}
+
+	return logger.NewCustomHandler(out, &logger.Options{NoColor: noColor})
 }
 
 func main() {
 	confFile := flag.String("c", "config.yaml", "Path to config file. Default is config.yaml")
+	envPrefix := flag.String("env-prefix", config.DefaultEnvPrefix, "Prefix of the environment variables which override the config file. Default is OT")
 	flag.Parse()
 
 	// global config object that will be passed to all downstream APIs and methods
-	conf, err := config.NewConfig(*confFile)
+	opts := []config.Option{config.WithEnvPrefix(*envPrefix)}
+	conf, err := config.NewConfigWithOptions(*confFile, opts...)
 	if err != nil {
 		slog.Error("Error initializing broker", "err", err)
 		os.Exit(1)
 	}
 
-	initLogger(conf)
+	logFile, err := initLogger(conf)
+	if err != nil {
+		slog.Error("Error initializing logger", "err", err)
+		os.Exit(1)
+	}
+	slog.Info("effective configuration", conf.LogAttrs()...)
 
 	if conf.OTProfile == config.Localdev {
 		slog.Info(fmt.Sprintf("starting in local dev mode, listening on port :%d", conf.DebugServerPort))
@@ -54,6 +123,39 @@ func main() {
 		go http.ListenAndServe(fmt.Sprintf(":%d", conf.DebugServerPort), nil)
 	}
 
-	server := NewServer(conf)
+	server, err := NewServer(conf)
+	if err != nil {
+		slog.Error("error creating the server", "err", err)
+		os.Exit(1)
+	}
+
+	// on SIGTERM, stop accepting connections and give the requests being processed shutdown.timeout.ms to complete
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		slog.Info("shutting down")
+
+		timeout := time.Duration(conf.Env.GetInt64("shutdown.timeout.ms")) * time.Millisecond
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		// requests still blocked after the timeout, like a JoinGroup waiting for a rebalance, don't hold up the exit
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			slog.Error("requests did not complete before shutdown.timeout.ms", "shutdown.timeout.ms", timeout.Milliseconds(), "err", err)
+			os.Exit(1)
+		}
+	}()
+
+	// on SIGHUP, re-read the config file and apply the log level and format, without a restart
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			reloadConfig(conf, opts, *confFile, logFile)
+		}
+	}()
+
+	// Run returns once the server has been shut down
 	server.Run()
 }
@@ -0,0 +1,150 @@
+package metadata
+
+import (
+	"sync"
+	"time"
+
+	"opentalaria/protocol"
+	"opentalaria/utils"
+)
+
+// BrokerRegistry is the controller side of the broker lifecycle in KRaft. Brokers register, and are fenced until they
+// caught up with the metadata log and asked to be unfenced in a heartbeat. Brokers which miss their heartbeats for
+// the session timeout are fenced again. The registrations and the fencing are records of the metadata log.
+type BrokerRegistry struct {
+	log            *Log
+	sessionTimeout time.Duration
+
+	// mu orders the registrations and heartbeats, so the fencing records follow the state they are based on
+	mu sync.Mutex
+	// lastHeartbeats are the times of the last heartbeats of the registered brokers, by broker ID.
+	// They are kept in memory only, like in Kafka, a new controller gives every broker a full session.
+	lastHeartbeats map[int32]time.Time
+}
+
+// HeartbeatResult is the state of a broker after its heartbeat.
+type HeartbeatResult struct {
+	// CaughtUp is set once the broker replicated the metadata log up to its registration
+	CaughtUp bool
+	Fenced   bool
+}
+
+// NewBrokerRegistry returns the registry of the brokers in the metadata log, which fences brokers that missed
+// their heartbeats for the session timeout.
+func NewBrokerRegistry(log *Log, sessionTimeout time.Duration) *BrokerRegistry {
+	return &BrokerRegistry{
+		log:            log,
+		sessionTimeout: sessionTimeout,
+		lastHeartbeats: map[int32]time.Time{},
+	}
+}
+
+// SessionTimeout returns the time after its last heartbeat at which a broker is fenced.
+func (r *BrokerRegistry) SessionTimeout() time.Duration {
+	return r.sessionTimeout
+}
+
+// ReplicaBrokers returns the unfenced brokers, which replicas of new partitions can be assigned to.
+func (r *BrokerRegistry) ReplicaBrokers() []ReplicaBroker {
+	var brokers []ReplicaBroker
+	for _, b := range r.log.Brokers() {
+		if !b.Fenced {
+			brokers = append(brokers, ReplicaBroker{ID: b.BrokerID, Rack: b.Rack})
+		}
+	}
+
+	return brokers
+}
+
+// NextBrokerID returns the lowest broker ID above reservedMaxID which no broker registered with, for brokers without broker.id.
+func (r *BrokerRegistry) NextBrokerID(reservedMaxID int32) int32 {
+	id := reservedMaxID + 1
+	// the brokers are sorted by ID, so the first gap is the lowest free ID
+	for _, b := range r.log.Brokers() {
+		if b.BrokerID == id {
+			id++
+		}
+	}
+
+	return id
+}
+
+// Register registers the broker, fenced, and returns its broker epoch, which is the offset of the registration record.
+// A broker which registers again with the same incarnation ID keeps its epoch, a new incarnation is only accepted
+// once the session of the previous one expired.
+func (r *BrokerRegistry) Register(registration protocol.RegisterBrokerRecord, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.log.Broker(registration.BrokerID); ok {
+		if existing.IncarnationID == registration.IncarnationID {
+			return existing.BrokerEpoch, nil
+		}
+		if now.Sub(r.lastHeartbeats[registration.BrokerID]) < r.sessionTimeout {
+			return 0, utils.ErrDuplicateBrokerRegistration
+		}
+	}
+
+	registration.BrokerEpoch = r.log.LogEndOffset()
+	registration.Fenced = true
+	if _, err := r.log.Append(&registration); err != nil {
+		return 0, err
+	}
+	r.lastHeartbeats[registration.BrokerID] = now
+
+	return registration.BrokerEpoch, nil
+}
+
+// Heartbeat records a heartbeat of the broker, which has replicated the metadata log up to metadataOffset.
+// A fenced broker is unfenced once it caught up, unless it wants to stay fenced, an unfenced broker is fenced if it wants to.
+func (r *BrokerRegistry) Heartbeat(brokerID int32, brokerEpoch int64, metadataOffset int64, wantFence bool, now time.Time) (HeartbeatResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	broker, ok := r.log.Broker(brokerID)
+	if !ok {
+		return HeartbeatResult{}, utils.ErrBrokerIDNotRegistered
+	}
+	if broker.BrokerEpoch != brokerEpoch {
+		return HeartbeatResult{}, utils.ErrStaleBrokerEpoch
+	}
+
+	r.lastHeartbeats[brokerID] = now
+
+	result := HeartbeatResult{CaughtUp: metadataOffset >= brokerEpoch, Fenced: broker.Fenced}
+	switch {
+	case broker.Fenced && result.CaughtUp && !wantFence:
+		if _, err := r.log.Append(&protocol.UnfenceBrokerRecord{ID: brokerID, Epoch: brokerEpoch}); err != nil {
+			return HeartbeatResult{}, err
+		}
+		result.Fenced = false
+	case !broker.Fenced && wantFence:
+		if _, err := r.log.Append(&protocol.FenceBrokerRecord{ID: brokerID, Epoch: brokerEpoch}); err != nil {
+			return HeartbeatResult{}, err
+		}
+		result.Fenced = true
+	}
+
+	return result, nil
+}
+
+// FenceExpired fences the unfenced brokers whose last heartbeat is older than the session timeout,
+// and returns their IDs.
+func (r *BrokerRegistry) FenceExpired(now time.Time) ([]int32, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var fenced []int32
+	for _, broker := range r.log.Brokers() {
+		if broker.Fenced || now.Sub(r.lastHeartbeats[broker.BrokerID]) < r.sessionTimeout {
+			continue
+		}
+
+		if _, err := r.log.Append(&protocol.FenceBrokerRecord{ID: broker.BrokerID, Epoch: broker.BrokerEpoch}); err != nil {
+			return fenced, err
+		}
+		fenced = append(fenced, broker.BrokerID)
+	}
+
+	return fenced, nil
+}
@@ -0,0 +1,121 @@
+package metadata
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"opentalaria/protocol"
+	"opentalaria/utils"
+
+	"github.com/google/uuid"
+)
+
+func TestBrokerRegistry_MissedHeartbeatFences(t *testing.T) {
+	log := NewLog(NewStandaloneRaftState(1))
+	registry := NewBrokerRegistry(log, 9*time.Second)
+	start := time.Now()
+
+	epoch, err := registry.Register(protocol.RegisterBrokerRecord{BrokerID: 2, IncarnationID: uuid.New()}, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if broker, _ := log.Broker(2); !broker.Fenced {
+		t.Error("broker is not fenced after registering")
+	}
+
+	// the broker is unfenced once it caught up with its registration
+	result, err := registry.Heartbeat(2, epoch, epoch-1, false, start.Add(time.Second))
+	if err != nil || !result.Fenced || result.CaughtUp {
+		t.Errorf("heartbeat behind the registration = %+v, %v, want fenced", result, err)
+	}
+	result, err = registry.Heartbeat(2, epoch, epoch, false, start.Add(2*time.Second))
+	if err != nil || result.Fenced || !result.CaughtUp {
+		t.Errorf("heartbeat after catching up = %+v, %v, want unfenced", result, err)
+	}
+
+	// heartbeats within the session keep the broker unfenced
+	if fenced, err := registry.FenceExpired(start.Add(10 * time.Second)); err != nil || len(fenced) != 0 {
+		t.Errorf("fenced %v, %v within the session", fenced, err)
+	}
+
+	// after missing its heartbeats for the session timeout, the broker is fenced by a record of the metadata log
+	endOffset := log.LogEndOffset()
+	fenced, err := registry.FenceExpired(start.Add(12 * time.Second))
+	if err != nil || len(fenced) != 1 || fenced[0] != 2 {
+		t.Fatalf("fenced %v, %v, want broker 2", fenced, err)
+	}
+	if broker, _ := log.Broker(2); !broker.Fenced {
+		t.Error("broker is not fenced after missing its heartbeats")
+	}
+	records, err := log.Read(endOffset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || *records[0].Record.(*protocol.FenceBrokerRecord) != (protocol.FenceBrokerRecord{ID: 2, Epoch: epoch}) {
+		t.Errorf("records = %+v, want a FenceBrokerRecord of broker 2", records)
+	}
+
+	// the next heartbeat unfences it again
+	if result, err := registry.Heartbeat(2, epoch, epoch, false, start.Add(13*time.Second)); err != nil || result.Fenced {
+		t.Errorf("heartbeat after fencing = %+v, %v, want unfenced", result, err)
+	}
+}
+
+func TestBrokerRegistry_Errors(t *testing.T) {
+	log := NewLog(NewStandaloneRaftState(1))
+	registry := NewBrokerRegistry(log, 9*time.Second)
+	now := time.Now()
+
+	if _, err := registry.Heartbeat(2, 0, 0, false, now); !errors.Is(err, utils.ErrBrokerIDNotRegistered) {
+		t.Errorf("heartbeat of unknown broker = %v, want %v", err, utils.ErrBrokerIDNotRegistered)
+	}
+
+	incarnationID := uuid.New()
+	epoch, err := registry.Register(protocol.RegisterBrokerRecord{BrokerID: 2, IncarnationID: incarnationID}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := registry.Heartbeat(2, epoch+1, epoch, false, now); !errors.Is(err, utils.ErrStaleBrokerEpoch) {
+		t.Errorf("heartbeat of other epoch = %v, want %v", err, utils.ErrStaleBrokerEpoch)
+	}
+
+	// a retried registration keeps the epoch, another incarnation has to wait for the session to expire
+	if retried, err := registry.Register(protocol.RegisterBrokerRecord{BrokerID: 2, IncarnationID: incarnationID}, now); err != nil || retried != epoch {
+		t.Errorf("retried registration = %d, %v, want epoch %d", retried, err, epoch)
+	}
+	if _, err := registry.Register(protocol.RegisterBrokerRecord{BrokerID: 2, IncarnationID: uuid.New()}, now); !errors.Is(err, utils.ErrDuplicateBrokerRegistration) {
+		t.Errorf("registration of another incarnation = %v, want %v", err, utils.ErrDuplicateBrokerRegistration)
+	}
+	restarted, err := registry.Register(protocol.RegisterBrokerRecord{BrokerID: 2, IncarnationID: uuid.New()}, now.Add(10*time.Second))
+	if err != nil || restarted <= epoch {
+		t.Errorf("registration after the session expired = %d, %v, want a new epoch", restarted, err)
+	}
+}
+
+func TestBrokerRegistry_NextBrokerID(t *testing.T) {
+	tests := []struct {
+		name       string
+		registered []int32
+		want       int32
+	}{
+		{name: "no brokers", want: 1001},
+		{name: "after the registered brokers", registered: []int32{1001, 1002}, want: 1003},
+		{name: "lowest free ID", registered: []int32{1003, 1001}, want: 1002},
+		{name: "configured IDs are ignored", registered: []int32{1, 1001}, want: 1002},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewBrokerRegistry(NewLog(NewStandaloneRaftState(1)), 9*time.Second)
+			for _, id := range tt.registered {
+				if _, err := registry.Register(protocol.RegisterBrokerRecord{BrokerID: id, IncarnationID: uuid.New()}, time.Now()); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := registry.NextBrokerID(1000); got != tt.want {
+				t.Errorf("NextBrokerID() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package metadata
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"opentalaria/protocol"
+
+	"github.com/google/uuid"
+)
+
+// MetadataVersionFeature is the feature which versions the records of the metadata log.
+const MetadataVersionFeature = "metadata.version"
+
+// MetadataVersion is the metadata.version level of the records the broker writes, 3.0-IV1, which is the first level of KRaft.
+const MetadataVersion int16 = 1
+
+// Log is the KRaft metadata log of typed metadata records, with the state of the cluster materialized from them.
+// The records are appended to the metadata log of the raft state, in the current leader epoch,
+// so the followers of the quorum replicate them with Fetch requests.
+type Log struct {
+	raft *RaftState
+
+	// mu orders the appends, so the materialized state applies the records in the order of their offsets
+	mu      sync.RWMutex
+	brokers map[int32]protocol.RegisterBrokerRecord
+	topics  map[uuid.UUID]*TopicImage
+}
+
+// LogRecord is a metadata record with its offset in the log.
+type LogRecord struct {
+	Offset int64
+	Record protocol.MetadataRecord
+}
+
+// TopicImage is the state of a topic materialized from the metadata log.
+type TopicImage struct {
+	Name    string
+	TopicID uuid.UUID
+	// Partitions are the partitions of the topic by partition ID
+	Partitions map[int32]protocol.PartitionRecord
+}
+
+// NewLog returns the metadata log on top of the metadata log of the raft state.
+func NewLog(raft *RaftState) *Log {
+	return &Log{
+		raft:    raft,
+		brokers: map[int32]protocol.RegisterBrokerRecord{},
+		topics:  map[uuid.UUID]*TopicImage{},
+	}
+}
+
+// Append appends the records to the log in a single batch and applies them to the materialized state.
+// It returns the offset of the first record.
+func (l *Log) Append(records ...protocol.MetadataRecord) (int64, error) {
+	if len(records) == 0 {
+		return 0, fmt.Errorf("no metadata records to append")
+	}
+
+	now := time.Now()
+	batch := protocol.RecordBatch{
+		BaseTimestamp: now,
+		MaxTimestamp:  now,
+		ProducerId:    -1,
+		ProducerEpoch: -1,
+		BaseSequence:  -1,
+	}
+	for i, record := range records {
+		value, err := protocol.EncodeMetadataRecord(record)
+		if err != nil {
+			return 0, fmt.Errorf("error encoding metadata record of type %d: %w", record.RecordType(), err)
+		}
+		batch.Records = append(batch.Records, protocol.Record{OffsetDelta: int32(i), Value: value})
+	}
+	batch.LastOffsetDelta = int32(len(records) - 1)
+
+	// encode and decode the batch, so it has the size and offsets of a batch received from the wire
+	batchBytes, err := protocol.Encode(&batch)
+	if err != nil {
+		return 0, err
+	}
+	if err := protocol.Decode(batchBytes, &batch); err != nil {
+		return 0, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	baseOffset, err := l.raft.AppendMetadata(batch)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, record := range records {
+		l.apply(record)
+	}
+
+	return baseOffset, nil
+}
+
+// Read returns the records from the offset up to the end of the log.
+func (l *Log) Read(offset int64) ([]LogRecord, error) {
+	batches, err := l.raft.MetadataLog().Read(offset, math.MaxInt32, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []LogRecord
+	for _, batch := range batches {
+		for _, r := range batch.Records {
+			recordOffset := batch.BaseOffset + int64(r.OffsetDelta)
+			if recordOffset < offset {
+				continue
+			}
+
+			record, err := protocol.DecodeMetadataRecord(r.Value)
+			if err != nil {
+				return nil, fmt.Errorf("error decoding metadata record at offset %d: %w", recordOffset, err)
+			}
+			records = append(records, LogRecord{Offset: recordOffset, Record: record})
+		}
+	}
+
+	return records, nil
+}
+
+// apply updates the materialized state with the record. Partitions of unknown topics are ignored,
+// like records of types which don't change the materialized state.
+func (l *Log) apply(record protocol.MetadataRecord) {
+	switch r := record.(type) {
+	case *protocol.RegisterBrokerRecord:
+		l.brokers[r.BrokerID] = *r
+	case *protocol.UnregisterBrokerRecord:
+		if b, ok := l.brokers[r.BrokerID]; ok && b.BrokerEpoch == r.BrokerEpoch {
+			delete(l.brokers, r.BrokerID)
+		}
+	case *protocol.FenceBrokerRecord:
+		l.setFenced(r.ID, r.Epoch, true)
+	case *protocol.UnfenceBrokerRecord:
+		l.setFenced(r.ID, r.Epoch, false)
+	case *protocol.TopicRecord:
+		l.topics[r.TopicID] = &TopicImage{Name: r.Name, TopicID: r.TopicID, Partitions: map[int32]protocol.PartitionRecord{}}
+	case *protocol.PartitionRecord:
+		if topic, ok := l.topics[r.TopicID]; ok {
+			topic.Partitions[r.PartitionID] = *r
+		}
+	}
+}
+
+// setFenced changes the fencing of the registration of the broker, records of previous registrations are ignored.
+func (l *Log) setFenced(brokerID int32, brokerEpoch int64, fenced bool) {
+	if b, ok := l.brokers[brokerID]; ok && b.BrokerEpoch == brokerEpoch {
+		b.Fenced = fenced
+		l.brokers[brokerID] = b
+	}
+}
+
+// Broker returns the registration of the broker.
+func (l *Log) Broker(brokerID int32) (protocol.RegisterBrokerRecord, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	b, ok := l.brokers[brokerID]
+	return b, ok
+}
+
+// LogEndOffset returns the offset of the next record appended to the log.
+func (l *Log) LogEndOffset() int64 {
+	return l.raft.MetadataLog().LogEndOffset()
+}
+
+// Brokers returns the registered brokers, sorted by broker ID.
+func (l *Log) Brokers() []protocol.RegisterBrokerRecord {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	brokers := make([]protocol.RegisterBrokerRecord, 0, len(l.brokers))
+	for _, b := range l.brokers {
+		brokers = append(brokers, b)
+	}
+	slices.SortFunc(brokers, func(a, b protocol.RegisterBrokerRecord) int { return cmp.Compare(a.BrokerID, b.BrokerID) })
+
+	return brokers
+}
+
+// Topics returns the topics with their partitions, sorted by name.
+func (l *Log) Topics() []TopicImage {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	topics := make([]TopicImage, 0, len(l.topics))
+	for _, t := range l.topics {
+		topic := *t
+		topic.Partitions = make(map[int32]protocol.PartitionRecord, len(t.Partitions))
+		for id, p := range t.Partitions {
+			topic.Partitions[id] = p
+		}
+		topics = append(topics, topic)
+	}
+	slices.SortFunc(topics, func(a, b TopicImage) int { return cmp.Compare(a.Name, b.Name) })
+
+	return topics
+}
@@ -0,0 +1,60 @@
+package metadata
+
+import (
+	"testing"
+
+	"opentalaria/protocol"
+
+	"github.com/google/uuid"
+)
+
+func TestLog_AppendTopic(t *testing.T) {
+	log := NewLog(NewStandaloneRaftState(1))
+
+	topicID := uuid.New()
+	offset, err := log.Append(
+		&protocol.TopicRecord{Name: "test-topic", TopicID: topicID},
+		&protocol.PartitionRecord{PartitionID: 0, TopicID: topicID, Replicas: []int32{1}, Isr: []int32{1}, Leader: 1},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offset != 0 {
+		t.Errorf("offset = %d, want 0", offset)
+	}
+
+	topics := log.Topics()
+	if len(topics) != 1 || topics[0].Name != "test-topic" || topics[0].TopicID != topicID {
+		t.Fatalf("topics = %+v, want test-topic", topics)
+	}
+	if partition, ok := topics[0].Partitions[0]; !ok || partition.Leader != 1 {
+		t.Errorf("partitions = %+v, want partition 0 led by 1", topics[0].Partitions)
+	}
+
+	// the records are read back from the log, with their offsets
+	offset, err = log.Append(&protocol.TopicRecord{Name: "other-topic", TopicID: uuid.New()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offset != 2 {
+		t.Errorf("offset = %d, want 2", offset)
+	}
+
+	records, err := log.Read(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Offset != 1 || records[1].Offset != 2 {
+		t.Fatalf("records = %+v, want offsets 1 and 2", records)
+	}
+	if partition, ok := records[0].Record.(*protocol.PartitionRecord); !ok || partition.TopicID != topicID {
+		t.Errorf("record at offset 1 = %+v, want the partition of test-topic", records[0].Record)
+	}
+	if topic, ok := records[1].Record.(*protocol.TopicRecord); !ok || topic.Name != "other-topic" {
+		t.Errorf("record at offset 2 = %+v, want other-topic", records[1].Record)
+	}
+
+	if topics := log.Topics(); len(topics) != 2 || topics[0].Name != "other-topic" {
+		t.Errorf("topics = %+v, want them sorted by name", topics)
+	}
+}
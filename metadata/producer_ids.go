@@ -0,0 +1,44 @@
+package metadata
+
+import (
+	"math"
+	"sync"
+
+	"opentalaria/utils"
+)
+
+// ProducerIDManager allocates the producer IDs of idempotent producers.
+// IDs are only unique for the lifetime of the broker, since they are kept in memory.
+// It is safe for concurrent use, since producers initialize their IDs from different client connections.
+type ProducerIDManager struct {
+	mu     sync.Mutex
+	nextID int64
+}
+
+// NewProducerIDManager returns a ProducerIDManager which allocates IDs starting at 0.
+func NewProducerIDManager() *ProducerIDManager {
+	return &ProducerIDManager{}
+}
+
+// InitProducerID returns a new producer ID with epoch 0. A producer which already has an ID passes it with its epoch,
+// which bumps the epoch of the ID. Once the epoch is exhausted, the producer gets a new ID instead.
+// The returned error is a utils.KError, so it can be sent back to the client as is.
+func (m *ProducerIDManager) InitProducerID(producerID int64, producerEpoch int16) (int64, int16, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if producerID < 0 || producerEpoch == math.MaxInt16-1 {
+		id := m.nextID
+		m.nextID++
+		return id, 0, nil
+	}
+
+	if producerID >= m.nextID {
+		return -1, -1, utils.ErrInvalidProducerIDMapping
+	}
+	if producerEpoch < 0 {
+		return -1, -1, utils.ErrInvalidProducerEpoch
+	}
+
+	return producerID, producerEpoch + 1, nil
+}
@@ -0,0 +1,35 @@
+package metadata
+
+import (
+	"errors"
+	"math"
+	"opentalaria/utils"
+	"testing"
+)
+
+func TestProducerIDManager_InitProducerID(t *testing.T) {
+	m := NewProducerIDManager()
+
+	first, epoch, err := m.InitProducerID(-1, -1)
+	if err != nil || epoch != 0 {
+		t.Fatalf("first producer = epoch %d, error %v, want epoch 0", epoch, err)
+	}
+	second, _, _ := m.InitProducerID(-1, -1)
+	if second == first {
+		t.Errorf("producer IDs = %d, %d, want distinct IDs", first, second)
+	}
+
+	// a producer passing its ID bumps the epoch
+	if id, epoch, err := m.InitProducerID(first, 0); err != nil || id != first || epoch != 1 {
+		t.Errorf("bumped producer = %d at epoch %d, error %v, want %d at epoch 1", id, epoch, err, first)
+	}
+
+	// an exhausted epoch gets a new ID
+	if id, epoch, _ := m.InitProducerID(first, math.MaxInt16-1); id == first || id == second || epoch != 0 {
+		t.Errorf("exhausted producer = %d at epoch %d, want a new ID at epoch 0", id, epoch)
+	}
+
+	if _, _, err := m.InitProducerID(100, 0); !errors.Is(err, utils.ErrInvalidProducerIDMapping) {
+		t.Errorf("unknown producer ID error = %v, want %v", err, utils.ErrInvalidProducerIDMapping)
+	}
+}
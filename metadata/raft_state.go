@@ -0,0 +1,134 @@
+package metadata
+
+import (
+	"opentalaria/protocol"
+	"opentalaria/storage"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// ClusterMetadataTopic is the name of the topic holding the KRaft metadata log. It has a single partition.
+const ClusterMetadataTopic = "__cluster_metadata"
+
+// ClusterMetadataTopicID is the fixed topic ID of the metadata topic, which Kafka uses in place of a random one.
+var ClusterMetadataTopicID = uuid.UUID{15: 1}
+
+// RaftState is the in-memory election state of the local node in the KRaft quorum.
+// OpenTalaria can't negotiate a quorum with other nodes yet, only a standalone node knows the leader, which is itself.
+type RaftState struct {
+	mu              sync.RWMutex
+	nodeID          int32
+	voters          []int32
+	leaderID        int32
+	leaderEpoch     int32
+	votedFor        int32
+	lastOffset      int64
+	lastOffsetEpoch int32
+	metadataLog     *storage.PartitionLog
+}
+
+// NewRaftState returns the state of a node which has not joined a quorum, so it knows neither the voters nor the leader.
+func NewRaftState(nodeID int32) *RaftState {
+	return &RaftState{
+		nodeID:      nodeID,
+		leaderID:    -1,
+		votedFor:    -1,
+		metadataLog: storage.NewPartitionLog(),
+	}
+}
+
+// NewStandaloneRaftState returns the state of a single-node quorum. The local node is the only voter and elects itself
+// leader of epoch 0 without any quorum messages, so the broker is ready as soon as it starts.
+func NewStandaloneRaftState(nodeID int32) *RaftState {
+	return &RaftState{
+		nodeID:      nodeID,
+		voters:      []int32{nodeID},
+		leaderID:    nodeID,
+		votedFor:    nodeID,
+		metadataLog: storage.NewPartitionLog(),
+	}
+}
+
+// NodeID returns the ID of the local node.
+func (s *RaftState) NodeID() int32 {
+	return s.nodeID
+}
+
+// Leader returns the current leader and epoch. The leader is -1 if it is not known in the current epoch.
+func (s *RaftState) Leader() (leaderID int32, leaderEpoch int32) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.leaderID, s.leaderEpoch
+}
+
+// Voters returns the IDs of the voters in the quorum.
+func (s *RaftState) Voters() []int32 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]int32{}, s.voters...)
+}
+
+// MetadataLog returns the log of the metadata topic partition, which followers replicate with Fetch requests.
+func (s *RaftState) MetadataLog() *storage.PartitionLog {
+	return s.metadataLog
+}
+
+// AppendMetadata appends a batch of metadata records to the metadata log, in the current leader epoch.
+// It returns the base offset assigned to the batch.
+func (s *RaftState) AppendMetadata(batch protocol.RecordBatch) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	batch.PartitionLeaderEpoch = s.leaderEpoch
+	baseOffset, err := s.metadataLog.Append(batch)
+	if err != nil {
+		return 0, err
+	}
+
+	s.updateLogEnd(s.metadataLog.LogEndOffset(), s.leaderEpoch)
+
+	return baseOffset, nil
+}
+
+// updateLogEnd records the end offset and epoch of the local metadata log,
+// which decide whether a candidate's log is up to date enough to get the vote.
+func (s *RaftState) updateLogEnd(lastOffset int64, lastOffsetEpoch int32) {
+	s.lastOffset = lastOffset
+	s.lastOffsetEpoch = lastOffsetEpoch
+}
+
+// Vote decides whether the candidate gets the vote of the local node for the candidate epoch.
+// The vote is granted if the epoch is newer than the current one, or if it is the current one and the node hasn't voted
+// for someone else, and the candidate's log is at least as up to date as the local log.
+// A granted vote moves the node to the candidate epoch, with an unknown leader, unless it is a pre-vote, which doesn't change any state.
+// It returns the leader and epoch after the vote.
+func (s *RaftState) Vote(candidateID, candidateEpoch, lastOffsetEpoch int32, lastOffset int64, preVote bool) (granted bool, leaderID int32, leaderEpoch int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if candidateEpoch < s.leaderEpoch {
+		return false, s.leaderID, s.leaderEpoch
+	}
+
+	if candidateEpoch == s.leaderEpoch && s.votedFor != -1 && s.votedFor != candidateID {
+		return false, s.leaderID, s.leaderEpoch
+	}
+
+	logUpToDate := lastOffsetEpoch > s.lastOffsetEpoch || (lastOffsetEpoch == s.lastOffsetEpoch && lastOffset >= s.lastOffset)
+	if !logUpToDate {
+		return false, s.leaderID, s.leaderEpoch
+	}
+
+	if !preVote && candidateEpoch > s.leaderEpoch {
+		s.leaderEpoch = candidateEpoch
+		s.leaderID = -1
+	}
+	if !preVote {
+		s.votedFor = candidateID
+	}
+
+	return true, s.leaderID, s.leaderEpoch
+}
@@ -0,0 +1,64 @@
+package metadata
+
+import "testing"
+
+func TestRaftState_Vote(t *testing.T) {
+	tests := []struct {
+		name            string
+		candidateEpoch  int32
+		lastOffsetEpoch int32
+		lastOffset      int64
+		preVote         bool
+		wantGranted     bool
+		wantLeaderID    int32
+		wantLeaderEpoch int32
+	}{
+		{name: "stale epoch", candidateEpoch: 0, lastOffsetEpoch: 1, lastOffset: 10, wantGranted: false, wantLeaderID: 1, wantLeaderEpoch: 1},
+		{name: "current epoch already voted", candidateEpoch: 1, lastOffsetEpoch: 1, lastOffset: 10, wantGranted: false, wantLeaderID: 1, wantLeaderEpoch: 1},
+		{name: "log behind", candidateEpoch: 2, lastOffsetEpoch: 1, lastOffset: 5, wantGranted: false, wantLeaderID: 1, wantLeaderEpoch: 1},
+		{name: "pre-vote", candidateEpoch: 2, lastOffsetEpoch: 1, lastOffset: 10, preVote: true, wantGranted: true, wantLeaderID: 1, wantLeaderEpoch: 1},
+		{name: "granted", candidateEpoch: 2, lastOffsetEpoch: 1, lastOffset: 10, wantGranted: true, wantLeaderID: -1, wantLeaderEpoch: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the local node leads epoch 1, so there is an epoch before it which is stale
+			s := NewStandaloneRaftState(1)
+			s.leaderEpoch = 1
+			s.updateLogEnd(10, 1)
+
+			granted, leaderID, leaderEpoch := s.Vote(2, tt.candidateEpoch, tt.lastOffsetEpoch, tt.lastOffset, tt.preVote)
+			if granted != tt.wantGranted || leaderID != tt.wantLeaderID || leaderEpoch != tt.wantLeaderEpoch {
+				t.Errorf("Vote() = %v, %d, %d, want %v, %d, %d", granted, leaderID, leaderEpoch, tt.wantGranted, tt.wantLeaderID, tt.wantLeaderEpoch)
+			}
+		})
+	}
+}
+
+func TestNewStandaloneRaftState(t *testing.T) {
+	s := NewStandaloneRaftState(1)
+
+	if leaderID, leaderEpoch := s.Leader(); leaderID != 1 || leaderEpoch != 0 {
+		t.Errorf("leader = %d in epoch %d, want the local node 1 in epoch 0", leaderID, leaderEpoch)
+	}
+	if voters := s.Voters(); len(voters) != 1 || voters[0] != 1 {
+		t.Errorf("voters = %v, want only the local node", voters)
+	}
+	// the local node voted for itself, so no other candidate can take over epoch 0
+	if granted, _, _ := s.Vote(2, 0, 0, 0, false); granted {
+		t.Error("expected a candidate of the standalone epoch to be rejected")
+	}
+}
+
+func TestRaftState_VoteOncePerEpoch(t *testing.T) {
+	s := NewStandaloneRaftState(1)
+
+	if granted, _, _ := s.Vote(2, 2, 0, 0, false); !granted {
+		t.Fatal("expected the first candidate of the epoch to get the vote")
+	}
+	if granted, _, _ := s.Vote(3, 2, 0, 0, false); granted {
+		t.Error("expected a second candidate of the same epoch to be rejected")
+	}
+	if granted, _, _ := s.Vote(2, 2, 0, 0, false); !granted {
+		t.Error("expected a repeated request of the same candidate to be granted")
+	}
+}
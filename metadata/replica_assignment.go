@@ -0,0 +1,157 @@
+package metadata
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"opentalaria/utils"
+)
+
+// ReplicaBroker is a broker which replicas can be assigned to, with its rack, which is nil if the broker has none.
+type ReplicaBroker struct {
+	ID   int32
+	Rack *string
+}
+
+// AssignReplicas assigns the replicas of the partitions of a new topic to the brokers, the way Kafka does.
+// The first replica of each partition, its preferred leader, goes round-robin over the brokers, starting at startIndex,
+// and the other replicas follow at a shift which grows every time the partitions went once around the brokers,
+// so the leaders and followers of a broker are spread over the others. Callers pick a random startIndex,
+// so the first partitions of all topics don't end up on the same broker.
+//
+// If all brokers have a rack, the brokers are ordered to alternate between the racks, and the replicas of a partition
+// are spread over as many racks as possible. Without racks, or with brokers without a rack, the replicas are assigned
+// without rack awareness, which is the same for single-rack clusters.
+// The result has the replicas of each partition, by partition ID.
+func AssignReplicas(brokers []ReplicaBroker, numPartitions int32, replicationFactor int16, startIndex int) ([][]int32, error) {
+	if numPartitions <= 0 {
+		return nil, utils.ErrInvalidPartitions
+	}
+	if replicationFactor <= 0 {
+		return nil, utils.ErrInvalidReplicationFactor
+	}
+	if int(replicationFactor) > len(brokers) {
+		return nil, fmt.Errorf("%w: replication factor %d larger than the %d available brokers", utils.ErrInvalidReplicationFactor, replicationFactor, len(brokers))
+	}
+
+	if slices.ContainsFunc(brokers, func(b ReplicaBroker) bool { return b.Rack == nil }) {
+		return assignReplicasRackUnaware(brokers, numPartitions, replicationFactor, startIndex), nil
+	}
+
+	return assignReplicasRackAware(brokers, numPartitions, replicationFactor, startIndex), nil
+}
+
+func assignReplicasRackUnaware(brokers []ReplicaBroker, numPartitions int32, replicationFactor int16, startIndex int) [][]int32 {
+	ids := make([]int32, 0, len(brokers))
+	for _, b := range brokers {
+		ids = append(ids, b.ID)
+	}
+	slices.Sort(ids)
+
+	n := len(ids)
+	startIndex %= n
+	replicaShift := startIndex
+	assignment := make([][]int32, numPartitions)
+	for p := 0; p < int(numPartitions); p++ {
+		if p > 0 && p%n == 0 {
+			replicaShift++
+		}
+
+		first := (p + startIndex) % n
+		replicas := []int32{ids[first]}
+		for j := 0; j < int(replicationFactor)-1; j++ {
+			replicas = append(replicas, ids[replicaIndex(first, replicaShift, j, n)])
+		}
+		assignment[p] = replicas
+	}
+
+	return assignment
+}
+
+func assignReplicasRackAware(brokers []ReplicaBroker, numPartitions int32, replicationFactor int16, startIndex int) [][]int32 {
+	racks := map[int32]string{}
+	distinctRacks := map[string]bool{}
+	for _, b := range brokers {
+		racks[b.ID] = *b.Rack
+		distinctRacks[*b.Rack] = true
+	}
+	arranged := rackAlternatedBrokers(brokers)
+	numRacks := len(distinctRacks)
+
+	n := len(arranged)
+	startIndex %= n
+	replicaShift := startIndex
+	assignment := make([][]int32, numPartitions)
+	for p := 0; p < int(numPartitions); p++ {
+		if p > 0 && p%n == 0 {
+			replicaShift++
+		}
+
+		first := (p + startIndex) % n
+		leader := arranged[first]
+		replicas := []int32{leader}
+		racksWithReplicas := map[string]bool{racks[leader]: true}
+		brokersWithReplicas := map[int32]bool{leader: true}
+
+		// a broker is skipped if its rack already has a replica, unless all racks have one,
+		// and if it already has a replica, unless all brokers have one
+		k := 0
+		for j := 0; j < int(replicationFactor)-1; j++ {
+			for {
+				broker := arranged[replicaIndex(first, replicaShift*numRacks, k, n)]
+				k++
+
+				rack := racks[broker]
+				if (!racksWithReplicas[rack] || len(racksWithReplicas) == numRacks) &&
+					(!brokersWithReplicas[broker] || len(brokersWithReplicas) == n) {
+					replicas = append(replicas, broker)
+					racksWithReplicas[rack] = true
+					brokersWithReplicas[broker] = true
+					break
+				}
+			}
+		}
+		assignment[p] = replicas
+	}
+
+	return assignment
+}
+
+// replicaIndex returns the index of a follower of the partition whose first replica is at index first.
+// The shift is never a multiple of n, so a follower never lands on the first replica.
+func replicaIndex(first, replicaShift, follower, n int) int {
+	if n == 1 {
+		return first
+	}
+
+	shift := 1 + (replicaShift+follower)%(n-1)
+	return (first + shift) % n
+}
+
+// rackAlternatedBrokers orders the brokers so that consecutive brokers are on different racks, as long as possible.
+// With brokers 0 and 1 on rack a, 2 on rack b and 3 and 4 on rack c, the order is 0, 2, 3, 1, 4.
+func rackAlternatedBrokers(brokers []ReplicaBroker) []int32 {
+	byRack := map[string][]int32{}
+	for _, b := range brokers {
+		byRack[*b.Rack] = append(byRack[*b.Rack], b.ID)
+	}
+
+	racks := make([]string, 0, len(byRack))
+	for rack, ids := range byRack {
+		slices.Sort(ids)
+		racks = append(racks, rack)
+	}
+	slices.SortFunc(racks, cmp.Compare[string])
+
+	arranged := make([]int32, 0, len(brokers))
+	for i := 0; len(arranged) < len(brokers); i++ {
+		for _, rack := range racks {
+			if i < len(byRack[rack]) {
+				arranged = append(arranged, byRack[rack][i])
+			}
+		}
+	}
+
+	return arranged
+}
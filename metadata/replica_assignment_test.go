@@ -0,0 +1,85 @@
+package metadata
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"opentalaria/utils"
+)
+
+func TestAssignReplicas_RackAware(t *testing.T) {
+	rackA, rackB := "a", "b"
+	brokers := []ReplicaBroker{{ID: 0, Rack: &rackA}, {ID: 1, Rack: &rackB}, {ID: 2, Rack: &rackA}}
+
+	got, err := AssignReplicas(brokers, 6, 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]int32{{0, 1}, {1, 2}, {2, 1}, {0, 1}, {1, 2}, {2, 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AssignReplicas() = %v, want %v", got, want)
+	}
+
+	racks := map[int32]string{0: rackA, 1: rackB, 2: rackA}
+	leaders := map[int32]int{}
+	for p, replicas := range got {
+		leaders[replicas[0]]++
+		if racks[replicas[0]] == racks[replicas[1]] {
+			t.Errorf("the replicas %v of partition %d are on the same rack", replicas, p)
+		}
+	}
+	for _, b := range brokers {
+		if leaders[b.ID] != 2 {
+			t.Errorf("broker %d leads %d partitions, want 2", b.ID, leaders[b.ID])
+		}
+	}
+}
+
+func TestAssignReplicas_RackUnaware(t *testing.T) {
+	rack := "a"
+	tests := []struct {
+		name    string
+		brokers []ReplicaBroker
+		want    [][]int32
+	}{
+		{
+			name:    "single broker",
+			brokers: []ReplicaBroker{{ID: 1}},
+			want:    [][]int32{{1}, {1}, {1}},
+		},
+		{
+			name:    "without racks",
+			brokers: []ReplicaBroker{{ID: 2}, {ID: 0}, {ID: 1}},
+			want:    [][]int32{{0, 1}, {1, 2}, {2, 0}},
+		},
+		{
+			name:    "single rack",
+			brokers: []ReplicaBroker{{ID: 0, Rack: &rack}, {ID: 1, Rack: &rack}, {ID: 2, Rack: &rack}},
+			want:    [][]int32{{0, 1}, {1, 2}, {2, 0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AssignReplicas(tt.brokers, 3, int16(len(tt.want[0])), 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AssignReplicas() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssignReplicas_Errors(t *testing.T) {
+	brokers := []ReplicaBroker{{ID: 1}}
+
+	if _, err := AssignReplicas(brokers, 1, 2, 0); !errors.Is(err, utils.ErrInvalidReplicationFactor) {
+		t.Errorf("replication factor above the number of brokers error = %v, want %v", err, utils.ErrInvalidReplicationFactor)
+	}
+	if _, err := AssignReplicas(brokers, 0, 1, 0); !errors.Is(err, utils.ErrInvalidPartitions) {
+		t.Errorf("no partitions error = %v, want %v", err, utils.ErrInvalidPartitions)
+	}
+}
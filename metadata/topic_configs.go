@@ -0,0 +1,55 @@
+package metadata
+
+import (
+	"fmt"
+	"slices"
+
+	"opentalaria/utils"
+)
+
+// TopicConfigMessageTimestampType selects the timestamps of the records of a topic: CreateTime keeps the timestamps
+// of the producers, LogAppendTime replaces them with the time the broker appended the batch.
+const TopicConfigMessageTimestampType = "message.timestamp.type"
+
+// values of TopicConfigMessageTimestampType
+const (
+	TimestampTypeCreateTime    = "CreateTime"
+	TimestampTypeLogAppendTime = "LogAppendTime"
+)
+
+// topicConfigDefaults are the topic configs supported by the broker, with the values of topics without an override.
+var topicConfigDefaults = map[string]string{
+	TopicConfigMessageTimestampType: TimestampTypeCreateTime,
+}
+
+// ValidateTopicConfigs checks the values of the supported topic configs. Other configs are ignored, since the broker
+// doesn't support them yet. The returned error wraps utils.ErrInvalidConfig, so it can be sent back to the client as is.
+func ValidateTopicConfigs(configs map[string]string) error {
+	if value, ok := configs[TopicConfigMessageTimestampType]; ok && value != TimestampTypeCreateTime && value != TimestampTypeLogAppendTime {
+		return fmt.Errorf("%w: invalid value %q for %s, expected %s or %s", utils.ErrInvalidConfig, value, TopicConfigMessageTimestampType, TimestampTypeCreateTime, TimestampTypeLogAppendTime)
+	}
+
+	return nil
+}
+
+// TopicConfigNames returns the names of the topic configs supported by the broker, sorted.
+func TopicConfigNames() []string {
+	names := make([]string, 0, len(topicConfigDefaults))
+	for name := range topicConfigDefaults {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// Config returns the value of the topic config, the default if the topic doesn't override it.
+// The second return value is false for configs the broker doesn't support.
+func (t Topic) Config(name string) (value string, ok bool) {
+	if value, ok := t.Configs[name]; ok {
+		return value, true
+	}
+
+	value, ok = topicConfigDefaults[name]
+	return value, ok
+}
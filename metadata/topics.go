@@ -0,0 +1,228 @@
+package metadata
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"opentalaria/utils"
+
+	"github.com/google/uuid"
+)
+
+const maxTopicNameLength = 249
+
+var legalTopicChars = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+// Topic holds the metadata of a topic known to the broker.
+type Topic struct {
+	Name              string
+	TopicID           uuid.UUID
+	NumPartitions     int32
+	ReplicationFactor int16
+	// Replicas are the brokers of the replicas of each partition, by partition ID, the first one is the preferred leader.
+	// They are nil for topics created without an assignment, which only live on the local broker.
+	Replicas [][]int32
+	// Configs are the topic configs which override the defaults, like message.timestamp.type.
+	Configs map[string]string
+}
+
+// TopicRegistry is an in-memory registry of topics keyed by topic name.
+// It is safe for concurrent use, since topics can be created and read from multiple client connections.
+type TopicRegistry struct {
+	mu     sync.RWMutex
+	topics map[string]Topic
+}
+
+// NewTopicRegistry returns an empty TopicRegistry.
+func NewTopicRegistry() *TopicRegistry {
+	return &TopicRegistry{
+		topics: map[string]Topic{},
+	}
+}
+
+// CreateTopic validates the topic and adds it to the registry.
+// If validateOnly is set, the checks are performed, but the topic is not created.
+// The returned error is a utils.KError, so it can be sent back to the client as is.
+func (r *TopicRegistry) CreateTopic(name string, numPartitions int32, replicationFactor int16, validateOnly bool) (Topic, error) {
+	return r.createTopic(name, numPartitions, replicationFactor, nil, validateOnly)
+}
+
+// CreateTopicWithReplicas creates a topic with the replica assignment, which sets the number of partitions
+// and the replication factor. See CreateTopic.
+func (r *TopicRegistry) CreateTopicWithReplicas(name string, replicas [][]int32, validateOnly bool) (Topic, error) {
+	if len(replicas) == 0 {
+		return Topic{}, utils.ErrInvalidPartitions
+	}
+
+	return r.createTopic(name, int32(len(replicas)), int16(len(replicas[0])), replicas, validateOnly)
+}
+
+func (r *TopicRegistry) createTopic(name string, numPartitions int32, replicationFactor int16, replicas [][]int32, validateOnly bool) (Topic, error) {
+	if err := ValidateTopicName(name); err != nil {
+		return Topic{}, err
+	}
+
+	if numPartitions <= 0 {
+		return Topic{}, utils.ErrInvalidPartitions
+	}
+
+	if replicationFactor <= 0 {
+		return Topic{}, utils.ErrInvalidReplicationFactor
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.topics[name]; ok {
+		return Topic{}, utils.ErrTopicAlreadyExists
+	}
+
+	for existing := range r.topics {
+		if topicNamesCollide(name, existing) {
+			return Topic{}, fmt.Errorf("%w: topic %s collides with existing topic %s", utils.ErrInvalidTopic, name, existing)
+		}
+	}
+
+	topic := Topic{
+		Name:              name,
+		TopicID:           uuid.New(),
+		NumPartitions:     numPartitions,
+		ReplicationFactor: replicationFactor,
+		Replicas:          replicas,
+	}
+
+	if !validateOnly {
+		r.topics[name] = topic
+	}
+
+	return topic, nil
+}
+
+// SetTopicConfigs replaces the config overrides of the topic, keeping those supported by the broker.
+// The configs are validated with ValidateTopicConfigs. If the topic doesn't exist, utils.ErrUnknownTopicOrPartition is returned.
+func (r *TopicRegistry) SetTopicConfigs(name string, configs map[string]string) error {
+	if err := ValidateTopicConfigs(configs); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	topic, ok := r.topics[name]
+	if !ok {
+		return utils.ErrUnknownTopicOrPartition
+	}
+
+	topic.Configs = map[string]string{}
+	for key, value := range configs {
+		if _, supported := topicConfigDefaults[key]; supported {
+			topic.Configs[key] = value
+		}
+	}
+	r.topics[name] = topic
+
+	return nil
+}
+
+// DeleteTopic removes the topic with the given name from the registry and returns it.
+// If the topic doesn't exist, utils.ErrUnknownTopicOrPartition is returned.
+func (r *TopicRegistry) DeleteTopic(name string) (Topic, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	topic, ok := r.topics[name]
+	if !ok {
+		return Topic{}, utils.ErrUnknownTopicOrPartition
+	}
+
+	delete(r.topics, name)
+
+	return topic, nil
+}
+
+// DeleteTopicByID removes the topic with the given topic ID from the registry and returns it.
+// If the topic doesn't exist, utils.ErrUnknownTopicOrPartition is returned.
+func (r *TopicRegistry) DeleteTopicByID(topicID uuid.UUID) (Topic, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name, topic := range r.topics {
+		if topic.TopicID == topicID {
+			delete(r.topics, name)
+			return topic, nil
+		}
+	}
+
+	return Topic{}, utils.ErrUnknownTopicOrPartition
+}
+
+// GetTopic returns the topic with the given name and true, or an empty Topic and false if the topic doesn't exist.
+func (r *TopicRegistry) GetTopic(name string) (Topic, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	topic, ok := r.topics[name]
+	return topic, ok
+}
+
+// GetTopicByID returns the topic with the given topic ID and true, or an empty Topic and false if the topic doesn't exist.
+func (r *TopicRegistry) GetTopicByID(topicID uuid.UUID) (Topic, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, topic := range r.topics {
+		if topic.TopicID == topicID {
+			return topic, true
+		}
+	}
+
+	return Topic{}, false
+}
+
+// ListTopics returns all topics in the registry, sorted by name.
+func (r *TopicRegistry) ListTopics() []Topic {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	topics := make([]Topic, 0, len(r.topics))
+	for _, topic := range r.topics {
+		topics = append(topics, topic)
+	}
+
+	sort.Slice(topics, func(i, j int) bool {
+		return topics[i].Name < topics[j].Name
+	})
+
+	return topics
+}
+
+// ValidateTopicName checks the topic name against Kafka's topic naming rules.
+// The returned error wraps utils.ErrInvalidTopic with the rule that was violated, so it can be sent back to the client as is.
+func ValidateTopicName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: topic name is empty", utils.ErrInvalidTopic)
+	}
+
+	if name == "." || name == ".." {
+		return fmt.Errorf("%w: topic name can't be %q", utils.ErrInvalidTopic, name)
+	}
+
+	if len(name) > maxTopicNameLength {
+		return fmt.Errorf("%w: topic name is longer than %d characters", utils.ErrInvalidTopic, maxTopicNameLength)
+	}
+
+	if !legalTopicChars.MatchString(name) {
+		return fmt.Errorf("%w: topic name %q contains characters other than ASCII alphanumerics, '.', '_' and '-'", utils.ErrInvalidTopic, name)
+	}
+
+	return nil
+}
+
+// topicNamesCollide reports whether two different topic names only differ by '.' and '_'.
+// Kafka replaces '.' with '_' in metric names, so the metrics of such topics would collide.
+func topicNamesCollide(name, other string) bool {
+	return name != other && strings.ReplaceAll(name, ".", "_") == strings.ReplaceAll(other, ".", "_")
+}
@@ -0,0 +1,190 @@
+package metadata
+
+import (
+	"errors"
+	"fmt"
+	"opentalaria/utils"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestTopicRegistry_CreateTopic(t *testing.T) {
+	tests := []struct {
+		name              string
+		topicName         string
+		numPartitions     int32
+		replicationFactor int16
+		wantErr           error
+	}{
+		{name: "valid topic", topicName: "test-topic", numPartitions: 3, replicationFactor: 1, wantErr: nil},
+		{name: "empty name", topicName: "", numPartitions: 1, replicationFactor: 1, wantErr: utils.ErrInvalidTopic},
+		{name: "illegal characters", topicName: "test topic!", numPartitions: 1, replicationFactor: 1, wantErr: utils.ErrInvalidTopic},
+		{name: "name too long", topicName: strings.Repeat("a", 250), numPartitions: 1, replicationFactor: 1, wantErr: utils.ErrInvalidTopic},
+		{name: "zero partitions", topicName: "test-topic", numPartitions: 0, replicationFactor: 1, wantErr: utils.ErrInvalidPartitions},
+		{name: "zero replication factor", topicName: "test-topic", numPartitions: 1, replicationFactor: 0, wantErr: utils.ErrInvalidReplicationFactor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewTopicRegistry()
+
+			_, err := r.CreateTopic(tt.topicName, tt.numPartitions, tt.replicationFactor, false)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("TopicRegistry.CreateTopic() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			_, exists := r.GetTopic(tt.topicName)
+			if exists != (tt.wantErr == nil) {
+				t.Errorf("TopicRegistry.GetTopic() exists = %v, want %v", exists, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestTopicRegistry_CreateTopicDuplicate(t *testing.T) {
+	r := NewTopicRegistry()
+
+	if _, err := r.CreateTopic("test-topic", 1, 1, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.CreateTopic("test-topic", 1, 1, false); !errors.Is(err, utils.ErrTopicAlreadyExists) {
+		t.Errorf("TopicRegistry.CreateTopic() error = %v, want %v", err, utils.ErrTopicAlreadyExists)
+	}
+}
+
+func TestTopicRegistry_CreateTopicCollision(t *testing.T) {
+	r := NewTopicRegistry()
+
+	if _, err := r.CreateTopic("test.topic", 1, 1, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.CreateTopic("test_topic", 1, 1, false); !errors.Is(err, utils.ErrInvalidTopic) {
+		t.Errorf("TopicRegistry.CreateTopic() error = %v, want %v", err, utils.ErrInvalidTopic)
+	}
+}
+
+func TestValidateTopicName(t *testing.T) {
+	tests := []struct {
+		name      string
+		topicName string
+		wantErr   error
+	}{
+		{name: "valid name", topicName: "test-topic_1.v2", wantErr: nil},
+		{name: "empty name", topicName: "", wantErr: utils.ErrInvalidTopic},
+		{name: "single dot", topicName: ".", wantErr: utils.ErrInvalidTopic},
+		{name: "double dot", topicName: "..", wantErr: utils.ErrInvalidTopic},
+		{name: "dots in a longer name", topicName: "...", wantErr: nil},
+		{name: "maximum length", topicName: strings.Repeat("a", 249), wantErr: nil},
+		{name: "name too long", topicName: strings.Repeat("a", 250), wantErr: utils.ErrInvalidTopic},
+		{name: "space", topicName: "test topic", wantErr: utils.ErrInvalidTopic},
+		{name: "slash", topicName: "test/topic", wantErr: utils.ErrInvalidTopic},
+		{name: "non-ASCII letter", topicName: "tést", wantErr: utils.ErrInvalidTopic},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateTopicName(tt.topicName); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateTopicName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTopicRegistry_CreateTopicValidateOnly(t *testing.T) {
+	r := NewTopicRegistry()
+
+	if _, err := r.CreateTopic("test-topic", 1, 1, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := r.GetTopic("test-topic"); ok {
+		t.Error("topic should not be created when validateOnly is set")
+	}
+}
+
+func TestTopicRegistry_DeleteTopic(t *testing.T) {
+	r := NewTopicRegistry()
+
+	created, err := r.CreateTopic("test-topic", 1, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.CreateTopic("other-topic", 1, 1, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.DeleteTopic("test-topic"); err != nil {
+		t.Errorf("TopicRegistry.DeleteTopic() error = %v", err)
+	}
+	if _, err := r.DeleteTopic("test-topic"); !errors.Is(err, utils.ErrUnknownTopicOrPartition) {
+		t.Errorf("TopicRegistry.DeleteTopic() error = %v, want %v", err, utils.ErrUnknownTopicOrPartition)
+	}
+	if _, err := r.DeleteTopicByID(created.TopicID); !errors.Is(err, utils.ErrUnknownTopicOrPartition) {
+		t.Errorf("TopicRegistry.DeleteTopicByID() error = %v, want %v", err, utils.ErrUnknownTopicOrPartition)
+	}
+
+	other, _ := r.GetTopic("other-topic")
+	if _, err := r.DeleteTopicByID(other.TopicID); err != nil {
+		t.Errorf("TopicRegistry.DeleteTopicByID() error = %v", err)
+	}
+
+	if topics := r.ListTopics(); len(topics) != 0 {
+		t.Errorf("expected no topics left, got %v", topics)
+	}
+}
+
+func TestTopicRegistry_ConcurrentAccess(t *testing.T) {
+	r := NewTopicRegistry()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			name := fmt.Sprintf("topic-%d", i)
+			r.CreateTopic(name, 1, 1, false)
+			r.ListTopics()
+			r.DeleteTopic(name)
+		}(i)
+	}
+	wg.Wait()
+
+	if topics := r.ListTopics(); len(topics) != 0 {
+		t.Errorf("expected no topics left, got %v", topics)
+	}
+}
+
+func TestTopicRegistry_SetTopicConfigs(t *testing.T) {
+	registry := NewTopicRegistry()
+	if _, err := registry.CreateTopic("test-topic", 1, 1, false); err != nil {
+		t.Fatal(err)
+	}
+
+	topic, _ := registry.GetTopic("test-topic")
+	if value, ok := topic.Config(TopicConfigMessageTimestampType); !ok || value != TimestampTypeCreateTime {
+		t.Errorf("default %s = %q, %v, want %s", TopicConfigMessageTimestampType, value, ok, TimestampTypeCreateTime)
+	}
+
+	configs := map[string]string{TopicConfigMessageTimestampType: TimestampTypeLogAppendTime, "cleanup.policy": "compact"}
+	if err := registry.SetTopicConfigs("test-topic", configs); err != nil {
+		t.Fatal(err)
+	}
+	topic, _ = registry.GetTopic("test-topic")
+	if value, _ := topic.Config(TopicConfigMessageTimestampType); value != TimestampTypeLogAppendTime {
+		t.Errorf("%s = %q, want %s", TopicConfigMessageTimestampType, value, TimestampTypeLogAppendTime)
+	}
+	// configs the broker doesn't support are not kept
+	if _, ok := topic.Config("cleanup.policy"); ok || len(topic.Configs) != 1 {
+		t.Errorf("configs = %v, want only %s", topic.Configs, TopicConfigMessageTimestampType)
+	}
+
+	err := registry.SetTopicConfigs("test-topic", map[string]string{TopicConfigMessageTimestampType: "WallClockTime"})
+	if !errors.Is(err, utils.ErrInvalidConfig) {
+		t.Errorf("invalid value: err = %v, want %v", err, utils.ErrInvalidConfig)
+	}
+	if err := registry.SetTopicConfigs("missing-topic", nil); !errors.Is(err, utils.ErrUnknownTopicOrPartition) {
+		t.Errorf("missing topic: err = %v, want %v", err, utils.ErrUnknownTopicOrPartition)
+	}
+}
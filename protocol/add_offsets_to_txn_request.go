@@ -42,24 +42,24 @@ func (r *AddOffsetsToTxnRequest) decode(pd packetDecoder, version int16) (err er
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.TransactionalID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TransactionalID", err)
 	}
 
 	if r.ProducerID, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProducerID", err)
 	}
 
 	if r.ProducerEpoch, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProducerEpoch", err)
 	}
 
 	if r.GroupID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	if r.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -85,6 +85,5 @@ func (r *AddOffsetsToTxnRequest) IsValidVersion() bool {
 }
 
 func (r *AddOffsetsToTxnRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
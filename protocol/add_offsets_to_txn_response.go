@@ -32,16 +32,16 @@ func (r *AddOffsetsToTxnResponse) decode(pd packetDecoder, version int16) (err e
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -67,8 +67,7 @@ func (r *AddOffsetsToTxnResponse) IsValidVersion() bool {
 }
 
 func (r *AddOffsetsToTxnResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *AddOffsetsToTxnResponse) throttleTime() time.Duration {
@@ -30,16 +30,16 @@ func (a *AddPartitionsToTxnTopic_AddPartitionsToTxnRequest) encode(pe packetEnco
 func (a *AddPartitionsToTxnTopic_AddPartitionsToTxnRequest) decode(pd packetDecoder, version int16) (err error) {
 	a.Version = version
 	if a.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if a.Partitions, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 
 	if a.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -102,39 +102,39 @@ func (t *AddPartitionsToTxnTransaction) decode(pd packetDecoder, version int16)
 	t.Version = version
 	if t.Version >= 4 {
 		if t.TransactionalID, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "TransactionalID", err)
 		}
 	}
 
 	if t.Version >= 4 {
 		if t.ProducerID, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "ProducerID", err)
 		}
 	}
 
 	if t.Version >= 4 {
 		if t.ProducerEpoch, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "ProducerEpoch", err)
 		}
 	}
 
 	if t.Version >= 4 {
 		if t.VerifyOnly, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "VerifyOnly", err)
 		}
 	}
 
 	if t.Version >= 4 {
 		var numTopics int
 		if numTopics, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Topics", err)
 		}
 		if numTopics > 0 {
 			t.Topics = make([]AddPartitionsToTxnTopic_AddPartitionsToTxnRequest, numTopics)
 			for i := 0; i < numTopics; i++ {
 				var block AddPartitionsToTxnTopic_AddPartitionsToTxnRequest
 				if err := block.decode(pd, t.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Topics", i, err)
 				}
 				t.Topics[i] = block
 			}
@@ -143,7 +143,7 @@ func (t *AddPartitionsToTxnTransaction) decode(pd packetDecoder, version int16)
 
 	if t.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -218,14 +218,14 @@ func (r *AddPartitionsToTxnRequest) decode(pd packetDecoder, version int16) (err
 	if r.Version >= 4 {
 		var numTransactions int
 		if numTransactions, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Transactions", err)
 		}
 		if numTransactions > 0 {
 			r.Transactions = make([]AddPartitionsToTxnTransaction, numTransactions)
 			for i := 0; i < numTransactions; i++ {
 				var block AddPartitionsToTxnTransaction
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Transactions", i, err)
 				}
 				r.Transactions[i] = block
 			}
@@ -234,33 +234,33 @@ func (r *AddPartitionsToTxnRequest) decode(pd packetDecoder, version int16) (err
 
 	if r.Version >= 0 && r.Version <= 3 {
 		if r.V3AndBelowTransactionalID, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "V3AndBelowTransactionalID", err)
 		}
 	}
 
 	if r.Version >= 0 && r.Version <= 3 {
 		if r.V3AndBelowProducerID, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "V3AndBelowProducerID", err)
 		}
 	}
 
 	if r.Version >= 0 && r.Version <= 3 {
 		if r.V3AndBelowProducerEpoch, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "V3AndBelowProducerEpoch", err)
 		}
 	}
 
 	if r.Version >= 0 && r.Version <= 3 {
 		var numV3AndBelowTopics int
 		if numV3AndBelowTopics, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "V3AndBelowTopics", err)
 		}
 		if numV3AndBelowTopics > 0 {
 			r.V3AndBelowTopics = make([]AddPartitionsToTxnTopic_AddPartitionsToTxnRequest, numV3AndBelowTopics)
 			for i := 0; i < numV3AndBelowTopics; i++ {
 				var block AddPartitionsToTxnTopic_AddPartitionsToTxnRequest
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "V3AndBelowTopics", i, err)
 				}
 				r.V3AndBelowTopics[i] = block
 			}
@@ -269,7 +269,7 @@ func (r *AddPartitionsToTxnRequest) decode(pd packetDecoder, version int16) (err
 
 	if r.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -295,6 +295,5 @@ func (r *AddPartitionsToTxnRequest) IsValidVersion() bool {
 }
 
 func (r *AddPartitionsToTxnRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -37,19 +37,19 @@ func (a *AddPartitionsToTxnTopicResult_AddPartitionsToTxnResponse) encode(pe pac
 func (a *AddPartitionsToTxnTopicResult_AddPartitionsToTxnResponse) decode(pd packetDecoder, version int16) (err error) {
 	a.Version = version
 	if a.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	var numResultsByPartition int
 	if numResultsByPartition, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResultsByPartition", err)
 	}
 	if numResultsByPartition > 0 {
 		a.ResultsByPartition = make([]AddPartitionsToTxnPartitionResult, numResultsByPartition)
 		for i := 0; i < numResultsByPartition; i++ {
 			var block AddPartitionsToTxnPartitionResult
 			if err := block.decode(pd, a.Version); err != nil {
-				return err
+				return wrapElementError(pd, "ResultsByPartition", i, err)
 			}
 			a.ResultsByPartition[i] = block
 		}
@@ -57,7 +57,7 @@ func (a *AddPartitionsToTxnTopicResult_AddPartitionsToTxnResponse) decode(pd pac
 
 	if a.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -88,16 +88,16 @@ func (a *AddPartitionsToTxnPartitionResult) encode(pe packetEncoder, version int
 func (a *AddPartitionsToTxnPartitionResult) decode(pd packetDecoder, version int16) (err error) {
 	a.Version = version
 	if a.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if a.PartitionErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionErrorCode", err)
 	}
 
 	if a.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -142,21 +142,21 @@ func (r *AddPartitionsToTxnResult) decode(pd packetDecoder, version int16) (err
 	r.Version = version
 	if r.Version >= 4 {
 		if r.TransactionalID, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "TransactionalID", err)
 		}
 	}
 
 	if r.Version >= 4 {
 		var numTopicResults int
 		if numTopicResults, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicResults", err)
 		}
 		if numTopicResults > 0 {
 			r.TopicResults = make([]AddPartitionsToTxnTopicResult_AddPartitionsToTxnResponse, numTopicResults)
 			for i := 0; i < numTopicResults; i++ {
 				var block AddPartitionsToTxnTopicResult_AddPartitionsToTxnResponse
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "TopicResults", i, err)
 				}
 				r.TopicResults[i] = block
 			}
@@ -165,7 +165,7 @@ func (r *AddPartitionsToTxnResult) decode(pd packetDecoder, version int16) (err
 
 	if r.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -228,26 +228,26 @@ func (r *AddPartitionsToTxnResponse) decode(pd packetDecoder, version int16) (er
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.Version >= 4 {
 		if r.ErrorCode, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "ErrorCode", err)
 		}
 	}
 
 	if r.Version >= 4 {
 		var numResultsByTransaction int
 		if numResultsByTransaction, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "ResultsByTransaction", err)
 		}
 		if numResultsByTransaction > 0 {
 			r.ResultsByTransaction = make([]AddPartitionsToTxnResult, numResultsByTransaction)
 			for i := 0; i < numResultsByTransaction; i++ {
 				var block AddPartitionsToTxnResult
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "ResultsByTransaction", i, err)
 				}
 				r.ResultsByTransaction[i] = block
 			}
@@ -257,14 +257,14 @@ func (r *AddPartitionsToTxnResponse) decode(pd packetDecoder, version int16) (er
 	if r.Version >= 0 && r.Version <= 3 {
 		var numResultsByTopicV3AndBelow int
 		if numResultsByTopicV3AndBelow, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "ResultsByTopicV3AndBelow", err)
 		}
 		if numResultsByTopicV3AndBelow > 0 {
 			r.ResultsByTopicV3AndBelow = make([]AddPartitionsToTxnTopicResult_AddPartitionsToTxnResponse, numResultsByTopicV3AndBelow)
 			for i := 0; i < numResultsByTopicV3AndBelow; i++ {
 				var block AddPartitionsToTxnTopicResult_AddPartitionsToTxnResponse
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "ResultsByTopicV3AndBelow", i, err)
 				}
 				r.ResultsByTopicV3AndBelow[i] = block
 			}
@@ -273,7 +273,7 @@ func (r *AddPartitionsToTxnResponse) decode(pd packetDecoder, version int16) (er
 
 	if r.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -299,8 +299,7 @@ func (r *AddPartitionsToTxnResponse) IsValidVersion() bool {
 }
 
 func (r *AddPartitionsToTxnResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *AddPartitionsToTxnResponse) throttleTime() time.Duration {
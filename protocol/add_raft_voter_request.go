@@ -34,19 +34,19 @@ func (l *Listener_AddRaftVoterRequest) encode(pe packetEncoder, version int16) (
 func (l *Listener_AddRaftVoterRequest) decode(pd packetDecoder, version int16) (err error) {
 	l.Version = version
 	if l.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if l.Host, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Host", err)
 	}
 
 	if l.Port, err = pd.getUint16(); err != nil {
-		return err
+		return wrapFieldError(pd, "Port", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -97,38 +97,38 @@ func (r *AddRaftVoterRequest) decode(pd packetDecoder, version int16) (err error
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ClusterID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClusterID", err)
 	}
 
 	if r.TimeoutMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "TimeoutMs", err)
 	}
 
 	if r.VoterID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "VoterID", err)
 	}
 
 	if r.VoterDirectoryID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "VoterDirectoryID", err)
 	}
 
 	var numListeners int
 	if numListeners, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Listeners", err)
 	}
 	if numListeners > 0 {
 		r.Listeners = make([]Listener_AddRaftVoterRequest, numListeners)
 		for i := 0; i < numListeners; i++ {
 			var block Listener_AddRaftVoterRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Listeners", i, err)
 			}
 			r.Listeners[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -150,6 +150,5 @@ func (r *AddRaftVoterRequest) IsValidVersion() bool {
 }
 
 func (r *AddRaftVoterRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
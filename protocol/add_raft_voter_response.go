@@ -32,19 +32,19 @@ func (r *AddRaftVoterResponse) decode(pd packetDecoder, version int16) (err erro
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -66,8 +66,7 @@ func (r *AddRaftVoterResponse) IsValidVersion() bool {
 }
 
 func (r *AddRaftVoterResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *AddRaftVoterResponse) throttleTime() time.Duration {
@@ -24,15 +24,15 @@ func (r *AllocateProducerIdsRequest) decode(pd packetDecoder, version int16) (er
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.BrokerID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "BrokerID", err)
 	}
 
 	if r.BrokerEpoch, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "BrokerEpoch", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -54,6 +54,5 @@ func (r *AllocateProducerIdsRequest) IsValidVersion() bool {
 }
 
 func (r *AllocateProducerIdsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
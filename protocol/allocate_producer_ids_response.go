@@ -34,23 +34,23 @@ func (r *AllocateProducerIdsResponse) decode(pd packetDecoder, version int16) (e
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ProducerIdStart, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProducerIdStart", err)
 	}
 
 	if r.ProducerIdLen, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProducerIdLen", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -72,8 +72,7 @@ func (r *AllocateProducerIdsResponse) IsValidVersion() bool {
 }
 
 func (r *AllocateProducerIdsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *AllocateProducerIdsResponse) throttleTime() time.Duration {
@@ -30,16 +30,16 @@ func (e *EntityData_AlterClientQuotasRequest) encode(pe packetEncoder, version i
 func (e *EntityData_AlterClientQuotasRequest) decode(pd packetDecoder, version int16) (err error) {
 	e.Version = version
 	if e.EntityType, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "EntityType", err)
 	}
 
 	if e.EntityName, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "EntityName", err)
 	}
 
 	if e.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -76,20 +76,20 @@ func (o *OpData) encode(pe packetEncoder, version int16) (err error) {
 func (o *OpData) decode(pd packetDecoder, version int16) (err error) {
 	o.Version = version
 	if o.Key, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Key", err)
 	}
 
 	if o.Value, err = pd.getFloat64(); err != nil {
-		return err
+		return wrapFieldError(pd, "Value", err)
 	}
 
 	if o.Remove, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "Remove", err)
 	}
 
 	if o.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -135,14 +135,14 @@ func (e *EntryData_AlterClientQuotasRequest) decode(pd packetDecoder, version in
 	e.Version = version
 	var numEntity int
 	if numEntity, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Entity", err)
 	}
 	if numEntity > 0 {
 		e.Entity = make([]EntityData_AlterClientQuotasRequest, numEntity)
 		for i := 0; i < numEntity; i++ {
 			var block EntityData_AlterClientQuotasRequest
 			if err := block.decode(pd, e.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Entity", i, err)
 			}
 			e.Entity[i] = block
 		}
@@ -150,14 +150,14 @@ func (e *EntryData_AlterClientQuotasRequest) decode(pd packetDecoder, version in
 
 	var numOps int
 	if numOps, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Ops", err)
 	}
 	if numOps > 0 {
 		e.Ops = make([]OpData, numOps)
 		for i := 0; i < numOps; i++ {
 			var block OpData
 			if err := block.decode(pd, e.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Ops", i, err)
 			}
 			e.Ops[i] = block
 		}
@@ -165,7 +165,7 @@ func (e *EntryData_AlterClientQuotasRequest) decode(pd packetDecoder, version in
 
 	if e.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -208,26 +208,26 @@ func (r *AlterClientQuotasRequest) decode(pd packetDecoder, version int16) (err
 	}
 	var numEntries int
 	if numEntries, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Entries", err)
 	}
 	if numEntries > 0 {
 		r.Entries = make([]EntryData_AlterClientQuotasRequest, numEntries)
 		for i := 0; i < numEntries; i++ {
 			var block EntryData_AlterClientQuotasRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Entries", i, err)
 			}
 			r.Entries[i] = block
 		}
 	}
 
 	if r.ValidateOnly, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "ValidateOnly", err)
 	}
 
 	if r.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -253,6 +253,5 @@ func (r *AlterClientQuotasRequest) IsValidVersion() bool {
 }
 
 func (r *AlterClientQuotasRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -32,16 +32,16 @@ func (e *EntityData_AlterClientQuotasResponse) encode(pe packetEncoder, version
 func (e *EntityData_AlterClientQuotasResponse) decode(pd packetDecoder, version int16) (err error) {
 	e.Version = version
 	if e.EntityType, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "EntityType", err)
 	}
 
 	if e.EntityName, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "EntityName", err)
 	}
 
 	if e.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -85,23 +85,23 @@ func (e *EntryData_AlterClientQuotasResponse) encode(pe packetEncoder, version i
 func (e *EntryData_AlterClientQuotasResponse) decode(pd packetDecoder, version int16) (err error) {
 	e.Version = version
 	if e.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if e.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	var numEntity int
 	if numEntity, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Entity", err)
 	}
 	if numEntity > 0 {
 		e.Entity = make([]EntityData_AlterClientQuotasResponse, numEntity)
 		for i := 0; i < numEntity; i++ {
 			var block EntityData_AlterClientQuotasResponse
 			if err := block.decode(pd, e.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Entity", i, err)
 			}
 			e.Entity[i] = block
 		}
@@ -109,7 +109,7 @@ func (e *EntryData_AlterClientQuotasResponse) decode(pd packetDecoder, version i
 
 	if e.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -151,19 +151,19 @@ func (r *AlterClientQuotasResponse) decode(pd packetDecoder, version int16) (err
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	var numEntries int
 	if numEntries, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Entries", err)
 	}
 	if numEntries > 0 {
 		r.Entries = make([]EntryData_AlterClientQuotasResponse, numEntries)
 		for i := 0; i < numEntries; i++ {
 			var block EntryData_AlterClientQuotasResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Entries", i, err)
 			}
 			r.Entries[i] = block
 		}
@@ -171,7 +171,7 @@ func (r *AlterClientQuotasResponse) decode(pd packetDecoder, version int16) (err
 
 	if r.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -197,8 +197,7 @@ func (r *AlterClientQuotasResponse) IsValidVersion() bool {
 }
 
 func (r *AlterClientQuotasResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *AlterClientQuotasResponse) throttleTime() time.Duration {
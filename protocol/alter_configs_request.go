@@ -30,16 +30,16 @@ func (c *AlterableConfig_AlterConfigsRequest) encode(pe packetEncoder, version i
 func (c *AlterableConfig_AlterConfigsRequest) decode(pd packetDecoder, version int16) (err error) {
 	c.Version = version
 	if c.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if c.Value, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Value", err)
 	}
 
 	if c.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -83,23 +83,23 @@ func (r *AlterConfigsResource_AlterConfigsRequest) encode(pe packetEncoder, vers
 func (r *AlterConfigsResource_AlterConfigsRequest) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.ResourceType, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResourceType", err)
 	}
 
 	if r.ResourceName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResourceName", err)
 	}
 
 	var numConfigs int
 	if numConfigs, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Configs", err)
 	}
 	if numConfigs > 0 {
 		r.Configs = make([]AlterableConfig_AlterConfigsRequest, numConfigs)
 		for i := 0; i < numConfigs; i++ {
 			var block AlterableConfig_AlterConfigsRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Configs", i, err)
 			}
 			r.Configs[i] = block
 		}
@@ -107,7 +107,7 @@ func (r *AlterConfigsResource_AlterConfigsRequest) decode(pd packetDecoder, vers
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -150,26 +150,26 @@ func (r *AlterConfigsRequest) decode(pd packetDecoder, version int16) (err error
 	}
 	var numResources int
 	if numResources, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Resources", err)
 	}
 	if numResources > 0 {
 		r.Resources = make([]AlterConfigsResource_AlterConfigsRequest, numResources)
 		for i := 0; i < numResources; i++ {
 			var block AlterConfigsResource_AlterConfigsRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Resources", i, err)
 			}
 			r.Resources[i] = block
 		}
 	}
 
 	if r.ValidateOnly, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "ValidateOnly", err)
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -195,6 +195,5 @@ func (r *AlterConfigsRequest) IsValidVersion() bool {
 }
 
 func (r *AlterConfigsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
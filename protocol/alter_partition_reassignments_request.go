@@ -26,15 +26,15 @@ func (p *ReassignablePartition) encode(pe packetEncoder, version int16) (err err
 func (p *ReassignablePartition) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.Replicas, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Replicas", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -71,26 +71,26 @@ func (t *ReassignableTopic) encode(pe packetEncoder, version int16) (err error)
 func (t *ReassignableTopic) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]ReassignablePartition, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block ReassignablePartition
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -125,26 +125,26 @@ func (r *AlterPartitionReassignmentsRequest) decode(pd packetDecoder, version in
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.TimeoutMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "TimeoutMs", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]ReassignableTopic, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block ReassignableTopic
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -166,6 +166,5 @@ func (r *AlterPartitionReassignmentsRequest) IsValidVersion() bool {
 }
 
 func (r *AlterPartitionReassignmentsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -32,19 +32,19 @@ func (p *ReassignablePartitionResponse) encode(pe packetEncoder, version int16)
 func (p *ReassignablePartitionResponse) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -81,26 +81,26 @@ func (r *ReassignableTopicResponse) encode(pe packetEncoder, version int16) (err
 func (r *ReassignableTopicResponse) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		r.Partitions = make([]ReassignablePartitionResponse, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block ReassignablePartitionResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			r.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -145,34 +145,34 @@ func (r *AlterPartitionReassignmentsResponse) decode(pd packetDecoder, version i
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	var numResponses int
 	if numResponses, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Responses", err)
 	}
 	if numResponses > 0 {
 		r.Responses = make([]ReassignableTopicResponse, numResponses)
 		for i := 0; i < numResponses; i++ {
 			var block ReassignableTopicResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Responses", i, err)
 			}
 			r.Responses[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -194,8 +194,7 @@ func (r *AlterPartitionReassignmentsResponse) IsValidVersion() bool {
 }
 
 func (r *AlterPartitionReassignmentsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *AlterPartitionReassignmentsResponse) throttleTime() time.Duration {
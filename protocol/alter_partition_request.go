@@ -31,18 +31,18 @@ func (n *BrokerState) decode(pd packetDecoder, version int16) (err error) {
 	n.Version = version
 	if n.Version >= 3 {
 		if n.BrokerID, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "BrokerID", err)
 		}
 	}
 
 	if n.Version >= 3 {
 		if n.BrokerEpoch, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "BrokerEpoch", err)
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -101,30 +101,30 @@ func (p *PartitionData_AlterPartitionRequest) encode(pe packetEncoder, version i
 func (p *PartitionData_AlterPartitionRequest) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.LeaderEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderEpoch", err)
 	}
 
 	if p.Version >= 0 && p.Version <= 2 {
 		if p.NewIsr, err = pd.getInt32Array(); err != nil {
-			return err
+			return wrapFieldError(pd, "NewIsr", err)
 		}
 	}
 
 	if p.Version >= 3 {
 		var numNewIsrWithEpochs int
 		if numNewIsrWithEpochs, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "NewIsrWithEpochs", err)
 		}
 		if numNewIsrWithEpochs > 0 {
 			p.NewIsrWithEpochs = make([]BrokerState, numNewIsrWithEpochs)
 			for i := 0; i < numNewIsrWithEpochs; i++ {
 				var block BrokerState
 				if err := block.decode(pd, p.Version); err != nil {
-					return err
+					return wrapElementError(pd, "NewIsrWithEpochs", i, err)
 				}
 				p.NewIsrWithEpochs[i] = block
 			}
@@ -133,16 +133,16 @@ func (p *PartitionData_AlterPartitionRequest) decode(pd packetDecoder, version i
 
 	if p.Version >= 1 {
 		if p.LeaderRecoveryState, err = pd.getInt8(); err != nil {
-			return err
+			return wrapFieldError(pd, "LeaderRecoveryState", err)
 		}
 	}
 
 	if p.PartitionEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionEpoch", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -190,33 +190,33 @@ func (t *TopicData_AlterPartitionRequest) decode(pd packetDecoder, version int16
 	t.Version = version
 	if t.Version >= 0 && t.Version <= 1 {
 		if t.TopicName, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicName", err)
 		}
 	}
 
 	if t.Version >= 2 {
 		if t.TopicID, err = pd.getUUID(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicID", err)
 		}
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]PartitionData_AlterPartitionRequest, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionData_AlterPartitionRequest
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -255,30 +255,30 @@ func (r *AlterPartitionRequest) decode(pd packetDecoder, version int16) (err err
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.BrokerID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "BrokerID", err)
 	}
 
 	if r.BrokerEpoch, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "BrokerEpoch", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]TopicData_AlterPartitionRequest, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block TopicData_AlterPartitionRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -300,6 +300,5 @@ func (r *AlterPartitionRequest) IsValidVersion() bool {
 }
 
 func (r *AlterPartitionRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
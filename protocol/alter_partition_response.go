@@ -53,37 +53,37 @@ func (p *PartitionData_AlterPartitionResponse) encode(pe packetEncoder, version
 func (p *PartitionData_AlterPartitionResponse) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.LeaderID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderID", err)
 	}
 
 	if p.LeaderEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderEpoch", err)
 	}
 
 	if p.Isr, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Isr", err)
 	}
 
 	if p.Version >= 1 {
 		if p.LeaderRecoveryState, err = pd.getInt8(); err != nil {
-			return err
+			return wrapFieldError(pd, "LeaderRecoveryState", err)
 		}
 	}
 
 	if p.PartitionEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionEpoch", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -131,33 +131,33 @@ func (t *TopicData_AlterPartitionResponse) decode(pd packetDecoder, version int1
 	t.Version = version
 	if t.Version >= 0 && t.Version <= 1 {
 		if t.TopicName, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicName", err)
 		}
 	}
 
 	if t.Version >= 2 {
 		if t.TopicID, err = pd.getUUID(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicID", err)
 		}
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]PartitionData_AlterPartitionResponse, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionData_AlterPartitionResponse
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -196,30 +196,30 @@ func (r *AlterPartitionResponse) decode(pd packetDecoder, version int16) (err er
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]TopicData_AlterPartitionResponse, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block TopicData_AlterPartitionResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -241,8 +241,7 @@ func (r *AlterPartitionResponse) IsValidVersion() bool {
 }
 
 func (r *AlterPartitionResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *AlterPartitionResponse) throttleTime() time.Duration {
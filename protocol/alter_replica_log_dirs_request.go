@@ -30,16 +30,16 @@ func (t *AlterReplicaLogDirTopic) encode(pe packetEncoder, version int16) (err e
 func (t *AlterReplicaLogDirTopic) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if t.Partitions, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 
 	if t.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -79,19 +79,19 @@ func (d *AlterReplicaLogDir) encode(pe packetEncoder, version int16) (err error)
 func (d *AlterReplicaLogDir) decode(pd packetDecoder, version int16) (err error) {
 	d.Version = version
 	if d.Path, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Path", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		d.Topics = make([]AlterReplicaLogDirTopic, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block AlterReplicaLogDirTopic
 			if err := block.decode(pd, d.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			d.Topics[i] = block
 		}
@@ -99,7 +99,7 @@ func (d *AlterReplicaLogDir) decode(pd packetDecoder, version int16) (err error)
 
 	if d.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -138,14 +138,14 @@ func (r *AlterReplicaLogDirsRequest) decode(pd packetDecoder, version int16) (er
 	}
 	var numDirs int
 	if numDirs, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Dirs", err)
 	}
 	if numDirs > 0 {
 		r.Dirs = make([]AlterReplicaLogDir, numDirs)
 		for i := 0; i < numDirs; i++ {
 			var block AlterReplicaLogDir
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Dirs", i, err)
 			}
 			r.Dirs[i] = block
 		}
@@ -153,7 +153,7 @@ func (r *AlterReplicaLogDirsRequest) decode(pd packetDecoder, version int16) (er
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -179,6 +179,5 @@ func (r *AlterReplicaLogDirsRequest) IsValidVersion() bool {
 }
 
 func (r *AlterReplicaLogDirsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
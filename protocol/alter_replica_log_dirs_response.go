@@ -28,16 +28,16 @@ func (p *AlterReplicaLogDirPartitionResult) encode(pe packetEncoder, version int
 func (p *AlterReplicaLogDirPartitionResult) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -77,19 +77,19 @@ func (r *AlterReplicaLogDirTopicResult) encode(pe packetEncoder, version int16)
 func (r *AlterReplicaLogDirTopicResult) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.TopicName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicName", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		r.Partitions = make([]AlterReplicaLogDirPartitionResult, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block AlterReplicaLogDirPartitionResult
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			r.Partitions[i] = block
 		}
@@ -97,7 +97,7 @@ func (r *AlterReplicaLogDirTopicResult) decode(pd packetDecoder, version int16)
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -139,19 +139,19 @@ func (r *AlterReplicaLogDirsResponse) decode(pd packetDecoder, version int16) (e
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	var numResults int
 	if numResults, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Results", err)
 	}
 	if numResults > 0 {
 		r.Results = make([]AlterReplicaLogDirTopicResult, numResults)
 		for i := 0; i < numResults; i++ {
 			var block AlterReplicaLogDirTopicResult
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Results", i, err)
 			}
 			r.Results[i] = block
 		}
@@ -159,7 +159,7 @@ func (r *AlterReplicaLogDirsResponse) decode(pd packetDecoder, version int16) (e
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -185,8 +185,7 @@ func (r *AlterReplicaLogDirsResponse) IsValidVersion() bool {
 }
 
 func (r *AlterReplicaLogDirsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *AlterReplicaLogDirsResponse) throttleTime() time.Duration {
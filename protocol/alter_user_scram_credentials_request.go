@@ -26,15 +26,15 @@ func (d *ScramCredentialDeletion) encode(pe packetEncoder, version int16) (err e
 func (d *ScramCredentialDeletion) decode(pd packetDecoder, version int16) (err error) {
 	d.Version = version
 	if d.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if d.Mechanism, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "Mechanism", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -50,9 +50,9 @@ type ScramCredentialUpsertion struct {
 	// Iterations contains the number of iterations.
 	Iterations int32
 	// Salt contains a A random salt generated by the client.
-	Salt []byte
+	Salt []byte `sensitive:"true"`
 	// SaltedPassword contains the salted password.
-	SaltedPassword []byte
+	SaltedPassword []byte `sensitive:"true"`
 }
 
 func (u *ScramCredentialUpsertion) encode(pe packetEncoder, version int16) (err error) {
@@ -80,27 +80,27 @@ func (u *ScramCredentialUpsertion) encode(pe packetEncoder, version int16) (err
 func (u *ScramCredentialUpsertion) decode(pd packetDecoder, version int16) (err error) {
 	u.Version = version
 	if u.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if u.Mechanism, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "Mechanism", err)
 	}
 
 	if u.Iterations, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Iterations", err)
 	}
 
 	if u.Salt, err = pd.getBytes(); err != nil {
-		return err
+		return wrapFieldError(pd, "Salt", err)
 	}
 
 	if u.SaltedPassword, err = pd.getBytes(); err != nil {
-		return err
+		return wrapFieldError(pd, "SaltedPassword", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -143,14 +143,14 @@ func (r *AlterUserScramCredentialsRequest) decode(pd packetDecoder, version int1
 	pd = FlexibleDecoderFrom(pd)
 	var numDeletions int
 	if numDeletions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Deletions", err)
 	}
 	if numDeletions > 0 {
 		r.Deletions = make([]ScramCredentialDeletion, numDeletions)
 		for i := 0; i < numDeletions; i++ {
 			var block ScramCredentialDeletion
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Deletions", i, err)
 			}
 			r.Deletions[i] = block
 		}
@@ -158,21 +158,21 @@ func (r *AlterUserScramCredentialsRequest) decode(pd packetDecoder, version int1
 
 	var numUpsertions int
 	if numUpsertions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Upsertions", err)
 	}
 	if numUpsertions > 0 {
 		r.Upsertions = make([]ScramCredentialUpsertion, numUpsertions)
 		for i := 0; i < numUpsertions; i++ {
 			var block ScramCredentialUpsertion
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Upsertions", i, err)
 			}
 			r.Upsertions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -194,6 +194,5 @@ func (r *AlterUserScramCredentialsRequest) IsValidVersion() bool {
 }
 
 func (r *AlterUserScramCredentialsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
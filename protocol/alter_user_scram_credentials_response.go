@@ -34,19 +34,19 @@ func (r *AlterUserScramCredentialsResult) encode(pe packetEncoder, version int16
 func (r *AlterUserScramCredentialsResult) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.User, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "User", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -81,26 +81,26 @@ func (r *AlterUserScramCredentialsResponse) decode(pd packetDecoder, version int
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	var numResults int
 	if numResults, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Results", err)
 	}
 	if numResults > 0 {
 		r.Results = make([]AlterUserScramCredentialsResult, numResults)
 		for i := 0; i < numResults; i++ {
 			var block AlterUserScramCredentialsResult
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Results", i, err)
 			}
 			r.Results[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -122,8 +122,7 @@ func (r *AlterUserScramCredentialsResponse) IsValidVersion() bool {
 }
 
 func (r *AlterUserScramCredentialsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *AlterUserScramCredentialsResponse) throttleTime() time.Duration {
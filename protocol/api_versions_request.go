@@ -39,19 +39,19 @@ func (r *ApiVersionsRequest) decode(pd packetDecoder, version int16) (err error)
 	}
 	if r.Version >= 3 {
 		if r.ClientSoftwareName, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "ClientSoftwareName", err)
 		}
 	}
 
 	if r.Version >= 3 {
 		if r.ClientSoftwareVersion, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "ClientSoftwareVersion", err)
 		}
 	}
 
 	if r.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -77,6 +77,5 @@ func (r *ApiVersionsRequest) IsValidVersion() bool {
 }
 
 func (r *ApiVersionsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -32,20 +32,20 @@ func (a *ApiVersion) encode(pe packetEncoder, version int16) (err error) {
 func (a *ApiVersion) decode(pd packetDecoder, version int16) (err error) {
 	a.Version = version
 	if a.ApiKey, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ApiKey", err)
 	}
 
 	if a.MinVersion, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "MinVersion", err)
 	}
 
 	if a.MaxVersion, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "MaxVersion", err)
 	}
 
 	if a.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -86,19 +86,19 @@ func (s *SupportedFeatureKey) decode(pd packetDecoder, version int16) (err error
 	s.Version = version
 	if s.Version >= 3 {
 		if s.Name, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Name", err)
 		}
 	}
 
 	if s.Version >= 3 {
 		if s.MinVersion, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "MinVersion", err)
 		}
 	}
 
 	if s.Version >= 3 {
 		if s.MaxVersion, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "MaxVersion", err)
 		}
 	}
 
@@ -140,19 +140,19 @@ func (f *FinalizedFeatureKey) decode(pd packetDecoder, version int16) (err error
 	f.Version = version
 	if f.Version >= 3 {
 		if f.Name, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Name", err)
 		}
 	}
 
 	if f.Version >= 3 {
 		if f.MaxVersionLevel, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "MaxVersionLevel", err)
 		}
 	}
 
 	if f.Version >= 3 {
 		if f.MinVersionLevel, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "MinVersionLevel", err)
 		}
 	}
 
@@ -209,19 +209,19 @@ func (r *ApiVersionsResponse) decode(pd packetDecoder, version int16) (err error
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	var numApiKeys int
 	if numApiKeys, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "ApiKeys", err)
 	}
 	if numApiKeys > 0 {
 		r.ApiKeys = make([]ApiVersion, numApiKeys)
 		for i := 0; i < numApiKeys; i++ {
 			var block ApiVersion
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "ApiKeys", i, err)
 			}
 			r.ApiKeys[i] = block
 		}
@@ -229,13 +229,13 @@ func (r *ApiVersionsResponse) decode(pd packetDecoder, version int16) (err error
 
 	if r.Version >= 1 {
 		if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "ThrottleTimeMs", err)
 		}
 	}
 
 	if r.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -258,8 +258,7 @@ func (r *ApiVersionsResponse) IsValidVersion() bool {
 }
 
 func (r *ApiVersionsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *ApiVersionsResponse) throttleTime() time.Duration {
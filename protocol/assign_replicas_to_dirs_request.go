@@ -22,11 +22,11 @@ func (p *PartitionData_AssignReplicasToDirsRequest) encode(pe packetEncoder, ver
 func (p *PartitionData_AssignReplicasToDirsRequest) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -63,26 +63,26 @@ func (t *TopicData_AssignReplicasToDirsRequest) encode(pe packetEncoder, version
 func (t *TopicData_AssignReplicasToDirsRequest) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.TopicID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicID", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]PartitionData_AssignReplicasToDirsRequest, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionData_AssignReplicasToDirsRequest
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -119,26 +119,26 @@ func (d *DirectoryData_AssignReplicasToDirsRequest) encode(pe packetEncoder, ver
 func (d *DirectoryData_AssignReplicasToDirsRequest) decode(pd packetDecoder, version int16) (err error) {
 	d.Version = version
 	if d.ID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "ID", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		d.Topics = make([]TopicData_AssignReplicasToDirsRequest, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block TopicData_AssignReplicasToDirsRequest
 			if err := block.decode(pd, d.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			d.Topics[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -177,30 +177,30 @@ func (r *AssignReplicasToDirsRequest) decode(pd packetDecoder, version int16) (e
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.BrokerID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "BrokerID", err)
 	}
 
 	if r.BrokerEpoch, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "BrokerEpoch", err)
 	}
 
 	var numDirectories int
 	if numDirectories, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Directories", err)
 	}
 	if numDirectories > 0 {
 		r.Directories = make([]DirectoryData_AssignReplicasToDirsRequest, numDirectories)
 		for i := 0; i < numDirectories; i++ {
 			var block DirectoryData_AssignReplicasToDirsRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Directories", i, err)
 			}
 			r.Directories[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -222,6 +222,5 @@ func (r *AssignReplicasToDirsRequest) IsValidVersion() bool {
 }
 
 func (r *AssignReplicasToDirsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -29,15 +29,15 @@ func (p *PartitionData_AssignReplicasToDirsResponse) encode(pe packetEncoder, ve
 func (p *PartitionData_AssignReplicasToDirsResponse) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -74,26 +74,26 @@ func (t *TopicData_AssignReplicasToDirsResponse) encode(pe packetEncoder, versio
 func (t *TopicData_AssignReplicasToDirsResponse) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.TopicID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicID", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]PartitionData_AssignReplicasToDirsResponse, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionData_AssignReplicasToDirsResponse
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -130,26 +130,26 @@ func (d *DirectoryData_AssignReplicasToDirsResponse) encode(pe packetEncoder, ve
 func (d *DirectoryData_AssignReplicasToDirsResponse) decode(pd packetDecoder, version int16) (err error) {
 	d.Version = version
 	if d.ID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "ID", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		d.Topics = make([]TopicData_AssignReplicasToDirsResponse, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block TopicData_AssignReplicasToDirsResponse
 			if err := block.decode(pd, d.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			d.Topics[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -188,30 +188,30 @@ func (r *AssignReplicasToDirsResponse) decode(pd packetDecoder, version int16) (
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	var numDirectories int
 	if numDirectories, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Directories", err)
 	}
 	if numDirectories > 0 {
 		r.Directories = make([]DirectoryData_AssignReplicasToDirsResponse, numDirectories)
 		for i := 0; i < numDirectories; i++ {
 			var block DirectoryData_AssignReplicasToDirsResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Directories", i, err)
 			}
 			r.Directories[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -233,8 +233,7 @@ func (r *AssignReplicasToDirsResponse) IsValidVersion() bool {
 }
 
 func (r *AssignReplicasToDirsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *AssignReplicasToDirsResponse) throttleTime() time.Duration {
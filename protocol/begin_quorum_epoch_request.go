@@ -40,26 +40,26 @@ func (p *PartitionData_BeginQuorumEpochRequest) encode(pe packetEncoder, version
 func (p *PartitionData_BeginQuorumEpochRequest) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.Version >= 1 {
 		if p.VoterDirectoryID, err = pd.getUUID(); err != nil {
-			return err
+			return wrapFieldError(pd, "VoterDirectoryID", err)
 		}
 	}
 
 	if p.LeaderID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderID", err)
 	}
 
 	if p.LeaderEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderEpoch", err)
 	}
 
 	if p.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -99,19 +99,19 @@ func (t *TopicData_BeginQuorumEpochRequest) encode(pe packetEncoder, version int
 func (t *TopicData_BeginQuorumEpochRequest) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.TopicName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicName", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]PartitionData_BeginQuorumEpochRequest, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionData_BeginQuorumEpochRequest
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
@@ -119,7 +119,7 @@ func (t *TopicData_BeginQuorumEpochRequest) decode(pd packetDecoder, version int
 
 	if t.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -165,25 +165,25 @@ func (l *LeaderEndpoint_BeginQuorumEpochRequest) decode(pd packetDecoder, versio
 	l.Version = version
 	if l.Version >= 1 {
 		if l.Name, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Name", err)
 		}
 	}
 
 	if l.Version >= 1 {
 		if l.Host, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Host", err)
 		}
 	}
 
 	if l.Version >= 1 {
 		if l.Port, err = pd.getUint16(); err != nil {
-			return err
+			return wrapFieldError(pd, "Port", err)
 		}
 	}
 
 	if l.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -246,25 +246,25 @@ func (r *BeginQuorumEpochRequest) decode(pd packetDecoder, version int16) (err e
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ClusterID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClusterID", err)
 	}
 
 	if r.Version >= 1 {
 		if r.VoterID, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "VoterID", err)
 		}
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]TopicData_BeginQuorumEpochRequest, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block TopicData_BeginQuorumEpochRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -273,14 +273,14 @@ func (r *BeginQuorumEpochRequest) decode(pd packetDecoder, version int16) (err e
 	if r.Version >= 1 {
 		var numLeaderEndpoints int
 		if numLeaderEndpoints, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "LeaderEndpoints", err)
 		}
 		if numLeaderEndpoints > 0 {
 			r.LeaderEndpoints = make([]LeaderEndpoint_BeginQuorumEpochRequest, numLeaderEndpoints)
 			for i := 0; i < numLeaderEndpoints; i++ {
 				var block LeaderEndpoint_BeginQuorumEpochRequest
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "LeaderEndpoints", i, err)
 				}
 				r.LeaderEndpoints[i] = block
 			}
@@ -289,7 +289,7 @@ func (r *BeginQuorumEpochRequest) decode(pd packetDecoder, version int16) (err e
 
 	if r.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -315,6 +315,5 @@ func (r *BeginQuorumEpochRequest) IsValidVersion() bool {
 }
 
 func (r *BeginQuorumEpochRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
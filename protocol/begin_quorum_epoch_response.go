@@ -34,24 +34,24 @@ func (p *PartitionData_BeginQuorumEpochResponse) encode(pe packetEncoder, versio
 func (p *PartitionData_BeginQuorumEpochResponse) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.LeaderID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderID", err)
 	}
 
 	if p.LeaderEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderEpoch", err)
 	}
 
 	if p.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -91,19 +91,19 @@ func (t *TopicData_BeginQuorumEpochResponse) encode(pe packetEncoder, version in
 func (t *TopicData_BeginQuorumEpochResponse) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.TopicName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicName", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]PartitionData_BeginQuorumEpochResponse, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionData_BeginQuorumEpochResponse
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
@@ -111,7 +111,7 @@ func (t *TopicData_BeginQuorumEpochResponse) decode(pd packetDecoder, version in
 
 	if t.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -152,19 +152,19 @@ func (n *NodeEndpoint_BeginQuorumEpochResponse) decode(pd packetDecoder, version
 	n.Version = version
 	if n.Version >= 1 {
 		if n.NodeID, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "NodeID", err)
 		}
 	}
 
 	if n.Version >= 1 {
 		if n.Host, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Host", err)
 		}
 	}
 
 	if n.Version >= 1 {
 		if n.Port, err = pd.getUint16(); err != nil {
-			return err
+			return wrapFieldError(pd, "Port", err)
 		}
 	}
 
@@ -209,19 +209,19 @@ func (r *BeginQuorumEpochResponse) decode(pd packetDecoder, version int16) (err
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]TopicData_BeginQuorumEpochResponse, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block TopicData_BeginQuorumEpochResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -229,7 +229,7 @@ func (r *BeginQuorumEpochResponse) decode(pd packetDecoder, version int16) (err
 
 	if r.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -255,6 +255,5 @@ func (r *BeginQuorumEpochResponse) IsValidVersion() bool {
 }
 
 func (r *BeginQuorumEpochResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
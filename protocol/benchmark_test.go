@@ -0,0 +1,186 @@
+package protocol
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// benchmarkMessages are representative requests, used to compare changes to the wire layer.
+func benchmarkMessages() []struct {
+	name    string
+	version int16
+	msg     message
+	new     func() message
+} {
+	clusterID := "test-cluster"
+	topic := "test-topic"
+
+	return []struct {
+		name    string
+		version int16
+		msg     message
+		new     func() message
+	}{
+		{
+			name:    "BeginQuorumEpoch",
+			version: 1,
+			msg: &BeginQuorumEpochRequest{
+				Version:   1,
+				ClusterID: &clusterID,
+				VoterID:   1,
+				Topics: []TopicData_BeginQuorumEpochRequest{{
+					Version:    1,
+					TopicName:  "__cluster_metadata",
+					Partitions: []PartitionData_BeginQuorumEpochRequest{{Version: 1, VoterDirectoryID: uuid.New(), LeaderID: 1, LeaderEpoch: 5}},
+				}},
+				LeaderEndpoints: []LeaderEndpoint_BeginQuorumEpochRequest{{Version: 1, Name: "CONTROLLER", Host: "localhost", Port: 9093}},
+			},
+			new: func() message { return &BeginQuorumEpochRequest{Version: 1} },
+		},
+		{
+			name:    "Metadata",
+			version: 12,
+			msg: &MetadataRequest{
+				Version:                12,
+				Topics:                 []MetadataRequestTopic{{Version: 12, Name: &topic}},
+				AllowAutoTopicCreation: true,
+			},
+			new: func() message { return &MetadataRequest{Version: 12} },
+		},
+		{
+			name:    "Produce",
+			version: 9,
+			msg: &ProduceRequest{
+				Version:   9,
+				Acks:      -1,
+				TimeoutMs: 30000,
+				TopicData: []TopicProduceData{{
+					Version:       9,
+					Name:          topic,
+					PartitionData: []PartitionProduceData{{Version: 9, Records: Records{Batches: []RecordBatch{testRecordBatch()}}}},
+				}},
+			},
+			new: func() message { return &ProduceRequest{Version: 9} },
+		},
+	}
+}
+
+func BenchmarkEncode(b *testing.B) {
+	for _, bm := range benchmarkMessages() {
+		b.Run(bm.name, func(b *testing.B) {
+			var prepEnc prepEncoder
+			if err := bm.msg.encode(&prepEnc); err != nil {
+				b.Fatal(err)
+			}
+			// the buffer is reused, so only the allocations of the encoding itself are reported
+			buf := make([]byte, prepEnc.length)
+
+			b.ReportAllocs()
+			b.SetBytes(int64(len(buf)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				realEnc := realEncoder{raw: buf}
+				if err := bm.msg.encode(&realEnc); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkEncodeAlloc measures Encode, which sizes and allocates a new buffer for every message.
+func BenchmarkEncodeAlloc(b *testing.B) {
+	for _, bm := range benchmarkMessages() {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := Encode(bm.msg); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkAppendEncode measures encoding into buffers borrowed from the pool, the way responses are written.
+func BenchmarkAppendEncode(b *testing.B) {
+	for _, bm := range benchmarkMessages() {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				bufp := GetBuffer()
+				buf, err := AppendEncode(*bufp, bm.msg)
+				if err != nil {
+					b.Fatal(err)
+				}
+				*bufp = buf
+				PutBuffer(bufp)
+			}
+		})
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	for _, bm := range benchmarkMessages() {
+		b.Run(bm.name, func(b *testing.B) {
+			buf, err := Encode(bm.msg)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			b.SetBytes(int64(len(buf)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := VersionedDecode(buf, bm.new(), bm.version); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// mediumMetadataResponse describes a cluster of 3 brokers with 10 topics of 6 partitions each.
+func mediumMetadataResponse(version int16) *MetadataResponse {
+	clusterID := "test-cluster"
+	resp := &MetadataResponse{Version: version, ClusterID: &clusterID, ControllerID: 1}
+	for i := int32(1); i <= 3; i++ {
+		resp.Brokers = append(resp.Brokers, MetadataResponseBroker{Version: version, NodeID: i, Host: "broker.example.com", Port: 9092})
+	}
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("topic-%d", i)
+		topic := MetadataResponseTopic{Version: version, Name: &name, TopicID: uuid.New()}
+		for p := int32(0); p < 6; p++ {
+			topic.Partitions = append(topic.Partitions, MetadataResponsePartition{
+				Version:         version,
+				PartitionIndex:  p,
+				LeaderID:        p%3 + 1,
+				ReplicaNodes:    []int32{1, 2, 3},
+				IsrNodes:        []int32{1, 2, 3},
+				OfflineReplicas: []int32{},
+			})
+		}
+		resp.Topics = append(resp.Topics, topic)
+	}
+
+	return resp
+}
+
+// BenchmarkEncode_MetadataResponse measures Encode for a medium-sized response, for which sizing the response first
+// should leave the buffer of the response as the only allocation.
+func BenchmarkEncode_MetadataResponse(b *testing.B) {
+	for _, version := range []int16{8, 12} {
+		b.Run(fmt.Sprintf("v%d", version), func(b *testing.B) {
+			resp := mediumMetadataResponse(version)
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := Encode(resp); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
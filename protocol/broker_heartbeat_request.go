@@ -40,27 +40,27 @@ func (r *BrokerHeartbeatRequest) decode(pd packetDecoder, version int16) (err er
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.BrokerID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "BrokerID", err)
 	}
 
 	if r.BrokerEpoch, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "BrokerEpoch", err)
 	}
 
 	if r.CurrentMetadataOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "CurrentMetadataOffset", err)
 	}
 
 	if r.WantFence, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "WantFence", err)
 	}
 
 	if r.WantShutDown, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "WantShutDown", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -82,6 +82,5 @@ func (r *BrokerHeartbeatRequest) IsValidVersion() bool {
 }
 
 func (r *BrokerHeartbeatRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
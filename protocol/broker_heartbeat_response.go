@@ -38,27 +38,27 @@ func (r *BrokerHeartbeatResponse) decode(pd packetDecoder, version int16) (err e
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.IsCaughtUp, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "IsCaughtUp", err)
 	}
 
 	if r.IsFenced, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "IsFenced", err)
 	}
 
 	if r.ShouldShutDown, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "ShouldShutDown", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -80,8 +80,7 @@ func (r *BrokerHeartbeatResponse) IsValidVersion() bool {
 }
 
 func (r *BrokerHeartbeatResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *BrokerHeartbeatResponse) throttleTime() time.Duration {
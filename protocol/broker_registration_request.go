@@ -38,23 +38,23 @@ func (l *Listener_BrokerRegistrationRequest) encode(pe packetEncoder, version in
 func (l *Listener_BrokerRegistrationRequest) decode(pd packetDecoder, version int16) (err error) {
 	l.Version = version
 	if l.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if l.Host, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Host", err)
 	}
 
 	if l.Port, err = pd.getUint16(); err != nil {
-		return err
+		return wrapFieldError(pd, "Port", err)
 	}
 
 	if l.SecurityProtocol, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "SecurityProtocol", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -88,19 +88,19 @@ func (f *Feature_BrokerRegistrationRequest) encode(pe packetEncoder, version int
 func (f *Feature_BrokerRegistrationRequest) decode(pd packetDecoder, version int16) (err error) {
 	f.Version = version
 	if f.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if f.MinSupportedVersion, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "MinSupportedVersion", err)
 	}
 
 	if f.MaxSupportedVersion, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "MaxSupportedVersion", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -184,27 +184,27 @@ func (r *BrokerRegistrationRequest) decode(pd packetDecoder, version int16) (err
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.BrokerID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "BrokerID", err)
 	}
 
 	if r.ClusterID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClusterID", err)
 	}
 
 	if r.IncarnationID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "IncarnationID", err)
 	}
 
 	var numListeners int
 	if numListeners, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Listeners", err)
 	}
 	if numListeners > 0 {
 		r.Listeners = make([]Listener_BrokerRegistrationRequest, numListeners)
 		for i := 0; i < numListeners; i++ {
 			var block Listener_BrokerRegistrationRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Listeners", i, err)
 			}
 			r.Listeners[i] = block
 		}
@@ -212,43 +212,43 @@ func (r *BrokerRegistrationRequest) decode(pd packetDecoder, version int16) (err
 
 	var numFeatures int
 	if numFeatures, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Features", err)
 	}
 	if numFeatures > 0 {
 		r.Features = make([]Feature_BrokerRegistrationRequest, numFeatures)
 		for i := 0; i < numFeatures; i++ {
 			var block Feature_BrokerRegistrationRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Features", i, err)
 			}
 			r.Features[i] = block
 		}
 	}
 
 	if r.Rack, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Rack", err)
 	}
 
 	if r.Version >= 1 {
 		if r.IsMigratingZkBroker, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "IsMigratingZkBroker", err)
 		}
 	}
 
 	if r.Version >= 2 {
 		if r.LogDirs, err = pd.getUUIDArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "LogDirs", err)
 		}
 	}
 
 	if r.Version >= 3 {
 		if r.PreviousBrokerEpoch, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "PreviousBrokerEpoch", err)
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -270,6 +270,5 @@ func (r *BrokerRegistrationRequest) IsValidVersion() bool {
 }
 
 func (r *BrokerRegistrationRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -30,19 +30,19 @@ func (r *BrokerRegistrationResponse) decode(pd packetDecoder, version int16) (er
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.BrokerEpoch, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "BrokerEpoch", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -64,8 +64,7 @@ func (r *BrokerRegistrationResponse) IsValidVersion() bool {
 }
 
 func (r *BrokerRegistrationResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *BrokerRegistrationResponse) throttleTime() time.Duration {
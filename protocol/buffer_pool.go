@@ -0,0 +1,64 @@
+package protocol
+
+import (
+	"fmt"
+	"sync"
+)
+
+// maxPooledBufferSize is the capacity above which buffers are not returned to the pool,
+// so a single large fetch response doesn't keep its buffer alive.
+const maxPooledBufferSize = 1 << 20
+
+var bufferPool = sync.Pool{
+	New: func() any {
+		b := make([]byte, 0, 4096)
+		return &b
+	},
+}
+
+// GetBuffer borrows an empty buffer from the pool. It must be returned with PutBuffer once nothing references it anymore,
+// for a buffer written to a connection that is after Write has returned.
+func GetBuffer() *[]byte {
+	return bufferPool.Get().(*[]byte)
+}
+
+// PutBuffer returns a buffer borrowed with GetBuffer to the pool.
+func PutBuffer(b *[]byte) {
+	if cap(*b) > maxPooledBufferSize {
+		return
+	}
+
+	*b = (*b)[:0]
+	bufferPool.Put(b)
+}
+
+// AppendEncode appends the encoding of e to buf. Like Encode, it sizes the encoding with a first pass,
+// so buf grows at most once.
+func AppendEncode(buf []byte, e encoder) ([]byte, error) {
+	enc := getEncoders()
+	defer putEncoders(enc)
+
+	if err := e.encode(&enc.prep); err != nil {
+		return buf, err
+	}
+
+	length := enc.prep.length
+	if length < 0 || length > int(MaxRequestSize) {
+		return buf, fmt.Errorf("invalid request size (%d)", length)
+	}
+
+	start := len(buf)
+	if free := cap(buf) - start; free < length {
+		grown := make([]byte, start, start+length)
+		copy(grown, buf)
+		buf = grown
+	}
+	buf = buf[:start+length]
+
+	enc.real.raw = buf[start:]
+	if err := e.encode(&enc.real); err != nil {
+		return buf[:start], err
+	}
+
+	return buf, nil
+}
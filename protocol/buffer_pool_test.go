@@ -0,0 +1,42 @@
+package protocol
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAppendEncode(t *testing.T) {
+	for _, bm := range benchmarkMessages() {
+		want, err := Encode(bm.msg)
+		if err != nil {
+			t.Fatalf("%s: error encoding: %v", bm.name, err)
+		}
+
+		// a short buffer has to grow, a pooled one usually has room
+		for _, buf := range [][]byte{{1, 2}, append(make([]byte, 0, 8192), 1, 2)} {
+			got, err := AppendEncode(buf, bm.msg)
+			if err != nil {
+				t.Fatalf("%s: error appending: %v", bm.name, err)
+			}
+			if !bytes.Equal(got[:2], []byte{1, 2}) || !bytes.Equal(got[2:], want) {
+				t.Errorf("%s: AppendEncode changed the prefix or differs from Encode\n got %v\nwant %v", bm.name, got, want)
+			}
+		}
+	}
+}
+
+func TestPutBuffer(t *testing.T) {
+	b := GetBuffer()
+	*b = append(*b, "response"...)
+	PutBuffer(b)
+	if len(*b) != 0 {
+		t.Errorf("returned buffer has length %d, want 0", len(*b))
+	}
+
+	// an oversized buffer is left to the garbage collector, and not emptied
+	large := make([]byte, 1, maxPooledBufferSize+1)
+	PutBuffer(&large)
+	if len(large) != 1 {
+		t.Error("oversized buffer should not be returned to the pool")
+	}
+}
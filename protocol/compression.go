@@ -0,0 +1,154 @@
+package protocol
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	snappy "github.com/eapache/go-xerial-snappy"
+	rawsnappy "github.com/golang/snappy"
+	"github.com/pierrec/lz4/v4"
+)
+
+// DefaultMaxDecompressedSize is the default of the largest size the records of a compressed batch may decompress to.
+const DefaultMaxDecompressedSize = 100 * 1024 * 1024
+
+var (
+	errUnsupportedCompressionCodec = errors.New("unsupported compression codec")
+	errDecompressedTooLarge        = errors.New("records decompress to more than the maximum size")
+
+	// xerialHeader starts the snappy data framed by the Java clients, which is followed by two int32 versions
+	// and chunks of raw snappy data, each preceded by its int32 length
+	xerialHeader = []byte{130, 'S', 'N', 'A', 'P', 'P', 'Y', 0}
+)
+
+// maxDecompressedSize is the largest size the records of a compressed batch may decompress to, 0 for the default.
+// socket.request.max.bytes only limits the compressed request, a small batch could decompress to gigabytes otherwise.
+var maxDecompressedSize atomic.Int64
+
+// SetMaxDecompressedSize sets the largest size the records of a compressed batch may decompress to. Batches which
+// decompress to more fail to decode.
+func SetMaxDecompressedSize(size int64) {
+	maxDecompressedSize.Store(size)
+}
+
+func decompressedSizeLimit() int64 {
+	if size := maxDecompressedSize.Load(); size > 0 {
+		return size
+	}
+	return DefaultMaxDecompressedSize
+}
+
+// compress compresses the records of a batch with the codec from the batch attributes.
+// New codecs need a case here and in decompress.
+func compress(codec CompressionType, data []byte) ([]byte, error) {
+	switch codec {
+	case CompressionNone:
+		return data, nil
+	case CompressionGzip:
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		if _, err := writer.Write(data); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case CompressionSnappy:
+		// Java clients use the xerial framing of snappy, so the broker frames the data the same way
+		return snappy.EncodeStream(nil, data), nil
+	case CompressionLz4:
+		// Since the v2 message format, Kafka uses the standard lz4 frame format.
+		// The broken header checksum of older Kafka versions only applies to magic 0 message sets.
+		var buf bytes.Buffer
+		writer := lz4.NewWriter(&buf)
+		if _, err := writer.Write(data); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("%w %d", errUnsupportedCompressionCodec, codec)
+	}
+}
+
+// decompress decompresses the records of a batch with the codec from the batch attributes.
+func decompress(codec CompressionType, data []byte) ([]byte, error) {
+	switch codec {
+	case CompressionNone:
+		return data, nil
+	case CompressionGzip:
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		return readAllLimited(reader)
+	case CompressionSnappy:
+		// snappy decodes all at once, so its decoded length is checked before it allocates
+		n, err := snappyDecodedLen(data)
+		if err != nil {
+			return nil, err
+		}
+		if int64(n) > decompressedSizeLimit() {
+			return nil, fmt.Errorf("%w of %d bytes", errDecompressedTooLarge, decompressedSizeLimit())
+		}
+		// Decode handles both xerial framed and raw snappy blocks, since not all clients frame the data
+		return snappy.Decode(data)
+	case CompressionLz4:
+		return readAllLimited(lz4.NewReader(bytes.NewReader(data)))
+	default:
+		return nil, fmt.Errorf("%w %d", errUnsupportedCompressionCodec, codec)
+	}
+}
+
+// readAllLimited reads the decompressed records up to the maximum size. One byte more is read to tell records of
+// exactly the maximum size from larger ones.
+func readAllLimited(r io.Reader) ([]byte, error) {
+	limit := decompressedSizeLimit()
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w of %d bytes", errDecompressedTooLarge, limit)
+	}
+
+	return data, nil
+}
+
+// snappyDecodedLen returns the length of the snappy data once decoded, which is stored in the header of each raw
+// block. It stops at the maximum size, so the lengths of many chunks can't add up past it.
+func snappyDecodedLen(data []byte) (int, error) {
+	if len(data) < len(xerialHeader) || !bytes.Equal(data[:len(xerialHeader)], xerialHeader) {
+		return rawsnappy.DecodedLen(data)
+	}
+
+	total := 0
+	for pos := len(xerialHeader) + 8; pos+4 <= len(data); {
+		size := int(binary.BigEndian.Uint32(data[pos:]))
+		pos += 4
+		if size < 0 || size > len(data)-pos {
+			return 0, snappy.ErrMalformed
+		}
+
+		n, err := rawsnappy.DecodedLen(data[pos : pos+size])
+		if err != nil {
+			return 0, err
+		}
+		if int64(total)+int64(n) > decompressedSizeLimit() {
+			return 0, fmt.Errorf("%w of %d bytes", errDecompressedTooLarge, decompressedSizeLimit())
+		}
+		total += n
+		pos += size
+	}
+
+	return total, nil
+}
@@ -0,0 +1,84 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/golang/snappy"
+)
+
+func TestDecompress_SnappyFraming(t *testing.T) {
+	data := bytes.Repeat([]byte("snappy"), 20)
+
+	// Java clients frame snappy data with the xerial header, other clients send plain snappy blocks
+	framed, err := compress(CompressionSnappy, data)
+	if err != nil {
+		t.Fatalf("error compressing data: %v", err)
+	}
+	if !bytes.HasPrefix(framed, []byte("\x82SNAPPY\x00")) {
+		t.Errorf("snappy data is missing the xerial header: %x", framed[:8])
+	}
+
+	for name, compressed := range map[string][]byte{"xerial": framed, "block": snappy.Encode(nil, data)} {
+		decompressed, err := decompress(CompressionSnappy, compressed)
+		if err != nil {
+			t.Fatalf("%s: error decompressing data: %v", name, err)
+		}
+		if !bytes.Equal(decompressed, data) {
+			t.Errorf("%s: decompressed data doesn't match", name)
+		}
+	}
+}
+
+func TestDecompress_Lz4Frame(t *testing.T) {
+	// "kafka" compressed by the lz4 command line tool, using the standard frame format with a content checksum
+	compressed := []byte{
+		0x04, 0x22, 0x4d, 0x18, 0x64, 0x40, 0xa7, 0x05, 0x00, 0x00, 0x80, 0x6b, 0x61, 0x66, 0x6b, 0x61,
+		0x00, 0x00, 0x00, 0x00, 0xf5, 0xcb, 0x8e, 0xde,
+	}
+
+	decompressed, err := decompress(CompressionLz4, compressed)
+	if err != nil {
+		t.Fatalf("error decompressing data: %v", err)
+	}
+	if string(decompressed) != "kafka" {
+		t.Errorf("decompressed data = %q, want %q", decompressed, "kafka")
+	}
+}
+
+func TestDecompress_MaxSize(t *testing.T) {
+	const limit = 64 * 1024
+	SetMaxDecompressedSize(limit)
+	t.Cleanup(func() { SetMaxDecompressedSize(0) })
+
+	for _, codec := range []CompressionType{CompressionGzip, CompressionSnappy, CompressionLz4} {
+		for _, size := range []int{limit, limit + 1} {
+			compressed, err := compress(codec, make([]byte, size))
+			if err != nil {
+				t.Fatalf("codec %d: error compressing data: %v", codec, err)
+			}
+
+			decompressed, err := decompress(codec, compressed)
+			if size > limit {
+				if !errors.Is(err, errDecompressedTooLarge) {
+					t.Errorf("codec %d: error = %v decompressing %d bytes, want %v", codec, err, size, errDecompressedTooLarge)
+				}
+			} else if err != nil || len(decompressed) != size {
+				t.Errorf("codec %d: decompressed %d bytes with error %v, want %d bytes", codec, len(decompressed), err, size)
+			}
+		}
+	}
+
+	// a raw snappy block only claiming a large length is rejected before the output is allocated
+	block := binary.AppendUvarint(nil, 1<<30)
+	framed := append([]byte("\x82SNAPPY\x00"), 0, 0, 0, 1, 0, 0, 0, 1)
+	framed = binary.BigEndian.AppendUint32(framed, uint32(len(block)))
+	framed = append(framed, block...)
+	for name, compressed := range map[string][]byte{"xerial": framed, "block": block} {
+		if _, err := decompress(CompressionSnappy, compressed); !errors.Is(err, errDecompressedTooLarge) {
+			t.Errorf("%s: error = %v, want %v", name, err, errDecompressedTooLarge)
+		}
+	}
+}
@@ -26,15 +26,15 @@ func (r *ConsumerGroupDescribeRequest) decode(pd packetDecoder, version int16) (
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.GroupIds, err = pd.getStringArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupIds", err)
 	}
 
 	if r.IncludeAuthorizedOperations, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "IncludeAuthorizedOperations", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -56,6 +56,5 @@ func (r *ConsumerGroupDescribeRequest) IsValidVersion() bool {
 }
 
 func (r *ConsumerGroupDescribeRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -39,19 +39,19 @@ func (t *TopicPartitions_ConsumerGroupDescribeResponse) encode(pe packetEncoder,
 func (t *TopicPartitions_ConsumerGroupDescribeResponse) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.TopicID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicID", err)
 	}
 
 	if t.TopicName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicName", err)
 	}
 
 	if t.Partitions, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -83,21 +83,21 @@ func (a *Assignment_ConsumerGroupDescribeResponse) decode(pd packetDecoder, vers
 	a.Version = version
 	var numTopicPartitions int
 	if numTopicPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicPartitions", err)
 	}
 	if numTopicPartitions > 0 {
 		a.TopicPartitions = make([]TopicPartitions_ConsumerGroupDescribeResponse, numTopicPartitions)
 		for i := 0; i < numTopicPartitions; i++ {
 			var block TopicPartitions_ConsumerGroupDescribeResponse
 			if err := block.decode(pd, a.Version); err != nil {
-				return err
+				return wrapElementError(pd, "TopicPartitions", i, err)
 			}
 			a.TopicPartitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -181,57 +181,57 @@ func (m *Member_ConsumerGroupDescribeResponse) encode(pe packetEncoder, version
 func (m *Member_ConsumerGroupDescribeResponse) decode(pd packetDecoder, version int16) (err error) {
 	m.Version = version
 	if m.MemberID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberID", err)
 	}
 
 	if m.InstanceID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "InstanceID", err)
 	}
 
 	if m.RackID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "RackID", err)
 	}
 
 	if m.MemberEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberEpoch", err)
 	}
 
 	if m.ClientID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClientID", err)
 	}
 
 	if m.ClientHost, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClientHost", err)
 	}
 
 	if m.SubscribedTopicNames, err = pd.getStringArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "SubscribedTopicNames", err)
 	}
 
 	if m.SubscribedTopicRegex, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "SubscribedTopicRegex", err)
 	}
 
 	tmpAssignment := Assignment_ConsumerGroupDescribeResponse{}
 	if err := tmpAssignment.decode(pd, m.Version); err != nil {
-		return err
+		return wrapFieldError(pd, "Assignment", err)
 	}
 	m.Assignment = tmpAssignment
 
 	tmpTargetAssignment := Assignment_ConsumerGroupDescribeResponse{}
 	if err := tmpTargetAssignment.decode(pd, m.Version); err != nil {
-		return err
+		return wrapFieldError(pd, "TargetAssignment", err)
 	}
 	m.TargetAssignment = tmpTargetAssignment
 
 	if m.Version >= 1 {
 		if m.MemberType, err = pd.getInt8(); err != nil {
-			return err
+			return wrapFieldError(pd, "MemberType", err)
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -302,54 +302,54 @@ func (g *DescribedGroup_ConsumerGroupDescribeResponse) encode(pe packetEncoder,
 func (g *DescribedGroup_ConsumerGroupDescribeResponse) decode(pd packetDecoder, version int16) (err error) {
 	g.Version = version
 	if g.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if g.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if g.GroupID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	if g.GroupState, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupState", err)
 	}
 
 	if g.GroupEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupEpoch", err)
 	}
 
 	if g.AssignmentEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "AssignmentEpoch", err)
 	}
 
 	if g.AssignorName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "AssignorName", err)
 	}
 
 	var numMembers int
 	if numMembers, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Members", err)
 	}
 	if numMembers > 0 {
 		g.Members = make([]Member_ConsumerGroupDescribeResponse, numMembers)
 		for i := 0; i < numMembers; i++ {
 			var block Member_ConsumerGroupDescribeResponse
 			if err := block.decode(pd, g.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Members", i, err)
 			}
 			g.Members[i] = block
 		}
 	}
 
 	if g.AuthorizedOperations, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "AuthorizedOperations", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -384,26 +384,26 @@ func (r *ConsumerGroupDescribeResponse) decode(pd packetDecoder, version int16)
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	var numGroups int
 	if numGroups, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Groups", err)
 	}
 	if numGroups > 0 {
 		r.Groups = make([]DescribedGroup_ConsumerGroupDescribeResponse, numGroups)
 		for i := 0; i < numGroups; i++ {
 			var block DescribedGroup_ConsumerGroupDescribeResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Groups", i, err)
 			}
 			r.Groups[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -425,8 +425,7 @@ func (r *ConsumerGroupDescribeResponse) IsValidVersion() bool {
 }
 
 func (r *ConsumerGroupDescribeResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *ConsumerGroupDescribeResponse) throttleTime() time.Duration {
@@ -30,15 +30,15 @@ func (t *TopicPartitions_ConsumerGroupHeartbeatRequest) encode(pe packetEncoder,
 func (t *TopicPartitions_ConsumerGroupHeartbeatRequest) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.TopicID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicID", err)
 	}
 
 	if t.Partitions, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -121,60 +121,60 @@ func (r *ConsumerGroupHeartbeatRequest) decode(pd packetDecoder, version int16)
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.GroupID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	if r.MemberID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberID", err)
 	}
 
 	if r.MemberEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberEpoch", err)
 	}
 
 	if r.InstanceID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "InstanceID", err)
 	}
 
 	if r.RackID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "RackID", err)
 	}
 
 	if r.RebalanceTimeoutMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "RebalanceTimeoutMs", err)
 	}
 
 	if r.SubscribedTopicNames, err = pd.getStringArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "SubscribedTopicNames", err)
 	}
 
 	if r.Version >= 1 {
 		if r.SubscribedTopicRegex, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "SubscribedTopicRegex", err)
 		}
 	}
 
 	if r.ServerAssignor, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ServerAssignor", err)
 	}
 
 	var numTopicPartitions int
 	if numTopicPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicPartitions", err)
 	}
 	if numTopicPartitions > 0 {
 		r.TopicPartitions = make([]TopicPartitions_ConsumerGroupHeartbeatRequest, numTopicPartitions)
 		for i := 0; i < numTopicPartitions; i++ {
 			var block TopicPartitions_ConsumerGroupHeartbeatRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "TopicPartitions", i, err)
 			}
 			r.TopicPartitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -196,6 +196,5 @@ func (r *ConsumerGroupHeartbeatRequest) IsValidVersion() bool {
 }
 
 func (r *ConsumerGroupHeartbeatRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -33,15 +33,15 @@ func (t *TopicPartitions_ConsumerGroupHeartbeatResponse) encode(pe packetEncoder
 func (t *TopicPartitions_ConsumerGroupHeartbeatResponse) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.TopicID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicID", err)
 	}
 
 	if t.Partitions, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -73,21 +73,21 @@ func (a *Assignment_ConsumerGroupHeartbeatResponse) decode(pd packetDecoder, ver
 	a.Version = version
 	var numTopicPartitions int
 	if numTopicPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicPartitions", err)
 	}
 	if numTopicPartitions > 0 {
 		a.TopicPartitions = make([]TopicPartitions_ConsumerGroupHeartbeatResponse, numTopicPartitions)
 		for i := 0; i < numTopicPartitions; i++ {
 			var block TopicPartitions_ConsumerGroupHeartbeatResponse
 			if err := block.decode(pd, a.Version); err != nil {
-				return err
+				return wrapElementError(pd, "TopicPartitions", i, err)
 			}
 			a.TopicPartitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -141,37 +141,37 @@ func (r *ConsumerGroupHeartbeatResponse) decode(pd packetDecoder, version int16)
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if r.MemberID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberID", err)
 	}
 
 	if r.MemberEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberEpoch", err)
 	}
 
 	if r.HeartbeatIntervalMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "HeartbeatIntervalMs", err)
 	}
 
 	tmpAssignment := Assignment_ConsumerGroupHeartbeatResponse{}
 	if err := tmpAssignment.decode(pd, r.Version); err != nil {
-		return err
+		return wrapFieldError(pd, "Assignment", err)
 	}
 	r.Assignment = tmpAssignment
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -193,8 +193,7 @@ func (r *ConsumerGroupHeartbeatResponse) IsValidVersion() bool {
 }
 
 func (r *ConsumerGroupHeartbeatResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *ConsumerGroupHeartbeatResponse) throttleTime() time.Duration {
@@ -32,18 +32,18 @@ func (r *ControlledShutdownRequest) decode(pd packetDecoder, version int16) (err
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.BrokerID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "BrokerID", err)
 	}
 
 	if r.Version >= 2 {
 		if r.BrokerEpoch, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "BrokerEpoch", err)
 		}
 	}
 
 	if r.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -69,6 +69,5 @@ func (r *ControlledShutdownRequest) IsValidVersion() bool {
 }
 
 func (r *ControlledShutdownRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
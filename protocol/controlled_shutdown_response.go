@@ -28,16 +28,16 @@ func (r *RemainingPartition) encode(pe packetEncoder, version int16) (err error)
 func (r *RemainingPartition) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.TopicName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicName", err)
 	}
 
 	if r.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if r.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -79,19 +79,19 @@ func (r *ControlledShutdownResponse) decode(pd packetDecoder, version int16) (er
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	var numRemainingPartitions int
 	if numRemainingPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "RemainingPartitions", err)
 	}
 	if numRemainingPartitions > 0 {
 		r.RemainingPartitions = make([]RemainingPartition, numRemainingPartitions)
 		for i := 0; i < numRemainingPartitions; i++ {
 			var block RemainingPartition
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "RemainingPartitions", i, err)
 			}
 			r.RemainingPartitions[i] = block
 		}
@@ -99,7 +99,7 @@ func (r *ControlledShutdownResponse) decode(pd packetDecoder, version int16) (er
 
 	if r.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -125,6 +125,5 @@ func (r *ControlledShutdownResponse) IsValidVersion() bool {
 }
 
 func (r *ControlledShutdownResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
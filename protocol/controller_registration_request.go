@@ -38,23 +38,23 @@ func (l *Listener_ControllerRegistrationRequest) encode(pe packetEncoder, versio
 func (l *Listener_ControllerRegistrationRequest) decode(pd packetDecoder, version int16) (err error) {
 	l.Version = version
 	if l.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if l.Host, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Host", err)
 	}
 
 	if l.Port, err = pd.getUint16(); err != nil {
-		return err
+		return wrapFieldError(pd, "Port", err)
 	}
 
 	if l.SecurityProtocol, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "SecurityProtocol", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -88,19 +88,19 @@ func (f *Feature_ControllerRegistrationRequest) encode(pe packetEncoder, version
 func (f *Feature_ControllerRegistrationRequest) decode(pd packetDecoder, version int16) (err error) {
 	f.Version = version
 	if f.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if f.MinSupportedVersion, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "MinSupportedVersion", err)
 	}
 
 	if f.MaxSupportedVersion, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "MaxSupportedVersion", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -156,27 +156,27 @@ func (r *ControllerRegistrationRequest) decode(pd packetDecoder, version int16)
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ControllerID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ControllerID", err)
 	}
 
 	if r.IncarnationID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "IncarnationID", err)
 	}
 
 	if r.ZkMigrationReady, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "ZkMigrationReady", err)
 	}
 
 	var numListeners int
 	if numListeners, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Listeners", err)
 	}
 	if numListeners > 0 {
 		r.Listeners = make([]Listener_ControllerRegistrationRequest, numListeners)
 		for i := 0; i < numListeners; i++ {
 			var block Listener_ControllerRegistrationRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Listeners", i, err)
 			}
 			r.Listeners[i] = block
 		}
@@ -184,21 +184,21 @@ func (r *ControllerRegistrationRequest) decode(pd packetDecoder, version int16)
 
 	var numFeatures int
 	if numFeatures, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Features", err)
 	}
 	if numFeatures > 0 {
 		r.Features = make([]Feature_ControllerRegistrationRequest, numFeatures)
 		for i := 0; i < numFeatures; i++ {
 			var block Feature_ControllerRegistrationRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Features", i, err)
 			}
 			r.Features[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -220,6 +220,5 @@ func (r *ControllerRegistrationRequest) IsValidVersion() bool {
 }
 
 func (r *ControllerRegistrationRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -0,0 +1,43 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+)
+
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+// crc32Field is a push encoder/decoder for the CRC-32C checksum of a record batch.
+// The checksum covers everything from the end of the field to the end of the batch.
+type crc32Field struct {
+	startOffset int
+}
+
+func newCRC32Field() *crc32Field {
+	return &crc32Field{}
+}
+
+func (c *crc32Field) saveOffset(in int) {
+	c.startOffset = in
+}
+
+func (c *crc32Field) reserveLength() int {
+	return 4
+}
+
+func (c *crc32Field) run(curOffset int, buf []byte) error {
+	crc := crc32.Checksum(buf[c.startOffset+4:curOffset], castagnoliTable)
+	binary.BigEndian.PutUint32(buf[c.startOffset:], crc)
+	return nil
+}
+
+func (c *crc32Field) check(curOffset int, buf []byte) error {
+	crc := crc32.Checksum(buf[c.startOffset+4:curOffset], castagnoliTable)
+	expected := binary.BigEndian.Uint32(buf[c.startOffset:])
+	if crc != expected {
+		return fmt.Errorf("CRC didn't match expected %#x got %#x", expected, crc)
+	}
+
+	return nil
+}
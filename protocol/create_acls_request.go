@@ -54,38 +54,38 @@ func (c *AclCreation) encode(pe packetEncoder, version int16) (err error) {
 func (c *AclCreation) decode(pd packetDecoder, version int16) (err error) {
 	c.Version = version
 	if c.ResourceType, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResourceType", err)
 	}
 
 	if c.ResourceName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResourceName", err)
 	}
 
 	if c.Version >= 1 {
 		if c.ResourcePatternType, err = pd.getInt8(); err != nil {
-			return err
+			return wrapFieldError(pd, "ResourcePatternType", err)
 		}
 	}
 
 	if c.Principal, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Principal", err)
 	}
 
 	if c.Host, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Host", err)
 	}
 
 	if c.Operation, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "Operation", err)
 	}
 
 	if c.PermissionType, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "PermissionType", err)
 	}
 
 	if c.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -124,14 +124,14 @@ func (r *CreateAclsRequest) decode(pd packetDecoder, version int16) (err error)
 	}
 	var numCreations int
 	if numCreations, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Creations", err)
 	}
 	if numCreations > 0 {
 		r.Creations = make([]AclCreation, numCreations)
 		for i := 0; i < numCreations; i++ {
 			var block AclCreation
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Creations", i, err)
 			}
 			r.Creations[i] = block
 		}
@@ -139,7 +139,7 @@ func (r *CreateAclsRequest) decode(pd packetDecoder, version int16) (err error)
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -165,6 +165,5 @@ func (r *CreateAclsRequest) IsValidVersion() bool {
 }
 
 func (r *CreateAclsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
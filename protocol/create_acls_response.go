@@ -30,16 +30,16 @@ func (r *AclCreationResult) encode(pe packetEncoder, version int16) (err error)
 func (r *AclCreationResult) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -81,19 +81,19 @@ func (r *CreateAclsResponse) decode(pd packetDecoder, version int16) (err error)
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	var numResults int
 	if numResults, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Results", err)
 	}
 	if numResults > 0 {
 		r.Results = make([]AclCreationResult, numResults)
 		for i := 0; i < numResults; i++ {
 			var block AclCreationResult
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Results", i, err)
 			}
 			r.Results[i] = block
 		}
@@ -101,7 +101,7 @@ func (r *CreateAclsResponse) decode(pd packetDecoder, version int16) (err error)
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -127,8 +127,7 @@ func (r *CreateAclsResponse) IsValidVersion() bool {
 }
 
 func (r *CreateAclsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *CreateAclsResponse) throttleTime() time.Duration {
@@ -30,16 +30,16 @@ func (r *CreatableRenewers) encode(pe packetEncoder, version int16) (err error)
 func (r *CreatableRenewers) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.PrincipalType, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "PrincipalType", err)
 	}
 
 	if r.PrincipalName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "PrincipalName", err)
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -98,38 +98,38 @@ func (r *CreateDelegationTokenRequest) decode(pd packetDecoder, version int16) (
 	}
 	if r.Version >= 3 {
 		if r.OwnerPrincipalType, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "OwnerPrincipalType", err)
 		}
 	}
 
 	if r.Version >= 3 {
 		if r.OwnerPrincipalName, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "OwnerPrincipalName", err)
 		}
 	}
 
 	var numRenewers int
 	if numRenewers, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Renewers", err)
 	}
 	if numRenewers > 0 {
 		r.Renewers = make([]CreatableRenewers, numRenewers)
 		for i := 0; i < numRenewers; i++ {
 			var block CreatableRenewers
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Renewers", i, err)
 			}
 			r.Renewers[i] = block
 		}
 	}
 
 	if r.MaxLifetimeMs, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "MaxLifetimeMs", err)
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -155,6 +155,5 @@ func (r *CreateDelegationTokenRequest) IsValidVersion() bool {
 }
 
 func (r *CreateDelegationTokenRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -25,7 +25,7 @@ type CreateDelegationTokenResponse struct {
 	// TokenID contains the token UUID.
 	TokenID string
 	// Hmac contains a HMAC of the delegation token.
-	Hmac []byte
+	Hmac []byte `sensitive:"true"`
 	// ThrottleTimeMs contains the duration in milliseconds for which the request was throttled due to a quota violation, or zero if the request did not violate any quota.
 	ThrottleTimeMs int32
 }
@@ -84,56 +84,56 @@ func (r *CreateDelegationTokenResponse) decode(pd packetDecoder, version int16)
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.PrincipalType, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "PrincipalType", err)
 	}
 
 	if r.PrincipalName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "PrincipalName", err)
 	}
 
 	if r.Version >= 3 {
 		if r.TokenRequesterPrincipalType, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "TokenRequesterPrincipalType", err)
 		}
 	}
 
 	if r.Version >= 3 {
 		if r.TokenRequesterPrincipalName, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "TokenRequesterPrincipalName", err)
 		}
 	}
 
 	if r.IssueTimestampMs, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "IssueTimestampMs", err)
 	}
 
 	if r.ExpiryTimestampMs, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "ExpiryTimestampMs", err)
 	}
 
 	if r.MaxTimestampMs, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "MaxTimestampMs", err)
 	}
 
 	if r.TokenID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TokenID", err)
 	}
 
 	if r.Hmac, err = pd.getBytes(); err != nil {
-		return err
+		return wrapFieldError(pd, "Hmac", err)
 	}
 
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -159,8 +159,7 @@ func (r *CreateDelegationTokenResponse) IsValidVersion() bool {
 }
 
 func (r *CreateDelegationTokenResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *CreateDelegationTokenResponse) throttleTime() time.Duration {
@@ -24,12 +24,12 @@ func (a *CreatePartitionsAssignment) encode(pe packetEncoder, version int16) (er
 func (a *CreatePartitionsAssignment) decode(pd packetDecoder, version int16) (err error) {
 	a.Version = version
 	if a.BrokerIds, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "BrokerIds", err)
 	}
 
 	if a.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -73,23 +73,23 @@ func (t *CreatePartitionsTopic) encode(pe packetEncoder, version int16) (err err
 func (t *CreatePartitionsTopic) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if t.Count, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Count", err)
 	}
 
 	var numAssignments int
 	if numAssignments, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Assignments", err)
 	}
 	if numAssignments > 0 {
 		t.Assignments = make([]CreatePartitionsAssignment, numAssignments)
 		for i := 0; i < numAssignments; i++ {
 			var block CreatePartitionsAssignment
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Assignments", i, err)
 			}
 			t.Assignments[i] = block
 		}
@@ -97,7 +97,7 @@ func (t *CreatePartitionsTopic) decode(pd packetDecoder, version int16) (err err
 
 	if t.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -144,30 +144,30 @@ func (r *CreatePartitionsRequest) decode(pd packetDecoder, version int16) (err e
 	}
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]CreatePartitionsTopic, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block CreatePartitionsTopic
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
 	}
 
 	if r.TimeoutMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "TimeoutMs", err)
 	}
 
 	if r.ValidateOnly, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "ValidateOnly", err)
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -193,6 +193,5 @@ func (r *CreatePartitionsRequest) IsValidVersion() bool {
 }
 
 func (r *CreatePartitionsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -36,20 +36,20 @@ func (r *CreatePartitionsTopicResult) encode(pe packetEncoder, version int16) (e
 func (r *CreatePartitionsTopicResult) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -91,19 +91,19 @@ func (r *CreatePartitionsResponse) decode(pd packetDecoder, version int16) (err
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	var numResults int
 	if numResults, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Results", err)
 	}
 	if numResults > 0 {
 		r.Results = make([]CreatePartitionsTopicResult, numResults)
 		for i := 0; i < numResults; i++ {
 			var block CreatePartitionsTopicResult
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Results", i, err)
 			}
 			r.Results[i] = block
 		}
@@ -111,7 +111,7 @@ func (r *CreatePartitionsResponse) decode(pd packetDecoder, version int16) (err
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -137,8 +137,7 @@ func (r *CreatePartitionsResponse) IsValidVersion() bool {
 }
 
 func (r *CreatePartitionsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *CreatePartitionsResponse) throttleTime() time.Duration {
@@ -28,16 +28,16 @@ func (a *CreatableReplicaAssignment) encode(pe packetEncoder, version int16) (er
 func (a *CreatableReplicaAssignment) decode(pd packetDecoder, version int16) (err error) {
 	a.Version = version
 	if a.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if a.BrokerIds, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "BrokerIds", err)
 	}
 
 	if a.Version >= 5 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -72,16 +72,16 @@ func (c *CreatableTopicConfig) encode(pe packetEncoder, version int16) (err erro
 func (c *CreatableTopicConfig) decode(pd packetDecoder, version int16) (err error) {
 	c.Version = version
 	if c.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if c.Value, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Value", err)
 	}
 
 	if c.Version >= 5 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -140,27 +140,27 @@ func (t *CreatableTopic) encode(pe packetEncoder, version int16) (err error) {
 func (t *CreatableTopic) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if t.NumPartitions, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "NumPartitions", err)
 	}
 
 	if t.ReplicationFactor, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ReplicationFactor", err)
 	}
 
 	var numAssignments int
 	if numAssignments, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Assignments", err)
 	}
 	if numAssignments > 0 {
 		t.Assignments = make([]CreatableReplicaAssignment, numAssignments)
 		for i := 0; i < numAssignments; i++ {
 			var block CreatableReplicaAssignment
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Assignments", i, err)
 			}
 			t.Assignments[i] = block
 		}
@@ -168,14 +168,14 @@ func (t *CreatableTopic) decode(pd packetDecoder, version int16) (err error) {
 
 	var numConfigs int
 	if numConfigs, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Configs", err)
 	}
 	if numConfigs > 0 {
 		t.Configs = make([]CreatableTopicConfig, numConfigs)
 		for i := 0; i < numConfigs; i++ {
 			var block CreatableTopicConfig
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Configs", i, err)
 			}
 			t.Configs[i] = block
 		}
@@ -183,7 +183,7 @@ func (t *CreatableTopic) decode(pd packetDecoder, version int16) (err error) {
 
 	if t.Version >= 5 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -194,10 +194,10 @@ type CreateTopicsRequest struct {
 	Version int16
 	// Topics contains the topics to create.
 	Topics []CreatableTopic
-	// timeoutMs contains a How long to wait in milliseconds before timing out the request.
-	timeoutMs int32
-	// validateOnly contains a If true, check that the topics can be created as specified, but don't create anything.
-	validateOnly bool
+	// TimeoutMs contains a How long to wait in milliseconds before timing out the request.
+	TimeoutMs int32
+	// ValidateOnly contains a If true, check that the topics can be created as specified, but don't create anything.
+	ValidateOnly bool
 }
 
 func (r *CreateTopicsRequest) encode(pe packetEncoder) (err error) {
@@ -213,10 +213,10 @@ func (r *CreateTopicsRequest) encode(pe packetEncoder) (err error) {
 		}
 	}
 
-	pe.putInt32(r.timeoutMs)
+	pe.putInt32(r.TimeoutMs)
 
 	if r.Version >= 1 {
-		pe.putBool(r.validateOnly)
+		pe.putBool(r.ValidateOnly)
 	}
 
 	if r.Version >= 5 {
@@ -232,32 +232,32 @@ func (r *CreateTopicsRequest) decode(pd packetDecoder, version int16) (err error
 	}
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]CreatableTopic, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block CreatableTopic
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
 	}
 
-	if r.timeoutMs, err = pd.getInt32(); err != nil {
-		return err
+	if r.TimeoutMs, err = pd.getInt32(); err != nil {
+		return wrapFieldError(pd, "TimeoutMs", err)
 	}
 
 	if r.Version >= 1 {
-		if r.validateOnly, err = pd.getBool(); err != nil {
-			return err
+		if r.ValidateOnly, err = pd.getBool(); err != nil {
+			return wrapFieldError(pd, "ValidateOnly", err)
 		}
 	}
 
 	if r.Version >= 5 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -283,6 +283,5 @@ func (r *CreateTopicsRequest) IsValidVersion() bool {
 }
 
 func (r *CreateTopicsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -58,37 +58,37 @@ func (c *CreatableTopicConfigs) decode(pd packetDecoder, version int16) (err err
 	c.Version = version
 	if c.Version >= 5 {
 		if c.Name, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Name", err)
 		}
 	}
 
 	if c.Version >= 5 {
 		if c.Value, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Value", err)
 		}
 	}
 
 	if c.Version >= 5 {
 		if c.ReadOnly, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "ReadOnly", err)
 		}
 	}
 
 	if c.Version >= 5 {
 		if c.ConfigSource, err = pd.getInt8(); err != nil {
-			return err
+			return wrapFieldError(pd, "ConfigSource", err)
 		}
 	}
 
 	if c.Version >= 5 {
 		if c.IsSensitive, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "IsSensitive", err)
 		}
 	}
 
 	if c.Version >= 5 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -164,48 +164,48 @@ func (t *CreatableTopicResult) encode(pe packetEncoder, version int16) (err erro
 func (t *CreatableTopicResult) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if t.Version >= 7 {
 		if t.TopicID, err = pd.getUUID(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicID", err)
 		}
 	}
 
 	if t.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if t.Version >= 1 {
 		if t.ErrorMessage, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "ErrorMessage", err)
 		}
 	}
 
 	if t.Version >= 5 {
 		if t.NumPartitions, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "NumPartitions", err)
 		}
 	}
 
 	if t.Version >= 5 {
 		if t.ReplicationFactor, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "ReplicationFactor", err)
 		}
 	}
 
 	if t.Version >= 5 {
 		var numConfigs int
 		if numConfigs, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Configs", err)
 		}
 		if numConfigs > 0 {
 			t.Configs = make([]CreatableTopicConfigs, numConfigs)
 			for i := 0; i < numConfigs; i++ {
 				var block CreatableTopicConfigs
 				if err := block.decode(pd, t.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Configs", i, err)
 				}
 				t.Configs[i] = block
 			}
@@ -214,7 +214,7 @@ func (t *CreatableTopicResult) decode(pd packetDecoder, version int16) (err erro
 
 	if t.Version >= 5 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -259,20 +259,20 @@ func (r *CreateTopicsResponse) decode(pd packetDecoder, version int16) (err erro
 	}
 	if r.Version >= 2 {
 		if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "ThrottleTimeMs", err)
 		}
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]CreatableTopicResult, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block CreatableTopicResult
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -280,7 +280,7 @@ func (r *CreateTopicsResponse) decode(pd packetDecoder, version int16) (err erro
 
 	if r.Version >= 5 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -306,8 +306,7 @@ func (r *CreateTopicsResponse) IsValidVersion() bool {
 }
 
 func (r *CreateTopicsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *CreateTopicsResponse) throttleTime() time.Duration {
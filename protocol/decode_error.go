@@ -0,0 +1,64 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// DecodeError is returned when a message can't be decoded. It names the field which couldn't be decoded by its path
+// in the message, like BeginQuorumEpochRequest.Topics[0].Partitions[3].LeaderEpoch, so a corrupt or truncated message
+// can be located from the logs alone. errors.Is matches the error of the failed read, like ErrInsufficientData.
+type DecodeError struct {
+	// Field is the path of the field in the message.
+	Field string
+	// Offset is the offset of the failed read in the decoded bytes, or -1 if the error didn't come from a read.
+	Offset int
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	if e.Offset < 0 {
+		return fmt.Sprintf("kafka: error decoding %s: %v", e.Field, e.Err)
+	}
+
+	return fmt.Sprintf("kafka: error decoding %s at offset %d: %v", e.Field, e.Offset, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// wrapFieldError adds the field to the path of the error of decoding it. The innermost field creates the DecodeError,
+// with the offset where the decoder failed, the fields enclosing it prepend their names.
+func wrapFieldError(pd packetDecoder, field string, err error) error {
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		decodeErr.Field = field + "." + decodeErr.Field
+		return err
+	}
+
+	return &DecodeError{Field: field, Offset: pd.failedOffset(), Err: err}
+}
+
+// wrapElementError is wrapFieldError for the element i of an array.
+func wrapElementError(pd packetDecoder, field string, i int, err error) error {
+	return wrapFieldError(pd, fmt.Sprintf("%s[%d]", field, i), err)
+}
+
+// wrapMessageError prepends the type of the decoded message to the path of the error, which is only known by the caller
+// of its decode method.
+func wrapMessageError(in any, err error) error {
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		return err
+	}
+
+	t := reflect.TypeOf(in)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	decodeErr.Field = t.Name() + "." + decodeErr.Field
+
+	return err
+}
@@ -0,0 +1,54 @@
+package protocol
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestVersionedDecode_DecodeError(t *testing.T) {
+	clusterID := "test-cluster"
+	req := BeginQuorumEpochRequest{
+		Version:   0,
+		ClusterID: &clusterID,
+		Topics: []TopicData_BeginQuorumEpochRequest{{
+			TopicName: "__cluster_metadata",
+			Partitions: []PartitionData_BeginQuorumEpochRequest{
+				{PartitionIndex: 0, LeaderID: 1, LeaderEpoch: 5},
+				{PartitionIndex: 1, LeaderID: 1, LeaderEpoch: 5},
+				{PartitionIndex: 2, LeaderID: 1, LeaderEpoch: 5},
+				{PartitionIndex: 3, LeaderID: 1, LeaderEpoch: 5},
+			},
+		}},
+	}
+	buf, err := Encode(&req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the request ends with the int32 LeaderEpoch of the last partition, of which 2 bytes remain
+	_, err = VersionedDecode(buf[:len(buf)-2], &BeginQuorumEpochRequest{}, 0)
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("error = %v, want a DecodeError", err)
+	}
+	if want := "BeginQuorumEpochRequest.Topics[0].Partitions[3].LeaderEpoch"; decodeErr.Field != want {
+		t.Errorf("field = %s, want %s", decodeErr.Field, want)
+	}
+	if want := len(buf) - 4; decodeErr.Offset != want {
+		t.Errorf("offset = %d, want %d", decodeErr.Offset, want)
+	}
+	if !errors.Is(err, ErrInsufficientData) {
+		t.Errorf("error = %v, want it to wrap %v", err, ErrInsufficientData)
+	}
+	if !strings.Contains(err.Error(), "Partitions[3].LeaderEpoch") {
+		t.Errorf("error = %q, want it to name the field", err)
+	}
+
+	// the header of the request is decoded on its own, its fields are named after it
+	_, err = VersionedDecode([]byte{0, 53, 0}, &RequestHeader{}, 1)
+	if !errors.As(err, &decodeErr) || decodeErr.Field != "RequestHeader.RequestApiVersion" || decodeErr.Offset != 2 {
+		t.Errorf("error = %v, want a DecodeError of RequestHeader.RequestApiVersion at offset 2", err)
+	}
+}
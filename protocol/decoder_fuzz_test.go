@@ -0,0 +1,98 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// FuzzBeginQuorumEpochRequest feeds arbitrary bytes to the decoder of BeginQuorumEpoch,
+// which must return an error for malformed input instead of panicking.
+func FuzzBeginQuorumEpochRequest(f *testing.F) {
+	for _, version := range []int16{0, 1} {
+		req := &BeginQuorumEpochRequest{Version: version, Topics: []TopicData_BeginQuorumEpochRequest{{Version: version, TopicName: "test-topic"}}}
+		if body, err := Encode(req); err == nil {
+			f.Add(version, body)
+		}
+	}
+	// negative array and string lengths
+	f.Add(int16(0), []byte{0xff, 0xff, 0xff, 0xfe, 0xff, 0xff, 0xff, 0xfe})
+	f.Add(int16(0), []byte{0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x80, 0x00})
+
+	f.Fuzz(func(t *testing.T, version int16, data []byte) {
+		req := &BeginQuorumEpochRequest{Version: version}
+		if !req.IsValidVersion() {
+			return
+		}
+
+		VersionedDecode(data, req, version)
+	})
+}
+
+// FuzzNewRequest decodes arbitrary bytes as the body of any supported request, chosen by the first four bytes
+// as API key and version, the way the server dispatches requests.
+func FuzzNewRequest(f *testing.F) {
+	f.Add([]byte{0, 18, 0, 3, 0x0b, 't', 'e', 's', 't', '-', 'c', 'l', 'i', 'e', 'n', 't', 0x06, '1', '.', '0', '.', '0', 0})
+	f.Add([]byte{0, 3, 0, 12, 0x02, 0xff, 0xff, 0xff, 0xff, 0x0f, 0x00, 0x00})
+	f.Add([]byte{0, 19, 0, 1, 0xff, 0xff, 0xff, 0xff, 0x7f, 0xff})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if len(data) < 4 {
+			return
+		}
+
+		apiKey := int16(binary.BigEndian.Uint16(data))
+		version := int16(binary.BigEndian.Uint16(data[2:]))
+		req, err := NewRequest(apiKey, version)
+		if err != nil || !req.IsValidVersion() {
+			return
+		}
+
+		VersionedDecode(data[4:], req, version)
+	})
+}
+
+// FuzzRealDecoder reads the primitives of the decoder in the order given by the first byte,
+// so their length checks are exercised directly, without a request type to reach them.
+func FuzzRealDecoder(f *testing.F) {
+	// negative and oversized array lengths
+	f.Add(byte(0), []byte{0xff, 0xff, 0xff, 0xfe})
+	f.Add(byte(1), []byte{0x81, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x01})
+	// oversized string length
+	f.Add(byte(2), []byte{0x7f, 0xff, 'a'})
+
+	reads := []func(rd *realDecoder) error{
+		func(rd *realDecoder) error { _, err := rd.getArrayLength(); return err },
+		func(rd *realDecoder) error { _, err := rd.getCompactArrayLength(); return err },
+		func(rd *realDecoder) error { _, err := rd.getString(); return err },
+		func(rd *realDecoder) error { _, err := rd.getNullableString(); return err },
+		func(rd *realDecoder) error { _, err := rd.getCompactString(); return err },
+		func(rd *realDecoder) error { _, err := rd.getCompactNullableString(); return err },
+		func(rd *realDecoder) error { _, err := rd.getBytes(); return err },
+		func(rd *realDecoder) error { _, err := rd.getVarintBytes(); return err },
+		func(rd *realDecoder) error { _, err := rd.getCompactBytes(); return err },
+		func(rd *realDecoder) error { _, err := rd.getCompactInt8Array(); return err },
+		func(rd *realDecoder) error { _, err := rd.getCompactInt16Array(); return err },
+		func(rd *realDecoder) error { _, err := rd.getCompactInt32Array(); return err },
+		func(rd *realDecoder) error { _, err := rd.getInt8Array(); return err },
+		func(rd *realDecoder) error { _, err := rd.getInt16Array(); return err },
+		func(rd *realDecoder) error { _, err := rd.getInt32Array(); return err },
+		func(rd *realDecoder) error { _, err := rd.getInt64Array(); return err },
+		func(rd *realDecoder) error { _, err := rd.getStringArray(); return err },
+		func(rd *realDecoder) error { _, err := rd.getCompactStringArray(); return err },
+		func(rd *realDecoder) error { _, err := rd.getUUIDArray(); return err },
+		func(rd *realDecoder) error { _, err := rd.getEmptyTaggedFieldArray(); return err },
+		func(rd *realDecoder) error { _, err := rd.getVarint(); return err },
+		func(rd *realDecoder) error { _, err := rd.getUVarint(); return err },
+		func(rd *realDecoder) error { _, err := rd.getVarint32(); return err },
+		func(rd *realDecoder) error { _, err := rd.getUVarint32(); return err },
+	}
+
+	f.Fuzz(func(t *testing.T, read byte, data []byte) {
+		rd := &realDecoder{raw: data}
+		for i := int(read); rd.remaining() > 0; i++ {
+			if err := reads[i%len(reads)](rd); err != nil {
+				return
+			}
+		}
+	})
+}
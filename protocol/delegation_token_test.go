@@ -0,0 +1,111 @@
+package protocol
+
+import "testing"
+
+func TestCreateDelegationTokenRequest_RoundTrip(t *testing.T) {
+	ownerType, ownerName := "User", "owner"
+
+	for _, version := range []int16{1, 2, 3} {
+		req := CreateDelegationTokenRequest{
+			Version:       version,
+			Renewers:      []CreatableRenewers{{PrincipalType: "User", PrincipalName: "renewer"}},
+			MaxLifetimeMs: 86400000,
+		}
+		if version >= 3 {
+			req.OwnerPrincipalType, req.OwnerPrincipalName = &ownerType, &ownerName
+		}
+
+		testRoundTrip(t, &req, &CreateDelegationTokenRequest{}, version)
+	}
+}
+
+func TestCreateDelegationTokenResponse_RoundTrip(t *testing.T) {
+	for _, version := range []int16{1, 2, 3} {
+		resp := CreateDelegationTokenResponse{
+			Version:           version,
+			ErrorCode:         61,
+			PrincipalType:     "User",
+			PrincipalName:     "owner",
+			IssueTimestampMs:  1000,
+			ExpiryTimestampMs: 2000,
+			MaxTimestampMs:    3000,
+			TokenID:           "token",
+			Hmac:              []byte{1, 2, 3},
+			ThrottleTimeMs:    10,
+		}
+		if version >= 3 {
+			resp.TokenRequesterPrincipalType, resp.TokenRequesterPrincipalName = "User", "requester"
+		}
+
+		testRoundTrip(t, &resp, &CreateDelegationTokenResponse{}, version)
+	}
+}
+
+func TestRenewDelegationTokenRequest_RoundTrip(t *testing.T) {
+	for _, version := range []int16{1, 2} {
+		req := RenewDelegationTokenRequest{Version: version, Hmac: []byte{1, 2, 3}, RenewPeriodMs: 3600000}
+
+		testRoundTrip(t, &req, &RenewDelegationTokenRequest{}, version)
+	}
+}
+
+func TestRenewDelegationTokenResponse_RoundTrip(t *testing.T) {
+	for _, version := range []int16{1, 2} {
+		resp := RenewDelegationTokenResponse{Version: version, ErrorCode: 61, ExpiryTimestampMs: 2000, ThrottleTimeMs: 10}
+
+		testRoundTrip(t, &resp, &RenewDelegationTokenResponse{}, version)
+	}
+}
+
+func TestExpireDelegationTokenRequest_RoundTrip(t *testing.T) {
+	for _, version := range []int16{1, 2} {
+		req := ExpireDelegationTokenRequest{Version: version, Hmac: []byte{1, 2, 3}, ExpiryTimePeriodMs: -1}
+
+		testRoundTrip(t, &req, &ExpireDelegationTokenRequest{}, version)
+	}
+}
+
+func TestExpireDelegationTokenResponse_RoundTrip(t *testing.T) {
+	for _, version := range []int16{1, 2} {
+		resp := ExpireDelegationTokenResponse{Version: version, ErrorCode: 61, ExpiryTimestampMs: 2000, ThrottleTimeMs: 10}
+
+		testRoundTrip(t, &resp, &ExpireDelegationTokenResponse{}, version)
+	}
+}
+
+func TestDescribeDelegationTokenRequest_RoundTrip(t *testing.T) {
+	for _, version := range []int16{1, 2, 3} {
+		req := DescribeDelegationTokenRequest{
+			Version: version,
+			Owners:  []DescribeDelegationTokenOwner{{PrincipalType: "User", PrincipalName: "owner"}},
+		}
+
+		testRoundTrip(t, &req, &DescribeDelegationTokenRequest{}, version)
+	}
+}
+
+func TestDescribeDelegationTokenResponse_RoundTrip(t *testing.T) {
+	for _, version := range []int16{1, 2, 3} {
+		token := DescribedDelegationToken{
+			PrincipalType:   "User",
+			PrincipalName:   "owner",
+			IssueTimestamp:  1000,
+			ExpiryTimestamp: 2000,
+			MaxTimestamp:    3000,
+			TokenID:         "token",
+			Hmac:            []byte{1, 2, 3},
+			Renewers:        []DescribedDelegationTokenRenewer{{PrincipalType: "User", PrincipalName: "renewer"}},
+		}
+		if version >= 3 {
+			token.TokenRequesterPrincipalType, token.TokenRequesterPrincipalName = "User", "requester"
+		}
+		resp := DescribeDelegationTokenResponse{Version: version, Tokens: []DescribedDelegationToken{token}, ThrottleTimeMs: 10}
+
+		decoded := DescribeDelegationTokenResponse{}
+		testRoundTrip(t, &resp, &decoded, version)
+
+		if len(decoded.Tokens) != 1 || decoded.Tokens[0].TokenID != "token" || len(decoded.Tokens[0].Renewers) != 1 {
+			t.Errorf("v%d: tokens = %+v", version, decoded.Tokens)
+		}
+	}
+}
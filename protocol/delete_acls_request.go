@@ -54,38 +54,38 @@ func (f *DeleteAclsFilter) encode(pe packetEncoder, version int16) (err error) {
 func (f *DeleteAclsFilter) decode(pd packetDecoder, version int16) (err error) {
 	f.Version = version
 	if f.ResourceTypeFilter, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResourceTypeFilter", err)
 	}
 
 	if f.ResourceNameFilter, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResourceNameFilter", err)
 	}
 
 	if f.Version >= 1 {
 		if f.PatternTypeFilter, err = pd.getInt8(); err != nil {
-			return err
+			return wrapFieldError(pd, "PatternTypeFilter", err)
 		}
 	}
 
 	if f.PrincipalFilter, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "PrincipalFilter", err)
 	}
 
 	if f.HostFilter, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "HostFilter", err)
 	}
 
 	if f.Operation, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "Operation", err)
 	}
 
 	if f.PermissionType, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "PermissionType", err)
 	}
 
 	if f.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -124,14 +124,14 @@ func (r *DeleteAclsRequest) decode(pd packetDecoder, version int16) (err error)
 	}
 	var numFilters int
 	if numFilters, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Filters", err)
 	}
 	if numFilters > 0 {
 		r.Filters = make([]DeleteAclsFilter, numFilters)
 		for i := 0; i < numFilters; i++ {
 			var block DeleteAclsFilter
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Filters", i, err)
 			}
 			r.Filters[i] = block
 		}
@@ -139,7 +139,7 @@ func (r *DeleteAclsRequest) decode(pd packetDecoder, version int16) (err error)
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -165,6 +165,5 @@ func (r *DeleteAclsRequest) IsValidVersion() bool {
 }
 
 func (r *DeleteAclsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
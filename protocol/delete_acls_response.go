@@ -66,46 +66,46 @@ func (m *DeleteAclsMatchingACL) encode(pe packetEncoder, version int16) (err err
 func (m *DeleteAclsMatchingACL) decode(pd packetDecoder, version int16) (err error) {
 	m.Version = version
 	if m.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if m.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if m.ResourceType, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResourceType", err)
 	}
 
 	if m.ResourceName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResourceName", err)
 	}
 
 	if m.Version >= 1 {
 		if m.PatternType, err = pd.getInt8(); err != nil {
-			return err
+			return wrapFieldError(pd, "PatternType", err)
 		}
 	}
 
 	if m.Principal, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Principal", err)
 	}
 
 	if m.Host, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Host", err)
 	}
 
 	if m.Operation, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "Operation", err)
 	}
 
 	if m.PermissionType, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "PermissionType", err)
 	}
 
 	if m.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -149,23 +149,23 @@ func (f *DeleteAclsFilterResult) encode(pe packetEncoder, version int16) (err er
 func (f *DeleteAclsFilterResult) decode(pd packetDecoder, version int16) (err error) {
 	f.Version = version
 	if f.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if f.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	var numMatchingAcls int
 	if numMatchingAcls, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "MatchingAcls", err)
 	}
 	if numMatchingAcls > 0 {
 		f.MatchingAcls = make([]DeleteAclsMatchingACL, numMatchingAcls)
 		for i := 0; i < numMatchingAcls; i++ {
 			var block DeleteAclsMatchingACL
 			if err := block.decode(pd, f.Version); err != nil {
-				return err
+				return wrapElementError(pd, "MatchingAcls", i, err)
 			}
 			f.MatchingAcls[i] = block
 		}
@@ -173,7 +173,7 @@ func (f *DeleteAclsFilterResult) decode(pd packetDecoder, version int16) (err er
 
 	if f.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -215,19 +215,19 @@ func (r *DeleteAclsResponse) decode(pd packetDecoder, version int16) (err error)
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	var numFilterResults int
 	if numFilterResults, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "FilterResults", err)
 	}
 	if numFilterResults > 0 {
 		r.FilterResults = make([]DeleteAclsFilterResult, numFilterResults)
 		for i := 0; i < numFilterResults; i++ {
 			var block DeleteAclsFilterResult
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "FilterResults", i, err)
 			}
 			r.FilterResults[i] = block
 		}
@@ -235,7 +235,7 @@ func (r *DeleteAclsResponse) decode(pd packetDecoder, version int16) (err error)
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -261,8 +261,7 @@ func (r *DeleteAclsResponse) IsValidVersion() bool {
 }
 
 func (r *DeleteAclsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *DeleteAclsResponse) throttleTime() time.Duration {
@@ -28,12 +28,12 @@ func (r *DeleteGroupsRequest) decode(pd packetDecoder, version int16) (err error
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.GroupsNames, err = pd.getStringArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupsNames", err)
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -59,6 +59,5 @@ func (r *DeleteGroupsRequest) IsValidVersion() bool {
 }
 
 func (r *DeleteGroupsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -30,16 +30,16 @@ func (r *DeletableGroupResult) encode(pe packetEncoder, version int16) (err erro
 func (r *DeletableGroupResult) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.GroupID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -81,19 +81,19 @@ func (r *DeleteGroupsResponse) decode(pd packetDecoder, version int16) (err erro
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	var numResults int
 	if numResults, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Results", err)
 	}
 	if numResults > 0 {
 		r.Results = make([]DeletableGroupResult, numResults)
 		for i := 0; i < numResults; i++ {
 			var block DeletableGroupResult
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Results", i, err)
 			}
 			r.Results[i] = block
 		}
@@ -101,7 +101,7 @@ func (r *DeleteGroupsResponse) decode(pd packetDecoder, version int16) (err erro
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -127,8 +127,7 @@ func (r *DeleteGroupsResponse) IsValidVersion() bool {
 }
 
 func (r *DeleteGroupsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *DeleteGroupsResponse) throttleTime() time.Duration {
@@ -26,16 +26,16 @@ func (p *DeleteRecordsPartition) encode(pe packetEncoder, version int16) (err er
 func (p *DeleteRecordsPartition) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.Offset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "Offset", err)
 	}
 
 	if p.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -75,19 +75,19 @@ func (t *DeleteRecordsTopic) encode(pe packetEncoder, version int16) (err error)
 func (t *DeleteRecordsTopic) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]DeleteRecordsPartition, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block DeleteRecordsPartition
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
@@ -95,7 +95,7 @@ func (t *DeleteRecordsTopic) decode(pd packetDecoder, version int16) (err error)
 
 	if t.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -138,26 +138,26 @@ func (r *DeleteRecordsRequest) decode(pd packetDecoder, version int16) (err erro
 	}
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]DeleteRecordsTopic, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block DeleteRecordsTopic
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
 	}
 
 	if r.TimeoutMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "TimeoutMs", err)
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -183,6 +183,5 @@ func (r *DeleteRecordsRequest) IsValidVersion() bool {
 }
 
 func (r *DeleteRecordsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -32,20 +32,20 @@ func (p *DeleteRecordsPartitionResult) encode(pe packetEncoder, version int16) (
 func (p *DeleteRecordsPartitionResult) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.LowWatermark, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "LowWatermark", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -85,19 +85,19 @@ func (t *DeleteRecordsTopicResult) encode(pe packetEncoder, version int16) (err
 func (t *DeleteRecordsTopicResult) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]DeleteRecordsPartitionResult, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block DeleteRecordsPartitionResult
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
@@ -105,7 +105,7 @@ func (t *DeleteRecordsTopicResult) decode(pd packetDecoder, version int16) (err
 
 	if t.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -147,19 +147,19 @@ func (r *DeleteRecordsResponse) decode(pd packetDecoder, version int16) (err err
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]DeleteRecordsTopicResult, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block DeleteRecordsTopicResult
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -167,7 +167,7 @@ func (r *DeleteRecordsResponse) decode(pd packetDecoder, version int16) (err err
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -193,8 +193,7 @@ func (r *DeleteRecordsResponse) IsValidVersion() bool {
 }
 
 func (r *DeleteRecordsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *DeleteRecordsResponse) throttleTime() time.Duration {
@@ -22,11 +22,11 @@ func (p *PartitionData_DeleteShareGroupStateRequest) encode(pe packetEncoder, ve
 func (p *PartitionData_DeleteShareGroupStateRequest) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.Partition, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partition", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -63,26 +63,26 @@ func (t *DeleteStateData) encode(pe packetEncoder, version int16) (err error) {
 func (t *DeleteStateData) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.TopicID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicID", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]PartitionData_DeleteShareGroupStateRequest, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionData_DeleteShareGroupStateRequest
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -119,26 +119,26 @@ func (r *DeleteShareGroupStateRequest) decode(pd packetDecoder, version int16) (
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.GroupID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]DeleteStateData, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block DeleteStateData
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -160,6 +160,5 @@ func (r *DeleteShareGroupStateRequest) IsValidVersion() bool {
 }
 
 func (r *DeleteShareGroupStateRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
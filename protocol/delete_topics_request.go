@@ -37,19 +37,19 @@ func (t *DeleteTopicState) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Version >= 6 {
 		if t.Name, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Name", err)
 		}
 	}
 
 	if t.Version >= 6 {
 		if t.TopicID, err = pd.getUUID(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicID", err)
 		}
 	}
 
 	if t.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -103,14 +103,14 @@ func (r *DeleteTopicsRequest) decode(pd packetDecoder, version int16) (err error
 	if r.Version >= 6 {
 		var numTopics int
 		if numTopics, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Topics", err)
 		}
 		if numTopics > 0 {
 			r.Topics = make([]DeleteTopicState, numTopics)
 			for i := 0; i < numTopics; i++ {
 				var block DeleteTopicState
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Topics", i, err)
 				}
 				r.Topics[i] = block
 			}
@@ -119,17 +119,17 @@ func (r *DeleteTopicsRequest) decode(pd packetDecoder, version int16) (err error
 
 	if r.Version >= 0 && r.Version <= 5 {
 		if r.TopicNames, err = pd.getStringArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicNames", err)
 		}
 	}
 
 	if r.TimeoutMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "TimeoutMs", err)
 	}
 
 	if r.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -155,6 +155,5 @@ func (r *DeleteTopicsRequest) IsValidVersion() bool {
 }
 
 func (r *DeleteTopicsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
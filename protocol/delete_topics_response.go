@@ -60,36 +60,36 @@ func (r *DeletableTopicResult) decode(pd packetDecoder, version int16) (err erro
 	r.Version = version
 	if r.Version >= 6 {
 		if r.Name, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Name", err)
 		}
 	} else {
 		def := ""
 		r.Name = &def
 
 		if *r.Name, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Name", err)
 		}
 	}
 
 	if r.Version >= 6 {
 		if r.TopicID, err = pd.getUUID(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicID", err)
 		}
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.Version >= 5 {
 		if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "ErrorMessage", err)
 		}
 	}
 
 	if r.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -134,20 +134,20 @@ func (r *DeleteTopicsResponse) decode(pd packetDecoder, version int16) (err erro
 	}
 	if r.Version >= 1 {
 		if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "ThrottleTimeMs", err)
 		}
 	}
 
 	var numResponses int
 	if numResponses, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Responses", err)
 	}
 	if numResponses > 0 {
 		r.Responses = make([]DeletableTopicResult, numResponses)
 		for i := 0; i < numResponses; i++ {
 			var block DeletableTopicResult
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Responses", i, err)
 			}
 			r.Responses[i] = block
 		}
@@ -155,7 +155,7 @@ func (r *DeleteTopicsResponse) decode(pd packetDecoder, version int16) (err erro
 
 	if r.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -181,8 +181,7 @@ func (r *DeleteTopicsResponse) IsValidVersion() bool {
 }
 
 func (r *DeleteTopicsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *DeleteTopicsResponse) throttleTime() time.Duration {
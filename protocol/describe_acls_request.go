@@ -58,38 +58,38 @@ func (r *DescribeAclsRequest) decode(pd packetDecoder, version int16) (err error
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ResourceTypeFilter, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResourceTypeFilter", err)
 	}
 
 	if r.ResourceNameFilter, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResourceNameFilter", err)
 	}
 
 	if r.Version >= 1 {
 		if r.PatternTypeFilter, err = pd.getInt8(); err != nil {
-			return err
+			return wrapFieldError(pd, "PatternTypeFilter", err)
 		}
 	}
 
 	if r.PrincipalFilter, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "PrincipalFilter", err)
 	}
 
 	if r.HostFilter, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "HostFilter", err)
 	}
 
 	if r.Operation, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "Operation", err)
 	}
 
 	if r.PermissionType, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "PermissionType", err)
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -115,6 +115,5 @@ func (r *DescribeAclsRequest) IsValidVersion() bool {
 }
 
 func (r *DescribeAclsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
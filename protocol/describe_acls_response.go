@@ -40,24 +40,24 @@ func (a *AclDescription) encode(pe packetEncoder, version int16) (err error) {
 func (a *AclDescription) decode(pd packetDecoder, version int16) (err error) {
 	a.Version = version
 	if a.Principal, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Principal", err)
 	}
 
 	if a.Host, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Host", err)
 	}
 
 	if a.Operation, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "Operation", err)
 	}
 
 	if a.PermissionType, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "PermissionType", err)
 	}
 
 	if a.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -107,29 +107,29 @@ func (r *DescribeAclsResource) encode(pe packetEncoder, version int16) (err erro
 func (r *DescribeAclsResource) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.ResourceType, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResourceType", err)
 	}
 
 	if r.ResourceName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResourceName", err)
 	}
 
 	if r.Version >= 1 {
 		if r.PatternType, err = pd.getInt8(); err != nil {
-			return err
+			return wrapFieldError(pd, "PatternType", err)
 		}
 	}
 
 	var numAcls int
 	if numAcls, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Acls", err)
 	}
 	if numAcls > 0 {
 		r.Acls = make([]AclDescription, numAcls)
 		for i := 0; i < numAcls; i++ {
 			var block AclDescription
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Acls", i, err)
 			}
 			r.Acls[i] = block
 		}
@@ -137,7 +137,7 @@ func (r *DescribeAclsResource) decode(pd packetDecoder, version int16) (err erro
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -189,27 +189,27 @@ func (r *DescribeAclsResponse) decode(pd packetDecoder, version int16) (err erro
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	var numResources int
 	if numResources, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Resources", err)
 	}
 	if numResources > 0 {
 		r.Resources = make([]DescribeAclsResource, numResources)
 		for i := 0; i < numResources; i++ {
 			var block DescribeAclsResource
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Resources", i, err)
 			}
 			r.Resources[i] = block
 		}
@@ -217,7 +217,7 @@ func (r *DescribeAclsResponse) decode(pd packetDecoder, version int16) (err erro
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -243,8 +243,7 @@ func (r *DescribeAclsResponse) IsValidVersion() bool {
 }
 
 func (r *DescribeAclsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *DescribeAclsResponse) throttleTime() time.Duration {
@@ -34,20 +34,20 @@ func (c *ComponentData) encode(pe packetEncoder, version int16) (err error) {
 func (c *ComponentData) decode(pd packetDecoder, version int16) (err error) {
 	c.Version = version
 	if c.EntityType, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "EntityType", err)
 	}
 
 	if c.MatchType, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "MatchType", err)
 	}
 
 	if c.Match, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Match", err)
 	}
 
 	if c.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -90,26 +90,26 @@ func (r *DescribeClientQuotasRequest) decode(pd packetDecoder, version int16) (e
 	}
 	var numComponents int
 	if numComponents, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Components", err)
 	}
 	if numComponents > 0 {
 		r.Components = make([]ComponentData, numComponents)
 		for i := 0; i < numComponents; i++ {
 			var block ComponentData
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Components", i, err)
 			}
 			r.Components[i] = block
 		}
 	}
 
 	if r.Strict, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "Strict", err)
 	}
 
 	if r.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -135,6 +135,5 @@ func (r *DescribeClientQuotasRequest) IsValidVersion() bool {
 }
 
 func (r *DescribeClientQuotasRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -32,16 +32,16 @@ func (e *EntityData_DescribeClientQuotasResponse) encode(pe packetEncoder, versi
 func (e *EntityData_DescribeClientQuotasResponse) decode(pd packetDecoder, version int16) (err error) {
 	e.Version = version
 	if e.EntityType, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "EntityType", err)
 	}
 
 	if e.EntityName, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "EntityName", err)
 	}
 
 	if e.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -74,16 +74,16 @@ func (v *ValueData) encode(pe packetEncoder, version int16) (err error) {
 func (v *ValueData) decode(pd packetDecoder, version int16) (err error) {
 	v.Version = version
 	if v.Key, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Key", err)
 	}
 
 	if v.Value, err = pd.getFloat64(); err != nil {
-		return err
+		return wrapFieldError(pd, "Value", err)
 	}
 
 	if v.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -129,14 +129,14 @@ func (e *EntryData_DescribeClientQuotasResponse) decode(pd packetDecoder, versio
 	e.Version = version
 	var numEntity int
 	if numEntity, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Entity", err)
 	}
 	if numEntity > 0 {
 		e.Entity = make([]EntityData_DescribeClientQuotasResponse, numEntity)
 		for i := 0; i < numEntity; i++ {
 			var block EntityData_DescribeClientQuotasResponse
 			if err := block.decode(pd, e.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Entity", i, err)
 			}
 			e.Entity[i] = block
 		}
@@ -144,14 +144,14 @@ func (e *EntryData_DescribeClientQuotasResponse) decode(pd packetDecoder, versio
 
 	var numValues int
 	if numValues, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Values", err)
 	}
 	if numValues > 0 {
 		e.Values = make([]ValueData, numValues)
 		for i := 0; i < numValues; i++ {
 			var block ValueData
 			if err := block.decode(pd, e.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Values", i, err)
 			}
 			e.Values[i] = block
 		}
@@ -159,7 +159,7 @@ func (e *EntryData_DescribeClientQuotasResponse) decode(pd packetDecoder, versio
 
 	if e.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -211,27 +211,27 @@ func (r *DescribeClientQuotasResponse) decode(pd packetDecoder, version int16) (
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	var numEntries int
 	if numEntries, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Entries", err)
 	}
 	if numEntries > 0 {
 		r.Entries = make([]EntryData_DescribeClientQuotasResponse, numEntries)
 		for i := 0; i < numEntries; i++ {
 			var block EntryData_DescribeClientQuotasResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Entries", i, err)
 			}
 			r.Entries[i] = block
 		}
@@ -239,7 +239,7 @@ func (r *DescribeClientQuotasResponse) decode(pd packetDecoder, version int16) (
 
 	if r.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -265,8 +265,7 @@ func (r *DescribeClientQuotasResponse) IsValidVersion() bool {
 }
 
 func (r *DescribeClientQuotasResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *DescribeClientQuotasResponse) throttleTime() time.Duration {
@@ -32,23 +32,23 @@ func (r *DescribeClusterRequest) decode(pd packetDecoder, version int16) (err er
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.IncludeClusterAuthorizedOperations, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "IncludeClusterAuthorizedOperations", err)
 	}
 
 	if r.Version >= 1 {
 		if r.EndpointType, err = pd.getInt8(); err != nil {
-			return err
+			return wrapFieldError(pd, "EndpointType", err)
 		}
 	}
 
 	if r.Version >= 2 {
 		if r.IncludeFencedBrokers, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "IncludeFencedBrokers", err)
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -70,6 +70,5 @@ func (r *DescribeClusterRequest) IsValidVersion() bool {
 }
 
 func (r *DescribeClusterRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -44,29 +44,29 @@ func (b *DescribeClusterBroker) encode(pe packetEncoder, version int16) (err err
 func (b *DescribeClusterBroker) decode(pd packetDecoder, version int16) (err error) {
 	b.Version = version
 	if b.BrokerID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "BrokerID", err)
 	}
 
 	if b.Host, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Host", err)
 	}
 
 	if b.Port, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Port", err)
 	}
 
 	if b.Rack, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Rack", err)
 	}
 
 	if b.Version >= 2 {
 		if b.IsFenced, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "IsFenced", err)
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -131,52 +131,52 @@ func (r *DescribeClusterResponse) decode(pd packetDecoder, version int16) (err e
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if r.Version >= 1 {
 		if r.EndpointType, err = pd.getInt8(); err != nil {
-			return err
+			return wrapFieldError(pd, "EndpointType", err)
 		}
 	}
 
 	if r.ClusterID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClusterID", err)
 	}
 
 	if r.ControllerID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ControllerID", err)
 	}
 
 	var numBrokers int
 	if numBrokers, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Brokers", err)
 	}
 	if numBrokers > 0 {
 		r.Brokers = make([]DescribeClusterBroker, numBrokers)
 		for i := 0; i < numBrokers; i++ {
 			var block DescribeClusterBroker
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Brokers", i, err)
 			}
 			r.Brokers[i] = block
 		}
 	}
 
 	if r.ClusterAuthorizedOperations, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClusterAuthorizedOperations", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -198,8 +198,7 @@ func (r *DescribeClusterResponse) IsValidVersion() bool {
 }
 
 func (r *DescribeClusterResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *DescribeClusterResponse) throttleTime() time.Duration {
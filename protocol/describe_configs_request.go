@@ -34,20 +34,20 @@ func (r *DescribeConfigsResource) encode(pe packetEncoder, version int16) (err e
 func (r *DescribeConfigsResource) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.ResourceType, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResourceType", err)
 	}
 
 	if r.ResourceName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResourceName", err)
 	}
 
 	if r.ConfigurationKeys, err = pd.getStringArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "ConfigurationKeys", err)
 	}
 
 	if r.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -98,14 +98,14 @@ func (r *DescribeConfigsRequest) decode(pd packetDecoder, version int16) (err er
 	}
 	var numResources int
 	if numResources, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Resources", err)
 	}
 	if numResources > 0 {
 		r.Resources = make([]DescribeConfigsResource, numResources)
 		for i := 0; i < numResources; i++ {
 			var block DescribeConfigsResource
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Resources", i, err)
 			}
 			r.Resources[i] = block
 		}
@@ -113,19 +113,19 @@ func (r *DescribeConfigsRequest) decode(pd packetDecoder, version int16) (err er
 
 	if r.Version >= 1 {
 		if r.IncludeSynonyms, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "IncludeSynonyms", err)
 		}
 	}
 
 	if r.Version >= 3 {
 		if r.IncludeDocumentation, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "IncludeDocumentation", err)
 		}
 	}
 
 	if r.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -151,6 +151,5 @@ func (r *DescribeConfigsRequest) IsValidVersion() bool {
 }
 
 func (r *DescribeConfigsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
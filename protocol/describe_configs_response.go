@@ -43,25 +43,25 @@ func (s *DescribeConfigsSynonym) decode(pd packetDecoder, version int16) (err er
 	s.Version = version
 	if s.Version >= 1 {
 		if s.Name, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Name", err)
 		}
 	}
 
 	if s.Version >= 1 {
 		if s.Value, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Value", err)
 		}
 	}
 
 	if s.Version >= 1 {
 		if s.Source, err = pd.getInt8(); err != nil {
-			return err
+			return wrapFieldError(pd, "Source", err)
 		}
 	}
 
 	if s.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -137,38 +137,38 @@ func (c *DescribeConfigsResourceResult) encode(pe packetEncoder, version int16)
 func (c *DescribeConfigsResourceResult) decode(pd packetDecoder, version int16) (err error) {
 	c.Version = version
 	if c.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if c.Value, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Value", err)
 	}
 
 	if c.ReadOnly, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "ReadOnly", err)
 	}
 
 	if c.Version >= 1 {
 		if c.ConfigSource, err = pd.getInt8(); err != nil {
-			return err
+			return wrapFieldError(pd, "ConfigSource", err)
 		}
 	}
 
 	if c.IsSensitive, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "IsSensitive", err)
 	}
 
 	if c.Version >= 1 {
 		var numSynonyms int
 		if numSynonyms, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Synonyms", err)
 		}
 		if numSynonyms > 0 {
 			c.Synonyms = make([]DescribeConfigsSynonym, numSynonyms)
 			for i := 0; i < numSynonyms; i++ {
 				var block DescribeConfigsSynonym
 				if err := block.decode(pd, c.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Synonyms", i, err)
 				}
 				c.Synonyms[i] = block
 			}
@@ -177,19 +177,19 @@ func (c *DescribeConfigsResourceResult) decode(pd packetDecoder, version int16)
 
 	if c.Version >= 3 {
 		if c.ConfigType, err = pd.getInt8(); err != nil {
-			return err
+			return wrapFieldError(pd, "ConfigType", err)
 		}
 	}
 
 	if c.Version >= 3 {
 		if c.Documentation, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Documentation", err)
 		}
 	}
 
 	if c.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -243,31 +243,31 @@ func (r *DescribeConfigsResult) encode(pe packetEncoder, version int16) (err err
 func (r *DescribeConfigsResult) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if r.ResourceType, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResourceType", err)
 	}
 
 	if r.ResourceName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResourceName", err)
 	}
 
 	var numConfigs int
 	if numConfigs, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Configs", err)
 	}
 	if numConfigs > 0 {
 		r.Configs = make([]DescribeConfigsResourceResult, numConfigs)
 		for i := 0; i < numConfigs; i++ {
 			var block DescribeConfigsResourceResult
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Configs", i, err)
 			}
 			r.Configs[i] = block
 		}
@@ -275,7 +275,7 @@ func (r *DescribeConfigsResult) decode(pd packetDecoder, version int16) (err err
 
 	if r.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -317,19 +317,19 @@ func (r *DescribeConfigsResponse) decode(pd packetDecoder, version int16) (err e
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	var numResults int
 	if numResults, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Results", err)
 	}
 	if numResults > 0 {
 		r.Results = make([]DescribeConfigsResult, numResults)
 		for i := 0; i < numResults; i++ {
 			var block DescribeConfigsResult
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Results", i, err)
 			}
 			r.Results[i] = block
 		}
@@ -337,7 +337,7 @@ func (r *DescribeConfigsResponse) decode(pd packetDecoder, version int16) (err e
 
 	if r.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -363,8 +363,7 @@ func (r *DescribeConfigsResponse) IsValidVersion() bool {
 }
 
 func (r *DescribeConfigsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *DescribeConfigsResponse) throttleTime() time.Duration {
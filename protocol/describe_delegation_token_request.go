@@ -30,16 +30,16 @@ func (o *DescribeDelegationTokenOwner) encode(pe packetEncoder, version int16) (
 func (o *DescribeDelegationTokenOwner) decode(pd packetDecoder, version int16) (err error) {
 	o.Version = version
 	if o.PrincipalType, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "PrincipalType", err)
 	}
 
 	if o.PrincipalName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "PrincipalName", err)
 	}
 
 	if o.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -78,14 +78,14 @@ func (r *DescribeDelegationTokenRequest) decode(pd packetDecoder, version int16)
 	}
 	var numOwners int
 	if numOwners, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Owners", err)
 	}
 	if numOwners > 0 {
 		r.Owners = make([]DescribeDelegationTokenOwner, numOwners)
 		for i := 0; i < numOwners; i++ {
 			var block DescribeDelegationTokenOwner
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Owners", i, err)
 			}
 			r.Owners[i] = block
 		}
@@ -93,7 +93,7 @@ func (r *DescribeDelegationTokenRequest) decode(pd packetDecoder, version int16)
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -119,6 +119,5 @@ func (r *DescribeDelegationTokenRequest) IsValidVersion() bool {
 }
 
 func (r *DescribeDelegationTokenRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -32,16 +32,16 @@ func (r *DescribedDelegationTokenRenewer) encode(pe packetEncoder, version int16
 func (r *DescribedDelegationTokenRenewer) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.PrincipalType, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "PrincipalType", err)
 	}
 
 	if r.PrincipalName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "PrincipalName", err)
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -68,7 +68,7 @@ type DescribedDelegationToken struct {
 	// TokenID contains the token ID.
 	TokenID string
 	// Hmac contains the token HMAC.
-	Hmac []byte
+	Hmac []byte `sensitive:"true"`
 	// Renewers contains a Those who are able to renew this token before it expires.
 	Renewers []DescribedDelegationTokenRenewer
 }
@@ -127,55 +127,55 @@ func (t *DescribedDelegationToken) encode(pe packetEncoder, version int16) (err
 func (t *DescribedDelegationToken) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.PrincipalType, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "PrincipalType", err)
 	}
 
 	if t.PrincipalName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "PrincipalName", err)
 	}
 
 	if t.Version >= 3 {
 		if t.TokenRequesterPrincipalType, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "TokenRequesterPrincipalType", err)
 		}
 	}
 
 	if t.Version >= 3 {
 		if t.TokenRequesterPrincipalName, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "TokenRequesterPrincipalName", err)
 		}
 	}
 
 	if t.IssueTimestamp, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "IssueTimestamp", err)
 	}
 
 	if t.ExpiryTimestamp, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "ExpiryTimestamp", err)
 	}
 
 	if t.MaxTimestamp, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "MaxTimestamp", err)
 	}
 
 	if t.TokenID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TokenID", err)
 	}
 
 	if t.Hmac, err = pd.getBytes(); err != nil {
-		return err
+		return wrapFieldError(pd, "Hmac", err)
 	}
 
 	var numRenewers int
 	if numRenewers, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Renewers", err)
 	}
 	if numRenewers > 0 {
 		t.Renewers = make([]DescribedDelegationTokenRenewer, numRenewers)
 		for i := 0; i < numRenewers; i++ {
 			var block DescribedDelegationTokenRenewer
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Renewers", i, err)
 			}
 			t.Renewers[i] = block
 		}
@@ -183,7 +183,7 @@ func (t *DescribedDelegationToken) decode(pd packetDecoder, version int16) (err
 
 	if t.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -229,31 +229,31 @@ func (r *DescribeDelegationTokenResponse) decode(pd packetDecoder, version int16
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	var numTokens int
 	if numTokens, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Tokens", err)
 	}
 	if numTokens > 0 {
 		r.Tokens = make([]DescribedDelegationToken, numTokens)
 		for i := 0; i < numTokens; i++ {
 			var block DescribedDelegationToken
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Tokens", i, err)
 			}
 			r.Tokens[i] = block
 		}
 	}
 
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -279,8 +279,7 @@ func (r *DescribeDelegationTokenResponse) IsValidVersion() bool {
 }
 
 func (r *DescribeDelegationTokenResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *DescribeDelegationTokenResponse) throttleTime() time.Duration {
@@ -34,18 +34,18 @@ func (r *DescribeGroupsRequest) decode(pd packetDecoder, version int16) (err err
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.Groups, err = pd.getStringArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "Groups", err)
 	}
 
 	if r.Version >= 3 {
 		if r.IncludeAuthorizedOperations, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "IncludeAuthorizedOperations", err)
 		}
 	}
 
 	if r.Version >= 5 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -71,6 +71,5 @@ func (r *DescribeGroupsRequest) IsValidVersion() bool {
 }
 
 func (r *DescribeGroupsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
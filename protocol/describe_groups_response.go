@@ -58,34 +58,34 @@ func (m *DescribedGroupMember) encode(pe packetEncoder, version int16) (err erro
 func (m *DescribedGroupMember) decode(pd packetDecoder, version int16) (err error) {
 	m.Version = version
 	if m.MemberID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberID", err)
 	}
 
 	if m.Version >= 4 {
 		if m.GroupInstanceID, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "GroupInstanceID", err)
 		}
 	}
 
 	if m.ClientID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClientID", err)
 	}
 
 	if m.ClientHost, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClientHost", err)
 	}
 
 	if m.MemberMetadata, err = pd.getBytes(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberMetadata", err)
 	}
 
 	if m.MemberAssignment, err = pd.getBytes(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberAssignment", err)
 	}
 
 	if m.Version >= 5 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -161,41 +161,41 @@ func (g *DescribedGroup_DescribeGroupsResponse) encode(pe packetEncoder, version
 func (g *DescribedGroup_DescribeGroupsResponse) decode(pd packetDecoder, version int16) (err error) {
 	g.Version = version
 	if g.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if g.Version >= 6 {
 		if g.ErrorMessage, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "ErrorMessage", err)
 		}
 	}
 
 	if g.GroupID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	if g.GroupState, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupState", err)
 	}
 
 	if g.ProtocolType, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProtocolType", err)
 	}
 
 	if g.ProtocolData, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProtocolData", err)
 	}
 
 	var numMembers int
 	if numMembers, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Members", err)
 	}
 	if numMembers > 0 {
 		g.Members = make([]DescribedGroupMember, numMembers)
 		for i := 0; i < numMembers; i++ {
 			var block DescribedGroupMember
 			if err := block.decode(pd, g.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Members", i, err)
 			}
 			g.Members[i] = block
 		}
@@ -203,13 +203,13 @@ func (g *DescribedGroup_DescribeGroupsResponse) decode(pd packetDecoder, version
 
 	if g.Version >= 3 {
 		if g.AuthorizedOperations, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "AuthorizedOperations", err)
 		}
 	}
 
 	if g.Version >= 5 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -254,20 +254,20 @@ func (r *DescribeGroupsResponse) decode(pd packetDecoder, version int16) (err er
 	}
 	if r.Version >= 1 {
 		if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "ThrottleTimeMs", err)
 		}
 	}
 
 	var numGroups int
 	if numGroups, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Groups", err)
 	}
 	if numGroups > 0 {
 		r.Groups = make([]DescribedGroup_DescribeGroupsResponse, numGroups)
 		for i := 0; i < numGroups; i++ {
 			var block DescribedGroup_DescribeGroupsResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Groups", i, err)
 			}
 			r.Groups[i] = block
 		}
@@ -275,7 +275,7 @@ func (r *DescribeGroupsResponse) decode(pd packetDecoder, version int16) (err er
 
 	if r.Version >= 5 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -301,8 +301,7 @@ func (r *DescribeGroupsResponse) IsValidVersion() bool {
 }
 
 func (r *DescribeGroupsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *DescribeGroupsResponse) throttleTime() time.Duration {
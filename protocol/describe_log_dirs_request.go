@@ -30,16 +30,16 @@ func (t *DescribableLogDirTopic) encode(pe packetEncoder, version int16) (err er
 func (t *DescribableLogDirTopic) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Topic, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topic", err)
 	}
 
 	if t.Partitions, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 
 	if t.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -78,14 +78,14 @@ func (r *DescribeLogDirsRequest) decode(pd packetDecoder, version int16) (err er
 	}
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]DescribableLogDirTopic, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block DescribableLogDirTopic
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -93,7 +93,7 @@ func (r *DescribeLogDirsRequest) decode(pd packetDecoder, version int16) (err er
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -119,6 +119,5 @@ func (r *DescribeLogDirsRequest) IsValidVersion() bool {
 }
 
 func (r *DescribeLogDirsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
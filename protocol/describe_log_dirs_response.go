@@ -36,24 +36,24 @@ func (p *DescribeLogDirsPartition) encode(pe packetEncoder, version int16) (err
 func (p *DescribeLogDirsPartition) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.PartitionSize, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionSize", err)
 	}
 
 	if p.OffsetLag, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "OffsetLag", err)
 	}
 
 	if p.IsFutureKey, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "IsFutureKey", err)
 	}
 
 	if p.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -93,19 +93,19 @@ func (t *DescribeLogDirsTopic) encode(pe packetEncoder, version int16) (err erro
 func (t *DescribeLogDirsTopic) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]DescribeLogDirsPartition, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block DescribeLogDirsPartition
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
@@ -113,7 +113,7 @@ func (t *DescribeLogDirsTopic) decode(pd packetDecoder, version int16) (err erro
 
 	if t.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -169,23 +169,23 @@ func (r *DescribeLogDirsResult) encode(pe packetEncoder, version int16) (err err
 func (r *DescribeLogDirsResult) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.LogDir, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "LogDir", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]DescribeLogDirsTopic, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block DescribeLogDirsTopic
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -193,19 +193,19 @@ func (r *DescribeLogDirsResult) decode(pd packetDecoder, version int16) (err err
 
 	if r.Version >= 4 {
 		if r.TotalBytes, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "TotalBytes", err)
 		}
 	}
 
 	if r.Version >= 4 {
 		if r.UsableBytes, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "UsableBytes", err)
 		}
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -253,25 +253,25 @@ func (r *DescribeLogDirsResponse) decode(pd packetDecoder, version int16) (err e
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.Version >= 3 {
 		if r.ErrorCode, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "ErrorCode", err)
 		}
 	}
 
 	var numResults int
 	if numResults, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Results", err)
 	}
 	if numResults > 0 {
 		r.Results = make([]DescribeLogDirsResult, numResults)
 		for i := 0; i < numResults; i++ {
 			var block DescribeLogDirsResult
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Results", i, err)
 			}
 			r.Results[i] = block
 		}
@@ -279,7 +279,7 @@ func (r *DescribeLogDirsResponse) decode(pd packetDecoder, version int16) (err e
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -305,8 +305,7 @@ func (r *DescribeLogDirsResponse) IsValidVersion() bool {
 }
 
 func (r *DescribeLogDirsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *DescribeLogDirsResponse) throttleTime() time.Duration {
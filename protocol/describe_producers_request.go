@@ -28,15 +28,15 @@ func (t *TopicRequest_DescribeProducersRequest) encode(pe packetEncoder, version
 func (t *TopicRequest_DescribeProducersRequest) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if t.PartitionIndexes, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndexes", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -68,21 +68,21 @@ func (r *DescribeProducersRequest) decode(pd packetDecoder, version int16) (err
 	pd = FlexibleDecoderFrom(pd)
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]TopicRequest_DescribeProducersRequest, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block TopicRequest_DescribeProducersRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -104,6 +104,5 @@ func (r *DescribeProducersRequest) IsValidVersion() bool {
 }
 
 func (r *DescribeProducersRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -42,31 +42,31 @@ func (a *ProducerState) encode(pe packetEncoder, version int16) (err error) {
 func (a *ProducerState) decode(pd packetDecoder, version int16) (err error) {
 	a.Version = version
 	if a.ProducerID, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProducerID", err)
 	}
 
 	if a.ProducerEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProducerEpoch", err)
 	}
 
 	if a.LastSequence, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LastSequence", err)
 	}
 
 	if a.LastTimestamp, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "LastTimestamp", err)
 	}
 
 	if a.CoordinatorEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "CoordinatorEpoch", err)
 	}
 
 	if a.CurrentTxnStartOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "CurrentTxnStartOffset", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -111,34 +111,34 @@ func (p *PartitionResponse) encode(pe packetEncoder, version int16) (err error)
 func (p *PartitionResponse) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	var numActiveProducers int
 	if numActiveProducers, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "ActiveProducers", err)
 	}
 	if numActiveProducers > 0 {
 		p.ActiveProducers = make([]ProducerState, numActiveProducers)
 		for i := 0; i < numActiveProducers; i++ {
 			var block ProducerState
 			if err := block.decode(pd, p.Version); err != nil {
-				return err
+				return wrapElementError(pd, "ActiveProducers", i, err)
 			}
 			p.ActiveProducers[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -175,26 +175,26 @@ func (t *TopicResponse) encode(pe packetEncoder, version int16) (err error) {
 func (t *TopicResponse) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]PartitionResponse, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionResponse
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -229,26 +229,26 @@ func (r *DescribeProducersResponse) decode(pd packetDecoder, version int16) (err
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]TopicResponse, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block TopicResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -270,8 +270,7 @@ func (r *DescribeProducersResponse) IsValidVersion() bool {
 }
 
 func (r *DescribeProducersResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *DescribeProducersResponse) throttleTime() time.Duration {
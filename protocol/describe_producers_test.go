@@ -0,0 +1,51 @@
+package protocol
+
+import "testing"
+
+func TestDescribeProducersRequest_RoundTrip(t *testing.T) {
+	req := DescribeProducersRequest{Topics: []TopicRequest_DescribeProducersRequest{{Name: "test-topic", PartitionIndexes: []int32{0, 2}}}}
+
+	testRoundTrip(t, &req, &DescribeProducersRequest{}, 0)
+}
+
+func TestDescribeProducersResponse_RoundTrip(t *testing.T) {
+	errMsg := "unknown partition"
+	resp := DescribeProducersResponse{
+		ThrottleTimeMs: 10,
+		Topics: []TopicResponse{{
+			Name: "test-topic",
+			Partitions: []PartitionResponse{
+				{PartitionIndex: 0, ActiveProducers: []ProducerState{{ProducerID: 1000, ProducerEpoch: 1, LastSequence: 5, LastTimestamp: 1234, CoordinatorEpoch: -1, CurrentTxnStartOffset: -1}}},
+				{PartitionIndex: 2, ErrorCode: 3, ErrorMessage: &errMsg},
+			},
+		}},
+	}
+
+	testRoundTrip(t, &resp, &DescribeProducersResponse{}, 0)
+}
+
+func TestDescribeTransactionsRequest_RoundTrip(t *testing.T) {
+	req := DescribeTransactionsRequest{TransactionalIds: []string{"txn-1", "txn-2"}}
+
+	testRoundTrip(t, &req, &DescribeTransactionsRequest{}, 0)
+}
+
+func TestDescribeTransactionsResponse_RoundTrip(t *testing.T) {
+	resp := DescribeTransactionsResponse{
+		ThrottleTimeMs: 10,
+		TransactionStates: []TransactionState_DescribeTransactionsResponse{
+			{
+				TransactionalID:        "txn-1",
+				TransactionState:       "Ongoing",
+				TransactionTimeoutMs:   60000,
+				TransactionStartTimeMs: 1234,
+				ProducerID:             1000,
+				ProducerEpoch:          1,
+				Topics:                 []TopicData_DescribeTransactionsResponse{{Topic: "test-topic", Partitions: []int32{0, 1}}},
+			},
+			{ErrorCode: 105, TransactionalID: "txn-2", ProducerID: -1, ProducerEpoch: -1},
+		},
+	}
+
+	testRoundTrip(t, &resp, &DescribeTransactionsResponse{}, 0)
+}
@@ -20,11 +20,11 @@ func (p *PartitionData_DescribeQuorumRequest) encode(pe packetEncoder, version i
 func (p *PartitionData_DescribeQuorumRequest) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -61,26 +61,26 @@ func (t *TopicData_DescribeQuorumRequest) encode(pe packetEncoder, version int16
 func (t *TopicData_DescribeQuorumRequest) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.TopicName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicName", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]PartitionData_DescribeQuorumRequest, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionData_DescribeQuorumRequest
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -112,21 +112,21 @@ func (r *DescribeQuorumRequest) decode(pd packetDecoder, version int16) (err err
 	pd = FlexibleDecoderFrom(pd)
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]TopicData_DescribeQuorumRequest, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block TopicData_DescribeQuorumRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -148,6 +148,5 @@ func (r *DescribeQuorumRequest) IsValidVersion() bool {
 }
 
 func (r *DescribeQuorumRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
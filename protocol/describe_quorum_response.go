@@ -46,33 +46,33 @@ func (r *ReplicaState_DescribeQuorumResponse) encode(pe packetEncoder, version i
 func (r *ReplicaState_DescribeQuorumResponse) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.ReplicaID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ReplicaID", err)
 	}
 
 	if r.Version >= 2 {
 		if r.ReplicaDirectoryID, err = pd.getUUID(); err != nil {
-			return err
+			return wrapFieldError(pd, "ReplicaDirectoryID", err)
 		}
 	}
 
 	if r.LogEndOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "LogEndOffset", err)
 	}
 
 	if r.Version >= 1 {
 		if r.LastFetchTimestamp, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "LastFetchTimestamp", err)
 		}
 	}
 
 	if r.Version >= 1 {
 		if r.LastCaughtUpTimestamp, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "LastCaughtUpTimestamp", err)
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -142,41 +142,41 @@ func (p *PartitionData_DescribeQuorumResponse) encode(pe packetEncoder, version
 func (p *PartitionData_DescribeQuorumResponse) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.Version >= 2 {
 		if p.ErrorMessage, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "ErrorMessage", err)
 		}
 	}
 
 	if p.LeaderID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderID", err)
 	}
 
 	if p.LeaderEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderEpoch", err)
 	}
 
 	if p.HighWatermark, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "HighWatermark", err)
 	}
 
 	var numCurrentVoters int
 	if numCurrentVoters, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "CurrentVoters", err)
 	}
 	if numCurrentVoters > 0 {
 		p.CurrentVoters = make([]ReplicaState_DescribeQuorumResponse, numCurrentVoters)
 		for i := 0; i < numCurrentVoters; i++ {
 			var block ReplicaState_DescribeQuorumResponse
 			if err := block.decode(pd, p.Version); err != nil {
-				return err
+				return wrapElementError(pd, "CurrentVoters", i, err)
 			}
 			p.CurrentVoters[i] = block
 		}
@@ -184,21 +184,21 @@ func (p *PartitionData_DescribeQuorumResponse) decode(pd packetDecoder, version
 
 	var numObservers int
 	if numObservers, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Observers", err)
 	}
 	if numObservers > 0 {
 		p.Observers = make([]ReplicaState_DescribeQuorumResponse, numObservers)
 		for i := 0; i < numObservers; i++ {
 			var block ReplicaState_DescribeQuorumResponse
 			if err := block.decode(pd, p.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Observers", i, err)
 			}
 			p.Observers[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -235,26 +235,26 @@ func (t *TopicData_DescribeQuorumResponse) encode(pe packetEncoder, version int1
 func (t *TopicData_DescribeQuorumResponse) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.TopicName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicName", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]PartitionData_DescribeQuorumResponse, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionData_DescribeQuorumResponse
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -297,24 +297,24 @@ func (l *Listener_DescribeQuorumResponse) decode(pd packetDecoder, version int16
 	l.Version = version
 	if l.Version >= 2 {
 		if l.Name, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Name", err)
 		}
 	}
 
 	if l.Version >= 2 {
 		if l.Host, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Host", err)
 		}
 	}
 
 	if l.Version >= 2 {
 		if l.Port, err = pd.getUint16(); err != nil {
-			return err
+			return wrapFieldError(pd, "Port", err)
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -354,21 +354,21 @@ func (n *Node) decode(pd packetDecoder, version int16) (err error) {
 	n.Version = version
 	if n.Version >= 2 {
 		if n.NodeID, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "NodeID", err)
 		}
 	}
 
 	if n.Version >= 2 {
 		var numListeners int
 		if numListeners, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Listeners", err)
 		}
 		if numListeners > 0 {
 			n.Listeners = make([]Listener_DescribeQuorumResponse, numListeners)
 			for i := 0; i < numListeners; i++ {
 				var block Listener_DescribeQuorumResponse
 				if err := block.decode(pd, n.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Listeners", i, err)
 				}
 				n.Listeners[i] = block
 			}
@@ -376,7 +376,7 @@ func (n *Node) decode(pd packetDecoder, version int16) (err error) {
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -432,25 +432,25 @@ func (r *DescribeQuorumResponse) decode(pd packetDecoder, version int16) (err er
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.Version >= 2 {
 		if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "ErrorMessage", err)
 		}
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]TopicData_DescribeQuorumResponse, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block TopicData_DescribeQuorumResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -459,14 +459,14 @@ func (r *DescribeQuorumResponse) decode(pd packetDecoder, version int16) (err er
 	if r.Version >= 2 {
 		var numNodes int
 		if numNodes, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Nodes", err)
 		}
 		if numNodes > 0 {
 			r.Nodes = make([]Node, numNodes)
 			for i := 0; i < numNodes; i++ {
 				var block Node
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Nodes", i, err)
 				}
 				r.Nodes[i] = block
 			}
@@ -474,7 +474,7 @@ func (r *DescribeQuorumResponse) decode(pd packetDecoder, version int16) (err er
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -496,6 +496,5 @@ func (r *DescribeQuorumResponse) IsValidVersion() bool {
 }
 
 func (r *DescribeQuorumResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
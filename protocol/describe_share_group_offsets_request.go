@@ -28,15 +28,15 @@ func (t *DescribeShareGroupOffsetsRequestTopic) encode(pe packetEncoder, version
 func (t *DescribeShareGroupOffsetsRequestTopic) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.TopicName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicName", err)
 	}
 
 	if t.Partitions, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -73,26 +73,26 @@ func (r *DescribeShareGroupOffsetsRequest) decode(pd packetDecoder, version int1
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.GroupID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]DescribeShareGroupOffsetsRequestTopic, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block DescribeShareGroupOffsetsRequestTopic
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -114,6 +114,5 @@ func (r *DescribeShareGroupOffsetsRequest) IsValidVersion() bool {
 }
 
 func (r *DescribeShareGroupOffsetsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -43,27 +43,27 @@ func (p *DescribeShareGroupOffsetsResponsePartition) encode(pe packetEncoder, ve
 func (p *DescribeShareGroupOffsetsResponsePartition) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.StartOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "StartOffset", err)
 	}
 
 	if p.LeaderEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderEpoch", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -106,30 +106,30 @@ func (r *DescribeShareGroupOffsetsResponseTopic) encode(pe packetEncoder, versio
 func (r *DescribeShareGroupOffsetsResponseTopic) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.TopicName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicName", err)
 	}
 
 	if r.TopicID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicID", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		r.Partitions = make([]DescribeShareGroupOffsetsResponsePartition, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block DescribeShareGroupOffsetsResponsePartition
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			r.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -164,26 +164,26 @@ func (r *DescribeShareGroupOffsetsResponse) decode(pd packetDecoder, version int
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	var numResponses int
 	if numResponses, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Responses", err)
 	}
 	if numResponses > 0 {
 		r.Responses = make([]DescribeShareGroupOffsetsResponseTopic, numResponses)
 		for i := 0; i < numResponses; i++ {
 			var block DescribeShareGroupOffsetsResponseTopic
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Responses", i, err)
 			}
 			r.Responses[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -205,8 +205,7 @@ func (r *DescribeShareGroupOffsetsResponse) IsValidVersion() bool {
 }
 
 func (r *DescribeShareGroupOffsetsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *DescribeShareGroupOffsetsResponse) throttleTime() time.Duration {
@@ -22,11 +22,11 @@ func (t *TopicRequest_DescribeTopicPartitionsRequest) encode(pe packetEncoder, v
 func (t *TopicRequest_DescribeTopicPartitionsRequest) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -56,15 +56,15 @@ func (c *Cursor_DescribeTopicPartitionsRequest) encode(pe packetEncoder, version
 func (c *Cursor_DescribeTopicPartitionsRequest) decode(pd packetDecoder, version int16) (err error) {
 	c.Version = version
 	if c.TopicName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicName", err)
 	}
 
 	if c.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -106,31 +106,31 @@ func (r *DescribeTopicPartitionsRequest) decode(pd packetDecoder, version int16)
 	pd = FlexibleDecoderFrom(pd)
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]TopicRequest_DescribeTopicPartitionsRequest, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block TopicRequest_DescribeTopicPartitionsRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
 	}
 
 	if r.ResponsePartitionLimit, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResponsePartitionLimit", err)
 	}
 
 	tmpCursor := Cursor_DescribeTopicPartitionsRequest{}
 	if err := tmpCursor.decode(pd, r.Version); err != nil {
-		return err
+		return wrapFieldError(pd, "Cursor", err)
 	}
 	r.Cursor = tmpCursor
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -152,6 +152,5 @@ func (r *DescribeTopicPartitionsRequest) IsValidVersion() bool {
 }
 
 func (r *DescribeTopicPartitionsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -67,43 +67,43 @@ func (p *DescribeTopicPartitionsResponsePartition) encode(pe packetEncoder, vers
 func (p *DescribeTopicPartitionsResponsePartition) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.LeaderID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderID", err)
 	}
 
 	if p.LeaderEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderEpoch", err)
 	}
 
 	if p.ReplicaNodes, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "ReplicaNodes", err)
 	}
 
 	if p.IsrNodes, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "IsrNodes", err)
 	}
 
 	if p.EligibleLeaderReplicas, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "EligibleLeaderReplicas", err)
 	}
 
 	if p.LastKnownElr, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "LastKnownElr", err)
 	}
 
 	if p.OfflineReplicas, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "OfflineReplicas", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -158,42 +158,42 @@ func (t *DescribeTopicPartitionsResponseTopic) encode(pe packetEncoder, version
 func (t *DescribeTopicPartitionsResponseTopic) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if t.Name, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if t.TopicID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicID", err)
 	}
 
 	if t.IsInternal, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "IsInternal", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]DescribeTopicPartitionsResponsePartition, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block DescribeTopicPartitionsResponsePartition
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
 	}
 
 	if t.TopicAuthorizedOperations, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicAuthorizedOperations", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -223,15 +223,15 @@ func (n *Cursor_DescribeTopicPartitionsResponse) encode(pe packetEncoder, versio
 func (n *Cursor_DescribeTopicPartitionsResponse) decode(pd packetDecoder, version int16) (err error) {
 	n.Version = version
 	if n.TopicName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicName", err)
 	}
 
 	if n.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -272,19 +272,19 @@ func (r *DescribeTopicPartitionsResponse) decode(pd packetDecoder, version int16
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]DescribeTopicPartitionsResponseTopic, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block DescribeTopicPartitionsResponseTopic
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -292,12 +292,12 @@ func (r *DescribeTopicPartitionsResponse) decode(pd packetDecoder, version int16
 
 	tmpNextCursor := Cursor_DescribeTopicPartitionsResponse{}
 	if err := tmpNextCursor.decode(pd, r.Version); err != nil {
-		return err
+		return wrapFieldError(pd, "NextCursor", err)
 	}
 	r.NextCursor = tmpNextCursor
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -319,8 +319,7 @@ func (r *DescribeTopicPartitionsResponse) IsValidVersion() bool {
 }
 
 func (r *DescribeTopicPartitionsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *DescribeTopicPartitionsResponse) throttleTime() time.Duration {
@@ -22,11 +22,11 @@ func (r *DescribeTransactionsRequest) decode(pd packetDecoder, version int16) (e
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.TransactionalIds, err = pd.getStringArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TransactionalIds", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -48,6 +48,5 @@ func (r *DescribeTransactionsRequest) IsValidVersion() bool {
 }
 
 func (r *DescribeTransactionsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
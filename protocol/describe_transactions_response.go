@@ -30,15 +30,15 @@ func (t *TopicData_DescribeTransactionsResponse) encode(pe packetEncoder, versio
 func (t *TopicData_DescribeTransactionsResponse) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Topic, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topic", err)
 	}
 
 	if t.Partitions, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -101,50 +101,50 @@ func (t *TransactionState_DescribeTransactionsResponse) encode(pe packetEncoder,
 func (t *TransactionState_DescribeTransactionsResponse) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if t.TransactionalID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TransactionalID", err)
 	}
 
 	if t.TransactionState, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TransactionState", err)
 	}
 
 	if t.TransactionTimeoutMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "TransactionTimeoutMs", err)
 	}
 
 	if t.TransactionStartTimeMs, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "TransactionStartTimeMs", err)
 	}
 
 	if t.ProducerID, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProducerID", err)
 	}
 
 	if t.ProducerEpoch, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProducerEpoch", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		t.Topics = make([]TopicData_DescribeTransactionsResponse, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block TopicData_DescribeTransactionsResponse
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			t.Topics[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -179,26 +179,26 @@ func (r *DescribeTransactionsResponse) decode(pd packetDecoder, version int16) (
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	var numTransactionStates int
 	if numTransactionStates, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "TransactionStates", err)
 	}
 	if numTransactionStates > 0 {
 		r.TransactionStates = make([]TransactionState_DescribeTransactionsResponse, numTransactionStates)
 		for i := 0; i < numTransactionStates; i++ {
 			var block TransactionState_DescribeTransactionsResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "TransactionStates", i, err)
 			}
 			r.TransactionStates[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -220,8 +220,7 @@ func (r *DescribeTransactionsResponse) IsValidVersion() bool {
 }
 
 func (r *DescribeTransactionsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *DescribeTransactionsResponse) throttleTime() time.Duration {
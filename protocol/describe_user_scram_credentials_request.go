@@ -22,11 +22,11 @@ func (u *UserName) encode(pe packetEncoder, version int16) (err error) {
 func (u *UserName) decode(pd packetDecoder, version int16) (err error) {
 	u.Version = version
 	if u.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -58,21 +58,21 @@ func (r *DescribeUserScramCredentialsRequest) decode(pd packetDecoder, version i
 	pd = FlexibleDecoderFrom(pd)
 	var numUsers int
 	if numUsers, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Users", err)
 	}
 	if numUsers > 0 {
 		r.Users = make([]UserName, numUsers)
 		for i := 0; i < numUsers; i++ {
 			var block UserName
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Users", i, err)
 			}
 			r.Users[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -94,6 +94,5 @@ func (r *DescribeUserScramCredentialsRequest) IsValidVersion() bool {
 }
 
 func (r *DescribeUserScramCredentialsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
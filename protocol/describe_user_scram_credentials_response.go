@@ -26,15 +26,15 @@ func (c *CredentialInfo) encode(pe packetEncoder, version int16) (err error) {
 func (c *CredentialInfo) decode(pd packetDecoder, version int16) (err error) {
 	c.Version = version
 	if c.Mechanism, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "Mechanism", err)
 	}
 
 	if c.Iterations, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Iterations", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -81,34 +81,34 @@ func (r *DescribeUserScramCredentialsResult) encode(pe packetEncoder, version in
 func (r *DescribeUserScramCredentialsResult) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.User, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "User", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	var numCredentialInfos int
 	if numCredentialInfos, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "CredentialInfos", err)
 	}
 	if numCredentialInfos > 0 {
 		r.CredentialInfos = make([]CredentialInfo, numCredentialInfos)
 		for i := 0; i < numCredentialInfos; i++ {
 			var block CredentialInfo
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "CredentialInfos", i, err)
 			}
 			r.CredentialInfos[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -153,34 +153,34 @@ func (r *DescribeUserScramCredentialsResponse) decode(pd packetDecoder, version
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	var numResults int
 	if numResults, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Results", err)
 	}
 	if numResults > 0 {
 		r.Results = make([]DescribeUserScramCredentialsResult, numResults)
 		for i := 0; i < numResults; i++ {
 			var block DescribeUserScramCredentialsResult
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Results", i, err)
 			}
 			r.Results[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -202,8 +202,7 @@ func (r *DescribeUserScramCredentialsResponse) IsValidVersion() bool {
 }
 
 func (r *DescribeUserScramCredentialsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *DescribeUserScramCredentialsResponse) throttleTime() time.Duration {
@@ -30,16 +30,16 @@ func (t *TopicPartitions_ElectLeadersRequest) encode(pe packetEncoder, version i
 func (t *TopicPartitions_ElectLeadersRequest) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Topic, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topic", err)
 	}
 
 	if t.Partitions, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 
 	if t.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -88,32 +88,32 @@ func (r *ElectLeadersRequest) decode(pd packetDecoder, version int16) (err error
 	}
 	if r.Version >= 1 {
 		if r.ElectionType, err = pd.getInt8(); err != nil {
-			return err
+			return wrapFieldError(pd, "ElectionType", err)
 		}
 	}
 
 	var numTopicPartitions int
 	if numTopicPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicPartitions", err)
 	}
 	if numTopicPartitions > 0 {
 		r.TopicPartitions = make([]TopicPartitions_ElectLeadersRequest, numTopicPartitions)
 		for i := 0; i < numTopicPartitions; i++ {
 			var block TopicPartitions_ElectLeadersRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "TopicPartitions", i, err)
 			}
 			r.TopicPartitions[i] = block
 		}
 	}
 
 	if r.TimeoutMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "TimeoutMs", err)
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -139,6 +139,5 @@ func (r *ElectLeadersRequest) IsValidVersion() bool {
 }
 
 func (r *ElectLeadersRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -34,20 +34,20 @@ func (p *PartitionResult_ElectLeadersResponse) encode(pe packetEncoder, version
 func (p *PartitionResult_ElectLeadersResponse) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionID", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if p.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -87,19 +87,19 @@ func (r *ReplicaElectionResult) encode(pe packetEncoder, version int16) (err err
 func (r *ReplicaElectionResult) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.Topic, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topic", err)
 	}
 
 	var numPartitionResult int
 	if numPartitionResult, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionResult", err)
 	}
 	if numPartitionResult > 0 {
 		r.PartitionResult = make([]PartitionResult_ElectLeadersResponse, numPartitionResult)
 		for i := 0; i < numPartitionResult; i++ {
 			var block PartitionResult_ElectLeadersResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "PartitionResult", i, err)
 			}
 			r.PartitionResult[i] = block
 		}
@@ -107,7 +107,7 @@ func (r *ReplicaElectionResult) decode(pd packetDecoder, version int16) (err err
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -155,25 +155,25 @@ func (r *ElectLeadersResponse) decode(pd packetDecoder, version int16) (err erro
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.Version >= 1 {
 		if r.ErrorCode, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "ErrorCode", err)
 		}
 	}
 
 	var numReplicaElectionResults int
 	if numReplicaElectionResults, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "ReplicaElectionResults", err)
 	}
 	if numReplicaElectionResults > 0 {
 		r.ReplicaElectionResults = make([]ReplicaElectionResult, numReplicaElectionResults)
 		for i := 0; i < numReplicaElectionResults; i++ {
 			var block ReplicaElectionResult
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "ReplicaElectionResults", i, err)
 			}
 			r.ReplicaElectionResults[i] = block
 		}
@@ -181,7 +181,7 @@ func (r *ElectLeadersResponse) decode(pd packetDecoder, version int16) (err erro
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -207,8 +207,7 @@ func (r *ElectLeadersResponse) IsValidVersion() bool {
 }
 
 func (r *ElectLeadersResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *ElectLeadersResponse) throttleTime() time.Duration {
@@ -3,6 +3,7 @@ package protocol
 import (
 	"errors"
 	"fmt"
+	"sync"
 )
 
 const (
@@ -31,31 +32,59 @@ type encoderWithHeader interface {
 	headerVersion() int16
 }
 
+// encoders holds the encoders of the two passes of Encode: the prep encoder sizes the message,
+// so the real encoder writes it into a buffer allocated once. They are pooled, since passing them
+// as packetEncoder moves them to the heap, which would cost two allocations per message.
+type encoders struct {
+	prep prepEncoder
+	real realEncoder
+}
+
+var encodersPool = sync.Pool{
+	New: func() any {
+		return &encoders{}
+	},
+}
+
+func getEncoders() *encoders {
+	return encodersPool.Get().(*encoders)
+}
+
+func putEncoders(enc *encoders) {
+	// drop the references to the encoded message, but keep the capacity of the stacks
+	clear(enc.prep.stack[:cap(enc.prep.stack)])
+	clear(enc.real.stack[:cap(enc.real.stack)])
+	enc.prep = prepEncoder{stack: enc.prep.stack[:0]}
+	enc.real = realEncoder{stack: enc.real.stack[:0]}
+	encodersPool.Put(enc)
+}
+
 // Encode takes an Encoder and turns it into bytes while potentially recording metrics.
+// The buffer of the bytes is the only allocation of Encode itself.
 func Encode(e encoder) ([]byte, error) {
 	if e == nil {
 		return nil, nil
 	}
 
-	var prepEnc prepEncoder
-	var realEnc realEncoder
+	enc := getEncoders()
+	defer putEncoders(enc)
 
-	err := e.encode(&prepEnc)
+	err := e.encode(&enc.prep)
 	if err != nil {
 		return nil, err
 	}
 
-	if prepEnc.length < 0 || prepEnc.length > int(MaxRequestSize) {
-		return nil, fmt.Errorf("invalid request size (%d)", prepEnc.length)
+	if enc.prep.length < 0 || enc.prep.length > int(MaxRequestSize) {
+		return nil, fmt.Errorf("invalid request size (%d)", enc.prep.length)
 	}
 
-	realEnc.raw = make([]byte, prepEnc.length)
-	err = e.encode(&realEnc)
+	enc.real.raw = make([]byte, enc.prep.length)
+	err = e.encode(&enc.real)
 	if err != nil {
 		return nil, err
 	}
 
-	return realEnc.raw, nil
+	return enc.real.raw, nil
 }
 
 // decoder is the interface that wraps the basic Decode method.
@@ -80,7 +109,7 @@ func Decode(buf []byte, in decoder) error {
 	}
 	err := in.decode(&helper)
 	if err != nil {
-		return err
+		return wrapMessageError(in, err)
 	}
 
 	if helper.off != len(buf) {
@@ -100,7 +129,7 @@ func VersionedDecode(buf []byte, in versionedDecoder, version int16) (int, error
 	}
 	err := in.decode(&helper, version)
 	if err != nil {
-		return -1, err
+		return -1, wrapMessageError(in, err)
 	}
 
 	return helper.off, nil
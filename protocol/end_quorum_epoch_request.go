@@ -35,19 +35,19 @@ func (p *ReplicaInfo) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.Version >= 1 {
 		if p.CandidateID, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "CandidateID", err)
 		}
 	}
 
 	if p.Version >= 1 {
 		if p.CandidateDirectoryID, err = pd.getUUID(); err != nil {
-			return err
+			return wrapFieldError(pd, "CandidateDirectoryID", err)
 		}
 	}
 
 	if p.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -103,34 +103,34 @@ func (p *PartitionData_EndQuorumEpochRequest) encode(pe packetEncoder, version i
 func (p *PartitionData_EndQuorumEpochRequest) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.LeaderID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderID", err)
 	}
 
 	if p.LeaderEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderEpoch", err)
 	}
 
 	if p.Version == 0 {
 		if p.PreferredSuccessors, err = pd.getInt32Array(); err != nil {
-			return err
+			return wrapFieldError(pd, "PreferredSuccessors", err)
 		}
 	}
 
 	if p.Version >= 1 {
 		var numPreferredCandidates int
 		if numPreferredCandidates, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "PreferredCandidates", err)
 		}
 		if numPreferredCandidates > 0 {
 			p.PreferredCandidates = make([]ReplicaInfo, numPreferredCandidates)
 			for i := 0; i < numPreferredCandidates; i++ {
 				var block ReplicaInfo
 				if err := block.decode(pd, p.Version); err != nil {
-					return err
+					return wrapElementError(pd, "PreferredCandidates", i, err)
 				}
 				p.PreferredCandidates[i] = block
 			}
@@ -139,7 +139,7 @@ func (p *PartitionData_EndQuorumEpochRequest) decode(pd packetDecoder, version i
 
 	if p.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -179,19 +179,19 @@ func (t *TopicData_EndQuorumEpochRequest) encode(pe packetEncoder, version int16
 func (t *TopicData_EndQuorumEpochRequest) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.TopicName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicName", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]PartitionData_EndQuorumEpochRequest, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionData_EndQuorumEpochRequest
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
@@ -199,7 +199,7 @@ func (t *TopicData_EndQuorumEpochRequest) decode(pd packetDecoder, version int16
 
 	if t.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -245,25 +245,25 @@ func (l *LeaderEndpoint_EndQuorumEpochRequest) decode(pd packetDecoder, version
 	l.Version = version
 	if l.Version >= 1 {
 		if l.Name, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Name", err)
 		}
 	}
 
 	if l.Version >= 1 {
 		if l.Host, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Host", err)
 		}
 	}
 
 	if l.Version >= 1 {
 		if l.Port, err = pd.getUint16(); err != nil {
-			return err
+			return wrapFieldError(pd, "Port", err)
 		}
 	}
 
 	if l.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -320,19 +320,19 @@ func (r *EndQuorumEpochRequest) decode(pd packetDecoder, version int16) (err err
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ClusterID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClusterID", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]TopicData_EndQuorumEpochRequest, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block TopicData_EndQuorumEpochRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -341,14 +341,14 @@ func (r *EndQuorumEpochRequest) decode(pd packetDecoder, version int16) (err err
 	if r.Version >= 1 {
 		var numLeaderEndpoints int
 		if numLeaderEndpoints, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "LeaderEndpoints", err)
 		}
 		if numLeaderEndpoints > 0 {
 			r.LeaderEndpoints = make([]LeaderEndpoint_EndQuorumEpochRequest, numLeaderEndpoints)
 			for i := 0; i < numLeaderEndpoints; i++ {
 				var block LeaderEndpoint_EndQuorumEpochRequest
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "LeaderEndpoints", i, err)
 				}
 				r.LeaderEndpoints[i] = block
 			}
@@ -357,7 +357,7 @@ func (r *EndQuorumEpochRequest) decode(pd packetDecoder, version int16) (err err
 
 	if r.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -383,6 +383,5 @@ func (r *EndQuorumEpochRequest) IsValidVersion() bool {
 }
 
 func (r *EndQuorumEpochRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -40,24 +40,24 @@ func (r *EndTxnRequest) decode(pd packetDecoder, version int16) (err error) {
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.TransactionalID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TransactionalID", err)
 	}
 
 	if r.ProducerID, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProducerID", err)
 	}
 
 	if r.ProducerEpoch, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProducerEpoch", err)
 	}
 
 	if r.Committed, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "Committed", err)
 	}
 
 	if r.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -83,6 +83,5 @@ func (r *EndTxnRequest) IsValidVersion() bool {
 }
 
 func (r *EndTxnRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
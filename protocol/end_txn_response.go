@@ -44,28 +44,28 @@ func (r *EndTxnResponse) decode(pd packetDecoder, version int16) (err error) {
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.Version >= 5 {
 		if r.ProducerID, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "ProducerID", err)
 		}
 	}
 
 	if r.Version >= 5 {
 		if r.ProducerEpoch, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "ProducerEpoch", err)
 		}
 	}
 
 	if r.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -91,8 +91,7 @@ func (r *EndTxnResponse) IsValidVersion() bool {
 }
 
 func (r *EndTxnResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *EndTxnResponse) throttleTime() time.Duration {
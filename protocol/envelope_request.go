@@ -34,19 +34,19 @@ func (r *EnvelopeRequest) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.RequestData, err = pd.getBytes(); err != nil {
-		return err
+		return wrapFieldError(pd, "RequestData", err)
 	}
 
 	if r.RequestPrincipal, err = pd.getBytes(); err != nil {
-		return err
+		return wrapFieldError(pd, "RequestPrincipal", err)
 	}
 
 	if r.ClientHostAddress, err = pd.getBytes(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClientHostAddress", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -68,6 +68,5 @@ func (r *EnvelopeRequest) IsValidVersion() bool {
 }
 
 func (r *EnvelopeRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -26,15 +26,15 @@ func (r *EnvelopeResponse) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ResponseData, err = pd.getBytes(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResponseData", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -56,6 +56,5 @@ func (r *EnvelopeResponse) IsValidVersion() bool {
 }
 
 func (r *EnvelopeResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -36,20 +36,20 @@ func (r *ExpireDelegationTokenResponse) decode(pd packetDecoder, version int16)
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ExpiryTimestampMs, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "ExpiryTimestampMs", err)
 	}
 
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -75,8 +75,7 @@ func (r *ExpireDelegationTokenResponse) IsValidVersion() bool {
 }
 
 func (r *ExpireDelegationTokenResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *ExpireDelegationTokenResponse) throttleTime() time.Duration {
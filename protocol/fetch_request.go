@@ -30,13 +30,13 @@ func (r *ReplicaState_FetchRequest) decode(pd packetDecoder, version int16) (err
 	r.Version = version
 	if r.Version >= 15 {
 		if r.ReplicaID, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "ReplicaID", err)
 		}
 	}
 
 	if r.Version >= 15 {
 		if r.ReplicaEpoch, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "ReplicaEpoch", err)
 		}
 	}
 
@@ -92,38 +92,38 @@ func (p *FetchPartition_FetchRequest) encode(pe packetEncoder, version int16) (e
 func (p *FetchPartition_FetchRequest) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.Partition, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partition", err)
 	}
 
 	if p.Version >= 9 {
 		if p.CurrentLeaderEpoch, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "CurrentLeaderEpoch", err)
 		}
 	}
 
 	if p.FetchOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "FetchOffset", err)
 	}
 
 	if p.Version >= 12 {
 		if p.LastFetchedEpoch, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "LastFetchedEpoch", err)
 		}
 	}
 
 	if p.Version >= 5 {
 		if p.LogStartOffset, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "LogStartOffset", err)
 		}
 	}
 
 	if p.PartitionMaxBytes, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionMaxBytes", err)
 	}
 
 	if p.Version >= 12 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -174,26 +174,26 @@ func (t *FetchTopic_FetchRequest) decode(pd packetDecoder, version int16) (err e
 	t.Version = version
 	if t.Version >= 0 && t.Version <= 12 {
 		if t.Topic, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Topic", err)
 		}
 	}
 
 	if t.Version >= 13 {
 		if t.TopicID, err = pd.getUUID(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicID", err)
 		}
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]FetchPartition_FetchRequest, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block FetchPartition_FetchRequest
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
@@ -201,7 +201,7 @@ func (t *FetchTopic_FetchRequest) decode(pd packetDecoder, version int16) (err e
 
 	if t.Version >= 12 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -249,25 +249,25 @@ func (f *ForgottenTopic_FetchRequest) decode(pd packetDecoder, version int16) (e
 	f.Version = version
 	if f.Version >= 7 && f.Version <= 12 {
 		if f.Topic, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Topic", err)
 		}
 	}
 
 	if f.Version >= 13 {
 		if f.TopicID, err = pd.getUUID(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicID", err)
 		}
 	}
 
 	if f.Version >= 7 {
 		if f.Partitions, err = pd.getInt32Array(); err != nil {
-			return err
+			return wrapFieldError(pd, "Partitions", err)
 		}
 	}
 
 	if f.Version >= 12 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -369,52 +369,52 @@ func (r *FetchRequest) decode(pd packetDecoder, version int16) (err error) {
 	}
 	if r.Version >= 0 && r.Version <= 14 {
 		if r.ReplicaID, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "ReplicaID", err)
 		}
 	}
 
 	if r.MaxWaitMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "MaxWaitMs", err)
 	}
 
 	if r.MinBytes, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "MinBytes", err)
 	}
 
 	if r.Version >= 3 {
 		if r.MaxBytes, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "MaxBytes", err)
 		}
 	}
 
 	if r.Version >= 4 {
 		if r.IsolationLevel, err = pd.getInt8(); err != nil {
-			return err
+			return wrapFieldError(pd, "IsolationLevel", err)
 		}
 	}
 
 	if r.Version >= 7 {
 		if r.SessionID, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "SessionID", err)
 		}
 	}
 
 	if r.Version >= 7 {
 		if r.SessionEpoch, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "SessionEpoch", err)
 		}
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]FetchTopic_FetchRequest, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block FetchTopic_FetchRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -423,14 +423,14 @@ func (r *FetchRequest) decode(pd packetDecoder, version int16) (err error) {
 	if r.Version >= 7 {
 		var numForgottenTopicsData int
 		if numForgottenTopicsData, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "ForgottenTopicsData", err)
 		}
 		if numForgottenTopicsData > 0 {
 			r.ForgottenTopicsData = make([]ForgottenTopic_FetchRequest, numForgottenTopicsData)
 			for i := 0; i < numForgottenTopicsData; i++ {
 				var block ForgottenTopic_FetchRequest
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "ForgottenTopicsData", i, err)
 				}
 				r.ForgottenTopicsData[i] = block
 			}
@@ -439,13 +439,13 @@ func (r *FetchRequest) decode(pd packetDecoder, version int16) (err error) {
 
 	if r.Version >= 11 {
 		if r.RackID, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "RackID", err)
 		}
 	}
 
 	if r.Version >= 12 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -471,6 +471,5 @@ func (r *FetchRequest) IsValidVersion() bool {
 }
 
 func (r *FetchRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -33,13 +33,13 @@ func (d *EpochEndOffset_FetchResponse) decode(pd packetDecoder, version int16) (
 	d.Version = version
 	if d.Version >= 12 {
 		if d.Epoch, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "Epoch", err)
 		}
 	}
 
 	if d.Version >= 12 {
 		if d.EndOffset, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "EndOffset", err)
 		}
 	}
 
@@ -73,13 +73,13 @@ func (c *LeaderIdAndEpoch_FetchResponse) decode(pd packetDecoder, version int16)
 	c.Version = version
 	if c.Version >= 12 {
 		if c.LeaderID, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "LeaderID", err)
 		}
 	}
 
 	if c.Version >= 12 {
 		if c.LeaderEpoch, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "LeaderEpoch", err)
 		}
 	}
 
@@ -108,11 +108,11 @@ func (s *SnapshotId_FetchResponse) encode(pe packetEncoder, version int16) (err
 func (s *SnapshotId_FetchResponse) decode(pd packetDecoder, version int16) (err error) {
 	s.Version = version
 	if s.EndOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "EndOffset", err)
 	}
 
 	if s.Epoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Epoch", err)
 	}
 
 	return nil
@@ -148,19 +148,19 @@ func (a *AbortedTransaction) decode(pd packetDecoder, version int16) (err error)
 	a.Version = version
 	if a.Version >= 4 {
 		if a.ProducerID, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "ProducerID", err)
 		}
 	}
 
 	if a.Version >= 4 {
 		if a.FirstOffset, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "FirstOffset", err)
 		}
 	}
 
 	if a.Version >= 12 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -191,7 +191,7 @@ type PartitionData_FetchResponse struct {
 	// PreferredReadReplica contains the preferred read replica for the consumer to use on its next fetch request.
 	PreferredReadReplica int32
 	// Records contains the record data.
-	Records RecordBatch
+	Records Records
 }
 
 func (p *PartitionData_FetchResponse) encode(pe packetEncoder, version int16) (err error) {
@@ -238,40 +238,40 @@ func (p *PartitionData_FetchResponse) encode(pe packetEncoder, version int16) (e
 func (p *PartitionData_FetchResponse) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.HighWatermark, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "HighWatermark", err)
 	}
 
 	if p.Version >= 4 {
 		if p.LastStableOffset, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "LastStableOffset", err)
 		}
 	}
 
 	if p.Version >= 5 {
 		if p.LogStartOffset, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "LogStartOffset", err)
 		}
 	}
 
 	if p.Version >= 4 {
 		var numAbortedTransactions int
 		if numAbortedTransactions, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "AbortedTransactions", err)
 		}
 		if numAbortedTransactions > 0 {
 			p.AbortedTransactions = make([]AbortedTransaction, numAbortedTransactions)
 			for i := 0; i < numAbortedTransactions; i++ {
 				var block AbortedTransaction
 				if err := block.decode(pd, p.Version); err != nil {
-					return err
+					return wrapElementError(pd, "AbortedTransactions", i, err)
 				}
 				p.AbortedTransactions[i] = block
 			}
@@ -280,19 +280,19 @@ func (p *PartitionData_FetchResponse) decode(pd packetDecoder, version int16) (e
 
 	if p.Version >= 11 {
 		if p.PreferredReadReplica, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "PreferredReadReplica", err)
 		}
 	}
 
-	tmpRecords := RecordBatch{}
+	tmpRecords := Records{}
 	if err := tmpRecords.decode(pd, p.Version); err != nil {
-		return err
+		return wrapFieldError(pd, "Records", err)
 	}
 	p.Records = tmpRecords
 
 	if p.Version >= 12 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -343,26 +343,26 @@ func (r *FetchableTopicResponse) decode(pd packetDecoder, version int16) (err er
 	r.Version = version
 	if r.Version >= 0 && r.Version <= 12 {
 		if r.Topic, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Topic", err)
 		}
 	}
 
 	if r.Version >= 13 {
 		if r.TopicID, err = pd.getUUID(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicID", err)
 		}
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		r.Partitions = make([]PartitionData_FetchResponse, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionData_FetchResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			r.Partitions[i] = block
 		}
@@ -370,7 +370,7 @@ func (r *FetchableTopicResponse) decode(pd packetDecoder, version int16) (err er
 
 	if r.Version >= 12 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -419,25 +419,25 @@ func (n *NodeEndpoint_FetchResponse) decode(pd packetDecoder, version int16) (er
 	n.Version = version
 	if n.Version >= 16 {
 		if n.NodeID, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "NodeID", err)
 		}
 	}
 
 	if n.Version >= 16 {
 		if n.Host, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Host", err)
 		}
 	}
 
 	if n.Version >= 16 {
 		if n.Port, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "Port", err)
 		}
 	}
 
 	if n.Version >= 16 {
 		if n.Rack, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Rack", err)
 		}
 	}
 
@@ -497,32 +497,32 @@ func (r *FetchResponse) decode(pd packetDecoder, version int16) (err error) {
 	}
 	if r.Version >= 1 {
 		if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "ThrottleTimeMs", err)
 		}
 	}
 
 	if r.Version >= 7 {
 		if r.ErrorCode, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "ErrorCode", err)
 		}
 	}
 
 	if r.Version >= 7 {
 		if r.SessionID, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "SessionID", err)
 		}
 	}
 
 	var numResponses int
 	if numResponses, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Responses", err)
 	}
 	if numResponses > 0 {
 		r.Responses = make([]FetchableTopicResponse, numResponses)
 		for i := 0; i < numResponses; i++ {
 			var block FetchableTopicResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Responses", i, err)
 			}
 			r.Responses[i] = block
 		}
@@ -530,7 +530,7 @@ func (r *FetchResponse) decode(pd packetDecoder, version int16) (err error) {
 
 	if r.Version >= 12 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -556,8 +556,7 @@ func (r *FetchResponse) IsValidVersion() bool {
 }
 
 func (r *FetchResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *FetchResponse) throttleTime() time.Duration {
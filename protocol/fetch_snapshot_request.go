@@ -26,15 +26,15 @@ func (s *SnapshotId_FetchSnapshotRequest) encode(pe packetEncoder, version int16
 func (s *SnapshotId_FetchSnapshotRequest) decode(pd packetDecoder, version int16) (err error) {
 	s.Version = version
 	if s.EndOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "EndOffset", err)
 	}
 
 	if s.Epoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Epoch", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -74,25 +74,25 @@ func (p *PartitionSnapshot_FetchSnapshotRequest) encode(pe packetEncoder, versio
 func (p *PartitionSnapshot_FetchSnapshotRequest) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.Partition, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partition", err)
 	}
 
 	if p.CurrentLeaderEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "CurrentLeaderEpoch", err)
 	}
 
 	tmpSnapshotId := SnapshotId_FetchSnapshotRequest{}
 	if err := tmpSnapshotId.decode(pd, p.Version); err != nil {
-		return err
+		return wrapFieldError(pd, "SnapshotID", err)
 	}
 	p.SnapshotID = tmpSnapshotId
 
 	if p.Position, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "Position", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -129,26 +129,26 @@ func (t *TopicSnapshot_FetchSnapshotRequest) encode(pe packetEncoder, version in
 func (t *TopicSnapshot_FetchSnapshotRequest) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]PartitionSnapshot_FetchSnapshotRequest, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionSnapshot_FetchSnapshotRequest
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -189,30 +189,30 @@ func (r *FetchSnapshotRequest) decode(pd packetDecoder, version int16) (err erro
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ReplicaID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ReplicaID", err)
 	}
 
 	if r.MaxBytes, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "MaxBytes", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]TopicSnapshot_FetchSnapshotRequest, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block TopicSnapshot_FetchSnapshotRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -234,6 +234,5 @@ func (r *FetchSnapshotRequest) IsValidVersion() bool {
 }
 
 func (r *FetchSnapshotRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -26,15 +26,15 @@ func (s *SnapshotId_FetchSnapshotResponse) encode(pe packetEncoder, version int1
 func (s *SnapshotId_FetchSnapshotResponse) decode(pd packetDecoder, version int16) (err error) {
 	s.Version = version
 	if s.EndOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "EndOffset", err)
 	}
 
 	if s.Epoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Epoch", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -61,11 +61,11 @@ func (c *LeaderIdAndEpoch_FetchSnapshotResponse) encode(pe packetEncoder, versio
 func (c *LeaderIdAndEpoch_FetchSnapshotResponse) decode(pd packetDecoder, version int16) (err error) {
 	c.Version = version
 	if c.LeaderID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderID", err)
 	}
 
 	if c.LeaderEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderEpoch", err)
 	}
 
 	return nil
@@ -88,7 +88,7 @@ type PartitionSnapshot_FetchSnapshotResponse struct {
 	// Position contains the starting byte position within the snapshot included in the Bytes field.
 	Position int64
 	// UnalignedRecords contains a Snapshot data in records format which may not be aligned on an offset boundary.
-	UnalignedRecords RecordBatch
+	UnalignedRecords Records
 }
 
 func (p *PartitionSnapshot_FetchSnapshotResponse) encode(pe packetEncoder, version int16) (err error) {
@@ -116,35 +116,35 @@ func (p *PartitionSnapshot_FetchSnapshotResponse) encode(pe packetEncoder, versi
 func (p *PartitionSnapshot_FetchSnapshotResponse) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.Index, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Index", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	tmpSnapshotId := SnapshotId_FetchSnapshotResponse{}
 	if err := tmpSnapshotId.decode(pd, p.Version); err != nil {
-		return err
+		return wrapFieldError(pd, "SnapshotID", err)
 	}
 	p.SnapshotID = tmpSnapshotId
 
 	if p.Size, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "Size", err)
 	}
 
 	if p.Position, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "Position", err)
 	}
 
-	tmpUnalignedRecords := RecordBatch{}
+	tmpUnalignedRecords := Records{}
 	if err := tmpUnalignedRecords.decode(pd, p.Version); err != nil {
-		return err
+		return wrapFieldError(pd, "UnalignedRecords", err)
 	}
 	p.UnalignedRecords = tmpUnalignedRecords
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -181,26 +181,26 @@ func (t *TopicSnapshot_FetchSnapshotResponse) encode(pe packetEncoder, version i
 func (t *TopicSnapshot_FetchSnapshotResponse) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]PartitionSnapshot_FetchSnapshotResponse, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionSnapshot_FetchSnapshotResponse
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -240,19 +240,19 @@ func (n *NodeEndpoint_FetchSnapshotResponse) decode(pd packetDecoder, version in
 	n.Version = version
 	if n.Version >= 1 {
 		if n.NodeID, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "NodeID", err)
 		}
 	}
 
 	if n.Version >= 1 {
 		if n.Host, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Host", err)
 		}
 	}
 
 	if n.Version >= 1 {
 		if n.Port, err = pd.getUint16(); err != nil {
-			return err
+			return wrapFieldError(pd, "Port", err)
 		}
 	}
 
@@ -295,30 +295,30 @@ func (r *FetchSnapshotResponse) decode(pd packetDecoder, version int16) (err err
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]TopicSnapshot_FetchSnapshotResponse, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block TopicSnapshot_FetchSnapshotResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -340,8 +340,7 @@ func (r *FetchSnapshotResponse) IsValidVersion() bool {
 }
 
 func (r *FetchSnapshotResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *FetchSnapshotResponse) throttleTime() time.Duration {
@@ -45,25 +45,25 @@ func (r *FindCoordinatorRequest) decode(pd packetDecoder, version int16) (err er
 	}
 	if r.Version >= 0 && r.Version <= 3 {
 		if r.Key, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Key", err)
 		}
 	}
 
 	if r.Version >= 1 {
 		if r.KeyType, err = pd.getInt8(); err != nil {
-			return err
+			return wrapFieldError(pd, "KeyType", err)
 		}
 	}
 
 	if r.Version >= 4 {
 		if r.CoordinatorKeys, err = pd.getStringArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "CoordinatorKeys", err)
 		}
 	}
 
 	if r.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -89,6 +89,5 @@ func (r *FindCoordinatorRequest) IsValidVersion() bool {
 }
 
 func (r *FindCoordinatorRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -63,43 +63,43 @@ func (c *Coordinator) decode(pd packetDecoder, version int16) (err error) {
 	c.Version = version
 	if c.Version >= 4 {
 		if c.Key, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Key", err)
 		}
 	}
 
 	if c.Version >= 4 {
 		if c.NodeID, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "NodeID", err)
 		}
 	}
 
 	if c.Version >= 4 {
 		if c.Host, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Host", err)
 		}
 	}
 
 	if c.Version >= 4 {
 		if c.Port, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "Port", err)
 		}
 	}
 
 	if c.Version >= 4 {
 		if c.ErrorCode, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "ErrorCode", err)
 		}
 	}
 
 	if c.Version >= 4 {
 		if c.ErrorMessage, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "ErrorMessage", err)
 		}
 	}
 
 	if c.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -180,51 +180,51 @@ func (r *FindCoordinatorResponse) decode(pd packetDecoder, version int16) (err e
 	}
 	if r.Version >= 1 {
 		if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "ThrottleTimeMs", err)
 		}
 	}
 
 	if r.Version >= 0 && r.Version <= 3 {
 		if r.ErrorCode, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "ErrorCode", err)
 		}
 	}
 
 	if r.Version >= 1 && r.Version <= 3 {
 		if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "ErrorMessage", err)
 		}
 	}
 
 	if r.Version >= 0 && r.Version <= 3 {
 		if r.NodeID, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "NodeID", err)
 		}
 	}
 
 	if r.Version >= 0 && r.Version <= 3 {
 		if r.Host, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Host", err)
 		}
 	}
 
 	if r.Version >= 0 && r.Version <= 3 {
 		if r.Port, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "Port", err)
 		}
 	}
 
 	if r.Version >= 4 {
 		var numCoordinators int
 		if numCoordinators, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Coordinators", err)
 		}
 		if numCoordinators > 0 {
 			r.Coordinators = make([]Coordinator, numCoordinators)
 			for i := 0; i < numCoordinators; i++ {
 				var block Coordinator
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Coordinators", i, err)
 				}
 				r.Coordinators[i] = block
 			}
@@ -233,7 +233,7 @@ func (r *FindCoordinatorResponse) decode(pd packetDecoder, version int16) (err e
 
 	if r.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -259,8 +259,7 @@ func (r *FindCoordinatorResponse) IsValidVersion() bool {
 }
 
 func (r *FindCoordinatorResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *FindCoordinatorResponse) throttleTime() time.Duration {
@@ -40,6 +40,14 @@ func (fd *flexibleDecoder) getVarint() (int64, error) {
 	return fd.parent.getVarint()
 }
 
+func (fd *flexibleDecoder) getVarint32() (int32, error) {
+	return fd.parent.getVarint32()
+}
+
+func (fd *flexibleDecoder) getUVarint32() (uint32, error) {
+	return fd.parent.getUVarint32()
+}
+
 func (fd *flexibleDecoder) getUVarint() (uint64, error) {
 	return fd.parent.getUVarint()
 }
@@ -82,7 +90,7 @@ func (fd *flexibleDecoder) getString() (string, error) {
 }
 
 func (fd *flexibleDecoder) getNullableString() (*string, error) {
-	return fd.parent.getNullableString()
+	return fd.parent.getCompactNullableString()
 }
 
 func (fd *flexibleDecoder) getCompactString() (string, error) {
@@ -122,7 +130,11 @@ func (fd *flexibleDecoder) getInt64Array() ([]int64, error) {
 }
 
 func (fd *flexibleDecoder) getStringArray() ([]string, error) {
-	return fd.parent.getStringArray()
+	return fd.parent.getCompactStringArray()
+}
+
+func (fd *flexibleDecoder) getCompactStringArray() ([]string, error) {
+	return fd.parent.getCompactStringArray()
 }
 
 func (fd *flexibleDecoder) getUUID() (uuid.UUID, error) {
@@ -158,3 +170,7 @@ func (fd *flexibleDecoder) push(in pushDecoder) error {
 func (fd *flexibleDecoder) pop() error {
 	return fd.parent.pop()
 }
+
+func (fd *flexibleDecoder) failedOffset() int {
+	return fd.parent.failedOffset()
+}
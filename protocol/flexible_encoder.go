@@ -38,6 +38,14 @@ func (fe *flexibleEncoder) putVarint(in int64) {
 	fe.parent.putVarint(in)
 }
 
+func (fe *flexibleEncoder) putVarint32(in int32) {
+	fe.parent.putVarint32(in)
+}
+
+func (fe *flexibleEncoder) putUVarint32(in uint32) {
+	fe.parent.putUVarint32(in)
+}
+
 func (fe *flexibleEncoder) putUVarint(in uint64) {
 	fe.parent.putUVarint(in)
 }
@@ -84,11 +92,15 @@ func (fe *flexibleEncoder) putString(in string) error {
 }
 
 func (fe *flexibleEncoder) putNullableString(in *string) error {
-	return fe.parent.putNullableString(in)
+	return fe.parent.putNullableCompactString(in)
 }
 
 func (fe *flexibleEncoder) putStringArray(in []string) error {
-	return fe.parent.putStringArray(in)
+	return fe.parent.putCompactStringArray(in)
+}
+
+func (fe *flexibleEncoder) putCompactStringArray(in []string) error {
+	return fe.parent.putCompactStringArray(in)
 }
 
 func (fe *flexibleEncoder) putCompactInt8Array(in []int8) error {
@@ -0,0 +1,51 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+)
+
+// frameSizeLength is the length of the big-endian size which prefixes every request and response.
+const frameSizeLength = 4
+
+// ErrFrameTooLarge is returned by ReadFrame for frames larger than the maximum size.
+var ErrFrameTooLarge = errors.New("frame too large")
+
+// WriteFrame writes the payload prefixed with its size. On connections, the size and the payload are written
+// with a single system call, without copying the payload.
+func WriteFrame(w io.Writer, payload []byte) error {
+	size := binary.BigEndian.AppendUint32(make([]byte, 0, frameSizeLength), uint32(len(payload)))
+
+	buffers := net.Buffers{size, payload}
+	_, err := buffers.WriteTo(w)
+	return err
+}
+
+// ReadFrame reads a size-prefixed frame and returns its payload. The size is checked before the payload is allocated,
+// so a bogus size can't make the reader allocate more than maxBytes.
+// It returns io.EOF if the reader ends before a frame, and io.ErrUnexpectedEOF if it ends within a frame.
+// Other errors of the reader, like deadlines, are returned as is.
+func ReadFrame(r io.Reader, maxBytes int) ([]byte, error) {
+	sizeBytes := make([]byte, frameSizeLength)
+	if _, err := io.ReadFull(r, sizeBytes); err != nil {
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(sizeBytes)
+	if uint64(size) > uint64(max(maxBytes, 0)) {
+		return nil, fmt.Errorf("%w: %d bytes, the maximum is %d", ErrFrameTooLarge, size, maxBytes)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,71 @@
+package protocol
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestFrame_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	for _, payload := range [][]byte{[]byte("first"), {}, []byte("second")} {
+		if err := WriteFrame(&buf, payload); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, want := range []string{"first", "", "second"} {
+		got, err := ReadFrame(&buf, 100)
+		if err != nil || string(got) != want {
+			t.Errorf("ReadFrame() = %q, %v, want %q", got, err, want)
+		}
+	}
+
+	if _, err := ReadFrame(&buf, 100); err != io.EOF {
+		t.Errorf("ReadFrame() at the end error = %v, want %v", err, io.EOF)
+	}
+}
+
+func TestReadFrame_SplitReads(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteFrame(&buf, []byte("payload")); err != nil {
+		t.Fatal(err)
+	}
+	frame := buf.Bytes()
+
+	// the frame arrives in two parts, split within the size prefix and within the payload
+	for _, split := range []int{2, 6} {
+		r := io.MultiReader(bytes.NewReader(frame[:split]), bytes.NewReader(frame[split:]))
+		if got, err := ReadFrame(r, 100); err != nil || string(got) != "payload" {
+			t.Errorf("split at %d: ReadFrame() = %q, %v, want payload", split, got, err)
+		}
+	}
+
+	// readers may return a single byte per read
+	if got, err := ReadFrame(iotest.OneByteReader(bytes.NewReader(frame)), 100); err != nil || string(got) != "payload" {
+		t.Errorf("one byte reads: ReadFrame() = %q, %v, want payload", got, err)
+	}
+}
+
+func TestReadFrame_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     []byte
+		wantErr error
+	}{
+		{name: "oversized frame", raw: []byte{0, 0, 0, 11, 'p', 'a', 'y', 'l', 'o', 'a', 'd', ' ', 'l', 'o', 'n'}, wantErr: ErrFrameTooLarge},
+		{name: "bogus size", raw: []byte{0xff, 0xff, 0xff, 0xff}, wantErr: ErrFrameTooLarge},
+		{name: "truncated size", raw: []byte{0, 0}, wantErr: io.ErrUnexpectedEOF},
+		{name: "truncated payload", raw: []byte{0, 0, 0, 5, 'a', 'b'}, wantErr: io.ErrUnexpectedEOF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadFrame(bytes.NewReader(tt.raw), 10)
+			if !errors.Is(err, tt.wantErr) || got != nil {
+				t.Errorf("ReadFrame() = %q, %v, want error %v", got, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -24,11 +24,11 @@ func (r *GetTelemetrySubscriptionsRequest) decode(pd packetDecoder, version int1
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ClientInstanceID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClientInstanceID", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -50,6 +50,5 @@ func (r *GetTelemetrySubscriptionsRequest) IsValidVersion() bool {
 }
 
 func (r *GetTelemetrySubscriptionsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
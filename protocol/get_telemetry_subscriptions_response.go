@@ -63,43 +63,43 @@ func (r *GetTelemetrySubscriptionsResponse) decode(pd packetDecoder, version int
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ClientInstanceID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClientInstanceID", err)
 	}
 
 	if r.SubscriptionID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "SubscriptionID", err)
 	}
 
 	if r.AcceptedCompressionTypes, err = pd.getInt8Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "AcceptedCompressionTypes", err)
 	}
 
 	if r.PushIntervalMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PushIntervalMs", err)
 	}
 
 	if r.TelemetryMaxBytes, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "TelemetryMaxBytes", err)
 	}
 
 	if r.DeltaTemporality, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "DeltaTemporality", err)
 	}
 
 	if r.RequestedMetrics, err = pd.getStringArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "RequestedMetrics", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -121,8 +121,7 @@ func (r *GetTelemetrySubscriptionsResponse) IsValidVersion() bool {
 }
 
 func (r *GetTelemetrySubscriptionsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *GetTelemetrySubscriptionsResponse) throttleTime() time.Duration {
@@ -46,26 +46,26 @@ func (r *HeartbeatRequest) decode(pd packetDecoder, version int16) (err error) {
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.GroupID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	if r.GenerationID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "GenerationID", err)
 	}
 
 	if r.MemberID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberID", err)
 	}
 
 	if r.Version >= 3 {
 		if r.GroupInstanceID, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "GroupInstanceID", err)
 		}
 	}
 
 	if r.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -91,6 +91,5 @@ func (r *HeartbeatRequest) IsValidVersion() bool {
 }
 
 func (r *HeartbeatRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
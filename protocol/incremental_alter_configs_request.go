@@ -34,20 +34,20 @@ func (c *AlterableConfig_IncrementalAlterConfigsRequest) encode(pe packetEncoder
 func (c *AlterableConfig_IncrementalAlterConfigsRequest) decode(pd packetDecoder, version int16) (err error) {
 	c.Version = version
 	if c.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if c.ConfigOperation, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "ConfigOperation", err)
 	}
 
 	if c.Value, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Value", err)
 	}
 
 	if c.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -91,23 +91,23 @@ func (r *AlterConfigsResource_IncrementalAlterConfigsRequest) encode(pe packetEn
 func (r *AlterConfigsResource_IncrementalAlterConfigsRequest) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.ResourceType, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResourceType", err)
 	}
 
 	if r.ResourceName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResourceName", err)
 	}
 
 	var numConfigs int
 	if numConfigs, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Configs", err)
 	}
 	if numConfigs > 0 {
 		r.Configs = make([]AlterableConfig_IncrementalAlterConfigsRequest, numConfigs)
 		for i := 0; i < numConfigs; i++ {
 			var block AlterableConfig_IncrementalAlterConfigsRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Configs", i, err)
 			}
 			r.Configs[i] = block
 		}
@@ -115,7 +115,7 @@ func (r *AlterConfigsResource_IncrementalAlterConfigsRequest) decode(pd packetDe
 
 	if r.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -158,26 +158,26 @@ func (r *IncrementalAlterConfigsRequest) decode(pd packetDecoder, version int16)
 	}
 	var numResources int
 	if numResources, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Resources", err)
 	}
 	if numResources > 0 {
 		r.Resources = make([]AlterConfigsResource_IncrementalAlterConfigsRequest, numResources)
 		for i := 0; i < numResources; i++ {
 			var block AlterConfigsResource_IncrementalAlterConfigsRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Resources", i, err)
 			}
 			r.Resources[i] = block
 		}
 	}
 
 	if r.ValidateOnly, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "ValidateOnly", err)
 	}
 
 	if r.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -203,6 +203,5 @@ func (r *IncrementalAlterConfigsRequest) IsValidVersion() bool {
 }
 
 func (r *IncrementalAlterConfigsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -40,24 +40,24 @@ func (r *AlterConfigsResourceResponse_IncrementalAlterConfigsResponse) encode(pe
 func (r *AlterConfigsResourceResponse_IncrementalAlterConfigsResponse) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if r.ResourceType, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResourceType", err)
 	}
 
 	if r.ResourceName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ResourceName", err)
 	}
 
 	if r.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -99,19 +99,19 @@ func (r *IncrementalAlterConfigsResponse) decode(pd packetDecoder, version int16
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	var numResponses int
 	if numResponses, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Responses", err)
 	}
 	if numResponses > 0 {
 		r.Responses = make([]AlterConfigsResourceResponse_IncrementalAlterConfigsResponse, numResponses)
 		for i := 0; i < numResponses; i++ {
 			var block AlterConfigsResourceResponse_IncrementalAlterConfigsResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Responses", i, err)
 			}
 			r.Responses[i] = block
 		}
@@ -119,7 +119,7 @@ func (r *IncrementalAlterConfigsResponse) decode(pd packetDecoder, version int16
 
 	if r.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -145,8 +145,7 @@ func (r *IncrementalAlterConfigsResponse) IsValidVersion() bool {
 }
 
 func (r *IncrementalAlterConfigsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *IncrementalAlterConfigsResponse) throttleTime() time.Duration {
@@ -56,40 +56,40 @@ func (r *InitProducerIdRequest) decode(pd packetDecoder, version int16) (err err
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.TransactionalID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TransactionalID", err)
 	}
 
 	if r.TransactionTimeoutMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "TransactionTimeoutMs", err)
 	}
 
 	if r.Version >= 3 {
 		if r.ProducerID, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "ProducerID", err)
 		}
 	}
 
 	if r.Version >= 3 {
 		if r.ProducerEpoch, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "ProducerEpoch", err)
 		}
 	}
 
 	if r.Version >= 6 {
 		if r.Enable2Pc, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "Enable2Pc", err)
 		}
 	}
 
 	if r.Version >= 6 {
 		if r.KeepPreparedTxn, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "KeepPreparedTxn", err)
 		}
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -115,6 +115,5 @@ func (r *InitProducerIdRequest) IsValidVersion() bool {
 }
 
 func (r *InitProducerIdRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
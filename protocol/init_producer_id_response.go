@@ -52,36 +52,36 @@ func (r *InitProducerIdResponse) decode(pd packetDecoder, version int16) (err er
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ProducerID, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProducerID", err)
 	}
 
 	if r.ProducerEpoch, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProducerEpoch", err)
 	}
 
 	if r.Version >= 6 {
 		if r.OngoingTxnProducerID, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "OngoingTxnProducerID", err)
 		}
 	}
 
 	if r.Version >= 6 {
 		if r.OngoingTxnProducerEpoch, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "OngoingTxnProducerEpoch", err)
 		}
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -107,8 +107,7 @@ func (r *InitProducerIdResponse) IsValidVersion() bool {
 }
 
 func (r *InitProducerIdResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *InitProducerIdResponse) throttleTime() time.Duration {
@@ -30,19 +30,19 @@ func (p *PartitionData_InitializeShareGroupStateRequest) encode(pe packetEncoder
 func (p *PartitionData_InitializeShareGroupStateRequest) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.Partition, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partition", err)
 	}
 
 	if p.StateEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "StateEpoch", err)
 	}
 
 	if p.StartOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "StartOffset", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -79,26 +79,26 @@ func (t *InitializeStateData) encode(pe packetEncoder, version int16) (err error
 func (t *InitializeStateData) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.TopicID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicID", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]PartitionData_InitializeShareGroupStateRequest, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionData_InitializeShareGroupStateRequest
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -135,26 +135,26 @@ func (r *InitializeShareGroupStateRequest) decode(pd packetDecoder, version int1
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.GroupID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]InitializeStateData, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block InitializeStateData
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -176,6 +176,5 @@ func (r *InitializeShareGroupStateRequest) IsValidVersion() bool {
 }
 
 func (r *InitializeShareGroupStateRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
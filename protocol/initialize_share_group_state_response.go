@@ -32,19 +32,19 @@ func (p *PartitionResult_InitializeShareGroupStateResponse) encode(pe packetEnco
 func (p *PartitionResult_InitializeShareGroupStateResponse) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.Partition, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partition", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -81,26 +81,26 @@ func (r *InitializeStateResult) encode(pe packetEncoder, version int16) (err err
 func (r *InitializeStateResult) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.TopicID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicID", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		r.Partitions = make([]PartitionResult_InitializeShareGroupStateResponse, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionResult_InitializeShareGroupStateResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			r.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -132,21 +132,21 @@ func (r *InitializeShareGroupStateResponse) decode(pd packetDecoder, version int
 	pd = FlexibleDecoderFrom(pd)
 	var numResults int
 	if numResults, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Results", err)
 	}
 	if numResults > 0 {
 		r.Results = make([]InitializeStateResult, numResults)
 		for i := 0; i < numResults; i++ {
 			var block InitializeStateResult
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Results", i, err)
 			}
 			r.Results[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -168,6 +168,5 @@ func (r *InitializeShareGroupStateResponse) IsValidVersion() bool {
 }
 
 func (r *InitializeShareGroupStateResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -30,16 +30,16 @@ func (p *JoinGroupRequestProtocol) encode(pe packetEncoder, version int16) (err
 func (p *JoinGroupRequestProtocol) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if p.Metadata, err = pd.getBytes(); err != nil {
-		return err
+		return wrapFieldError(pd, "Metadata", err)
 	}
 
 	if p.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -121,43 +121,43 @@ func (r *JoinGroupRequest) decode(pd packetDecoder, version int16) (err error) {
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.GroupID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	if r.SessionTimeoutMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "SessionTimeoutMs", err)
 	}
 
 	if r.Version >= 1 {
 		if r.RebalanceTimeoutMs, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "RebalanceTimeoutMs", err)
 		}
 	}
 
 	if r.MemberID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberID", err)
 	}
 
 	if r.Version >= 5 {
 		if r.GroupInstanceID, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "GroupInstanceID", err)
 		}
 	}
 
 	if r.ProtocolType, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProtocolType", err)
 	}
 
 	var numProtocols int
 	if numProtocols, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Protocols", err)
 	}
 	if numProtocols > 0 {
 		r.Protocols = make([]JoinGroupRequestProtocol, numProtocols)
 		for i := 0; i < numProtocols; i++ {
 			var block JoinGroupRequestProtocol
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Protocols", i, err)
 			}
 			r.Protocols[i] = block
 		}
@@ -165,13 +165,13 @@ func (r *JoinGroupRequest) decode(pd packetDecoder, version int16) (err error) {
 
 	if r.Version >= 8 {
 		if r.Reason, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Reason", err)
 		}
 	}
 
 	if r.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -197,6 +197,5 @@ func (r *JoinGroupRequest) IsValidVersion() bool {
 }
 
 func (r *JoinGroupRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -43,22 +43,22 @@ func (m *JoinGroupResponseMember) encode(pe packetEncoder, version int16) (err e
 func (m *JoinGroupResponseMember) decode(pd packetDecoder, version int16) (err error) {
 	m.Version = version
 	if m.MemberID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberID", err)
 	}
 
 	if m.Version >= 5 {
 		if m.GroupInstanceID, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "GroupInstanceID", err)
 		}
 	}
 
 	if m.Metadata, err = pd.getBytes(); err != nil {
-		return err
+		return wrapFieldError(pd, "Metadata", err)
 	}
 
 	if m.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -152,61 +152,61 @@ func (r *JoinGroupResponse) decode(pd packetDecoder, version int16) (err error)
 	}
 	if r.Version >= 2 {
 		if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "ThrottleTimeMs", err)
 		}
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.GenerationID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "GenerationID", err)
 	}
 
 	if r.Version >= 7 {
 		if r.ProtocolType, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "ProtocolType", err)
 		}
 	}
 
 	if r.Version >= 7 {
 		if r.ProtocolName, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "ProtocolName", err)
 		}
 	} else {
 		def := ""
 		r.ProtocolName = &def
 
 		if *r.ProtocolName, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "ProtocolName", err)
 		}
 	}
 
 	if r.Leader, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Leader", err)
 	}
 
 	if r.Version >= 9 {
 		if r.SkipAssignment, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "SkipAssignment", err)
 		}
 	}
 
 	if r.MemberID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberID", err)
 	}
 
 	var numMembers int
 	if numMembers, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Members", err)
 	}
 	if numMembers > 0 {
 		r.Members = make([]JoinGroupResponseMember, numMembers)
 		for i := 0; i < numMembers; i++ {
 			var block JoinGroupResponseMember
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Members", i, err)
 			}
 			r.Members[i] = block
 		}
@@ -214,7 +214,7 @@ func (r *JoinGroupResponse) decode(pd packetDecoder, version int16) (err error)
 
 	if r.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -240,8 +240,7 @@ func (r *JoinGroupResponse) IsValidVersion() bool {
 }
 
 func (r *JoinGroupResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *JoinGroupResponse) throttleTime() time.Duration {
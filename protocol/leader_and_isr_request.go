@@ -89,65 +89,65 @@ func (l *LeaderAndIsrPartitionState_LeaderAndIsrRequest) decode(pd packetDecoder
 	l.Version = version
 	if l.Version >= 0 && l.Version <= 1 {
 		if l.TopicName, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicName", err)
 		}
 	}
 
 	if l.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if l.ControllerEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ControllerEpoch", err)
 	}
 
 	if l.Leader, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Leader", err)
 	}
 
 	if l.LeaderEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderEpoch", err)
 	}
 
 	if l.Isr, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Isr", err)
 	}
 
 	if l.PartitionEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionEpoch", err)
 	}
 
 	if l.Replicas, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Replicas", err)
 	}
 
 	if l.Version >= 3 {
 		if l.AddingReplicas, err = pd.getInt32Array(); err != nil {
-			return err
+			return wrapFieldError(pd, "AddingReplicas", err)
 		}
 	}
 
 	if l.Version >= 3 {
 		if l.RemovingReplicas, err = pd.getInt32Array(); err != nil {
-			return err
+			return wrapFieldError(pd, "RemovingReplicas", err)
 		}
 	}
 
 	if l.Version >= 1 {
 		if l.IsNew, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "IsNew", err)
 		}
 	}
 
 	if l.Version >= 6 {
 		if l.LeaderRecoveryState, err = pd.getInt8(); err != nil {
-			return err
+			return wrapFieldError(pd, "LeaderRecoveryState", err)
 		}
 	}
 
 	if l.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -200,27 +200,27 @@ func (t *LeaderAndIsrTopicState) decode(pd packetDecoder, version int16) (err er
 	t.Version = version
 	if t.Version >= 2 {
 		if t.TopicName, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicName", err)
 		}
 	}
 
 	if t.Version >= 5 {
 		if t.TopicID, err = pd.getUUID(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicID", err)
 		}
 	}
 
 	if t.Version >= 2 {
 		var numPartitionStates int
 		if numPartitionStates, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "PartitionStates", err)
 		}
 		if numPartitionStates > 0 {
 			t.PartitionStates = make([]LeaderAndIsrPartitionState_LeaderAndIsrRequest, numPartitionStates)
 			for i := 0; i < numPartitionStates; i++ {
 				var block LeaderAndIsrPartitionState_LeaderAndIsrRequest
 				if err := block.decode(pd, t.Version); err != nil {
-					return err
+					return wrapElementError(pd, "PartitionStates", i, err)
 				}
 				t.PartitionStates[i] = block
 			}
@@ -229,7 +229,7 @@ func (t *LeaderAndIsrTopicState) decode(pd packetDecoder, version int16) (err er
 
 	if t.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -266,20 +266,20 @@ func (l *LeaderAndIsrLiveLeader) encode(pe packetEncoder, version int16) (err er
 func (l *LeaderAndIsrLiveLeader) decode(pd packetDecoder, version int16) (err error) {
 	l.Version = version
 	if l.BrokerID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "BrokerID", err)
 	}
 
 	if l.HostName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "HostName", err)
 	}
 
 	if l.Port, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Port", err)
 	}
 
 	if l.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -369,42 +369,42 @@ func (r *LeaderAndIsrRequest) decode(pd packetDecoder, version int16) (err error
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ControllerID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ControllerID", err)
 	}
 
 	if r.Version >= 7 {
 		if r.isKRaftController, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "isKRaftController", err)
 		}
 	}
 
 	if r.ControllerEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ControllerEpoch", err)
 	}
 
 	if r.Version >= 2 {
 		if r.BrokerEpoch, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "BrokerEpoch", err)
 		}
 	}
 
 	if r.Version >= 5 {
 		if r.Type, err = pd.getInt8(); err != nil {
-			return err
+			return wrapFieldError(pd, "Type", err)
 		}
 	}
 
 	if r.Version >= 0 && r.Version <= 1 {
 		var numUngroupedPartitionStates int
 		if numUngroupedPartitionStates, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "UngroupedPartitionStates", err)
 		}
 		if numUngroupedPartitionStates > 0 {
 			r.UngroupedPartitionStates = make([]LeaderAndIsrPartitionState_LeaderAndIsrRequest, numUngroupedPartitionStates)
 			for i := 0; i < numUngroupedPartitionStates; i++ {
 				var block LeaderAndIsrPartitionState_LeaderAndIsrRequest
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "UngroupedPartitionStates", i, err)
 				}
 				r.UngroupedPartitionStates[i] = block
 			}
@@ -414,14 +414,14 @@ func (r *LeaderAndIsrRequest) decode(pd packetDecoder, version int16) (err error
 	if r.Version >= 2 {
 		var numTopicStates int
 		if numTopicStates, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicStates", err)
 		}
 		if numTopicStates > 0 {
 			r.TopicStates = make([]LeaderAndIsrTopicState, numTopicStates)
 			for i := 0; i < numTopicStates; i++ {
 				var block LeaderAndIsrTopicState
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "TopicStates", i, err)
 				}
 				r.TopicStates[i] = block
 			}
@@ -430,14 +430,14 @@ func (r *LeaderAndIsrRequest) decode(pd packetDecoder, version int16) (err error
 
 	var numLiveLeaders int
 	if numLiveLeaders, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "LiveLeaders", err)
 	}
 	if numLiveLeaders > 0 {
 		r.LiveLeaders = make([]LeaderAndIsrLiveLeader, numLiveLeaders)
 		for i := 0; i < numLiveLeaders; i++ {
 			var block LeaderAndIsrLiveLeader
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "LiveLeaders", i, err)
 			}
 			r.LiveLeaders[i] = block
 		}
@@ -445,7 +445,7 @@ func (r *LeaderAndIsrRequest) decode(pd packetDecoder, version int16) (err error
 
 	if r.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -471,6 +471,5 @@ func (r *LeaderAndIsrRequest) IsValidVersion() bool {
 }
 
 func (r *LeaderAndIsrRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
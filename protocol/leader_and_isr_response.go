@@ -37,21 +37,21 @@ func (l *LeaderAndIsrPartitionError_LeaderAndIsrResponse) decode(pd packetDecode
 	l.Version = version
 	if l.Version >= 0 && l.Version <= 4 {
 		if l.TopicName, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicName", err)
 		}
 	}
 
 	if l.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if l.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if l.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -96,21 +96,21 @@ func (t *LeaderAndIsrTopicError) decode(pd packetDecoder, version int16) (err er
 	t.Version = version
 	if t.Version >= 5 {
 		if t.TopicID, err = pd.getUUID(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicID", err)
 		}
 	}
 
 	if t.Version >= 5 {
 		var numPartitionErrors int
 		if numPartitionErrors, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "PartitionErrors", err)
 		}
 		if numPartitionErrors > 0 {
 			t.PartitionErrors = make([]LeaderAndIsrPartitionError_LeaderAndIsrResponse, numPartitionErrors)
 			for i := 0; i < numPartitionErrors; i++ {
 				var block LeaderAndIsrPartitionError_LeaderAndIsrResponse
 				if err := block.decode(pd, t.Version); err != nil {
-					return err
+					return wrapElementError(pd, "PartitionErrors", i, err)
 				}
 				t.PartitionErrors[i] = block
 			}
@@ -119,7 +119,7 @@ func (t *LeaderAndIsrTopicError) decode(pd packetDecoder, version int16) (err er
 
 	if t.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -176,20 +176,20 @@ func (r *LeaderAndIsrResponse) decode(pd packetDecoder, version int16) (err erro
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.Version >= 0 && r.Version <= 4 {
 		var numPartitionErrors int
 		if numPartitionErrors, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "PartitionErrors", err)
 		}
 		if numPartitionErrors > 0 {
 			r.PartitionErrors = make([]LeaderAndIsrPartitionError_LeaderAndIsrResponse, numPartitionErrors)
 			for i := 0; i < numPartitionErrors; i++ {
 				var block LeaderAndIsrPartitionError_LeaderAndIsrResponse
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "PartitionErrors", i, err)
 				}
 				r.PartitionErrors[i] = block
 			}
@@ -199,14 +199,14 @@ func (r *LeaderAndIsrResponse) decode(pd packetDecoder, version int16) (err erro
 	if r.Version >= 5 {
 		var numTopics int
 		if numTopics, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Topics", err)
 		}
 		if numTopics > 0 {
 			r.Topics = make([]LeaderAndIsrTopicError, numTopics)
 			for i := 0; i < numTopics; i++ {
 				var block LeaderAndIsrTopicError
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Topics", i, err)
 				}
 				r.Topics[i] = block
 			}
@@ -215,7 +215,7 @@ func (r *LeaderAndIsrResponse) decode(pd packetDecoder, version int16) (err erro
 
 	if r.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -241,6 +241,5 @@ func (r *LeaderAndIsrResponse) IsValidVersion() bool {
 }
 
 func (r *LeaderAndIsrResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -43,25 +43,25 @@ func (m *MemberIdentity) decode(pd packetDecoder, version int16) (err error) {
 	m.Version = version
 	if m.Version >= 3 {
 		if m.MemberID, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "MemberID", err)
 		}
 	}
 
 	if m.Version >= 3 {
 		if m.GroupInstanceID, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "GroupInstanceID", err)
 		}
 	}
 
 	if m.Version >= 5 {
 		if m.Reason, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Reason", err)
 		}
 	}
 
 	if m.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -115,26 +115,26 @@ func (r *LeaveGroupRequest) decode(pd packetDecoder, version int16) (err error)
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.GroupID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	if r.Version >= 0 && r.Version <= 2 {
 		if r.MemberID, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "MemberID", err)
 		}
 	}
 
 	if r.Version >= 3 {
 		var numMembers int
 		if numMembers, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Members", err)
 		}
 		if numMembers > 0 {
 			r.Members = make([]MemberIdentity, numMembers)
 			for i := 0; i < numMembers; i++ {
 				var block MemberIdentity
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Members", i, err)
 				}
 				r.Members[i] = block
 			}
@@ -143,7 +143,7 @@ func (r *LeaveGroupRequest) decode(pd packetDecoder, version int16) (err error)
 
 	if r.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -169,6 +169,5 @@ func (r *LeaveGroupRequest) IsValidVersion() bool {
 }
 
 func (r *LeaveGroupRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
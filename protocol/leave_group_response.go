@@ -43,25 +43,25 @@ func (m *MemberResponse) decode(pd packetDecoder, version int16) (err error) {
 	m.Version = version
 	if m.Version >= 3 {
 		if m.MemberID, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "MemberID", err)
 		}
 	}
 
 	if m.Version >= 3 {
 		if m.GroupInstanceID, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "GroupInstanceID", err)
 		}
 	}
 
 	if m.Version >= 3 {
 		if m.ErrorCode, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "ErrorCode", err)
 		}
 	}
 
 	if m.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -112,25 +112,25 @@ func (r *LeaveGroupResponse) decode(pd packetDecoder, version int16) (err error)
 	}
 	if r.Version >= 1 {
 		if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "ThrottleTimeMs", err)
 		}
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.Version >= 3 {
 		var numMembers int
 		if numMembers, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Members", err)
 		}
 		if numMembers > 0 {
 			r.Members = make([]MemberResponse, numMembers)
 			for i := 0; i < numMembers; i++ {
 				var block MemberResponse
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Members", i, err)
 				}
 				r.Members[i] = block
 			}
@@ -139,7 +139,7 @@ func (r *LeaveGroupResponse) decode(pd packetDecoder, version int16) (err error)
 
 	if r.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -165,8 +165,7 @@ func (r *LeaveGroupResponse) IsValidVersion() bool {
 }
 
 func (r *LeaveGroupResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *LeaveGroupResponse) throttleTime() time.Duration {
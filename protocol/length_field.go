@@ -0,0 +1,37 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// lengthField is a push encoder/decoder for int32 length prefixes, like the batch length of a record batch.
+type lengthField struct {
+	startOffset int
+}
+
+func newLengthField() *lengthField {
+	return &lengthField{}
+}
+
+func (l *lengthField) saveOffset(in int) {
+	l.startOffset = in
+}
+
+func (l *lengthField) reserveLength() int {
+	return 4
+}
+
+func (l *lengthField) run(curOffset int, buf []byte) error {
+	binary.BigEndian.PutUint32(buf[l.startOffset:], uint32(curOffset-l.startOffset-4))
+	return nil
+}
+
+func (l *lengthField) check(curOffset int, buf []byte) error {
+	length := int32(binary.BigEndian.Uint32(buf[l.startOffset:]))
+	if int32(curOffset-l.startOffset-4) != length {
+		return fmt.Errorf("length field invalid, expected %d got %d", length, curOffset-l.startOffset-4)
+	}
+
+	return nil
+}
@@ -16,7 +16,7 @@ func (r *ListClientMetricsResourcesRequest) decode(pd packetDecoder, version int
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -38,6 +38,5 @@ func (r *ListClientMetricsResourcesRequest) IsValidVersion() bool {
 }
 
 func (r *ListClientMetricsResourcesRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -24,11 +24,11 @@ func (c *ClientMetricsResource) encode(pe packetEncoder, version int16) (err err
 func (c *ClientMetricsResource) decode(pd packetDecoder, version int16) (err error) {
 	c.Version = version
 	if c.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -67,30 +67,30 @@ func (r *ListClientMetricsResourcesResponse) decode(pd packetDecoder, version in
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	var numClientMetricsResources int
 	if numClientMetricsResources, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClientMetricsResources", err)
 	}
 	if numClientMetricsResources > 0 {
 		r.ClientMetricsResources = make([]ClientMetricsResource, numClientMetricsResources)
 		for i := 0; i < numClientMetricsResources; i++ {
 			var block ClientMetricsResource
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "ClientMetricsResources", i, err)
 			}
 			r.ClientMetricsResources[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -112,8 +112,7 @@ func (r *ListClientMetricsResourcesResponse) IsValidVersion() bool {
 }
 
 func (r *ListClientMetricsResourcesResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *ListClientMetricsResourcesResponse) throttleTime() time.Duration {
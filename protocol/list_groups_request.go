@@ -39,19 +39,19 @@ func (r *ListGroupsRequest) decode(pd packetDecoder, version int16) (err error)
 	}
 	if r.Version >= 4 {
 		if r.StatesFilter, err = pd.getStringArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "StatesFilter", err)
 		}
 	}
 
 	if r.Version >= 5 {
 		if r.TypesFilter, err = pd.getStringArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TypesFilter", err)
 		}
 	}
 
 	if r.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -77,6 +77,5 @@ func (r *ListGroupsRequest) IsValidVersion() bool {
 }
 
 func (r *ListGroupsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -48,28 +48,28 @@ func (g *ListedGroup) encode(pe packetEncoder, version int16) (err error) {
 func (g *ListedGroup) decode(pd packetDecoder, version int16) (err error) {
 	g.Version = version
 	if g.GroupID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	if g.ProtocolType, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProtocolType", err)
 	}
 
 	if g.Version >= 4 {
 		if g.GroupState, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "GroupState", err)
 		}
 	}
 
 	if g.Version >= 5 {
 		if g.GroupType, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "GroupType", err)
 		}
 	}
 
 	if g.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -118,24 +118,24 @@ func (r *ListGroupsResponse) decode(pd packetDecoder, version int16) (err error)
 	}
 	if r.Version >= 1 {
 		if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "ThrottleTimeMs", err)
 		}
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	var numGroups int
 	if numGroups, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Groups", err)
 	}
 	if numGroups > 0 {
 		r.Groups = make([]ListedGroup, numGroups)
 		for i := 0; i < numGroups; i++ {
 			var block ListedGroup
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Groups", i, err)
 			}
 			r.Groups[i] = block
 		}
@@ -143,7 +143,7 @@ func (r *ListGroupsResponse) decode(pd packetDecoder, version int16) (err error)
 
 	if r.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -169,8 +169,7 @@ func (r *ListGroupsResponse) IsValidVersion() bool {
 }
 
 func (r *ListGroupsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *ListGroupsResponse) throttleTime() time.Duration {
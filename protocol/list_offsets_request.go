@@ -32,22 +32,22 @@ func (p *ListOffsetsPartition) encode(pe packetEncoder, version int16) (err erro
 func (p *ListOffsetsPartition) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.Version >= 4 {
 		if p.CurrentLeaderEpoch, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "CurrentLeaderEpoch", err)
 		}
 	}
 
 	if p.Timestamp, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "Timestamp", err)
 	}
 
 	if p.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -87,19 +87,19 @@ func (t *ListOffsetsTopic) encode(pe packetEncoder, version int16) (err error) {
 func (t *ListOffsetsTopic) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]ListOffsetsPartition, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block ListOffsetsPartition
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
@@ -107,7 +107,7 @@ func (t *ListOffsetsTopic) decode(pd packetDecoder, version int16) (err error) {
 
 	if t.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -161,25 +161,25 @@ func (r *ListOffsetsRequest) decode(pd packetDecoder, version int16) (err error)
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ReplicaID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ReplicaID", err)
 	}
 
 	if r.Version >= 2 {
 		if r.IsolationLevel, err = pd.getInt8(); err != nil {
-			return err
+			return wrapFieldError(pd, "IsolationLevel", err)
 		}
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]ListOffsetsTopic, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block ListOffsetsTopic
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -187,13 +187,13 @@ func (r *ListOffsetsRequest) decode(pd packetDecoder, version int16) (err error)
 
 	if r.Version >= 10 {
 		if r.TimeoutMs, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "TimeoutMs", err)
 		}
 	}
 
 	if r.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -219,6 +219,5 @@ func (r *ListOffsetsRequest) IsValidVersion() bool {
 }
 
 func (r *ListOffsetsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
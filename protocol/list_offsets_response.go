@@ -46,34 +46,34 @@ func (p *ListOffsetsPartitionResponse) encode(pe packetEncoder, version int16) (
 func (p *ListOffsetsPartitionResponse) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.Version >= 1 {
 		if p.Timestamp, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "Timestamp", err)
 		}
 	}
 
 	if p.Version >= 1 {
 		if p.Offset, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "Offset", err)
 		}
 	}
 
 	if p.Version >= 4 {
 		if p.LeaderEpoch, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "LeaderEpoch", err)
 		}
 	}
 
 	if p.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -113,19 +113,19 @@ func (t *ListOffsetsTopicResponse) encode(pe packetEncoder, version int16) (err
 func (t *ListOffsetsTopicResponse) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]ListOffsetsPartitionResponse, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block ListOffsetsPartitionResponse
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
@@ -133,7 +133,7 @@ func (t *ListOffsetsTopicResponse) decode(pd packetDecoder, version int16) (err
 
 	if t.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -178,20 +178,20 @@ func (r *ListOffsetsResponse) decode(pd packetDecoder, version int16) (err error
 	}
 	if r.Version >= 2 {
 		if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "ThrottleTimeMs", err)
 		}
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]ListOffsetsTopicResponse, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block ListOffsetsTopicResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -199,7 +199,7 @@ func (r *ListOffsetsResponse) decode(pd packetDecoder, version int16) (err error
 
 	if r.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -225,8 +225,7 @@ func (r *ListOffsetsResponse) IsValidVersion() bool {
 }
 
 func (r *ListOffsetsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *ListOffsetsResponse) throttleTime() time.Duration {
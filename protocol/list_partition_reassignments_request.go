@@ -28,15 +28,15 @@ func (t *ListPartitionReassignmentsTopics) encode(pe packetEncoder, version int1
 func (t *ListPartitionReassignmentsTopics) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if t.PartitionIndexes, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndexes", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -71,26 +71,26 @@ func (r *ListPartitionReassignmentsRequest) decode(pd packetDecoder, version int
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.TimeoutMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "TimeoutMs", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]ListPartitionReassignmentsTopics, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block ListPartitionReassignmentsTopics
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -112,6 +112,5 @@ func (r *ListPartitionReassignmentsRequest) IsValidVersion() bool {
 }
 
 func (r *ListPartitionReassignmentsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -40,23 +40,23 @@ func (p *OngoingPartitionReassignment) encode(pe packetEncoder, version int16) (
 func (p *OngoingPartitionReassignment) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.Replicas, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Replicas", err)
 	}
 
 	if p.AddingReplicas, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "AddingReplicas", err)
 	}
 
 	if p.RemovingReplicas, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "RemovingReplicas", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -93,26 +93,26 @@ func (t *OngoingTopicReassignment) encode(pe packetEncoder, version int16) (err
 func (t *OngoingTopicReassignment) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]OngoingPartitionReassignment, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block OngoingPartitionReassignment
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -157,34 +157,34 @@ func (r *ListPartitionReassignmentsResponse) decode(pd packetDecoder, version in
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]OngoingTopicReassignment, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block OngoingTopicReassignment
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -206,8 +206,7 @@ func (r *ListPartitionReassignmentsResponse) IsValidVersion() bool {
 }
 
 func (r *ListPartitionReassignmentsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *ListPartitionReassignmentsResponse) throttleTime() time.Duration {
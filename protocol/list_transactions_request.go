@@ -34,21 +34,21 @@ func (r *ListTransactionsRequest) decode(pd packetDecoder, version int16) (err e
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.StateFilters, err = pd.getStringArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "StateFilters", err)
 	}
 
 	if r.ProducerIdFilters, err = pd.getInt64Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProducerIdFilters", err)
 	}
 
 	if r.Version >= 1 {
 		if r.DurationFilter, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "DurationFilter", err)
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -70,6 +70,5 @@ func (r *ListTransactionsRequest) IsValidVersion() bool {
 }
 
 func (r *ListTransactionsRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
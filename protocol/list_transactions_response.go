@@ -34,19 +34,19 @@ func (t *TransactionState_ListTransactionsResponse) encode(pe packetEncoder, ver
 func (t *TransactionState_ListTransactionsResponse) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.TransactionalID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TransactionalID", err)
 	}
 
 	if t.ProducerID, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProducerID", err)
 	}
 
 	if t.TransactionState, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TransactionState", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -91,34 +91,34 @@ func (r *ListTransactionsResponse) decode(pd packetDecoder, version int16) (err
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.UnknownStateFilters, err = pd.getStringArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "UnknownStateFilters", err)
 	}
 
 	var numTransactionStates int
 	if numTransactionStates, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "TransactionStates", err)
 	}
 	if numTransactionStates > 0 {
 		r.TransactionStates = make([]TransactionState_ListTransactionsResponse, numTransactionStates)
 		for i := 0; i < numTransactionStates; i++ {
 			var block TransactionState_ListTransactionsResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "TransactionStates", i, err)
 			}
 			r.TransactionStates[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -140,8 +140,7 @@ func (r *ListTransactionsResponse) IsValidVersion() bool {
 }
 
 func (r *ListTransactionsResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *ListTransactionsResponse) throttleTime() time.Duration {
@@ -0,0 +1,534 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+
+	uuid "github.com/google/uuid"
+)
+
+// The metadata records are the records of the KRaft metadata log, which Kafka defines in its metadata module
+// rather than as API messages. They are written by hand, since the generator only knows the messages of the APIs.
+// https://github.com/apache/kafka/tree/trunk/metadata/src/main/resources/common/metadata
+
+// metadataRecordFrameVersion is the version of the frame which precedes every metadata record in the record value.
+const metadataRecordFrameVersion = 1
+
+// The types of the metadata records, which are their API keys in the frame.
+const (
+	RegisterBrokerRecordType   int16 = 0
+	UnregisterBrokerRecordType int16 = 1
+	TopicRecordType            int16 = 2
+	PartitionRecordType        int16 = 3
+	FenceBrokerRecordType      int16 = 7
+	UnfenceBrokerRecordType    int16 = 8
+)
+
+// ErrUnknownMetadataRecordType is returned when decoding a metadata record of a type or version the broker doesn't know.
+var ErrUnknownMetadataRecordType = errors.New("unknown metadata record type")
+
+// MetadataRecord is a record of the KRaft metadata log.
+type MetadataRecord interface {
+	// RecordType returns the type of the record, its API key in the frame
+	RecordType() int16
+	// RecordVersion returns the version the record is encoded with
+	RecordVersion() int16
+	encode(pe packetEncoder) error
+	decode(pd packetDecoder, version int16) error
+}
+
+// metadataRecordConstructors returns an empty record of each type, with the highest version the broker can decode.
+var metadataRecordConstructors = map[int16]struct {
+	newRecord  func() MetadataRecord
+	maxVersion int16
+}{
+	RegisterBrokerRecordType:   {func() MetadataRecord { return &RegisterBrokerRecord{} }, 1},
+	TopicRecordType:            {func() MetadataRecord { return &TopicRecord{} }, 0},
+	PartitionRecordType:        {func() MetadataRecord { return &PartitionRecord{} }, 0},
+	UnregisterBrokerRecordType: {func() MetadataRecord { return &UnregisterBrokerRecord{} }, 0},
+	FenceBrokerRecordType:      {func() MetadataRecord { return &FenceBrokerRecord{} }, 0},
+	UnfenceBrokerRecordType:    {func() MetadataRecord { return &UnfenceBrokerRecord{} }, 0},
+}
+
+// EncodeMetadataRecord encodes the record with its frame, as the value of a record in the metadata log.
+func EncodeMetadataRecord(record MetadataRecord) ([]byte, error) {
+	return Encode(metadataRecordFrame{record: record})
+}
+
+// DecodeMetadataRecord decodes the value of a record in the metadata log into the record type of its frame.
+func DecodeMetadataRecord(value []byte) (MetadataRecord, error) {
+	frame := metadataRecordFrame{}
+	if err := Decode(value, &frame); err != nil {
+		return nil, err
+	}
+
+	return frame.record, nil
+}
+
+// metadataRecordFrame is the frame of a metadata record: the frame version, the type and the version of the record,
+// followed by the record in the flexible format.
+type metadataRecordFrame struct {
+	record MetadataRecord
+}
+
+func (f metadataRecordFrame) encode(pe packetEncoder) error {
+	pe.putUVarint(metadataRecordFrameVersion)
+	pe.putUVarint(uint64(f.record.RecordType()))
+	pe.putUVarint(uint64(f.record.RecordVersion()))
+
+	return f.record.encode(FlexibleEncoderFrom(pe))
+}
+
+func (f *metadataRecordFrame) decode(pd packetDecoder) error {
+	frameVersion, err := pd.getUVarint()
+	if err != nil {
+		return err
+	}
+	if frameVersion != metadataRecordFrameVersion {
+		return fmt.Errorf("unsupported metadata record frame version %d", frameVersion)
+	}
+
+	recordType, err := pd.getUVarint()
+	if err != nil {
+		return err
+	}
+	version, err := pd.getUVarint()
+	if err != nil {
+		return err
+	}
+
+	constructor, ok := metadataRecordConstructors[int16(recordType)]
+	if !ok || int16(version) > constructor.maxVersion {
+		return fmt.Errorf("%w: type %d version %d", ErrUnknownMetadataRecordType, recordType, version)
+	}
+
+	f.record = constructor.newRecord()
+	return f.record.decode(FlexibleDecoderFrom(pd), int16(version))
+}
+
+// BrokerEndpoint is a listener of a registered broker.
+type BrokerEndpoint struct {
+	// Name contains the name of the listener.
+	Name string
+	// Host contains the hostname of the listener.
+	Host string
+	// Port contains the port of the listener.
+	Port uint16
+	// SecurityProtocol contains the security protocol of the listener.
+	SecurityProtocol int16
+}
+
+// BrokerFeature is a feature supported by a registered broker, with the range of its supported levels.
+type BrokerFeature struct {
+	// Name contains the feature name.
+	Name string
+	// MinSupportedVersion contains the minimum supported feature level.
+	MinSupportedVersion int16
+	// MaxSupportedVersion contains the maximum supported feature level.
+	MaxSupportedVersion int16
+}
+
+// RegisterBrokerRecord registers a broker in the cluster.
+type RegisterBrokerRecord struct {
+	// Version defines the version of the record to use for encode and decode
+	Version int16
+	// BrokerID contains the broker id.
+	BrokerID int32
+	// IncarnationID contains the incarnation ID of the broker process, which changes when the broker restarts.
+	IncarnationID uuid.UUID
+	// BrokerEpoch contains the broker epoch assigned by the controller.
+	BrokerEpoch int64
+	// EndPoints contains the endpoints that can be used to communicate with this broker.
+	EndPoints []BrokerEndpoint
+	// Features contains the features on this broker.
+	Features []BrokerFeature
+	// Rack contains the broker rack.
+	Rack *string
+	// Fenced contains true if the broker is fenced.
+	Fenced bool
+	// InControlledShutdown contains true if the broker is in controlled shutdown.
+	InControlledShutdown bool
+}
+
+func (r *RegisterBrokerRecord) RecordType() int16 {
+	return RegisterBrokerRecordType
+}
+
+func (r *RegisterBrokerRecord) RecordVersion() int16 {
+	return r.Version
+}
+
+func (r *RegisterBrokerRecord) encode(pe packetEncoder) error {
+	pe.putInt32(r.BrokerID)
+
+	if err := pe.putUUID(r.IncarnationID); err != nil {
+		return err
+	}
+
+	pe.putInt64(r.BrokerEpoch)
+
+	if err := pe.putArrayLength(len(r.EndPoints)); err != nil {
+		return err
+	}
+	for _, e := range r.EndPoints {
+		if err := pe.putString(e.Name); err != nil {
+			return err
+		}
+		if err := pe.putString(e.Host); err != nil {
+			return err
+		}
+		pe.putUint16(e.Port)
+		pe.putInt16(e.SecurityProtocol)
+		pe.putUVarint(0)
+	}
+
+	if err := pe.putArrayLength(len(r.Features)); err != nil {
+		return err
+	}
+	for _, f := range r.Features {
+		if err := pe.putString(f.Name); err != nil {
+			return err
+		}
+		pe.putInt16(f.MinSupportedVersion)
+		pe.putInt16(f.MaxSupportedVersion)
+		pe.putUVarint(0)
+	}
+
+	if err := pe.putNullableString(r.Rack); err != nil {
+		return err
+	}
+
+	pe.putBool(r.Fenced)
+
+	if r.Version >= 1 {
+		pe.putBool(r.InControlledShutdown)
+	}
+
+	pe.putUVarint(0)
+	return nil
+}
+
+func (r *RegisterBrokerRecord) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+	if r.BrokerID, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if r.IncarnationID, err = pd.getUUID(); err != nil {
+		return err
+	}
+
+	if r.BrokerEpoch, err = pd.getInt64(); err != nil {
+		return err
+	}
+
+	numEndPoints, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	r.EndPoints = make([]BrokerEndpoint, max(numEndPoints, 0))
+	for i := range r.EndPoints {
+		e := &r.EndPoints[i]
+		if e.Name, err = pd.getString(); err != nil {
+			return err
+		}
+		if e.Host, err = pd.getString(); err != nil {
+			return err
+		}
+		if e.Port, err = pd.getUint16(); err != nil {
+			return err
+		}
+		if e.SecurityProtocol, err = pd.getInt16(); err != nil {
+			return err
+		}
+		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
+			return err
+		}
+	}
+
+	numFeatures, err := pd.getArrayLength()
+	if err != nil {
+		return err
+	}
+	r.Features = make([]BrokerFeature, max(numFeatures, 0))
+	for i := range r.Features {
+		f := &r.Features[i]
+		if f.Name, err = pd.getString(); err != nil {
+			return err
+		}
+		if f.MinSupportedVersion, err = pd.getInt16(); err != nil {
+			return err
+		}
+		if f.MaxSupportedVersion, err = pd.getInt16(); err != nil {
+			return err
+		}
+		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
+			return err
+		}
+	}
+
+	if r.Rack, err = pd.getNullableString(); err != nil {
+		return err
+	}
+
+	if r.Fenced, err = pd.getBool(); err != nil {
+		return err
+	}
+
+	if r.Version >= 1 {
+		if r.InControlledShutdown, err = pd.getBool(); err != nil {
+			return err
+		}
+	}
+
+	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// TopicRecord creates a topic.
+type TopicRecord struct {
+	// Version defines the version of the record to use for encode and decode
+	Version int16
+	// Name contains the topic name.
+	Name string
+	// TopicID contains the unique ID of this topic.
+	TopicID uuid.UUID
+}
+
+func (r *TopicRecord) RecordType() int16 {
+	return TopicRecordType
+}
+
+func (r *TopicRecord) RecordVersion() int16 {
+	return r.Version
+}
+
+func (r *TopicRecord) encode(pe packetEncoder) error {
+	if err := pe.putString(r.Name); err != nil {
+		return err
+	}
+
+	if err := pe.putUUID(r.TopicID); err != nil {
+		return err
+	}
+
+	pe.putUVarint(0)
+	return nil
+}
+
+func (r *TopicRecord) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+	if r.Name, err = pd.getString(); err != nil {
+		return err
+	}
+
+	if r.TopicID, err = pd.getUUID(); err != nil {
+		return err
+	}
+
+	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// PartitionRecord creates a partition of a topic.
+type PartitionRecord struct {
+	// Version defines the version of the record to use for encode and decode
+	Version int16
+	// PartitionID contains the partition id.
+	PartitionID int32
+	// TopicID contains the unique ID of the topic of the partition.
+	TopicID uuid.UUID
+	// Replicas contains the replicas of this partition, sorted by preferred order.
+	Replicas []int32
+	// Isr contains the in-sync replicas of this partition.
+	Isr []int32
+	// RemovingReplicas contains the replicas that we are in the process of removing.
+	RemovingReplicas []int32
+	// AddingReplicas contains the replicas that we are in the process of adding.
+	AddingReplicas []int32
+	// Leader contains the lead replica, or -1 if there is no leader.
+	Leader int32
+	// LeaderEpoch contains the epoch of the partition leader.
+	LeaderEpoch int32
+	// PartitionEpoch contains an epoch that gets incremented each time we change anything in the partition.
+	PartitionEpoch int32
+}
+
+func (r *PartitionRecord) RecordType() int16 {
+	return PartitionRecordType
+}
+
+func (r *PartitionRecord) RecordVersion() int16 {
+	return r.Version
+}
+
+func (r *PartitionRecord) encode(pe packetEncoder) error {
+	pe.putInt32(r.PartitionID)
+
+	if err := pe.putUUID(r.TopicID); err != nil {
+		return err
+	}
+
+	// the replica lists are not nullable, so nil lists are written as empty ones
+	for _, replicas := range [][]int32{r.Replicas, r.Isr, r.RemovingReplicas, r.AddingReplicas} {
+		if replicas == nil {
+			replicas = []int32{}
+		}
+		if err := pe.putInt32Array(replicas); err != nil {
+			return err
+		}
+	}
+
+	pe.putInt32(r.Leader)
+	pe.putInt32(r.LeaderEpoch)
+	pe.putInt32(r.PartitionEpoch)
+
+	pe.putUVarint(0)
+	return nil
+}
+
+func (r *PartitionRecord) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+	if r.PartitionID, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if r.TopicID, err = pd.getUUID(); err != nil {
+		return err
+	}
+
+	for _, replicas := range []*[]int32{&r.Replicas, &r.Isr, &r.RemovingReplicas, &r.AddingReplicas} {
+		if *replicas, err = pd.getInt32Array(); err != nil {
+			return err
+		}
+	}
+
+	if r.Leader, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if r.LeaderEpoch, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if r.PartitionEpoch, err = pd.getInt32(); err != nil {
+		return err
+	}
+
+	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UnregisterBrokerRecord removes a broker from the cluster.
+type UnregisterBrokerRecord struct {
+	// Version defines the version of the record to use for encode and decode
+	Version int16
+	// BrokerID contains the broker id.
+	BrokerID int32
+	// BrokerEpoch contains the broker epoch.
+	BrokerEpoch int64
+}
+
+func (r *UnregisterBrokerRecord) RecordType() int16 {
+	return UnregisterBrokerRecordType
+}
+
+func (r *UnregisterBrokerRecord) RecordVersion() int16 {
+	return r.Version
+}
+
+func (r *UnregisterBrokerRecord) encode(pe packetEncoder) error {
+	return encodeBrokerEpoch(pe, r.BrokerID, r.BrokerEpoch)
+}
+
+func (r *UnregisterBrokerRecord) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+	r.BrokerID, r.BrokerEpoch, err = decodeBrokerEpoch(pd)
+	return err
+}
+
+// FenceBrokerRecord fences a broker, which can no longer lead partitions or be in their ISR.
+type FenceBrokerRecord struct {
+	// Version defines the version of the record to use for encode and decode
+	Version int16
+	// ID contains the broker ID to fence. It will be removed from all ISRs.
+	ID int32
+	// Epoch contains the epoch of the broker to fence.
+	Epoch int64
+}
+
+func (r *FenceBrokerRecord) RecordType() int16 {
+	return FenceBrokerRecordType
+}
+
+func (r *FenceBrokerRecord) RecordVersion() int16 {
+	return r.Version
+}
+
+func (r *FenceBrokerRecord) encode(pe packetEncoder) error {
+	return encodeBrokerEpoch(pe, r.ID, r.Epoch)
+}
+
+func (r *FenceBrokerRecord) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+	r.ID, r.Epoch, err = decodeBrokerEpoch(pd)
+	return err
+}
+
+// UnfenceBrokerRecord unfences a broker, once it caught up with the metadata log.
+type UnfenceBrokerRecord struct {
+	// Version defines the version of the record to use for encode and decode
+	Version int16
+	// ID contains the broker ID to unfence.
+	ID int32
+	// Epoch contains the epoch of the broker to unfence.
+	Epoch int64
+}
+
+func (r *UnfenceBrokerRecord) RecordType() int16 {
+	return UnfenceBrokerRecordType
+}
+
+func (r *UnfenceBrokerRecord) RecordVersion() int16 {
+	return r.Version
+}
+
+func (r *UnfenceBrokerRecord) encode(pe packetEncoder) error {
+	return encodeBrokerEpoch(pe, r.ID, r.Epoch)
+}
+
+func (r *UnfenceBrokerRecord) decode(pd packetDecoder, version int16) (err error) {
+	r.Version = version
+	r.ID, r.Epoch, err = decodeBrokerEpoch(pd)
+	return err
+}
+
+// encodeBrokerEpoch encodes the fields of the records which only reference a broker by ID and epoch.
+func encodeBrokerEpoch(pe packetEncoder, brokerID int32, brokerEpoch int64) error {
+	pe.putInt32(brokerID)
+	pe.putInt64(brokerEpoch)
+
+	pe.putUVarint(0)
+	return nil
+}
+
+func decodeBrokerEpoch(pd packetDecoder) (brokerID int32, brokerEpoch int64, err error) {
+	if brokerID, err = pd.getInt32(); err != nil {
+		return 0, 0, err
+	}
+
+	if brokerEpoch, err = pd.getInt64(); err != nil {
+		return 0, 0, err
+	}
+
+	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
+		return 0, 0, err
+	}
+	return brokerID, brokerEpoch, nil
+}
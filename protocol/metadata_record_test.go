@@ -0,0 +1,62 @@
+package protocol
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestMetadataRecord_RoundTrip(t *testing.T) {
+	rack := "rack-1"
+	topicID := uuid.New()
+	records := []MetadataRecord{
+		&RegisterBrokerRecord{
+			Version:       1,
+			BrokerID:      1,
+			IncarnationID: uuid.New(),
+			BrokerEpoch:   5,
+			EndPoints:     []BrokerEndpoint{{Name: "PLAINTEXT", Host: "localhost", Port: 9092, SecurityProtocol: 0}},
+			Features:      []BrokerFeature{{Name: "metadata.version", MinSupportedVersion: 1, MaxSupportedVersion: 20}},
+			Rack:          &rack,
+			Fenced:        true,
+		},
+		&TopicRecord{Name: "test-topic", TopicID: topicID},
+		&PartitionRecord{PartitionID: 2, TopicID: topicID, Replicas: []int32{1}, Isr: []int32{1}, Leader: 1},
+		&FenceBrokerRecord{ID: 1, Epoch: 5},
+		&UnfenceBrokerRecord{ID: 1, Epoch: 5},
+		&UnregisterBrokerRecord{BrokerID: 1, BrokerEpoch: 5},
+	}
+	for _, record := range records {
+		value, err := EncodeMetadataRecord(record)
+		if err != nil {
+			t.Fatalf("error encoding %T: %v", record, err)
+		}
+
+		// the frame holds the frame version, then the type and version of the record
+		if value[0] != metadataRecordFrameVersion || int16(value[1]) != record.RecordType() || int16(value[2]) != record.RecordVersion() {
+			t.Errorf("%T frame = %v", record, value[:3])
+		}
+
+		decoded, err := DecodeMetadataRecord(value)
+		if err != nil {
+			t.Fatalf("error decoding %T: %v", record, err)
+		}
+		if !reflect.DeepEqual(decoded, record) {
+			t.Errorf("decoded %+v, want %+v", decoded, record)
+		}
+	}
+}
+
+func TestDecodeMetadataRecord_Unknown(t *testing.T) {
+	// a FeatureLevelRecord, which the broker doesn't know
+	if _, err := DecodeMetadataRecord([]byte{1, 12, 0, 0}); !errors.Is(err, ErrUnknownMetadataRecordType) {
+		t.Errorf("error = %v, want %v", err, ErrUnknownMetadataRecordType)
+	}
+
+	// a TopicRecord of a future version
+	if _, err := DecodeMetadataRecord([]byte{1, 2, 9, 0}); !errors.Is(err, ErrUnknownMetadataRecordType) {
+		t.Errorf("error = %v, want %v", err, ErrUnknownMetadataRecordType)
+	}
+}
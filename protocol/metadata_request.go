@@ -47,26 +47,26 @@ func (t *MetadataRequestTopic) decode(pd packetDecoder, version int16) (err erro
 	t.Version = version
 	if t.Version >= 10 {
 		if t.TopicID, err = pd.getUUID(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicID", err)
 		}
 	}
 
 	if t.Version >= 10 {
 		if t.Name, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Name", err)
 		}
 	} else {
 		def := ""
 		t.Name = &def
 
 		if *t.Name, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Name", err)
 		}
 	}
 
 	if t.Version >= 9 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -75,7 +75,8 @@ func (t *MetadataRequestTopic) decode(pd packetDecoder, version int16) (err erro
 type MetadataRequest struct {
 	// Version defines the protocol version to use for encode and decode
 	Version int16
-	// Topics contains the topics to fetch metadata for.
+	// Topics contains the topics to fetch metadata for. Since v1, a nil list is sent as null, which requests all topics,
+	// and an empty list requests none of them.
 	Topics []MetadataRequestTopic
 	// AllowAutoTopicCreation contains a If this is true, the broker may auto-create topics that we requested which do not already exist, if it is configured to do so.
 	AllowAutoTopicCreation bool
@@ -89,7 +90,11 @@ func (r *MetadataRequest) encode(pe packetEncoder) (err error) {
 	if r.Version >= 9 {
 		pe = FlexibleEncoderFrom(pe)
 	}
-	if err := pe.putArrayLength(len(r.Topics)); err != nil {
+	numTopics := len(r.Topics)
+	if r.Topics == nil && r.Version >= 1 {
+		numTopics = -1
+	}
+	if err := pe.putArrayLength(numTopics); err != nil {
 		return err
 	}
 	for _, block := range r.Topics {
@@ -123,14 +128,15 @@ func (r *MetadataRequest) decode(pd packetDecoder, version int16) (err error) {
 	}
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
-	if numTopics > 0 {
+	// a null array stays nil, an empty one is kept apart from it
+	if numTopics >= 0 {
 		r.Topics = make([]MetadataRequestTopic, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block MetadataRequestTopic
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -138,25 +144,25 @@ func (r *MetadataRequest) decode(pd packetDecoder, version int16) (err error) {
 
 	if r.Version >= 4 {
 		if r.AllowAutoTopicCreation, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "AllowAutoTopicCreation", err)
 		}
 	}
 
 	if r.Version >= 8 && r.Version <= 10 {
 		if r.IncludeClusterAuthorizedOperations, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "IncludeClusterAuthorizedOperations", err)
 		}
 	}
 
 	if r.Version >= 8 {
 		if r.IncludeTopicAuthorizedOperations, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "IncludeTopicAuthorizedOperations", err)
 		}
 	}
 
 	if r.Version >= 9 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -182,6 +188,5 @@ func (r *MetadataRequest) IsValidVersion() bool {
 }
 
 func (r *MetadataRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
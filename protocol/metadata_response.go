@@ -46,26 +46,26 @@ func (b *MetadataResponseBroker) encode(pe packetEncoder, version int16) (err er
 func (b *MetadataResponseBroker) decode(pd packetDecoder, version int16) (err error) {
 	b.Version = version
 	if b.NodeID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "NodeID", err)
 	}
 
 	if b.Host, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Host", err)
 	}
 
 	if b.Port, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Port", err)
 	}
 
 	if b.Version >= 1 {
 		if b.Rack, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Rack", err)
 		}
 	}
 
 	if b.Version >= 9 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -126,40 +126,40 @@ func (p *MetadataResponsePartition) encode(pe packetEncoder, version int16) (err
 func (p *MetadataResponsePartition) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.LeaderID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderID", err)
 	}
 
 	if p.Version >= 7 {
 		if p.LeaderEpoch, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "LeaderEpoch", err)
 		}
 	}
 
 	if p.ReplicaNodes, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "ReplicaNodes", err)
 	}
 
 	if p.IsrNodes, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "IsrNodes", err)
 	}
 
 	if p.Version >= 5 {
 		if p.OfflineReplicas, err = pd.getInt32Array(); err != nil {
-			return err
+			return wrapFieldError(pd, "OfflineReplicas", err)
 		}
 	}
 
 	if p.Version >= 9 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -232,44 +232,44 @@ func (t *MetadataResponseTopic) encode(pe packetEncoder, version int16) (err err
 func (t *MetadataResponseTopic) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if t.Version >= 12 {
 		if t.Name, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Name", err)
 		}
 	} else {
 		def := ""
 		t.Name = &def
 
 		if *t.Name, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Name", err)
 		}
 	}
 
 	if t.Version >= 10 {
 		if t.TopicID, err = pd.getUUID(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicID", err)
 		}
 	}
 
 	if t.Version >= 1 {
 		if t.IsInternal, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "IsInternal", err)
 		}
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]MetadataResponsePartition, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block MetadataResponsePartition
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
@@ -277,13 +277,13 @@ func (t *MetadataResponseTopic) decode(pd packetDecoder, version int16) (err err
 
 	if t.Version >= 8 {
 		if t.TopicAuthorizedOperations, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicAuthorizedOperations", err)
 		}
 	}
 
 	if t.Version >= 9 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -365,20 +365,20 @@ func (r *MetadataResponse) decode(pd packetDecoder, version int16) (err error) {
 	}
 	if r.Version >= 3 {
 		if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "ThrottleTimeMs", err)
 		}
 	}
 
 	var numBrokers int
 	if numBrokers, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Brokers", err)
 	}
 	if numBrokers > 0 {
 		r.Brokers = make([]MetadataResponseBroker, numBrokers)
 		for i := 0; i < numBrokers; i++ {
 			var block MetadataResponseBroker
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Brokers", i, err)
 			}
 			r.Brokers[i] = block
 		}
@@ -386,26 +386,26 @@ func (r *MetadataResponse) decode(pd packetDecoder, version int16) (err error) {
 
 	if r.Version >= 2 {
 		if r.ClusterID, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "ClusterID", err)
 		}
 	}
 
 	if r.Version >= 1 {
 		if r.ControllerID, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "ControllerID", err)
 		}
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]MetadataResponseTopic, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block MetadataResponseTopic
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -413,19 +413,19 @@ func (r *MetadataResponse) decode(pd packetDecoder, version int16) (err error) {
 
 	if r.Version >= 8 && r.Version <= 10 {
 		if r.ClusterAuthorizedOperations, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "ClusterAuthorizedOperations", err)
 		}
 	}
 
 	if r.Version >= 13 {
 		if r.ErrorCode, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "ErrorCode", err)
 		}
 	}
 
 	if r.Version >= 9 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -451,8 +451,7 @@ func (r *MetadataResponse) IsValidVersion() bool {
 }
 
 func (r *MetadataResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *MetadataResponse) throttleTime() time.Duration {
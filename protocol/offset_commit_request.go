@@ -38,26 +38,26 @@ func (p *OffsetCommitRequestPartition) encode(pe packetEncoder, version int16) (
 func (p *OffsetCommitRequestPartition) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.CommittedOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "CommittedOffset", err)
 	}
 
 	if p.Version >= 6 {
 		if p.CommittedLeaderEpoch, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "CommittedLeaderEpoch", err)
 		}
 	}
 
 	if p.CommittedMetadata, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "CommittedMetadata", err)
 	}
 
 	if p.Version >= 8 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -97,19 +97,19 @@ func (t *OffsetCommitRequestTopic) encode(pe packetEncoder, version int16) (err
 func (t *OffsetCommitRequestTopic) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]OffsetCommitRequestPartition, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block OffsetCommitRequestPartition
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
@@ -117,7 +117,7 @@ func (t *OffsetCommitRequestTopic) decode(pd packetDecoder, version int16) (err
 
 	if t.Version >= 8 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -189,43 +189,43 @@ func (r *OffsetCommitRequest) decode(pd packetDecoder, version int16) (err error
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.GroupID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	if r.Version >= 1 {
 		if r.GenerationIdOrMemberEpoch, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "GenerationIdOrMemberEpoch", err)
 		}
 	}
 
 	if r.Version >= 1 {
 		if r.MemberID, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "MemberID", err)
 		}
 	}
 
 	if r.Version >= 7 {
 		if r.GroupInstanceID, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "GroupInstanceID", err)
 		}
 	}
 
 	if r.Version >= 2 && r.Version <= 4 {
 		if r.RetentionTimeMs, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "RetentionTimeMs", err)
 		}
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]OffsetCommitRequestTopic, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block OffsetCommitRequestTopic
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -233,7 +233,7 @@ func (r *OffsetCommitRequest) decode(pd packetDecoder, version int16) (err error
 
 	if r.Version >= 8 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -259,6 +259,5 @@ func (r *OffsetCommitRequest) IsValidVersion() bool {
 }
 
 func (r *OffsetCommitRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -28,16 +28,16 @@ func (p *OffsetCommitResponsePartition) encode(pe packetEncoder, version int16)
 func (p *OffsetCommitResponsePartition) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.Version >= 8 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -77,19 +77,19 @@ func (t *OffsetCommitResponseTopic) encode(pe packetEncoder, version int16) (err
 func (t *OffsetCommitResponseTopic) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]OffsetCommitResponsePartition, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block OffsetCommitResponsePartition
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
@@ -97,7 +97,7 @@ func (t *OffsetCommitResponseTopic) decode(pd packetDecoder, version int16) (err
 
 	if t.Version >= 8 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -142,20 +142,20 @@ func (r *OffsetCommitResponse) decode(pd packetDecoder, version int16) (err erro
 	}
 	if r.Version >= 3 {
 		if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "ThrottleTimeMs", err)
 		}
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]OffsetCommitResponseTopic, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block OffsetCommitResponseTopic
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -163,7 +163,7 @@ func (r *OffsetCommitResponse) decode(pd packetDecoder, version int16) (err erro
 
 	if r.Version >= 8 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -189,8 +189,7 @@ func (r *OffsetCommitResponse) IsValidVersion() bool {
 }
 
 func (r *OffsetCommitResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *OffsetCommitResponse) throttleTime() time.Duration {
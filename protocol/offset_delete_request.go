@@ -19,7 +19,7 @@ func (p *OffsetDeleteRequestPartition) encode(pe packetEncoder, version int16) (
 func (p *OffsetDeleteRequestPartition) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	return nil
@@ -56,19 +56,19 @@ func (t *OffsetDeleteRequestTopic) encode(pe packetEncoder, version int16) (err
 func (t *OffsetDeleteRequestTopic) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]OffsetDeleteRequestPartition, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block OffsetDeleteRequestPartition
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
@@ -106,19 +106,19 @@ func (r *OffsetDeleteRequest) encode(pe packetEncoder) (err error) {
 func (r *OffsetDeleteRequest) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.GroupID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]OffsetDeleteRequestTopic, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block OffsetDeleteRequestTopic
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -144,6 +144,5 @@ func (r *OffsetDeleteRequest) IsValidVersion() bool {
 }
 
 func (r *OffsetDeleteRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
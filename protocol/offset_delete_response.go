@@ -25,11 +25,11 @@ func (p *OffsetDeleteResponsePartition) encode(pe packetEncoder, version int16)
 func (p *OffsetDeleteResponsePartition) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	return nil
@@ -66,19 +66,19 @@ func (t *OffsetDeleteResponseTopic) encode(pe packetEncoder, version int16) (err
 func (t *OffsetDeleteResponseTopic) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]OffsetDeleteResponsePartition, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block OffsetDeleteResponsePartition
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
@@ -118,23 +118,23 @@ func (r *OffsetDeleteResponse) encode(pe packetEncoder) (err error) {
 func (r *OffsetDeleteResponse) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]OffsetDeleteResponseTopic, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block OffsetDeleteResponseTopic
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -160,8 +160,7 @@ func (r *OffsetDeleteResponse) IsValidVersion() bool {
 }
 
 func (r *OffsetDeleteResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *OffsetDeleteResponse) throttleTime() time.Duration {
@@ -35,19 +35,19 @@ func (t *OffsetFetchRequestTopic) decode(pd packetDecoder, version int16) (err e
 	t.Version = version
 	if t.Version >= 0 && t.Version <= 7 {
 		if t.Name, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Name", err)
 		}
 	}
 
 	if t.Version >= 0 && t.Version <= 7 {
 		if t.PartitionIndexes, err = pd.getInt32Array(); err != nil {
-			return err
+			return wrapFieldError(pd, "PartitionIndexes", err)
 		}
 	}
 
 	if t.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -87,19 +87,19 @@ func (t *OffsetFetchRequestTopics) decode(pd packetDecoder, version int16) (err
 	t.Version = version
 	if t.Version >= 8 {
 		if t.Name, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Name", err)
 		}
 	}
 
 	if t.Version >= 8 {
 		if t.PartitionIndexes, err = pd.getInt32Array(); err != nil {
-			return err
+			return wrapFieldError(pd, "PartitionIndexes", err)
 		}
 	}
 
 	if t.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -158,33 +158,33 @@ func (g *OffsetFetchRequestGroup) decode(pd packetDecoder, version int16) (err e
 	g.Version = version
 	if g.Version >= 8 {
 		if g.GroupID, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "GroupID", err)
 		}
 	}
 
 	if g.Version >= 9 {
 		if g.MemberID, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "MemberID", err)
 		}
 	}
 
 	if g.Version >= 9 {
 		if g.MemberEpoch, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "MemberEpoch", err)
 		}
 	}
 
 	if g.Version >= 8 {
 		var numTopics int
 		if numTopics, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Topics", err)
 		}
 		if numTopics > 0 {
 			g.Topics = make([]OffsetFetchRequestTopics, numTopics)
 			for i := 0; i < numTopics; i++ {
 				var block OffsetFetchRequestTopics
 				if err := block.decode(pd, g.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Topics", i, err)
 				}
 				g.Topics[i] = block
 			}
@@ -193,7 +193,7 @@ func (g *OffsetFetchRequestGroup) decode(pd packetDecoder, version int16) (err e
 
 	if g.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -261,21 +261,21 @@ func (r *OffsetFetchRequest) decode(pd packetDecoder, version int16) (err error)
 	}
 	if r.Version >= 0 && r.Version <= 7 {
 		if r.GroupID, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "GroupID", err)
 		}
 	}
 
 	if r.Version >= 0 && r.Version <= 7 {
 		var numTopics int
 		if numTopics, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Topics", err)
 		}
 		if numTopics > 0 {
 			r.Topics = make([]OffsetFetchRequestTopic, numTopics)
 			for i := 0; i < numTopics; i++ {
 				var block OffsetFetchRequestTopic
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Topics", i, err)
 				}
 				r.Topics[i] = block
 			}
@@ -285,14 +285,14 @@ func (r *OffsetFetchRequest) decode(pd packetDecoder, version int16) (err error)
 	if r.Version >= 8 {
 		var numGroups int
 		if numGroups, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Groups", err)
 		}
 		if numGroups > 0 {
 			r.Groups = make([]OffsetFetchRequestGroup, numGroups)
 			for i := 0; i < numGroups; i++ {
 				var block OffsetFetchRequestGroup
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Groups", i, err)
 				}
 				r.Groups[i] = block
 			}
@@ -301,13 +301,13 @@ func (r *OffsetFetchRequest) decode(pd packetDecoder, version int16) (err error)
 
 	if r.Version >= 7 {
 		if r.RequireStable, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "RequireStable", err)
 		}
 	}
 
 	if r.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -333,6 +333,5 @@ func (r *OffsetFetchRequest) IsValidVersion() bool {
 }
 
 func (r *OffsetFetchRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
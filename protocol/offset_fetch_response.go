@@ -53,37 +53,37 @@ func (p *OffsetFetchResponsePartition) decode(pd packetDecoder, version int16) (
 	p.Version = version
 	if p.Version >= 0 && p.Version <= 7 {
 		if p.PartitionIndex, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "PartitionIndex", err)
 		}
 	}
 
 	if p.Version >= 0 && p.Version <= 7 {
 		if p.CommittedOffset, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "CommittedOffset", err)
 		}
 	}
 
 	if p.Version >= 5 && p.Version <= 7 {
 		if p.CommittedLeaderEpoch, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "CommittedLeaderEpoch", err)
 		}
 	}
 
 	if p.Version >= 0 && p.Version <= 7 {
 		if p.Metadata, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Metadata", err)
 		}
 	}
 
 	if p.Version >= 0 && p.Version <= 7 {
 		if p.ErrorCode, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "ErrorCode", err)
 		}
 	}
 
 	if p.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -128,21 +128,21 @@ func (t *OffsetFetchResponseTopic) decode(pd packetDecoder, version int16) (err
 	t.Version = version
 	if t.Version >= 0 && t.Version <= 7 {
 		if t.Name, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Name", err)
 		}
 	}
 
 	if t.Version >= 0 && t.Version <= 7 {
 		var numPartitions int
 		if numPartitions, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Partitions", err)
 		}
 		if numPartitions > 0 {
 			t.Partitions = make([]OffsetFetchResponsePartition, numPartitions)
 			for i := 0; i < numPartitions; i++ {
 				var block OffsetFetchResponsePartition
 				if err := block.decode(pd, t.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Partitions", i, err)
 				}
 				t.Partitions[i] = block
 			}
@@ -151,7 +151,7 @@ func (t *OffsetFetchResponseTopic) decode(pd packetDecoder, version int16) (err
 
 	if t.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -207,37 +207,37 @@ func (p *OffsetFetchResponsePartitions) decode(pd packetDecoder, version int16)
 	p.Version = version
 	if p.Version >= 8 {
 		if p.PartitionIndex, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "PartitionIndex", err)
 		}
 	}
 
 	if p.Version >= 8 {
 		if p.CommittedOffset, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "CommittedOffset", err)
 		}
 	}
 
 	if p.Version >= 8 {
 		if p.CommittedLeaderEpoch, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "CommittedLeaderEpoch", err)
 		}
 	}
 
 	if p.Version >= 8 {
 		if p.Metadata, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Metadata", err)
 		}
 	}
 
 	if p.Version >= 8 {
 		if p.ErrorCode, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "ErrorCode", err)
 		}
 	}
 
 	if p.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -282,21 +282,21 @@ func (t *OffsetFetchResponseTopics) decode(pd packetDecoder, version int16) (err
 	t.Version = version
 	if t.Version >= 8 {
 		if t.Name, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Name", err)
 		}
 	}
 
 	if t.Version >= 8 {
 		var numPartitions int
 		if numPartitions, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Partitions", err)
 		}
 		if numPartitions > 0 {
 			t.Partitions = make([]OffsetFetchResponsePartitions, numPartitions)
 			for i := 0; i < numPartitions; i++ {
 				var block OffsetFetchResponsePartitions
 				if err := block.decode(pd, t.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Partitions", i, err)
 				}
 				t.Partitions[i] = block
 			}
@@ -305,7 +305,7 @@ func (t *OffsetFetchResponseTopics) decode(pd packetDecoder, version int16) (err
 
 	if t.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -356,21 +356,21 @@ func (g *OffsetFetchResponseGroup) decode(pd packetDecoder, version int16) (err
 	g.Version = version
 	if g.Version >= 8 {
 		if g.GroupID, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "GroupID", err)
 		}
 	}
 
 	if g.Version >= 8 {
 		var numTopics int
 		if numTopics, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Topics", err)
 		}
 		if numTopics > 0 {
 			g.Topics = make([]OffsetFetchResponseTopics, numTopics)
 			for i := 0; i < numTopics; i++ {
 				var block OffsetFetchResponseTopics
 				if err := block.decode(pd, g.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Topics", i, err)
 				}
 				g.Topics[i] = block
 			}
@@ -379,13 +379,13 @@ func (g *OffsetFetchResponseGroup) decode(pd packetDecoder, version int16) (err
 
 	if g.Version >= 8 {
 		if g.ErrorCode, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "ErrorCode", err)
 		}
 	}
 
 	if g.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -451,21 +451,21 @@ func (r *OffsetFetchResponse) decode(pd packetDecoder, version int16) (err error
 	}
 	if r.Version >= 3 {
 		if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "ThrottleTimeMs", err)
 		}
 	}
 
 	if r.Version >= 0 && r.Version <= 7 {
 		var numTopics int
 		if numTopics, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Topics", err)
 		}
 		if numTopics > 0 {
 			r.Topics = make([]OffsetFetchResponseTopic, numTopics)
 			for i := 0; i < numTopics; i++ {
 				var block OffsetFetchResponseTopic
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Topics", i, err)
 				}
 				r.Topics[i] = block
 			}
@@ -474,21 +474,21 @@ func (r *OffsetFetchResponse) decode(pd packetDecoder, version int16) (err error
 
 	if r.Version >= 2 && r.Version <= 7 {
 		if r.ErrorCode, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "ErrorCode", err)
 		}
 	}
 
 	if r.Version >= 8 {
 		var numGroups int
 		if numGroups, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Groups", err)
 		}
 		if numGroups > 0 {
 			r.Groups = make([]OffsetFetchResponseGroup, numGroups)
 			for i := 0; i < numGroups; i++ {
 				var block OffsetFetchResponseGroup
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Groups", i, err)
 				}
 				r.Groups[i] = block
 			}
@@ -497,7 +497,7 @@ func (r *OffsetFetchResponse) decode(pd packetDecoder, version int16) (err error
 
 	if r.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -523,8 +523,7 @@ func (r *OffsetFetchResponse) IsValidVersion() bool {
 }
 
 func (r *OffsetFetchResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *OffsetFetchResponse) throttleTime() time.Duration {
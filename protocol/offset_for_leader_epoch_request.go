@@ -32,22 +32,22 @@ func (p *OffsetForLeaderPartition) encode(pe packetEncoder, version int16) (err
 func (p *OffsetForLeaderPartition) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.Partition, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partition", err)
 	}
 
 	if p.Version >= 2 {
 		if p.CurrentLeaderEpoch, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "CurrentLeaderEpoch", err)
 		}
 	}
 
 	if p.LeaderEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderEpoch", err)
 	}
 
 	if p.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -87,19 +87,19 @@ func (t *OffsetForLeaderTopic) encode(pe packetEncoder, version int16) (err erro
 func (t *OffsetForLeaderTopic) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Topic, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topic", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]OffsetForLeaderPartition, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block OffsetForLeaderPartition
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
@@ -107,7 +107,7 @@ func (t *OffsetForLeaderTopic) decode(pd packetDecoder, version int16) (err erro
 
 	if t.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -152,20 +152,20 @@ func (r *OffsetForLeaderEpochRequest) decode(pd packetDecoder, version int16) (e
 	}
 	if r.Version >= 3 {
 		if r.ReplicaID, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "ReplicaID", err)
 		}
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]OffsetForLeaderTopic, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block OffsetForLeaderTopic
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -173,7 +173,7 @@ func (r *OffsetForLeaderEpochRequest) decode(pd packetDecoder, version int16) (e
 
 	if r.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -199,6 +199,5 @@ func (r *OffsetForLeaderEpochRequest) IsValidVersion() bool {
 }
 
 func (r *OffsetForLeaderEpochRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
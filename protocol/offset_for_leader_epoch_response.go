@@ -38,26 +38,26 @@ func (p *EpochEndOffset_OffsetForLeaderEpochResponse) encode(pe packetEncoder, v
 func (p *EpochEndOffset_OffsetForLeaderEpochResponse) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.Partition, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partition", err)
 	}
 
 	if p.Version >= 1 {
 		if p.LeaderEpoch, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "LeaderEpoch", err)
 		}
 	}
 
 	if p.EndOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "EndOffset", err)
 	}
 
 	if p.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -97,19 +97,19 @@ func (t *OffsetForLeaderTopicResult) encode(pe packetEncoder, version int16) (er
 func (t *OffsetForLeaderTopicResult) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Topic, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topic", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]EpochEndOffset_OffsetForLeaderEpochResponse, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block EpochEndOffset_OffsetForLeaderEpochResponse
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
@@ -117,7 +117,7 @@ func (t *OffsetForLeaderTopicResult) decode(pd packetDecoder, version int16) (er
 
 	if t.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -162,20 +162,20 @@ func (r *OffsetForLeaderEpochResponse) decode(pd packetDecoder, version int16) (
 	}
 	if r.Version >= 2 {
 		if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "ThrottleTimeMs", err)
 		}
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]OffsetForLeaderTopicResult, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block OffsetForLeaderTopicResult
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -183,7 +183,7 @@ func (r *OffsetForLeaderEpochResponse) decode(pd packetDecoder, version int16) (
 
 	if r.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -209,8 +209,7 @@ func (r *OffsetForLeaderEpochResponse) IsValidVersion() bool {
 }
 
 func (r *OffsetForLeaderEpochResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *OffsetForLeaderEpochResponse) throttleTime() time.Duration {
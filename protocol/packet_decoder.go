@@ -16,6 +16,8 @@ type packetDecoder interface {
 	getFloat64() (float64, error)
 	getVarint() (int64, error)
 	getUVarint() (uint64, error)
+	getVarint32() (int32, error)
+	getUVarint32() (uint32, error)
 	getArrayLength() (int, error)
 	getCompactArrayLength() (int, error)
 	getBool() (bool, error)
@@ -38,6 +40,7 @@ type packetDecoder interface {
 	getInt32Array() ([]int32, error)
 	getInt64Array() ([]int64, error)
 	getStringArray() ([]string, error)
+	getCompactStringArray() ([]string, error)
 	getUUID() (uuid.UUID, error)
 	getUUIDArray() ([]uuid.UUID, error)
 
@@ -50,6 +53,9 @@ type packetDecoder interface {
 	// Stacks, see PushDecoder
 	push(in pushDecoder) error
 	pop() error
+
+	// failedOffset returns the offset of the last read which failed, or -1 if none did
+	failedOffset() int
 }
 
 // PushDecoder is the interface for decoding fields like CRCs and lengths where the validity
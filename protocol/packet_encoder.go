@@ -16,6 +16,8 @@ type packetEncoder interface {
 	putFloat64(in float64)
 	putVarint(in int64)
 	putUVarint(in uint64)
+	putVarint32(in int32)
+	putUVarint32(in uint32)
 	putCompactArrayLength(in int) error
 	putArrayLength(in int) error
 	putBool(in bool)
@@ -30,6 +32,7 @@ type packetEncoder interface {
 	putString(in string) error
 	putNullableString(in *string) error
 	putStringArray(in []string) error
+	putCompactStringArray(in []string) error
 	putCompactInt8Array(in []int8) error
 	putCompactInt16Array(in []int16) error
 	putCompactInt32Array(in []int32) error
@@ -78,10 +81,21 @@ type dynamicPushEncoder interface {
 	adjustLength(currOffset int) int
 }
 
+// FlexibleEncoderFrom returns an encoder writing the compact types of flexible versions to pe.
+// The prep and real encoders embed their flexible encoder, so switching to a flexible version doesn't allocate.
 func FlexibleEncoderFrom(pe packetEncoder) packetEncoder {
-	fe := &flexibleEncoder{
-		parent: pe,
+	switch e := pe.(type) {
+	case *flexibleEncoder:
+		return e
+	case *prepEncoder:
+		e.flexible.parent = e
+		return &e.flexible
+	case *realEncoder:
+		e.flexible.parent = e
+		return &e.flexible
 	}
 
-	return fe
+	return &flexibleEncoder{
+		parent: pe,
+	}
 }
@@ -10,8 +10,9 @@ import (
 )
 
 type prepEncoder struct {
-	stack  []pushEncoder
-	length int
+	stack    []pushEncoder
+	length   int
+	flexible flexibleEncoder
 }
 
 // primitives
@@ -59,6 +60,14 @@ func (pe *prepEncoder) putUVarint(in uint64) {
 	pe.length += binary.PutUvarint(buf[:], in)
 }
 
+func (pe *prepEncoder) putVarint32(in int32) {
+	pe.putVarint(int64(in))
+}
+
+func (pe *prepEncoder) putUVarint32(in uint32) {
+	pe.putUVarint(uint64(in))
+}
+
 func (pe *prepEncoder) putFloat64(in float64) {
 	pe.length += 8
 }
@@ -158,6 +167,21 @@ func (pe *prepEncoder) putStringArray(in []string) error {
 	return nil
 }
 
+func (pe *prepEncoder) putCompactStringArray(in []string) error {
+	err := pe.putCompactArrayLength(len(in))
+	if err != nil {
+		return err
+	}
+
+	for _, str := range in {
+		if err := pe.putCompactString(str); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (pe *prepEncoder) putCompactInt8Array(in []int8) error {
 	if in == nil {
 		return errors.New("expected int8 array to be non null")
@@ -8,7 +8,7 @@ type PartitionProduceData struct {
 	// Index contains the partition index.
 	Index int32
 	// Records contains the record data to be produced.
-	Records RecordBatch
+	Records Records
 }
 
 func (p *PartitionProduceData) encode(pe packetEncoder, version int16) (err error) {
@@ -28,18 +28,18 @@ func (p *PartitionProduceData) encode(pe packetEncoder, version int16) (err erro
 func (p *PartitionProduceData) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.Index, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Index", err)
 	}
 
-	tmpRecords := RecordBatch{}
+	tmpRecords := Records{}
 	if err := tmpRecords.decode(pd, p.Version); err != nil {
-		return err
+		return wrapFieldError(pd, "Records", err)
 	}
 	p.Records = tmpRecords
 
 	if p.Version >= 9 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -79,19 +79,19 @@ func (t *TopicProduceData) encode(pe packetEncoder, version int16) (err error) {
 func (t *TopicProduceData) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	var numPartitionData int
 	if numPartitionData, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionData", err)
 	}
 	if numPartitionData > 0 {
 		t.PartitionData = make([]PartitionProduceData, numPartitionData)
 		for i := 0; i < numPartitionData; i++ {
 			var block PartitionProduceData
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "PartitionData", i, err)
 			}
 			t.PartitionData[i] = block
 		}
@@ -99,7 +99,7 @@ func (t *TopicProduceData) decode(pd packetDecoder, version int16) (err error) {
 
 	if t.Version >= 9 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -154,28 +154,28 @@ func (r *ProduceRequest) decode(pd packetDecoder, version int16) (err error) {
 	}
 	if r.Version >= 3 {
 		if r.TransactionalID, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "TransactionalID", err)
 		}
 	}
 
 	if r.Acks, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "Acks", err)
 	}
 
 	if r.TimeoutMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "TimeoutMs", err)
 	}
 
 	var numTopicData int
 	if numTopicData, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicData", err)
 	}
 	if numTopicData > 0 {
 		r.TopicData = make([]TopicProduceData, numTopicData)
 		for i := 0; i < numTopicData; i++ {
 			var block TopicProduceData
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "TopicData", i, err)
 			}
 			r.TopicData[i] = block
 		}
@@ -183,7 +183,7 @@ func (r *ProduceRequest) decode(pd packetDecoder, version int16) (err error) {
 
 	if r.Version >= 9 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -209,6 +209,5 @@ func (r *ProduceRequest) IsValidVersion() bool {
 }
 
 func (r *ProduceRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -35,19 +35,19 @@ func (r *BatchIndexAndErrorMessage) decode(pd packetDecoder, version int16) (err
 	r.Version = version
 	if r.Version >= 8 {
 		if r.BatchIndex, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "BatchIndex", err)
 		}
 	}
 
 	if r.Version >= 8 {
 		if r.BatchIndexErrorMessage, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "BatchIndexErrorMessage", err)
 		}
 	}
 
 	if r.Version >= 9 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -80,13 +80,13 @@ func (c *LeaderIdAndEpoch_ProduceResponse) decode(pd packetDecoder, version int1
 	c.Version = version
 	if c.Version >= 10 {
 		if c.LeaderID, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "LeaderID", err)
 		}
 	}
 
 	if c.Version >= 10 {
 		if c.LeaderEpoch, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "LeaderEpoch", err)
 		}
 	}
 
@@ -157,40 +157,40 @@ func (p *PartitionProduceResponse) encode(pe packetEncoder, version int16) (err
 func (p *PartitionProduceResponse) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.Index, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Index", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.BaseOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "BaseOffset", err)
 	}
 
 	if p.Version >= 2 {
 		if p.LogAppendTimeMs, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "LogAppendTimeMs", err)
 		}
 	}
 
 	if p.Version >= 5 {
 		if p.LogStartOffset, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "LogStartOffset", err)
 		}
 	}
 
 	if p.Version >= 8 {
 		var numRecordErrors int
 		if numRecordErrors, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "RecordErrors", err)
 		}
 		if numRecordErrors > 0 {
 			p.RecordErrors = make([]BatchIndexAndErrorMessage, numRecordErrors)
 			for i := 0; i < numRecordErrors; i++ {
 				var block BatchIndexAndErrorMessage
 				if err := block.decode(pd, p.Version); err != nil {
-					return err
+					return wrapElementError(pd, "RecordErrors", i, err)
 				}
 				p.RecordErrors[i] = block
 			}
@@ -199,13 +199,13 @@ func (p *PartitionProduceResponse) decode(pd packetDecoder, version int16) (err
 
 	if p.Version >= 8 {
 		if p.ErrorMessage, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "ErrorMessage", err)
 		}
 	}
 
 	if p.Version >= 9 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -245,19 +245,19 @@ func (r *TopicProduceResponse) encode(pe packetEncoder, version int16) (err erro
 func (r *TopicProduceResponse) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	var numPartitionResponses int
 	if numPartitionResponses, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionResponses", err)
 	}
 	if numPartitionResponses > 0 {
 		r.PartitionResponses = make([]PartitionProduceResponse, numPartitionResponses)
 		for i := 0; i < numPartitionResponses; i++ {
 			var block PartitionProduceResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "PartitionResponses", i, err)
 			}
 			r.PartitionResponses[i] = block
 		}
@@ -265,7 +265,7 @@ func (r *TopicProduceResponse) decode(pd packetDecoder, version int16) (err erro
 
 	if r.Version >= 9 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -314,25 +314,25 @@ func (n *NodeEndpoint_ProduceResponse) decode(pd packetDecoder, version int16) (
 	n.Version = version
 	if n.Version >= 10 {
 		if n.NodeID, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "NodeID", err)
 		}
 	}
 
 	if n.Version >= 10 {
 		if n.Host, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Host", err)
 		}
 	}
 
 	if n.Version >= 10 {
 		if n.Port, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "Port", err)
 		}
 	}
 
 	if n.Version >= 10 {
 		if n.Rack, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Rack", err)
 		}
 	}
 
@@ -380,14 +380,14 @@ func (r *ProduceResponse) decode(pd packetDecoder, version int16) (err error) {
 	}
 	var numResponses int
 	if numResponses, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Responses", err)
 	}
 	if numResponses > 0 {
 		r.Responses = make([]TopicProduceResponse, numResponses)
 		for i := 0; i < numResponses; i++ {
 			var block TopicProduceResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Responses", i, err)
 			}
 			r.Responses[i] = block
 		}
@@ -395,13 +395,13 @@ func (r *ProduceResponse) decode(pd packetDecoder, version int16) (err error) {
 
 	if r.Version >= 1 {
 		if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "ThrottleTimeMs", err)
 		}
 	}
 
 	if r.Version >= 9 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -427,8 +427,7 @@ func (r *ProduceResponse) IsValidVersion() bool {
 }
 
 func (r *ProduceResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *ProduceResponse) throttleTime() time.Duration {
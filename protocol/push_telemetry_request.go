@@ -42,27 +42,27 @@ func (r *PushTelemetryRequest) decode(pd packetDecoder, version int16) (err erro
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ClientInstanceID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClientInstanceID", err)
 	}
 
 	if r.SubscriptionID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "SubscriptionID", err)
 	}
 
 	if r.Terminating, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "Terminating", err)
 	}
 
 	if r.CompressionType, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "CompressionType", err)
 	}
 
 	if r.Metrics, err = pd.getBytes(); err != nil {
-		return err
+		return wrapFieldError(pd, "Metrics", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -84,6 +84,5 @@ func (r *PushTelemetryRequest) IsValidVersion() bool {
 }
 
 func (r *PushTelemetryRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -0,0 +1,100 @@
+package protocol
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type message interface {
+	encoder
+	versionedDecoder
+}
+
+// testRoundTrip encodes in, decodes it into out and checks that out encodes to the same bytes.
+func testRoundTrip(t *testing.T, in message, out message, version int16) {
+	t.Helper()
+
+	buf, err := Encode(in)
+	if err != nil {
+		t.Fatalf("v%d: error encoding: %v", version, err)
+	}
+
+	n, err := VersionedDecode(buf, out, version)
+	if err != nil {
+		t.Fatalf("v%d: error decoding: %v", version, err)
+	}
+	if n != len(buf) {
+		t.Errorf("v%d: decoded %d of %d bytes", version, n, len(buf))
+	}
+
+	reencoded, err := Encode(out)
+	if err != nil {
+		t.Fatalf("v%d: error encoding decoded message: %v", version, err)
+	}
+	if !bytes.Equal(buf, reencoded) {
+		t.Errorf("v%d: round trip changed the message\n got %v\nwant %v", version, reencoded, buf)
+	}
+}
+
+func TestEndQuorumEpochRequest_RoundTrip(t *testing.T) {
+	clusterID := "test-cluster"
+
+	for _, version := range []int16{0, 1} {
+		req := EndQuorumEpochRequest{
+			Version:   version,
+			ClusterID: &clusterID,
+			Topics: []TopicData_EndQuorumEpochRequest{{
+				TopicName: "__cluster_metadata",
+				Partitions: []PartitionData_EndQuorumEpochRequest{{
+					PartitionIndex:      0,
+					LeaderID:            1,
+					LeaderEpoch:         5,
+					PreferredSuccessors: []int32{2, 3},
+					PreferredCandidates: []ReplicaInfo{{CandidateID: 2, CandidateDirectoryID: uuid.New()}},
+				}},
+			}},
+		}
+		if version >= 1 {
+			req.LeaderEndpoints = []LeaderEndpoint_EndQuorumEpochRequest{{Name: "CONTROLLER", Host: "localhost", Port: 9093}}
+		}
+
+		decoded := EndQuorumEpochRequest{}
+		testRoundTrip(t, &req, &decoded, version)
+
+		partition := decoded.Topics[0].Partitions[0]
+		if partition.LeaderID != 1 || partition.LeaderEpoch != 5 {
+			t.Errorf("v%d: unexpected partition %+v", version, partition)
+		}
+		// successors were replaced by candidates with directory IDs in v1
+		if version == 0 && len(partition.PreferredSuccessors) != 2 {
+			t.Errorf("v%d: preferred successors = %v, want [2 3]", version, partition.PreferredSuccessors)
+		}
+		if version == 1 && (len(partition.PreferredSuccessors) != 0 || partition.PreferredCandidates[0].CandidateID != 2) {
+			t.Errorf("v%d: unexpected preferred candidates %+v", version, partition.PreferredCandidates)
+		}
+	}
+}
+
+func TestEndQuorumEpochResponse_RoundTrip(t *testing.T) {
+	for _, version := range []int16{0, 1} {
+		resp := EndQuorumEpochResponse{
+			Version: version,
+			Topics: []TopicData_EndQuorumEpochResponse{{
+				TopicName: "__cluster_metadata",
+				Partitions: []PartitionData_EndQuorumEpochResponse{
+					{PartitionIndex: 0, LeaderID: 1, LeaderEpoch: 5},
+					{PartitionIndex: 1, ErrorCode: 3, LeaderID: -1, LeaderEpoch: -1},
+				},
+			}},
+		}
+
+		decoded := EndQuorumEpochResponse{}
+		testRoundTrip(t, &resp, &decoded, version)
+
+		if len(decoded.Topics[0].Partitions) != 2 || decoded.Topics[0].Partitions[1].ErrorCode != 3 {
+			t.Errorf("v%d: unexpected partitions %+v", version, decoded.Topics[0].Partitions)
+		}
+	}
+}
@@ -34,23 +34,23 @@ func (s *StateBatch_ReadShareGroupStateResponse) encode(pe packetEncoder, versio
 func (s *StateBatch_ReadShareGroupStateResponse) decode(pd packetDecoder, version int16) (err error) {
 	s.Version = version
 	if s.FirstOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "FirstOffset", err)
 	}
 
 	if s.LastOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "LastOffset", err)
 	}
 
 	if s.DeliveryState, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "DeliveryState", err)
 	}
 
 	if s.DeliveryCount, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "DeliveryCount", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -103,42 +103,42 @@ func (p *PartitionResult_ReadShareGroupStateResponse) encode(pe packetEncoder, v
 func (p *PartitionResult_ReadShareGroupStateResponse) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.Partition, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partition", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if p.StateEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "StateEpoch", err)
 	}
 
 	if p.StartOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "StartOffset", err)
 	}
 
 	var numStateBatches int
 	if numStateBatches, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "StateBatches", err)
 	}
 	if numStateBatches > 0 {
 		p.StateBatches = make([]StateBatch_ReadShareGroupStateResponse, numStateBatches)
 		for i := 0; i < numStateBatches; i++ {
 			var block StateBatch_ReadShareGroupStateResponse
 			if err := block.decode(pd, p.Version); err != nil {
-				return err
+				return wrapElementError(pd, "StateBatches", i, err)
 			}
 			p.StateBatches[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -175,26 +175,26 @@ func (r *ReadStateResult) encode(pe packetEncoder, version int16) (err error) {
 func (r *ReadStateResult) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.TopicID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicID", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		r.Partitions = make([]PartitionResult_ReadShareGroupStateResponse, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionResult_ReadShareGroupStateResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			r.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -226,21 +226,21 @@ func (r *ReadShareGroupStateResponse) decode(pd packetDecoder, version int16) (e
 	pd = FlexibleDecoderFrom(pd)
 	var numResults int
 	if numResults, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Results", err)
 	}
 	if numResults > 0 {
 		r.Results = make([]ReadStateResult, numResults)
 		for i := 0; i < numResults; i++ {
 			var block ReadStateResult
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Results", i, err)
 			}
 			r.Results[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -262,6 +262,5 @@ func (r *ReadShareGroupStateResponse) IsValidVersion() bool {
 }
 
 func (r *ReadShareGroupStateResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
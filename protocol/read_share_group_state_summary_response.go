@@ -40,27 +40,27 @@ func (p *PartitionResult_ReadShareGroupStateSummaryResponse) encode(pe packetEnc
 func (p *PartitionResult_ReadShareGroupStateSummaryResponse) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.Partition, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partition", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if p.StateEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "StateEpoch", err)
 	}
 
 	if p.StartOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "StartOffset", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -97,26 +97,26 @@ func (r *ReadStateSummaryResult) encode(pe packetEncoder, version int16) (err er
 func (r *ReadStateSummaryResult) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.TopicID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicID", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		r.Partitions = make([]PartitionResult_ReadShareGroupStateSummaryResponse, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionResult_ReadShareGroupStateSummaryResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			r.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -148,21 +148,21 @@ func (r *ReadShareGroupStateSummaryResponse) decode(pd packetDecoder, version in
 	pd = FlexibleDecoderFrom(pd)
 	var numResults int
 	if numResults, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Results", err)
 	}
 	if numResults > 0 {
 		r.Results = make([]ReadStateSummaryResult, numResults)
 		for i := 0; i < numResults; i++ {
 			var block ReadStateSummaryResult
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Results", i, err)
 			}
 			r.Results[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -184,6 +184,5 @@ func (r *ReadShareGroupStateSummaryResponse) IsValidVersion() bool {
 }
 
 func (r *ReadShareGroupStateSummaryResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
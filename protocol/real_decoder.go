@@ -22,14 +22,38 @@ type realDecoder struct {
 	raw   []byte
 	off   int
 	stack []pushDecoder
+
+	// failedAt is the offset of the last read which failed, if failed is set
+	failedAt int
+	failed   bool
+}
+
+// fail records the offset of a read which failed with err, for the DecodeError of the field being decoded.
+func (rd *realDecoder) fail(err error) error {
+	rd.failedAt, rd.failed = rd.off, true
+	return err
+}
+
+// exhaust fails a read which needs more bytes than remain. It moves to the end of the data, so a decoder never continues
+// in the middle of a field.
+func (rd *realDecoder) exhaust() error {
+	err := rd.fail(ErrInsufficientData)
+	rd.off = len(rd.raw)
+	return err
+}
+
+func (rd *realDecoder) failedOffset() int {
+	if !rd.failed {
+		return -1
+	}
+	return rd.failedAt
 }
 
 // primitives
 
 func (rd *realDecoder) getInt8() (int8, error) {
 	if rd.remaining() < 1 {
-		rd.off = len(rd.raw)
-		return -1, ErrInsufficientData
+		return -1, rd.exhaust()
 	}
 	tmp := int8(rd.raw[rd.off])
 	rd.off++
@@ -38,8 +62,7 @@ func (rd *realDecoder) getInt8() (int8, error) {
 
 func (rd *realDecoder) getInt16() (int16, error) {
 	if rd.remaining() < 2 {
-		rd.off = len(rd.raw)
-		return -1, ErrInsufficientData
+		return -1, rd.exhaust()
 	}
 	tmp := int16(binary.BigEndian.Uint16(rd.raw[rd.off:]))
 	rd.off += 2
@@ -54,8 +77,7 @@ func (rd *realDecoder) getUint16() (uint16, error) {
 
 func (rd *realDecoder) getInt32() (int32, error) {
 	if rd.remaining() < 4 {
-		rd.off = len(rd.raw)
-		return -1, ErrInsufficientData
+		return -1, rd.exhaust()
 	}
 	tmp := int32(binary.BigEndian.Uint32(rd.raw[rd.off:]))
 	rd.off += 4
@@ -70,8 +92,7 @@ func (rd *realDecoder) getUint32() (uint32, error) {
 
 func (rd *realDecoder) getInt64() (int64, error) {
 	if rd.remaining() < 8 {
-		rd.off = len(rd.raw)
-		return -1, ErrInsufficientData
+		return -1, rd.exhaust()
 	}
 	tmp := int64(binary.BigEndian.Uint64(rd.raw[rd.off:]))
 	rd.off += 8
@@ -81,27 +102,53 @@ func (rd *realDecoder) getInt64() (int64, error) {
 func (rd *realDecoder) getVarint() (int64, error) {
 	tmp, n := binary.Varint(rd.raw[rd.off:])
 	if n == 0 {
-		rd.off = len(rd.raw)
-		return -1, ErrInsufficientData
+		return -1, rd.exhaust()
 	}
 	if n < 0 {
+		err := rd.fail(errVarintOverflow)
 		rd.off -= n
-		return -1, errVarintOverflow
+		return -1, err
 	}
 	rd.off += n
 	return tmp, nil
 }
 
+// getVarint32 reads a zig-zag varint whose value must fit in an int32, like the varints of records.
+func (rd *realDecoder) getVarint32() (int32, error) {
+	tmp, err := rd.getVarint()
+	if err != nil {
+		return -1, err
+	}
+	if tmp < math.MinInt32 || tmp > math.MaxInt32 {
+		return -1, rd.fail(errVarintOverflow)
+	}
+
+	return int32(tmp), nil
+}
+
+// getUVarint32 reads an unsigned varint whose value must fit in a uint32.
+func (rd *realDecoder) getUVarint32() (uint32, error) {
+	tmp, err := rd.getUVarint()
+	if err != nil {
+		return 0, err
+	}
+	if tmp > math.MaxUint32 {
+		return 0, rd.fail(errUVarintOverflow)
+	}
+
+	return uint32(tmp), nil
+}
+
 func (rd *realDecoder) getUVarint() (uint64, error) {
 	tmp, n := binary.Uvarint(rd.raw[rd.off:])
 	if n == 0 {
-		rd.off = len(rd.raw)
-		return 0, ErrInsufficientData
+		return 0, rd.exhaust()
 	}
 
 	if n < 0 {
+		err := rd.fail(errUVarintOverflow)
 		rd.off -= n
-		return 0, errUVarintOverflow
+		return 0, err
 	}
 
 	rd.off += n
@@ -110,42 +157,88 @@ func (rd *realDecoder) getUVarint() (uint64, error) {
 
 func (rd *realDecoder) getFloat64() (float64, error) {
 	if rd.remaining() < 8 {
-		rd.off = len(rd.raw)
-		return -1, ErrInsufficientData
+		return -1, rd.exhaust()
 	}
 	tmp := math.Float64frombits(binary.BigEndian.Uint64(rd.raw[rd.off:]))
 	rd.off += 8
 	return tmp, nil
 }
 
+// getArrayLength reads the int32 length of an array. A null array has length -1, which callers treat as empty.
+// Other negative lengths, and lengths longer than the remaining bytes, are rejected before the caller allocates the array,
+// since every element takes at least one byte.
 func (rd *realDecoder) getArrayLength() (int, error) {
 	if rd.remaining() < 4 {
-		rd.off = len(rd.raw)
-		return -1, ErrInsufficientData
+		return -1, rd.exhaust()
 	}
 	tmp := int(int32(binary.BigEndian.Uint32(rd.raw[rd.off:])))
 	rd.off += 4
-	if tmp > rd.remaining() {
-		rd.off = len(rd.raw)
-		return -1, ErrInsufficientData
+	if tmp < -1 {
+		return -1, rd.fail(errInvalidArrayLength)
+	} else if tmp > rd.remaining() {
+		return -1, rd.exhaust()
 	} else if tmp > 2*math.MaxUint16 {
-		return -1, errInvalidArrayLength
+		return -1, rd.fail(errInvalidArrayLength)
 	}
 
 	return tmp, nil
 }
 
+// getCompactArrayLength reads the uvarint length + 1 of a compact array. Like getArrayLength, a null array has length -1.
 func (rd *realDecoder) getCompactArrayLength() (int, error) {
 	n, err := rd.getUVarint()
 	if err != nil {
-		return 0, err
+		return -1, err
 	}
 
 	if n == 0 {
-		return 0, nil
+		return -1, nil
 	}
 
-	return int(n) - 1, nil
+	// every element takes at least one byte, a longer array is corrupt
+	if n-1 > uint64(rd.remaining()) {
+		return -1, rd.exhaust()
+	}
+
+	return int(n - 1), nil
+}
+
+// getPrimitiveArrayLength reads the length of an array of elements of elemSize bytes, and checks that all elements
+// are available. A null array has length -1, any other negative length is invalid.
+func (rd *realDecoder) getPrimitiveArrayLength(elemSize int) (int, error) {
+	if rd.remaining() < 4 {
+		return -1, rd.exhaust()
+	}
+	n := int(int32(binary.BigEndian.Uint32(rd.raw[rd.off:])))
+	rd.off += 4
+
+	if n < -1 {
+		return -1, rd.fail(errInvalidArrayLength)
+	}
+	if n > rd.remaining()/elemSize {
+		return -1, rd.exhaust()
+	}
+
+	return n, nil
+}
+
+// getCompactPrimitiveArrayLength is getPrimitiveArrayLength for compact arrays, whose length is a uvarint of length + 1.
+// The length is checked before converting it to int, since a huge uvarint would overflow into a negative length.
+func (rd *realDecoder) getCompactPrimitiveArrayLength(elemSize int) (int, error) {
+	n, err := rd.getUVarint()
+	if err != nil {
+		return -1, err
+	}
+
+	if n == 0 {
+		return -1, nil
+	}
+
+	if n-1 > uint64(rd.remaining()/elemSize) {
+		return -1, rd.exhaust()
+	}
+
+	return int(n - 1), nil
 }
 
 func (rd *realDecoder) getBool() (bool, error) {
@@ -154,7 +247,7 @@ func (rd *realDecoder) getBool() (bool, error) {
 		return false, err
 	}
 	if b != 1 {
-		return false, errInvalidBool
+		return false, rd.fail(errInvalidBool)
 	}
 	return true, nil
 }
@@ -216,6 +309,9 @@ func (rd *realDecoder) getCompactBytes() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	if n == 0 {
+		return nil, nil
+	}
 
 	length := int(n - 1)
 	return rd.getRawBytes(length)
@@ -231,10 +327,9 @@ func (rd *realDecoder) getStringLength() (int, error) {
 
 	switch {
 	case n < -1:
-		return 0, errInvalidStringLength
+		return 0, rd.fail(errInvalidStringLength)
 	case n > rd.remaining():
-		rd.off = len(rd.raw)
-		return 0, ErrInsufficientData
+		return 0, rd.exhaust()
 	}
 
 	return n, nil
@@ -268,13 +363,15 @@ func (rd *realDecoder) getCompactString() (string, error) {
 		return "", err
 	}
 
-	length := int(n - 1)
-	if length < 0 {
-		return "", errInvalidByteSliceLength
+	if n == 0 {
+		return "", rd.fail(errInvalidByteSliceLength)
 	}
-	tmpStr := string(rd.raw[rd.off : rd.off+length])
-	rd.off += length
-	return tmpStr, nil
+
+	tmp, err := rd.getRawBytes(int(n - 1))
+	if err != nil {
+		return "", err
+	}
+	return string(tmp), nil
 }
 
 func (rd *realDecoder) getCompactNullableString() (*string, error) {
@@ -283,29 +380,24 @@ func (rd *realDecoder) getCompactNullableString() (*string, error) {
 		return nil, err
 	}
 
-	length := int(n - 1)
+	if n == 0 {
+		return nil, nil
+	}
 
-	if length < 0 {
+	tmp, err := rd.getRawBytes(int(n - 1))
+	if err != nil {
 		return nil, err
 	}
-
-	tmpStr := string(rd.raw[rd.off : rd.off+length])
-	rd.off += length
-	return &tmpStr, err
+	tmpStr := string(tmp)
+	return &tmpStr, nil
 }
 
 func (rd *realDecoder) getCompactInt8Array() ([]int8, error) {
-	n, err := rd.getUVarint()
-	if err != nil {
+	arrayLength, err := rd.getCompactPrimitiveArrayLength(1)
+	if err != nil || arrayLength <= 0 {
 		return nil, err
 	}
 
-	if n == 0 {
-		return nil, nil
-	}
-
-	arrayLength := int(n) - 1
-
 	ret := make([]int8, arrayLength)
 
 	for i := range ret {
@@ -316,17 +408,11 @@ func (rd *realDecoder) getCompactInt8Array() ([]int8, error) {
 }
 
 func (rd *realDecoder) getCompactInt16Array() ([]int16, error) {
-	n, err := rd.getUVarint()
-	if err != nil {
+	arrayLength, err := rd.getCompactPrimitiveArrayLength(2)
+	if err != nil || arrayLength <= 0 {
 		return nil, err
 	}
 
-	if n == 0 {
-		return nil, nil
-	}
-
-	arrayLength := int(n) - 1
-
 	ret := make([]int16, arrayLength)
 
 	for i := range ret {
@@ -337,17 +423,11 @@ func (rd *realDecoder) getCompactInt16Array() ([]int16, error) {
 }
 
 func (rd *realDecoder) getCompactInt32Array() ([]int32, error) {
-	n, err := rd.getUVarint()
-	if err != nil {
+	arrayLength, err := rd.getCompactPrimitiveArrayLength(4)
+	if err != nil || arrayLength <= 0 {
 		return nil, err
 	}
 
-	if n == 0 {
-		return nil, nil
-	}
-
-	arrayLength := int(n) - 1
-
 	ret := make([]int32, arrayLength)
 
 	for i := range ret {
@@ -358,24 +438,9 @@ func (rd *realDecoder) getCompactInt32Array() ([]int32, error) {
 }
 
 func (rd *realDecoder) getInt8Array() ([]int8, error) {
-	if rd.remaining() < 4 {
-		rd.off = len(rd.raw)
-		return nil, ErrInsufficientData
-	}
-	n := int(binary.BigEndian.Uint32(rd.raw[rd.off:]))
-	rd.off += 4
-
-	if rd.remaining() < 4*n {
-		rd.off = len(rd.raw)
-		return nil, ErrInsufficientData
-	}
-
-	if n == 0 {
-		return nil, nil
-	}
-
-	if n < 0 {
-		return nil, errInvalidArrayLength
+	n, err := rd.getPrimitiveArrayLength(1)
+	if err != nil || n <= 0 {
+		return nil, err
 	}
 
 	ret := make([]int8, n)
@@ -387,24 +452,9 @@ func (rd *realDecoder) getInt8Array() ([]int8, error) {
 }
 
 func (rd *realDecoder) getInt16Array() ([]int16, error) {
-	if rd.remaining() < 4 {
-		rd.off = len(rd.raw)
-		return nil, ErrInsufficientData
-	}
-	n := int(binary.BigEndian.Uint32(rd.raw[rd.off:]))
-	rd.off += 4
-
-	if rd.remaining() < 4*n {
-		rd.off = len(rd.raw)
-		return nil, ErrInsufficientData
-	}
-
-	if n == 0 {
-		return nil, nil
-	}
-
-	if n < 0 {
-		return nil, errInvalidArrayLength
+	n, err := rd.getPrimitiveArrayLength(2)
+	if err != nil || n <= 0 {
+		return nil, err
 	}
 
 	ret := make([]int16, n)
@@ -416,24 +466,9 @@ func (rd *realDecoder) getInt16Array() ([]int16, error) {
 }
 
 func (rd *realDecoder) getInt32Array() ([]int32, error) {
-	if rd.remaining() < 4 {
-		rd.off = len(rd.raw)
-		return nil, ErrInsufficientData
-	}
-	n := int(binary.BigEndian.Uint32(rd.raw[rd.off:]))
-	rd.off += 4
-
-	if rd.remaining() < 4*n {
-		rd.off = len(rd.raw)
-		return nil, ErrInsufficientData
-	}
-
-	if n == 0 {
-		return nil, nil
-	}
-
-	if n < 0 {
-		return nil, errInvalidArrayLength
+	n, err := rd.getPrimitiveArrayLength(4)
+	if err != nil || n <= 0 {
+		return nil, err
 	}
 
 	ret := make([]int32, n)
@@ -445,24 +480,9 @@ func (rd *realDecoder) getInt32Array() ([]int32, error) {
 }
 
 func (rd *realDecoder) getInt64Array() ([]int64, error) {
-	if rd.remaining() < 4 {
-		rd.off = len(rd.raw)
-		return nil, ErrInsufficientData
-	}
-	n := int(binary.BigEndian.Uint32(rd.raw[rd.off:]))
-	rd.off += 4
-
-	if rd.remaining() < 8*n {
-		rd.off = len(rd.raw)
-		return nil, ErrInsufficientData
-	}
-
-	if n == 0 {
-		return nil, nil
-	}
-
-	if n < 0 {
-		return nil, errInvalidArrayLength
+	n, err := rd.getPrimitiveArrayLength(8)
+	if err != nil || n <= 0 {
+		return nil, err
 	}
 
 	ret := make([]int64, n)
@@ -475,18 +495,22 @@ func (rd *realDecoder) getInt64Array() ([]int64, error) {
 
 func (rd *realDecoder) getStringArray() ([]string, error) {
 	if rd.remaining() < 4 {
-		rd.off = len(rd.raw)
-		return nil, ErrInsufficientData
+		return nil, rd.exhaust()
 	}
-	n := int(binary.BigEndian.Uint32(rd.raw[rd.off:]))
+	n := int(int32(binary.BigEndian.Uint32(rd.raw[rd.off:])))
 	rd.off += 4
 
-	if n == 0 {
+	if n == 0 || n == -1 {
 		return nil, nil
 	}
 
 	if n < 0 {
-		return nil, errInvalidArrayLength
+		return nil, rd.fail(errInvalidArrayLength)
+	}
+
+	// every string takes at least two bytes for its length
+	if 2*n > rd.remaining() {
+		return nil, rd.exhaust()
 	}
 
 	ret := make([]string, n)
@@ -501,12 +525,46 @@ func (rd *realDecoder) getStringArray() ([]string, error) {
 	return ret, nil
 }
 
+func (rd *realDecoder) getCompactStringArray() ([]string, error) {
+	n, err := rd.getCompactArrayLength()
+	if err != nil || n <= 0 {
+		return nil, err
+	}
+
+	ret := make([]string, n)
+	for i := range ret {
+		str, err := rd.getCompactString()
+		if err != nil {
+			return nil, err
+		}
+
+		ret[i] = str
+	}
+	return ret, nil
+}
+
 func (rd *realDecoder) getUUID() (uuid.UUID, error) {
-	return uuid.UUID{}, nil
+	bytes, err := rd.getRawBytes(16)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	return uuid.FromBytes(bytes)
 }
 
 func (rd *realDecoder) getUUIDArray() ([]uuid.UUID, error) {
-	return []uuid.UUID{}, nil
+	n, err := rd.getArrayLength()
+	if err != nil || n <= 0 {
+		return nil, err
+	}
+
+	ret := make([]uuid.UUID, n)
+	for i := range ret {
+		if ret[i], err = rd.getUUID(); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
 }
 
 // subsets
@@ -525,10 +583,9 @@ func (rd *realDecoder) getSubset(length int) (packetDecoder, error) {
 
 func (rd *realDecoder) getRawBytes(length int) ([]byte, error) {
 	if length < 0 {
-		return nil, errInvalidByteSliceLength
+		return nil, rd.fail(errInvalidByteSliceLength)
 	} else if length > rd.remaining() {
-		rd.off = len(rd.raw)
-		return nil, ErrInsufficientData
+		return nil, rd.exhaust()
 	}
 
 	start := rd.off
@@ -538,7 +595,7 @@ func (rd *realDecoder) getRawBytes(length int) ([]byte, error) {
 
 func (rd *realDecoder) peek(offset, length int) (packetDecoder, error) {
 	if rd.remaining() < offset+length {
-		return nil, ErrInsufficientData
+		return nil, rd.fail(ErrInsufficientData)
 	}
 	off := rd.off + offset
 	return &realDecoder{raw: rd.raw[off : off+length]}, nil
@@ -547,7 +604,7 @@ func (rd *realDecoder) peek(offset, length int) (packetDecoder, error) {
 func (rd *realDecoder) peekInt8(offset int) (int8, error) {
 	const byteLen = 1
 	if rd.remaining() < offset+byteLen {
-		return -1, ErrInsufficientData
+		return -1, rd.fail(ErrInsufficientData)
 	}
 	return int8(rd.raw[rd.off+offset]), nil
 }
@@ -561,8 +618,7 @@ func (rd *realDecoder) push(in pushDecoder) error {
 
 	reserve = in.reserveLength()
 	if rd.remaining() < reserve {
-		rd.off = len(rd.raw)
-		return ErrInsufficientData
+		return rd.exhaust()
 	}
 
 	rd.stack = append(rd.stack, in)
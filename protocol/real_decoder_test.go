@@ -0,0 +1,143 @@
+package protocol
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRealDecoder_TruncatedCompactFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    []byte
+		decode func(rd *realDecoder) error
+	}{
+		{name: "compact string", raw: []byte{10, 'a', 'b'}, decode: func(rd *realDecoder) error { _, err := rd.getCompactString(); return err }},
+		{name: "compact nullable string", raw: []byte{10, 'a', 'b'}, decode: func(rd *realDecoder) error { _, err := rd.getCompactNullableString(); return err }},
+		{name: "compact array length", raw: []byte{0xff, 0xff, 0x03, 1}, decode: func(rd *realDecoder) error { _, err := rd.getCompactArrayLength(); return err }},
+		{name: "compact int32 array", raw: []byte{3, 0, 0, 0, 1}, decode: func(rd *realDecoder) error { _, err := rd.getCompactInt32Array(); return err }},
+		{name: "string array", raw: []byte{0x7f, 0xff, 0xff, 0xff, 0, 1, 'a'}, decode: func(rd *realDecoder) error { _, err := rd.getStringArray(); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.decode(&realDecoder{raw: tt.raw}); !errors.Is(err, ErrInsufficientData) {
+				t.Errorf("error = %v, want %v", err, ErrInsufficientData)
+			}
+		})
+	}
+}
+
+func TestRealDecoder_ArrayLengths(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     []byte
+		wantErr error
+	}{
+		{name: "null array", raw: []byte{0xff, 0xff, 0xff, 0xff}},
+		{name: "negative length", raw: []byte{0xff, 0xff, 0xff, 0xfe}, wantErr: errInvalidArrayLength},
+		{name: "longer than the data", raw: []byte{0, 0, 0, 2, 0, 0, 0, 1}, wantErr: ErrInsufficientData},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&realDecoder{raw: tt.raw}).getInt32Array()
+			if !errors.Is(err, tt.wantErr) || got != nil {
+				t.Errorf("getInt32Array() = %v, %v, want no elements and error %v", got, err, tt.wantErr)
+			}
+		})
+	}
+
+	// a compact length close to 2^64 must not overflow into a negative slice length
+	raw := []byte{0x81, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x01, 0, 0, 0, 1}
+	if _, err := (&realDecoder{raw: raw}).getCompactInt32Array(); !errors.Is(err, ErrInsufficientData) {
+		t.Errorf("getCompactInt32Array() error = %v, want %v", err, ErrInsufficientData)
+	}
+}
+
+func TestRealDecoder_getArrayLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     []byte
+		want    int
+		wantErr error
+	}{
+		{name: "null", raw: []byte{0xff, 0xff, 0xff, 0xff}, want: -1},
+		{name: "empty", raw: []byte{0, 0, 0, 0}, want: 0},
+		{name: "negative", raw: []byte{0x80, 0, 0, 0}, want: -1, wantErr: errInvalidArrayLength},
+		{name: "longer than the buffer", raw: []byte{0, 0, 0, 3, 1, 2}, want: -1, wantErr: ErrInsufficientData},
+		{name: "absurd", raw: []byte{0x7f, 0xff, 0xff, 0xff}, want: -1, wantErr: ErrInsufficientData},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&realDecoder{raw: tt.raw}).getArrayLength()
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("getArrayLength() = %d, %v, want %d, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRealDecoder_getCompactArrayLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     []byte
+		want    int
+		wantErr error
+	}{
+		{name: "null", raw: []byte{0}, want: -1},
+		{name: "empty", raw: []byte{1}, want: 0},
+		{name: "longer than the buffer", raw: []byte{4, 1, 2}, want: -1, wantErr: ErrInsufficientData},
+		{name: "absurd", raw: []byte{0xff, 0xff, 0xff, 0xff, 0x0f}, want: -1, wantErr: ErrInsufficientData},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&realDecoder{raw: tt.raw}).getCompactArrayLength()
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("getCompactArrayLength() = %d, %v, want %d, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRealDecoder_Bytes(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     []byte
+		decode  func(rd *realDecoder) ([]byte, error)
+		want    []byte
+		wantErr error
+	}{
+		{name: "bytes", raw: []byte{0, 0, 0, 2, 'a', 'b', 'c'}, decode: (*realDecoder).getBytes, want: []byte("ab")},
+		{name: "null bytes", raw: []byte{0xff, 0xff, 0xff, 0xff}, decode: (*realDecoder).getBytes},
+		{name: "bytes with negative length", raw: []byte{0xff, 0xff, 0xff, 0xfe, 'a'}, decode: (*realDecoder).getBytes, wantErr: errInvalidByteSliceLength},
+		{name: "bytes longer than the data", raw: []byte{0, 0, 0, 3, 'a', 'b'}, decode: (*realDecoder).getBytes, wantErr: ErrInsufficientData},
+		{name: "bytes with the maximum length", raw: []byte{0x7f, 0xff, 0xff, 0xff, 'a'}, decode: (*realDecoder).getBytes, wantErr: ErrInsufficientData},
+		{name: "compact bytes", raw: []byte{3, 'a', 'b', 'c'}, decode: (*realDecoder).getCompactBytes, want: []byte("ab")},
+		{name: "null compact bytes", raw: []byte{0}, decode: (*realDecoder).getCompactBytes},
+		{name: "compact bytes longer than the data", raw: []byte{4, 'a', 'b'}, decode: (*realDecoder).getCompactBytes, wantErr: ErrInsufficientData},
+		{
+			name:    "compact bytes with a length close to 2^64",
+			raw:     []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01, 'a'},
+			decode:  (*realDecoder).getCompactBytes,
+			wantErr: errInvalidByteSliceLength,
+		},
+		{name: "raw bytes", raw: []byte{'a', 'b', 'c'}, decode: func(rd *realDecoder) ([]byte, error) { return rd.getRawBytes(2) }, want: []byte("ab")},
+		{name: "raw bytes longer than the data", raw: []byte{'a', 'b'}, decode: func(rd *realDecoder) ([]byte, error) { return rd.getRawBytes(3) }, wantErr: ErrInsufficientData},
+		{name: "raw bytes with negative length", raw: []byte{'a'}, decode: func(rd *realDecoder) ([]byte, error) { return rd.getRawBytes(-1) }, wantErr: errInvalidByteSliceLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rd := &realDecoder{raw: tt.raw}
+			got, err := tt.decode(rd)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if string(got) != string(tt.want) || (got == nil) != (tt.want == nil) {
+				t.Errorf("bytes = %v, want %v", got, tt.want)
+			}
+
+			// a failed read leaves nothing to read, so a decoder never continues in the middle of a field
+			if err == ErrInsufficientData && rd.remaining() != 0 {
+				t.Errorf("remaining = %d after reading past the end, want 0", rd.remaining())
+			}
+		})
+	}
+}
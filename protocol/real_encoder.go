@@ -9,9 +9,10 @@ import (
 )
 
 type realEncoder struct {
-	raw   []byte
-	off   int
-	stack []pushEncoder
+	raw      []byte
+	off      int
+	stack    []pushEncoder
+	flexible flexibleEncoder
 }
 
 // primitives
@@ -52,6 +53,15 @@ func (re *realEncoder) putUVarint(in uint64) {
 	re.off += binary.PutUvarint(re.raw[re.off:], in)
 }
 
+// putVarint32 writes a zig-zag varint of an int32, which has the same bytes as the varint of the int64 of the same value.
+func (re *realEncoder) putVarint32(in int32) {
+	re.putVarint(int64(in))
+}
+
+func (re *realEncoder) putUVarint32(in uint32) {
+	re.putUVarint(uint64(in))
+}
+
 func (re *realEncoder) putFloat64(in float64) {
 	binary.BigEndian.PutUint64(re.raw[re.off:], math.Float64bits(in))
 	re.off += 8
@@ -150,6 +160,21 @@ func (re *realEncoder) putStringArray(in []string) error {
 	return nil
 }
 
+func (re *realEncoder) putCompactStringArray(in []string) error {
+	err := re.putCompactArrayLength(len(in))
+	if err != nil {
+		return err
+	}
+
+	for _, val := range in {
+		if err := re.putCompactString(val); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (re *realEncoder) putCompactInt8Array(in []int8) error {
 	if in == nil {
 		return errors.New("expected int8 array to be non null")
@@ -0,0 +1,97 @@
+package protocol
+
+// RecordHeader is a key-value pair attached to a record.
+type RecordHeader struct {
+	Key   string
+	Value []byte
+}
+
+// Record is a single record inside a v2 record batch. Timestamps and offsets are stored as deltas
+// to the base timestamp and base offset of the batch that contains the record.
+// https://kafka.apache.org/documentation/#record
+type Record struct {
+	Attributes     int8
+	TimestampDelta int64
+	OffsetDelta    int32
+	Key            []byte
+	Value          []byte
+	Headers        []RecordHeader
+}
+
+// encode writes the record body. The varint length prefix of the record is written by the record batch.
+func (r *Record) encode(pe packetEncoder) error {
+	pe.putInt8(r.Attributes)
+	pe.putVarint(r.TimestampDelta)
+	pe.putVarint32(r.OffsetDelta)
+
+	if err := pe.putVarintBytes(r.Key); err != nil {
+		return err
+	}
+
+	if err := pe.putVarintBytes(r.Value); err != nil {
+		return err
+	}
+
+	pe.putVarint32(int32(len(r.Headers)))
+	for _, header := range r.Headers {
+		if err := pe.putVarintBytes([]byte(header.Key)); err != nil {
+			return err
+		}
+
+		if err := pe.putVarintBytes(header.Value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Record) decode(pd packetDecoder) (err error) {
+	if r.Attributes, err = pd.getInt8(); err != nil {
+		return err
+	}
+
+	if r.TimestampDelta, err = pd.getVarint(); err != nil {
+		return err
+	}
+
+	if r.OffsetDelta, err = pd.getVarint32(); err != nil {
+		return err
+	}
+
+	if r.Key, err = pd.getVarintBytes(); err != nil {
+		return err
+	}
+
+	if r.Value, err = pd.getVarintBytes(); err != nil {
+		return err
+	}
+
+	numHeaders, err := pd.getVarint32()
+	if err != nil {
+		return err
+	}
+
+	if numHeaders < 0 || int(numHeaders) > pd.remaining() {
+		return errInvalidArrayLength
+	}
+
+	r.Headers = nil
+	if numHeaders > 0 {
+		r.Headers = make([]RecordHeader, numHeaders)
+	}
+
+	for i := range r.Headers {
+		key, err := pd.getVarintBytes()
+		if err != nil {
+			return err
+		}
+		r.Headers[i].Key = string(key)
+
+		if r.Headers[i].Value, err = pd.getVarintBytes(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package protocol
 
 import (
+	"encoding/binary"
+	"fmt"
 	"time"
 )
 
@@ -27,13 +29,22 @@ const (
 	hasDeleteHorizonMsBit = 0x40 // attr bit 6
 )
 
-// RecordBatch is the struct representation of the Kafka primitive type records
+const (
+	// recordBatchMagic is the only message format version supported by the broker.
+	recordBatchMagic int8 = 2
+	// recordBatchOverhead is the size of the base offset and batch length fields,
+	// which precede the part of the batch counted in BatchLength.
+	recordBatchOverhead = 12
+	// recordBatchHeaderSize is the size of a v2 record batch without any records.
+	recordBatchHeaderSize = 61
+	// recordBatchCRCOffset is the position of the CRC, after the partition leader epoch and the magic.
+	// The CRC covers the rest of the batch, from the attributes on.
+	recordBatchCRCOffset = recordBatchOverhead + 5
+)
+
+// RecordBatch is the struct representation of a v2 record batch, the unit in which records are produced, stored and fetched.
 // https://kafka.apache.org/documentation/#recordbatch
 type RecordBatch struct {
-	size       int32
-	attributes int16
-
-	Version              int16
 	BaseOffset           int64
 	BatchLength          int32
 	PartitionLeaderEpoch int32
@@ -50,110 +61,283 @@ type RecordBatch struct {
 	ProducerId           int64
 	ProducerEpoch        int16
 	BaseSequence         int32
-	RecordsLen           int
-	Records              []byte
+	Records              []Record
+
+	// raw holds the batch as it was decoded. Batches read from the wire are encoded byte for byte,
+	// so the CRC stays valid. Only BaseOffset and PartitionLeaderEpoch, which are not covered by the CRC,
+	// are taken from the struct fields.
+	raw []byte
+}
+
+// Size returns the number of bytes the batch takes on the wire.
+func (b *RecordBatch) Size() int {
+	if b.raw != nil {
+		return len(b.raw)
+	}
+
+	return recordBatchOverhead + int(b.BatchLength)
+}
+
+// VerifyCRC checks the CRC-32C of a decoded batch against its bytes from the attributes to the end of the batch,
+// so corrupt batches are rejected rather than stored. Batches built by the broker get their CRC when they are encoded.
+func (b *RecordBatch) VerifyCRC() error {
+	if b.raw == nil {
+		return nil
+	}
+
+	c := &crc32Field{startOffset: recordBatchCRCOffset}
+
+	return c.check(len(b.raw), b.raw)
+}
+
+// SetLogAppendTime marks the batch as LogAppendTime with the append time as its max timestamp, which all its records
+// share. Like Kafka, a decoded batch is updated in place, with a new CRC, so its records aren't encoded again.
+func (b *RecordBatch) SetLogAppendTime(appendTime time.Time) {
+	b.TimestampType = LogAppendTime
+	// the timestamps have millisecond precision on the wire
+	b.MaxTimestamp = getTimeFromMillis(getMillisFromTime(appendTime))
+
+	if b.raw == nil {
+		return
+	}
+
+	// the attributes and the max timestamp follow the CRC, which covers them
+	binary.BigEndian.PutUint16(b.raw[recordBatchCRCOffset+4:], uint16(b.attributes()))
+	binary.BigEndian.PutUint64(b.raw[recordBatchCRCOffset+18:], uint64(getMillisFromTime(appendTime)))
+
+	c := &crc32Field{startOffset: recordBatchCRCOffset}
+	_ = c.run(len(b.raw), b.raw)
 }
 
-func (r *RecordBatch) encode(pe packetEncoder, version int16) (err error) {
-	pe.putInt64(r.BaseOffset)
-	pe.putInt32(r.BatchLength)
-	pe.putInt32(r.PartitionLeaderEpoch)
-	pe.putInt8(r.Magic)
-
-	// TODO: This has to be dynamically calculated
-	pe.putUint32(r.CRC)
-	// TODO: This has to be dynamically calculated as well
-	pe.putInt16(r.attributes)
-
-	pe.putInt32(r.LastOffsetDelta)
-	pe.putInt64(getMillisFromTime(r.BaseTimestamp))
-	pe.putInt64(getMillisFromTime(r.MaxTimestamp))
-	pe.putInt64(r.ProducerId)
-	pe.putInt16(r.ProducerEpoch)
-	pe.putInt32(r.BaseSequence)
-	pe.putBytes(r.Records)
-
-	return err
+// LastOffset returns the offset of the last record in the batch.
+func (b *RecordBatch) LastOffset() int64 {
+	return b.BaseOffset + int64(b.LastOffsetDelta)
 }
 
-func (r *RecordBatch) decode(pd packetDecoder, version int16) (err error) {
-	r.Version = version
+// RecordTimestamp returns the timestamp of the i-th record in the batch.
+// With LogAppendTime, all records share the timestamp set by the broker in MaxTimestamp.
+func (b *RecordBatch) RecordTimestamp(i int) time.Time {
+	if b.TimestampType == LogAppendTime {
+		return b.MaxTimestamp
+	}
+
+	return b.BaseTimestamp.Add(time.Duration(b.Records[i].TimestampDelta) * time.Millisecond)
+}
+
+func (b *RecordBatch) attributes() int16 {
+	attributes := int16(b.CompressionType) & compressionCodecBit
+	if b.TimestampType == LogAppendTime {
+		attributes |= timestampTypeBit
+	}
+	if b.IsTransactional {
+		attributes |= isTransactionalBit
+	}
+	if b.IsControlBatch {
+		attributes |= isControlBit
+	}
+	if b.HasDeleteHorizonMs {
+		attributes |= hasDeleteHorizonMsBit
+	}
+
+	return attributes
+}
 
-	if r.size, err = pd.getInt32(); err != nil {
+func (b *RecordBatch) setAttributes(attributes int16) {
+	b.CompressionType = CompressionType(attributes & compressionCodecBit)
+	// If the timestamp type is LogAppendType, the broker should set the record batch timestamp,
+	// overriding the timestamp set by the client.
+	b.TimestampType = CreateTime
+	if attributes&timestampTypeBit == timestampTypeBit {
+		b.TimestampType = LogAppendTime
+	}
+	b.IsTransactional = attributes&isTransactionalBit == isTransactionalBit
+	b.IsControlBatch = attributes&isControlBit == isControlBit
+	b.HasDeleteHorizonMs = attributes&hasDeleteHorizonMsBit == hasDeleteHorizonMsBit
+}
+
+func (b *RecordBatch) encode(pe packetEncoder) error {
+	if b.raw != nil {
+		pe.putInt64(b.BaseOffset)
+		if err := pe.putRawBytes(b.raw[8:12]); err != nil {
+			return err
+		}
+		pe.putInt32(b.PartitionLeaderEpoch)
+		return pe.putRawBytes(b.raw[16:])
+	}
+
+	pe.putInt64(b.BaseOffset)
+	pe.push(newLengthField())
+	pe.putInt32(b.PartitionLeaderEpoch)
+	pe.putInt8(recordBatchMagic)
+	pe.push(newCRC32Field())
+	pe.putInt16(b.attributes())
+	pe.putInt32(b.LastOffsetDelta)
+	pe.putInt64(getMillisFromTime(b.BaseTimestamp))
+	pe.putInt64(getMillisFromTime(b.MaxTimestamp))
+	pe.putInt64(b.ProducerId)
+	pe.putInt16(b.ProducerEpoch)
+	pe.putInt32(b.BaseSequence)
+	pe.putInt32(int32(len(b.Records)))
+
+	records, err := Encode(recordList(b.Records))
+	if err != nil {
 		return err
 	}
 
-	if r.BaseOffset, err = pd.getInt64(); err != nil {
+	if records, err = compress(b.CompressionType, records); err != nil {
 		return err
 	}
 
-	if r.BatchLength, err = pd.getInt32(); err != nil {
+	if err := pe.putRawBytes(records); err != nil {
 		return err
 	}
 
-	if r.PartitionLeaderEpoch, err = pd.getInt32(); err != nil {
+	// pop the CRC first, it covers the bytes up to the end of the batch and is itself part of the batch length
+	if err := pe.pop(); err != nil {
 		return err
 	}
 
-	if r.Magic, err = pd.getInt8(); err != nil {
+	return pe.pop()
+}
+
+func (b *RecordBatch) decode(pd packetDecoder) (err error) {
+	if b.BaseOffset, err = pd.getInt64(); err != nil {
 		return err
 	}
 
-	if r.CRC, err = pd.getUint32(); err != nil {
+	if b.BatchLength, err = pd.getInt32(); err != nil {
 		return err
 	}
 
-	if r.attributes, err = pd.getInt16(); err != nil {
+	if b.BatchLength < recordBatchHeaderSize-recordBatchOverhead {
+		return fmt.Errorf("invalid record batch length %d", b.BatchLength)
+	}
+
+	data, err := pd.getRawBytes(int(b.BatchLength))
+	if err != nil {
 		return err
 	}
 
-	// parse attributes and append corresponding flags in the struct
-	r.CompressionType = CompressionType(int8(r.attributes) & compressionCodecBit)
-	// If the timestamp type is LogAppendType, the broker should set the record batch timestamp,
-	// overriding the timestamp set by the client. The bindings won't set the timestamp automatically,
-	// this will be left to the client software that consumes the library.
-	r.TimestampType = TimestampType(int8(r.attributes) & timestampTypeBit)
-	r.IsTransactional = r.attributes&isTransactionalBit == isTransactionalBit
-	r.IsControlBatch = r.attributes&isControlBit == isControlBit
+	// keep a private copy of the batch, so it doesn't hold on to the rest of the request buffer.
+	b.raw = make([]byte, recordBatchOverhead+len(data))
+	binary.BigEndian.PutUint64(b.raw, uint64(b.BaseOffset))
+	binary.BigEndian.PutUint32(b.raw[8:], uint32(b.BatchLength))
+	copy(b.raw[recordBatchOverhead:], data)
+
+	bd := &realDecoder{raw: b.raw[recordBatchOverhead:]}
+
+	if b.PartitionLeaderEpoch, err = bd.getInt32(); err != nil {
+		return err
+	}
+
+	if b.Magic, err = bd.getInt8(); err != nil {
+		return err
+	}
+
+	if b.Magic != recordBatchMagic {
+		return fmt.Errorf("unsupported record batch magic %d", b.Magic)
+	}
 
-	if r.LastOffsetDelta, err = pd.getInt32(); err != nil {
+	if b.CRC, err = bd.getUint32(); err != nil {
 		return err
 	}
 
-	baseTime, err := pd.getInt64()
+	attributes, err := bd.getInt16()
 	if err != nil {
 		return err
 	}
-	r.BaseTimestamp = getTimeFromMillis(baseTime)
+	b.setAttributes(attributes)
+
+	if b.LastOffsetDelta, err = bd.getInt32(); err != nil {
+		return err
+	}
 
-	maxTime, err := pd.getInt64()
+	baseTime, err := bd.getInt64()
 	if err != nil {
 		return err
 	}
-	r.MaxTimestamp = getTimeFromMillis(maxTime)
+	b.BaseTimestamp = getTimeFromMillis(baseTime)
 
-	if r.ProducerId, err = pd.getInt64(); err != nil {
+	maxTime, err := bd.getInt64()
+	if err != nil {
 		return err
 	}
+	b.MaxTimestamp = getTimeFromMillis(maxTime)
 
-	if r.ProducerEpoch, err = pd.getInt16(); err != nil {
+	if b.ProducerId, err = bd.getInt64(); err != nil {
 		return err
 	}
 
-	if r.BaseSequence, err = pd.getInt32(); err != nil {
+	if b.ProducerEpoch, err = bd.getInt16(); err != nil {
 		return err
 	}
 
-	if r.RecordsLen, err = pd.getArrayLength(); err != nil {
+	if b.BaseSequence, err = bd.getInt32(); err != nil {
 		return err
 	}
 
-	remainingBytes, err := pd.getRawBytes(pd.remaining())
+	numRecords, err := bd.getInt32()
 	if err != nil {
 		return err
 	}
 
-	r.Records = remainingBytes
+	records, err := bd.getRawBytes(bd.remaining())
+	if err != nil {
+		return err
+	}
+
+	if records, err = decompress(b.CompressionType, records); err != nil {
+		return err
+	}
+
+	return b.decodeRecords(numRecords, records)
+}
+
+func (b *RecordBatch) decodeRecords(numRecords int32, records []byte) error {
+	// every record takes at least one byte on the wire, anything above that is a corrupt count.
+	if numRecords < 0 || int(numRecords) > len(records) {
+		return fmt.Errorf("invalid record count %d", numRecords)
+	}
+
+	b.Records = make([]Record, numRecords)
+	rd := &realDecoder{raw: records}
+	for i := range b.Records {
+		length, err := rd.getVarint()
+		if err != nil {
+			return err
+		}
+
+		body, err := rd.getRawBytes(int(length))
+		if err != nil {
+			return err
+		}
+
+		if err := Decode(body, &b.Records[i]); err != nil {
+			return err
+		}
+	}
+
+	if rd.remaining() != 0 {
+		return fmt.Errorf("record batch has %d trailing bytes", rd.remaining())
+	}
+
+	return nil
+}
+
+// recordList encodes the records of a batch, each prefixed with its varint length.
+type recordList []Record
+
+func (l recordList) encode(pe packetEncoder) error {
+	for i := range l {
+		body, err := Encode(&l[i])
+		if err != nil {
+			return err
+		}
+
+		if err := pe.putVarintBytes(body); err != nil {
+			return err
+		}
+	}
 
-	return err
+	return nil
 }
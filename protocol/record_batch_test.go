@@ -0,0 +1,256 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"testing"
+	"time"
+)
+
+func testRecordBatch() RecordBatch {
+	return RecordBatch{
+		BaseOffset:      0,
+		LastOffsetDelta: 1,
+		BaseTimestamp:   time.UnixMilli(1700000000000),
+		MaxTimestamp:    time.UnixMilli(1700000000005),
+		ProducerId:      -1,
+		ProducerEpoch:   -1,
+		BaseSequence:    -1,
+		Records: []Record{
+			{OffsetDelta: 0, Key: []byte("key"), Value: []byte("value")},
+			{TimestampDelta: 5, OffsetDelta: 1, Value: []byte("value-2"), Headers: []RecordHeader{{Key: "header", Value: []byte("header-value")}}},
+		},
+	}
+}
+
+func TestRecordBatch_RoundTrip(t *testing.T) {
+	batch := testRecordBatch()
+
+	buf, err := Encode(&batch)
+	if err != nil {
+		t.Fatalf("error encoding batch: %v", err)
+	}
+
+	if length := binary.BigEndian.Uint32(buf[8:]); int(length) != len(buf)-recordBatchOverhead {
+		t.Errorf("batch length = %d, want %d", length, len(buf)-recordBatchOverhead)
+	}
+
+	if crc := binary.BigEndian.Uint32(buf[17:]); crc != crc32.Checksum(buf[21:], castagnoliTable) {
+		t.Errorf("CRC %#x doesn't match the batch contents", crc)
+	}
+
+	decoded := RecordBatch{}
+	if err := Decode(buf, &decoded); err != nil {
+		t.Fatalf("error decoding batch: %v", err)
+	}
+
+	if len(decoded.Records) != 2 {
+		t.Fatalf("decoded %d records, want 2", len(decoded.Records))
+	}
+	if string(decoded.Records[0].Key) != "key" || string(decoded.Records[1].Value) != "value-2" {
+		t.Errorf("unexpected records %+v", decoded.Records)
+	}
+	if decoded.Records[1].Headers[0].Key != "header" {
+		t.Errorf("unexpected record headers %+v", decoded.Records[1].Headers)
+	}
+	if !decoded.MaxTimestamp.Equal(batch.MaxTimestamp) {
+		t.Errorf("max timestamp = %v, want %v", decoded.MaxTimestamp, batch.MaxTimestamp)
+	}
+
+	// a decoded batch is encoded byte for byte, with the new base offset
+	decoded.BaseOffset = 42
+	reencoded, err := Encode(&decoded)
+	if err != nil {
+		t.Fatalf("error encoding decoded batch: %v", err)
+	}
+	if binary.BigEndian.Uint64(reencoded) != 42 {
+		t.Errorf("base offset = %d, want 42", binary.BigEndian.Uint64(reencoded))
+	}
+	if !bytes.Equal(reencoded[8:], buf[8:]) {
+		t.Error("re-encoded batch differs from the original")
+	}
+}
+
+func TestRecordBatch_Attributes(t *testing.T) {
+	batch := testRecordBatch()
+	batch.TimestampType = LogAppendTime
+	batch.IsTransactional = true
+
+	buf, err := Encode(&batch)
+	if err != nil {
+		t.Fatalf("error encoding batch: %v", err)
+	}
+
+	if attributes := binary.BigEndian.Uint16(buf[21:]); attributes != timestampTypeBit|isTransactionalBit {
+		t.Errorf("attributes = %#x, want %#x", attributes, timestampTypeBit|isTransactionalBit)
+	}
+
+	decoded := RecordBatch{}
+	if err := Decode(buf, &decoded); err != nil {
+		t.Fatalf("error decoding batch: %v", err)
+	}
+	if decoded.TimestampType != LogAppendTime || !decoded.IsTransactional || decoded.IsControlBatch {
+		t.Errorf("unexpected attributes in decoded batch %+v", decoded)
+	}
+
+	// flip the compression codec to zstd, which is not supported yet
+	binary.BigEndian.PutUint16(buf[21:], timestampTypeBit|uint16(CompressionZstd))
+	if err := Decode(buf, &RecordBatch{}); !errors.Is(err, errUnsupportedCompressionCodec) {
+		t.Errorf("expected unsupported compression error, got %v", err)
+	}
+}
+
+func TestRecordBatch_Compression(t *testing.T) {
+	for _, codec := range []CompressionType{CompressionGzip, CompressionSnappy, CompressionLz4} {
+		batch := testRecordBatch()
+		batch.CompressionType = codec
+		// add some more records, so the compression has something to work with
+		for i := 2; i < 10; i++ {
+			batch.Records = append(batch.Records, Record{OffsetDelta: int32(i), Value: bytes.Repeat([]byte("value"), 10)})
+		}
+		batch.LastOffsetDelta = int32(len(batch.Records) - 1)
+
+		buf, err := Encode(&batch)
+		if err != nil {
+			t.Fatalf("codec %d: error encoding batch: %v", codec, err)
+		}
+
+		uncompressed := batch
+		uncompressed.CompressionType = CompressionNone
+		uncompressedBuf, err := Encode(&uncompressed)
+		if err != nil {
+			t.Fatalf("codec %d: error encoding batch: %v", codec, err)
+		}
+		if len(buf) >= len(uncompressedBuf) {
+			t.Errorf("codec %d: compressed batch has %d bytes, uncompressed %d", codec, len(buf), len(uncompressedBuf))
+		}
+
+		decoded := RecordBatch{}
+		if err := Decode(buf, &decoded); err != nil {
+			t.Fatalf("codec %d: error decoding batch: %v", codec, err)
+		}
+
+		if decoded.CompressionType != codec {
+			t.Errorf("compression type = %d, want %d", decoded.CompressionType, codec)
+		}
+		if len(decoded.Records) != len(batch.Records) {
+			t.Fatalf("codec %d: decoded %d records, want %d", codec, len(decoded.Records), len(batch.Records))
+		}
+		for i := range batch.Records {
+			if !bytes.Equal(decoded.Records[i].Value, batch.Records[i].Value) || decoded.Records[i].OffsetDelta != batch.Records[i].OffsetDelta {
+				t.Errorf("codec %d: record %d = %+v, want %+v", codec, i, decoded.Records[i], batch.Records[i])
+			}
+		}
+	}
+}
+
+func TestProduceRequest_Records(t *testing.T) {
+	for _, version := range []int16{3, 9} {
+		req := ProduceRequest{
+			Version:   version,
+			Acks:      -1,
+			TimeoutMs: 1000,
+			TopicData: []TopicProduceData{{
+				Name:          "test-topic",
+				PartitionData: []PartitionProduceData{{Index: 0, Records: Records{Batches: []RecordBatch{testRecordBatch()}}}},
+			}},
+		}
+
+		buf, err := Encode(&req)
+		if err != nil {
+			t.Fatalf("v%d: error encoding request: %v", version, err)
+		}
+
+		decoded := ProduceRequest{}
+		if _, err := VersionedDecode(buf, &decoded, version); err != nil {
+			t.Fatalf("v%d: error decoding request: %v", version, err)
+		}
+
+		batches := decoded.TopicData[0].PartitionData[0].Records.Batches
+		if len(batches) != 1 || len(batches[0].Records) != 2 {
+			t.Errorf("v%d: unexpected batches %+v", version, batches)
+		}
+	}
+}
+
+func TestRecordBatch_VerifyCRC(t *testing.T) {
+	batch := testRecordBatch()
+	if err := batch.VerifyCRC(); err != nil {
+		t.Errorf("VerifyCRC() of a batch built by the broker = %v", err)
+	}
+
+	buf, err := Encode(&batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded RecordBatch
+	if err := Decode(buf, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.VerifyCRC(); err != nil {
+		t.Errorf("VerifyCRC() of a valid batch = %v", err)
+	}
+
+	// the base offset and the partition leader epoch are not covered by the CRC
+	decoded.BaseOffset = 42
+	decoded.PartitionLeaderEpoch = 7
+	if err := decoded.VerifyCRC(); err != nil {
+		t.Errorf("VerifyCRC() after assigning the offsets = %v", err)
+	}
+
+	// flip a bit of the value of the last record
+	buf[bytes.Index(buf, []byte("value-2"))] ^= 0x01
+	var corrupt RecordBatch
+	if err := Decode(buf, &corrupt); err != nil {
+		t.Fatal(err)
+	}
+	if err := corrupt.VerifyCRC(); err == nil {
+		t.Error("VerifyCRC() of a corrupt batch succeeded")
+	}
+}
+
+func TestRecordBatch_SetLogAppendTime(t *testing.T) {
+	appendTime := time.UnixMilli(1730473445123)
+
+	batch := testRecordBatch()
+	buf, err := Encode(&batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var produced RecordBatch
+	if err := Decode(buf, &produced); err != nil {
+		t.Fatal(err)
+	}
+	built := testRecordBatch()
+
+	for name, b := range map[string]*RecordBatch{"decoded": &produced, "built": &built} {
+		b.SetLogAppendTime(appendTime)
+
+		encoded, err := Encode(b)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var decoded RecordBatch
+		if err := Decode(encoded, &decoded); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if err := decoded.VerifyCRC(); err != nil {
+			t.Errorf("%s: VerifyCRC() = %v", name, err)
+		}
+		if decoded.TimestampType != LogAppendTime || !decoded.MaxTimestamp.Equal(appendTime) {
+			t.Errorf("%s: timestamp type = %d, max timestamp = %v, want LogAppendTime at %v", name, decoded.TimestampType, decoded.MaxTimestamp, appendTime)
+		}
+		for i := range decoded.Records {
+			if ts := decoded.RecordTimestamp(i); !ts.Equal(appendTime) {
+				t.Errorf("%s: record %d timestamp = %v, want %v", name, i, ts, appendTime)
+			}
+		}
+		if !decoded.BaseTimestamp.Equal(batch.BaseTimestamp) || string(decoded.Records[1].Value) != "value-2" {
+			t.Errorf("%s: the rest of the batch changed: %+v", name, decoded)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package protocol
+
+// Records is the struct representation of the Kafka primitive type records, a nullable,
+// length-prefixed sequence of record batches.
+type Records struct {
+	Batches []RecordBatch
+}
+
+func (r *Records) encode(pe packetEncoder, version int16) error {
+	if r.Batches == nil {
+		return pe.putBytes(nil)
+	}
+
+	data := make([]byte, 0)
+	for i := range r.Batches {
+		batch, err := Encode(&r.Batches[i])
+		if err != nil {
+			return err
+		}
+		data = append(data, batch...)
+	}
+
+	return pe.putBytes(data)
+}
+
+func (r *Records) decode(pd packetDecoder, version int16) error {
+	data, err := pd.getBytes()
+	if err != nil {
+		return err
+	}
+
+	r.Batches = nil
+	if data == nil {
+		return nil
+	}
+
+	rd := &realDecoder{raw: data}
+	for rd.remaining() > 0 {
+		// a fetch response is allowed to end with a partial batch, which the client should skip
+		if rd.remaining() < recordBatchOverhead {
+			break
+		}
+		batchLength, err := rd.peek(8, 4)
+		if err != nil {
+			return err
+		}
+		length, err := batchLength.getInt32()
+		if err != nil {
+			return err
+		}
+		if int(length) > rd.remaining()-recordBatchOverhead {
+			break
+		}
+
+		batch := RecordBatch{}
+		if err := batch.decode(rd); err != nil {
+			return err
+		}
+		r.Batches = append(r.Batches, batch)
+	}
+
+	return nil
+}
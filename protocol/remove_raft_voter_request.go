@@ -34,19 +34,19 @@ func (r *RemoveRaftVoterRequest) decode(pd packetDecoder, version int16) (err er
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ClusterID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClusterID", err)
 	}
 
 	if r.VoterID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "VoterID", err)
 	}
 
 	if r.VoterDirectoryID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "VoterDirectoryID", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -68,6 +68,5 @@ func (r *RemoveRaftVoterRequest) IsValidVersion() bool {
 }
 
 func (r *RemoveRaftVoterRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -5,7 +5,7 @@ type RenewDelegationTokenRequest struct {
 	// Version defines the protocol version to use for encode and decode
 	Version int16
 	// Hmac contains the HMAC of the delegation token to be renewed.
-	Hmac []byte
+	Hmac []byte `sensitive:"true"`
 	// RenewPeriodMs contains the renewal time period in milliseconds.
 	RenewPeriodMs int64
 }
@@ -32,16 +32,16 @@ func (r *RenewDelegationTokenRequest) decode(pd packetDecoder, version int16) (e
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.Hmac, err = pd.getBytes(); err != nil {
-		return err
+		return wrapFieldError(pd, "Hmac", err)
 	}
 
 	if r.RenewPeriodMs, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "RenewPeriodMs", err)
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -67,6 +67,5 @@ func (r *RenewDelegationTokenRequest) IsValidVersion() bool {
 }
 
 func (r *RenewDelegationTokenRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
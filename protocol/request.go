@@ -0,0 +1,131 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Request is implemented by every request type of the Kafka protocol.
+type Request interface {
+	encoder
+	versionedDecoder
+	GetKey() int16
+	GetVersion() int16
+	GetHeaderVersion() int16
+	IsValidVersion() bool
+	GetRequiredVersion() int16
+}
+
+// requestConstructors maps each API key to a constructor of its request type.
+// Supporting a new API only requires the request type to be listed here and a handler to be registered in the api package.
+var requestConstructors = map[int16]func(version int16) Request{
+	0:  func(version int16) Request { return &ProduceRequest{Version: version} },
+	1:  func(version int16) Request { return &FetchRequest{Version: version} },
+	2:  func(version int16) Request { return &ListOffsetsRequest{Version: version} },
+	3:  func(version int16) Request { return &MetadataRequest{Version: version} },
+	4:  func(version int16) Request { return &LeaderAndIsrRequest{Version: version} },
+	5:  func(version int16) Request { return &StopReplicaRequest{Version: version} },
+	6:  func(version int16) Request { return &UpdateMetadataRequest{Version: version} },
+	7:  func(version int16) Request { return &ControlledShutdownRequest{Version: version} },
+	8:  func(version int16) Request { return &OffsetCommitRequest{Version: version} },
+	9:  func(version int16) Request { return &OffsetFetchRequest{Version: version} },
+	10: func(version int16) Request { return &FindCoordinatorRequest{Version: version} },
+	11: func(version int16) Request { return &JoinGroupRequest{Version: version} },
+	12: func(version int16) Request { return &HeartbeatRequest{Version: version} },
+	13: func(version int16) Request { return &LeaveGroupRequest{Version: version} },
+	14: func(version int16) Request { return &SyncGroupRequest{Version: version} },
+	15: func(version int16) Request { return &DescribeGroupsRequest{Version: version} },
+	16: func(version int16) Request { return &ListGroupsRequest{Version: version} },
+	17: func(version int16) Request { return &SaslHandshakeRequest{Version: version} },
+	18: func(version int16) Request { return &ApiVersionsRequest{Version: version} },
+	19: func(version int16) Request { return &CreateTopicsRequest{Version: version} },
+	20: func(version int16) Request { return &DeleteTopicsRequest{Version: version} },
+	21: func(version int16) Request { return &DeleteRecordsRequest{Version: version} },
+	22: func(version int16) Request { return &InitProducerIdRequest{Version: version} },
+	23: func(version int16) Request { return &OffsetForLeaderEpochRequest{Version: version} },
+	24: func(version int16) Request { return &AddPartitionsToTxnRequest{Version: version} },
+	25: func(version int16) Request { return &AddOffsetsToTxnRequest{Version: version} },
+	26: func(version int16) Request { return &EndTxnRequest{Version: version} },
+	27: func(version int16) Request { return &WriteTxnMarkersRequest{Version: version} },
+	28: func(version int16) Request { return &TxnOffsetCommitRequest{Version: version} },
+	29: func(version int16) Request { return &DescribeAclsRequest{Version: version} },
+	30: func(version int16) Request { return &CreateAclsRequest{Version: version} },
+	31: func(version int16) Request { return &DeleteAclsRequest{Version: version} },
+	32: func(version int16) Request { return &DescribeConfigsRequest{Version: version} },
+	33: func(version int16) Request { return &AlterConfigsRequest{Version: version} },
+	34: func(version int16) Request { return &AlterReplicaLogDirsRequest{Version: version} },
+	35: func(version int16) Request { return &DescribeLogDirsRequest{Version: version} },
+	36: func(version int16) Request { return &SaslAuthenticateRequest{Version: version} },
+	37: func(version int16) Request { return &CreatePartitionsRequest{Version: version} },
+	38: func(version int16) Request { return &CreateDelegationTokenRequest{Version: version} },
+	39: func(version int16) Request { return &RenewDelegationTokenRequest{Version: version} },
+	40: func(version int16) Request { return &ExpireDelegationTokenRequest{Version: version} },
+	41: func(version int16) Request { return &DescribeDelegationTokenRequest{Version: version} },
+	42: func(version int16) Request { return &DeleteGroupsRequest{Version: version} },
+	43: func(version int16) Request { return &ElectLeadersRequest{Version: version} },
+	44: func(version int16) Request { return &IncrementalAlterConfigsRequest{Version: version} },
+	45: func(version int16) Request { return &AlterPartitionReassignmentsRequest{Version: version} },
+	46: func(version int16) Request { return &ListPartitionReassignmentsRequest{Version: version} },
+	47: func(version int16) Request { return &OffsetDeleteRequest{Version: version} },
+	48: func(version int16) Request { return &DescribeClientQuotasRequest{Version: version} },
+	49: func(version int16) Request { return &AlterClientQuotasRequest{Version: version} },
+	50: func(version int16) Request { return &DescribeUserScramCredentialsRequest{Version: version} },
+	51: func(version int16) Request { return &AlterUserScramCredentialsRequest{Version: version} },
+	52: func(version int16) Request { return &VoteRequest{Version: version} },
+	53: func(version int16) Request { return &BeginQuorumEpochRequest{Version: version} },
+	54: func(version int16) Request { return &EndQuorumEpochRequest{Version: version} },
+	55: func(version int16) Request { return &DescribeQuorumRequest{Version: version} },
+	56: func(version int16) Request { return &AlterPartitionRequest{Version: version} },
+	57: func(version int16) Request { return &UpdateFeaturesRequest{Version: version} },
+	58: func(version int16) Request { return &EnvelopeRequest{Version: version} },
+	59: func(version int16) Request { return &FetchSnapshotRequest{Version: version} },
+	60: func(version int16) Request { return &DescribeClusterRequest{Version: version} },
+	61: func(version int16) Request { return &DescribeProducersRequest{Version: version} },
+	62: func(version int16) Request { return &BrokerRegistrationRequest{Version: version} },
+	63: func(version int16) Request { return &BrokerHeartbeatRequest{Version: version} },
+	64: func(version int16) Request { return &UnregisterBrokerRequest{Version: version} },
+	65: func(version int16) Request { return &DescribeTransactionsRequest{Version: version} },
+	66: func(version int16) Request { return &ListTransactionsRequest{Version: version} },
+	67: func(version int16) Request { return &AllocateProducerIdsRequest{Version: version} },
+	68: func(version int16) Request { return &ConsumerGroupHeartbeatRequest{Version: version} },
+	69: func(version int16) Request { return &ConsumerGroupDescribeRequest{Version: version} },
+	70: func(version int16) Request { return &ControllerRegistrationRequest{Version: version} },
+	71: func(version int16) Request { return &GetTelemetrySubscriptionsRequest{Version: version} },
+	72: func(version int16) Request { return &PushTelemetryRequest{Version: version} },
+	73: func(version int16) Request { return &AssignReplicasToDirsRequest{Version: version} },
+	74: func(version int16) Request { return &ListClientMetricsResourcesRequest{Version: version} },
+	75: func(version int16) Request { return &DescribeTopicPartitionsRequest{Version: version} },
+	76: func(version int16) Request { return &ShareGroupHeartbeatRequest{Version: version} },
+	77: func(version int16) Request { return &ShareGroupDescribeRequest{Version: version} },
+	78: func(version int16) Request { return &ShareFetchRequest{Version: version} },
+	79: func(version int16) Request { return &ShareAcknowledgeRequest{Version: version} },
+	80: func(version int16) Request { return &AddRaftVoterRequest{Version: version} },
+	81: func(version int16) Request { return &RemoveRaftVoterRequest{Version: version} },
+	82: func(version int16) Request { return &UpdateRaftVoterRequest{Version: version} },
+	83: func(version int16) Request { return &InitializeShareGroupStateRequest{Version: version} },
+	84: func(version int16) Request { return &ReadShareGroupStateRequest{Version: version} },
+	85: func(version int16) Request { return &WriteShareGroupStateRequest{Version: version} },
+	86: func(version int16) Request { return &DeleteShareGroupStateRequest{Version: version} },
+	87: func(version int16) Request { return &ReadShareGroupStateSummaryRequest{Version: version} },
+	88: func(version int16) Request { return &StreamsGroupHeartbeatRequest{Version: version} },
+	89: func(version int16) Request { return &StreamsGroupDescribeRequest{Version: version} },
+	90: func(version int16) Request { return &DescribeShareGroupOffsetsRequest{Version: version} },
+}
+
+// ErrUnknownAPIKey is returned for API keys the protocol doesn't define.
+var ErrUnknownAPIKey = errors.New("unknown API key")
+
+// NewRequest returns an empty request struct for the API key and version, ready to be decoded.
+func NewRequest(apiKey int16, version int16) (Request, error) {
+	newRequest, ok := requestConstructors[apiKey]
+	if !ok {
+		return nil, fmt.Errorf("%w %d", ErrUnknownAPIKey, apiKey)
+	}
+
+	return newRequest(version), nil
+}
+
+// IsSupportedVersion reports whether the broker can decode and serve the version of the request.
+func IsSupportedVersion(r Request) bool {
+	return r.IsValidVersion() && r.GetVersion() >= r.GetRequiredVersion()
+}
@@ -44,26 +44,26 @@ func (r *RequestHeader) decode(pd packetDecoder, version int16) (err error) {
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.RequestApiKey, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "RequestApiKey", err)
 	}
 
 	if r.RequestApiVersion, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "RequestApiVersion", err)
 	}
 
 	if r.CorrelationID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "CorrelationID", err)
 	}
 
 	if r.Version >= 1 {
 		if r.ClientID, err = nonFlexiblePd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(nonFlexiblePd, "ClientID", err)
 		}
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -89,6 +89,5 @@ func (r *RequestHeader) IsValidVersion() bool {
 }
 
 func (r *RequestHeader) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
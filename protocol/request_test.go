@@ -0,0 +1,74 @@
+package protocol
+
+import "testing"
+
+func TestNewRequest(t *testing.T) {
+	for apiKey := range requestConstructors {
+		req, err := NewRequest(apiKey, 3)
+		if err != nil {
+			t.Fatalf("error creating request for API key %d: %v", apiKey, err)
+		}
+		if req.GetKey() != apiKey {
+			t.Errorf("request for API key %d has key %d", apiKey, req.GetKey())
+		}
+		if req.GetVersion() != 3 {
+			t.Errorf("request for API key %d has version %d, want 3", apiKey, req.GetVersion())
+		}
+	}
+
+	if _, err := NewRequest(-1, 0); err == nil {
+		t.Error("expected an error for an unknown API key")
+	}
+}
+
+func TestApiKeyName(t *testing.T) {
+	tests := map[int16]string{
+		0:  "Produce",
+		3:  "Metadata",
+		18: "ApiVersions",
+		22: "InitProducerId",
+		-1: "UNKNOWN(-1)",
+	}
+	for apiKey, want := range tests {
+		if got := ApiKeyName(apiKey); got != want {
+			t.Errorf("ApiKeyName(%d) = %s, want %s", apiKey, got, want)
+		}
+	}
+}
+
+func TestErrorCodeName(t *testing.T) {
+	tests := map[int16]string{
+		-1:   "UNKNOWN_SERVER_ERROR",
+		0:    "NONE",
+		3:    "UNKNOWN_TOPIC_OR_PARTITION",
+		35:   "UNSUPPORTED_VERSION",
+		100:  "UNKNOWN_TOPIC_ID",
+		1000: "UNKNOWN(1000)",
+	}
+	for errorCode, want := range tests {
+		if got := ErrorCodeName(errorCode); got != want {
+			t.Errorf("ErrorCodeName(%d) = %s, want %s", errorCode, got, want)
+		}
+	}
+}
+
+func TestApiVersionsRequest_DecodeClientSoftware(t *testing.T) {
+	// compact strings are prefixed with their length + 1, followed by the empty tagged fields
+	body := []byte{11}
+	body = append(body, "librdkafka"...)
+	body = append(body, 6)
+	body = append(body, "2.3.0"...)
+	body = append(body, 0)
+
+	req := ApiVersionsRequest{}
+	n, err := VersionedDecode(body, &req, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(body) {
+		t.Errorf("decoded %d of %d bytes", n, len(body))
+	}
+	if req.ClientSoftwareName != "librdkafka" || req.ClientSoftwareVersion != "2.3.0" {
+		t.Errorf("client software = %q %q, want librdkafka 2.3.0", req.ClientSoftwareName, req.ClientSoftwareVersion)
+	}
+}
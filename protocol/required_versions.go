@@ -0,0 +1,42 @@
+package protocol
+
+import "sync"
+
+// The message format json files only describe which versions of an API exist, not which of them the broker is able to serve.
+// requiredVersions is the hand-maintained list of the lowest version the broker accepts per API key.
+// APIs which are not listed require version 0.
+var (
+	requiredVersionsMu sync.RWMutex
+	requiredVersions   = map[int16]int16{
+		(&ProduceRequest{}).GetKey():      3, // older versions use message sets instead of v2 record batches
+		(&FetchRequest{}).GetKey():        4,
+		(&ListOffsetsRequest{}).GetKey():  1,
+		(&DeleteTopicsRequest{}).GetKey(): 1,
+		// v0 sends the SASL tokens unframed after the handshake, instead of in SaslAuthenticate requests
+		(&SaslHandshakeRequest{}).GetKey(): 1,
+		// v0 of the delegation token APIs was removed from the message formats
+		(&CreateDelegationTokenRequest{}).GetKey():   1,
+		(&RenewDelegationTokenRequest{}).GetKey():    1,
+		(&ExpireDelegationTokenRequest{}).GetKey():   1,
+		(&DescribeDelegationTokenRequest{}).GetKey(): 1,
+		// v0 was removed in Kafka 4.0 (KIP-896), the Kafka 2.1 clients it still supports send v1 or later
+		(&DescribeLogDirsRequest{}).GetKey(): 1,
+	}
+)
+
+// RequiredVersion returns the lowest version of the API the broker accepts.
+func RequiredVersion(apiKey int16) int16 {
+	requiredVersionsMu.RLock()
+	defer requiredVersionsMu.RUnlock()
+
+	return requiredVersions[apiKey]
+}
+
+// SetRequiredVersion overrides the lowest version of the API the broker accepts,
+// without having to edit the generated request and response types.
+func SetRequiredVersion(apiKey int16, version int16) {
+	requiredVersionsMu.Lock()
+	defer requiredVersionsMu.Unlock()
+
+	requiredVersions[apiKey] = version
+}
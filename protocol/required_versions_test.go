@@ -0,0 +1,24 @@
+package protocol
+
+import "testing"
+
+func TestRequiredVersion(t *testing.T) {
+	if v := (&ProduceRequest{}).GetRequiredVersion(); v != 3 {
+		t.Errorf("produce required version = %d, want 3", v)
+	}
+
+	if v := (&MetadataRequest{}).GetRequiredVersion(); v != 0 {
+		t.Errorf("metadata required version = %d, want 0", v)
+	}
+
+	key := (&MetadataRequest{}).GetKey()
+	SetRequiredVersion(key, 4)
+	t.Cleanup(func() { SetRequiredVersion(key, 0) })
+
+	if v := (&MetadataRequest{}).GetRequiredVersion(); v != 4 {
+		t.Errorf("overridden metadata required version = %d, want 4", v)
+	}
+	if v := (&MetadataResponse{}).GetRequiredVersion(); v != 4 {
+		t.Errorf("overridden metadata response required version = %d, want 4", v)
+	}
+}
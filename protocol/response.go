@@ -0,0 +1,118 @@
+package protocol
+
+import "fmt"
+
+// Response is implemented by every response type of the Kafka protocol.
+type Response interface {
+	encoder
+	versionedDecoder
+	GetKey() int16
+	GetVersion() int16
+	GetHeaderVersion() int16
+	IsValidVersion() bool
+}
+
+// responseConstructors maps each API key to a constructor of its response type, like requestConstructors does for requests.
+var responseConstructors = map[int16]func(version int16) Response{
+	0:  func(version int16) Response { return &ProduceResponse{Version: version} },
+	1:  func(version int16) Response { return &FetchResponse{Version: version} },
+	2:  func(version int16) Response { return &ListOffsetsResponse{Version: version} },
+	3:  func(version int16) Response { return &MetadataResponse{Version: version} },
+	4:  func(version int16) Response { return &LeaderAndIsrResponse{Version: version} },
+	5:  func(version int16) Response { return &StopReplicaResponse{Version: version} },
+	6:  func(version int16) Response { return &UpdateMetadataResponse{Version: version} },
+	7:  func(version int16) Response { return &ControlledShutdownResponse{Version: version} },
+	8:  func(version int16) Response { return &OffsetCommitResponse{Version: version} },
+	9:  func(version int16) Response { return &OffsetFetchResponse{Version: version} },
+	10: func(version int16) Response { return &FindCoordinatorResponse{Version: version} },
+	11: func(version int16) Response { return &JoinGroupResponse{Version: version} },
+	12: func(version int16) Response { return &HeartbeatResponse{Version: version} },
+	13: func(version int16) Response { return &LeaveGroupResponse{Version: version} },
+	14: func(version int16) Response { return &SyncGroupResponse{Version: version} },
+	15: func(version int16) Response { return &DescribeGroupsResponse{Version: version} },
+	16: func(version int16) Response { return &ListGroupsResponse{Version: version} },
+	17: func(version int16) Response { return &SaslHandshakeResponse{Version: version} },
+	18: func(version int16) Response { return &ApiVersionsResponse{Version: version} },
+	19: func(version int16) Response { return &CreateTopicsResponse{Version: version} },
+	20: func(version int16) Response { return &DeleteTopicsResponse{Version: version} },
+	21: func(version int16) Response { return &DeleteRecordsResponse{Version: version} },
+	22: func(version int16) Response { return &InitProducerIdResponse{Version: version} },
+	23: func(version int16) Response { return &OffsetForLeaderEpochResponse{Version: version} },
+	24: func(version int16) Response { return &AddPartitionsToTxnResponse{Version: version} },
+	25: func(version int16) Response { return &AddOffsetsToTxnResponse{Version: version} },
+	26: func(version int16) Response { return &EndTxnResponse{Version: version} },
+	27: func(version int16) Response { return &WriteTxnMarkersResponse{Version: version} },
+	28: func(version int16) Response { return &TxnOffsetCommitResponse{Version: version} },
+	29: func(version int16) Response { return &DescribeAclsResponse{Version: version} },
+	30: func(version int16) Response { return &CreateAclsResponse{Version: version} },
+	31: func(version int16) Response { return &DeleteAclsResponse{Version: version} },
+	32: func(version int16) Response { return &DescribeConfigsResponse{Version: version} },
+	33: func(version int16) Response { return &AlterConfigsResponse{Version: version} },
+	34: func(version int16) Response { return &AlterReplicaLogDirsResponse{Version: version} },
+	35: func(version int16) Response { return &DescribeLogDirsResponse{Version: version} },
+	36: func(version int16) Response { return &SaslAuthenticateResponse{Version: version} },
+	37: func(version int16) Response { return &CreatePartitionsResponse{Version: version} },
+	38: func(version int16) Response { return &CreateDelegationTokenResponse{Version: version} },
+	39: func(version int16) Response { return &RenewDelegationTokenResponse{Version: version} },
+	40: func(version int16) Response { return &ExpireDelegationTokenResponse{Version: version} },
+	41: func(version int16) Response { return &DescribeDelegationTokenResponse{Version: version} },
+	42: func(version int16) Response { return &DeleteGroupsResponse{Version: version} },
+	43: func(version int16) Response { return &ElectLeadersResponse{Version: version} },
+	44: func(version int16) Response { return &IncrementalAlterConfigsResponse{Version: version} },
+	45: func(version int16) Response { return &AlterPartitionReassignmentsResponse{Version: version} },
+	46: func(version int16) Response { return &ListPartitionReassignmentsResponse{Version: version} },
+	47: func(version int16) Response { return &OffsetDeleteResponse{Version: version} },
+	48: func(version int16) Response { return &DescribeClientQuotasResponse{Version: version} },
+	49: func(version int16) Response { return &AlterClientQuotasResponse{Version: version} },
+	50: func(version int16) Response { return &DescribeUserScramCredentialsResponse{Version: version} },
+	51: func(version int16) Response { return &AlterUserScramCredentialsResponse{Version: version} },
+	52: func(version int16) Response { return &VoteResponse{Version: version} },
+	53: func(version int16) Response { return &BeginQuorumEpochResponse{Version: version} },
+	54: func(version int16) Response { return &EndQuorumEpochResponse{Version: version} },
+	55: func(version int16) Response { return &DescribeQuorumResponse{Version: version} },
+	56: func(version int16) Response { return &AlterPartitionResponse{Version: version} },
+	57: func(version int16) Response { return &UpdateFeaturesResponse{Version: version} },
+	58: func(version int16) Response { return &EnvelopeResponse{Version: version} },
+	59: func(version int16) Response { return &FetchSnapshotResponse{Version: version} },
+	60: func(version int16) Response { return &DescribeClusterResponse{Version: version} },
+	61: func(version int16) Response { return &DescribeProducersResponse{Version: version} },
+	62: func(version int16) Response { return &BrokerRegistrationResponse{Version: version} },
+	63: func(version int16) Response { return &BrokerHeartbeatResponse{Version: version} },
+	64: func(version int16) Response { return &UnregisterBrokerResponse{Version: version} },
+	65: func(version int16) Response { return &DescribeTransactionsResponse{Version: version} },
+	66: func(version int16) Response { return &ListTransactionsResponse{Version: version} },
+	67: func(version int16) Response { return &AllocateProducerIdsResponse{Version: version} },
+	68: func(version int16) Response { return &ConsumerGroupHeartbeatResponse{Version: version} },
+	69: func(version int16) Response { return &ConsumerGroupDescribeResponse{Version: version} },
+	70: func(version int16) Response { return &ControllerRegistrationResponse{Version: version} },
+	71: func(version int16) Response { return &GetTelemetrySubscriptionsResponse{Version: version} },
+	72: func(version int16) Response { return &PushTelemetryResponse{Version: version} },
+	73: func(version int16) Response { return &AssignReplicasToDirsResponse{Version: version} },
+	74: func(version int16) Response { return &ListClientMetricsResourcesResponse{Version: version} },
+	75: func(version int16) Response { return &DescribeTopicPartitionsResponse{Version: version} },
+	76: func(version int16) Response { return &ShareGroupHeartbeatResponse{Version: version} },
+	77: func(version int16) Response { return &ShareGroupDescribeResponse{Version: version} },
+	78: func(version int16) Response { return &ShareFetchResponse{Version: version} },
+	79: func(version int16) Response { return &ShareAcknowledgeResponse{Version: version} },
+	80: func(version int16) Response { return &AddRaftVoterResponse{Version: version} },
+	81: func(version int16) Response { return &RemoveRaftVoterResponse{Version: version} },
+	82: func(version int16) Response { return &UpdateRaftVoterResponse{Version: version} },
+	83: func(version int16) Response { return &InitializeShareGroupStateResponse{Version: version} },
+	84: func(version int16) Response { return &ReadShareGroupStateResponse{Version: version} },
+	85: func(version int16) Response { return &WriteShareGroupStateResponse{Version: version} },
+	86: func(version int16) Response { return &DeleteShareGroupStateResponse{Version: version} },
+	87: func(version int16) Response { return &ReadShareGroupStateSummaryResponse{Version: version} },
+	88: func(version int16) Response { return &StreamsGroupHeartbeatResponse{Version: version} },
+	89: func(version int16) Response { return &StreamsGroupDescribeResponse{Version: version} },
+	90: func(version int16) Response { return &DescribeShareGroupOffsetsResponse{Version: version} },
+}
+
+// NewResponse returns an empty response struct for the API key and version, ready to be filled and encoded.
+func NewResponse(apiKey int16, version int16) (Response, error) {
+	newResponse, ok := responseConstructors[apiKey]
+	if !ok {
+		return nil, fmt.Errorf("%w %d", ErrUnknownAPIKey, apiKey)
+	}
+
+	return newResponse(version), nil
+}
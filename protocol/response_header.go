@@ -26,12 +26,12 @@ func (r *ResponseHeader) decode(pd packetDecoder, version int16) (err error) {
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.CorrelationID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "CorrelationID", err)
 	}
 
 	if r.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -57,6 +57,5 @@ func (r *ResponseHeader) IsValidVersion() bool {
 }
 
 func (r *ResponseHeader) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -0,0 +1,54 @@
+package protocol
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestResponseHeader_Encode(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int16
+		want    []byte
+	}{
+		{name: "v0 is the correlation ID only", version: 0, want: []byte{0, 0, 0, 42}},
+		{name: "v1 adds the tagged fields", version: 1, want: []byte{0, 0, 0, 42, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := ResponseHeader{Version: tt.version, CorrelationID: 42}
+			got, err := Encode(&header)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("Encode() = %v, want %v", got, tt.want)
+			}
+
+			decoded := ResponseHeader{}
+			if _, err := VersionedDecode(got, &decoded, tt.version); err != nil {
+				t.Fatal(err)
+			}
+			if decoded != header {
+				t.Errorf("decoded header = %+v, want %+v", decoded, header)
+			}
+		})
+	}
+}
+
+func TestResponse_GetHeaderVersion(t *testing.T) {
+	// ApiVersions responses keep header v0 even in flexible versions, so clients which don't know the version
+	// of the broker can always read the correlation ID
+	for _, version := range []int16{0, 3, 4} {
+		if got := (&ApiVersionsResponse{Version: version}).GetHeaderVersion(); got != 0 {
+			t.Errorf("ApiVersions v%d header version = %d, want 0", version, got)
+		}
+	}
+
+	if got := (&MetadataResponse{Version: 8}).GetHeaderVersion(); got != 0 {
+		t.Errorf("Metadata v8 header version = %d, want 0", got)
+	}
+	if got := (&MetadataResponse{Version: 9}).GetHeaderVersion(); got != 1 {
+		t.Errorf("Metadata v9 header version = %d, want 1", got)
+	}
+}
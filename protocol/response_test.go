@@ -0,0 +1,22 @@
+package protocol
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewResponse(t *testing.T) {
+	for apiKey := range requestConstructors {
+		resp, err := NewResponse(apiKey, 0)
+		if err != nil {
+			t.Fatalf("no response type for API key %d: %v", apiKey, err)
+		}
+		if resp.GetKey() != apiKey {
+			t.Errorf("response of API key %d has key %d", apiKey, resp.GetKey())
+		}
+	}
+
+	if _, err := NewResponse(9999, 0); !errors.Is(err, ErrUnknownAPIKey) {
+		t.Errorf("error = %v, want %v", err, ErrUnknownAPIKey)
+	}
+}
@@ -5,7 +5,7 @@ type SaslAuthenticateRequest struct {
 	// Version defines the protocol version to use for encode and decode
 	Version int16
 	// AuthBytes contains the SASL authentication bytes from the client, as defined by the SASL mechanism.
-	AuthBytes []byte
+	AuthBytes []byte `sensitive:"true"`
 }
 
 func (r *SaslAuthenticateRequest) encode(pe packetEncoder) (err error) {
@@ -28,12 +28,12 @@ func (r *SaslAuthenticateRequest) decode(pd packetDecoder, version int16) (err e
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.AuthBytes, err = pd.getBytes(); err != nil {
-		return err
+		return wrapFieldError(pd, "AuthBytes", err)
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -59,6 +59,5 @@ func (r *SaslAuthenticateRequest) IsValidVersion() bool {
 }
 
 func (r *SaslAuthenticateRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
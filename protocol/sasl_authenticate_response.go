@@ -9,7 +9,7 @@ type SaslAuthenticateResponse struct {
 	// ErrorMessage contains the error message, or null if there was no error.
 	ErrorMessage *string
 	// AuthBytes contains the SASL authentication bytes from the server, as defined by the SASL mechanism.
-	AuthBytes []byte
+	AuthBytes []byte `sensitive:"true"`
 	// SessionLifetimeMs contains a Number of milliseconds after which only re-authentication over the existing connection to create a new session can occur.
 	SessionLifetimeMs int64
 }
@@ -44,26 +44,26 @@ func (r *SaslAuthenticateResponse) decode(pd packetDecoder, version int16) (err
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if r.AuthBytes, err = pd.getBytes(); err != nil {
-		return err
+		return wrapFieldError(pd, "AuthBytes", err)
 	}
 
 	if r.Version >= 1 {
 		if r.SessionLifetimeMs, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "SessionLifetimeMs", err)
 		}
 	}
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -89,6 +89,5 @@ func (r *SaslAuthenticateResponse) IsValidVersion() bool {
 }
 
 func (r *SaslAuthenticateResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -19,7 +19,7 @@ func (r *SaslHandshakeRequest) encode(pe packetEncoder) (err error) {
 func (r *SaslHandshakeRequest) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.Mechanism, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Mechanism", err)
 	}
 
 	return nil
@@ -42,6 +42,5 @@ func (r *SaslHandshakeRequest) IsValidVersion() bool {
 }
 
 func (r *SaslHandshakeRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -23,11 +23,11 @@ func (r *SaslHandshakeResponse) encode(pe packetEncoder) (err error) {
 func (r *SaslHandshakeResponse) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.Mechanisms, err = pd.getStringArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "Mechanisms", err)
 	}
 
 	return nil
@@ -50,6 +50,5 @@ func (r *SaslHandshakeResponse) IsValidVersion() bool {
 }
 
 func (r *SaslHandshakeResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -32,19 +32,19 @@ func (a *AcknowledgementBatch_ShareAcknowledgeRequest) encode(pe packetEncoder,
 func (a *AcknowledgementBatch_ShareAcknowledgeRequest) decode(pd packetDecoder, version int16) (err error) {
 	a.Version = version
 	if a.FirstOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "FirstOffset", err)
 	}
 
 	if a.LastOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "LastOffset", err)
 	}
 
 	if a.AcknowledgeTypes, err = pd.getInt8Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "AcknowledgeTypes", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -79,26 +79,26 @@ func (p *AcknowledgePartition) encode(pe packetEncoder, version int16) (err erro
 func (p *AcknowledgePartition) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	var numAcknowledgementBatches int
 	if numAcknowledgementBatches, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "AcknowledgementBatches", err)
 	}
 	if numAcknowledgementBatches > 0 {
 		p.AcknowledgementBatches = make([]AcknowledgementBatch_ShareAcknowledgeRequest, numAcknowledgementBatches)
 		for i := 0; i < numAcknowledgementBatches; i++ {
 			var block AcknowledgementBatch_ShareAcknowledgeRequest
 			if err := block.decode(pd, p.Version); err != nil {
-				return err
+				return wrapElementError(pd, "AcknowledgementBatches", i, err)
 			}
 			p.AcknowledgementBatches[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -135,26 +135,26 @@ func (t *AcknowledgeTopic) encode(pe packetEncoder, version int16) (err error) {
 func (t *AcknowledgeTopic) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.TopicID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicID", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]AcknowledgePartition, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block AcknowledgePartition
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -201,34 +201,34 @@ func (r *ShareAcknowledgeRequest) decode(pd packetDecoder, version int16) (err e
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.GroupID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	if r.MemberID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberID", err)
 	}
 
 	if r.ShareSessionEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ShareSessionEpoch", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]AcknowledgeTopic, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block AcknowledgeTopic
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -250,6 +250,5 @@ func (r *ShareAcknowledgeRequest) IsValidVersion() bool {
 }
 
 func (r *ShareAcknowledgeRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -29,15 +29,15 @@ func (c *LeaderIdAndEpoch_ShareAcknowledgeResponse) encode(pe packetEncoder, ver
 func (c *LeaderIdAndEpoch_ShareAcknowledgeResponse) decode(pd packetDecoder, version int16) (err error) {
 	c.Version = version
 	if c.LeaderID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderID", err)
 	}
 
 	if c.LeaderEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderEpoch", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -77,25 +77,25 @@ func (p *PartitionData_ShareAcknowledgeResponse) encode(pe packetEncoder, versio
 func (p *PartitionData_ShareAcknowledgeResponse) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	tmpCurrentLeader := LeaderIdAndEpoch_ShareAcknowledgeResponse{}
 	if err := tmpCurrentLeader.decode(pd, p.Version); err != nil {
-		return err
+		return wrapFieldError(pd, "CurrentLeader", err)
 	}
 	p.CurrentLeader = tmpCurrentLeader
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -132,26 +132,26 @@ func (r *ShareAcknowledgeTopicResponse) encode(pe packetEncoder, version int16)
 func (r *ShareAcknowledgeTopicResponse) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.TopicID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicID", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		r.Partitions = make([]PartitionData_ShareAcknowledgeResponse, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionData_ShareAcknowledgeResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			r.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -191,23 +191,23 @@ func (n *NodeEndpoint_ShareAcknowledgeResponse) encode(pe packetEncoder, version
 func (n *NodeEndpoint_ShareAcknowledgeResponse) decode(pd packetDecoder, version int16) (err error) {
 	n.Version = version
 	if n.NodeID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "NodeID", err)
 	}
 
 	if n.Host, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Host", err)
 	}
 
 	if n.Port, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Port", err)
 	}
 
 	if n.Rack, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Rack", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -263,27 +263,27 @@ func (r *ShareAcknowledgeResponse) decode(pd packetDecoder, version int16) (err
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	var numResponses int
 	if numResponses, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Responses", err)
 	}
 	if numResponses > 0 {
 		r.Responses = make([]ShareAcknowledgeTopicResponse, numResponses)
 		for i := 0; i < numResponses; i++ {
 			var block ShareAcknowledgeTopicResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Responses", i, err)
 			}
 			r.Responses[i] = block
 		}
@@ -291,21 +291,21 @@ func (r *ShareAcknowledgeResponse) decode(pd packetDecoder, version int16) (err
 
 	var numNodeEndpoints int
 	if numNodeEndpoints, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "NodeEndpoints", err)
 	}
 	if numNodeEndpoints > 0 {
 		r.NodeEndpoints = make([]NodeEndpoint_ShareAcknowledgeResponse, numNodeEndpoints)
 		for i := 0; i < numNodeEndpoints; i++ {
 			var block NodeEndpoint_ShareAcknowledgeResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "NodeEndpoints", i, err)
 			}
 			r.NodeEndpoints[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -327,8 +327,7 @@ func (r *ShareAcknowledgeResponse) IsValidVersion() bool {
 }
 
 func (r *ShareAcknowledgeResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *ShareAcknowledgeResponse) throttleTime() time.Duration {
@@ -32,19 +32,19 @@ func (a *AcknowledgementBatch_ShareFetchRequest) encode(pe packetEncoder, versio
 func (a *AcknowledgementBatch_ShareFetchRequest) decode(pd packetDecoder, version int16) (err error) {
 	a.Version = version
 	if a.FirstOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "FirstOffset", err)
 	}
 
 	if a.LastOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "LastOffset", err)
 	}
 
 	if a.AcknowledgeTypes, err = pd.getInt8Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "AcknowledgeTypes", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -83,30 +83,30 @@ func (p *FetchPartition_ShareFetchRequest) encode(pe packetEncoder, version int1
 func (p *FetchPartition_ShareFetchRequest) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.PartitionMaxBytes, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionMaxBytes", err)
 	}
 
 	var numAcknowledgementBatches int
 	if numAcknowledgementBatches, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "AcknowledgementBatches", err)
 	}
 	if numAcknowledgementBatches > 0 {
 		p.AcknowledgementBatches = make([]AcknowledgementBatch_ShareFetchRequest, numAcknowledgementBatches)
 		for i := 0; i < numAcknowledgementBatches; i++ {
 			var block AcknowledgementBatch_ShareFetchRequest
 			if err := block.decode(pd, p.Version); err != nil {
-				return err
+				return wrapElementError(pd, "AcknowledgementBatches", i, err)
 			}
 			p.AcknowledgementBatches[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -143,26 +143,26 @@ func (t *FetchTopic_ShareFetchRequest) encode(pe packetEncoder, version int16) (
 func (t *FetchTopic_ShareFetchRequest) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.TopicID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicID", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]FetchPartition_ShareFetchRequest, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block FetchPartition_ShareFetchRequest
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -194,15 +194,15 @@ func (f *ForgottenTopic_ShareFetchRequest) encode(pe packetEncoder, version int1
 func (f *ForgottenTopic_ShareFetchRequest) decode(pd packetDecoder, version int16) (err error) {
 	f.Version = version
 	if f.TopicID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicID", err)
 	}
 
 	if f.Partitions, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -276,43 +276,43 @@ func (r *ShareFetchRequest) decode(pd packetDecoder, version int16) (err error)
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.GroupID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	if r.MemberID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberID", err)
 	}
 
 	if r.ShareSessionEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ShareSessionEpoch", err)
 	}
 
 	if r.MaxWaitMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "MaxWaitMs", err)
 	}
 
 	if r.MinBytes, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "MinBytes", err)
 	}
 
 	if r.MaxBytes, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "MaxBytes", err)
 	}
 
 	if r.BatchSize, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "BatchSize", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]FetchTopic_ShareFetchRequest, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block FetchTopic_ShareFetchRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -320,21 +320,21 @@ func (r *ShareFetchRequest) decode(pd packetDecoder, version int16) (err error)
 
 	var numForgottenTopicsData int
 	if numForgottenTopicsData, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "ForgottenTopicsData", err)
 	}
 	if numForgottenTopicsData > 0 {
 		r.ForgottenTopicsData = make([]ForgottenTopic_ShareFetchRequest, numForgottenTopicsData)
 		for i := 0; i < numForgottenTopicsData; i++ {
 			var block ForgottenTopic_ShareFetchRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "ForgottenTopicsData", i, err)
 			}
 			r.ForgottenTopicsData[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -356,6 +356,5 @@ func (r *ShareFetchRequest) IsValidVersion() bool {
 }
 
 func (r *ShareFetchRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
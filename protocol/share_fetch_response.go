@@ -29,15 +29,15 @@ func (c *LeaderIdAndEpoch_ShareFetchResponse) encode(pe packetEncoder, version i
 func (c *LeaderIdAndEpoch_ShareFetchResponse) decode(pd packetDecoder, version int16) (err error) {
 	c.Version = version
 	if c.LeaderID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderID", err)
 	}
 
 	if c.LeaderEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderEpoch", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -69,19 +69,19 @@ func (a *AcquiredRecords) encode(pe packetEncoder, version int16) (err error) {
 func (a *AcquiredRecords) decode(pd packetDecoder, version int16) (err error) {
 	a.Version = version
 	if a.FirstOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "FirstOffset", err)
 	}
 
 	if a.LastOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "LastOffset", err)
 	}
 
 	if a.DeliveryCount, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "DeliveryCount", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -103,7 +103,7 @@ type PartitionData_ShareFetchResponse struct {
 	// CurrentLeader contains the current leader of the partition.
 	CurrentLeader LeaderIdAndEpoch_ShareFetchResponse
 	// Records contains the record data.
-	Records RecordBatch
+	Records Records
 	// AcquiredRecords contains the acquired records.
 	AcquiredRecords []AcquiredRecords
 }
@@ -148,54 +148,54 @@ func (p *PartitionData_ShareFetchResponse) encode(pe packetEncoder, version int1
 func (p *PartitionData_ShareFetchResponse) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if p.AcknowledgeErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "AcknowledgeErrorCode", err)
 	}
 
 	if p.AcknowledgeErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "AcknowledgeErrorMessage", err)
 	}
 
 	tmpCurrentLeader := LeaderIdAndEpoch_ShareFetchResponse{}
 	if err := tmpCurrentLeader.decode(pd, p.Version); err != nil {
-		return err
+		return wrapFieldError(pd, "CurrentLeader", err)
 	}
 	p.CurrentLeader = tmpCurrentLeader
 
-	tmpRecords := RecordBatch{}
+	tmpRecords := Records{}
 	if err := tmpRecords.decode(pd, p.Version); err != nil {
-		return err
+		return wrapFieldError(pd, "Records", err)
 	}
 	p.Records = tmpRecords
 
 	var numAcquiredRecords int
 	if numAcquiredRecords, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "AcquiredRecords", err)
 	}
 	if numAcquiredRecords > 0 {
 		p.AcquiredRecords = make([]AcquiredRecords, numAcquiredRecords)
 		for i := 0; i < numAcquiredRecords; i++ {
 			var block AcquiredRecords
 			if err := block.decode(pd, p.Version); err != nil {
-				return err
+				return wrapElementError(pd, "AcquiredRecords", i, err)
 			}
 			p.AcquiredRecords[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -232,26 +232,26 @@ func (r *ShareFetchableTopicResponse) encode(pe packetEncoder, version int16) (e
 func (r *ShareFetchableTopicResponse) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.TopicID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicID", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		r.Partitions = make([]PartitionData_ShareFetchResponse, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionData_ShareFetchResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			r.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -291,23 +291,23 @@ func (n *NodeEndpoint_ShareFetchResponse) encode(pe packetEncoder, version int16
 func (n *NodeEndpoint_ShareFetchResponse) decode(pd packetDecoder, version int16) (err error) {
 	n.Version = version
 	if n.NodeID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "NodeID", err)
 	}
 
 	if n.Host, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Host", err)
 	}
 
 	if n.Port, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Port", err)
 	}
 
 	if n.Rack, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Rack", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -363,27 +363,27 @@ func (r *ShareFetchResponse) decode(pd packetDecoder, version int16) (err error)
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	var numResponses int
 	if numResponses, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Responses", err)
 	}
 	if numResponses > 0 {
 		r.Responses = make([]ShareFetchableTopicResponse, numResponses)
 		for i := 0; i < numResponses; i++ {
 			var block ShareFetchableTopicResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Responses", i, err)
 			}
 			r.Responses[i] = block
 		}
@@ -391,21 +391,21 @@ func (r *ShareFetchResponse) decode(pd packetDecoder, version int16) (err error)
 
 	var numNodeEndpoints int
 	if numNodeEndpoints, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "NodeEndpoints", err)
 	}
 	if numNodeEndpoints > 0 {
 		r.NodeEndpoints = make([]NodeEndpoint_ShareFetchResponse, numNodeEndpoints)
 		for i := 0; i < numNodeEndpoints; i++ {
 			var block NodeEndpoint_ShareFetchResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "NodeEndpoints", i, err)
 			}
 			r.NodeEndpoints[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -427,8 +427,7 @@ func (r *ShareFetchResponse) IsValidVersion() bool {
 }
 
 func (r *ShareFetchResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *ShareFetchResponse) throttleTime() time.Duration {
@@ -44,27 +44,27 @@ func (r *ShareGroupHeartbeatRequest) decode(pd packetDecoder, version int16) (er
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.GroupID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	if r.MemberID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberID", err)
 	}
 
 	if r.MemberEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberEpoch", err)
 	}
 
 	if r.RackID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "RackID", err)
 	}
 
 	if r.SubscribedTopicNames, err = pd.getStringArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "SubscribedTopicNames", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -86,6 +86,5 @@ func (r *ShareGroupHeartbeatRequest) IsValidVersion() bool {
 }
 
 func (r *ShareGroupHeartbeatRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -33,19 +33,19 @@ func (u *StopReplicaPartitionV0) decode(pd packetDecoder, version int16) (err er
 	u.Version = version
 	if u.Version == 0 {
 		if u.TopicName, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicName", err)
 		}
 	}
 
 	if u.Version == 0 {
 		if u.PartitionIndex, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "PartitionIndex", err)
 		}
 	}
 
 	if u.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -85,19 +85,19 @@ func (t *StopReplicaTopicV1) decode(pd packetDecoder, version int16) (err error)
 	t.Version = version
 	if t.Version >= 1 && t.Version <= 2 {
 		if t.Name, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Name", err)
 		}
 	}
 
 	if t.Version >= 1 && t.Version <= 2 {
 		if t.PartitionIndexes, err = pd.getInt32Array(); err != nil {
-			return err
+			return wrapFieldError(pd, "PartitionIndexes", err)
 		}
 	}
 
 	if t.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -139,25 +139,25 @@ func (p *StopReplicaPartitionState) decode(pd packetDecoder, version int16) (err
 	p.Version = version
 	if p.Version >= 3 {
 		if p.PartitionIndex, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "PartitionIndex", err)
 		}
 	}
 
 	if p.Version >= 3 {
 		if p.LeaderEpoch, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "LeaderEpoch", err)
 		}
 	}
 
 	if p.Version >= 3 {
 		if p.DeletePartition, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "DeletePartition", err)
 		}
 	}
 
 	if p.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -202,21 +202,21 @@ func (t *StopReplicaTopicState) decode(pd packetDecoder, version int16) (err err
 	t.Version = version
 	if t.Version >= 3 {
 		if t.TopicName, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicName", err)
 		}
 	}
 
 	if t.Version >= 3 {
 		var numPartitionStates int
 		if numPartitionStates, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "PartitionStates", err)
 		}
 		if numPartitionStates > 0 {
 			t.PartitionStates = make([]StopReplicaPartitionState, numPartitionStates)
 			for i := 0; i < numPartitionStates; i++ {
 				var block StopReplicaPartitionState
 				if err := block.decode(pd, t.Version); err != nil {
-					return err
+					return wrapElementError(pd, "PartitionStates", i, err)
 				}
 				t.PartitionStates[i] = block
 			}
@@ -225,7 +225,7 @@ func (t *StopReplicaTopicState) decode(pd packetDecoder, version int16) (err err
 
 	if t.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -317,42 +317,42 @@ func (r *StopReplicaRequest) decode(pd packetDecoder, version int16) (err error)
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ControllerID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ControllerID", err)
 	}
 
 	if r.Version >= 4 {
 		if r.isKRaftController, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "isKRaftController", err)
 		}
 	}
 
 	if r.ControllerEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ControllerEpoch", err)
 	}
 
 	if r.Version >= 1 {
 		if r.BrokerEpoch, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "BrokerEpoch", err)
 		}
 	}
 
 	if r.Version >= 0 && r.Version <= 2 {
 		if r.DeletePartitions, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "DeletePartitions", err)
 		}
 	}
 
 	if r.Version == 0 {
 		var numUngroupedPartitions int
 		if numUngroupedPartitions, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "UngroupedPartitions", err)
 		}
 		if numUngroupedPartitions > 0 {
 			r.UngroupedPartitions = make([]StopReplicaPartitionV0, numUngroupedPartitions)
 			for i := 0; i < numUngroupedPartitions; i++ {
 				var block StopReplicaPartitionV0
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "UngroupedPartitions", i, err)
 				}
 				r.UngroupedPartitions[i] = block
 			}
@@ -362,14 +362,14 @@ func (r *StopReplicaRequest) decode(pd packetDecoder, version int16) (err error)
 	if r.Version >= 1 && r.Version <= 2 {
 		var numTopics int
 		if numTopics, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Topics", err)
 		}
 		if numTopics > 0 {
 			r.Topics = make([]StopReplicaTopicV1, numTopics)
 			for i := 0; i < numTopics; i++ {
 				var block StopReplicaTopicV1
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Topics", i, err)
 				}
 				r.Topics[i] = block
 			}
@@ -379,14 +379,14 @@ func (r *StopReplicaRequest) decode(pd packetDecoder, version int16) (err error)
 	if r.Version >= 3 {
 		var numTopicStates int
 		if numTopicStates, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicStates", err)
 		}
 		if numTopicStates > 0 {
 			r.TopicStates = make([]StopReplicaTopicState, numTopicStates)
 			for i := 0; i < numTopicStates; i++ {
 				var block StopReplicaTopicState
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "TopicStates", i, err)
 				}
 				r.TopicStates[i] = block
 			}
@@ -395,7 +395,7 @@ func (r *StopReplicaRequest) decode(pd packetDecoder, version int16) (err error)
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -421,6 +421,5 @@ func (r *StopReplicaRequest) IsValidVersion() bool {
 }
 
 func (r *StopReplicaRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
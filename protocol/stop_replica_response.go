@@ -32,20 +32,20 @@ func (p *StopReplicaPartitionError) encode(pe packetEncoder, version int16) (err
 func (p *StopReplicaPartitionError) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.TopicName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicName", err)
 	}
 
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -87,19 +87,19 @@ func (r *StopReplicaResponse) decode(pd packetDecoder, version int16) (err error
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	var numPartitionErrors int
 	if numPartitionErrors, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionErrors", err)
 	}
 	if numPartitionErrors > 0 {
 		r.PartitionErrors = make([]StopReplicaPartitionError, numPartitionErrors)
 		for i := 0; i < numPartitionErrors; i++ {
 			var block StopReplicaPartitionError
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "PartitionErrors", i, err)
 			}
 			r.PartitionErrors[i] = block
 		}
@@ -107,7 +107,7 @@ func (r *StopReplicaResponse) decode(pd packetDecoder, version int16) (err error
 
 	if r.Version >= 2 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -133,6 +133,5 @@ func (r *StopReplicaResponse) IsValidVersion() bool {
 }
 
 func (r *StopReplicaResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
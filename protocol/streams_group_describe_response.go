@@ -31,15 +31,15 @@ func (e *Endpoint_StreamsGroupDescribeResponse) encode(pe packetEncoder, version
 func (e *Endpoint_StreamsGroupDescribeResponse) decode(pd packetDecoder, version int16) (err error) {
 	e.Version = version
 	if e.Host, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Host", err)
 	}
 
 	if e.Port, err = pd.getUint16(); err != nil {
-		return err
+		return wrapFieldError(pd, "Port", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -73,19 +73,19 @@ func (t *TaskOffset_StreamsGroupDescribeResponse) encode(pe packetEncoder, versi
 func (t *TaskOffset_StreamsGroupDescribeResponse) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.SubtopologyID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "SubtopologyID", err)
 	}
 
 	if t.Partition, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partition", err)
 	}
 
 	if t.Offset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "Offset", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -123,19 +123,19 @@ func (t *TopicPartitions_StreamsGroupDescribeResponse) encode(pe packetEncoder,
 func (t *TopicPartitions_StreamsGroupDescribeResponse) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.TopicID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicID", err)
 	}
 
 	if t.TopicName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicName", err)
 	}
 
 	if t.Partitions, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -189,14 +189,14 @@ func (a *Assignment_StreamsGroupDescribeResponse) decode(pd packetDecoder, versi
 	a.Version = version
 	var numActiveTasks int
 	if numActiveTasks, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "ActiveTasks", err)
 	}
 	if numActiveTasks > 0 {
 		a.ActiveTasks = make([]TaskIds_StreamsGroupDescribeResponse, numActiveTasks)
 		for i := 0; i < numActiveTasks; i++ {
 			var block TaskIds_StreamsGroupDescribeResponse
 			if err := block.decode(pd, a.Version); err != nil {
-				return err
+				return wrapElementError(pd, "ActiveTasks", i, err)
 			}
 			a.ActiveTasks[i] = block
 		}
@@ -204,14 +204,14 @@ func (a *Assignment_StreamsGroupDescribeResponse) decode(pd packetDecoder, versi
 
 	var numStandbyTasks int
 	if numStandbyTasks, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "StandbyTasks", err)
 	}
 	if numStandbyTasks > 0 {
 		a.StandbyTasks = make([]TaskIds_StreamsGroupDescribeResponse, numStandbyTasks)
 		for i := 0; i < numStandbyTasks; i++ {
 			var block TaskIds_StreamsGroupDescribeResponse
 			if err := block.decode(pd, a.Version); err != nil {
-				return err
+				return wrapElementError(pd, "StandbyTasks", i, err)
 			}
 			a.StandbyTasks[i] = block
 		}
@@ -219,21 +219,21 @@ func (a *Assignment_StreamsGroupDescribeResponse) decode(pd packetDecoder, versi
 
 	var numWarmupTasks int
 	if numWarmupTasks, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "WarmupTasks", err)
 	}
 	if numWarmupTasks > 0 {
 		a.WarmupTasks = make([]TaskIds_StreamsGroupDescribeResponse, numWarmupTasks)
 		for i := 0; i < numWarmupTasks; i++ {
 			var block TaskIds_StreamsGroupDescribeResponse
 			if err := block.decode(pd, a.Version); err != nil {
-				return err
+				return wrapElementError(pd, "WarmupTasks", i, err)
 			}
 			a.WarmupTasks[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -265,15 +265,15 @@ func (t *TaskIds_StreamsGroupDescribeResponse) encode(pe packetEncoder, version
 func (t *TaskIds_StreamsGroupDescribeResponse) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.SubtopologyID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "SubtopologyID", err)
 	}
 
 	if t.Partitions, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -305,15 +305,15 @@ func (k *KeyValue_StreamsGroupDescribeResponse) encode(pe packetEncoder, version
 func (k *KeyValue_StreamsGroupDescribeResponse) decode(pd packetDecoder, version int16) (err error) {
 	k.Version = version
 	if k.Key, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Key", err)
 	}
 
 	if k.Value, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Value", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -358,34 +358,34 @@ func (t *TopicInfo_StreamsGroupDescribeResponse) encode(pe packetEncoder, versio
 func (t *TopicInfo_StreamsGroupDescribeResponse) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if t.Partitions, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 
 	if t.ReplicationFactor, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ReplicationFactor", err)
 	}
 
 	var numTopicConfigs int
 	if numTopicConfigs, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicConfigs", err)
 	}
 	if numTopicConfigs > 0 {
 		t.TopicConfigs = make([]KeyValue_StreamsGroupDescribeResponse, numTopicConfigs)
 		for i := 0; i < numTopicConfigs; i++ {
 			var block KeyValue_StreamsGroupDescribeResponse
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "TopicConfigs", i, err)
 			}
 			t.TopicConfigs[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -445,27 +445,27 @@ func (s *Subtopology_StreamsGroupDescribeResponse) encode(pe packetEncoder, vers
 func (s *Subtopology_StreamsGroupDescribeResponse) decode(pd packetDecoder, version int16) (err error) {
 	s.Version = version
 	if s.SubtopologyID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "SubtopologyID", err)
 	}
 
 	if s.SourceTopics, err = pd.getStringArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "SourceTopics", err)
 	}
 
 	if s.RepartitionSinkTopics, err = pd.getStringArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "RepartitionSinkTopics", err)
 	}
 
 	var numStateChangelogTopics int
 	if numStateChangelogTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "StateChangelogTopics", err)
 	}
 	if numStateChangelogTopics > 0 {
 		s.StateChangelogTopics = make([]TopicInfo_StreamsGroupDescribeResponse, numStateChangelogTopics)
 		for i := 0; i < numStateChangelogTopics; i++ {
 			var block TopicInfo_StreamsGroupDescribeResponse
 			if err := block.decode(pd, s.Version); err != nil {
-				return err
+				return wrapElementError(pd, "StateChangelogTopics", i, err)
 			}
 			s.StateChangelogTopics[i] = block
 		}
@@ -473,21 +473,21 @@ func (s *Subtopology_StreamsGroupDescribeResponse) decode(pd packetDecoder, vers
 
 	var numRepartitionSourceTopics int
 	if numRepartitionSourceTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "RepartitionSourceTopics", err)
 	}
 	if numRepartitionSourceTopics > 0 {
 		s.RepartitionSourceTopics = make([]TopicInfo_StreamsGroupDescribeResponse, numRepartitionSourceTopics)
 		for i := 0; i < numRepartitionSourceTopics; i++ {
 			var block TopicInfo_StreamsGroupDescribeResponse
 			if err := block.decode(pd, s.Version); err != nil {
-				return err
+				return wrapElementError(pd, "RepartitionSourceTopics", i, err)
 			}
 			s.RepartitionSourceTopics[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -522,26 +522,26 @@ func (t *Topology_StreamsGroupDescribeResponse) encode(pe packetEncoder, version
 func (t *Topology_StreamsGroupDescribeResponse) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Epoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Epoch", err)
 	}
 
 	var numSubtopologies int
 	if numSubtopologies, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Subtopologies", err)
 	}
 	if numSubtopologies > 0 {
 		t.Subtopologies = make([]Subtopology_StreamsGroupDescribeResponse, numSubtopologies)
 		for i := 0; i < numSubtopologies; i++ {
 			var block Subtopology_StreamsGroupDescribeResponse
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Subtopologies", i, err)
 			}
 			t.Subtopologies[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -660,53 +660,53 @@ func (m *Member_StreamsGroupDescribeResponse) encode(pe packetEncoder, version i
 func (m *Member_StreamsGroupDescribeResponse) decode(pd packetDecoder, version int16) (err error) {
 	m.Version = version
 	if m.MemberID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberID", err)
 	}
 
 	if m.MemberEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberEpoch", err)
 	}
 
 	if m.InstanceID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "InstanceID", err)
 	}
 
 	if m.RackID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "RackID", err)
 	}
 
 	if m.ClientID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClientID", err)
 	}
 
 	if m.ClientHost, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClientHost", err)
 	}
 
 	if m.TopologyEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopologyEpoch", err)
 	}
 
 	if m.ProcessID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProcessID", err)
 	}
 
 	tmpUserEndpoint := Endpoint_StreamsGroupDescribeResponse{}
 	if err := tmpUserEndpoint.decode(pd, m.Version); err != nil {
-		return err
+		return wrapFieldError(pd, "UserEndpoint", err)
 	}
 	m.UserEndpoint = tmpUserEndpoint
 
 	var numClientTags int
 	if numClientTags, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClientTags", err)
 	}
 	if numClientTags > 0 {
 		m.ClientTags = make([]KeyValue_StreamsGroupDescribeResponse, numClientTags)
 		for i := 0; i < numClientTags; i++ {
 			var block KeyValue_StreamsGroupDescribeResponse
 			if err := block.decode(pd, m.Version); err != nil {
-				return err
+				return wrapElementError(pd, "ClientTags", i, err)
 			}
 			m.ClientTags[i] = block
 		}
@@ -714,14 +714,14 @@ func (m *Member_StreamsGroupDescribeResponse) decode(pd packetDecoder, version i
 
 	var numTaskOffsets int
 	if numTaskOffsets, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaskOffsets", err)
 	}
 	if numTaskOffsets > 0 {
 		m.TaskOffsets = make([]TaskOffset_StreamsGroupDescribeResponse, numTaskOffsets)
 		for i := 0; i < numTaskOffsets; i++ {
 			var block TaskOffset_StreamsGroupDescribeResponse
 			if err := block.decode(pd, m.Version); err != nil {
-				return err
+				return wrapElementError(pd, "TaskOffsets", i, err)
 			}
 			m.TaskOffsets[i] = block
 		}
@@ -729,14 +729,14 @@ func (m *Member_StreamsGroupDescribeResponse) decode(pd packetDecoder, version i
 
 	var numTaskEndOffsets int
 	if numTaskEndOffsets, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaskEndOffsets", err)
 	}
 	if numTaskEndOffsets > 0 {
 		m.TaskEndOffsets = make([]TaskOffset_StreamsGroupDescribeResponse, numTaskEndOffsets)
 		for i := 0; i < numTaskEndOffsets; i++ {
 			var block TaskOffset_StreamsGroupDescribeResponse
 			if err := block.decode(pd, m.Version); err != nil {
-				return err
+				return wrapElementError(pd, "TaskEndOffsets", i, err)
 			}
 			m.TaskEndOffsets[i] = block
 		}
@@ -744,22 +744,22 @@ func (m *Member_StreamsGroupDescribeResponse) decode(pd packetDecoder, version i
 
 	tmpAssignment := Assignment_StreamsGroupDescribeResponse{}
 	if err := tmpAssignment.decode(pd, m.Version); err != nil {
-		return err
+		return wrapFieldError(pd, "Assignment", err)
 	}
 	m.Assignment = tmpAssignment
 
 	tmpTargetAssignment := Assignment_StreamsGroupDescribeResponse{}
 	if err := tmpTargetAssignment.decode(pd, m.Version); err != nil {
-		return err
+		return wrapFieldError(pd, "TargetAssignment", err)
 	}
 	m.TargetAssignment = tmpTargetAssignment
 
 	if m.IsClassic, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "IsClassic", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -830,56 +830,56 @@ func (g *DescribedGroup_StreamsGroupDescribeResponse) encode(pe packetEncoder, v
 func (g *DescribedGroup_StreamsGroupDescribeResponse) decode(pd packetDecoder, version int16) (err error) {
 	g.Version = version
 	if g.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if g.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if g.GroupID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	if g.GroupState, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupState", err)
 	}
 
 	if g.GroupEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupEpoch", err)
 	}
 
 	if g.AssignmentEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "AssignmentEpoch", err)
 	}
 
 	tmpTopology := Topology_StreamsGroupDescribeResponse{}
 	if err := tmpTopology.decode(pd, g.Version); err != nil {
-		return err
+		return wrapFieldError(pd, "Topology", err)
 	}
 	g.Topology = tmpTopology
 
 	var numMembers int
 	if numMembers, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Members", err)
 	}
 	if numMembers > 0 {
 		g.Members = make([]Member_StreamsGroupDescribeResponse, numMembers)
 		for i := 0; i < numMembers; i++ {
 			var block Member_StreamsGroupDescribeResponse
 			if err := block.decode(pd, g.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Members", i, err)
 			}
 			g.Members[i] = block
 		}
 	}
 
 	if g.AuthorizedOperations, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "AuthorizedOperations", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -914,26 +914,26 @@ func (r *StreamsGroupDescribeResponse) decode(pd packetDecoder, version int16) (
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	var numGroups int
 	if numGroups, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Groups", err)
 	}
 	if numGroups > 0 {
 		r.Groups = make([]DescribedGroup_StreamsGroupDescribeResponse, numGroups)
 		for i := 0; i < numGroups; i++ {
 			var block DescribedGroup_StreamsGroupDescribeResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Groups", i, err)
 			}
 			r.Groups[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -955,8 +955,7 @@ func (r *StreamsGroupDescribeResponse) IsValidVersion() bool {
 }
 
 func (r *StreamsGroupDescribeResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *StreamsGroupDescribeResponse) throttleTime() time.Duration {
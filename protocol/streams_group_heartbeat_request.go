@@ -28,15 +28,15 @@ func (k *KeyValue_StreamsGroupHeartbeatRequest) encode(pe packetEncoder, version
 func (k *KeyValue_StreamsGroupHeartbeatRequest) decode(pd packetDecoder, version int16) (err error) {
 	k.Version = version
 	if k.Key, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Key", err)
 	}
 
 	if k.Value, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Value", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -81,34 +81,34 @@ func (t *TopicInfo_StreamsGroupHeartbeatRequest) encode(pe packetEncoder, versio
 func (t *TopicInfo_StreamsGroupHeartbeatRequest) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if t.Partitions, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 
 	if t.ReplicationFactor, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ReplicationFactor", err)
 	}
 
 	var numTopicConfigs int
 	if numTopicConfigs, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicConfigs", err)
 	}
 	if numTopicConfigs > 0 {
 		t.TopicConfigs = make([]KeyValue_StreamsGroupHeartbeatRequest, numTopicConfigs)
 		for i := 0; i < numTopicConfigs; i++ {
 			var block KeyValue_StreamsGroupHeartbeatRequest
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "TopicConfigs", i, err)
 			}
 			t.TopicConfigs[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -138,15 +138,15 @@ func (e *Endpoint_StreamsGroupHeartbeatRequest) encode(pe packetEncoder, version
 func (e *Endpoint_StreamsGroupHeartbeatRequest) decode(pd packetDecoder, version int16) (err error) {
 	e.Version = version
 	if e.Host, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Host", err)
 	}
 
 	if e.Port, err = pd.getUint16(); err != nil {
-		return err
+		return wrapFieldError(pd, "Port", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -180,19 +180,19 @@ func (t *TaskOffset_StreamsGroupHeartbeatRequest) encode(pe packetEncoder, versi
 func (t *TaskOffset_StreamsGroupHeartbeatRequest) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.SubtopologyID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "SubtopologyID", err)
 	}
 
 	if t.Partition, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partition", err)
 	}
 
 	if t.Offset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "Offset", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -224,15 +224,15 @@ func (t *TaskIds_StreamsGroupHeartbeatRequest) encode(pe packetEncoder, version
 func (t *TaskIds_StreamsGroupHeartbeatRequest) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.SubtopologyID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "SubtopologyID", err)
 	}
 
 	if t.Partitions, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -270,19 +270,19 @@ func (c *CopartitionGroup) encode(pe packetEncoder, version int16) (err error) {
 func (c *CopartitionGroup) decode(pd packetDecoder, version int16) (err error) {
 	c.Version = version
 	if c.SourceTopics, err = pd.getInt16Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "SourceTopics", err)
 	}
 
 	if c.SourceTopicRegex, err = pd.getInt16Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "SourceTopicRegex", err)
 	}
 
 	if c.RepartitionSourceTopics, err = pd.getInt16Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "RepartitionSourceTopics", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -359,46 +359,46 @@ func (s *Subtopology_StreamsGroupHeartbeatRequest) encode(pe packetEncoder, vers
 func (s *Subtopology_StreamsGroupHeartbeatRequest) decode(pd packetDecoder, version int16) (err error) {
 	s.Version = version
 	if s.SubtopologyID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "SubtopologyID", err)
 	}
 
 	if s.SourceTopics, err = pd.getStringArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "SourceTopics", err)
 	}
 
 	if s.SourceTopicRegex, err = pd.getStringArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "SourceTopicRegex", err)
 	}
 
 	var numStateChangelogTopics int
 	if numStateChangelogTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "StateChangelogTopics", err)
 	}
 	if numStateChangelogTopics > 0 {
 		s.StateChangelogTopics = make([]TopicInfo_StreamsGroupHeartbeatRequest, numStateChangelogTopics)
 		for i := 0; i < numStateChangelogTopics; i++ {
 			var block TopicInfo_StreamsGroupHeartbeatRequest
 			if err := block.decode(pd, s.Version); err != nil {
-				return err
+				return wrapElementError(pd, "StateChangelogTopics", i, err)
 			}
 			s.StateChangelogTopics[i] = block
 		}
 	}
 
 	if s.RepartitionSinkTopics, err = pd.getStringArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "RepartitionSinkTopics", err)
 	}
 
 	var numRepartitionSourceTopics int
 	if numRepartitionSourceTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "RepartitionSourceTopics", err)
 	}
 	if numRepartitionSourceTopics > 0 {
 		s.RepartitionSourceTopics = make([]TopicInfo_StreamsGroupHeartbeatRequest, numRepartitionSourceTopics)
 		for i := 0; i < numRepartitionSourceTopics; i++ {
 			var block TopicInfo_StreamsGroupHeartbeatRequest
 			if err := block.decode(pd, s.Version); err != nil {
-				return err
+				return wrapElementError(pd, "RepartitionSourceTopics", i, err)
 			}
 			s.RepartitionSourceTopics[i] = block
 		}
@@ -406,21 +406,21 @@ func (s *Subtopology_StreamsGroupHeartbeatRequest) decode(pd packetDecoder, vers
 
 	var numCopartitionGroups int
 	if numCopartitionGroups, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "CopartitionGroups", err)
 	}
 	if numCopartitionGroups > 0 {
 		s.CopartitionGroups = make([]CopartitionGroup, numCopartitionGroups)
 		for i := 0; i < numCopartitionGroups; i++ {
 			var block CopartitionGroup
 			if err := block.decode(pd, s.Version); err != nil {
-				return err
+				return wrapElementError(pd, "CopartitionGroups", i, err)
 			}
 			s.CopartitionGroups[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -455,26 +455,26 @@ func (t *Topology_StreamsGroupHeartbeatRequest) encode(pe packetEncoder, version
 func (t *Topology_StreamsGroupHeartbeatRequest) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Epoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Epoch", err)
 	}
 
 	var numSubtopologies int
 	if numSubtopologies, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Subtopologies", err)
 	}
 	if numSubtopologies > 0 {
 		t.Subtopologies = make([]Subtopology_StreamsGroupHeartbeatRequest, numSubtopologies)
 		for i := 0; i < numSubtopologies; i++ {
 			var block Subtopology_StreamsGroupHeartbeatRequest
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Subtopologies", i, err)
 			}
 			t.Subtopologies[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -614,45 +614,45 @@ func (r *StreamsGroupHeartbeatRequest) decode(pd packetDecoder, version int16) (
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.GroupID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	if r.MemberID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberID", err)
 	}
 
 	if r.MemberEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberEpoch", err)
 	}
 
 	if r.InstanceID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "InstanceID", err)
 	}
 
 	if r.RackID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "RackID", err)
 	}
 
 	if r.RebalanceTimeoutMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "RebalanceTimeoutMs", err)
 	}
 
 	tmpTopology := Topology_StreamsGroupHeartbeatRequest{}
 	if err := tmpTopology.decode(pd, r.Version); err != nil {
-		return err
+		return wrapFieldError(pd, "Topology", err)
 	}
 	r.Topology = tmpTopology
 
 	var numActiveTasks int
 	if numActiveTasks, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "ActiveTasks", err)
 	}
 	if numActiveTasks > 0 {
 		r.ActiveTasks = make([]TaskIds_StreamsGroupHeartbeatRequest, numActiveTasks)
 		for i := 0; i < numActiveTasks; i++ {
 			var block TaskIds_StreamsGroupHeartbeatRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "ActiveTasks", i, err)
 			}
 			r.ActiveTasks[i] = block
 		}
@@ -660,14 +660,14 @@ func (r *StreamsGroupHeartbeatRequest) decode(pd packetDecoder, version int16) (
 
 	var numStandbyTasks int
 	if numStandbyTasks, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "StandbyTasks", err)
 	}
 	if numStandbyTasks > 0 {
 		r.StandbyTasks = make([]TaskIds_StreamsGroupHeartbeatRequest, numStandbyTasks)
 		for i := 0; i < numStandbyTasks; i++ {
 			var block TaskIds_StreamsGroupHeartbeatRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "StandbyTasks", i, err)
 			}
 			r.StandbyTasks[i] = block
 		}
@@ -675,39 +675,39 @@ func (r *StreamsGroupHeartbeatRequest) decode(pd packetDecoder, version int16) (
 
 	var numWarmupTasks int
 	if numWarmupTasks, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "WarmupTasks", err)
 	}
 	if numWarmupTasks > 0 {
 		r.WarmupTasks = make([]TaskIds_StreamsGroupHeartbeatRequest, numWarmupTasks)
 		for i := 0; i < numWarmupTasks; i++ {
 			var block TaskIds_StreamsGroupHeartbeatRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "WarmupTasks", i, err)
 			}
 			r.WarmupTasks[i] = block
 		}
 	}
 
 	if r.ProcessID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProcessID", err)
 	}
 
 	tmpUserEndpoint := Endpoint_StreamsGroupHeartbeatRequest{}
 	if err := tmpUserEndpoint.decode(pd, r.Version); err != nil {
-		return err
+		return wrapFieldError(pd, "UserEndpoint", err)
 	}
 	r.UserEndpoint = tmpUserEndpoint
 
 	var numClientTags int
 	if numClientTags, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClientTags", err)
 	}
 	if numClientTags > 0 {
 		r.ClientTags = make([]KeyValue_StreamsGroupHeartbeatRequest, numClientTags)
 		for i := 0; i < numClientTags; i++ {
 			var block KeyValue_StreamsGroupHeartbeatRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "ClientTags", i, err)
 			}
 			r.ClientTags[i] = block
 		}
@@ -715,14 +715,14 @@ func (r *StreamsGroupHeartbeatRequest) decode(pd packetDecoder, version int16) (
 
 	var numTaskOffsets int
 	if numTaskOffsets, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaskOffsets", err)
 	}
 	if numTaskOffsets > 0 {
 		r.TaskOffsets = make([]TaskOffset_StreamsGroupHeartbeatRequest, numTaskOffsets)
 		for i := 0; i < numTaskOffsets; i++ {
 			var block TaskOffset_StreamsGroupHeartbeatRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "TaskOffsets", i, err)
 			}
 			r.TaskOffsets[i] = block
 		}
@@ -730,25 +730,25 @@ func (r *StreamsGroupHeartbeatRequest) decode(pd packetDecoder, version int16) (
 
 	var numTaskEndOffsets int
 	if numTaskEndOffsets, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaskEndOffsets", err)
 	}
 	if numTaskEndOffsets > 0 {
 		r.TaskEndOffsets = make([]TaskOffset_StreamsGroupHeartbeatRequest, numTaskEndOffsets)
 		for i := 0; i < numTaskEndOffsets; i++ {
 			var block TaskOffset_StreamsGroupHeartbeatRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "TaskEndOffsets", i, err)
 			}
 			r.TaskEndOffsets[i] = block
 		}
 	}
 
 	if r.ShutdownApplication, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "ShutdownApplication", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -770,6 +770,5 @@ func (r *StreamsGroupHeartbeatRequest) IsValidVersion() bool {
 }
 
 func (r *StreamsGroupHeartbeatRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
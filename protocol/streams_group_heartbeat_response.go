@@ -28,15 +28,15 @@ func (s *Status_StreamsGroupHeartbeatResponse) encode(pe packetEncoder, version
 func (s *Status_StreamsGroupHeartbeatResponse) decode(pd packetDecoder, version int16) (err error) {
 	s.Version = version
 	if s.StatusCode, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "StatusCode", err)
 	}
 
 	if s.StatusDetail, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "StatusDetail", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -68,15 +68,15 @@ func (t *TopicPartition_StreamsGroupHeartbeatResponse) encode(pe packetEncoder,
 func (t *TopicPartition_StreamsGroupHeartbeatResponse) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Topic, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topic", err)
 	}
 
 	if t.Partitions, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -108,15 +108,15 @@ func (t *TaskIds_StreamsGroupHeartbeatResponse) encode(pe packetEncoder, version
 func (t *TaskIds_StreamsGroupHeartbeatResponse) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.SubtopologyID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "SubtopologyID", err)
 	}
 
 	if t.Partitions, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -146,15 +146,15 @@ func (e *Endpoint_StreamsGroupHeartbeatResponse) encode(pe packetEncoder, versio
 func (e *Endpoint_StreamsGroupHeartbeatResponse) decode(pd packetDecoder, version int16) (err error) {
 	e.Version = version
 	if e.Host, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Host", err)
 	}
 
 	if e.Port, err = pd.getUint16(); err != nil {
-		return err
+		return wrapFieldError(pd, "Port", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -192,27 +192,27 @@ func (p *EndpointToPartitions) decode(pd packetDecoder, version int16) (err erro
 	p.Version = version
 	tmpUserEndpoint := Endpoint_StreamsGroupHeartbeatResponse{}
 	if err := tmpUserEndpoint.decode(pd, p.Version); err != nil {
-		return err
+		return wrapFieldError(pd, "UserEndpoint", err)
 	}
 	p.UserEndpoint = tmpUserEndpoint
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		p.Partitions = make([]TopicPartition_StreamsGroupHeartbeatResponse, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block TopicPartition_StreamsGroupHeartbeatResponse
 			if err := block.decode(pd, p.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			p.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -323,47 +323,47 @@ func (r *StreamsGroupHeartbeatResponse) decode(pd packetDecoder, version int16)
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if r.MemberID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberID", err)
 	}
 
 	if r.MemberEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberEpoch", err)
 	}
 
 	if r.HeartbeatIntervalMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "HeartbeatIntervalMs", err)
 	}
 
 	if r.AcceptableRecoveryLag, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "AcceptableRecoveryLag", err)
 	}
 
 	if r.TaskOffsetIntervalMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaskOffsetIntervalMs", err)
 	}
 
 	var numStatus int
 	if numStatus, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Status", err)
 	}
 	if numStatus > 0 {
 		r.Status = make([]Status_StreamsGroupHeartbeatResponse, numStatus)
 		for i := 0; i < numStatus; i++ {
 			var block Status_StreamsGroupHeartbeatResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Status", i, err)
 			}
 			r.Status[i] = block
 		}
@@ -371,14 +371,14 @@ func (r *StreamsGroupHeartbeatResponse) decode(pd packetDecoder, version int16)
 
 	var numActiveTasks int
 	if numActiveTasks, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "ActiveTasks", err)
 	}
 	if numActiveTasks > 0 {
 		r.ActiveTasks = make([]TaskIds_StreamsGroupHeartbeatResponse, numActiveTasks)
 		for i := 0; i < numActiveTasks; i++ {
 			var block TaskIds_StreamsGroupHeartbeatResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "ActiveTasks", i, err)
 			}
 			r.ActiveTasks[i] = block
 		}
@@ -386,14 +386,14 @@ func (r *StreamsGroupHeartbeatResponse) decode(pd packetDecoder, version int16)
 
 	var numStandbyTasks int
 	if numStandbyTasks, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "StandbyTasks", err)
 	}
 	if numStandbyTasks > 0 {
 		r.StandbyTasks = make([]TaskIds_StreamsGroupHeartbeatResponse, numStandbyTasks)
 		for i := 0; i < numStandbyTasks; i++ {
 			var block TaskIds_StreamsGroupHeartbeatResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "StandbyTasks", i, err)
 			}
 			r.StandbyTasks[i] = block
 		}
@@ -401,14 +401,14 @@ func (r *StreamsGroupHeartbeatResponse) decode(pd packetDecoder, version int16)
 
 	var numWarmupTasks int
 	if numWarmupTasks, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "WarmupTasks", err)
 	}
 	if numWarmupTasks > 0 {
 		r.WarmupTasks = make([]TaskIds_StreamsGroupHeartbeatResponse, numWarmupTasks)
 		for i := 0; i < numWarmupTasks; i++ {
 			var block TaskIds_StreamsGroupHeartbeatResponse
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "WarmupTasks", i, err)
 			}
 			r.WarmupTasks[i] = block
 		}
@@ -416,21 +416,21 @@ func (r *StreamsGroupHeartbeatResponse) decode(pd packetDecoder, version int16)
 
 	var numPartitionsByUserEndpoint int
 	if numPartitionsByUserEndpoint, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionsByUserEndpoint", err)
 	}
 	if numPartitionsByUserEndpoint > 0 {
 		r.PartitionsByUserEndpoint = make([]EndpointToPartitions, numPartitionsByUserEndpoint)
 		for i := 0; i < numPartitionsByUserEndpoint; i++ {
 			var block EndpointToPartitions
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "PartitionsByUserEndpoint", i, err)
 			}
 			r.PartitionsByUserEndpoint[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -452,8 +452,7 @@ func (r *StreamsGroupHeartbeatResponse) IsValidVersion() bool {
 }
 
 func (r *StreamsGroupHeartbeatResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *StreamsGroupHeartbeatResponse) throttleTime() time.Duration {
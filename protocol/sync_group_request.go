@@ -30,16 +30,16 @@ func (a *SyncGroupRequestAssignment) encode(pe packetEncoder, version int16) (er
 func (a *SyncGroupRequestAssignment) decode(pd packetDecoder, version int16) (err error) {
 	a.Version = version
 	if a.MemberID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberID", err)
 	}
 
 	if a.Assignment, err = pd.getBytes(); err != nil {
-		return err
+		return wrapFieldError(pd, "Assignment", err)
 	}
 
 	if a.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -117,45 +117,45 @@ func (r *SyncGroupRequest) decode(pd packetDecoder, version int16) (err error) {
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.GroupID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	if r.GenerationID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "GenerationID", err)
 	}
 
 	if r.MemberID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "MemberID", err)
 	}
 
 	if r.Version >= 3 {
 		if r.GroupInstanceID, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "GroupInstanceID", err)
 		}
 	}
 
 	if r.Version >= 5 {
 		if r.ProtocolType, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "ProtocolType", err)
 		}
 	}
 
 	if r.Version >= 5 {
 		if r.ProtocolName, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "ProtocolName", err)
 		}
 	}
 
 	var numAssignments int
 	if numAssignments, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Assignments", err)
 	}
 	if numAssignments > 0 {
 		r.Assignments = make([]SyncGroupRequestAssignment, numAssignments)
 		for i := 0; i < numAssignments; i++ {
 			var block SyncGroupRequestAssignment
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Assignments", i, err)
 			}
 			r.Assignments[i] = block
 		}
@@ -163,7 +163,7 @@ func (r *SyncGroupRequest) decode(pd packetDecoder, version int16) (err error) {
 
 	if r.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -189,6 +189,5 @@ func (r *SyncGroupRequest) IsValidVersion() bool {
 }
 
 func (r *SyncGroupRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -57,33 +57,33 @@ func (r *SyncGroupResponse) decode(pd packetDecoder, version int16) (err error)
 	}
 	if r.Version >= 1 {
 		if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "ThrottleTimeMs", err)
 		}
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.Version >= 5 {
 		if r.ProtocolType, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "ProtocolType", err)
 		}
 	}
 
 	if r.Version >= 5 {
 		if r.ProtocolName, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "ProtocolName", err)
 		}
 	}
 
 	if r.Assignment, err = pd.getBytes(); err != nil {
-		return err
+		return wrapFieldError(pd, "Assignment", err)
 	}
 
 	if r.Version >= 4 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -109,8 +109,7 @@ func (r *SyncGroupResponse) IsValidVersion() bool {
 }
 
 func (r *SyncGroupResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *SyncGroupResponse) throttleTime() time.Duration {
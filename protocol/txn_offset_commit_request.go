@@ -38,26 +38,26 @@ func (p *TxnOffsetCommitRequestPartition) encode(pe packetEncoder, version int16
 func (p *TxnOffsetCommitRequestPartition) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.CommittedOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "CommittedOffset", err)
 	}
 
 	if p.Version >= 2 {
 		if p.CommittedLeaderEpoch, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "CommittedLeaderEpoch", err)
 		}
 	}
 
 	if p.CommittedMetadata, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "CommittedMetadata", err)
 	}
 
 	if p.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -97,19 +97,19 @@ func (t *TxnOffsetCommitRequestTopic) encode(pe packetEncoder, version int16) (e
 func (t *TxnOffsetCommitRequestTopic) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]TxnOffsetCommitRequestPartition, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block TxnOffsetCommitRequestPartition
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
@@ -117,7 +117,7 @@ func (t *TxnOffsetCommitRequestTopic) decode(pd packetDecoder, version int16) (e
 
 	if t.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -197,49 +197,49 @@ func (r *TxnOffsetCommitRequest) decode(pd packetDecoder, version int16) (err er
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.TransactionalID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TransactionalID", err)
 	}
 
 	if r.GroupID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	if r.ProducerID, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProducerID", err)
 	}
 
 	if r.ProducerEpoch, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProducerEpoch", err)
 	}
 
 	if r.Version >= 3 {
 		if r.GenerationID, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "GenerationID", err)
 		}
 	}
 
 	if r.Version >= 3 {
 		if r.MemberID, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "MemberID", err)
 		}
 	}
 
 	if r.Version >= 3 {
 		if r.GroupInstanceID, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "GroupInstanceID", err)
 		}
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]TxnOffsetCommitRequestTopic, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block TxnOffsetCommitRequestTopic
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -247,7 +247,7 @@ func (r *TxnOffsetCommitRequest) decode(pd packetDecoder, version int16) (err er
 
 	if r.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -273,6 +273,5 @@ func (r *TxnOffsetCommitRequest) IsValidVersion() bool {
 }
 
 func (r *TxnOffsetCommitRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
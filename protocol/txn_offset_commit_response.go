@@ -28,16 +28,16 @@ func (p *TxnOffsetCommitResponsePartition) encode(pe packetEncoder, version int1
 func (p *TxnOffsetCommitResponsePartition) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -77,19 +77,19 @@ func (t *TxnOffsetCommitResponseTopic) encode(pe packetEncoder, version int16) (
 func (t *TxnOffsetCommitResponseTopic) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]TxnOffsetCommitResponsePartition, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block TxnOffsetCommitResponsePartition
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
@@ -97,7 +97,7 @@ func (t *TxnOffsetCommitResponseTopic) decode(pd packetDecoder, version int16) (
 
 	if t.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -139,19 +139,19 @@ func (r *TxnOffsetCommitResponse) decode(pd packetDecoder, version int16) (err e
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]TxnOffsetCommitResponseTopic, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block TxnOffsetCommitResponseTopic
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
@@ -159,7 +159,7 @@ func (r *TxnOffsetCommitResponse) decode(pd packetDecoder, version int16) (err e
 
 	if r.Version >= 3 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -185,8 +185,7 @@ func (r *TxnOffsetCommitResponse) IsValidVersion() bool {
 }
 
 func (r *TxnOffsetCommitResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *TxnOffsetCommitResponse) throttleTime() time.Duration {
@@ -38,27 +38,27 @@ func (f *FeatureUpdateKey) encode(pe packetEncoder, version int16) (err error) {
 func (f *FeatureUpdateKey) decode(pd packetDecoder, version int16) (err error) {
 	f.Version = version
 	if f.Feature, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Feature", err)
 	}
 
 	if f.MaxVersionLevel, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "MaxVersionLevel", err)
 	}
 
 	if f.Version == 0 {
 		if f.AllowDowngrade, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "AllowDowngrade", err)
 		}
 	}
 
 	if f.Version >= 1 {
 		if f.UpgradeType, err = pd.getInt8(); err != nil {
-			return err
+			return wrapFieldError(pd, "UpgradeType", err)
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -99,19 +99,19 @@ func (r *UpdateFeaturesRequest) decode(pd packetDecoder, version int16) (err err
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.timeoutMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "timeoutMs", err)
 	}
 
 	var numFeatureUpdates int
 	if numFeatureUpdates, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "FeatureUpdates", err)
 	}
 	if numFeatureUpdates > 0 {
 		r.FeatureUpdates = make([]FeatureUpdateKey, numFeatureUpdates)
 		for i := 0; i < numFeatureUpdates; i++ {
 			var block FeatureUpdateKey
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "FeatureUpdates", i, err)
 			}
 			r.FeatureUpdates[i] = block
 		}
@@ -119,12 +119,12 @@ func (r *UpdateFeaturesRequest) decode(pd packetDecoder, version int16) (err err
 
 	if r.Version >= 1 {
 		if r.ValidateOnly, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "ValidateOnly", err)
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -146,6 +146,5 @@ func (r *UpdateFeaturesRequest) IsValidVersion() bool {
 }
 
 func (r *UpdateFeaturesRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -34,19 +34,19 @@ func (r *UpdatableFeatureResult) encode(pe packetEncoder, version int16) (err er
 func (r *UpdatableFeatureResult) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	if r.Feature, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Feature", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -93,28 +93,28 @@ func (r *UpdateFeaturesResponse) decode(pd packetDecoder, version int16) (err er
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ThrottleTimeMs, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ThrottleTimeMs", err)
 	}
 
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.ErrorMessage, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorMessage", err)
 	}
 
 	if r.Version >= 0 && r.Version <= 1 {
 		var numResults int
 		if numResults, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Results", err)
 		}
 		if numResults > 0 {
 			r.Results = make([]UpdatableFeatureResult, numResults)
 			for i := 0; i < numResults; i++ {
 				var block UpdatableFeatureResult
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Results", i, err)
 				}
 				r.Results[i] = block
 			}
@@ -122,7 +122,7 @@ func (r *UpdateFeaturesResponse) decode(pd packetDecoder, version int16) (err er
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -144,8 +144,7 @@ func (r *UpdateFeaturesResponse) IsValidVersion() bool {
 }
 
 func (r *UpdateFeaturesResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
 
 func (r *UpdateFeaturesResponse) throttleTime() time.Duration {
@@ -69,47 +69,47 @@ func (u *UpdateMetadataPartitionState_UpdateMetadataRequest) decode(pd packetDec
 	u.Version = version
 	if u.Version >= 0 && u.Version <= 4 {
 		if u.TopicName, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicName", err)
 		}
 	}
 
 	if u.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if u.ControllerEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ControllerEpoch", err)
 	}
 
 	if u.Leader, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Leader", err)
 	}
 
 	if u.LeaderEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderEpoch", err)
 	}
 
 	if u.Isr, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Isr", err)
 	}
 
 	if u.ZkVersion, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ZkVersion", err)
 	}
 
 	if u.Replicas, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "Replicas", err)
 	}
 
 	if u.Version >= 4 {
 		if u.OfflineReplicas, err = pd.getInt32Array(); err != nil {
-			return err
+			return wrapFieldError(pd, "OfflineReplicas", err)
 		}
 	}
 
 	if u.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -162,27 +162,27 @@ func (t *UpdateMetadataTopicState) decode(pd packetDecoder, version int16) (err
 	t.Version = version
 	if t.Version >= 5 {
 		if t.TopicName, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicName", err)
 		}
 	}
 
 	if t.Version >= 7 {
 		if t.TopicID, err = pd.getUUID(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicID", err)
 		}
 	}
 
 	if t.Version >= 5 {
 		var numPartitionStates int
 		if numPartitionStates, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "PartitionStates", err)
 		}
 		if numPartitionStates > 0 {
 			t.PartitionStates = make([]UpdateMetadataPartitionState_UpdateMetadataRequest, numPartitionStates)
 			for i := 0; i < numPartitionStates; i++ {
 				var block UpdateMetadataPartitionState_UpdateMetadataRequest
 				if err := block.decode(pd, t.Version); err != nil {
-					return err
+					return wrapElementError(pd, "PartitionStates", i, err)
 				}
 				t.PartitionStates[i] = block
 			}
@@ -191,7 +191,7 @@ func (t *UpdateMetadataTopicState) decode(pd packetDecoder, version int16) (err
 
 	if t.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -243,31 +243,31 @@ func (e *UpdateMetadataEndpoint) decode(pd packetDecoder, version int16) (err er
 	e.Version = version
 	if e.Version >= 1 {
 		if e.Port, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "Port", err)
 		}
 	}
 
 	if e.Version >= 1 {
 		if e.Host, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Host", err)
 		}
 	}
 
 	if e.Version >= 3 {
 		if e.Listener, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Listener", err)
 		}
 	}
 
 	if e.Version >= 1 {
 		if e.SecurityProtocol, err = pd.getInt16(); err != nil {
-			return err
+			return wrapFieldError(pd, "SecurityProtocol", err)
 		}
 	}
 
 	if e.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -329,32 +329,32 @@ func (l *UpdateMetadataBroker) encode(pe packetEncoder, version int16) (err erro
 func (l *UpdateMetadataBroker) decode(pd packetDecoder, version int16) (err error) {
 	l.Version = version
 	if l.ID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ID", err)
 	}
 
 	if l.Version == 0 {
 		if l.V0Host, err = pd.getString(); err != nil {
-			return err
+			return wrapFieldError(pd, "V0Host", err)
 		}
 	}
 
 	if l.Version == 0 {
 		if l.V0Port, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "V0Port", err)
 		}
 	}
 
 	if l.Version >= 1 {
 		var numEndpoints int
 		if numEndpoints, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "Endpoints", err)
 		}
 		if numEndpoints > 0 {
 			l.Endpoints = make([]UpdateMetadataEndpoint, numEndpoints)
 			for i := 0; i < numEndpoints; i++ {
 				var block UpdateMetadataEndpoint
 				if err := block.decode(pd, l.Version); err != nil {
-					return err
+					return wrapElementError(pd, "Endpoints", i, err)
 				}
 				l.Endpoints[i] = block
 			}
@@ -363,13 +363,13 @@ func (l *UpdateMetadataBroker) decode(pd packetDecoder, version int16) (err erro
 
 	if l.Version >= 2 {
 		if l.Rack, err = pd.getNullableString(); err != nil {
-			return err
+			return wrapFieldError(pd, "Rack", err)
 		}
 	}
 
 	if l.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -453,36 +453,36 @@ func (r *UpdateMetadataRequest) decode(pd packetDecoder, version int16) (err err
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ControllerID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ControllerID", err)
 	}
 
 	if r.Version >= 8 {
 		if r.isKRaftController, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "isKRaftController", err)
 		}
 	}
 
 	if r.ControllerEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ControllerEpoch", err)
 	}
 
 	if r.Version >= 5 {
 		if r.BrokerEpoch, err = pd.getInt64(); err != nil {
-			return err
+			return wrapFieldError(pd, "BrokerEpoch", err)
 		}
 	}
 
 	if r.Version >= 0 && r.Version <= 4 {
 		var numUngroupedPartitionStates int
 		if numUngroupedPartitionStates, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "UngroupedPartitionStates", err)
 		}
 		if numUngroupedPartitionStates > 0 {
 			r.UngroupedPartitionStates = make([]UpdateMetadataPartitionState_UpdateMetadataRequest, numUngroupedPartitionStates)
 			for i := 0; i < numUngroupedPartitionStates; i++ {
 				var block UpdateMetadataPartitionState_UpdateMetadataRequest
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "UngroupedPartitionStates", i, err)
 				}
 				r.UngroupedPartitionStates[i] = block
 			}
@@ -492,14 +492,14 @@ func (r *UpdateMetadataRequest) decode(pd packetDecoder, version int16) (err err
 	if r.Version >= 5 {
 		var numTopicStates int
 		if numTopicStates, err = pd.getArrayLength(); err != nil {
-			return err
+			return wrapFieldError(pd, "TopicStates", err)
 		}
 		if numTopicStates > 0 {
 			r.TopicStates = make([]UpdateMetadataTopicState, numTopicStates)
 			for i := 0; i < numTopicStates; i++ {
 				var block UpdateMetadataTopicState
 				if err := block.decode(pd, r.Version); err != nil {
-					return err
+					return wrapElementError(pd, "TopicStates", i, err)
 				}
 				r.TopicStates[i] = block
 			}
@@ -508,14 +508,14 @@ func (r *UpdateMetadataRequest) decode(pd packetDecoder, version int16) (err err
 
 	var numLiveBrokers int
 	if numLiveBrokers, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "LiveBrokers", err)
 	}
 	if numLiveBrokers > 0 {
 		r.LiveBrokers = make([]UpdateMetadataBroker, numLiveBrokers)
 		for i := 0; i < numLiveBrokers; i++ {
 			var block UpdateMetadataBroker
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "LiveBrokers", i, err)
 			}
 			r.LiveBrokers[i] = block
 		}
@@ -523,7 +523,7 @@ func (r *UpdateMetadataRequest) decode(pd packetDecoder, version int16) (err err
 
 	if r.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -549,6 +549,5 @@ func (r *UpdateMetadataRequest) IsValidVersion() bool {
 }
 
 func (r *UpdateMetadataRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -26,12 +26,12 @@ func (r *UpdateMetadataResponse) decode(pd packetDecoder, version int16) (err er
 		pd = FlexibleDecoderFrom(pd)
 	}
 	if r.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if r.Version >= 6 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -57,6 +57,5 @@ func (r *UpdateMetadataResponse) IsValidVersion() bool {
 }
 
 func (r *UpdateMetadataResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
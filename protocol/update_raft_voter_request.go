@@ -34,19 +34,19 @@ func (l *Listener_UpdateRaftVoterRequest) encode(pe packetEncoder, version int16
 func (l *Listener_UpdateRaftVoterRequest) decode(pd packetDecoder, version int16) (err error) {
 	l.Version = version
 	if l.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if l.Host, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Host", err)
 	}
 
 	if l.Port, err = pd.getUint16(); err != nil {
-		return err
+		return wrapFieldError(pd, "Port", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -74,15 +74,15 @@ func (k *KRaftVersionFeature_UpdateRaftVoterRequest) encode(pe packetEncoder, ve
 func (k *KRaftVersionFeature_UpdateRaftVoterRequest) decode(pd packetDecoder, version int16) (err error) {
 	k.Version = version
 	if k.MinSupportedVersion, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "MinSupportedVersion", err)
 	}
 
 	if k.MaxSupportedVersion, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "MaxSupportedVersion", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -139,31 +139,31 @@ func (r *UpdateRaftVoterRequest) decode(pd packetDecoder, version int16) (err er
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ClusterID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClusterID", err)
 	}
 
 	if r.CurrentLeaderEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "CurrentLeaderEpoch", err)
 	}
 
 	if r.VoterID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "VoterID", err)
 	}
 
 	if r.VoterDirectoryID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "VoterDirectoryID", err)
 	}
 
 	var numListeners int
 	if numListeners, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Listeners", err)
 	}
 	if numListeners > 0 {
 		r.Listeners = make([]Listener_UpdateRaftVoterRequest, numListeners)
 		for i := 0; i < numListeners; i++ {
 			var block Listener_UpdateRaftVoterRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Listeners", i, err)
 			}
 			r.Listeners[i] = block
 		}
@@ -171,12 +171,12 @@ func (r *UpdateRaftVoterRequest) decode(pd packetDecoder, version int16) (err er
 
 	tmpKRaftVersionFeature := KRaftVersionFeature_UpdateRaftVoterRequest{}
 	if err := tmpKRaftVersionFeature.decode(pd, r.Version); err != nil {
-		return err
+		return wrapFieldError(pd, "KRaftVersionFeature", err)
 	}
 	r.KRaftVersionFeature = tmpKRaftVersionFeature
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -198,6 +198,5 @@ func (r *UpdateRaftVoterRequest) IsValidVersion() bool {
 }
 
 func (r *UpdateRaftVoterRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
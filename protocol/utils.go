@@ -15,5 +15,10 @@ func getTimeFromMillis(millis int64) time.Time {
 }
 
 func getMillisFromTime(t time.Time) int64 {
+	// the inverse of getTimeFromMillis, an empty time.Time object is sent as -1.
+	if t.IsZero() {
+		return -1
+	}
+
 	return t.UnixNano() / int64(time.Millisecond)
 }
@@ -0,0 +1,107 @@
+package protocol
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// encodeWith encodes the values written by put, the way Encode sizes and then writes a message.
+func encodeWith(put func(pe packetEncoder)) []byte {
+	prep := &prepEncoder{}
+	put(prep)
+
+	re := &realEncoder{raw: make([]byte, prep.length)}
+	put(re)
+
+	return re.raw[:re.off]
+}
+
+func TestVarint_RoundTrip(t *testing.T) {
+	for _, v := range []int64{0, -1, 1, math.MaxInt32, math.MinInt32, math.MaxInt64, math.MinInt64} {
+		buf := encodeWith(func(pe packetEncoder) { pe.putVarint(v) })
+		rd := &realDecoder{raw: buf}
+		if got, err := rd.getVarint(); err != nil || got != v || rd.remaining() != 0 {
+			t.Errorf("getVarint() = %d, %v, want %d", got, err, v)
+		}
+	}
+
+	for _, v := range []uint64{0, 1, math.MaxUint32, math.MaxUint64} {
+		buf := encodeWith(func(pe packetEncoder) { pe.putUVarint(v) })
+		rd := &realDecoder{raw: buf}
+		if got, err := rd.getUVarint(); err != nil || got != v || rd.remaining() != 0 {
+			t.Errorf("getUVarint() = %d, %v, want %d", got, err, v)
+		}
+	}
+
+	for _, v := range []int32{0, -1, 1, math.MaxInt32, math.MinInt32} {
+		buf := encodeWith(func(pe packetEncoder) { pe.putVarint32(v) })
+		rd := &realDecoder{raw: buf}
+		if got, err := rd.getVarint32(); err != nil || got != v || rd.remaining() != 0 {
+			t.Errorf("getVarint32() = %d, %v, want %d", got, err, v)
+		}
+	}
+
+	for _, v := range []uint32{0, 1, math.MaxUint32} {
+		buf := encodeWith(func(pe packetEncoder) { pe.putUVarint32(v) })
+		rd := &realDecoder{raw: buf}
+		if got, err := rd.getUVarint32(); err != nil || got != v || rd.remaining() != 0 {
+			t.Errorf("getUVarint32() = %d, %v, want %d", got, err, v)
+		}
+	}
+}
+
+func TestVarint_Zigzag(t *testing.T) {
+	// zig-zag maps small negative numbers to small unsigned numbers, like Kafka's ByteUtils
+	tests := []struct {
+		value int32
+		want  []byte
+	}{
+		{0, []byte{0x00}},
+		{-1, []byte{0x01}},
+		{1, []byte{0x02}},
+		{-64, []byte{0x7f}},
+		{64, []byte{0x80, 0x01}},
+		{math.MaxInt32, []byte{0xfe, 0xff, 0xff, 0xff, 0x0f}},
+		{math.MinInt32, []byte{0xff, 0xff, 0xff, 0xff, 0x0f}},
+	}
+	for _, tt := range tests {
+		if got := encodeWith(func(pe packetEncoder) { pe.putVarint32(tt.value) }); string(got) != string(tt.want) {
+			t.Errorf("putVarint32(%d) = %x, want %x", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestVarint_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     []byte
+		decode  func(rd *realDecoder) error
+		wantErr error
+	}{
+		{name: "truncated varint", raw: []byte{0x80, 0x80}, decode: func(rd *realDecoder) error { _, err := rd.getVarint(); return err }, wantErr: ErrInsufficientData},
+		{name: "truncated uvarint", raw: []byte{0xff}, decode: func(rd *realDecoder) error { _, err := rd.getUVarint(); return err }, wantErr: ErrInsufficientData},
+		{name: "truncated varint32", raw: []byte{0x80}, decode: func(rd *realDecoder) error { _, err := rd.getVarint32(); return err }, wantErr: ErrInsufficientData},
+		{name: "empty uvarint32", raw: nil, decode: func(rd *realDecoder) error { _, err := rd.getUVarint32(); return err }, wantErr: ErrInsufficientData},
+		{
+			name:    "varint32 out of range",
+			raw:     encodeWith(func(pe packetEncoder) { pe.putVarint(math.MaxInt32 + 1) }),
+			decode:  func(rd *realDecoder) error { _, err := rd.getVarint32(); return err },
+			wantErr: errVarintOverflow,
+		},
+		{
+			name:    "uvarint32 out of range",
+			raw:     encodeWith(func(pe packetEncoder) { pe.putUVarint(math.MaxUint32 + 1) }),
+			decode:  func(rd *realDecoder) error { _, err := rd.getUVarint32(); return err },
+			wantErr: errUVarintOverflow,
+		},
+		{name: "varint longer than 64 bits", raw: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}, decode: func(rd *realDecoder) error { _, err := rd.getVarint(); return err }, wantErr: errVarintOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.decode(&realDecoder{raw: tt.raw}); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -60,45 +60,45 @@ func (p *PartitionData_VoteRequest) encode(pe packetEncoder, version int16) (err
 func (p *PartitionData_VoteRequest) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.ReplicaEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ReplicaEpoch", err)
 	}
 
 	if p.ReplicaID, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "ReplicaID", err)
 	}
 
 	if p.Version >= 1 {
 		if p.ReplicaDirectoryID, err = pd.getUUID(); err != nil {
-			return err
+			return wrapFieldError(pd, "ReplicaDirectoryID", err)
 		}
 	}
 
 	if p.Version >= 1 {
 		if p.VoterDirectoryID, err = pd.getUUID(); err != nil {
-			return err
+			return wrapFieldError(pd, "VoterDirectoryID", err)
 		}
 	}
 
 	if p.LastOffsetEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LastOffsetEpoch", err)
 	}
 
 	if p.LastOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "LastOffset", err)
 	}
 
 	if p.Version >= 2 {
 		if p.PreVote, err = pd.getBool(); err != nil {
-			return err
+			return wrapFieldError(pd, "PreVote", err)
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -135,26 +135,26 @@ func (t *TopicData_VoteRequest) encode(pe packetEncoder, version int16) (err err
 func (t *TopicData_VoteRequest) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.TopicName, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicName", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]PartitionData_VoteRequest, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionData_VoteRequest
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -197,32 +197,32 @@ func (r *VoteRequest) decode(pd packetDecoder, version int16) (err error) {
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.ClusterID, err = pd.getNullableString(); err != nil {
-		return err
+		return wrapFieldError(pd, "ClusterID", err)
 	}
 
 	if r.Version >= 1 {
 		if r.VoterID, err = pd.getInt32(); err != nil {
-			return err
+			return wrapFieldError(pd, "VoterID", err)
 		}
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]TopicData_VoteRequest, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block TopicData_VoteRequest
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -244,6 +244,5 @@ func (r *VoteRequest) IsValidVersion() bool {
 }
 
 func (r *VoteRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
@@ -0,0 +1,72 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestVoteRequest_RoundTrip(t *testing.T) {
+	clusterID := "test-cluster"
+
+	for _, version := range []int16{0, 1, 2} {
+		req := VoteRequest{
+			Version:   version,
+			ClusterID: &clusterID,
+			VoterID:   1,
+			Topics: []TopicData_VoteRequest{{
+				TopicName: "__cluster_metadata",
+				Partitions: []PartitionData_VoteRequest{{
+					PartitionIndex:     0,
+					ReplicaEpoch:       6,
+					ReplicaID:          2,
+					ReplicaDirectoryID: uuid.New(),
+					VoterDirectoryID:   uuid.New(),
+					LastOffsetEpoch:    5,
+					LastOffset:         1234,
+					PreVote:            version >= 2,
+				}},
+			}},
+		}
+
+		testRoundTrip(t, &req, &VoteRequest{}, version)
+	}
+}
+
+func TestVoteResponse_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name        string
+		voteGranted bool
+		leaderID    int32
+		leaderEpoch int32
+	}{
+		{name: "granted", voteGranted: true, leaderID: -1, leaderEpoch: 6},
+		{name: "not granted", voteGranted: false, leaderID: 1, leaderEpoch: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, version := range []int16{0, 1, 2} {
+				resp := VoteResponse{
+					Version: version,
+					Topics: []TopicData_VoteResponse{{
+						TopicName: "__cluster_metadata",
+						Partitions: []PartitionData_VoteResponse{{
+							PartitionIndex: 0,
+							LeaderID:       tt.leaderID,
+							LeaderEpoch:    tt.leaderEpoch,
+							VoteGranted:    tt.voteGranted,
+						}},
+					}},
+				}
+
+				decoded := VoteResponse{}
+				testRoundTrip(t, &resp, &decoded, version)
+
+				partition := decoded.Topics[0].Partitions[0]
+				if partition.VoteGranted != tt.voteGranted || partition.LeaderID != tt.leaderID || partition.LeaderEpoch != tt.leaderEpoch {
+					t.Errorf("v%d: got %+v", version, partition)
+				}
+			}
+		})
+	}
+}
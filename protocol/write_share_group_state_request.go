@@ -34,23 +34,23 @@ func (s *StateBatch_WriteShareGroupStateRequest) encode(pe packetEncoder, versio
 func (s *StateBatch_WriteShareGroupStateRequest) decode(pd packetDecoder, version int16) (err error) {
 	s.Version = version
 	if s.FirstOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "FirstOffset", err)
 	}
 
 	if s.LastOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "LastOffset", err)
 	}
 
 	if s.DeliveryState, err = pd.getInt8(); err != nil {
-		return err
+		return wrapFieldError(pd, "DeliveryState", err)
 	}
 
 	if s.DeliveryCount, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "DeliveryCount", err)
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -97,38 +97,38 @@ func (p *PartitionData_WriteShareGroupStateRequest) encode(pe packetEncoder, ver
 func (p *PartitionData_WriteShareGroupStateRequest) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.Partition, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partition", err)
 	}
 
 	if p.StateEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "StateEpoch", err)
 	}
 
 	if p.LeaderEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "LeaderEpoch", err)
 	}
 
 	if p.StartOffset, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "StartOffset", err)
 	}
 
 	var numStateBatches int
 	if numStateBatches, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "StateBatches", err)
 	}
 	if numStateBatches > 0 {
 		p.StateBatches = make([]StateBatch_WriteShareGroupStateRequest, numStateBatches)
 		for i := 0; i < numStateBatches; i++ {
 			var block StateBatch_WriteShareGroupStateRequest
 			if err := block.decode(pd, p.Version); err != nil {
-				return err
+				return wrapElementError(pd, "StateBatches", i, err)
 			}
 			p.StateBatches[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -165,26 +165,26 @@ func (t *WriteStateData) encode(pe packetEncoder, version int16) (err error) {
 func (t *WriteStateData) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.TopicID, err = pd.getUUID(); err != nil {
-		return err
+		return wrapFieldError(pd, "TopicID", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]PartitionData_WriteShareGroupStateRequest, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block PartitionData_WriteShareGroupStateRequest
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -221,26 +221,26 @@ func (r *WriteShareGroupStateRequest) decode(pd packetDecoder, version int16) (e
 	r.Version = version
 	pd = FlexibleDecoderFrom(pd)
 	if r.GroupID, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "GroupID", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		r.Topics = make([]WriteStateData, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block WriteStateData
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			r.Topics[i] = block
 		}
 	}
 
 	if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-		return err
+		return wrapFieldError(pd, "TaggedFields", err)
 	}
 	return nil
 }
@@ -262,6 +262,5 @@ func (r *WriteShareGroupStateRequest) IsValidVersion() bool {
 }
 
 func (r *WriteShareGroupStateRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
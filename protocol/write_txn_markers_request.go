@@ -30,16 +30,16 @@ func (t *WritableTxnMarkerTopic) encode(pe packetEncoder, version int16) (err er
 func (t *WritableTxnMarkerTopic) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	if t.PartitionIndexes, err = pd.getInt32Array(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndexes", err)
 	}
 
 	if t.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -89,39 +89,39 @@ func (m *WritableTxnMarker) encode(pe packetEncoder, version int16) (err error)
 func (m *WritableTxnMarker) decode(pd packetDecoder, version int16) (err error) {
 	m.Version = version
 	if m.ProducerID, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProducerID", err)
 	}
 
 	if m.ProducerEpoch, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProducerEpoch", err)
 	}
 
 	if m.TransactionResult, err = pd.getBool(); err != nil {
-		return err
+		return wrapFieldError(pd, "TransactionResult", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		m.Topics = make([]WritableTxnMarkerTopic, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block WritableTxnMarkerTopic
 			if err := block.decode(pd, m.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			m.Topics[i] = block
 		}
 	}
 
 	if m.CoordinatorEpoch, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "CoordinatorEpoch", err)
 	}
 
 	if m.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -160,14 +160,14 @@ func (r *WriteTxnMarkersRequest) decode(pd packetDecoder, version int16) (err er
 	}
 	var numMarkers int
 	if numMarkers, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Markers", err)
 	}
 	if numMarkers > 0 {
 		r.Markers = make([]WritableTxnMarker, numMarkers)
 		for i := 0; i < numMarkers; i++ {
 			var block WritableTxnMarker
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Markers", i, err)
 			}
 			r.Markers[i] = block
 		}
@@ -175,7 +175,7 @@ func (r *WriteTxnMarkersRequest) decode(pd packetDecoder, version int16) (err er
 
 	if r.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -201,6 +201,5 @@ func (r *WriteTxnMarkersRequest) IsValidVersion() bool {
 }
 
 func (r *WriteTxnMarkersRequest) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
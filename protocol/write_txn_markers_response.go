@@ -26,16 +26,16 @@ func (p *WritableTxnMarkerPartitionResult) encode(pe packetEncoder, version int1
 func (p *WritableTxnMarkerPartitionResult) decode(pd packetDecoder, version int16) (err error) {
 	p.Version = version
 	if p.PartitionIndex, err = pd.getInt32(); err != nil {
-		return err
+		return wrapFieldError(pd, "PartitionIndex", err)
 	}
 
 	if p.ErrorCode, err = pd.getInt16(); err != nil {
-		return err
+		return wrapFieldError(pd, "ErrorCode", err)
 	}
 
 	if p.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -75,19 +75,19 @@ func (t *WritableTxnMarkerTopicResult) encode(pe packetEncoder, version int16) (
 func (t *WritableTxnMarkerTopicResult) decode(pd packetDecoder, version int16) (err error) {
 	t.Version = version
 	if t.Name, err = pd.getString(); err != nil {
-		return err
+		return wrapFieldError(pd, "Name", err)
 	}
 
 	var numPartitions int
 	if numPartitions, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Partitions", err)
 	}
 	if numPartitions > 0 {
 		t.Partitions = make([]WritableTxnMarkerPartitionResult, numPartitions)
 		for i := 0; i < numPartitions; i++ {
 			var block WritableTxnMarkerPartitionResult
 			if err := block.decode(pd, t.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Partitions", i, err)
 			}
 			t.Partitions[i] = block
 		}
@@ -95,7 +95,7 @@ func (t *WritableTxnMarkerTopicResult) decode(pd packetDecoder, version int16) (
 
 	if t.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -133,19 +133,19 @@ func (m *WritableTxnMarkerResult) encode(pe packetEncoder, version int16) (err e
 func (m *WritableTxnMarkerResult) decode(pd packetDecoder, version int16) (err error) {
 	m.Version = version
 	if m.ProducerID, err = pd.getInt64(); err != nil {
-		return err
+		return wrapFieldError(pd, "ProducerID", err)
 	}
 
 	var numTopics int
 	if numTopics, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Topics", err)
 	}
 	if numTopics > 0 {
 		m.Topics = make([]WritableTxnMarkerTopicResult, numTopics)
 		for i := 0; i < numTopics; i++ {
 			var block WritableTxnMarkerTopicResult
 			if err := block.decode(pd, m.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Topics", i, err)
 			}
 			m.Topics[i] = block
 		}
@@ -153,7 +153,7 @@ func (m *WritableTxnMarkerResult) decode(pd packetDecoder, version int16) (err e
 
 	if m.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -192,14 +192,14 @@ func (r *WriteTxnMarkersResponse) decode(pd packetDecoder, version int16) (err e
 	}
 	var numMarkers int
 	if numMarkers, err = pd.getArrayLength(); err != nil {
-		return err
+		return wrapFieldError(pd, "Markers", err)
 	}
 	if numMarkers > 0 {
 		r.Markers = make([]WritableTxnMarkerResult, numMarkers)
 		for i := 0; i < numMarkers; i++ {
 			var block WritableTxnMarkerResult
 			if err := block.decode(pd, r.Version); err != nil {
-				return err
+				return wrapElementError(pd, "Markers", i, err)
 			}
 			r.Markers[i] = block
 		}
@@ -207,7 +207,7 @@ func (r *WriteTxnMarkersResponse) decode(pd packetDecoder, version int16) (err e
 
 	if r.Version >= 1 {
 		if _, err = pd.getEmptyTaggedFieldArray(); err != nil {
-			return err
+			return wrapFieldError(pd, "TaggedFields", err)
 		}
 	}
 	return nil
@@ -233,6 +233,5 @@ func (r *WriteTxnMarkersResponse) IsValidVersion() bool {
 }
 
 func (r *WriteTxnMarkersResponse) GetRequiredVersion() int16 {
-	// TODO - it isn't possible to determine this from the message format json files
-	return 0
+	return RequiredVersion(r.GetKey())
 }
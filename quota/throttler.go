@@ -0,0 +1,81 @@
+// Package quota limits the request rate of clients, so that a single client can't overwhelm the broker.
+package quota
+
+import (
+	"sync"
+	"time"
+)
+
+// pruneInterval is how often buckets of clients which are idle are removed.
+const pruneInterval = time.Minute
+
+// Throttler is a token bucket per client ID. Every request takes a token, tokens are refilled at the configured rate
+// up to the burst size. A client which runs out of tokens is throttled until its bucket is no longer in debt.
+// It is safe for concurrent use, since requests of the same client ID can come from different connections.
+type Throttler struct {
+	rate  float64
+	burst float64
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastPrune time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewThrottler returns a throttler which allows rate requests per second per client, with bursts of up to burst requests.
+// A rate of 0 or less disables throttling.
+func NewThrottler(rate, burst float64) *Throttler {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &Throttler{rate: rate, burst: burst, buckets: map[string]*bucket{}}
+}
+
+// Record takes a token from the bucket of the client for a request received at now,
+// and returns how long the response must be delayed, 0 if the client is within its quota.
+func (t *Throttler) Record(clientID string, now time.Time) time.Duration {
+	if t == nil || t.rate <= 0 {
+		return 0
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.prune(now)
+
+	b, ok := t.buckets[clientID]
+	if !ok {
+		b = &bucket{tokens: t.burst, last: now}
+		t.buckets[clientID] = b
+	}
+
+	b.tokens = min(t.burst, b.tokens+now.Sub(b.last).Seconds()*t.rate)
+	b.last = now
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	// the bucket may go into debt, so clients which keep sending while throttled are throttled for longer
+	return time.Duration(-b.tokens / t.rate * float64(time.Second))
+}
+
+// prune removes the buckets which are full again, they are recreated full when their client sends its next request.
+func (t *Throttler) prune(now time.Time) {
+	if now.Sub(t.lastPrune) < pruneInterval {
+		return
+	}
+	t.lastPrune = now
+
+	for clientID, b := range t.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*t.rate >= t.burst {
+			delete(t.buckets, clientID)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package quota
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThrottler(t *testing.T) {
+	throttler := NewThrottler(10, 5)
+	now := time.Now()
+
+	// the burst is served without throttling
+	for i := 0; i < 5; i++ {
+		if d := throttler.Record("client", now); d != 0 {
+			t.Fatalf("request %d throttled for %s, want no throttling within the burst", i, d)
+		}
+	}
+
+	// every request past the burst adds 100ms of debt at 10 requests per second
+	if d := throttler.Record("client", now); d != 100*time.Millisecond {
+		t.Errorf("throttle time = %s, want 100ms", d)
+	}
+	if d := throttler.Record("client", now); d != 200*time.Millisecond {
+		t.Errorf("throttle time = %s, want 200ms", d)
+	}
+
+	// other clients have their own bucket
+	if d := throttler.Record("other", now); d != 0 {
+		t.Errorf("other client throttled for %s, want no throttling", d)
+	}
+
+	// the debt is paid off over time
+	if d := throttler.Record("client", now.Add(time.Second)); d != 0 {
+		t.Errorf("throttle time after a second = %s, want no throttling", d)
+	}
+}
+
+func TestThrottler_Disabled(t *testing.T) {
+	throttler := NewThrottler(0, 0)
+	for i := 0; i < 100; i++ {
+		if d := throttler.Record("client", time.Now()); d != 0 {
+			t.Fatalf("throttle time = %s, want no throttling", d)
+		}
+	}
+
+	var nilThrottler *Throttler
+	if d := nilThrottler.Record("client", time.Now()); d != 0 {
+		t.Errorf("throttle time of nil throttler = %s, want no throttling", d)
+	}
+}
+
+func TestThrottler_Prune(t *testing.T) {
+	throttler := NewThrottler(10, 5)
+	now := time.Now()
+
+	throttler.Record("idle", now)
+	throttler.Record("busy", now.Add(pruneInterval))
+	if _, ok := throttler.buckets["idle"]; ok {
+		t.Error("bucket of idle client was not pruned")
+	}
+	if _, ok := throttler.buckets["busy"]; !ok {
+		t.Error("bucket of busy client was pruned")
+	}
+}
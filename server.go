@@ -1,60 +1,227 @@
 package main
 
 import (
+	"bufio"
 	"context"
-	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"math"
 	"net"
 	"opentalaria/api"
+	"opentalaria/auth"
 	"opentalaria/config"
+	"opentalaria/coordinator"
+	"opentalaria/logger"
+	"opentalaria/metadata"
 	"opentalaria/protocol"
+	"opentalaria/quota"
+	"opentalaria/storage"
 	"os"
 	"runtime"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/sync/semaphore"
 )
 
 type Server struct {
-	host   string
-	port   string
-	config *config.Config
+	host         string
+	port         string
+	listenerName string
+	config       *config.Config
+	topics       *metadata.TopicRegistry
+	logs         *storage.LogManager
+	raft         *metadata.RaftState
+	metadataLog  *metadata.Log
+	// brokers tracks the liveness of the registered brokers, it is nil if the node is not a controller
+	brokers *metadata.BrokerRegistry
+	// heartbeatInterval is the time between the heartbeats of the broker, set by broker.heartbeat.interval.ms
+	heartbeatInterval time.Duration
+	groups            *coordinator.GroupCoordinator
+	producerIDs       *metadata.ProducerIDManager
+	// authenticator authenticates the clients of SASL listeners, it is nil if the listener doesn't use SASL
+	authenticator *auth.Authenticator
+	// authorizer decides which operations clients may perform, based on the ACLs
+	authorizer auth.Authorizer
+	acls       *auth.ACLStore
+	// throttler delays the responses of clients which exceed quota.requests.per.second
+	throttler *quota.Throttler
+	// maxRequestSize is the largest request the server accepts, set by socket.request.max.bytes
+	maxRequestSize uint32
+	// idleTimeout is the time after which a connection without requests is closed, set by connections.max.idle.ms
+	idleTimeout time.Duration
+	// connsPerIP counts the live connections of each client IP, limited by max.connections.per.ip
+	connsPerIP    map[string]int64
+	connsPerIPMu  sync.Mutex
+	maxConnsPerIP int64
+
+	// mu guards the listener and the clients, which Shutdown closes
+	mu       sync.Mutex
+	listener net.Listener
+	clients  map[*Client]struct{}
+	// closing is set once Shutdown was called, so clients stop reading requests
+	closing atomic.Bool
+	// done is closed when serve returns, after all connections were handled
+	done chan struct{}
 }
 
 type Client struct {
-	conn   net.Conn
-	config *config.Config
+	conn net.Conn
+	// reader buffers the reads from the connection, so the disconnect watcher can peek at it without losing bytes
+	reader      *bufio.Reader
+	config      *config.Config
+	topics      *metadata.TopicRegistry
+	logs        *storage.LogManager
+	raft        *metadata.RaftState
+	groups      *coordinator.GroupCoordinator
+	producerIDs *metadata.ProducerIDManager
+	brokers     *metadata.BrokerRegistry
+	session     *auth.Session
+	// software is the client library which the client reported in ApiVersions
+	software *api.ClientSoftware
+	// listener is the kind of listener the connection was accepted on, which decides the APIs the client may use
+	listener   listenerKind
+	authorizer auth.Authorizer
+	acls       *auth.ACLStore
+	throttler  *quota.Throttler
+
+	maxRequestSize uint32
+	idleTimeout    time.Duration
+	closing        *atomic.Bool
 }
 
-func NewServer(config *config.Config) *Server {
-	var host, port string
+func NewServer(config *config.Config) (*Server, error) {
+	// hosts of the form if:<name> are resolved once, before the server starts, so the resolved address is bound and advertised
+	if err := config.Broker.ResolveInterfaceHosts(); err != nil {
+		return nil, err
+	}
+
+	var host, port, listenerName string
 	if len(config.Broker.Listeners) > 0 {
 		listener := config.Broker.Listeners[0]
 		host = listener.Host
 		port = strconv.Itoa(int(listener.Port))
+		listenerName = listener.ListenerName
 	}
 
-	return &Server{
-		host:   host,
-		port:   port,
-		config: config,
+	// viper hides the default of max.connections.per.ip when OT_MAX_CONNECTIONS is set, because it treats max.connections
+	// as a parent key, so a missing value means no limit
+	maxConnsPerIP := config.Env.GetInt64("max.connections.per.ip")
+	if maxConnsPerIP <= 0 {
+		maxConnsPerIP = math.MaxInt32
+	}
+
+	// the records of produced batches are decompressed while their request is decoded, which has no access to the config
+	protocol.SetMaxDecompressedSize(config.MessageDecompressedMaxBytes)
+
+	authenticator, err := newAuthenticator(config)
+	if err != nil {
+		return nil, fmt.Errorf("error setting up SASL authentication: %w", err)
+	}
+
+	// a standalone node leads the metadata quorum on its own, other nodes would have to negotiate a quorum first
+	raft := metadata.NewRaftState(config.Broker.BrokerID)
+	if config.Cluster.Standalone(config.Broker.BrokerID) {
+		raft = metadata.NewStandaloneRaftState(config.Broker.BrokerID)
+	}
+
+	metadataLog := metadata.NewLog(raft)
+
+	acls := auth.NewACLStore()
+
+	// without a burst size, a client may send the requests of one second at once
+	requestRate := config.Env.GetFloat64("quota.requests.per.second")
+	requestBurst := config.Env.GetFloat64("quota.requests.burst")
+	if requestBurst <= 0 {
+		requestBurst = requestRate
 	}
+
+	return &Server{
+		host:         host,
+		port:         port,
+		listenerName: listenerName,
+		config:       config,
+		topics:       metadata.NewTopicRegistry(),
+		logs:         storage.NewLogManager(),
+		raft:         raft,
+		metadataLog:  metadataLog,
+		brokers:      newBrokerRegistry(config, metadataLog),
+
+		heartbeatInterval: time.Duration(config.Env.GetInt64("broker.heartbeat.interval.ms")) * time.Millisecond,
+		groups: coordinator.NewGroupCoordinator(
+			time.Duration(config.Env.GetInt64("group.min.session.timeout.ms"))*time.Millisecond,
+			time.Duration(config.Env.GetInt64("group.max.session.timeout.ms"))*time.Millisecond,
+		),
+		producerIDs:   metadata.NewProducerIDManager(),
+		authenticator: authenticator,
+		authorizer:    auth.NewACLAuthorizer(acls, config.Env.GetBool("allow.everyone.if.no.acl.found")),
+		acls:          acls,
+		throttler:     quota.NewThrottler(requestRate, requestBurst),
+
+		maxRequestSize: config.Env.GetUint32("socket.request.max.bytes"),
+		idleTimeout:    time.Duration(config.Env.GetInt64("connections.max.idle.ms")) * time.Millisecond,
+		connsPerIP:     map[string]int64{},
+		maxConnsPerIP:  maxConnsPerIP,
+
+		clients: map[*Client]struct{}{},
+		done:    make(chan struct{}),
+	}, nil
 }
 
 func (server *Server) Run() {
-	ctx := context.TODO()
+	if server.config.LogStorage == config.LogStorageDisk {
+		logs, err := storage.OpenLogManager(storage.DiskOptions{
+			Dirs:               server.config.LogDirs,
+			SegmentBytes:       server.config.LogSegmentBytes,
+			FlushInterval:      server.config.LogFlushInterval,
+			IndexIntervalBytes: server.config.LogIndexIntervalBytes,
+			Retention: storage.Retention{
+				Time:  server.config.LogRetentionTime,
+				Bytes: server.config.LogRetentionBytes,
+			},
+			RetentionCheckInterval: server.config.LogRetentionCheckInterval,
+		})
+		if err != nil {
+			slog.Error("error opening the partition logs", "log.dirs", server.config.LogDirs, "err", err)
+			return
+		}
+		server.logs = logs
+		defer func() {
+			if err := logs.Close(); err != nil {
+				slog.Error("error closing the partition logs", "err", err)
+			}
+		}()
+	}
 
-	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%s", server.host, server.port))
+	if !server.config.Cluster.Standalone(server.config.Broker.BrokerID) {
+		slog.Warn("the node is not a standalone broker and controller, it can't negotiate a KRaft quorum, so the metadata quorum has no leader",
+			"process.roles", server.config.Env.Get("process.roles"), "controller.quorum.voters", server.config.Env.Get("controller.quorum.voters"))
+	}
+
+	listener, err := server.listen()
 	if err != nil {
 		slog.Error("error creating tcp listener", "err", err)
 		return
 	}
 	defer listener.Close()
 
-	slog.Info(fmt.Sprintf("tcp server listening on %s:%s", server.host, server.port))
+	// the broker registers once the listener is bound, so the registration has the resolved port of an ephemeral listener
+	brokerEpoch, err := server.registerBroker()
+	if err != nil {
+		slog.Error("error registering the broker", "err", err)
+		return
+	}
+	if brokerEpoch >= 0 {
+		go server.heartbeat(brokerEpoch)
+	}
+
+	slog.Info(fmt.Sprintf("tcp server listening on %s", net.JoinHostPort(server.host, server.port)))
 
 	cpu := os.Getenv("GOMAXPROCS")
 	if cpu == "" {
@@ -71,6 +238,24 @@ func (server *Server) Run() {
 	runtime.GOMAXPROCS(numberOfCpu)
 	slog.Debug("number of available CPU's ", "GOMAXPROCS", numberOfCpu)
 
+	server.serve(listener)
+}
+
+// serve accepts connections on the listener until it is closed, and handles each connection in its own goroutine.
+// Connections past max.connections or max.connections.per.ip are closed right away.
+func (server *Server) serve(listener net.Listener) {
+	defer close(server.done)
+
+	// a shutdown which happened before the server started serving closes the listener right away
+	server.mu.Lock()
+	server.listener = listener
+	if server.closing.Load() {
+		listener.Close()
+	}
+	server.mu.Unlock()
+
+	ctx := context.TODO()
+
 	var conCapacity int64
 	conPoolStr := server.config.Env.GetString("max.connections")
 	if conPoolStr == "" {
@@ -94,21 +279,38 @@ func (server *Server) Run() {
 
 	for {
 		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			break
+		}
 		if err != nil {
 			slog.Error("error accepting tcp connections", "err", err)
+			continue
 		}
 
-		client := &Client{
-			conn:   conn,
-			config: server.config,
+		if !sem.TryAcquire(1) {
+			slog.Warn("max.connections reached, closing the connection", "max.connections", conCapacity, "remote", conn.RemoteAddr())
+			conn.Close()
+			continue
 		}
 
-		if err := sem.Acquire(ctx, 1); err != nil {
-			slog.Error("Failed to acquire semaphore: %v", "err", err)
-			break
+		ip := remoteIP(conn)
+		if !server.acquireIPConnection(ip) {
+			slog.Warn("max.connections.per.ip reached, closing the connection", "max.connections.per.ip", server.maxConnsPerIP, "remote", conn.RemoteAddr())
+			sem.Release(1)
+			conn.Close()
+			continue
+		}
+
+		// the connection works with the options of the OS, so it is served anyway
+		if err := server.configureSocket(conn); err != nil {
+			slog.Warn("error configuring the socket", "remote", conn.RemoteAddr(), "err", err)
 		}
+		client := server.newClient(conn)
+		server.addClient(client)
 		go func() {
 			defer sem.Release(1)
+			defer server.releaseIPConnection(ip)
+			defer server.removeClient(client)
 			client.handleRequest()
 		}()
 	}
@@ -118,29 +320,359 @@ func (server *Server) Run() {
 	}
 }
 
+// Shutdown stops accepting connections and waits for the requests being processed to complete.
+// Idle connections are closed right away, all other connections once their current request has been answered.
+// If the context expires first, the remaining connections are closed and the error of the context is returned.
+func (server *Server) Shutdown(ctx context.Context) error {
+	server.mu.Lock()
+	server.closing.Store(true)
+	serving := server.listener != nil
+	if serving {
+		server.listener.Close()
+	}
+	// unblock the clients waiting for their next request
+	for client := range server.clients {
+		client.conn.SetReadDeadline(time.Now())
+	}
+	server.mu.Unlock()
+
+	if !serving {
+		return nil
+	}
+
+	select {
+	case <-server.done:
+		return nil
+	case <-ctx.Done():
+		server.mu.Lock()
+		for client := range server.clients {
+			client.conn.Close()
+		}
+		server.mu.Unlock()
+
+		return ctx.Err()
+	}
+}
+
+func (server *Server) addClient(client *Client) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.clients[client] = struct{}{}
+	// a client accepted while shutting down must not wait for a request
+	if server.closing.Load() {
+		client.conn.SetReadDeadline(time.Now())
+	}
+}
+
+func (server *Server) removeClient(client *Client) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	delete(server.clients, client)
+}
+
+// listen binds the server to the configured host and port.
+// If the configured port is 0, the OS picks a free ephemeral port, which is then written back to the broker config,
+// so the Metadata API advertises the port the server is actually listening on.
+func (server *Server) listen() (net.Listener, error) {
+	listener, err := net.Listen("tcp", net.JoinHostPort(server.host, server.port))
+	if err != nil {
+		return nil, err
+	}
+
+	if server.port == "0" {
+		port := listener.Addr().(*net.TCPAddr).Port
+		server.port = strconv.Itoa(port)
+		server.config.Broker.ResolveEphemeralPort(server.listenerName, int32(port))
+
+		slog.Info("listener port resolved to ephemeral port", "listener", server.listenerName, "port", port)
+	}
+
+	return listener, nil
+}
+
+// registerBroker registers the broker with its advertised listeners, by appending a RegisterBrokerRecord to the metadata log,
+// and returns its broker epoch, or -1 if the broker is not registered.
+// A standalone broker is its own controller, so it registers and heartbeats locally, without BrokerRegistration requests.
+// TODO: brokers of a quorum send a BrokerRegistration request to the controller.
+func (server *Server) registerBroker() (int64, error) {
+	if !server.config.Cluster.Standalone(server.config.Broker.BrokerID) {
+		slog.Warn("the broker is not registered, there is no KRaft quorum to register with")
+		return -1, nil
+	}
+
+	incarnationID, err := uuid.NewRandom()
+	if err != nil {
+		return -1, err
+	}
+
+	broker := server.config.Broker
+	// a generated broker ID must not collide with the brokers which registered before. A standalone node is the only broker
+	// of its metadata log, so it keeps the first ID above reserved.broker.max.id
+	if broker.GeneratedBrokerID {
+		if id := server.brokers.NextBrokerID(broker.ReservedBrokerMaxID); id != broker.BrokerID {
+			slog.Info("generated broker ID is taken, using the next free ID", "taken", broker.BrokerID, "broker.id", id)
+			broker.BrokerID = id
+		}
+	}
+
+	record := protocol.RegisterBrokerRecord{
+		BrokerID:      broker.BrokerID,
+		IncarnationID: incarnationID,
+		Features:      []protocol.BrokerFeature{{Name: metadata.MetadataVersionFeature, MinSupportedVersion: metadata.MetadataVersion, MaxSupportedVersion: metadata.MetadataVersion}},
+		Rack:          broker.Rack,
+	}
+	for _, l := range broker.AdvertisedListeners {
+		record.EndPoints = append(record.EndPoints, protocol.BrokerEndpoint{
+			Name:             strings.ToUpper(l.ListenerName),
+			Host:             l.Host,
+			Port:             uint16(l.Port),
+			SecurityProtocol: int16(l.SecurityProtocol),
+		})
+	}
+
+	brokerEpoch, err := server.brokers.Register(record, time.Now())
+	if err != nil {
+		return -1, err
+	}
+
+	// the broker registers fenced, the first heartbeat unfences it, as the local metadata log is always caught up
+	if _, err := server.brokers.Heartbeat(broker.BrokerID, brokerEpoch, server.metadataLog.LogEndOffset(), false, time.Now()); err != nil {
+		return -1, err
+	}
+
+	slog.Debug("broker registered", "broker.id", record.BrokerID, "brokerEpoch", brokerEpoch)
+	return brokerEpoch, nil
+}
+
+// heartbeat sends the heartbeats of the local broker to its own controller every broker.heartbeat.interval.ms,
+// and fences the brokers whose last heartbeat is older than broker.session.timeout.ms, until the server is shut down.
+func (server *Server) heartbeat(brokerEpoch int64) {
+	ticker := time.NewTicker(server.heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-server.done:
+			return
+		case now := <-ticker.C:
+			if _, err := server.brokers.Heartbeat(server.config.Broker.BrokerID, brokerEpoch, server.metadataLog.LogEndOffset(), false, now); err != nil {
+				slog.Error("error sending the broker heartbeat", "err", err)
+			}
+
+			fenced, err := server.brokers.FenceExpired(now)
+			if err != nil {
+				slog.Error("error fencing brokers without heartbeats", "err", err)
+				continue
+			}
+			for _, id := range fenced {
+				slog.Warn("fenced broker after missing heartbeats", "broker.id", id, "broker.session.timeout.ms", server.brokers.SessionTimeout().Milliseconds())
+			}
+		}
+	}
+}
+
+// acquireIPConnection counts a new connection from the IP and reports whether it is within max.connections.per.ip.
+func (server *Server) acquireIPConnection(ip string) bool {
+	server.connsPerIPMu.Lock()
+	defer server.connsPerIPMu.Unlock()
+
+	if server.connsPerIP[ip] >= server.maxConnsPerIP {
+		return false
+	}
+	server.connsPerIP[ip]++
+
+	return true
+}
+
+func (server *Server) releaseIPConnection(ip string) {
+	server.connsPerIPMu.Lock()
+	defer server.connsPerIPMu.Unlock()
+
+	server.connsPerIP[ip]--
+	if server.connsPerIP[ip] <= 0 {
+		delete(server.connsPerIP, ip)
+	}
+}
+
+// remoteIP returns the IP of the client, without the port.
+func remoteIP(conn net.Conn) string {
+	host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return conn.RemoteAddr().String()
+	}
+
+	return host
+}
+
+// newAuthenticator returns the authenticator of the clients of the listener, or nil if the listener doesn't use SASL.
+// The SCRAM credentials of the users in sasl.scram.passwords are derived here, their passwords are not kept.
+func newAuthenticator(conf *config.Config) (*auth.Authenticator, error) {
+	if len(conf.Broker.Listeners) == 0 || conf.Sasl == nil {
+		return nil, nil
+	}
+	if p := conf.Broker.Listeners[0].SecurityProtocol; p != config.SASL_PLAINTEXT && p != config.SASL_SSL {
+		return nil, nil
+	}
+
+	var tokens auth.TokenValidator
+	credentials := auth.NewMemoryCredentialStore()
+	for _, mechanism := range conf.Sasl.Mechanisms {
+		if mechanism == auth.OAuthBearer {
+			validator, err := newTokenValidator(conf.Sasl)
+			if err != nil {
+				return nil, err
+			}
+			tokens = validator
+			continue
+		}
+
+		for _, user := range conf.Sasl.ScramUsers {
+			credential, err := auth.NewScramCredential(mechanism, user.Password, auth.MinScramIterations)
+			if err != nil {
+				return nil, err
+			}
+			credentials.SetScramCredential(mechanism, user.Name, credential)
+		}
+	}
+
+	return auth.NewAuthenticator(conf.Sasl.Mechanisms, credentials, tokens)
+}
+
+// newTokenValidator returns the validator of OAUTHBEARER tokens. Tokens must be signed by a key of
+// sasl.oauthbearer.jwks.endpoint.url, unsigned tokens are only accepted if sasl.oauthbearer.unsecured is set,
+// like Kafka only accepts them if its unsecured validator is configured explicitly.
+func newTokenValidator(sasl *config.Sasl) (auth.TokenValidator, error) {
+	switch {
+	case sasl.OAuthBearerJWKSURL != "" && sasl.OAuthBearerUnsecured:
+		return nil, errors.New("sasl.oauthbearer.jwks.endpoint.url and sasl.oauthbearer.unsecured are both set, OAUTHBEARER tokens are either verified or not")
+	case sasl.OAuthBearerJWKSURL != "":
+		return auth.NewJWKSTokenValidator(sasl.OAuthBearerJWKSURL, sasl.OAuthBearerExpectedIssuer, sasl.OAuthBearerExpectedAudience, sasl.OAuthBearerJWKSRefreshInterval), nil
+	case sasl.OAuthBearerUnsecured:
+		slog.Warn("OAUTHBEARER tokens are not verified, any client can claim any principal", "sasl.oauthbearer.unsecured", true)
+		return auth.NewUnsecuredTokenValidator(), nil
+	default:
+		return nil, errors.New("OAUTHBEARER is enabled, but neither sasl.oauthbearer.jwks.endpoint.url nor sasl.oauthbearer.unsecured is set")
+	}
+}
+
+// newBrokerRegistry returns the registry of the brokers of the controller, or nil if the node is not a controller.
+func newBrokerRegistry(conf *config.Config, metadataLog *metadata.Log) *metadata.BrokerRegistry {
+	if !conf.Cluster.HasRole(config.RoleController) {
+		return nil
+	}
+
+	return metadata.NewBrokerRegistry(metadataLog, time.Duration(conf.Env.GetInt64("broker.session.timeout.ms"))*time.Millisecond)
+}
+
+// configureSocket sets the socket options of a TCP connection. Like in Kafka, TCP_NODELAY is always set, so small responses
+// are not delayed. The buffer sizes are set by socket.send.buffer.bytes and socket.receive.buffer.bytes, buffers of -1 keep
+// the sizes of the OS, and keepalive probes are sent if socket.keepalive.enable is set.
+func (server *Server) configureSocket(conn net.Conn) error {
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		return nil
+	}
+
+	var errs []error
+	if err := tcpConn.SetNoDelay(true); err != nil {
+		errs = append(errs, fmt.Errorf("error setting TCP_NODELAY: %w", err))
+	}
+
+	if size := server.config.SocketSendBufferBytes; size > 0 {
+		if err := tcpConn.SetWriteBuffer(size); err != nil {
+			errs = append(errs, fmt.Errorf("error setting the send buffer size to %d: %w", size, err))
+		}
+	}
+	if size := server.config.SocketReceiveBufferBytes; size > 0 {
+		if err := tcpConn.SetReadBuffer(size); err != nil {
+			errs = append(errs, fmt.Errorf("error setting the receive buffer size to %d: %w", size, err))
+		}
+	}
+
+	if err := tcpConn.SetKeepAlive(server.config.SocketKeepAlive); err != nil {
+		errs = append(errs, fmt.Errorf("error setting SO_KEEPALIVE: %w", err))
+	}
+	if server.config.SocketKeepAlive {
+		if err := tcpConn.SetKeepAlivePeriod(server.config.SocketKeepAliveInterval); err != nil {
+			errs = append(errs, fmt.Errorf("error setting the keepalive interval: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// newClient returns the client handling the requests of a connection, sharing the state of the server.
+func (server *Server) newClient(conn net.Conn) *Client {
+	// the requests are read through a buffer as large as the receive buffer of the socket
+	reader := bufio.NewReader(conn)
+	if size := server.config.SocketReceiveBufferBytes; size > 0 {
+		reader = bufio.NewReaderSize(conn, size)
+	}
+
+	return &Client{
+		conn:        conn,
+		reader:      reader,
+		config:      server.config,
+		topics:      server.topics,
+		logs:        server.logs,
+		raft:        server.raft,
+		groups:      server.groups,
+		producerIDs: server.producerIDs,
+		brokers:     server.brokers,
+		session:     auth.NewSession(server.authenticator),
+		software:    &api.ClientSoftware{},
+		listener:    server.listenerKind(),
+		authorizer:  server.authorizer,
+		acls:        server.acls,
+		throttler:   server.throttler,
+
+		maxRequestSize: server.maxRequestSize,
+		idleTimeout:    server.idleTimeout,
+		closing:        &server.closing,
+	}
+}
+
 func (client *Client) handleRequest() {
 	defer client.conn.Close()
 
-Exit:
 	// read from socket until there are no more bytes left.
 	for {
+		// the idle timer restarts with every request
+		if client.idleTimeout > 0 {
+			if err := client.conn.SetReadDeadline(time.Now().Add(client.idleTimeout)); err != nil {
+				slog.Error("error setting idle timeout", "err", err)
+				break
+			}
+		}
+
+		// checked after setting the idle timeout, which would otherwise override the deadline set by Shutdown
+		if client.closing != nil && client.closing.Load() {
+			break
+		}
+
 		// first 4 bytes contain the message size
-		sizeBytes := make([]byte, 4)
-		_, err := io.ReadFull(client.conn, sizeBytes[:])
+		// the size is checked before allocating the buffer, a bogus length prefix must not make us allocate gigabytes
+		messageBytes, err := protocol.ReadFrame(client.reader, int(client.maxRequestSize))
 		if err == io.EOF {
 			break
 		}
-		if err != nil {
-			slog.Error("tcp read error", "err", err)
+		if errors.Is(err, os.ErrDeadlineExceeded) && client.closing != nil && client.closing.Load() {
+			slog.Debug("closing connection for shutdown", "remote", client.conn.RemoteAddr())
 			break
 		}
-		size := binary.BigEndian.Uint32(sizeBytes)
-
-		// read the rest of the message into the buffer.
-		messageBytes := make([]byte, size)
-
-		if _, err := io.ReadFull(client.conn, messageBytes[:]); err != nil {
-			slog.Error("error decoding message", "err", err)
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			slog.Info("closing idle connection", "connections.max.idle.ms", client.idleTimeout.Milliseconds(), "remote", client.conn.RemoteAddr())
+			break
+		}
+		if errors.Is(err, protocol.ErrFrameTooLarge) {
+			slog.Warn("request size exceeds socket.request.max.bytes, closing the connection",
+				"err", err, "socket.request.max.bytes", client.maxRequestSize, "remote", client.conn.RemoteAddr())
+			break
+		}
+		if err != nil {
+			slog.Error("tcp read error", "err", err)
 			break
 		}
 
@@ -151,78 +683,269 @@ Exit:
 		// We parse the header twice, first time parse only API key and API version, from which we can
 		// infer the correct header version and then parse that again in the API code to get the full header.
 		header := &protocol.RequestHeader{}
-		protocol.VersionedDecode(messageBytes, header, 1)
+		if _, err := protocol.VersionedDecode(messageBytes, header, 1); err != nil {
+			// without a correlation ID there is no way to answer the request
+			slog.Error("error decoding request header", "err", err)
+			break
+		}
 
-		slog.Debug(header.String())
+		slog.Debug("handling request", logger.Request(header))
 
-		var apiHandler api.API
-		switch header.RequestApiKey {
-		case (&protocol.ApiVersionsRequest{}).GetKey():
-			req, err := makeRequest(messageBytes,
-				client.conn,
-				(&protocol.ApiVersionsRequest{Version: header.RequestApiVersion}).GetHeaderVersion(),
-				client.config)
-			if err != nil {
-				slog.Error("error creating request", "err", err)
-				// This break exits the outer for loop and closes the socket connection.
-				// If there is an error in the metadata exchange for example, we don't want to continue consuming the rest of the APIs.
-				break Exit
-			}
-			apiHandler = api.APIVersionsAPI{Request: req}
-		case (&protocol.MetadataRequest{}).GetKey():
-			req, err := makeRequest(messageBytes,
-				client.conn,
-				(&protocol.MetadataRequest{Version: header.RequestApiVersion}).GetHeaderVersion(),
-				client.config)
-			if err != nil {
-				slog.Error("error creating request", "err", err)
-				break Exit
-			}
-			apiHandler = api.MetadataAPI{Request: req}
-		case (&protocol.ProduceRequest{}).GetKey():
-			req, err := makeRequest(messageBytes,
-				client.conn,
-				(&protocol.ProduceRequest{Version: header.RequestApiVersion}).GetHeaderVersion(),
-				client.config)
-			if err != nil {
-				slog.Error("error creating request", "err", err)
-				break Exit
-			}
-			apiHandler = api.ProduceAPI{Request: req}
-		case (&protocol.CreateTopicsRequest{}).GetKey():
-			req, err := makeRequest(messageBytes,
-				client.conn,
-				(&protocol.CreateTopicsRequest{Version: header.RequestApiVersion}).GetHeaderVersion(),
-				client.config)
-			if err != nil {
-				slog.Error("error creating request", "err", err)
-				break Exit
-			}
-			apiHandler = api.CreateTopicsAPI{Request: req}
-		default:
-			slog.Error("Unknown API key", "key", header.RequestApiKey)
+		if err := client.handle(messageBytes, header); err != nil {
+			break
 		}
+	}
+}
 
-		err = api.HandleResponse(apiHandler)
-		if err != nil {
-			slog.Error("error handling response", "err", err)
-			break
+// handle answers a single request. The context of the request is cancelled if the client disconnects while it is handled,
+// so handlers waiting for something, like fetches waiting for records, don't outlive the connection.
+// Errors are logged, the returned error only tells the caller to close the connection.
+func (client *Client) handle(msg []byte, header *protocol.RequestHeader) error {
+	if !client.mayHandle(header.RequestApiKey) {
+		slog.Warn("request before SASL authentication, closing the connection", logger.Request(header), "remote", client.conn.RemoteAddr())
+		return errUnauthenticated
+	}
+
+	if !client.listener.serves(header.RequestApiKey) {
+		slog.Warn("API is not served on this listener, closing the connection", logger.Request(header), "listener", client.listener.String(), "remote", client.conn.RemoteAddr())
+		return errWrongListener
+	}
+
+	ctx, stop := client.watchDisconnect()
+	defer stop()
+
+	req, err := client.makeRequest(ctx, msg, header.RequestApiKey, header.RequestApiVersion)
+	if errors.Is(err, protocol.ErrUnknownAPIKey) {
+		slog.Warn("unknown API key, closing the connection", "apiKey", header.RequestApiKey, "remote", client.conn.RemoteAddr())
+		return err
+	}
+	if err != nil {
+		slog.Error("error creating request", "err", err)
+		// If there is an error in the metadata exchange for example, we don't want to continue consuming the rest of the APIs.
+		return err
+	}
+
+	// requests without a client ID share a quota, like in Kafka
+	var clientID string
+	if header.ClientID != nil {
+		clientID = *header.ClientID
+	}
+	req.ThrottleTime = client.throttler.Record(clientID, time.Now())
+	if req.ThrottleTime > 0 {
+		slog.Debug("client exceeded its request quota, throttling the response", logger.Request(header), "throttleTime", req.ThrottleTime)
+	}
+
+	apiHandler, err := api.NewHandler(req)
+	if err != nil {
+		slog.Error("error routing request", "err", err)
+		return err
+	}
+
+	err = api.HandleResponse(apiHandler)
+	if errors.Is(err, context.Canceled) {
+		slog.Debug("request cancelled, the client disconnected", logger.Request(header))
+		return err
+	}
+	if err != nil {
+		slog.Error("error handling response", "err", err)
+		return err
+	}
+
+	if client.session != nil && client.session.Failed() {
+		slog.Info("SASL authentication failed, closing the connection", "remote", client.conn.RemoteAddr())
+		return errAuthenticationFailed
+	}
+
+	return nil
+}
+
+// errAuthenticationFailed closes the connection of a client after the response to its failed authentication, like Kafka does.
+var errAuthenticationFailed = errors.New("client failed to authenticate")
+
+// errUnauthenticated closes the connection of a client that didn't authenticate before sending its requests.
+var errUnauthenticated = errors.New("client is not authenticated")
+
+// mayHandle reports whether the request can be handled before the client authenticated.
+// Like Kafka, the clients of SASL listeners may only ask for the API versions and authenticate, until they are authenticated.
+func (client *Client) mayHandle(apiKey int16) bool {
+	if client.session == nil || !client.session.Enabled() || client.session.Authenticated() {
+		return true
+	}
+
+	switch apiKey {
+	case (&protocol.ApiVersionsRequest{}).GetKey(), (&protocol.SaslHandshakeRequest{}).GetKey(), (&protocol.SaslAuthenticateRequest{}).GetKey():
+		return true
+	default:
+		return false
+	}
+}
+
+// errWrongListener closes the connection of a client that sent a controller API to a broker listener, or a client API to
+// a controller listener.
+var errWrongListener = errors.New("API is not served on this listener")
+
+// listenerKind is the kind of a listener, which decides the APIs it serves.
+type listenerKind int
+
+const (
+	// anyListener serves all APIs, it is the listener of a node without controller.listener.names
+	anyListener listenerKind = iota
+	brokerListener
+	controllerListener
+)
+
+func (k listenerKind) String() string {
+	switch k {
+	case brokerListener:
+		return "broker"
+	case controllerListener:
+		return "controller"
+	default:
+		return "any"
+	}
+}
+
+// controllerAPIs are the APIs of the KRaft quorum and of the cluster membership, which only controller listeners serve.
+var controllerAPIs = map[int16]bool{
+	(&protocol.VoteRequest{}).GetKey():                   true,
+	(&protocol.BeginQuorumEpochRequest{}).GetKey():       true,
+	(&protocol.EndQuorumEpochRequest{}).GetKey():         true,
+	(&protocol.FetchSnapshotRequest{}).GetKey():          true,
+	(&protocol.AddRaftVoterRequest{}).GetKey():           true,
+	(&protocol.RemoveRaftVoterRequest{}).GetKey():        true,
+	(&protocol.UpdateRaftVoterRequest{}).GetKey():        true,
+	(&protocol.BrokerRegistrationRequest{}).GetKey():     true,
+	(&protocol.BrokerHeartbeatRequest{}).GetKey():        true,
+	(&protocol.ControllerRegistrationRequest{}).GetKey(): true,
+	(&protocol.AllocateProducerIdsRequest{}).GetKey():    true,
+	(&protocol.AlterPartitionRequest{}).GetKey():         true,
+	(&protocol.AssignReplicasToDirsRequest{}).GetKey():   true,
+}
+
+// sharedAPIs are served on both kinds of listeners. Clients authenticate on both, and the followers of the quorum
+// replicate the metadata log with Fetch requests.
+var sharedAPIs = map[int16]bool{
+	(&protocol.ApiVersionsRequest{}).GetKey():      true,
+	(&protocol.SaslHandshakeRequest{}).GetKey():    true,
+	(&protocol.SaslAuthenticateRequest{}).GetKey(): true,
+	(&protocol.FetchRequest{}).GetKey():            true,
+	(&protocol.DescribeQuorumRequest{}).GetKey():   true,
+}
+
+// serves reports whether the API is served on the listener, like in Kafka, where controller APIs are only served
+// on the listeners of controller.listener.names and client APIs only on the other listeners.
+func (k listenerKind) serves(apiKey int16) bool {
+	switch {
+	case k == anyListener || sharedAPIs[apiKey]:
+		return true
+	case k == controllerListener:
+		return controllerAPIs[apiKey]
+	default:
+		return !controllerAPIs[apiKey]
+	}
+}
+
+// listenerKind returns the kind of the listener of the server. Without controller.listener.names, the listener of
+// a standalone node serves the clients and the quorum APIs alike.
+func (server *Server) listenerKind() listenerKind {
+	switch {
+	case len(server.config.Cluster.ControllerListenerNames) == 0:
+		return anyListener
+	case server.config.Cluster.IsControllerListener(server.listenerName):
+		return controllerListener
+	default:
+		return brokerListener
+	}
+}
+
+// watchDisconnect returns a context which is cancelled when the connection is closed by the client.
+// It peeks at the connection in the background, a client which sends its next request before the answer isn't disconnected,
+// so the peek stops without cancelling the context. stop ends the watch and must be called before reading the next request.
+func (client *Client) watchDisconnect() (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	watching := make(chan struct{})
+	go func() {
+		defer close(watching)
+
+		// the deadline set by stop, or the idle timeout, ends the peek without a disconnect
+		if _, err := client.reader.Peek(1); err != nil && !errors.Is(err, os.ErrDeadlineExceeded) {
+			cancel()
 		}
+	}()
+
+	return ctx, func() {
+		client.conn.SetReadDeadline(time.Now())
+		<-watching
+		cancel()
+
+		// the idle timeout is set again before reading the next request, a deadline set by Shutdown is replaced by
+		// the check of closing that follows it
+		client.conn.SetReadDeadline(time.Time{})
 	}
 }
 
-func makeRequest(msg []byte, conn net.Conn, headerVersion int16, config *config.Config) (api.Request, error) {
+// credentialAPIs are the APIs whose requests carry credentials, like SASL tokens and delegation token HMACs.
+// Their bodies are not dumped when they fail to decode, like their fields are redacted when they are traced.
+var credentialAPIs = map[int16]bool{
+	(&protocol.SaslHandshakeRequest{}).GetKey():             true,
+	(&protocol.SaslAuthenticateRequest{}).GetKey():          true,
+	(&protocol.CreateDelegationTokenRequest{}).GetKey():     true,
+	(&protocol.RenewDelegationTokenRequest{}).GetKey():      true,
+	(&protocol.ExpireDelegationTokenRequest{}).GetKey():     true,
+	(&protocol.DescribeDelegationTokenRequest{}).GetKey():   true,
+	(&protocol.AlterUserScramCredentialsRequest{}).GetKey(): true,
+}
+
+// makeRequest parses the full request header and decodes the body into the request type of the API key.
+// The body of an unsupported version is left empty, the handler answers it with an UNSUPPORTED_VERSION error.
+// A body that can't be decoded is reported in the DecodeError of the request, so the handler can answer it with an error,
+// only an error parsing the header is returned.
+func (client *Client) makeRequest(ctx context.Context, msg []byte, apiKey, apiVersion int16) (api.Request, error) {
+	body, err := protocol.NewRequest(apiKey, apiVersion)
+	if err != nil {
+		return api.Request{}, err
+	}
+
 	// parse the full header, based on API key and version
 	header := &protocol.RequestHeader{}
-	headerSize, err := protocol.VersionedDecode(msg, header, headerVersion)
+	headerSize, err := protocol.VersionedDecode(msg, header, body.GetHeaderVersion())
 	if err != nil {
 		return api.Request{}, err
 	}
 
+	var decodeErr error
+	if protocol.IsSupportedVersion(body) {
+		if _, decodeErr = protocol.VersionedDecode(msg[headerSize:], body, apiVersion); decodeErr != nil {
+			attrs := []any{"apiKey", apiKey, "apiVersion", apiVersion, "err", decodeErr}
+			if credentialAPIs[apiKey] {
+				attrs = append(attrs, "size", len(msg[headerSize:]))
+			} else {
+				attrs = append(attrs, "bytes", logger.HexDump(msg[headerSize:]))
+			}
+			slog.Log(context.Background(), logger.LevelTrace, "error decoding request body", attrs...)
+		}
+	}
+
 	return api.Request{
-		Header:  *header,
-		Message: msg[headerSize:],
-		Conn:    conn,
-		Config:  config,
+		Ctx:         ctx,
+		Header:      *header,
+		Message:     msg[headerSize:],
+		Body:        body,
+		DecodeError: decodeErr,
+		Conn:        client.conn,
+		Config:      client.config,
+		Topics:      client.topics,
+		Logs:        client.logs,
+		Raft:        client.raft,
+		Groups:      client.groups,
+		ProducerIDs: client.producerIDs,
+		Brokers:     client.brokers,
+		Session:     client.session,
+		Authorizer:  client.authorizer,
+		ACLs:        client.acls,
+
+		ClientSoftware: client.software,
+		Serves:         client.listener.serves,
 	}, nil
 }
@@ -1,14 +1,28 @@
 package main
 
 import (
+	"bytes"
 	"context"
-	"fmt"
+	"encoding/binary"
+	"io"
+	"log/slog"
 	"net"
+	"opentalaria/auth"
 	"opentalaria/config"
+	"opentalaria/coordinator"
+	"opentalaria/logger"
+	"opentalaria/protocol"
+	"opentalaria/utils"
 	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type MockClient struct {
@@ -36,7 +50,10 @@ func TestServer_Run(t *testing.T) {
 		t.Error(err)
 	}
 
-	server := NewServer(conf)
+	server, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	// Create a context with cancellation
 	_, cancel := context.WithCancel(context.Background())
@@ -49,7 +66,7 @@ func TestServer_Run(t *testing.T) {
 	time.Sleep(100 * time.Millisecond)
 
 	// Dial the server to simulate a client
-	addr := fmt.Sprintf("%s:%s", server.host, server.port)
+	addr := net.JoinHostPort(server.host, server.port)
 	conn, err := net.Dial("tcp", addr)
 
 	if err != nil {
@@ -79,3 +96,1043 @@ func TestServer_Run(t *testing.T) {
 	cancel()
 	time.Sleep(100 * time.Millisecond)
 }
+
+func TestServer_listenEphemeralPort(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:0")
+	t.Setenv("OT_ADVERTISED_LISTENERS", "PLAINTEXT://localhost:0")
+
+	conf, err := config.NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := server.listen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	port := conf.Broker.Listeners[0].Port
+	if port == 0 {
+		t.Fatal("expected listener port to be resolved to a non-zero ephemeral port")
+	}
+
+	if got := int32(listener.Addr().(*net.TCPAddr).Port); got != port {
+		t.Errorf("listener port = %d, bound port %d", port, got)
+	}
+
+	if got := conf.Broker.AdvertisedListeners[0].Port; got != port {
+		t.Errorf("advertised listener port = %d, want %d", got, port)
+	}
+}
+
+func TestNewServer_Standalone(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:9092")
+
+	conf, err := config.NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a node with both roles is standalone by default, the local node leads the quorum without any quorum messages
+	server, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeID := conf.Broker.BrokerID
+	if leaderID, leaderEpoch := server.raft.Leader(); leaderID != nodeID || leaderEpoch != 0 {
+		t.Errorf("leader = %d in epoch %d, want the local node %d in epoch 0", leaderID, leaderEpoch, nodeID)
+	}
+	if voters := server.raft.Voters(); len(voters) != 1 || voters[0] != nodeID {
+		t.Errorf("voters = %v, want only the local node", voters)
+	}
+
+	t.Setenv("OT_PROCESS_ROLES", "broker")
+	conf, err = config.NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server, err = NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leaderID, _ := server.raft.Leader(); leaderID != -1 {
+		t.Errorf("leader of a broker without quorum = %d, want -1", leaderID)
+	}
+}
+
+func TestServer_registerBroker(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:0")
+	t.Setenv("OT_ADVERTISED_LISTENERS", "PLAINTEXT://broker.example.com:0")
+
+	conf, err := config.NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := server.listen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	brokerEpoch, err := server.registerBroker()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	brokers := server.metadataLog.Brokers()
+	if len(brokers) != 1 || brokers[0].BrokerID != conf.Broker.BrokerID {
+		t.Fatalf("brokers = %+v, want the local broker %d", brokers, conf.Broker.BrokerID)
+	}
+
+	// the broker is registered with its advertised listener, on the port the listener is bound to
+	port := uint16(listener.Addr().(*net.TCPAddr).Port)
+	want := []protocol.BrokerEndpoint{{Name: "PLAINTEXT", Host: "broker.example.com", Port: port, SecurityProtocol: int16(config.PLAINTEXT)}}
+	if !slices.Equal(brokers[0].EndPoints, want) {
+		t.Errorf("endpoints = %+v, want %+v", brokers[0].EndPoints, want)
+	}
+	if brokers[0].BrokerEpoch != brokerEpoch || brokers[0].Fenced {
+		t.Errorf("broker epoch = %d, fenced = %v, want epoch %d, unfenced", brokers[0].BrokerEpoch, brokers[0].Fenced, brokerEpoch)
+	}
+
+	// the registration and the unfencing by the first heartbeat are records of the metadata log, which followers replicate
+	records, err := server.metadataLog.Read(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Record.RecordType() != protocol.RegisterBrokerRecordType || records[1].Record.RecordType() != protocol.UnfenceBrokerRecordType {
+		t.Errorf("metadata records = %+v, want the registration and the unfencing", records)
+	}
+}
+
+func TestServer_registerBroker_GeneratedID(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:0")
+	t.Setenv("OT_ADVERTISED_LISTENERS", "PLAINTEXT://localhost:0")
+
+	conf, err := config.NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !conf.Broker.GeneratedBrokerID || conf.Broker.BrokerID != 1001 {
+		t.Fatalf("broker ID = %d, generated = %v, want the generated ID 1001", conf.Broker.BrokerID, conf.Broker.GeneratedBrokerID)
+	}
+
+	// two brokers registered with generated IDs before
+	server, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []int32{1001, 1002} {
+		if _, err := server.brokers.Register(protocol.RegisterBrokerRecord{BrokerID: id, IncarnationID: uuid.New()}, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := server.registerBroker(); err != nil {
+		t.Fatal(err)
+	}
+	if conf.Broker.BrokerID != 1003 {
+		t.Errorf("broker ID = %d, want the next free ID 1003", conf.Broker.BrokerID)
+	}
+	if _, ok := server.metadataLog.Broker(1003); !ok {
+		t.Error("the broker is not registered with the next free ID")
+	}
+}
+
+// writeTestRequest frames the header and body the way a client sends a request.
+func writeTestRequest(t *testing.T, conn net.Conn, header protocol.RequestHeader, body []byte) {
+	t.Helper()
+
+	headerBytes, err := protocol.Encode(&header)
+	if err != nil {
+		t.Fatalf("error encoding header: %v", err)
+	}
+
+	if err := protocol.WriteFrame(conn, append(headerBytes, body...)); err != nil {
+		t.Fatalf("error writing request: %v", err)
+	}
+}
+
+// readTestApiVersionsResponse reads a response and decodes it as a v0 ApiVersions response.
+func readTestApiVersionsResponse(t *testing.T, conn net.Conn) (int32, protocol.ApiVersionsResponse) {
+	t.Helper()
+
+	return readTestVersionedApiVersionsResponse(t, conn, 0)
+}
+
+// readTestVersionedApiVersionsResponse reads a response and decodes it as an ApiVersions response of the version.
+func readTestVersionedApiVersionsResponse(t *testing.T, conn net.Conn, version int16) (int32, protocol.ApiVersionsResponse) {
+	t.Helper()
+
+	payload, err := protocol.ReadFrame(conn, 1<<20)
+	if err != nil {
+		t.Fatalf("error reading response: %v", err)
+	}
+
+	// ApiVersions responses always have a v0 header, so clients can read them whatever version they sent
+	header := protocol.ResponseHeader{}
+	headerSize, err := protocol.VersionedDecode(payload, &header, 0)
+	if err != nil {
+		t.Fatalf("error decoding response header: %v", err)
+	}
+	resp := protocol.ApiVersionsResponse{}
+	if _, err := protocol.VersionedDecode(payload[headerSize:], &resp, version); err != nil {
+		t.Fatalf("error decoding response: %v", err)
+	}
+
+	return header.CorrelationID, resp
+}
+
+func TestClient_handleRequest_DecodeError(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:9092")
+
+	conf, err := config.NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+
+	client := server.newClient(serverConn)
+	go client.handleRequest()
+
+	clientID := "test-client"
+	header := protocol.RequestHeader{Version: 2, RequestApiKey: (&protocol.ApiVersionsRequest{}).GetKey(), RequestApiVersion: 3, CorrelationID: 7, ClientID: &clientID}
+	body, err := protocol.Encode(&protocol.ApiVersionsRequest{Version: 3, ClientSoftwareName: "test-client", ClientSoftwareVersion: "1.0.0"})
+	if err != nil {
+		t.Fatalf("error encoding body: %v", err)
+	}
+
+	// a truncated body can't be decoded, but the header can
+	writeTestRequest(t, clientConn, header, body[:len(body)-3])
+
+	correlationID, resp := readTestApiVersionsResponse(t, clientConn)
+	if correlationID != 7 {
+		t.Errorf("correlation ID = %d, want 7", correlationID)
+	}
+	if resp.ErrorCode != int16(utils.ErrInvalidMessage) {
+		t.Errorf("error code = %d, want %d", resp.ErrorCode, utils.ErrInvalidMessage)
+	}
+
+	// the connection stays open for the next request
+	header.RequestApiVersion = 0
+	header.CorrelationID = 8
+	writeTestRequest(t, clientConn, header, nil)
+
+	correlationID, resp = readTestApiVersionsResponse(t, clientConn)
+	if correlationID != 8 {
+		t.Errorf("correlation ID = %d, want 8", correlationID)
+	}
+	if resp.ErrorCode != int16(utils.ErrNoError) {
+		t.Errorf("error code = %d, want %d", resp.ErrorCode, utils.ErrNoError)
+	}
+}
+
+func TestClient_handleRequest_RequestTooLarge(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:9092")
+	t.Setenv("OT_SOCKET_REQUEST_MAX_BYTES", "1024")
+
+	conf, err := config.NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+
+	client := server.newClient(serverConn)
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	done := make(chan struct{})
+	go func() {
+		client.handleRequest()
+		close(done)
+	}()
+
+	// a 2GB length prefix, without the request that should follow it
+	if _, err := clientConn.Write(binary.BigEndian.AppendUint32(nil, 2<<30-1)); err != nil {
+		t.Fatalf("error writing length prefix: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the connection was not closed after an oversized length prefix")
+	}
+
+	runtime.ReadMemStats(&after)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+		t.Errorf("allocated %d bytes for a refused request", allocated)
+	}
+
+	if _, err := clientConn.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("read error = %v, want %v", err, io.EOF)
+	}
+}
+
+func TestClient_handleRequest_SaslRequired(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "CLIENT://localhost:9092")
+	t.Setenv("OT_LISTENER_SECURITY_PROTOCOL_MAP", "CLIENT:SASL_PLAINTEXT")
+	t.Setenv("OT_SASL_SCRAM_PASSWORDS", "alice:secret")
+
+	conf, err := config.NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+
+	done := make(chan struct{})
+	go func() {
+		server.newClient(serverConn).handleRequest()
+		close(done)
+	}()
+
+	// the API versions are served before authenticating
+	header := protocol.RequestHeader{Version: 1, RequestApiKey: (&protocol.ApiVersionsRequest{}).GetKey(), RequestApiVersion: 0, CorrelationID: 1}
+	writeTestRequest(t, clientConn, header, nil)
+	if _, resp := readTestApiVersionsResponse(t, clientConn); resp.ErrorCode != int16(utils.ErrNoError) {
+		t.Errorf("error code = %d, want %d", resp.ErrorCode, utils.ErrNoError)
+	}
+
+	body, err := protocol.Encode(&protocol.MetadataRequest{Version: 1})
+	if err != nil {
+		t.Fatalf("error encoding body: %v", err)
+	}
+	header = protocol.RequestHeader{Version: 1, RequestApiKey: (&protocol.MetadataRequest{}).GetKey(), RequestApiVersion: 1, CorrelationID: 2}
+	writeTestRequest(t, clientConn, header, body)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the connection was not closed after a request before authenticating")
+	}
+}
+
+func TestClient_handleRequest_SaslAuthenticationFailed(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "CLIENT://localhost:9092")
+	t.Setenv("OT_LISTENER_SECURITY_PROTOCOL_MAP", "CLIENT:SASL_PLAINTEXT")
+	t.Setenv("OT_SASL_SCRAM_PASSWORDS", "alice:secret")
+
+	conf, err := config.NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+
+	done := make(chan struct{})
+	go func() {
+		server.newClient(serverConn).handleRequest()
+		close(done)
+	}()
+
+	body, err := protocol.Encode(&protocol.SaslHandshakeRequest{Version: 1, Mechanism: auth.ScramSha256})
+	if err != nil {
+		t.Fatalf("error encoding body: %v", err)
+	}
+	writeTestRequest(t, clientConn, protocol.RequestHeader{Version: 1, RequestApiKey: (&protocol.SaslHandshakeRequest{}).GetKey(), RequestApiVersion: 1, CorrelationID: 1}, body)
+	if _, err := protocol.ReadFrame(clientConn, 1<<20); err != nil {
+		t.Fatalf("error reading handshake response: %v", err)
+	}
+
+	body, err = protocol.Encode(&protocol.SaslAuthenticateRequest{Version: 1, AuthBytes: []byte("n,,n=bob,r=abc")})
+	if err != nil {
+		t.Fatalf("error encoding body: %v", err)
+	}
+	writeTestRequest(t, clientConn, protocol.RequestHeader{Version: 1, RequestApiKey: (&protocol.SaslAuthenticateRequest{}).GetKey(), RequestApiVersion: 1, CorrelationID: 2}, body)
+
+	// the client gets the reason before the connection is closed
+	payload, err := protocol.ReadFrame(clientConn, 1<<20)
+	if err != nil {
+		t.Fatalf("error reading authenticate response: %v", err)
+	}
+	resp := protocol.SaslAuthenticateResponse{}
+	if _, err := protocol.VersionedDecode(payload[4:], &resp, 1); err != nil {
+		t.Fatalf("error decoding response: %v", err)
+	}
+	if resp.ErrorCode != int16(utils.ErrSASLAuthenticationFailed) {
+		t.Errorf("error code = %d, want %d", resp.ErrorCode, utils.ErrSASLAuthenticationFailed)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the connection was not closed after a failed authentication")
+	}
+}
+
+func TestNewServer_OAuthBearerValidator(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr bool
+	}{
+		{name: "without validator", wantErr: true},
+		{name: "JWKS endpoint", env: map[string]string{"OT_SASL_OAUTHBEARER_JWKS_ENDPOINT_URL": "https://idp.example.com/jwks"}},
+		{name: "unsecured", env: map[string]string{"OT_SASL_OAUTHBEARER_UNSECURED": "true"}},
+		{name: "both", env: map[string]string{"OT_SASL_OAUTHBEARER_JWKS_ENDPOINT_URL": "https://idp.example.com/jwks", "OT_SASL_OAUTHBEARER_UNSECURED": "true"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OT_LISTENERS", "CLIENT://localhost:9092")
+			t.Setenv("OT_LISTENER_SECURITY_PROTOCOL_MAP", "CLIENT:SASL_PLAINTEXT")
+			t.Setenv("OT_SASL_ENABLED_MECHANISMS", "OAUTHBEARER")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			conf, err := config.NewConfig("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := NewServer(conf); (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_handleRequest_WrongListener(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		rejected int16
+	}{
+		{
+			name: "client API on the controller listener",
+			env: map[string]string{
+				"OT_LISTENERS":                      "CONTROLLER://localhost:9093",
+				"OT_LISTENER_SECURITY_PROTOCOL_MAP": "CONTROLLER:PLAINTEXT",
+				"OT_PROCESS_ROLES":                  "controller",
+				"OT_CONTROLLER_LISTENER_NAMES":      "CONTROLLER",
+			},
+			rejected: (&protocol.MetadataRequest{}).GetKey(),
+		},
+		{
+			name: "controller API on the broker listener",
+			env: map[string]string{
+				"OT_LISTENERS":                 "PLAINTEXT://localhost:9092",
+				"OT_PROCESS_ROLES":             "broker",
+				"OT_CONTROLLER_LISTENER_NAMES": "CONTROLLER",
+			},
+			rejected: (&protocol.VoteRequest{}).GetKey(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			conf, err := config.NewConfig("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			server, err := NewServer(conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			serverConn, clientConn := net.Pipe()
+			defer clientConn.Close()
+
+			done := make(chan struct{})
+			go func() {
+				server.newClient(serverConn).handleRequest()
+				close(done)
+			}()
+
+			// the API versions are served on every listener
+			header := protocol.RequestHeader{Version: 1, RequestApiKey: (&protocol.ApiVersionsRequest{}).GetKey(), RequestApiVersion: 0, CorrelationID: 1}
+			writeTestRequest(t, clientConn, header, nil)
+			if _, resp := readTestApiVersionsResponse(t, clientConn); resp.ErrorCode != int16(utils.ErrNoError) {
+				t.Errorf("error code = %d, want %d", resp.ErrorCode, utils.ErrNoError)
+			}
+
+			header = protocol.RequestHeader{Version: 1, RequestApiKey: tt.rejected, RequestApiVersion: 0, CorrelationID: 2}
+			writeTestRequest(t, clientConn, header, nil)
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatalf("the connection was not closed after a request of API %d", tt.rejected)
+			}
+		})
+	}
+}
+
+func TestClient_handleRequest_ListenerAPIVersions(t *testing.T) {
+	voteKey, beginQuorumEpochKey := (&protocol.VoteRequest{}).GetKey(), (&protocol.BeginQuorumEpochRequest{}).GetKey()
+	metadataKey := (&protocol.MetadataRequest{}).GetKey()
+
+	tests := []struct {
+		name       string
+		env        map[string]string
+		advertised []int16
+		absent     []int16
+	}{
+		{
+			name: "client listener",
+			env: map[string]string{
+				"OT_LISTENERS":                 "PLAINTEXT://localhost:9092",
+				"OT_PROCESS_ROLES":             "broker",
+				"OT_CONTROLLER_LISTENER_NAMES": "CONTROLLER",
+			},
+			advertised: []int16{metadataKey},
+			absent:     []int16{voteKey, beginQuorumEpochKey},
+		},
+		{
+			name: "controller listener",
+			env: map[string]string{
+				"OT_LISTENERS":                      "CONTROLLER://localhost:9093",
+				"OT_LISTENER_SECURITY_PROTOCOL_MAP": "CONTROLLER:PLAINTEXT",
+				"OT_PROCESS_ROLES":                  "controller",
+				"OT_CONTROLLER_LISTENER_NAMES":      "CONTROLLER",
+			},
+			advertised: []int16{voteKey},
+			absent:     []int16{metadataKey},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			conf, err := config.NewConfig("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			server, err := NewServer(conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			serverConn, clientConn := net.Pipe()
+			defer clientConn.Close()
+
+			go server.newClient(serverConn).handleRequest()
+
+			header := protocol.RequestHeader{Version: 1, RequestApiKey: (&protocol.ApiVersionsRequest{}).GetKey(), RequestApiVersion: 0, CorrelationID: 1}
+			writeTestRequest(t, clientConn, header, nil)
+			_, resp := readTestApiVersionsResponse(t, clientConn)
+
+			advertised := func(apiKey int16) bool {
+				return slices.ContainsFunc(resp.ApiKeys, func(v protocol.ApiVersion) bool { return v.ApiKey == apiKey })
+			}
+			for _, apiKey := range tt.advertised {
+				if !advertised(apiKey) {
+					t.Errorf("API %d is not advertised, want it served on the listener", apiKey)
+				}
+			}
+			for _, apiKey := range tt.absent {
+				if advertised(apiKey) {
+					t.Errorf("API %d is advertised, but the listener doesn't serve it", apiKey)
+				}
+			}
+			if !advertised((&protocol.ApiVersionsRequest{}).GetKey()) {
+				t.Error("ApiVersions is not advertised, want it served on every listener")
+			}
+		})
+	}
+}
+
+func TestClient_handleRequest_Throttled(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:9092")
+	t.Setenv("OT_QUOTA_REQUESTS_PER_SECOND", "10")
+	t.Setenv("OT_QUOTA_REQUESTS_BURST", "2")
+
+	conf, err := config.NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+	go server.newClient(serverConn).handleRequest()
+
+	// the burst is answered right away, the requests after it are delayed by their throttle time
+	clientID := "test-client"
+	var throttleTimes []int32
+	for i := int32(0); i < 4; i++ {
+		body, err := protocol.Encode(&protocol.ApiVersionsRequest{Version: 1})
+		if err != nil {
+			t.Fatalf("error encoding body: %v", err)
+		}
+		header := protocol.RequestHeader{Version: 1, RequestApiKey: (&protocol.ApiVersionsRequest{}).GetKey(), RequestApiVersion: 1, CorrelationID: i, ClientID: &clientID}
+
+		start := time.Now()
+		writeTestRequest(t, clientConn, header, body)
+		_, resp := readTestVersionedApiVersionsResponse(t, clientConn, 1)
+		if elapsed := time.Since(start); elapsed < time.Duration(resp.ThrottleTimeMs)*time.Millisecond {
+			t.Errorf("response %d after %v, want it delayed by the throttle time of %d ms", i, elapsed, resp.ThrottleTimeMs)
+		}
+		throttleTimes = append(throttleTimes, resp.ThrottleTimeMs)
+	}
+
+	if throttleTimes[0] != 0 || throttleTimes[1] != 0 {
+		t.Errorf("throttle times of the burst = %v, want 0", throttleTimes[:2])
+	}
+	if throttleTimes[2] == 0 || throttleTimes[3] == 0 {
+		t.Errorf("throttle times after the burst = %v, want them throttled", throttleTimes[2:])
+	}
+}
+
+func TestClient_handleRequest_IdleTimeout(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:9092")
+	t.Setenv("OT_CONNECTIONS_MAX_IDLE_MS", "100")
+
+	conf, err := config.NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+
+	done := make(chan struct{})
+	go func() {
+		server.newClient(serverConn).handleRequest()
+		close(done)
+	}()
+
+	// every request restarts the idle timer
+	clientID := "test-client"
+	for i := int32(0); i < 3; i++ {
+		time.Sleep(60 * time.Millisecond)
+		writeTestRequest(t, clientConn, protocol.RequestHeader{Version: 2, RequestApiKey: (&protocol.ApiVersionsRequest{}).GetKey(), CorrelationID: i, ClientID: &clientID}, nil)
+		if correlationID, _ := readTestApiVersionsResponse(t, clientConn); correlationID != i {
+			t.Fatalf("correlation ID = %d, want %d", correlationID, i)
+		}
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the idle connection was not closed")
+	}
+}
+
+func TestClient_handleRequest_FetchCancelledOnDisconnect(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:9092")
+
+	conf, err := config.NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.topics.CreateTopic("test-topic", 1, 1, false); err != nil {
+		t.Fatalf("error creating topic: %v", err)
+	}
+
+	serverConn, clientConn := net.Pipe()
+
+	done := make(chan struct{})
+	go func() {
+		server.newClient(serverConn).handleRequest()
+		close(done)
+	}()
+
+	// the topic is empty, so the fetch waits for records up to a minute
+	clientID := "test-client"
+	header := protocol.RequestHeader{Version: 2, RequestApiKey: (&protocol.FetchRequest{}).GetKey(), RequestApiVersion: 12, CorrelationID: 1, ClientID: &clientID}
+	body, err := protocol.Encode(&protocol.FetchRequest{
+		Version:   12,
+		ReplicaID: -1,
+		MaxWaitMs: 60000,
+		MinBytes:  1,
+		MaxBytes:  1024 * 1024,
+		Topics: []protocol.FetchTopic_FetchRequest{{
+			Version:    12,
+			Topic:      "test-topic",
+			Partitions: []protocol.FetchPartition_FetchRequest{{Version: 12, PartitionMaxBytes: 1024}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("error encoding body: %v", err)
+	}
+	writeTestRequest(t, clientConn, header, body)
+
+	select {
+	case <-done:
+		t.Fatal("the connection was closed before the client disconnected")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	clientConn.Close()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the fetch was not aborted after the client disconnected")
+	}
+}
+
+func TestServer_serve_ConnectionLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+	}{
+		{name: "max.connections", env: "OT_MAX_CONNECTIONS"},
+		{name: "max.connections.per.ip", env: "OT_MAX_CONNECTIONS_PER_IP"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:0")
+			t.Setenv("OT_ADVERTISED_LISTENERS", "PLAINTEXT://localhost:0")
+			t.Setenv(tt.env, "2")
+
+			conf, err := config.NewConfig("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			server, err := NewServer(conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			listener, err := server.listen()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer listener.Close()
+			go server.serve(listener)
+
+			// open one connection more than the limit
+			var conns []net.Conn
+			for i := 0; i < 3; i++ {
+				conn, err := net.Dial("tcp", listener.Addr().String())
+				if err != nil {
+					t.Fatalf("error connecting: %v", err)
+				}
+				defer conn.Close()
+				conns = append(conns, conn)
+			}
+
+			for i, conn := range conns {
+				conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+				_, err := conn.Read(make([]byte, 1))
+
+				refused := err == io.EOF
+				if wantRefused := i == 2; refused != wantRefused {
+					t.Errorf("connection %d refused = %v, want %v (read error %v)", i, refused, wantRefused, err)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_listenInterface(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://if:lo:0")
+	t.Setenv("OT_ADVERTISED_LISTENERS", "PLAINTEXT://if:lo:0")
+
+	conf, err := config.NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := server.listen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	if got := listener.Addr().(*net.TCPAddr).IP.String(); got != "127.0.0.1" {
+		t.Errorf("listener bound to %s, want the address of the loopback interface", got)
+	}
+	if got := conf.Broker.AdvertisedListeners[0].Host; got != "127.0.0.1" {
+		t.Errorf("advertised listener host = %s, want 127.0.0.1", got)
+	}
+}
+
+func TestServer_wildcardListener(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://0.0.0.0:0")
+	t.Setenv("OT_ADVERTISED_LISTENERS", "PLAINTEXT://broker.example.com:0")
+
+	conf, err := config.NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := server.listen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.serve(listener)
+	defer server.Shutdown(context.Background())
+
+	// the listener accepts connections on every interface
+	port := listener.Addr().(*net.TCPAddr).Port
+	conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		t.Fatalf("error connecting: %v", err)
+	}
+	defer conn.Close()
+
+	header := protocol.RequestHeader{Version: 1, RequestApiKey: (&protocol.MetadataRequest{}).GetKey(), RequestApiVersion: 1, CorrelationID: 1}
+	body, err := protocol.Encode(&protocol.MetadataRequest{Version: 1})
+	if err != nil {
+		t.Fatalf("error encoding body: %v", err)
+	}
+	writeTestRequest(t, conn, header, body)
+
+	payload, err := protocol.ReadFrame(conn, 1<<20)
+	if err != nil {
+		t.Fatalf("error reading response: %v", err)
+	}
+	resp := protocol.MetadataResponse{}
+	if _, err := protocol.VersionedDecode(payload[4:], &resp, 1); err != nil {
+		t.Fatalf("error decoding response: %v", err)
+	}
+
+	// clients are sent the advertised host, with the port the wildcard listener is bound to
+	if len(resp.Brokers) != 1 || resp.Brokers[0].Host != "broker.example.com" || resp.Brokers[0].Port != int32(port) {
+		t.Errorf("brokers = %+v, want broker.example.com:%d", resp.Brokers, port)
+	}
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:0")
+	t.Setenv("OT_ADVERTISED_LISTENERS", "PLAINTEXT://localhost:0")
+
+	conf, err := config.NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := server.listen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.serve(listener)
+
+	// a member which never rejoins keeps the next join waiting for the rebalance timeout
+	join := coordinator.JoinRequest{
+		ClientID:         "test-client",
+		ProtocolType:     "consumer",
+		Protocols:        []coordinator.Protocol{{Name: "range"}},
+		SessionTimeout:   time.Minute,
+		RebalanceTimeout: 300 * time.Millisecond,
+	}
+	first := server.groups.JoinGroup("test-group", join)
+	server.groups.SyncGroup("test-group", first.GenerationID, first.MemberID, nil)
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("error connecting: %v", err)
+	}
+	defer conn.Close()
+
+	clientID := "test-client"
+	header := protocol.RequestHeader{Version: 1, RequestApiKey: (&protocol.JoinGroupRequest{}).GetKey(), RequestApiVersion: 5, CorrelationID: 3, ClientID: &clientID}
+	body, err := protocol.Encode(&protocol.JoinGroupRequest{
+		Version:            5,
+		GroupID:            "test-group",
+		SessionTimeoutMs:   60000,
+		RebalanceTimeoutMs: 300,
+		ProtocolType:       "consumer",
+		Protocols:          []protocol.JoinGroupRequestProtocol{{Version: 5, Name: "range"}},
+	})
+	if err != nil {
+		t.Fatalf("error encoding body: %v", err)
+	}
+	writeTestRequest(t, conn, header, body)
+
+	group, _ := server.groups.Group("test-group")
+	for group.State() != coordinator.PreparingRebalance {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	shutdown := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		shutdown <- server.Shutdown(ctx)
+	}()
+
+	// wait for the listener to be closed, while the join is still in flight
+	for {
+		newConn, err := net.Dial("tcp", listener.Addr().String())
+		if err != nil {
+			break
+		}
+		newConn.Close()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	payload, err := protocol.ReadFrame(conn, 1<<20)
+	if err != nil {
+		t.Fatalf("error reading response: %v", err)
+	}
+	resp := protocol.JoinGroupResponse{}
+	if _, err := protocol.VersionedDecode(payload[4:], &resp, 5); err != nil {
+		t.Fatalf("error decoding response: %v", err)
+	}
+	if resp.ErrorCode != int16(utils.ErrNoError) || resp.GenerationID != 2 {
+		t.Errorf("join completed with error code %d at generation %d, want no error at generation 2", resp.ErrorCode, resp.GenerationID)
+	}
+
+	if err := <-shutdown; err != nil {
+		t.Errorf("shutdown: %v", err)
+	}
+
+	// the connection is closed once the request was answered
+	if _, err := conn.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("read after shutdown = %v, want EOF", err)
+	}
+}
+
+func TestServer_configureSocket(t *testing.T) {
+	t.Setenv("OT_LISTENERS", "PLAINTEXT://localhost:0")
+	t.Setenv("OT_ADVERTISED_LISTENERS", "PLAINTEXT://localhost:0")
+	t.Setenv("OT_SOCKET_RECEIVE_BUFFER_BYTES", "8192")
+	t.Setenv("OT_SOCKET_KEEPALIVE_INTERVAL_MS", "30000")
+
+	conf, err := config.NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := server.listen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	clientConn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("error connecting: %v", err)
+	}
+	defer clientConn.Close()
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := server.configureSocket(conn); err != nil {
+		t.Errorf("error configuring the socket: %v", err)
+	}
+	client := server.newClient(conn)
+	if got := client.reader.Size(); got != 8192 {
+		t.Errorf("read buffer size = %d, want 8192", got)
+	}
+
+	// the socket still carries requests with the sized buffers
+	clientID := "test-client"
+	writeTestRequest(t, clientConn, protocol.RequestHeader{Version: 1, RequestApiKey: (&protocol.ApiVersionsRequest{}).GetKey(), CorrelationID: 7, ClientID: &clientID}, nil)
+	frame, err := protocol.ReadFrame(client.reader, 1024)
+	if err != nil || len(frame) == 0 {
+		t.Errorf("ReadFrame() = %d bytes, %v, want the request", len(frame), err)
+	}
+}
+
+func TestClient_makeRequest_DecodeErrorDump(t *testing.T) {
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: logger.LevelTrace})))
+
+	conf, err := config.NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := NewServer(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+	client := server.newClient(serverConn)
+
+	tests := []struct {
+		name     string
+		body     protocol.Request
+		wantDump bool
+	}{
+		{name: "credentials", body: &protocol.SaslAuthenticateRequest{Version: 0, AuthBytes: []byte("n,,\x01auth=Bearer secret-token\x01\x01")}},
+		{name: "other API", body: &protocol.DescribeGroupsRequest{Version: 0, Groups: []string{"secret-token"}}, wantDump: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs.Reset()
+
+			header := protocol.RequestHeader{Version: 1, RequestApiKey: tt.body.GetKey(), RequestApiVersion: tt.body.GetVersion(), CorrelationID: 1}
+			msg, err := protocol.Encode(&header)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, err := protocol.Encode(tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the truncated body fails to decode, but still contains the secret
+			msg = append(msg, body[:len(body)-2]...)
+
+			req, err := client.makeRequest(context.Background(), msg, header.RequestApiKey, header.RequestApiVersion)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if req.DecodeError == nil {
+				t.Fatal("truncated body decoded without error")
+			}
+			if dumped := strings.Contains(logs.String(), "secret"); dumped != tt.wantDump {
+				t.Errorf("body dumped = %v, want %v, logs: %s", dumped, tt.wantDump, logs.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TopicPartition identifies a single partition of a topic.
+type TopicPartition struct {
+	Topic     string
+	Partition int32
+}
+
+// dirName returns the name of the directory of the partition in its log dir, like Kafka's test-topic-0.
+func (tp TopicPartition) dirName() string {
+	return fmt.Sprintf("%s-%d", tp.Topic, tp.Partition)
+}
+
+// parseTopicPartition parses the name of a partition directory. Topic names may contain dashes,
+// the partition follows the last one.
+func parseTopicPartition(name string) (TopicPartition, bool) {
+	i := strings.LastIndexByte(name, '-')
+	if i <= 0 {
+		return TopicPartition{}, false
+	}
+	partition, err := strconv.ParseInt(name[i+1:], 10, 32)
+	if err != nil || partition < 0 {
+		return TopicPartition{}, false
+	}
+
+	return TopicPartition{Topic: name[:i], Partition: int32(partition)}, true
+}
+
+// DiskOptions are the settings of partition logs on disk.
+type DiskOptions struct {
+	// Dirs are the log dirs, each partition is placed in the log dir with the fewest partitions
+	Dirs []string
+	// SegmentBytes is the size of the segment files, above which a new segment is rolled
+	SegmentBytes int64
+	// IndexIntervalBytes is the number of bytes of batches between the entries of the offset index of a segment
+	IndexIntervalBytes int64
+	// FlushInterval is the time between the flushes of the segments to the disk, 0 leaves the flushes to the OS
+	FlushInterval time.Duration
+	// Retention are the limits of the partition logs, which are checked every RetentionCheckInterval, 0 never checks them
+	Retention              Retention
+	RetentionCheckInterval time.Duration
+}
+
+// LogManager holds the partition logs of the broker.
+type LogManager struct {
+	mu         sync.RWMutex
+	partitions map[TopicPartition]*PartitionLog
+	// disk are the settings of logs on disk, it is nil if the logs are kept in memory
+	disk *DiskOptions
+	// stop ends the periodic flushes and retention checks, wg waits for them to end
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewLogManager returns a LogManager without any partition logs, which keeps the logs in memory.
+func NewLogManager() *LogManager {
+	return &LogManager{
+		partitions: map[TopicPartition]*PartitionLog{},
+	}
+}
+
+// OpenLogManager returns a LogManager which keeps the logs in segment files in the log dirs.
+// The partition logs found in the log dirs are recovered. Until Close, the segments are flushed every FlushInterval,
+// and the segments beyond the retention are deleted every RetentionCheckInterval.
+func OpenLogManager(opts DiskOptions) (*LogManager, error) {
+	m := &LogManager{
+		partitions: map[TopicPartition]*PartitionLog{},
+		disk:       &opts,
+		stop:       make(chan struct{}),
+	}
+
+	for _, logDir := range opts.Dirs {
+		if err := os.MkdirAll(logDir, 0o755); err != nil {
+			m.Close()
+			return nil, fmt.Errorf("error creating log dir %s: %w", logDir, err)
+		}
+		dirs, err := os.ReadDir(logDir)
+		if err != nil {
+			m.Close()
+			return nil, fmt.Errorf("error reading log dir %s: %w", logDir, err)
+		}
+
+		for _, dir := range dirs {
+			tp, ok := parseTopicPartition(dir.Name())
+			if !dir.IsDir() || !ok {
+				continue
+			}
+			if _, exists := m.partitions[tp]; exists {
+				m.Close()
+				return nil, fmt.Errorf("partition %s is in more than one log dir", dir.Name())
+			}
+
+			log, err := OpenPartitionLog(logDir, dir.Name(), opts.SegmentBytes, opts.IndexIntervalBytes)
+			if err != nil {
+				m.Close()
+				return nil, err
+			}
+			m.partitions[tp] = log
+			slog.Debug("recovered partition log", "topic", tp.Topic, "partition", tp.Partition, "logDir", logDir, "logEndOffset", log.LogEndOffset())
+		}
+	}
+
+	if opts.FlushInterval > 0 {
+		m.runPeriodically(opts.FlushInterval, func() {
+			if err := m.Flush(); err != nil {
+				slog.Error("error flushing the partition logs", "err", err)
+			}
+		})
+	}
+	if opts.RetentionCheckInterval > 0 {
+		m.runPeriodically(opts.RetentionCheckInterval, func() {
+			if err := m.ApplyRetention(time.Now()); err != nil {
+				slog.Error("error deleting the segments beyond the retention", "err", err)
+			}
+		})
+	}
+
+	return m, nil
+}
+
+// GetPartition returns the log of the given partition, if it exists.
+func (m *LogManager) GetPartition(topic string, partition int32) (*PartitionLog, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	log, ok := m.partitions[TopicPartition{Topic: topic, Partition: partition}]
+	return log, ok
+}
+
+// GetOrCreatePartition returns the log of the given partition, creating an empty one on first use.
+// Creating a log on disk fails if its directory can't be created.
+func (m *LogManager) GetOrCreatePartition(topic string, partition int32) (*PartitionLog, error) {
+	tp := TopicPartition{Topic: topic, Partition: partition}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	log, ok := m.partitions[tp]
+	if ok {
+		return log, nil
+	}
+
+	if m.disk == nil {
+		log = NewPartitionLog()
+	} else {
+		var err error
+		if log, err = OpenPartitionLog(m.nextLogDir(), tp.dirName(), m.disk.SegmentBytes, m.disk.IndexIntervalBytes); err != nil {
+			return nil, err
+		}
+	}
+	m.partitions[tp] = log
+
+	return log, nil
+}
+
+// nextLogDir returns the log dir with the fewest partitions, the first one of them on a tie.
+// The caller must hold the lock of the manager.
+func (m *LogManager) nextLogDir() string {
+	counts := map[string]int{}
+	for _, log := range m.partitions {
+		counts[log.LogDir()]++
+	}
+
+	next := m.disk.Dirs[0]
+	for _, dir := range m.disk.Dirs[1:] {
+		if counts[dir] < counts[next] {
+			next = dir
+		}
+	}
+
+	return next
+}
+
+// DeleteTopic drops the logs of all partitions of the topic, with their directories for logs on disk.
+// Fetches waiting for records of the topic are woken up, so they answer with an unknown topic error right away.
+func (m *LogManager) DeleteTopic(topic string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for tp, log := range m.partitions {
+		if tp.Topic == topic {
+			delete(m.partitions, tp)
+			log.notify()
+
+			if err := log.Close(); err != nil {
+				slog.Warn("error closing the log of a deleted partition", "topic", tp.Topic, "partition", tp.Partition, "err", err)
+			}
+			if log.LogDir() != "" {
+				if err := os.RemoveAll(filepath.Join(log.LogDir(), tp.dirName())); err != nil {
+					slog.Warn("error removing the log of a deleted partition", "topic", tp.Topic, "partition", tp.Partition, "err", err)
+				}
+			}
+		}
+	}
+}
+
+// Flush writes the segments of all partition logs to the disk.
+func (m *LogManager) Flush() error {
+	m.mu.RLock()
+	logs := make([]*PartitionLog, 0, len(m.partitions))
+	for _, log := range m.partitions {
+		logs = append(logs, log)
+	}
+	m.mu.RUnlock()
+
+	var errs []error
+	for _, log := range logs {
+		errs = append(errs, log.Flush())
+	}
+
+	return errors.Join(errs...)
+}
+
+// ApplyRetention deletes the segments of the partition logs beyond the retention.
+func (m *LogManager) ApplyRetention(now time.Time) error {
+	if m.disk == nil {
+		return nil
+	}
+
+	m.mu.RLock()
+	logs := make(map[TopicPartition]*PartitionLog, len(m.partitions))
+	for tp, log := range m.partitions {
+		logs[tp] = log
+	}
+	m.mu.RUnlock()
+
+	var errs []error
+	for tp, log := range logs {
+		deleted, err := log.ApplyRetention(m.disk.Retention, now)
+		if deleted > 0 {
+			slog.Info("deleted segments beyond the retention", "topic", tp.Topic, "partition", tp.Partition, "segments", deleted, "logStartOffset", log.LogStartOffset())
+		}
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// runPeriodically runs f every interval in the background, until Close.
+func (m *LogManager) runPeriodically(interval time.Duration, f func()) {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-m.stop:
+				return
+			case <-ticker.C:
+				f()
+			}
+		}
+	}()
+}
+
+// Close stops the periodic flushes and retention checks, then flushes and closes the partition logs.
+func (m *LogManager) Close() error {
+	if m.stop != nil {
+		close(m.stop)
+		m.wg.Wait()
+		m.stop = nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var errs []error
+	for _, log := range m.partitions {
+		errs = append(errs, log.Close())
+	}
+
+	return errors.Join(errs...)
+}
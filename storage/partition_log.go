@@ -0,0 +1,337 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"opentalaria/protocol"
+	"opentalaria/utils"
+)
+
+// PartitionLog is an append-only log of record batches for a single topic partition, kept in memory or
+// in segment files on disk. It is safe for concurrent use, since several producers can write to the same partition.
+type PartitionLog struct {
+	mu sync.RWMutex
+	// entries are the batches of logs kept in memory
+	entries        []batchEntry
+	logStartOffset int64
+	logEndOffset   int64
+	// size is the number of bytes of the batches in the log
+	size int64
+	// appended is closed by the next Append, to wake up fetches waiting for records
+	appended chan struct{}
+	// segments are the files of the log on disk, they are nil for logs kept in memory
+	segments *segmentedLog
+	// logDir is the log dir of the partition directory of logs on disk
+	logDir string
+}
+
+// batchEntry is a batch of a log kept in memory, with the offsets and timestamp needed to find it.
+type batchEntry struct {
+	baseOffset   int64
+	lastOffset   int64
+	maxTimestamp time.Time
+	size         int
+	batch        *protocol.RecordBatch
+}
+
+// NewPartitionLog returns an empty PartitionLog kept in memory.
+func NewPartitionLog() *PartitionLog {
+	return &PartitionLog{}
+}
+
+// OpenPartitionLog opens the log in the partition directory of the log dir, recovering its segments.
+// New segments are rolled once the active one would grow past segmentBytes, and the offsets of the segments
+// are indexed every indexIntervalBytes of batches.
+func OpenPartitionLog(logDir, dir string, segmentBytes, indexIntervalBytes int64) (*PartitionLog, error) {
+	segments, err := openSegmentedLog(filepath.Join(logDir, dir), segmentBytes, indexIntervalBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &PartitionLog{segments: segments, logDir: logDir}
+	l.logEndOffset = segments.nextOffset()
+	l.logStartOffset = l.logEndOffset
+	if len(segments.segments) > 0 {
+		l.logStartOffset = segments.segments[0].baseOffset
+	}
+	for _, s := range segments.segments {
+		l.size += s.size
+	}
+
+	return l, nil
+}
+
+// Append assigns offsets to the batch, starting at the log end offset, and adds it to the log.
+// It returns the base offset assigned to the batch.
+func (l *PartitionLog) Append(batch protocol.RecordBatch) (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	batch.BaseOffset = l.logEndOffset
+
+	if l.segments != nil {
+		size, err := l.segments.append(&batch)
+		if err != nil {
+			return 0, err
+		}
+		l.size += size
+	} else {
+		l.entries = append(l.entries, batchEntry{
+			baseOffset:   batch.BaseOffset,
+			lastOffset:   batch.LastOffset(),
+			maxTimestamp: batch.MaxTimestamp,
+			size:         batch.Size(),
+			batch:        &batch,
+		})
+		l.size += int64(batch.Size())
+	}
+	l.logEndOffset = batch.LastOffset() + 1
+	l.notifyLocked()
+
+	return batch.BaseOffset, nil
+}
+
+// Flush writes the segments appended to since the last flush to the disk. It does nothing for logs kept in memory.
+func (l *PartitionLog) Flush() error {
+	if l.segments == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	dirty := l.segments.dirtySegments()
+	l.mu.Unlock()
+
+	var errs []error
+	for _, s := range dirty {
+		errs = append(errs, s.sync())
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close flushes and closes the segments of the log, later appends and reads fail.
+// It does nothing for logs kept in memory.
+func (l *PartitionLog) Close() error {
+	if l.segments == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.segments.close()
+}
+
+// Retention are the limits of a partition log on disk, beyond which its oldest segments are deleted.
+type Retention struct {
+	// Time is how long a segment is kept after its newest record, a negative Time keeps it regardless of its age
+	Time time.Duration
+	// Bytes is the size of the log which its segments are deleted down to, a negative Bytes keeps them regardless of the size
+	Bytes int64
+}
+
+// ApplyRetention deletes the oldest segments which are older than the retention time or which the log can do without
+// and stay within the retention bytes, and advances the log start offset past them. The active segment is never deleted.
+// It returns the number of deleted segments, and does nothing for logs kept in memory.
+func (l *PartitionLog) ApplyRetention(retention Retention, now time.Time) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.segments == nil {
+		return 0, nil
+	}
+
+	deleted := 0
+	var errs []error
+	for len(l.segments.segments) > 1 {
+		s := l.segments.segments[0]
+
+		expired := false
+		if retention.Time >= 0 {
+			retentionTime, err := s.retentionTime()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("error reading the time of segment %d of %s: %w", s.baseOffset, l.segments.dir, err))
+				break
+			}
+			expired = now.Sub(retentionTime) > retention.Time
+		}
+		oversized := retention.Bytes >= 0 && l.size-s.size >= retention.Bytes
+		if !expired && !oversized {
+			break
+		}
+
+		if err := s.remove(); err != nil {
+			errs = append(errs, fmt.Errorf("error deleting segment %d of %s: %w", s.baseOffset, l.segments.dir, err))
+		}
+		l.segments.segments = l.segments.segments[1:]
+		l.size -= s.size
+		deleted++
+	}
+
+	if deleted > 0 {
+		l.logStartOffset = l.segments.segments[0].baseOffset
+	}
+
+	return deleted, errors.Join(errs...)
+}
+
+// LogDir returns the log dir of the partition, which is empty for logs kept in memory.
+func (l *PartitionLog) LogDir() string {
+	return l.logDir
+}
+
+// notify wakes up the fetches waiting for the log, without appending to it.
+func (l *PartitionLog) notify() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.notifyLocked()
+}
+
+// notifyLocked closes the channel returned by Appended. The caller must hold the lock of the log.
+func (l *PartitionLog) notifyLocked() {
+	if l.appended != nil {
+		close(l.appended)
+		l.appended = nil
+	}
+}
+
+// Appended returns a channel which is closed when the next batch is appended to the log.
+// Fetches get the channel before reading the log, so they don't miss a batch appended in between.
+func (l *PartitionLog) Appended() <-chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.appended == nil {
+		l.appended = make(chan struct{})
+	}
+
+	return l.appended
+}
+
+// Read returns the batches starting with the one that contains offset, up to maxBytes in total.
+// If minOneBatch is set, the first batch is returned even if it is larger than maxBytes, so consumers can make progress.
+// Reading at or past the log end offset returns no batches.
+func (l *PartitionLog) Read(offset int64, maxBytes int32, minOneBatch bool) ([]protocol.RecordBatch, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if offset < l.logStartOffset {
+		return nil, utils.ErrOffsetOutOfRange
+	}
+	if l.segments != nil {
+		return l.segments.read(offset, maxBytes, minOneBatch)
+	}
+
+	// batches are sorted by offset, find the first one which ends at or after the requested offset
+	start := sort.Search(len(l.entries), func(i int) bool {
+		return l.entries[i].lastOffset >= offset
+	})
+
+	batches := []protocol.RecordBatch{}
+	size := 0
+	for _, entry := range l.entries[start:] {
+		size += entry.size
+		if size > int(maxBytes) && !(minOneBatch && len(batches) == 0) {
+			break
+		}
+		batches = append(batches, *entry.batch)
+	}
+
+	return batches, nil
+}
+
+// OffsetForTimestamp returns the offset and timestamp of the first record with a timestamp greater than or equal to timestamp.
+// If no such record exists, ok is false.
+func (l *PartitionLog) OffsetForTimestamp(timestamp time.Time) (offset int64, recordTimestamp time.Time, ok bool, err error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.segments != nil {
+		return l.segments.offsetForTimestamp(timestamp)
+	}
+
+	for _, entry := range l.entries {
+		if entry.maxTimestamp.Before(timestamp) {
+			continue
+		}
+		if offset, recordTimestamp, ok := firstRecordAtOrAfter(entry.batch, timestamp); ok {
+			return offset, recordTimestamp, true, nil
+		}
+	}
+
+	return -1, time.Time{}, false, nil
+}
+
+// firstRecordAtOrAfter returns the offset and timestamp of the first record of the batch with a timestamp greater than
+// or equal to timestamp.
+func firstRecordAtOrAfter(batch *protocol.RecordBatch, timestamp time.Time) (int64, time.Time, bool) {
+	for i, record := range batch.Records {
+		if ts := batch.RecordTimestamp(i); !ts.Before(timestamp) {
+			return batch.BaseOffset + int64(record.OffsetDelta), ts, true
+		}
+	}
+
+	return -1, time.Time{}, false
+}
+
+// MaxTimestampOffset returns the offset and timestamp of the record with the largest timestamp in the log.
+// If the log is empty, ok is false.
+func (l *PartitionLog) MaxTimestampOffset() (offset int64, recordTimestamp time.Time, ok bool, err error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.segments != nil {
+		return l.segments.maxTimestampOffset()
+	}
+
+	offset = -1
+	for _, entry := range l.entries {
+		if batchOffset, ts, batchOk := maxTimestampRecord(entry.batch); batchOk && (!ok || ts.After(recordTimestamp)) {
+			offset, recordTimestamp, ok = batchOffset, ts, true
+		}
+	}
+
+	return offset, recordTimestamp, ok, nil
+}
+
+// maxTimestampRecord returns the offset and timestamp of the first record of the batch with its largest timestamp.
+func maxTimestampRecord(batch *protocol.RecordBatch) (offset int64, recordTimestamp time.Time, ok bool) {
+	offset = -1
+	for i, record := range batch.Records {
+		if ts := batch.RecordTimestamp(i); !ok || ts.After(recordTimestamp) {
+			offset, recordTimestamp, ok = batch.BaseOffset+int64(record.OffsetDelta), ts, true
+		}
+	}
+
+	return offset, recordTimestamp, ok
+}
+
+// LogStartOffset returns the offset of the first record in the log.
+func (l *PartitionLog) LogStartOffset() int64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.logStartOffset
+}
+
+// LogEndOffset returns the offset that will be assigned to the next appended record.
+func (l *PartitionLog) LogEndOffset() int64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.logEndOffset
+}
+
+// Size returns the number of bytes of the record batches in the log, as they were produced.
+func (l *PartitionLog) Size() int64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.size
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"opentalaria/protocol"
+	"testing"
+)
+
+func TestPartitionLog_Append(t *testing.T) {
+	log := NewPartitionLog()
+
+	tests := []struct {
+		lastOffsetDelta int32
+		wantBaseOffset  int64
+	}{
+		{lastOffsetDelta: 0, wantBaseOffset: 0},
+		{lastOffsetDelta: 4, wantBaseOffset: 1},
+		{lastOffsetDelta: 1, wantBaseOffset: 6},
+	}
+
+	for _, tt := range tests {
+		// producers send the batch with a base offset of 0, the log assigns the real one
+		baseOffset, err := log.Append(protocol.RecordBatch{LastOffsetDelta: tt.lastOffsetDelta})
+		if err != nil {
+			t.Fatalf("error appending batch: %v", err)
+		}
+		if baseOffset != tt.wantBaseOffset {
+			t.Errorf("base offset = %d, want %d", baseOffset, tt.wantBaseOffset)
+		}
+	}
+
+	if log.LogEndOffset() != 8 {
+		t.Errorf("log end offset = %d, want 8", log.LogEndOffset())
+	}
+}
+
+func TestLogManager_DeleteTopic(t *testing.T) {
+	logs := NewLogManager()
+	logs.GetOrCreatePartition("test-topic", 0)
+	logs.GetOrCreatePartition("test-topic", 1)
+	logs.GetOrCreatePartition("other-topic", 0)
+
+	logs.DeleteTopic("test-topic")
+
+	if _, ok := logs.GetPartition("test-topic", 1); ok {
+		t.Error("partition log of deleted topic still exists")
+	}
+	if _, ok := logs.GetPartition("other-topic", 0); !ok {
+		t.Error("partition log of other topic was deleted")
+	}
+}
+
+func TestPartitionLog_Appended(t *testing.T) {
+	log := NewPartitionLog()
+
+	appended := log.Appended()
+	select {
+	case <-appended:
+		t.Fatal("appended channel closed before an append")
+	default:
+	}
+
+	if _, err := log.Append(protocol.RecordBatch{}); err != nil {
+		t.Fatalf("error appending batch: %v", err)
+	}
+
+	select {
+	case <-appended:
+	default:
+		t.Fatal("appended channel not closed by the append")
+	}
+
+	// the next append gets a new channel
+	select {
+	case <-log.Appended():
+		t.Fatal("new appended channel is already closed")
+	default:
+	}
+}
+
+func TestPartitionLog_Size(t *testing.T) {
+	log := NewPartitionLog()
+	if log.Size() != 0 {
+		t.Errorf("size of the empty log = %d, want 0", log.Size())
+	}
+
+	batch := protocol.RecordBatch{BatchLength: 100, Records: make([]protocol.Record, 1)}
+	for i := 0; i < 2; i++ {
+		if _, err := log.Append(batch); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if want := int64(2 * batch.Size()); log.Size() != want {
+		t.Errorf("size = %d, want %d", log.Size(), want)
+	}
+}
@@ -0,0 +1,603 @@
+package storage
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"opentalaria/protocol"
+)
+
+const (
+	logFileSuffix   = ".log"
+	indexFileSuffix = ".index"
+
+	// batchHeaderSize is the size of the base offset and the batch length, which precede the rest of the batch
+	batchHeaderSize = 12
+	// batchInfoSize is the size of the fields of a batch up to its max timestamp, which locate and date the batch
+	// without decoding its records
+	batchInfoSize = 43
+	// indexEntrySize is the size of an index entry: the offset of the batch relative to the segment, its position
+	// in the log file and the max timestamp of the segment up to and including the batch
+	indexEntrySize = 16
+)
+
+// A segment is a file of the partition log with the batches from its base offset on, named after the base offset
+// like Kafka's segments, 00000000000000000000.log. The index file next to it is a sparse index of the offsets
+// in the log file, with an entry every indexIntervalBytes of batches. Reads seek to the last entry at or before
+// the offset they look for, and read the batch headers from there on.
+type segment struct {
+	baseOffset int64
+	log        *os.File
+	index      *os.File
+	// size is the number of bytes of the batches in the log file
+	size int64
+	// nextOffset is the offset after the last batch of the segment, the base offset if the segment is empty
+	nextOffset int64
+	// maxTimestamp is the largest max timestamp of the batches of the segment
+	maxTimestamp time.Time
+	// offsets are the entries of the index file, sorted by offset
+	offsets []indexEntry
+	// bytesSinceIndexEntry is the number of bytes of the batches since the last index entry
+	bytesSinceIndexEntry int64
+	// dirty is set when the segment was written since it was last flushed
+	dirty bool
+}
+
+// indexEntry locates a batch in the log file of its segment.
+type indexEntry struct {
+	offset   int64
+	position int64
+	// maxTimestamp is the largest max timestamp of the batches of the segment up to and including this one,
+	// so lookups by timestamp can skip the batches before the entry
+	maxTimestamp time.Time
+}
+
+// batchInfo are the fields of a batch read from its header.
+type batchInfo struct {
+	baseOffset   int64
+	lastOffset   int64
+	maxTimestamp time.Time
+	size         int64
+}
+
+// segmentedLog are the segments of a partition log in its directory.
+type segmentedLog struct {
+	dir                string
+	segmentBytes       int64
+	indexIntervalBytes int64
+	segments           []*segment
+	closed             bool
+}
+
+// segmentFileName returns the name of the file of the segment with the base offset.
+func segmentFileName(baseOffset int64, suffix string) string {
+	return fmt.Sprintf("%020d%s", baseOffset, suffix)
+}
+
+// openSegmentedLog opens the segments in the directory, creating it if it doesn't exist. A batch which was only partly
+// written, because the broker stopped in the middle of an append, is truncated with everything after it.
+func openSegmentedLog(dir string, segmentBytes, indexIntervalBytes int64) (*segmentedLog, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating partition log directory %s: %w", dir, err)
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading partition log directory %s: %w", dir, err)
+	}
+
+	var baseOffsets []int64
+	for _, f := range files {
+		name, ok := strings.CutSuffix(f.Name(), logFileSuffix)
+		if !ok {
+			continue
+		}
+		baseOffset, err := strconv.ParseInt(name, 10, 64)
+		if err != nil || baseOffset < 0 {
+			slog.Warn("ignoring file which is not a segment in the partition log directory", "dir", dir, "file", f.Name())
+			continue
+		}
+		baseOffsets = append(baseOffsets, baseOffset)
+	}
+	slices.Sort(baseOffsets)
+
+	l := &segmentedLog{dir: dir, segmentBytes: segmentBytes, indexIntervalBytes: indexIntervalBytes}
+	for _, baseOffset := range baseOffsets {
+		s, err := openSegment(dir, baseOffset, indexIntervalBytes)
+		if err != nil {
+			l.close()
+			return nil, err
+		}
+		l.segments = append(l.segments, s)
+	}
+
+	return l, nil
+}
+
+// append writes the batch to the active segment, rolling a new segment first if the batch would grow the active one
+// past segmentBytes. The batch must have its base offset assigned. It returns the number of bytes written.
+func (l *segmentedLog) append(batch *protocol.RecordBatch) (int64, error) {
+	if l.closed {
+		return 0, fmt.Errorf("error appending to partition log %s: %w", l.dir, os.ErrClosed)
+	}
+
+	data, err := protocol.Encode(batch)
+	if err != nil {
+		return 0, fmt.Errorf("error encoding record batch: %w", err)
+	}
+
+	active := l.active()
+	if active == nil || (active.size > 0 && active.size+int64(len(data)) > l.segmentBytes) {
+		if active, err = createSegment(l.dir, batch.BaseOffset, l.indexIntervalBytes); err != nil {
+			return 0, err
+		}
+		l.segments = append(l.segments, active)
+	}
+
+	position := active.size
+	if _, err := active.log.WriteAt(data, position); err != nil {
+		// drop what was written of the batch, so the next append doesn't leave a gap
+		active.log.Truncate(position)
+		return 0, fmt.Errorf("error writing to segment %s: %w", active.log.Name(), err)
+	}
+
+	info := batchInfo{baseOffset: batch.BaseOffset, lastOffset: batch.LastOffset(), maxTimestamp: batch.MaxTimestamp, size: int64(len(data))}
+	if entry, ok := active.add(info, position, l.indexIntervalBytes); ok {
+		if _, err := active.index.Write(encodeIndexEntry(active.baseOffset, entry)); err != nil {
+			// the recovery rebuilds the missing entries of the index from the log file
+			slog.Warn("error writing to segment index", "file", active.index.Name(), "err", err)
+		}
+	}
+	active.dirty = true
+
+	return info.size, nil
+}
+
+// active returns the segment the batches are appended to, or nil if the log has no segments yet.
+func (l *segmentedLog) active() *segment {
+	if len(l.segments) == 0 {
+		return nil
+	}
+
+	return l.segments[len(l.segments)-1]
+}
+
+// nextOffset returns the offset of the next batch.
+func (l *segmentedLog) nextOffset() int64 {
+	if active := l.active(); active != nil {
+		return active.nextOffset
+	}
+
+	return 0
+}
+
+// read returns the batches starting with the one that contains offset, up to maxBytes in total. If minOneBatch is set,
+// the first batch is returned even if it is larger than maxBytes.
+func (l *segmentedLog) read(offset int64, maxBytes int32, minOneBatch bool) ([]protocol.RecordBatch, error) {
+	if l.closed {
+		return nil, fmt.Errorf("error reading partition log %s: %w", l.dir, os.ErrClosed)
+	}
+
+	// the segment of the offset is the last one which starts at or before it
+	i := sort.Search(len(l.segments), func(i int) bool {
+		return l.segments[i].baseOffset > offset
+	})
+
+	batches := []protocol.RecordBatch{}
+	size := int64(0)
+	for _, s := range l.segments[max(i-1, 0):] {
+		done := false
+		err := s.scan(s.lookup(offset), func(info batchInfo, position int64) (bool, error) {
+			if info.lastOffset < offset {
+				return true, nil
+			}
+
+			size += info.size
+			if size > int64(maxBytes) && !(minOneBatch && len(batches) == 0) {
+				done = true
+				return false, nil
+			}
+			batch, err := s.read(position, info.size)
+			if err != nil {
+				return false, err
+			}
+			batches = append(batches, batch)
+
+			return true, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			break
+		}
+	}
+
+	return batches, nil
+}
+
+// offsetForTimestamp returns the offset and timestamp of the first record with a timestamp at or after timestamp.
+func (l *segmentedLog) offsetForTimestamp(timestamp time.Time) (offset int64, recordTimestamp time.Time, ok bool, err error) {
+	if l.closed {
+		return -1, time.Time{}, false, fmt.Errorf("error reading partition log %s: %w", l.dir, os.ErrClosed)
+	}
+
+	for _, s := range l.segments {
+		if s.maxTimestamp.Before(timestamp) {
+			continue
+		}
+
+		// the batches up to the last index entry before the timestamp are all older than it
+		i := sort.Search(len(s.offsets), func(i int) bool {
+			return !s.offsets[i].maxTimestamp.Before(timestamp)
+		})
+		position := int64(0)
+		if i > 0 {
+			position = s.offsets[i-1].position
+		}
+
+		err = s.scan(position, func(info batchInfo, position int64) (bool, error) {
+			if info.maxTimestamp.Before(timestamp) {
+				return true, nil
+			}
+			batch, err := s.read(position, info.size)
+			if err != nil {
+				return false, err
+			}
+			offset, recordTimestamp, ok = firstRecordAtOrAfter(&batch, timestamp)
+
+			return !ok, nil
+		})
+		if err != nil || ok {
+			return offset, recordTimestamp, ok, err
+		}
+	}
+
+	return -1, time.Time{}, false, nil
+}
+
+// maxTimestampOffset returns the offset and timestamp of the first record with the largest timestamp of the log.
+func (l *segmentedLog) maxTimestampOffset() (offset int64, recordTimestamp time.Time, ok bool, err error) {
+	if l.closed {
+		return -1, time.Time{}, false, fmt.Errorf("error reading partition log %s: %w", l.dir, os.ErrClosed)
+	}
+
+	var latest *segment
+	for _, s := range l.segments {
+		if s.size > 0 && (latest == nil || s.maxTimestamp.After(latest.maxTimestamp)) {
+			latest = s
+		}
+	}
+	if latest == nil {
+		return -1, time.Time{}, false, nil
+	}
+
+	err = latest.scan(0, func(info batchInfo, position int64) (bool, error) {
+		if info.maxTimestamp.Before(latest.maxTimestamp) {
+			return true, nil
+		}
+		batch, err := latest.read(position, info.size)
+		if err != nil {
+			return false, err
+		}
+		offset, recordTimestamp, ok = maxTimestampRecord(&batch)
+
+		return false, nil
+	})
+	if err != nil {
+		return -1, time.Time{}, false, err
+	}
+
+	return offset, recordTimestamp, ok, nil
+}
+
+// dirtySegments returns the segments written since they were last flushed, and marks them as flushed.
+func (l *segmentedLog) dirtySegments() []*segment {
+	var dirty []*segment
+	for _, s := range l.segments {
+		if s.dirty {
+			s.dirty = false
+			dirty = append(dirty, s)
+		}
+	}
+
+	return dirty
+}
+
+// close flushes and closes the files of the segments.
+func (l *segmentedLog) close() error {
+	var errs []error
+	for _, s := range l.segments {
+		errs = append(errs, s.sync(), s.log.Close(), s.index.Close())
+	}
+	l.segments = nil
+	l.closed = true
+
+	return errors.Join(errs...)
+}
+
+// add accounts for the batch appended at the position of the log file. It adds an index entry for the batch
+// if indexIntervalBytes of batches were appended since the last one, and returns the entry it added.
+func (s *segment) add(info batchInfo, position, indexIntervalBytes int64) (indexEntry, bool) {
+	if info.maxTimestamp.After(s.maxTimestamp) {
+		s.maxTimestamp = info.maxTimestamp
+	}
+	s.nextOffset = info.lastOffset + 1
+	s.size = position + info.size
+
+	indexed := len(s.offsets) > 0 && s.offsets[len(s.offsets)-1].offset >= info.baseOffset
+	if indexed || s.bytesSinceIndexEntry < indexIntervalBytes {
+		s.bytesSinceIndexEntry += info.size
+		return indexEntry{}, false
+	}
+
+	entry := indexEntry{offset: info.baseOffset, position: position, maxTimestamp: s.maxTimestamp}
+	s.offsets = append(s.offsets, entry)
+	s.bytesSinceIndexEntry = info.size
+
+	return entry, true
+}
+
+// lookup returns the position of the last indexed batch at or before the offset, the start of the log file
+// if there is none.
+func (s *segment) lookup(offset int64) int64 {
+	i := sort.Search(len(s.offsets), func(i int) bool {
+		return s.offsets[i].offset > offset
+	})
+	if i == 0 {
+		return 0
+	}
+
+	return s.offsets[i-1].position
+}
+
+// scan calls f with the header of each batch from the position of the log file on, until f returns false.
+func (s *segment) scan(position int64, f func(info batchInfo, position int64) (bool, error)) error {
+	for position < s.size {
+		info, err := s.readInfo(position, s.size)
+		if err != nil {
+			return err
+		}
+		next, err := f(info, position)
+		if err != nil || !next {
+			return err
+		}
+		position += info.size
+	}
+
+	return nil
+}
+
+// readInfo reads the header of the batch at the position of the log file, which must end within logSize.
+func (s *segment) readInfo(position, logSize int64) (batchInfo, error) {
+	if position+batchInfoSize > logSize {
+		return batchInfo{}, io.ErrUnexpectedEOF
+	}
+	header := make([]byte, batchInfoSize)
+	if _, err := s.log.ReadAt(header, position); err != nil {
+		return batchInfo{}, fmt.Errorf("error reading segment %s at position %d: %w", s.log.Name(), position, err)
+	}
+
+	info := batchInfo{
+		baseOffset:   int64(binary.BigEndian.Uint64(header[0:])),
+		size:         batchHeaderSize + int64(int32(binary.BigEndian.Uint32(header[8:]))),
+		maxTimestamp: timeFromMillis(int64(binary.BigEndian.Uint64(header[35:]))),
+	}
+	info.lastOffset = info.baseOffset + int64(int32(binary.BigEndian.Uint32(header[23:])))
+	if info.size < batchInfoSize || position+info.size > logSize {
+		return batchInfo{}, io.ErrUnexpectedEOF
+	}
+
+	return info, nil
+}
+
+// read returns the batch of size bytes at the position of the log file.
+func (s *segment) read(position, size int64) (protocol.RecordBatch, error) {
+	data := make([]byte, size)
+	if _, err := s.log.ReadAt(data, position); err != nil {
+		return protocol.RecordBatch{}, fmt.Errorf("error reading segment %s at position %d: %w", s.log.Name(), position, err)
+	}
+
+	var batch protocol.RecordBatch
+	if err := protocol.Decode(data, &batch); err != nil {
+		return protocol.RecordBatch{}, fmt.Errorf("error decoding record batch of segment %s at position %d: %w", s.log.Name(), position, err)
+	}
+
+	return batch, nil
+}
+
+// remove closes and deletes the files of the segment.
+func (s *segment) remove() error {
+	return errors.Join(s.log.Close(), s.index.Close(), os.Remove(s.log.Name()), os.Remove(s.index.Name()))
+}
+
+// retentionTime returns the time the retention time of the segment counts from, the max timestamp of its batches.
+// Like Kafka, it falls back to the modification time of the log file when no batch has a timestamp, which happens
+// with the -1 timestamps of old clients, instead of deleting the segment on the first check.
+func (s *segment) retentionTime() (time.Time, error) {
+	if !s.maxTimestamp.IsZero() {
+		return s.maxTimestamp, nil
+	}
+	info, err := s.log.Stat()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return info.ModTime(), nil
+}
+
+// sync flushes the log and index files of the segment to the disk.
+func (s *segment) sync() error {
+	return errors.Join(s.log.Sync(), s.index.Sync())
+}
+
+// createSegment creates the empty files of a new segment.
+func createSegment(dir string, baseOffset, indexIntervalBytes int64) (*segment, error) {
+	s, err := openSegment(dir, baseOffset, indexIntervalBytes)
+	if err != nil {
+		return nil, err
+	}
+	if s.size > 0 {
+		s.log.Close()
+		s.index.Close()
+		return nil, fmt.Errorf("segment %d of %s already exists", baseOffset, dir)
+	}
+
+	return s, nil
+}
+
+// openSegment opens or creates the files of the segment and recovers it.
+func openSegment(dir string, baseOffset, indexIntervalBytes int64) (*segment, error) {
+	logPath := filepath.Join(dir, segmentFileName(baseOffset, logFileSuffix))
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("error opening segment %s: %w", logPath, err)
+	}
+	indexPath := filepath.Join(dir, segmentFileName(baseOffset, indexFileSuffix))
+	indexFile, err := os.OpenFile(indexPath, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		logFile.Close()
+		return nil, fmt.Errorf("error opening segment index %s: %w", indexPath, err)
+	}
+
+	s := &segment{baseOffset: baseOffset, nextOffset: baseOffset, log: logFile, index: indexFile}
+	if err := s.recover(indexIntervalBytes); err != nil {
+		logFile.Close()
+		indexFile.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// recover loads the index of the segment and reads the batches of the log file after the last index entry,
+// which are at most indexIntervalBytes unless the index is incomplete. A partly written batch at the end of the log
+// file is truncated, and the index is rewritten if it was incomplete or pointed past the log file.
+func (s *segment) recover(indexIntervalBytes int64) error {
+	info, err := s.log.Stat()
+	if err != nil {
+		return fmt.Errorf("error reading the size of segment %s: %w", s.log.Name(), err)
+	}
+	logSize := info.Size()
+
+	indexData, err := io.ReadAll(s.index)
+	if err != nil {
+		return fmt.Errorf("error reading segment index %s: %w", s.index.Name(), err)
+	}
+
+	// the entries are trusted as long as they increase and are within the log file
+	var entries []indexEntry
+	for ; len(indexData) >= indexEntrySize; indexData = indexData[indexEntrySize:] {
+		entry := decodeIndexEntry(s.baseOffset, indexData[:indexEntrySize])
+		if n := len(entries); entry.position+batchInfoSize > logSize ||
+			(n > 0 && (entry.offset <= entries[n-1].offset || entry.position <= entries[n-1].position)) {
+			break
+		}
+		entries = append(entries, entry)
+	}
+
+	// the last entry must point at a complete batch, otherwise the whole log file is read
+	position := int64(0)
+	if n := len(entries); n > 0 {
+		last := entries[n-1]
+		batch, err := s.readInfo(last.position, logSize)
+		if err == nil && batch.baseOffset == last.offset {
+			_, err = s.read(last.position, batch.size)
+		}
+		if err == nil && batch.baseOffset == last.offset {
+			position = last.position
+			s.offsets = entries
+			s.maxTimestamp = last.maxTimestamp
+		}
+	}
+	indexed := len(s.offsets)
+
+	for position < logSize {
+		batch, err := s.readInfo(position, logSize)
+		if err == nil {
+			_, err = s.read(position, batch.size)
+		}
+		if err != nil {
+			slog.Warn("truncating the incomplete batch at the end of the segment", "file", s.log.Name(), "position", position, "size", logSize, "err", err)
+			if err := s.log.Truncate(position); err != nil {
+				return fmt.Errorf("error truncating segment %s: %w", s.log.Name(), err)
+			}
+			break
+		}
+		s.add(batch, position, indexIntervalBytes)
+		position += batch.size
+	}
+
+	if indexed != len(s.offsets) || indexed != len(entries) || len(indexData) > 0 {
+		if err := s.rewriteIndex(); err != nil {
+			return err
+		}
+	}
+	if _, err := s.index.Seek(0, io.SeekEnd); err != nil {
+		return fmt.Errorf("error seeking segment index %s: %w", s.index.Name(), err)
+	}
+
+	return nil
+}
+
+// rewriteIndex replaces the content of the index file with the entries of the segment.
+func (s *segment) rewriteIndex() error {
+	data := make([]byte, 0, len(s.offsets)*indexEntrySize)
+	for _, entry := range s.offsets {
+		data = append(data, encodeIndexEntry(s.baseOffset, entry)...)
+	}
+
+	if err := s.index.Truncate(0); err != nil {
+		return fmt.Errorf("error truncating segment index %s: %w", s.index.Name(), err)
+	}
+	if _, err := s.index.WriteAt(data, 0); err != nil {
+		return fmt.Errorf("error writing segment index %s: %w", s.index.Name(), err)
+	}
+
+	return nil
+}
+
+func encodeIndexEntry(segmentBaseOffset int64, entry indexEntry) []byte {
+	data := make([]byte, indexEntrySize)
+	binary.BigEndian.PutUint32(data[0:], uint32(entry.offset-segmentBaseOffset))
+	binary.BigEndian.PutUint32(data[4:], uint32(entry.position))
+	binary.BigEndian.PutUint64(data[8:], uint64(millisFromTime(entry.maxTimestamp)))
+
+	return data
+}
+
+func decodeIndexEntry(segmentBaseOffset int64, data []byte) indexEntry {
+	return indexEntry{
+		offset:       segmentBaseOffset + int64(binary.BigEndian.Uint32(data[0:])),
+		position:     int64(binary.BigEndian.Uint32(data[4:])),
+		maxTimestamp: timeFromMillis(int64(binary.BigEndian.Uint64(data[8:]))),
+	}
+}
+
+// millisFromTime returns the timestamp in milliseconds as stored in the batches, -1 for a missing timestamp.
+func millisFromTime(t time.Time) int64 {
+	if t.IsZero() {
+		return -1
+	}
+
+	return t.UnixMilli()
+}
+
+// timeFromMillis is the inverse of millisFromTime.
+func timeFromMillis(millis int64) time.Time {
+	if millis < 0 {
+		return time.Time{}
+	}
+
+	return time.UnixMilli(millis)
+}
@@ -0,0 +1,477 @@
+package storage
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"opentalaria/protocol"
+	"opentalaria/utils"
+)
+
+// newTestBatch returns a batch with a record per value, like the batches producers send.
+func newTestBatch(timestamp time.Time, values ...string) protocol.RecordBatch {
+	batch := protocol.RecordBatch{
+		BaseTimestamp:   timestamp,
+		MaxTimestamp:    timestamp,
+		ProducerId:      -1,
+		ProducerEpoch:   -1,
+		BaseSequence:    -1,
+		LastOffsetDelta: int32(len(values) - 1),
+	}
+	for i, v := range values {
+		batch.Records = append(batch.Records, protocol.Record{OffsetDelta: int32(i), Value: []byte(v)})
+	}
+
+	return batch
+}
+
+// readValues returns the values of the records of the log from the offset on.
+func readValues(t *testing.T, log *PartitionLog, offset int64) []string {
+	t.Helper()
+
+	batches, err := log.Read(offset, 1<<20, true)
+	if err != nil {
+		t.Fatalf("error reading the log: %v", err)
+	}
+
+	var values []string
+	for _, batch := range batches {
+		for _, r := range batch.Records {
+			values = append(values, string(r.Value))
+		}
+	}
+
+	return values
+}
+
+func assertValues(t *testing.T, got []string, want ...string) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("values = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("values = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestLogManager_AppendRecoverRead(t *testing.T) {
+	opts := DiskOptions{Dirs: []string{t.TempDir()}, SegmentBytes: 1 << 20}
+	timestamp := time.UnixMilli(1730473445000)
+
+	logs, err := OpenLogManager(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log, err := logs.GetOrCreatePartition("test-topic", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, values := range [][]string{{"a", "b"}, {"c"}, {"d", "e", "f"}} {
+		if _, err := log.Append(newTestBatch(timestamp, values...)); err != nil {
+			t.Fatal(err)
+		}
+		timestamp = timestamp.Add(time.Second)
+	}
+	size := log.Size()
+	if err := logs.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the broker restarts
+	logs, err = OpenLogManager(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logs.Close()
+
+	log, ok := logs.GetPartition("test-topic", 1)
+	if !ok {
+		t.Fatal("the partition log was not recovered")
+	}
+	if log.LogStartOffset() != 0 || log.LogEndOffset() != 6 || log.Size() != size {
+		t.Errorf("recovered log from %d to %d with %d bytes, want from 0 to 6 with %d bytes", log.LogStartOffset(), log.LogEndOffset(), log.Size(), size)
+	}
+	assertValues(t, readValues(t, log, 0), "a", "b", "c", "d", "e", "f")
+	assertValues(t, readValues(t, log, 3), "d", "e", "f")
+
+	offset, ts, ok, err := log.OffsetForTimestamp(time.UnixMilli(1730473446000))
+	if err != nil || !ok || offset != 2 || ts.UnixMilli() != 1730473446000 {
+		t.Errorf("offset for timestamp = %d at %v, ok = %v, err = %v, want offset 2", offset, ts, ok, err)
+	}
+
+	// appends continue after the recovered batches
+	baseOffset, err := log.Append(newTestBatch(timestamp, "g"))
+	if err != nil || baseOffset != 6 {
+		t.Fatalf("base offset = %d, err = %v, want 6", baseOffset, err)
+	}
+	// reads start with the batch of the offset
+	assertValues(t, readValues(t, log, 5), "d", "e", "f", "g")
+}
+
+func TestPartitionLog_SegmentRollover(t *testing.T) {
+	logDir := t.TempDir()
+	batch := newTestBatch(time.Now(), "value")
+	batchBytes, err := protocol.Encode(&batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// two batches fit in a segment
+	log, err := OpenPartitionLog(logDir, "test-topic-0", int64(2*len(batchBytes)), 4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := log.Append(batch); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := log.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, baseOffset := range []int64{0, 2, 4} {
+		for _, suffix := range []string{logFileSuffix, indexFileSuffix} {
+			if _, err := os.Stat(filepath.Join(logDir, "test-topic-0", segmentFileName(baseOffset, suffix))); err != nil {
+				t.Errorf("segment file: %v", err)
+			}
+		}
+	}
+
+	log, err = OpenPartitionLog(logDir, "test-topic-0", int64(2*len(batchBytes)), 4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Close()
+	if log.LogEndOffset() != 5 {
+		t.Errorf("log end offset = %d, want 5", log.LogEndOffset())
+	}
+	assertValues(t, readValues(t, log, 1), "value", "value", "value", "value")
+}
+
+func TestPartitionLog_RecoverIncompleteWrites(t *testing.T) {
+	logDir := t.TempDir()
+	dir := filepath.Join(logDir, "test-topic-0")
+
+	log, err := OpenPartitionLog(logDir, "test-topic-0", 1<<20, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{"a", "b", "c"} {
+		if _, err := log.Append(newTestBatch(time.Now(), v)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := log.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	logPath := filepath.Join(dir, segmentFileName(0, logFileSuffix))
+	indexPath := filepath.Join(dir, segmentFileName(0, indexFileSuffix))
+	info, err := os.Stat(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the broker stopped in the middle of writing the last batch, before writing its index entry
+	if err := os.Truncate(logPath, info.Size()-5); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(indexPath, indexEntrySize); err != nil {
+		t.Fatal(err)
+	}
+
+	log, err = OpenPartitionLog(logDir, "test-topic-0", 1<<20, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if log.LogEndOffset() != 2 {
+		t.Errorf("log end offset = %d, want 2", log.LogEndOffset())
+	}
+	assertValues(t, readValues(t, log, 0), "a", "b")
+
+	// the index was rebuilt from the log file, and the next batch replaces the incomplete one
+	if _, err := log.Append(newTestBatch(time.Now(), "d")); err != nil {
+		t.Fatal(err)
+	}
+	if err := log.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if index, err := os.ReadFile(indexPath); err != nil || len(index) != 3*indexEntrySize {
+		t.Errorf("index has %d bytes, err = %v, want 3 entries", len(index), err)
+	}
+
+	log, err = OpenPartitionLog(logDir, "test-topic-0", 1<<20, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Close()
+	assertValues(t, readValues(t, log, 0), "a", "b", "d")
+}
+
+func TestLogManager_DiskPlacementAndDelete(t *testing.T) {
+	dirs := []string{t.TempDir(), t.TempDir()}
+	logs, err := OpenLogManager(DiskOptions{Dirs: dirs, SegmentBytes: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logs.Close()
+
+	var placed []string
+	for _, tp := range []TopicPartition{{"test-topic", 0}, {"test-topic", 1}, {"other-topic", 0}} {
+		log, err := logs.GetOrCreatePartition(tp.Topic, tp.Partition)
+		if err != nil {
+			t.Fatal(err)
+		}
+		placed = append(placed, log.LogDir())
+	}
+	if placed[0] != dirs[0] || placed[1] != dirs[1] || placed[2] != dirs[0] {
+		t.Errorf("log dirs = %v, want the partitions spread over %v", placed, dirs)
+	}
+
+	logs.DeleteTopic("test-topic")
+
+	for i, name := range []string{"test-topic-0", "test-topic-1"} {
+		if _, err := os.Stat(filepath.Join(dirs[i], name)); !os.IsNotExist(err) {
+			t.Errorf("the directory of deleted partition %s still exists: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dirs[0], "other-topic-0")); err != nil {
+		t.Errorf("the directory of the other topic was removed: %v", err)
+	}
+}
+
+func TestParseTopicPartition(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   TopicPartition
+		wantOk bool
+	}{
+		{name: "test-topic-0", want: TopicPartition{"test-topic", 0}, wantOk: true},
+		{name: "topic-12", want: TopicPartition{"topic", 12}, wantOk: true},
+		{name: "topic"},
+		{name: "-1"},
+		{name: "topic-x"},
+		{name: "topic--1", want: TopicPartition{"topic-", 1}, wantOk: true},
+	}
+	for _, tt := range tests {
+		got, ok := parseTopicPartition(tt.name)
+		if ok != tt.wantOk || got != tt.want {
+			t.Errorf("parseTopicPartition(%q) = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func TestPartitionLog_ApplyRetention(t *testing.T) {
+	now := time.UnixMilli(1730473445000)
+	batch := newTestBatch(now, "value")
+	batchBytes, err := protocol.Encode(&batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		retention Retention
+		// the batches are a minute apart, the last one at now
+		wantDeleted        int
+		wantLogStartOffset int64
+	}{
+		{name: "unlimited", retention: Retention{Time: -1, Bytes: -1}, wantLogStartOffset: 0},
+		{name: "by time", retention: Retention{Time: 100 * time.Second, Bytes: -1}, wantDeleted: 2, wantLogStartOffset: 2},
+		{name: "by size", retention: Retention{Time: -1, Bytes: int64(3 * len(batchBytes))}, wantDeleted: 1, wantLogStartOffset: 1},
+		// the active segment is kept even if it is beyond the retention
+		{name: "everything expired", retention: Retention{Time: 0, Bytes: 0}, wantDeleted: 3, wantLogStartOffset: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logDir := t.TempDir()
+			// a segment per batch
+			log, err := OpenPartitionLog(logDir, "test-topic-0", int64(len(batchBytes)), 4096)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer log.Close()
+			for i := 3; i >= 0; i-- {
+				if _, err := log.Append(newTestBatch(now.Add(-time.Duration(i)*time.Minute), "value")); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			deleted, err := log.ApplyRetention(tt.retention, now.Add(time.Second))
+			if err != nil || deleted != tt.wantDeleted {
+				t.Fatalf("deleted %d segments, err = %v, want %d", deleted, err, tt.wantDeleted)
+			}
+			if log.LogStartOffset() != tt.wantLogStartOffset || log.LogEndOffset() != 4 {
+				t.Errorf("log from %d to %d, want from %d to 4", log.LogStartOffset(), log.LogEndOffset(), tt.wantLogStartOffset)
+			}
+			if log.Size() != int64((4-tt.wantDeleted)*len(batchBytes)) {
+				t.Errorf("size = %d, want %d batches", log.Size(), 4-tt.wantDeleted)
+			}
+			if tt.wantLogStartOffset > 0 {
+				if _, err := log.Read(tt.wantLogStartOffset-1, 1<<20, true); err != utils.ErrOffsetOutOfRange {
+					t.Errorf("reading a deleted offset: err = %v, want %v", err, utils.ErrOffsetOutOfRange)
+				}
+			}
+			for offset := int64(0); offset < tt.wantLogStartOffset; offset++ {
+				if _, err := os.Stat(filepath.Join(logDir, "test-topic-0", segmentFileName(offset, logFileSuffix))); !os.IsNotExist(err) {
+					t.Errorf("segment %d was not deleted: %v", offset, err)
+				}
+			}
+
+			// the log start offset survives a restart
+			if err := log.Close(); err != nil {
+				t.Fatal(err)
+			}
+			log, err = OpenPartitionLog(logDir, "test-topic-0", int64(len(batchBytes)), 4096)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer log.Close()
+			if log.LogStartOffset() != tt.wantLogStartOffset {
+				t.Errorf("recovered log start offset = %d, want %d", log.LogStartOffset(), tt.wantLogStartOffset)
+			}
+		})
+	}
+}
+
+func TestPartitionLog_ApplyRetentionWithoutTimestamps(t *testing.T) {
+	// the batches of old clients have no timestamps
+	batch := newTestBatch(time.Time{}, "value")
+	batchBytes, err := protocol.Encode(&batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logDir := t.TempDir()
+	log, err := OpenPartitionLog(logDir, "test-topic-0", int64(len(batchBytes)), 4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Close()
+	for i := 0; i < 2; i++ {
+		if _, err := log.Append(newTestBatch(time.Time{}, "value")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the segment is as old as its log file
+	retention := Retention{Time: time.Hour, Bytes: -1}
+	if deleted, err := log.ApplyRetention(retention, time.Now()); err != nil || deleted != 0 {
+		t.Fatalf("deleted %d segments, err = %v, want none", deleted, err)
+	}
+
+	modTime := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(filepath.Join(logDir, "test-topic-0", segmentFileName(0, logFileSuffix)), modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+	if deleted, err := log.ApplyRetention(retention, time.Now()); err != nil || deleted != 1 {
+		t.Fatalf("deleted %d segments, err = %v, want 1", deleted, err)
+	}
+}
+
+func TestPartitionLog_SparseIndex(t *testing.T) {
+	logDir := t.TempDir()
+	indexPath := filepath.Join(logDir, "test-topic-0", segmentFileName(0, indexFileSuffix))
+	timestamp := time.UnixMilli(1730473445000)
+	batch := newTestBatch(timestamp, "0")
+	batchBytes, err := protocol.Encode(&batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an index entry every two batches, the first one at offset 2
+	indexIntervalBytes := int64(2 * len(batchBytes))
+	log, err := OpenPartitionLog(logDir, "test-topic-0", 1<<20, indexIntervalBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := log.Append(newTestBatch(timestamp.Add(time.Duration(i)*time.Second), strconv.Itoa(i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if index, err := os.ReadFile(indexPath); err != nil || len(index) != 4*indexEntrySize {
+		t.Fatalf("index has %d bytes, err = %v, want 4 entries", len(index), err)
+	}
+
+	assertReads := func(t *testing.T, log *PartitionLog) {
+		t.Helper()
+
+		for offset := 0; offset < 10; offset++ {
+			if got := readValues(t, log, int64(offset)); len(got) != 10-offset || got[0] != strconv.Itoa(offset) {
+				t.Errorf("values from offset %d = %q", offset, got)
+			}
+		}
+		offset, _, ok, err := log.OffsetForTimestamp(timestamp.Add(5500 * time.Millisecond))
+		if err != nil || !ok || offset != 6 {
+			t.Errorf("offset for timestamp = %d, ok = %v, err = %v, want 6", offset, ok, err)
+		}
+		offset, _, ok, err = log.MaxTimestampOffset()
+		if err != nil || !ok || offset != 9 {
+			t.Errorf("max timestamp offset = %d, ok = %v, err = %v, want 9", offset, ok, err)
+		}
+	}
+	assertReads(t, log)
+	if err := log.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the entries lost with the end of the index are rebuilt from the log file
+	if err := os.Truncate(indexPath, indexEntrySize); err != nil {
+		t.Fatal(err)
+	}
+	log, err = OpenPartitionLog(logDir, "test-topic-0", 1<<20, indexIntervalBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Close()
+	if index, err := os.ReadFile(indexPath); err != nil || len(index) != 4*indexEntrySize {
+		t.Errorf("index has %d bytes, err = %v, want 4 entries", len(index), err)
+	}
+	if log.LogEndOffset() != 10 || log.Size() != int64(10*len(batchBytes)) {
+		t.Errorf("recovered log to %d with %d bytes, want to 10 with %d bytes", log.LogEndOffset(), log.Size(), 10*len(batchBytes))
+	}
+	assertReads(t, log)
+}
+
+// BenchmarkPartitionLog_Read fetches from offsets all over a segment, with the default index interval
+// and without index entries, where each fetch reads the batch headers from the start of the segment.
+func BenchmarkPartitionLog_Read(b *testing.B) {
+	const batches = 10000
+
+	for _, bm := range []struct {
+		name               string
+		indexIntervalBytes int64
+	}{
+		{name: "index", indexIntervalBytes: 4096},
+		{name: "no index", indexIntervalBytes: math.MaxInt32},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			log, err := OpenPartitionLog(b.TempDir(), "test-topic-0", 1<<30, bm.indexIntervalBytes)
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer log.Close()
+			for i := 0; i < batches; i++ {
+				if _, err := log.Append(newTestBatch(time.Now(), "value")); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// a small fetch, so the time goes into finding the offset rather than decoding the batches
+				if _, err := log.Read(int64(i*7919%batches), 1024, true); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
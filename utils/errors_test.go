@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorCode(t *testing.T) {
+	wrapped := fmt.Errorf("topic test-topic: %w", ErrUnknownTopicOrPartition)
+
+	tests := []struct {
+		name string
+		err  error
+		want KError
+	}{
+		{name: "nil", err: nil, want: ErrNoError},
+		{name: "kafka error", err: ErrInvalidTopic, want: ErrInvalidTopic},
+		{name: "wrapped kafka error", err: wrapped, want: ErrUnknownTopicOrPartition},
+		{name: "other error", err: errors.New("disk full"), want: ErrUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorCode(tt.err); got != tt.want {
+				t.Errorf("ErrorCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if !errors.Is(wrapped, ErrUnknownTopicOrPartition) {
+		t.Error("errors.Is should find the wrapped kafka error")
+	}
+}